@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/ask"
+	"hire.ai/pkg/export"
+)
+
+// runAsk answers a question about the stored jobs, citing the postings the
+// answer is drawn from
+func runAsk(args []string) error {
+	fs := flag.NewFlagSet("ask", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	resultsFlag := fs.Int("results", 0, "Number of postings to answer from (default from config, 8)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	question := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(question) == "" {
+		return fmt.Errorf("usage: job-scraper ask [flags] \"question\"")
+	}
+	if *resultsFlag < 0 {
+		return fmt.Errorf("-results must not be negative")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if app.assistant == nil {
+		return fmt.Errorf("question answering is not enabled in the config")
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("no stored jobs to search; run a scrape first")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	answer, err := app.assistant.Ask(ctx, question, jobs, *resultsFlag)
+	if err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, answer.Sources)
+		}
+		return export.Write(os.Stdout, *common.output, answer)
+	}
+	printAnswer(answer)
+	return nil
+}
+
+func printAnswer(answer *ask.Answer) {
+	fmt.Println(answer.Answer)
+
+	var cited []ask.Source
+	for _, source := range answer.Sources {
+		if source.Cited {
+			cited = append(cited, source)
+		}
+	}
+	heading := "Sources:"
+	if len(cited) == 0 {
+		// The answer cites nothing; show what it was given
+		cited, heading = answer.Sources, "Postings searched:"
+	}
+
+	fmt.Printf("\n%s\n", heading)
+	for _, source := range cited {
+		fmt.Printf("  [%s] %s at %s", source.ID, source.Title, source.Company)
+		if source.Location != "" {
+			fmt.Printf(" (%s)", source.Location)
+		}
+		fmt.Println()
+		if source.Link != "" {
+			fmt.Printf("      %s\n", source.Link)
+		}
+	}
+}
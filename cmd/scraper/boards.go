@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/transport"
+)
+
+const defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+func runBoards(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper boards <add|lint|test> [flags]")
+	}
+
+	switch args[0] {
+	case "add":
+		return runBoardsAdd(args[1:])
+	case "lint":
+		return runBoardsLint(args[1:])
+	case "test":
+		return runBoardsTest(args[1:])
+	default:
+		return fmt.Errorf("unknown boards command: %s", args[0])
+	}
+}
+
+func runBoardsAdd(args []string) error {
+	fs := flag.NewFlagSet("boards add", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	nameFlag := fs.String("name", "", "Board name (defaults to one derived from the URL host)")
+	yesFlag := fs.Bool("yes", false, "Accept the proposed entry without prompting")
+	timeoutFlag := fs.Duration("timeout", 30*time.Second, "Timeout for fetching the page")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper boards add <url> [flags]")
+	}
+	pageURL := fs.Arg(0)
+	logger := common.logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+
+	logger.Infof("Fetching %s to propose a board entry...", pageURL)
+	proposal, err := scraper.DiscoverBoard(ctx, transport.NewClient(*timeoutFlag), pageURL, defaultUserAgent)
+	if err != nil {
+		return err
+	}
+
+	board := proposal.Board
+	if *nameFlag != "" {
+		board.Name = *nameFlag
+		if board.RSSConfig != nil {
+			board.RSSConfig.Name = *nameFlag
+		}
+	}
+
+	if !common.machineReadable() {
+		printProposal(proposal)
+	}
+
+	if !*yesFlag {
+		prompt := newPrompter()
+		board.Name = prompt.ask("Board name", board.Name)
+		if board.RSSConfig != nil {
+			board.RSSConfig.Name = board.Name
+			board.RSSConfig.FeedURL = prompt.ask("Feed URL", board.RSSConfig.FeedURL)
+		} else {
+			board.Selectors.JobContainer = prompt.ask("Job container selector", board.Selectors.JobContainer)
+			board.Selectors.Title = prompt.ask("Title selector", board.Selectors.Title)
+			board.Selectors.Company = prompt.ask("Company selector", board.Selectors.Company)
+			if board.Selectors.Company == "" {
+				board.CompanyName = prompt.ask("Static company name", board.CompanyName)
+			}
+			board.Selectors.Location = prompt.ask("Location selector", board.Selectors.Location)
+			board.Selectors.Salary = prompt.ask("Salary selector", board.Selectors.Salary)
+			board.Selectors.Description = prompt.ask("Description selector", board.Selectors.Description)
+			board.Selectors.Link = prompt.ask("Link selector", board.Selectors.Link)
+		}
+
+		if !prompt.confirm(fmt.Sprintf("Append %s to %s?", board.Name, *common.config)) {
+			fmt.Fprintln(os.Stderr, "Aborted; config left unchanged.")
+			return nil
+		}
+	}
+
+	if err := scraper.AppendBoard(*common.config, board); err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		proposal.Board = board
+		return export.Write(os.Stdout, *common.output, proposal)
+	}
+
+	fmt.Printf("Added board %s to %s\n", board.Name, *common.config)
+	if len(board.SearchParams) > 0 {
+		fmt.Println("Tip: replace search parameter values with {keywords} or {location} to make them dynamic.")
+	}
+	return nil
+}
+
+func printProposal(proposal *scraper.BoardProposal) {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("PROPOSED BOARD")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Detected: %s\n", proposal.Detected)
+
+	if proposal.Board.RSSConfig == nil {
+		fmt.Printf("Matched containers: %d\n", proposal.Matches)
+		if len(proposal.Coverage) > 0 {
+			fields := make([]string, 0, len(proposal.Coverage))
+			for field := range proposal.Coverage {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+
+			fmt.Println("Field coverage:")
+			for _, field := range fields {
+				fmt.Printf("  %-12s: %.0f%%\n", field, proposal.Coverage[field]*100)
+			}
+		}
+	}
+
+	for _, warning := range proposal.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	entry, _ := json.MarshalIndent(proposal.Board, "", "  ")
+	fmt.Printf("\n%s\n\n", entry)
+}
+
+// prompter reads interactive answers from stdin. Prompts go to stderr so
+// stdout stays clean for machine-readable output.
+type prompter struct {
+	reader *bufio.Reader
+}
+
+func newPrompter() *prompter {
+	return &prompter{reader: bufio.NewReader(os.Stdin)}
+}
+
+// ask prints label with the default value and returns the answer, or the
+// default if the user just presses enter. A single "-" clears the value.
+func (p *prompter) ask(label, defaultValue string) string {
+	fmt.Fprintf(os.Stderr, "%s [%s]: ", label, defaultValue)
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return defaultValue
+	}
+
+	line = strings.TrimSpace(line)
+	switch line {
+	case "":
+		return defaultValue
+	case "-":
+		return ""
+	default:
+		return line
+	}
+}
+
+func (p *prompter) confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
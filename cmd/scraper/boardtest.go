@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/scraper"
+)
+
+// boardLint is the lint result of one board, for output
+type boardLint struct {
+	Board    string   `json:"board"`
+	Problems []string `json:"problems"`
+}
+
+// runBoardsLint checks the board entries without fetching anything
+func runBoardsLint(args []string) error {
+	fs := flag.NewFlagSet("boards lint", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	core, err := scraper.NewScraperCoreWithLogger(*common.config, common.logger())
+	if err != nil {
+		return err
+	}
+	boards, err := selectBoards(core.GetConfig().JobBoards, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var lints []boardLint
+	failed := 0
+	for _, board := range boards {
+		problems := scraper.LintBoard(board)
+		if len(problems) > 0 {
+			failed++
+		}
+		lints = append(lints, boardLint{Board: board.Name, Problems: problems})
+	}
+
+	if common.machineReadable() {
+		if err := export.Write(os.Stdout, *common.output, lints); err != nil {
+			return err
+		}
+	} else {
+		for _, lint := range lints {
+			if len(lint.Problems) == 0 {
+				fmt.Printf("ok    %s\n", lint.Board)
+				continue
+			}
+			fmt.Printf("FAIL  %s\n", lint.Board)
+			for _, problem := range lint.Problems {
+				fmt.Printf("        %s\n", problem)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d boards have problems", failed, len(boards))
+	}
+	return nil
+}
+
+// runBoardsTest runs each board's selectors against its saved fixture and,
+// with -live, its live search page, reporting how many containers yielded
+// each field so selector rot shows up before a production run does
+func runBoardsTest(args []string) error {
+	fs := flag.NewFlagSet("boards test", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	liveFlag := fs.Bool("live", false, "Also fetch each board's live search page")
+	keywordsFlag := fs.String("keywords", "software engineer", "Keywords for the live search pages")
+	locationFlag := fs.String("location", "", "Location for the live search pages (default: the config's defaultLocation)")
+	coverageFlag := fs.Float64("min-coverage", scraper.DefaultMinCoverage, "Share of containers that must yield a title, company and link")
+	saveFlag := fs.Bool("save", false, "Save each live page as the board's fixture (with -live)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *coverageFlag < 0 || *coverageFlag > 1 {
+		return fmt.Errorf("-min-coverage must be between 0 and 1")
+	}
+	if *saveFlag && !*liveFlag {
+		return fmt.Errorf("-save needs -live")
+	}
+
+	logger := common.logger()
+	core, err := scraper.NewScraperCoreWithLogger(*common.config, logger)
+	if err != nil {
+		return err
+	}
+	config := core.GetConfig()
+	boards, err := selectBoards(config.JobBoards, fs.Args())
+	if err != nil {
+		return err
+	}
+	location := *locationFlag
+	if location == "" {
+		location = config.GlobalSettings.DefaultLocation
+	}
+
+	// Live pages count against the request budgets scrapes use
+	if *liveFlag {
+		budgets := filepath.Join(*common.data, requestBudgetFile)
+		if err := core.TrackRequestBudgets(budgets); err != nil {
+			logger.Warnf("Failed to load request budgets: %v", err)
+		}
+		defer func() {
+			if err := core.SaveRequestBudgets(); err != nil {
+				logger.Warnf("Failed to save request budgets: %v", err)
+			}
+		}()
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	var tests []scraper.SelectorTest
+	var notes []string
+	for _, board := range boards {
+		if problems := scraper.LintBoard(board); len(problems) > 0 {
+			tests = append(tests, scraper.SelectorTest{Board: board.Name, Page: "config", Source: *common.config, Problems: problems})
+			continue
+		}
+		if board.ScrapingMethod == "rss" {
+			notes = append(notes, fmt.Sprintf("%s is an rss board; it has no selectors to test", board.Name))
+			continue
+		}
+
+		fixture := scraper.FixturePath(*common.config, board)
+		body, err := os.ReadFile(fixture)
+		switch {
+		case err == nil:
+			tests = append(tests, scraper.TestSelectors(board, "fixture", fixture, body, *coverageFlag))
+		case errors.Is(err, os.ErrNotExist) && *saveFlag:
+			// The live page saved below becomes the fixture
+		case errors.Is(err, os.ErrNotExist) && board.Fixture == "":
+			notes = append(notes, fmt.Sprintf("%s has no fixture; save one with -live -save", board.Name))
+		default:
+			tests = append(tests, scraper.SelectorTest{Board: board.Name, Page: "fixture", Source: fixture, Problems: []string{err.Error()}})
+		}
+
+		if !*liveFlag {
+			continue
+		}
+		url, body, err := core.FetchSearchPage(ctx, board, *keywordsFlag, location)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			tests = append(tests, scraper.SelectorTest{Board: board.Name, Page: "live", Source: url, Problems: []string{err.Error()}})
+			continue
+		}
+		test := scraper.TestSelectors(board, "live", url, body, *coverageFlag)
+		if !test.Passed() && core.RequiresJavaScript(board) {
+			test.Problems = append(test.Problems, "scrapes render this board in a browser; the live test reads the page without running its scripts")
+		}
+		tests = append(tests, test)
+
+		if *saveFlag {
+			if err := os.MkdirAll(filepath.Dir(fixture), 0755); err != nil {
+				return fmt.Errorf("failed to create fixture directory: %w", err)
+			}
+			if err := os.WriteFile(fixture, body, 0644); err != nil {
+				return fmt.Errorf("failed to save fixture: %w", err)
+			}
+			notes = append(notes, fmt.Sprintf("saved %s's live page to %s", board.Name, fixture))
+		}
+	}
+
+	failed := 0
+	for _, test := range tests {
+		if !test.Passed() {
+			failed++
+		}
+	}
+	if common.machineReadable() {
+		if err := export.Write(os.Stdout, *common.output, tests); err != nil {
+			return err
+		}
+	} else {
+		printSelectorTests(tests, notes)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d board tests failed", failed, len(tests))
+	}
+	return nil
+}
+
+// selectBoards returns the boards named, or every enabled board when no
+// names are given
+func selectBoards(boards []scraper.JobBoard, names []string) ([]scraper.JobBoard, error) {
+	if len(names) == 0 {
+		var enabled []scraper.JobBoard
+		for _, board := range boards {
+			if board.Enabled {
+				enabled = append(enabled, board)
+			}
+		}
+		return enabled, nil
+	}
+
+	byName := make(map[string]scraper.JobBoard, len(boards))
+	for _, board := range boards {
+		byName[board.Name] = board
+	}
+	selected := make([]scraper.JobBoard, 0, len(names))
+	for _, name := range names {
+		board, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no board named %q", name)
+		}
+		selected = append(selected, board)
+	}
+	return selected, nil
+}
+
+func printSelectorTests(tests []scraper.SelectorTest, notes []string) {
+	for _, test := range tests {
+		status := "ok  "
+		if !test.Passed() {
+			status = "FAIL"
+		}
+		fmt.Printf("%s  %s (%s %s)\n", status, test.Board, test.Page, test.Source)
+		if test.Containers > 0 {
+			fields := make([]string, 0, len(test.Coverage))
+			for field := range test.Coverage {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			coverage := make([]string, len(fields))
+			for i, field := range fields {
+				coverage[i] = fmt.Sprintf("%s %.0f%%", field, test.Coverage[field]*100)
+			}
+			fmt.Printf("        %d containers, %d jobs kept; %s\n", test.Containers, test.Jobs, strings.Join(coverage, ", "))
+		}
+		for _, problem := range test.Problems {
+			fmt.Printf("        %s\n", problem)
+		}
+	}
+	if len(tests) == 0 {
+		fmt.Println("No board pages were tested")
+	}
+	for _, note := range notes {
+		fmt.Printf("Note: %s\n", note)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+)
+
+// checkpointFile records the progress of the last full scrape run until it
+// completes, so 'scrape -continue' can pick up an interrupted run
+const checkpointFile = "scrape_checkpoint.json"
+
+// runCheckpoint lists the sources of a full scrape run whose jobs are all
+// stored. A source is only recorded once the pipeline has stored every batch
+// sent before it, so a crash never leaves a source recorded whose jobs were
+// lost in flight.
+type runCheckpoint struct {
+	Keywords  []string    `json:"keywords"`
+	Location  string      `json:"location"`
+	Resume    string      `json:"resume,omitempty"`
+	Area      *searchArea `json:"area,omitempty"`
+	StartedAt time.Time   `json:"started_at"`
+	Completed []string    `json:"completed,omitempty"`
+
+	path    string
+	mutex   sync.Mutex
+	sent    int      // batches the scrape has sent or is about to send
+	stored  int      // batches the pipeline has stored
+	pending []string // finished sources whose jobs may still be in flight
+}
+
+func newRunCheckpoint(dataDir string, keywords []string, location, resumePath string, area *searchArea) *runCheckpoint {
+	return &runCheckpoint{
+		Keywords:  keywords,
+		Location:  location,
+		Resume:    resumePath,
+		Area:      area,
+		StartedAt: time.Now(),
+		path:      filepath.Join(dataDir, checkpointFile),
+	}
+}
+
+// loadRunCheckpoint reads the checkpoint of an interrupted run, or returns
+// nil if the last run completed
+func loadRunCheckpoint(dataDir string) (*runCheckpoint, error) {
+	path := filepath.Join(dataDir, checkpointFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &runCheckpoint{path: path}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// sourceFinished notes a source the scrape reports completed. The scrape
+// sends a source's batches right after reporting it.
+func (c *runCheckpoint) sourceFinished(update models.SourceProgress) error {
+	if update.Status != models.SourceCompleted {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sent += (update.JobsFound + scraper.StreamBatchSize - 1) / scraper.StreamBatchSize
+	c.pending = append(c.pending, update.Source)
+	return c.commit()
+}
+
+// batchStored notes a batch the pipeline has stored
+func (c *runCheckpoint) batchStored() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stored++
+	return c.commit()
+}
+
+// commit records the pending sources once nothing sent is left unstored.
+// The caller holds the mutex.
+func (c *runCheckpoint) commit() error {
+	if len(c.pending) == 0 || c.stored < c.sent {
+		return nil
+	}
+	c.Completed = append(c.Completed, c.pending...)
+	c.pending = nil
+	return c.save()
+}
+
+func (c *runCheckpoint) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(c.path+".tmp", c.path)
+}
+
+// remove deletes the checkpoint of a run that completed
+func (c *runCheckpoint) remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"hire.ai/pkg/classify"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+)
+
+// runClassify classifies stored jobs that have no classification yet and,
+// when LLM classification is enabled, relabels rule-based ones with it
+func runClassify(args []string) error {
+	fs := flag.NewFlagSet("classify", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	limitFlag := fs.Int("limit", 50, "Maximum number of jobs to send to the LLM")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag <= 0 {
+		return fmt.Errorf("-limit must be positive")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	classification := func(job *models.Job) interface{} { return job.Classification }
+	before := fieldValues(jobs, classification)
+	byRules := 0
+	for i := range jobs {
+		if jobs[i].Classification == nil {
+			jobs[i].Classification = classify.Classify(&jobs[i])
+			byRules++
+		}
+	}
+	byLLM := 0
+	if app.classifier != nil {
+		llmCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+		defer cancel()
+		byLLM = app.classifier.ClassifyAll(llmCtx, jobs, *limitFlag)
+	}
+
+	if byRules > 0 || byLLM > 0 {
+		if _, err := app.storage.Upsert(ctx, jobs); err != nil {
+			return fmt.Errorf("failed to store classifications: %w", err)
+		}
+	}
+
+	if common.machineReadable() {
+		classified := updatedJobs(jobs, before, classification)
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, classified)
+		}
+		return export.Write(os.Stdout, *common.output, struct {
+			ByRules int          `json:"by_rules"`
+			ByLLM   int          `json:"by_llm"`
+			Jobs    []models.Job `json:"jobs"`
+		}{ByRules: byRules, ByLLM: byLLM, Jobs: classified})
+	}
+	if byRules == 0 && byLLM == 0 {
+		fmt.Println("No jobs were classified")
+		return nil
+	}
+	fmt.Printf("Classified %d jobs by rules and %d with the LLM\n", byRules, byLLM)
+	return nil
+}
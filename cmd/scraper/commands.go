@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// command is a named subcommand; invoking the binary without one runs the
+// classic flag-driven scrape so existing scripts keep working
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"ask":       {summary: "Answer a question about the stored jobs, citing the postings", run: runAsk},
+	"boards":    {summary: "Manage job board configuration (add) and check selectors against fixtures and live pages (lint, test)", run: runBoards},
+	"classify":  {summary: "Tag stored jobs with role category, seniority and industry", run: runClassify},
+	"companies": {summary: "List the employers of stored jobs with job counts, counting names like \"Google\" and \"Google LLC\" as one", run: runCompanies},
+	"daemon":    {summary: "Run the scrapes in globalSettings.schedules on their cron schedules until interrupted (-list shows them)", run: runDaemon},
+	"digest":    {summary: "Summarise new jobs over a period as Markdown, HTML or an email", run: runDigest},
+	"enrich":    {summary: "Have the LLM read required skills, tech stack, experience, visa sponsorship and seniority into stored jobs that lack them", run: runEnrich},
+	"expire":    {summary: "Revisit stored job links and mark postings that were taken down as expired", run: runExpire},
+	"gaps":      {summary: "Report the skills your best-matching jobs ask for that your resume lacks", run: runGaps},
+	"import":    {summary: "Store and track jobs from a LinkedIn saved-jobs export or any CSV or JSON list (-map names columns)", run: runImport},
+	"mcp":       {summary: "Serve search, scrape, stats and tagging as MCP tools over stdio for agents", run: runMCP},
+	"prefill":   {summary: "Answer a Greenhouse, Lever or Workday application form from your saved answers (-fill enters them in a browser)", run: runPrefill},
+	"prep":      {summary: "Write an interview prep pack (topics and practice questions) for a stored job", run: runPrep},
+	"proxies":   {summary: "Report proxy usage: requests, success rate, blocks and data (status)", run: runProxies},
+	"purge":     {summary: "Delete stored jobs by age, source, status or relevance", run: runPurge},
+	"runs":      {summary: "Show the history of scrape runs with per-source timings and errors (list, show)", run: runRuns},
+	"salaries":  {summary: "Report salary percentiles of stored jobs by title, seniority or location, with charts", run: runSalaries},
+	"scrape":    {summary: "Scrape all enabled sources (supports -resume, -watch, -desktop and -continue)", run: runScrape},
+	"searches":  {summary: "Manage saved searches (add, list, remove) and read the new jobs they matched since last time (alerts)", run: runSearches},
+	"serve":     {summary: "Run the REST API server over stored jobs", run: runServe},
+	"status":    {summary: "Track where you stand with stored jobs: mark them new, seen, applied, rejected or archived, or list them", run: runStatus},
+	"stats":     {summary: "Show stored job counts by source, location, day and keyword (-rebuild recounts them), or API usage against quotas (api)", run: runStats},
+	"suggest":   {summary: "Suggest keywords, exclusions and companies from the jobs you keep and archive", run: runSuggest},
+	"summarize": {summary: "Add LLM summaries to stored jobs that have none", run: runSummarize},
+	"telegram":  {summary: "Run the Telegram bot answering /search, /latest and /applied from the chats in the telegram settings", run: runTelegram},
+	"trends":    {summary: "Report weekly postings by skill, company or location, posting lifetimes and repost rates, with a dashboard", run: runTrends},
+	"tokens":    {summary: "Manage API server tokens (create, list, revoke)", run: runTokens},
+	"users":     {summary: "Manage user accounts for multi-user servers (add, list, remove)", run: runUsers},
+}
+
+// runCommand dispatches os.Args to a subcommand and reports whether one matched
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	name := args[0]
+	if name == "help" {
+		printCommands()
+		return true
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		return false
+	}
+
+	if err := cmd.run(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	return true
+}
+
+// commandContext returns the context a command's storage and network work
+// runs under, cancelled when the user interrupts the command. A second
+// interrupt kills the process as usual, for when winding down takes too long.
+func commandContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// Exit codes tell scripts why a command failed; 2 is left to flag parsing
+const (
+	exitFailure      = 1
+	exitAuth         = 3
+	exitRateLimited  = 4 // also when a quota or request budget is used up
+	exitBlocked      = 5
+	exitSelectorMiss = 6
+	exitConfig       = 7
+	exitAllFailed    = 8 // every source failed for a cause without its own code
+	exitPartial      = 9 // with -strict, some sources failed
+	exitNoNewJobs    = 10
+)
+
+// exitCode picks the exit code for err's cause. When sources failed for
+// different reasons, the first cause in the order below wins.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrConfig):
+		return exitConfig
+	case errors.Is(err, errs.ErrAuth):
+		return exitAuth
+	case errors.Is(err, errs.ErrRateLimited), errors.Is(err, errs.ErrQuotaExhausted), errors.Is(err, errs.ErrBudgetSpent):
+		return exitRateLimited
+	case errors.Is(err, errs.ErrBlocked):
+		return exitBlocked
+	case errors.Is(err, errs.ErrSelectorMiss):
+		return exitSelectorMiss
+	case errors.Is(err, errs.ErrAllSourcesFailed):
+		return exitAllFailed
+	case errors.Is(err, errPartialFailure):
+		return exitPartial
+	case errors.Is(err, errNoNewJobs):
+		return exitNoNewJobs
+	}
+	return exitFailure
+}
+
+func printCommands() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Usage: job-scraper [command] [flags]")
+	fmt.Println("\nRunning without a command scrapes using -keywords/-location flags.")
+	fmt.Println("\nCommands:")
+	for _, name := range names {
+		fmt.Printf("  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// commonFlags are the flags shared by every subcommand
+type commonFlags struct {
+	config    *string
+	data      *string
+	verbose   *bool
+	output    *string
+	logFormat *string
+	logFile   *string
+	offline   *bool
+	record    *bool
+}
+
+func registerCommonFlags(fs *flag.FlagSet) *commonFlags {
+	return &commonFlags{
+		config:    fs.String("config", "config/job-boards.json", "Path to job boards configuration"),
+		data:      fs.String("data", "data", "Data directory for storage"),
+		verbose:   fs.Bool("verbose", false, "Verbose logging"),
+		output:    fs.String("output", export.FormatTable, "Output format for results (table, json, yaml, csv)"),
+		logFormat: fs.String("log-format", os.Getenv("LOG_FORMAT"), "Log format (text, json)"),
+		logFile:   fs.String("log-file", os.Getenv("LOG_FILE"), "Also append logs to this file"),
+		offline:   fs.Bool("offline", false, "Answer every request from the recordings in the data directory instead of the network"),
+		record:    fs.Bool("record", false, "Save every response to the recordings in the data directory for -offline runs"),
+	}
+}
+
+// validate checks flag values shared by all commands and switches HTTP
+// clients to recording or replaying when -record or -offline is set
+func (cf *commonFlags) validate() error {
+	if err := export.ValidateFormat(*cf.output); err != nil {
+		return err
+	}
+	return setRecording(*cf.offline, *cf.record, *cf.data)
+}
+
+// setRecording switches HTTP clients to replaying the recordings in the data
+// directory when offline, or to saving responses there when record is set
+func setRecording(offline, record bool, dataDir string) error {
+	mode := transport.ModeLive
+	switch {
+	case offline && record:
+		return fmt.Errorf("-offline and -record cannot be used together")
+	case offline:
+		mode = transport.ModeOffline
+	case record:
+		mode = transport.ModeRecord
+	}
+	return transport.SetRecording(mode, filepath.Join(dataDir, recordingsDir))
+}
+
+// machineReadable reports whether results should be written with export.Write
+func (cf *commonFlags) machineReadable() bool {
+	return *cf.output != export.FormatTable
+}
+
+// fieldValues records a field of each job, so updatedJobs can tell which
+// jobs a backfill pass gave a new value
+func fieldValues(jobs []models.Job, field func(*models.Job) interface{}) []interface{} {
+	values := make([]interface{}, len(jobs))
+	for i := range jobs {
+		values[i] = field(&jobs[i])
+	}
+	return values
+}
+
+// updatedJobs returns the jobs whose field no longer holds the value
+// fieldValues recorded. Passes set new pointers rather than changing the
+// ones they find, so comparing pointers is enough.
+func updatedJobs(jobs []models.Job, before []interface{}, field func(*models.Job) interface{}) []models.Job {
+	updated := []models.Job{}
+	for i := range jobs {
+		if field(&jobs[i]) != before[i] {
+			updated = append(updated, jobs[i])
+		}
+	}
+	return updated
+}
+
+func (cf *commonFlags) logger() *logrus.Logger {
+	logger, err := setupLogger(*cf.verbose, *cf.logFormat, *cf.logFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return logger
+}
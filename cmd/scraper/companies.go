@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+// runCompanies lists the employers of the stored jobs, each company's
+// aliases such as "Google" and "Google LLC" counted together
+func runCompanies(args []string) error {
+	fs := flag.NewFlagSet("companies", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	searchFlag := fs.String("search", "", "Only list companies whose name contains this text or that go by this name")
+	limitFlag := fs.Int("limit", 25, "Maximum companies to list (0 lists all)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag < 0 {
+		return fmt.Errorf("-limit must not be negative")
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	companies, err := store.GetCompanies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get companies: %w", err)
+	}
+
+	if search := strings.ToLower(strings.TrimSpace(*searchFlag)); search != "" {
+		var matched []models.Company
+		for _, c := range companies {
+			if strings.Contains(strings.ToLower(c.Name), search) || c.Key == models.CompanyKey(search) {
+				matched = append(matched, c)
+			}
+		}
+		companies = matched
+	}
+	total := len(companies)
+	if *limitFlag > 0 && *limitFlag < len(companies) {
+		companies = companies[:*limitFlag]
+	}
+
+	if common.machineReadable() {
+		if companies == nil {
+			companies = []models.Company{}
+		}
+		return export.Write(os.Stdout, *common.output, companies)
+	}
+
+	if len(companies) == 0 {
+		fmt.Println("No companies found.")
+		return nil
+	}
+	fmt.Printf("%-30s %6s %7s %-24s %-10s  %s\n", "COMPANY", "JOBS", "ACTIVE", "SOURCES", "LATEST", "ALSO KNOWN AS")
+	for _, c := range companies {
+		fmt.Printf("%-30.30s %6d %7d %-24.24s %-10s  %s\n", c.Name, c.JobCount, c.ActiveJobs,
+			strings.Join(c.Sources, ","), models.InDisplayZone(c.LatestJobAt).Format("2006-01-02"), strings.Join(c.Aliases, "; "))
+	}
+	if total > len(companies) {
+		fmt.Printf("\nShowing %d of %d companies (-limit 0 lists all).\n", len(companies), total)
+	}
+	return nil
+}
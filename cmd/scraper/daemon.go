@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/schedule"
+)
+
+// daemonRecheck bounds how long the daemon sleeps before looking at the
+// clock again, so a suspended machine or a clock change is caught up with
+const daemonRecheck = time.Minute
+
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	listFlag := fs.Bool("list", false, "List the schedules and when each next runs, then exit")
+	pprofFlag := fs.String("pprof-addr", "", "Serve pprof profiles on this address while the daemon runs (e.g. localhost:6060)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+	app.output = *common.output
+
+	schedules, err := schedule.Parse(app.config.GlobalSettings.Schedules, time.Now().In(models.DisplayLocation()))
+	if err != nil {
+		return errs.WithCause(err, errs.ErrConfig)
+	}
+	if len(schedules) == 0 {
+		return errs.WithCause(fmt.Errorf("no schedules configured; add them to globalSettings.schedules"), errs.ErrConfig)
+	}
+
+	if *listFlag {
+		fmt.Printf("%-20s %-20s %-17s  %s\n", "SCHEDULE", "CRON", "NEXT RUN", "SEARCH")
+		for _, s := range schedules {
+			fmt.Printf("%-20.20s %-20.20s %-17s  %s\n", s.Name, s.Cron, s.Next.Format("2006-01-02 15:04"), describeSchedule(s.Entry))
+		}
+		return nil
+	}
+
+	if *pprofFlag != "" {
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		if err := startPprof(ctx, *pprofFlag, app.logger); err != nil {
+			return fmt.Errorf("failed to start pprof server: %w", err)
+		}
+	}
+
+	// The first interrupt lets the current run finish before exiting; a
+	// second stops it, leaving a checkpoint for scrape -continue
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	stopping, stopScheduling := context.WithCancel(context.Background())
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+	go func() {
+		select {
+		case <-signals:
+		case <-runCtx.Done():
+			return
+		}
+		app.logger.Info("Stopping after the current run; interrupt again to stop it now")
+		stopScheduling()
+		select {
+		case <-signals:
+			cancelRun()
+		case <-runCtx.Done():
+		}
+	}()
+
+	return app.Daemon(stopping, runCtx, schedules)
+}
+
+// Daemon runs each schedule's scrape when its cron expression comes due,
+// until stopping is done. Runs never overlap: schedules due together or
+// while a run goes on run one after another, and a schedule's due times
+// passed while runs went on are skipped rather than run late. runCtx is
+// handed to the runs, so cancelling it stops the one in progress.
+func (app *Application) Daemon(stopping, runCtx context.Context, schedules []*schedule.Schedule) error {
+	// Postings that disappear between runs are expired as the daemon goes on
+	app.expiry.Start(stopping)
+	defer app.expiry.Wait()
+
+	for _, s := range schedules {
+		app.logger.Infof("Schedule %s (%s): %s, next run at %s", s.Name, s.Cron, describeSchedule(s.Entry), s.Next.Format(time.RFC3339))
+	}
+
+	for {
+		now := time.Now().In(models.DisplayLocation())
+		for _, s := range schedule.Due(schedules, now) {
+			if stopping.Err() != nil {
+				break
+			}
+			app.runScheduled(runCtx, s)
+
+			now = time.Now().In(models.DisplayLocation())
+			if skipped := s.Advance(now); skipped > 0 {
+				app.logger.Warnf("Schedule %s skipped %d due runs that came while earlier runs went on", s.Name, skipped)
+			}
+			app.logger.Infof("Schedule %s next runs at %s", s.Name, s.Next.Format(time.RFC3339))
+		}
+
+		wait := min(time.Until(schedule.NextDue(schedules)), daemonRecheck)
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-stopping.Done():
+			timer.Stop()
+			app.logger.Info("Daemon stopped")
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// runScheduled runs one schedule's scrape and logs its summary; a failed
+// run is left for the schedule's next due time
+func (app *Application) runScheduled(ctx context.Context, s *schedule.Schedule) {
+	app.logger.Infof("Starting scheduled run %s", s.Name)
+	app.schedule, app.profile = s.Name, nil
+	var report *models.RunReport
+	app.onRun = func(r models.RunReport) { report = &r }
+	defer func() { app.schedule, app.onRun = "", nil }()
+
+	keywordsList, location, err := app.resolveSearch(strings.Join(s.Keywords, ","), s.Location, s.Resume)
+	if err != nil {
+		app.logger.Errorf("Schedule %s cannot run: %v", s.Name, err)
+		return
+	}
+
+	checkpoint := newRunCheckpoint(app.dataDir, keywordsList, location, s.Resume, app.area)
+	if _, err := app.scrapeRun(ctx, checkpoint); err != nil {
+		app.logger.Errorf("Scheduled run %s failed: %v", s.Name, err)
+	} else {
+		app.autoExport(ctx)
+	}
+	if report == nil {
+		return
+	}
+
+	summary := fmt.Sprintf("Scheduled run %s finished in %s: %d jobs found, %d new, %d duplicates",
+		s.Name, report.FinishedAt.Sub(report.StartedAt).Round(time.Second), report.JobsFound, report.NewJobs, report.Duplicates)
+	if failed := report.FailedSources(); len(failed) > 0 {
+		names := make([]string, len(failed))
+		for i, source := range failed {
+			names[i] = source.Source
+		}
+		summary += fmt.Sprintf("; %d of %d sources failed (%s)", len(failed), len(report.Sources), strings.Join(names, ", "))
+	}
+	app.logger.Info(summary)
+}
+
+// describeSchedule sums up what a schedule searches for
+func describeSchedule(entry schedule.Entry) string {
+	parts := []string{}
+	if len(entry.Keywords) > 0 {
+		parts = append(parts, strings.Join(entry.Keywords, ", "))
+	}
+	if entry.Resume != "" {
+		parts = append(parts, "resume "+entry.Resume)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "default keywords")
+	}
+	location := entry.Location
+	if location == "" {
+		location = "default location"
+	}
+	return strings.Join(parts, " + ") + " in " + location
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hire.ai/pkg/notify"
+)
+
+// digestStateFile records when the last email digest went out, so scheduled
+// runs and the digest command do not send the same period twice
+const digestStateFile = "digest.json"
+
+type digestState struct {
+	LastSent time.Time `json:"last_sent"`
+}
+
+func runDigest(args []string) error {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	periodFlag := fs.String("period", "", "Digest period: daily, weekly or a duration such as 12h (default: the email digest setting, else daily)")
+	formatFlag := fs.String("format", "markdown", "Digest format (markdown, html)")
+	outFlag := fs.String("out", "", "Write the digest to this file instead of stdout")
+	sendFlag := fs.Bool("send", false, "Email the digest using the email notification settings")
+	fs.Parse(args)
+
+	if *formatFlag != "markdown" && *formatFlag != "html" {
+		return fmt.Errorf("unsupported digest format %q: use markdown or html", *formatFlag)
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	email := app.emailNotifier()
+	period := *periodFlag
+	if period == "" && email != nil {
+		period = email.DigestPeriod()
+	}
+	if period == "" {
+		period = notify.PeriodDaily
+	}
+	length, err := notify.ParsePeriod(period)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	until := time.Now()
+	digest, err := app.buildDigest(ctx, period, until.Add(-length), until)
+	if err != nil {
+		return err
+	}
+
+	if *sendFlag {
+		if email == nil {
+			return fmt.Errorf("email notifications are not enabled in the config")
+		}
+		if err := email.SendDigest(ctx, digest); err != nil {
+			return err
+		}
+		return app.saveDigestState(digestState{LastSent: until})
+	}
+
+	var rendered string
+	switch {
+	case *formatFlag == "html" && email != nil:
+		rendered, err = email.DigestHTML(digest)
+	case *formatFlag == "html":
+		rendered, err = digest.HTML()
+	default:
+		rendered, err = digest.Markdown()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	if *outFlag == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(*outFlag, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write digest: %w", err)
+	}
+	fmt.Printf("Wrote digest of %d jobs to %s\n", len(digest.Jobs), *outFlag)
+	return nil
+}
+
+// buildDigest summarises the jobs first stored between since and until
+func (app *Application) buildDigest(ctx context.Context, period string, since, until time.Time) (*notify.Digest, error) {
+	history, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	return notify.BuildDigest(period, history, since, until), nil
+}
+
+// emailNotifier returns the configured email notifier, if any
+func (app *Application) emailNotifier() *notify.EmailNotifier {
+	for _, notifier := range app.notifiers {
+		if email, ok := notifier.(*notify.EmailNotifier); ok {
+			return email
+		}
+	}
+	return nil
+}
+
+// sendDueDigest emails the digest once its period has passed since the last
+// one. The first call only starts the clock.
+func (app *Application) sendDueDigest() {
+	email := app.emailNotifier()
+	if email == nil || email.DigestPeriod() == "" {
+		return
+	}
+	length, err := notify.ParsePeriod(email.DigestPeriod())
+	if err != nil {
+		return
+	}
+
+	state, err := app.loadDigestState()
+	if err != nil {
+		app.logger.Errorf("Failed to read digest state: %v", err)
+		return
+	}
+	now := time.Now()
+	if state.LastSent.IsZero() {
+		if err := app.saveDigestState(digestState{LastSent: now}); err != nil {
+			app.logger.Errorf("Failed to save digest state: %v", err)
+		}
+		return
+	}
+	if now.Sub(state.LastSent) < length {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	digest, err := app.buildDigest(ctx, email.DigestPeriod(), state.LastSent, now)
+	if err != nil {
+		app.logger.Errorf("Failed to build digest: %v", err)
+		return
+	}
+	if err := email.SendDigest(ctx, digest); err != nil {
+		app.logger.Errorf("Digest email failed: %v", err)
+		return
+	}
+	if err := app.saveDigestState(digestState{LastSent: now}); err != nil {
+		app.logger.Errorf("Failed to save digest state: %v", err)
+	}
+}
+
+func (app *Application) loadDigestState() (digestState, error) {
+	var state digestState
+	data, err := os.ReadFile(filepath.Join(app.dataDir, digestStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	return state, json.Unmarshal(data, &state)
+}
+
+func (app *Application) saveDigestState(state digestState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(app.dataDir, digestStateFile)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+)
+
+// runEnrich backfills LLM-read details for stored jobs that have none
+func runEnrich(args []string) error {
+	fs := flag.NewFlagSet("enrich", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	limitFlag := fs.Int("limit", 50, "Maximum number of jobs to enrich")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag <= 0 {
+		return fmt.Errorf("-limit must be positive")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if app.enricher == nil {
+		return fmt.Errorf("enrichment is not enabled in the config")
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	llmCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+	enrichment := func(job *models.Job) interface{} { return job.Enrichment }
+	before := fieldValues(jobs, enrichment)
+	count := app.enricher.EnrichAll(llmCtx, jobs, *limitFlag)
+	app.logAIUsage()
+	if count > 0 {
+		if _, err := app.storage.Upsert(ctx, jobs); err != nil {
+			return fmt.Errorf("failed to store enrichments: %w", err)
+		}
+	}
+
+	if common.machineReadable() {
+		enriched := updatedJobs(jobs, before, enrichment)
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, enriched)
+		}
+		return export.Write(os.Stdout, *common.output, struct {
+			Enriched int          `json:"enriched"`
+			Jobs     []models.Job `json:"jobs"`
+		}{Enriched: count, Jobs: enriched})
+	}
+	if count == 0 {
+		fmt.Println("No jobs were enriched")
+		return nil
+	}
+	fmt.Printf("Enriched %d jobs\n", count)
+	return nil
+}
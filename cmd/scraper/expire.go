@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"hire.ai/pkg/export"
+)
+
+// runExpire revisits the links of stored jobs not seen lately and marks the
+// postings that have been taken down as expired
+func runExpire(args []string) error {
+	fs := flag.NewFlagSet("expire", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	limitFlag := fs.Int("limit", 0, "Maximum number of links to revisit (default: the expiry section's batchSize)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag < 0 {
+		return fmt.Errorf("-limit cannot be negative")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	limit := *limitFlag
+	if limit == 0 {
+		limit = app.expiry.BatchSize()
+	}
+	ctx, stop := commandContext()
+	defer stop()
+	result, err := app.expiry.Check(ctx, limit)
+	if result == nil {
+		return err
+	}
+	if err != nil && !errors.Is(err, ctx.Err()) {
+		return err
+	}
+
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, result.Expired)
+		}
+		return export.Write(os.Stdout, *common.output, result)
+	}
+	if result.Checked == 0 && result.Deferred == 0 {
+		fmt.Println("No stored job links are due for a check")
+		return nil
+	}
+	fmt.Printf("Checked %d job links: %d expired, %d could not be checked\n", result.Checked, len(result.Expired), result.Failed)
+	for _, expired := range result.Expired {
+		fmt.Printf("  %s at %s (%s)\n    %s\n", expired.Title, expired.Company, expired.Reason, expired.Link)
+	}
+	if result.Deferred > 0 {
+		fmt.Printf("%d links were left for later because their sites' request budgets are spent\n", result.Deferred)
+	}
+	return err
+}
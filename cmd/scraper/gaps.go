@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/resume"
+	"hire.ai/pkg/storage"
+)
+
+// runGaps reports the skills the stored jobs that best match a resume ask
+// for most often and the resume lacks
+func runGaps(args []string) error {
+	fs := flag.NewFlagSet("gaps", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	resumeFlag := fs.String("resume", "", "Resume (PDF, DOCX or text) to compare against")
+	skillsFlag := fs.String("skills", "", "Skills you have (comma-separated), added to any from -resume")
+	topFlag := fs.Int("top", 50, "Number of best-matching jobs to analyse")
+	limitFlag := fs.Int("limit", 15, "Number of missing skills to report")
+	examplesFlag := fs.Int("examples", 3, "Example postings shown per missing skill")
+	sinceFlag := fs.String("since", "", "Only analyse jobs scraped within this age (e.g. 30d, 2w)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *resumeFlag == "" && *skillsFlag == "" {
+		return fmt.Errorf("set -resume or -skills to compare the jobs against")
+	}
+	if *topFlag <= 0 || *limitFlag <= 0 || *examplesFlag < 0 {
+		return fmt.Errorf("-top and -limit must be positive and -examples not negative")
+	}
+
+	profile, err := loadProfile(*resumeFlag, *skillsFlag)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := store.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	if *sinceFlag != "" {
+		age, err := parseAge(*sinceFlag)
+		if err != nil {
+			return err
+		}
+		cutoff := time.Now().Add(-age)
+		var recent []models.Job
+		for _, job := range jobs {
+			if job.ScrapedAt.After(cutoff) {
+				recent = append(recent, job)
+			}
+		}
+		jobs = recent
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("no stored jobs to analyse; run a scrape first")
+	}
+
+	report := profile.SkillGaps(jobs, *topFlag, *examplesFlag)
+	if len(report.Missing) > *limitFlag {
+		report.Missing = report.Missing[:*limitFlag]
+	}
+
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, report.Missing)
+		}
+		return export.Write(os.Stdout, *common.output, report)
+	}
+	printGapReport(report)
+	return nil
+}
+
+// loadProfile parses the resume at path, if any, and adds the
+// comma-separated skills to it
+func loadProfile(path, skills string) (*resume.Profile, error) {
+	profile := &resume.Profile{}
+	if path != "" {
+		parsed, err := resume.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse resume: %w", err)
+		}
+		profile = parsed
+	}
+	for _, skill := range splitList(skills) {
+		if skill = strings.ToLower(skill); !containsString(profile.Skills, skill) {
+			profile.Skills = append(profile.Skills, skill)
+		}
+	}
+	return profile, nil
+}
+
+func printGapReport(report *resume.GapReport) {
+	fmt.Printf("Skill gaps across your %d best-matching jobs\n", report.JobsAnalyzed)
+	if len(report.ResumeSkills) > 0 {
+		fmt.Printf("Your skills: %s\n", strings.Join(report.ResumeSkills, ", "))
+	}
+
+	if len(report.Missing) == 0 {
+		fmt.Println("\nThese jobs ask for no skills you are missing.")
+	} else {
+		fmt.Println("\nMost requested skills you are missing:")
+		for i, gap := range report.Missing {
+			jobs := "jobs"
+			if gap.Jobs == 1 {
+				jobs = "job"
+			}
+			fmt.Printf("\n%2d. %s: %d %s (%.0f%%)\n", i+1, gap.Skill, gap.Jobs, jobs, gap.Share*100)
+			for _, example := range gap.Examples {
+				fmt.Printf("      - %s at %s (match %.0f%%)\n", example.Title, example.Company, example.Score*100)
+				if example.Link != "" {
+					fmt.Printf("        %s\n", example.Link)
+				}
+			}
+		}
+	}
+
+	if len(report.Covered) > 0 {
+		var covered []string
+		for _, skill := range report.Covered[:min(len(report.Covered), 10)] {
+			covered = append(covered, fmt.Sprintf("%s (%d)", skill.Skill, skill.Jobs))
+		}
+		fmt.Printf("\nYour skills these jobs ask for most: %s\n", strings.Join(covered, ", "))
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hire.ai/pkg/events"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/importer"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/storage"
+)
+
+// importedJob is one imported row as the import command reports it
+type importedJob struct {
+	JobID   string `json:"job_id"`
+	Title   string `json:"title"`
+	Company string `json:"company"`
+	Status  string `json:"status"`
+	New     bool   `json:"new"`
+}
+
+// runImport stores the jobs of a LinkedIn export or another CSV or JSON
+// list through the scrape pipeline, so they are scored and deduplicated
+// like scraped ones, and tracks each as an application
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	formatFlag := fs.String("format", importer.FormatAuto, "File format: auto, linkedin, csv or json")
+	mapFlag := fs.String("map", "", "Columns holding each field, as field=column pairs separated by commas (fields: "+strings.Join(importer.Fields, ", ")+")")
+	sourceFlag := fs.String("source", "", "Source to store the jobs under (default: LinkedIn for LinkedIn exports, Import otherwise)")
+	keywordsFlag := fs.String("keywords", "", "Comma-separated keywords to score the jobs' relevance against")
+	statusFlag := fs.String("status", models.ApplicationSaved, "Application status for jobs the file gives none")
+	userFlag := fs.String("user", "", "User to track the jobs for (default: the default profile)")
+	dryRunFlag := fs.Bool("dry-run", false, "Show what would be imported without storing anything")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper import [-format auto|linkedin|csv|json] [-map field=column,...] <file, or - for stdin>")
+	}
+	if err := models.ValidateApplicationStatus(*statusFlag); err != nil {
+		return err
+	}
+	mapping, err := importer.ParseMapping(*mapFlag)
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	var data []byte
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	result, err := importer.Parse(data, *formatFlag, mapping, *sourceFlag)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", skipped)
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	// Jobs at companies the allow and deny lists leave out are skipped
+	// here, as the pipeline would drop them without their rows knowing
+	var records []importer.Record
+	for _, record := range result.Records {
+		if !models.CompanyAllowed(record.Job.Company) {
+			fmt.Fprintf(os.Stderr, "Skipped %s at %s: company not allowed\n", record.Job.Title, record.Job.Company)
+			continue
+		}
+		if record.Status != "" && models.ValidateApplicationStatus(record.Status) != nil {
+			fmt.Fprintf(os.Stderr, "Tracking %s as %s: unknown status %q\n", record.Job.Title, *statusFlag, record.Status)
+			record.Status = ""
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no jobs to import in %s", path)
+	}
+
+	if *dryRunFlag {
+		jobs := make([]models.Job, len(records))
+		for i := range records {
+			jobs[i] = records[i].Job
+		}
+		if common.machineReadable() {
+			return export.Write(os.Stdout, *common.output, jobs)
+		}
+		fmt.Printf("Would import %d jobs from %s (%s):\n", len(jobs), filepath.Base(path), result.Format)
+		for i := range records {
+			fmt.Printf("  %s at %s  %s\n", records[i].Job.Title, records[i].Job.Company, records[i].Job.Link)
+		}
+		return nil
+	}
+
+	applications, err := storage.NewApplicationStore(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open applications: %w", err)
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	// The pipeline updates the batch in place, so jobs carries the IDs of
+	// the stored jobs near-duplicates were pointed at
+	jobs := make([]models.Job, len(records))
+	for i := range records {
+		jobs[i] = records[i].Job
+	}
+	// Imported jobs are kept whether or not they match a boolean query;
+	// its terms only score them
+	keywords := splitList(*keywordsFlag)
+	expression, err := models.KeywordExpression(keywords)
+	if err != nil {
+		return fmt.Errorf("invalid -keywords: %w", err)
+	}
+	if expression != nil {
+		keywords, _ = expression.Terms()
+	}
+	runID := scraper.NewRunID()
+	inserted := make(map[string]bool)
+	pipeline, err := app.runPipeline(keywords, nil, nil, func(out chan<- []models.Job) error {
+		defer close(out)
+		out <- jobs
+		return nil
+	}, nil, func(newJobs, updated []models.Job) {
+		app.publish(events.JobCreated, runID, newJobs)
+		app.publish(events.JobUpdated, runID, app.storedCopies(updated))
+		for i := range newJobs {
+			inserted[newJobs[i].ID] = true
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	stored, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	imported := make([]importedJob, 0, len(jobs))
+	for i := range jobs {
+		id := storedID(stored, &jobs[i])
+		row := importedJob{JobID: id, Title: jobs[i].Title, Company: jobs[i].Company, New: inserted[id]}
+
+		// Tracking already kept for a job is only replaced by a status the
+		// file gives
+		application := &models.Application{JobID: id, UserID: *userFlag, Status: records[i].Status, Notes: records[i].Notes}
+		existing, err := applications.Get(*userFlag, id)
+		if err == nil {
+			if application.Status == "" {
+				application.Status = existing.Status
+			}
+			if application.Notes == "" {
+				application.Notes = existing.Notes
+			}
+		}
+		if application.Status == "" {
+			application.Status = *statusFlag
+		}
+		if err := applications.Set(application); err != nil {
+			return fmt.Errorf("failed to track job %s: %w", id, err)
+		}
+		row.Status = application.Status
+		imported = append(imported, row)
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, imported)
+	}
+	fmt.Printf("Imported %d jobs from %s: %d new, %d already stored\n",
+		len(imported), filepath.Base(path), pipeline.inserted, len(imported)-pipeline.inserted)
+	for _, row := range imported {
+		marker := " "
+		if row.New {
+			marker = "+"
+		}
+		fmt.Printf("%s %-12s %s at %s  [%s]\n", marker, row.Status, row.Title, row.Company, row.JobID)
+	}
+	return nil
+}
+
+// storedID returns the ID job is stored under: its own, or that of the
+// stored job with the same title and company storage merged it into
+func storedID(stored []models.Job, job *models.Job) string {
+	for i := range stored {
+		if stored[i].ID == job.ID {
+			return job.ID
+		}
+	}
+	for i := range stored {
+		if job.IsDuplicate(&stored[i]) {
+			return stored[i].ID
+		}
+	}
+	return job.ID
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+)
+
+// knownJobs builds the check a -since-last-run scrape stops paging on. A
+// scraped job was found before when it is stored under its ID or its title
+// and company, or when its board dates it before the board was last
+// scraped. Boards never scraped before are read in full.
+func (app *Application) knownJobs(ctx context.Context) (scraper.KnownJobs, error) {
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs for an incremental scrape: %w", err)
+	}
+
+	stored := make(map[string]bool, 2*len(jobs))
+	lastScraped := make(map[string]time.Time)
+	for i := range jobs {
+		stored[jobs[i].ID] = true
+		stored[models.PostingKey(&jobs[i])] = true
+		if jobs[i].UpdatedAt.After(lastScraped[jobs[i].Source]) {
+			lastScraped[jobs[i].Source] = jobs[i].UpdatedAt
+		}
+	}
+	app.logger.Infof("Incremental scrape: stopping boards at the first page of jobs among %d stored", len(jobs))
+
+	return func(job *models.Job) bool {
+		if stored[job.ID] || stored[models.PostingKey(job)] {
+			return true
+		}
+		last, ok := lastScraped[job.Source]
+		return ok && job.PostedAt != nil && job.PostedAt.Before(last)
+	}, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// setupLogger builds the CLI logger. Format is "text" or "json"; when file is
+// set, logs are appended to it in addition to stderr so daemon output can be
+// shipped to Loki/ELK without losing the console view.
+func setupLogger(verbose bool, format, file string) (*logrus.Logger, error) {
+	logger := logrus.New()
+	if verbose {
+		logger.SetLevel(logrus.DebugLevel)
+	}
+
+	switch format {
+	case "", "text":
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s (use text or json)", format)
+	}
+
+	if file != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		logger.SetOutput(io.MultiWriter(os.Stderr, f))
+	}
+
+	return logger, nil
+}
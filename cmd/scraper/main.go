@@ -1,58 +1,129 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	// Embeds the zone database for hosts and containers without one
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 
+	"hire.ai/pkg/ai"
 	"hire.ai/pkg/api"
+	"hire.ai/pkg/ask"
+	"hire.ai/pkg/classify"
+	"hire.ai/pkg/dedup"
+	"hire.ai/pkg/enrich"
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/events"
+	"hire.ai/pkg/expiry"
 	"hire.ai/pkg/export"
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/hooks"
 	"hire.ai/pkg/keywords"
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/notify"
+	"hire.ai/pkg/prep"
+	"hire.ai/pkg/providers"
+	"hire.ai/pkg/relevance"
+	"hire.ai/pkg/resume"
+	"hire.ai/pkg/salary"
 	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/semantic"
 	"hire.ai/pkg/storage"
+	"hire.ai/pkg/summarize"
+)
+
+// Files kept in the data directory
+const (
+	salaryCacheFile    = "salary_cache.json"
+	embeddingCacheFile = "embeddings.json"
+	searchIndexFile    = "search_index.json"
+	semanticIndexFile  = "semantic_index.json"
+	proxyUsageFile     = "proxy_usage.json"
+	seenIndexFile      = "seen_jobs.json"
+	geocodeCacheFile   = "geocode_cache.json"
+	exchangeRatesFile  = "exchange_rates.json"
+	requestBudgetFile  = "request_budgets.json"
+	apiUsageFile       = "api_usage.json"
+	// recordingsDir holds the responses -record saves and -offline replays
+	recordingsDir = "recordings"
 )
 
 func main() {
 	// Load environment variables
 	godotenv.Load()
 
+	// Dispatch subcommands; plain flags fall through to the classic scrape
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
 	// Command line flags
 	var (
-		keywordsFlag    = flag.String("keywords", "", "Job search keywords (comma-separated)")
-		locationFlag    = flag.String("location", "", "Job location")
-		configFlag      = flag.String("config", "config/job-boards.json", "Path to job boards configuration")
-		dataFlag        = flag.String("data", "data", "Data directory for storage")
-		verboseFlag     = flag.Bool("verbose", false, "Verbose logging")
-		exportFlag      = flag.String("export", "", "Export format (csv, json) - if specified, exports and exits")
-		exportFileFlag  = flag.String("export-file", "", "Custom export filename")
-		apiStatsFlag    = flag.Bool("api-stats", false, "Show API provider statistics and exit")
-		validateAPIFlag = flag.Bool("validate-api", false, "Validate API credentials and exit")
+		keywordsFlag     = flag.String("keywords", "", "Job search keywords (comma-separated), or a boolean query with capitalised AND, OR or NOT such as \"golang AND (kubernetes OR terraform) NOT intern\"")
+		locationFlag     = flag.String("location", "", "Job location")
+		configFlag       = flag.String("config", "config/job-boards.json", "Path to job boards configuration")
+		dataFlag         = flag.String("data", "data", "Data directory for storage")
+		verboseFlag      = flag.Bool("verbose", false, "Verbose logging")
+		exportFlag       = flag.String("export", "", "Export format (csv, json) - if specified, exports and exits")
+		exportFileFlag   = flag.String("export-file", "", "Custom export filename")
+		apiStatsFlag     = flag.Bool("api-stats", false, "Show API provider statistics and exit")
+		validateAPIFlag  = flag.Bool("validate-api", false, "Validate API credentials and exit")
+		outputFlag       = flag.String("output", export.FormatTable, "Output format for results (table, json, yaml, csv)")
+		logFormatFlag    = flag.String("log-format", os.Getenv("LOG_FORMAT"), "Log format (text, json)")
+		logFileFlag      = flag.String("log-file", os.Getenv("LOG_FILE"), "Also append logs to this file")
+		resumeFlag       = flag.String("resume", "", "Resume (PDF, DOCX or text) to derive keywords and boost matching jobs")
+		offlineFlag      = flag.Bool("offline", false, "Answer every request from the recordings in the data directory instead of the network")
+		recordFlag       = flag.Bool("record", false, "Save every response to the recordings in the data directory for -offline runs")
+		summaryFlag      = flag.String("summary-file", "", "Write a JSON summary of the run (outcome, exit code, counts and errors per source) to this path")
+		strictFlag       = flag.Bool("strict", false, "Exit non-zero when some sources failed (9) or no new jobs were found (10)")
+		sinceLastRunFlag = flag.Bool("since-last-run", false, "Stop reading a board's result pages at the first page with no job found before")
 	)
 	flag.Parse()
 
 	// Setup logging
-	logger := logrus.New()
-	if *verboseFlag {
-		logger.SetLevel(logrus.DebugLevel)
+	logger, err := setupLogger(*verboseFlag, *logFormatFlag, *logFileFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := export.ValidateFormat(*outputFlag); err != nil {
+		logger.Fatal(err)
+	}
+	if err := setRecording(*offlineFlag, *recordFlag, *dataFlag); err != nil {
+		logger.Fatal(err)
 	}
 
 	// Initialize components
 	app, err := NewApplication(*configFlag, *dataFlag, logger)
 	if err != nil {
-		logger.Fatalf("Failed to initialize application: %v", err)
+		logger.Errorf("Failed to initialize application: %v", err)
+		os.Exit(exitCode(finishRun(nil, err, *strictFlag, *summaryFlag)))
 	}
 	defer app.Close()
+	app.output = *outputFlag
+	app.sinceLastRun = *sinceLastRunFlag
+	var report *models.RunReport
+	app.onRun = func(r models.RunReport) {
+		report = &r
+	}
 
 	// Check if we should export existing data without scraping
 	if *exportFlag != "" {
-		if err := app.ExportExistingData(*exportFlag, *exportFileFlag); err != nil {
+		ctx, stop := commandContext()
+		defer stop()
+		if err := app.ExportExistingData(ctx, *exportFlag, *exportFileFlag); err != nil {
 			logger.Fatalf("Export failed: %v", err)
 		}
 		return
@@ -66,55 +137,19 @@ func main() {
 
 	// Check if we should validate API credentials
 	if *validateAPIFlag {
-		app.ValidateAndShowAPICredentials()
+		ctx, stop := commandContext()
+		defer stop()
+		app.ValidateAndShowAPICredentials(ctx)
 		return
 	}
 
-	// Get keywords from flag or environment
-	keywordsInput := *keywordsFlag
-	if keywordsInput == "" {
-		keywordsInput = os.Getenv("DEFAULT_KEYWORDS")
-	}
-	if keywordsInput == "" {
-		logger.Fatal("No keywords provided. Use -keywords flag or set DEFAULT_KEYWORDS environment variable")
-	}
-
-	// Get location from flag or environment
-	location := *locationFlag
-	if location == "" {
-		location = os.Getenv("DEFAULT_LOCATION")
-	}
-	if location == "" {
-		location = "Remote"
-	}
-
-	logger.Infof("Starting job scraper with keywords: %s, location: %s", keywordsInput, location)
-
-	// Process keywords
-	keywordsList := strings.Split(keywordsInput, ",")
-	for i := range keywordsList {
-		keywordsList[i] = strings.TrimSpace(keywordsList[i])
-	}
-
-	// Run the scraping process
-	if err := app.ScrapeJobs(keywordsList, location); err != nil {
-		logger.Fatalf("Scraping failed: %v", err)
-	}
-
-	// Display results
-	if err := app.DisplayResults(); err != nil {
-		logger.Errorf("Failed to display results: %v", err)
-	}
-
-	// Auto-export if configured
-	if len(app.config.GlobalSettings.ExportFormats) > 0 {
-		for _, format := range app.config.GlobalSettings.ExportFormats {
-			if err := app.ExportExistingData(format, ""); err != nil {
-				logger.Warnf("Auto-export to %s failed: %v", format, err)
-			} else {
-				logger.Infof("Auto-exported data to %s format", format)
-			}
-		}
+	ctx, stop := commandContext()
+	defer stop()
+	err = app.Run(ctx, *keywordsFlag, *locationFlag, *resumeFlag)
+	if err = finishRun(report, err, *strictFlag, *summaryFlag); err != nil {
+		logger.Error(err)
+		app.Close()
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -125,12 +160,48 @@ type Application struct {
 	csvExporter      *export.CSVExporter
 	logger           *logrus.Logger
 	config           *scraper.Config
+	output           string
+	profile          *resume.Profile
+	users            *storage.UserStore
+	searches         *storage.SavedSearchStore
+	alerts           *storage.AlertStore
+	runs             *storage.RunHistory
+	notifiers        []notify.Notifier
+	publishers       []events.Publisher
+	summarizer       *summarize.Summarizer
+	salaries         *salary.Extractor
+	classifier       *classify.Classifier
+	enricher         *enrich.Enricher
+	duplicates       *dedup.Detector
+	seen             *dedup.SeenIndex
+	assistant        *ask.Assistant
+	semantic         *semantic.Index
+	prep             *prep.Generator
+	geocoder         *geo.Geocoder
+	scorer           relevance.Scorer
+	expiry           *expiry.Checker
+	hooks            *hooks.Runner
+	dataDir          string
+	// area, when set, keeps scrapes to the jobs near a place
+	area *searchArea
+	// sinceLastRun stops reading a board's result pages once a page has
+	// only jobs found before
+	sinceLastRun bool
+	// schedule names the daemon schedule running, for the run history
+	schedule string
+
+	// onNewJobs, when set, receives the jobs a scrape stored that were not
+	// already in storage; serve uses it to drive webhooks
+	onNewJobs func(jobs []models.Job)
+	// onRun, when set, receives the report of each finished scrape run;
+	// -summary-file uses it
+	onRun func(report models.RunReport)
 }
 
 // NewApplication creates a new application instance with the specified configuration
 func NewApplication(configPath, dataDir string, logger *logrus.Logger) (*Application, error) {
 	// Initialize scraper
-	scraperCore, err := scraper.NewScraperCore(configPath)
+	scraperCore, err := scraper.NewScraperCoreWithLogger(configPath, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scraper: %w", err)
 	}
@@ -144,6 +215,16 @@ func NewApplication(configPath, dataDir string, logger *logrus.Logger) (*Applica
 		return nil, fmt.Errorf("failed to create storage: %w", err)
 	}
 
+	if err := scraperCore.TrackProxyUsage(filepath.Join(dataDir, proxyUsageFile)); err != nil {
+		logger.Warnf("Failed to load proxy usage: %v", err)
+	}
+	if err := scraperCore.TrackRequestBudgets(filepath.Join(dataDir, requestBudgetFile)); err != nil {
+		logger.Warnf("Failed to load request budgets: %v", err)
+	}
+	if err := scraperCore.TrackAPIUsage(filepath.Join(dataDir, apiUsageFile)); err != nil {
+		logger.Warnf("Failed to load API usage: %v", err)
+	}
+
 	// Initialize keyword processor
 	keywordProcessor := keywords.NewKeywordProcessor()
 
@@ -154,50 +235,695 @@ func NewApplication(configPath, dataDir string, logger *logrus.Logger) (*Applica
 	}
 	csvExporter := export.NewCSVExporter(exportPath)
 
+	// User data lets notifiers honour each user's notification settings
+	users, err := storage.NewUserStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	searches, err := storage.NewSavedSearchStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := storage.NewAlertStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := storage.NewRunHistory(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	notifiers, err := notify.New(config.GlobalSettings.Notifications, notify.Stores{Users: users, Searches: searches}, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up notifications: %w", err), errs.ErrConfig)
+	}
+	publishers, err := events.New(config.GlobalSettings.Events)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up event publishing: %w", err), errs.ErrConfig)
+	}
+	// AI features share the llm section's server and key and pick their own
+	// models, so they can all run against a local Ollama or vLLM
+	llmDefaults := config.GlobalSettings.LLM
+	summarizer, err := summarize.New(config.GlobalSettings.Summarization, llmDefaults, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up summarization: %w", err), errs.ErrConfig)
+	}
+	salaries, err := salary.New(config.GlobalSettings.SalaryExtraction, llmDefaults, filepath.Join(dataDir, salaryCacheFile), logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up salary extraction: %w", err), errs.ErrConfig)
+	}
+	classifier, err := classify.New(config.GlobalSettings.Classification, llmDefaults, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up classification: %w", err), errs.ErrConfig)
+	}
+	enricher, err := enrich.New(config.GlobalSettings.Enrichment, llmDefaults, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up enrichment: %w", err), errs.ErrConfig)
+	}
+	duplicates, err := dedup.New(config.GlobalSettings.Deduplication, llmDefaults, filepath.Join(dataDir, embeddingCacheFile), logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up deduplication: %w", err), errs.ErrConfig)
+	}
+	seen, err := loadSeenIndex(filepath.Join(dataDir, seenIndexFile), fileStorage, logger)
+	if err != nil {
+		return nil, err
+	}
+	fileStorage.SetSeenIndex(seen)
+	assistant, err := ask.New(config.GlobalSettings.Ask, llmDefaults, filepath.Join(dataDir, searchIndexFile), logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up question answering: %w", err), errs.ErrConfig)
+	}
+	semanticIndex, err := semantic.New(config.GlobalSettings.SemanticSearch, llmDefaults, filepath.Join(dataDir, semanticIndexFile), logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up semantic search: %w", err), errs.ErrConfig)
+	}
+	if semanticIndex != nil {
+		fileStorage.SetSemanticRanker(semanticIndex)
+	}
+	prepGenerator, err := prep.New(config.GlobalSettings.InterviewPrep, llmDefaults, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up interview prep: %w", err), errs.ErrConfig)
+	}
+	geocoder, err := geo.New(config.GlobalSettings.Geocoding, filepath.Join(dataDir, geocodeCacheFile), logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up geocoding: %w", err), errs.ErrConfig)
+	}
+	ratesCtx, cancelRates := context.WithTimeout(context.Background(), 30*time.Second)
+	err = salary.LoadRates(ratesCtx, config.GlobalSettings.ExchangeRates, filepath.Join(dataDir, exchangeRatesFile), logger)
+	cancelRates()
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("invalid globalSettings.exchangeRates: %w", err), errs.ErrConfig)
+	}
+	scorer, err := relevance.New(config.GlobalSettings.Relevance, fileStorage.GetAll, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("invalid globalSettings.relevance: %w", err), errs.ErrConfig)
+	}
+	pipelineHooks, err := hooks.New(config.GlobalSettings.Hooks, logger)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to set up hooks: %w", err), errs.ErrConfig)
+	}
+
+	store := storage.NewCachedStorage(fileStorage, storage.DefaultCacheSize)
+	expiryChecker := expiry.New(config.GlobalSettings.Expiry, scraperCore.Revisit, store, logger)
+
 	return &Application{
 		scraper:          scraperCore,
-		storage:          fileStorage,
+		storage:          store,
 		keywordProcessor: keywordProcessor,
 		csvExporter:      csvExporter,
 		logger:           logger,
 		config:           &config,
+		output:           export.FormatTable,
+		users:            users,
+		searches:         searches,
+		alerts:           alerts,
+		runs:             runs,
+		notifiers:        notifiers,
+		publishers:       publishers,
+		summarizer:       summarizer,
+		salaries:         salaries,
+		classifier:       classifier,
+		enricher:         enricher,
+		duplicates:       duplicates,
+		seen:             seen,
+		assistant:        assistant,
+		semantic:         semanticIndex,
+		prep:             prepGenerator,
+		geocoder:         geocoder,
+		scorer:           scorer,
+		expiry:           expiryChecker,
+		hooks:            pipelineHooks,
+		dataDir:          dataDir,
 	}, nil
 }
 
-func (app *Application) ScrapeJobs(keywordsList []string, location string) error {
+// loadSeenIndex loads the seen job index, building it from the stored jobs
+// when there is none yet, it has filled up or it is older than the stored
+// jobs, which it might then be missing some of
+func loadSeenIndex(path string, store *storage.FileStorage, logger *logrus.Logger) (*dedup.SeenIndex, error) {
+	seen, err := dedup.LoadSeenIndex(path)
+	if err != nil {
+		logger.Warnf("Rebuilding seen job index: %v", err)
+	}
+	if seen != nil {
+		if info, err := os.Stat(path); err == nil && !info.ModTime().Before(store.ModTime()) {
+			return seen, nil
+		}
+	}
+
+	jobs, err := store.GetAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs for the seen job index: %w", err)
+	}
+	seen = dedup.BuildSeenIndex(path, jobs)
+	if err := seen.Save(); err != nil {
+		logger.Warnf("Failed to save seen job index: %v", err)
+	}
+	return seen, nil
+}
+
+// Run resolves keywords and location (flags, then resume, then environment),
+// scrapes, displays the results and performs any configured auto-export.
+// Cancelling ctx stops the scrape after the sources already running; what
+// they found is stored and Continue picks up the rest.
+func (app *Application) Run(ctx context.Context, keywordsInput, location, resumePath string) error {
+	keywordsList, location, err := app.resolveSearch(keywordsInput, location, resumePath)
+	if err != nil {
+		return err
+	}
+	return app.runToEnd(ctx, newRunCheckpoint(app.dataDir, keywordsList, location, resumePath, app.area))
+}
+
+// Continue finishes the last interrupted run, scraping only the sources
+// whose jobs it had not stored, with the run's keywords, location and resume
+func (app *Application) Continue(ctx context.Context) error {
+	checkpoint, err := loadRunCheckpoint(app.dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to read scrape checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		return fmt.Errorf("no interrupted scrape run to continue")
+	}
+	if _, _, err := app.resolveSearch(strings.Join(checkpoint.Keywords, ","), checkpoint.Location, checkpoint.Resume); err != nil {
+		return err
+	}
+	app.area = checkpoint.Area
+	app.logger.Infof("Continuing the run started %s; %d sources already done",
+		models.InDisplayZone(checkpoint.StartedAt).Format("2006-01-02 15:04"), len(checkpoint.Completed))
+	return app.runToEnd(ctx, checkpoint)
+}
+
+// runToEnd scrapes the sources checkpoint has not completed, then displays
+// the results and auto-exports
+func (app *Application) runToEnd(ctx context.Context, checkpoint *runCheckpoint) error {
+	if _, err := app.scrapeRun(ctx, checkpoint); err != nil {
+		return fmt.Errorf("scraping failed: %w", err)
+	}
+
+	// Display results
+	if err := app.DisplayResults(ctx); err != nil {
+		app.logger.Errorf("Failed to display results: %v", err)
+	}
+
+	app.autoExport(ctx)
+	return nil
+}
+
+// Watch scrapes immediately and then every interval until ctx is cancelled.
+// Failed runs are logged and retried at the next tick; new jobs reach the
+// configured notifiers after each run. Stored links are checked for expired
+// postings meanwhile when the expiry section enables it.
+func (app *Application) Watch(ctx context.Context, keywordsInput, location, resumePath string, interval time.Duration) error {
+	keywordsList, location, err := app.resolveSearch(keywordsInput, location, resumePath)
+	if err != nil {
+		return err
+	}
+
+	app.logger.Infof("Watching for new jobs every %v", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Postings that disappear between runs are expired as the watch goes on
+	app.expiry.Start(ctx)
+	defer app.expiry.Wait()
+
+	for {
+		checkpoint := newRunCheckpoint(app.dataDir, keywordsList, location, resumePath, app.area)
+		if _, err := app.scrapeRun(ctx, checkpoint); err != nil {
+			app.logger.Errorf("Scraping failed: %v", err)
+		} else {
+			app.autoExport(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			app.logger.Info("Stopped watching")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// resolveSearch picks keywords from the flag, resume or environment and the
+// location from the flag or environment, parsing the resume into app.profile
+func (app *Application) resolveSearch(keywordsInput, location, resumePath string) ([]string, string, error) {
+	if resumePath != "" {
+		profile, err := resume.ParseFile(resumePath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse resume: %w", err)
+		}
+		app.profile = profile
+		app.logger.Infof("Parsed resume: %d skills, titles %v, %d years of experience",
+			len(profile.Skills), profile.Titles, profile.YearsExperience)
+	}
+
+	// Get keywords from flag, resume or environment
+	var keywordsList []string
+	if keywordsInput != "" {
+		keywordsList = strings.Split(keywordsInput, ",")
+	} else if app.profile != nil {
+		keywordsList = app.profile.Keywords(2, 5)
+	} else if envKeywords := os.Getenv("DEFAULT_KEYWORDS"); envKeywords != "" {
+		keywordsList = strings.Split(envKeywords, ",")
+	}
+	for i := range keywordsList {
+		keywordsList[i] = strings.TrimSpace(keywordsList[i])
+	}
+	if len(keywordsList) == 0 {
+		return nil, "", fmt.Errorf("no keywords provided. Use -keywords or -resume, or set DEFAULT_KEYWORDS environment variable")
+	}
+	if _, err := models.KeywordExpression(keywordsList); err != nil {
+		return nil, "", fmt.Errorf("invalid keywords: %w", err)
+	}
+
+	// Get location from flag or environment
+	if location == "" {
+		location = os.Getenv("DEFAULT_LOCATION")
+	}
+	if location == "" {
+		location = "Remote"
+	}
+
+	app.logger.Infof("Starting job scraper with keywords: %s, location: %s", strings.Join(keywordsList, ","), location)
+	return keywordsList, location, nil
+}
+
+// autoExport writes stored jobs in each configured export format
+func (app *Application) autoExport(ctx context.Context) {
+	for _, format := range app.config.GlobalSettings.ExportFormats {
+		if err := app.ExportExistingData(ctx, format, ""); err != nil {
+			app.logger.Warnf("Auto-export to %s failed: %v", format, err)
+		} else {
+			app.logger.Infof("Auto-exported data to %s format", format)
+		}
+	}
+}
+
+// ScrapeJobs scrapes every enabled source, scores and stores the results, and
+// returns the number of jobs scraped. progress, if not nil, receives
+// per-source updates. Cancelling ctx stops the scrape early; the jobs found
+// until then are still stored.
+func (app *Application) ScrapeJobs(ctx context.Context, keywordsList []string, location string, progress scraper.ProgressFunc) (int, error) {
+	return app.scrapeSources(ctx, nil, keywordsList, location, progress, nil)
+}
+
+// ScrapeBoards scrapes, scores and stores like ScrapeJobs, but only from the
+// named job boards
+func (app *Application) ScrapeBoards(ctx context.Context, boards, keywordsList []string, location string, progress scraper.ProgressFunc) (int, error) {
+	if len(boards) == 0 {
+		return 0, fmt.Errorf("no job boards named")
+	}
+	return app.scrapeSources(ctx, boards, keywordsList, location, progress, nil)
+}
+
+// scrapeRun scrapes every enabled source checkpoint has not completed,
+// recording each source in it once its jobs are stored. The checkpoint is
+// removed when the run completes and kept when it is interrupted or fails.
+func (app *Application) scrapeRun(ctx context.Context, checkpoint *runCheckpoint) (int, error) {
+	if err := checkpoint.save(); err != nil {
+		app.logger.Warnf("Failed to save scrape checkpoint: %v", err)
+	}
+	scraped, err := app.scrapeSources(ctx, nil, checkpoint.Keywords, checkpoint.Location, nil, checkpoint)
+	if err == nil {
+		if err := checkpoint.remove(); err != nil {
+			app.logger.Warnf("Failed to remove scrape checkpoint: %v", err)
+		}
+	} else if ctx.Err() != nil {
+		app.logger.Info("Run interrupted; continue it with 'job-scraper scrape -continue'")
+	}
+	return scraped, err
+}
+
+// scrapeSources runs a scrape of the named boards, or of every enabled
+// source when boards is empty. checkpoint, if not nil, skips the sources it
+// lists and records those that finish.
+func (app *Application) scrapeSources(ctx context.Context, boards, keywordsList []string, location string, progress scraper.ProgressFunc, checkpoint *runCheckpoint) (int, error) {
 	start := time.Now()
 	app.logger.Infof("Starting job scraping process...")
 
 	// Process keywords
-	keywordsStr := strings.Join(keywordsList, " ")
-	query := app.keywordProcessor.ProcessKeywords(keywordsStr)
+	query, err := app.keywordProcessor.ProcessQuery(keywordsList)
+	if err != nil {
+		return 0, fmt.Errorf("invalid keywords: %w", err)
+	}
 	query.Location = location
 
 	app.logger.Infof("Processed keywords: %v", query.Keywords)
+	if query.Expression != nil {
+		app.logger.Infof("Keeping jobs matching %s", query.Expression)
+	}
+
+	// Providers that take a radius or a date posted window search them
+	// themselves; the pipeline drops the jobs of other sources located
+	// outside the radius or posted before the window
+	var area *models.JobFilter
+	if app.area != nil {
+		area = &models.JobFilter{Near: app.area.Near, RadiusKm: app.area.RadiusKm}
+		if area.Near != "" {
+			if err := app.geocoder.ResolveFilter(ctx, area); err != nil {
+				return 0, err
+			}
+			ctx = scraper.WithRadius(ctx, area.RadiusKm)
+			app.logger.Infof("Keeping jobs within %.0f mi (%.0f km) of %s", geo.Miles(area.RadiusKm), area.RadiusKm, area.Near)
+		}
+		if days := providers.DatePostedDays(app.area.PostedWithin); days > 0 {
+			area.PostedSince = start.AddDate(0, 0, -days)
+			ctx = scraper.WithDatePosted(ctx, app.area.PostedWithin)
+			app.logger.Infof("Keeping jobs posted in the last %d days", days)
+		}
+	}
+	if app.sinceLastRun {
+		known, err := app.knownJobs(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ctx = scraper.WithKnownJobs(ctx, known)
+	}
+
+	// The run ID ties the scraper's log lines to the report kept in the run
+	// history, which run notifiers also hear
+	runID := scraper.RunID(ctx)
+	ctx = scraper.WithRunID(ctx, runID)
 
-	// Scrape jobs using goroutines
-	jobs, err := app.scraper.ScrapeAllBoards(query.Keywords, location)
+	// Pre-scrape hooks may change the search or call the run off
+	search := hooks.Scrape{RunID: runID, Keywords: query.Keywords, Location: location}
+	if err := app.hooks.PreScrape(ctx, &search); err != nil {
+		return 0, err
+	}
+	query.Keywords, location = search.Keywords, search.Location
+
+	report := models.RunReport{
+		ID:         runID,
+		Keywords:   query.Keywords,
+		Location:   location,
+		ConfigHash: app.scraper.ConfigHash(),
+		Schedule:   app.schedule,
+		StartedAt:  start,
+	}
+	var sources sourceTracker
+	var newJobs []models.Job
+	track := func(update models.SourceProgress) {
+		sources.update(update)
+		if checkpoint != nil {
+			if err := checkpoint.sourceFinished(update); err != nil {
+				app.logger.Warnf("Failed to save scrape checkpoint: %v", err)
+			}
+		}
+		if progress != nil {
+			progress(update)
+		}
+	}
+	defer func() {
+		report.FinishedAt = time.Now()
+		report.Sources = sources.list()
+		if app.onRun != nil {
+			app.onRun(report)
+		}
+		if err := app.runs.Add(report); err != nil {
+			app.logger.Warnf("Failed to record run %s: %v", report.ID, err)
+		}
+		app.notifyRun(report)
+		app.postRunHooks(report, newJobs)
+		if err := app.scraper.SaveProxyUsage(); err != nil {
+			app.logger.Warnf("Failed to save proxy usage: %v", err)
+		}
+		if err := app.scraper.SaveRequestBudgets(); err != nil {
+			app.logger.Warnf("Failed to save request budgets: %v", err)
+		}
+		if err := app.scraper.SaveAPIUsage(); err != nil {
+			app.logger.Warnf("Failed to save API usage: %v", err)
+		}
+	}()
+
+	// Stream jobs from the scrapers through scoring and deduplication into
+	// storage, so they persist as each source finishes
+	scrape := func(out chan<- []models.Job) error {
+		if len(boards) == 0 {
+			var skip []string
+			if checkpoint != nil {
+				skip = checkpoint.Completed
+			}
+			return app.scraper.StreamAllBoards(ctx, query.Keywords, location, skip, track, out)
+		}
+		return app.scraper.StreamBoards(ctx, boards, query.Keywords, location, track, out)
+	}
+	// Job events are published a stored batch at a time; the new jobs are
+	// announced once the run is done, in one notification
+	stored := func(batch, updated []models.Job) {
+		app.publish(events.JobCreated, runID, batch)
+		app.publish(events.JobUpdated, runID, app.storedCopies(updated))
+		newJobs = append(newJobs, batch...)
+	}
+	result, err := app.runPipeline(query.Keywords, query.Expression, area, scrape, checkpoint, stored)
+	report.JobsFound = result.scraped
+	report.NewJobs = result.inserted
+	report.Duplicates = result.scraped - result.inserted
 	if err != nil {
-		return fmt.Errorf("scraping failed: %w", err)
+		report.Error = err.Error()
+		if result.inserted == 0 {
+			return 0, err
+		}
+	}
+
+	app.logger.Infof("Scraped %d jobs in %v", result.scraped, time.Since(start))
+	app.logger.Infof("Successfully stored %d jobs: %d new, %d duplicates merged into stored copies", result.scraped, result.inserted, result.scraped-result.inserted)
+	app.logAIUsage()
+	if len(newJobs) > 0 {
+		if app.onNewJobs != nil {
+			app.onNewJobs(newJobs)
+		}
+		app.notify(newJobs)
+		app.alert(runID, newJobs)
+	}
+	app.sendDueDigest()
+	return result.scraped, err
+}
+
+// logAIUsage logs the LLM requests and tokens of each task so far
+func (app *Application) logAIUsage() {
+	usage := ai.UsageByTask()
+	for _, task := range ai.Tasks() {
+		u := usage[task]
+		app.logger.Debugf("LLM usage for %s: %d requests (%d failed, %d cached), %d prompt and %d completion tokens",
+			task, u.Requests, u.Failures, u.CachedRequests, u.PromptTokens, u.CompletionTokens)
+	}
+}
+
+// processNewJobs runs the LLM passes over newly stored jobs: salaries,
+// classification, enrichment and summaries
+func (app *Application) processNewJobs(jobs []models.Job) {
+	app.extractSalaries(jobs)
+	app.classify(jobs)
+	app.enrichDetails(jobs)
+	app.summarize(jobs)
+}
+
+// extractSalaries asks the LLM for the salaries of newly stored jobs whose
+// salary could not be parsed, and stores them
+func (app *Application) extractSalaries(jobs []models.Job) {
+	if app.salaries == nil || len(jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if count := app.salaries.ExtractAll(ctx, jobs); count > 0 {
+		if _, err := app.storage.Upsert(context.Background(), jobs); err != nil {
+			app.logger.Errorf("Failed to store extracted salaries: %v", err)
+			return
+		}
+		app.logger.Infof("Extracted salaries for %d new jobs", count)
+	}
+}
+
+// classify replaces the rule-based classification of newly stored jobs with
+// the LLM's and stores them
+func (app *Application) classify(jobs []models.Job) {
+	if app.classifier == nil || len(jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if count := app.classifier.ClassifyAll(ctx, jobs, 0); count > 0 {
+		if _, err := app.storage.Upsert(context.Background(), jobs); err != nil {
+			app.logger.Errorf("Failed to store job classifications: %v", err)
+			return
+		}
+		app.logger.Infof("Classified %d new jobs", count)
 	}
+}
 
-	app.logger.Infof("Scraped %d jobs in %v", len(jobs), time.Since(start))
+// enrichDetails has the LLM read the details of newly stored jobs and
+// stores them
+func (app *Application) enrichDetails(jobs []models.Job) {
+	if app.enricher == nil || len(jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if count := app.enricher.EnrichAll(ctx, jobs, 0); count > 0 {
+		if _, err := app.storage.Upsert(context.Background(), jobs); err != nil {
+			app.logger.Errorf("Failed to store job enrichments: %v", err)
+			return
+		}
+		app.logger.Infof("Enriched %d new jobs", count)
+	}
+}
+
+// summarize adds LLM summaries to newly stored jobs and stores them, so
+// notifications and exports can show them
+func (app *Application) summarize(jobs []models.Job) {
+	if app.summarizer == nil || len(jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if count := app.summarizer.SummarizeAll(ctx, jobs, 0); count > 0 {
+		if _, err := app.storage.Upsert(context.Background(), jobs); err != nil {
+			app.logger.Errorf("Failed to store job summaries: %v", err)
+			return
+		}
+		app.logger.Infof("Summarized %d new jobs", count)
+	}
+}
 
-	// Calculate relevance scores
+// publish sends events of eventType for jobs to every configured broker
+func (app *Application) publish(eventType, runID string, jobs []models.Job) {
+	if len(app.publishers) == 0 || len(jobs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	jobEvents := events.NewEvents(eventType, runID, jobs)
+	for _, publisher := range app.publishers {
+		if err := publisher.Publish(ctx, eventType, jobEvents); err != nil {
+			app.logger.Errorf("Failed to publish %d %s events to %s: %v", len(jobEvents), eventType, publisher.Name(), err)
+			continue
+		}
+		app.logger.Infof("Published %d %s events to %s", len(jobEvents), eventType, publisher.Name())
+	}
+}
+
+// storedCopies returns the stored copies of jobs a scrape found again, which
+// keep the first-seen ID, link and scrape time and any LLM enrichment. A
+// job merged by title and company under another ID is looked up as scraped.
+func (app *Application) storedCopies(jobs []models.Job) []models.Job {
+	stored := make([]models.Job, 0, len(jobs))
 	for i := range jobs {
-		jobs[i].CalculateRelevance(query.Keywords)
+		job, err := app.storage.GetByID(context.Background(), jobs[i].ID)
+		if err != nil {
+			app.logger.Debugf("Publishing job %s as scraped: %v", jobs[i].ID, err)
+			stored = append(stored, jobs[i])
+			continue
+		}
+		stored = append(stored, *job)
 	}
+	return stored
+}
+
+// notifyRun hands the run report to notifiers that report on runs
+func (app *Application) notifyRun(report models.RunReport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-	// Store jobs
-	if err := app.storage.Store(jobs); err != nil {
-		return fmt.Errorf("failed to store jobs: %w", err)
+	for _, notifier := range app.notifiers {
+		if runNotifier, ok := notifier.(notify.RunNotifier); ok {
+			if err := runNotifier.NotifyRun(ctx, report); err != nil {
+				app.logger.Errorf("%s run notifications failed: %v", notifier.Name(), err)
+			}
+		}
 	}
+}
 
-	app.logger.Infof("Successfully stored %d jobs", len(jobs))
-	return nil
+// postRunHooks runs the post-run hooks with the run's report and new jobs
+func (app *Application) postRunHooks(report models.RunReport, newJobs []models.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	app.hooks.PostRun(ctx, report, newJobs)
+}
+
+// sourceTracker keeps the latest progress of each source in the order the
+// sources were first reported
+type sourceTracker struct {
+	mutex   sync.Mutex
+	sources []models.SourceProgress
 }
 
-func (app *Application) DisplayResults() error {
+func (t *sourceTracker) update(update models.SourceProgress) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i := range t.sources {
+		if t.sources[i].Source == update.Source {
+			t.sources[i] = update
+			return
+		}
+	}
+	t.sources = append(t.sources, update)
+}
+
+func (t *sourceTracker) list() []models.SourceProgress {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]models.SourceProgress(nil), t.sources...)
+}
+
+// addNotifier adds n, replacing a configured notifier of the same kind
+func (app *Application) addNotifier(n notify.Notifier) {
+	for i, existing := range app.notifiers {
+		if existing.Name() == n.Name() {
+			app.notifiers[i] = n
+			return
+		}
+	}
+	app.notifiers = append(app.notifiers, n)
+}
+
+// notify hands newly stored jobs to each configured notifier, skipping jobs
+// a channel was already sent, and records the deliveries. Failures are
+// logged rather than failing the run, since the jobs are already stored.
+func (app *Application) notify(jobs []models.Job) {
+	if len(app.notifiers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	for _, notifier := range app.notifiers {
+		channel := notifier.Name()
+		var pending []models.Job
+		for _, job := range jobs {
+			if !job.Notified(channel) {
+				pending = append(pending, job)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err := notifier.Notify(ctx, pending); err != nil {
+			app.logger.Errorf("%s notifications failed: %v", channel, err)
+			continue
+		}
+		ids := make([]string, len(pending))
+		for i := range pending {
+			ids[i] = pending[i].ID
+		}
+		if err := app.storage.MarkNotified(context.Background(), ids, channel, time.Now()); err != nil {
+			app.logger.Errorf("Failed to record %s notifications: %v", channel, err)
+		}
+	}
+}
+
+func (app *Application) DisplayResults(ctx context.Context) error {
 	// Get recent jobs
 	filter := models.JobFilter{
 		DateFrom: time.Now().Add(-24 * time.Hour),
@@ -205,17 +931,23 @@ func (app *Application) DisplayResults() error {
 		Offset:   0,
 	}
 
-	result, err := app.storage.Search(filter)
+	result, err := app.storage.Search(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("failed to search jobs: %w", err)
 	}
 
 	// Display summary
-	stats, err := app.storage.GetStats()
+	stats, err := app.storage.GetStats(ctx)
 	if err != nil {
 		app.logger.Warnf("Failed to get stats: %v", err)
-	} else {
-		app.displayStats(stats)
+	}
+
+	if app.output != export.FormatTable {
+		return app.writeResults(stats, result.Jobs)
+	}
+
+	if stats != nil {
+		displayStats(stats)
 	}
 
 	// Display recent jobs
@@ -224,13 +956,30 @@ func (app *Application) DisplayResults() error {
 	return nil
 }
 
-func (app *Application) displayStats(stats *models.JobStats) {
+// writeResults prints the run summary in a machine-readable format. CSV has no
+// room for nested stats, so it carries the job rows only.
+func (app *Application) writeResults(stats *models.JobStats, jobs []models.Job) error {
+	if jobs == nil {
+		jobs = []models.Job{}
+	}
+
+	if app.output == export.FormatCSV {
+		return export.Write(os.Stdout, app.output, jobs)
+	}
+
+	return export.Write(os.Stdout, app.output, struct {
+		Stats *models.JobStats `json:"stats"`
+		Jobs  []models.Job     `json:"jobs"`
+	}{Stats: stats, Jobs: jobs})
+}
+
+func displayStats(stats *models.JobStats) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("JOB SCRAPING SUMMARY")
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Printf("Total Jobs Found: %d\n", stats.TotalJobs)
 	fmt.Printf("Recent Jobs (24h): %d\n", stats.RecentJobs)
-	fmt.Printf("Last Scraped: %s\n", stats.LastScraped.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Last Scraped: %s\n", models.InDisplayZone(stats.LastScraped).Format("2006-01-02 15:04:05"))
 
 	fmt.Println("\nJobs by Source:")
 	for source, count := range stats.JobsBySource {
@@ -247,6 +996,16 @@ func (app *Application) displayStats(stats *models.JobStats) {
 		count++
 	}
 
+	fmt.Println("\nTop Companies:")
+	count = 0
+	for company, jobCount := range stats.JobsByCompany {
+		if count >= 5 {
+			break
+		}
+		fmt.Printf("  %-20s: %d\n", company, jobCount)
+		count++
+	}
+
 	fmt.Println("\nTop Keywords:")
 	count = 0
 	for keyword, keywordCount := range stats.Keywords {
@@ -276,19 +1035,53 @@ func (app *Application) displayJobs(jobs []models.Job) {
 		fmt.Printf("\n%d. %s\n", i+1, job.Title)
 		fmt.Printf("   Company: %s\n", job.Company)
 		fmt.Printf("   Location: %s\n", job.Location)
+		if types := workTypes(job); types != "" {
+			fmt.Printf("   Type: %s\n", types)
+		}
 		if job.Salary != "" {
 			fmt.Printf("   Salary: %s\n", job.Salary)
+		} else if job.SalaryRange != nil {
+			fmt.Printf("   Salary: %s (from description)\n", job.SalaryRange)
 		}
 		fmt.Printf("   Source: %s\n", job.Source)
+		if c := job.Classification; c != nil {
+			fmt.Printf("   Role: %s, %s, %s industry\n", c.Category, c.Seniority, c.Industry)
+		}
 		fmt.Printf("   Relevance: %.2f\n", job.Relevance)
+		if job.Match != nil {
+			fmt.Printf("   Resume match: %.0f%%\n", job.Match.Score*100)
+			for _, gap := range job.Match.Gaps {
+				fmt.Printf("     - %s\n", gap)
+			}
+		}
 		fmt.Printf("   Link: %s\n", job.Link)
-		fmt.Printf("   Scraped: %s\n", job.ScrapedAt.Format("2006-01-02 15:04"))
+		if job.PostedAt != nil {
+			fmt.Printf("   Posted: %s\n", models.InDisplayZone(*job.PostedAt).Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("   Scraped: %s\n", models.InDisplayZone(job.ScrapedAt).Format("2006-01-02 15:04"))
 
 		if len(job.Keywords) > 0 {
 			fmt.Printf("   Keywords: %s\n", strings.Join(job.Keywords, ", "))
 		}
+		if e := job.Enrichment; e != nil {
+			if len(e.RequiredSkills) > 0 {
+				fmt.Printf("   Required skills: %s\n", strings.Join(e.RequiredSkills, ", "))
+			}
+			if len(e.TechStack) > 0 {
+				fmt.Printf("   Tech stack: %s\n", strings.Join(e.TechStack, ", "))
+			}
+			if e.YearsExperience > 0 {
+				fmt.Printf("   Experience: %d+ years\n", e.YearsExperience)
+			}
+			fmt.Printf("   Visa sponsorship: %s\n", e.VisaSponsorship)
+		}
 
-		if job.Description != "" && len(job.Description) > 100 {
+		if job.Summary != nil {
+			fmt.Println("   Summary:")
+			for _, bullet := range job.Summary.Bullets() {
+				fmt.Printf("     - %s\n", bullet)
+			}
+		} else if job.Description != "" && len(job.Description) > 100 {
 			fmt.Printf("   Description: %s...\n", job.Description[:100])
 		} else if job.Description != "" {
 			fmt.Printf("   Description: %s\n", job.Description)
@@ -300,13 +1093,36 @@ func (app *Application) displayJobs(jobs []models.Job) {
 	}
 }
 
-func (app *Application) ExportExistingData(format, filename string) error {
+// workTypes joins a job's employment and remote types, those known
+func workTypes(job models.Job) string {
+	var types []string
+	for _, t := range []string{job.EmploymentType, job.RemoteType} {
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return strings.Join(types, ", ")
+}
+
+func (app *Application) ExportExistingData(ctx context.Context, format, filename string) error {
 	// Get all jobs from storage
-	jobs, err := app.storage.GetAll()
+	jobs, err := app.storage.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get jobs for export: %w", err)
 	}
 
+	// Postings found taken down are left out
+	current := jobs[:0]
+	for _, job := range jobs {
+		if job.ExpiredAt == nil {
+			current = append(current, job)
+		}
+	}
+	if expired := len(jobs) - len(current); expired > 0 {
+		app.logger.Infof("Leaving %d expired jobs out of the export", expired)
+	}
+	jobs = current
+
 	if len(jobs) == 0 {
 		app.logger.Warn("No jobs found to export")
 		return fmt.Errorf("no jobs found to export")
@@ -315,18 +1131,18 @@ func (app *Application) ExportExistingData(format, filename string) error {
 	switch strings.ToLower(format) {
 	case "csv":
 		// Get stats for comprehensive export
-		stats, err := app.storage.GetStats()
+		stats, err := app.storage.GetStats(ctx)
 		if err != nil {
 			app.logger.Warnf("Failed to get stats for export: %v", err)
 			// Export without stats
-			filePath, err := app.csvExporter.ExportJobs(jobs, filename)
+			filePath, err := app.csvExporter.ExportJobs(ctx, jobs, filename)
 			if err != nil {
 				return fmt.Errorf("CSV export failed: %w", err)
 			}
 			app.logger.Infof("Exported %d jobs to CSV: %s", len(jobs), filePath)
 		} else {
 			// Export with stats
-			filePath, err := app.csvExporter.ExportJobsWithStats(jobs, stats, filename)
+			filePath, err := app.csvExporter.ExportJobsWithStats(ctx, jobs, stats, filename)
 			if err != nil {
 				return fmt.Errorf("CSV export with stats failed: %w", err)
 			}
@@ -390,14 +1206,26 @@ func (app *Application) GetAPIStats() map[string]*api.APIStats {
 }
 
 // ValidateAPICredentials validates all configured API providers
-func (app *Application) ValidateAPICredentials() map[string]error {
-	return app.scraper.ValidateAPICredentials()
+func (app *Application) ValidateAPICredentials(ctx context.Context) []api.ProviderValidation {
+	return app.scraper.ValidateAPICredentials(ctx)
 }
 
 // ShowAPIStats displays API provider statistics
 func (app *Application) ShowAPIStats() {
 	stats := app.GetAPIStats()
 
+	if app.output != export.FormatTable {
+		list := make([]*api.APIStats, 0, len(stats))
+		for _, stat := range stats {
+			list = append(list, stat)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Provider < list[j].Provider })
+		if err := export.Write(os.Stdout, app.output, list); err != nil {
+			app.logger.Errorf("Failed to write API stats: %v", err)
+		}
+		return
+	}
+
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("API PROVIDER STATISTICS")
 	fmt.Println(strings.Repeat("=", 60))
@@ -420,18 +1248,31 @@ func (app *Application) ShowAPIStats() {
 		fmt.Printf("  Total Jobs Found: %d\n", stat.TotalJobs)
 		fmt.Printf("  Average Latency: %v\n", stat.AverageLatency)
 		if !stat.LastUsed.IsZero() {
-			fmt.Printf("  Last Used: %s\n", stat.LastUsed.Format("2006-01-02 15:04:05"))
+			fmt.Printf("  Last Used: %s\n", models.InDisplayZone(stat.LastUsed).Format("2006-01-02 15:04:05"))
+		}
+		fmt.Printf("  Circuit: %s", stat.CircuitState)
+		if stat.CircuitOpenUntil != nil {
+			fmt.Printf(" until %s", models.InDisplayZone(*stat.CircuitOpenUntil).Format("2006-01-02 15:04:05"))
+		}
+		if stat.ConsecutiveFailures > 0 {
+			fmt.Printf(" (%d failures in a row)", stat.ConsecutiveFailures)
 		}
+		fmt.Println()
 	}
 }
 
 // ValidateAndShowAPICredentials validates and displays API credential status
-func (app *Application) ValidateAndShowAPICredentials() {
+func (app *Application) ValidateAndShowAPICredentials(ctx context.Context) {
+	if app.output != export.FormatTable {
+		app.writeCredentialResults(app.ValidateAPICredentials(ctx))
+		return
+	}
+
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("API CREDENTIALS VALIDATION")
 	fmt.Println(strings.Repeat("=", 60))
 
-	results := app.ValidateAPICredentials()
+	results := app.ValidateAPICredentials(ctx)
 
 	if len(results) == 0 {
 		fmt.Println("No API providers configured.")
@@ -440,14 +1281,14 @@ func (app *Application) ValidateAndShowAPICredentials() {
 
 	var validCount, invalidCount int
 
-	for provider, err := range results {
-		fmt.Printf("\nProvider: %s\n", strings.ToUpper(provider))
-		if err == nil {
-			fmt.Printf("  Status: ✅ VALID\n")
+	for _, result := range results {
+		fmt.Printf("\nProvider: %s\n", strings.ToUpper(result.Provider))
+		if result.Status == api.ValidationValid {
+			fmt.Printf("  Status: ✅ VALID (%v)\n", result.Latency.Round(time.Millisecond))
 			validCount++
 		} else {
-			fmt.Printf("  Status: ❌ INVALID\n")
-			fmt.Printf("  Error: %v\n", err)
+			fmt.Printf("  Status: ❌ %s\n", strings.ToUpper(strings.ReplaceAll(string(result.Status), "_", " ")))
+			fmt.Printf("  Error: %v\n", result.Err)
 			invalidCount++
 		}
 	}
@@ -462,7 +1303,50 @@ func (app *Application) ValidateAndShowAPICredentials() {
 	}
 }
 
+// writeCredentialResults prints validation results as
+// provider/valid/status/latency/error rows
+func (app *Application) writeCredentialResults(results []api.ProviderValidation) {
+	type credentialStatus struct {
+		Provider string        `json:"provider"`
+		Valid    bool          `json:"valid"`
+		Status   string        `json:"status"`
+		Latency  time.Duration `json:"latency"`
+		Error    string        `json:"error,omitempty"`
+	}
+
+	statuses := make([]credentialStatus, 0, len(results))
+	for _, result := range results {
+		status := credentialStatus{
+			Provider: result.Provider,
+			Valid:    result.Status == api.ValidationValid,
+			Status:   string(result.Status),
+			Latency:  result.Latency,
+		}
+		if result.Err != nil {
+			status.Error = result.Err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := export.Write(os.Stdout, app.output, statuses); err != nil {
+		app.logger.Errorf("Failed to write validation results: %v", err)
+	}
+}
+
 func (app *Application) Close() {
+	// Posting fetches by the server and link checks spend budget outside
+	// any run, and API usage may grow after the last run's save
+	if err := app.scraper.SaveRequestBudgets(); err != nil {
+		app.logger.Warnf("Failed to save request budgets: %v", err)
+	}
+	if err := app.scraper.SaveAPIUsage(); err != nil {
+		app.logger.Warnf("Failed to save API usage: %v", err)
+	}
+	if app.geocoder != nil {
+		if err := app.geocoder.Save(); err != nil {
+			app.logger.Warnf("Failed to save geocoding cache: %v", err)
+		}
+	}
 	if app.storage != nil {
 		app.storage.Close()
 	}
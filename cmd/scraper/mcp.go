@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/mcp"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+// mcpServerVersion is reported to MCP clients
+const mcpServerVersion = "1.0.0"
+
+// maxToolResults bounds search_jobs pages so replies fit an agent's context
+const maxToolResults = 100
+
+// runMCP serves the scraper as MCP tools over stdin and stdout, for agents
+// that launch it as a local tool server. Logs go to stderr.
+func runMCP(args []string) error {
+	fs := flag.NewFlagSet("mcp", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "User whose application statuses tag_job sets (default: the shared profile)")
+	fs.Parse(args)
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	applications, err := storage.NewApplicationStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	tools := &mcpTools{app: app, applications: applications, user: *userFlag}
+	server := mcp.NewServer("hire.ai", mcpServerVersion, app.logger)
+	tools.register(server)
+
+	ctx, stop := commandContext()
+	defer stop()
+	app.logger.Info("Serving MCP tools on stdio")
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// mcpTools implements the tools over the application's storage and scraper
+type mcpTools struct {
+	app          *Application
+	applications *storage.ApplicationStore
+	user         string
+}
+
+func (t *mcpTools) register(server *mcp.Server) {
+	stringList := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+
+	server.AddTool(mcp.Tool{
+		Name:        "search_jobs",
+		Description: "Search the stored jobs. Returns matching jobs without descriptions (use get_job for those) and the total count.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"query":              map[string]interface{}{"type": "string", "description": `Full-text query ranking the results: every word must match, "quoted phrases" word for word, a trailing * matches by prefix and a leading - excludes, e.g. "site reliability" kube* -manager`},
+			"semantic":           map[string]interface{}{"type": "string", "description": "Natural-language description of the jobs wanted, e.g. remote backend work at a climate startup; ranks the results by meaning when semantic search is set up, and by its keywords otherwise"},
+			"keywords":           describe(stringList, "Jobs must mention at least one of these in the title or description"),
+			"keyword_query":      map[string]interface{}{"type": "string", "description": "Boolean query jobs must satisfy, with AND, OR, NOT and parentheses, e.g. golang AND (kubernetes OR terraform) NOT intern"},
+			"location":           map[string]interface{}{"type": "string", "description": "Substring of the job location, e.g. Berlin or Remote"},
+			"near":               map[string]interface{}{"type": "string", "description": "Only jobs located near this place, e.g. Austin, TX"},
+			"radius":             map[string]interface{}{"type": "string", "description": "Distance from near, e.g. 50mi or 80km (default 25mi)"},
+			"sources":            describe(stringList, "Only jobs from these job boards or APIs"),
+			"categories":         describe(stringList, "Role categories, e.g. backend, data, sre"),
+			"seniorities":        describe(stringList, "intern, junior, mid, senior, lead or director"),
+			"industries":         describe(stringList, "Industries, e.g. fintech, healthcare"),
+			"employment_types":   describe(stringList, "full-time, part-time, contract or intern"),
+			"remote_types":       describe(stringList, "remote, hybrid or onsite"),
+			"min_salary":         map[string]interface{}{"type": "integer", "description": "Minimum yearly salary"},
+			"salary_currency":    map[string]interface{}{"type": "string", "description": "ISO currency of min_salary, e.g. USD; salaries in other currencies are converted. Without it figures are compared as stated."},
+			"posted_within_days": map[string]interface{}{"type": "integer", "description": "Only jobs posted in this many days, counting jobs without a posted date from when they were scraped"},
+			"active_only":        map[string]interface{}{"type": "boolean", "description": "Only jobs still listed"},
+			"sort_by":            map[string]interface{}{"type": "string", "enum": []string{"relevance", "match", "date", "posted", "title", "company"}},
+			"limit":              map[string]interface{}{"type": "integer", "description": "Jobs to return, at most 100 (default 20)"},
+			"offset":             map[string]interface{}{"type": "integer"},
+		}),
+		Handler: t.searchJobs,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_job",
+		Description: "Get one stored job by ID, with its full description and your application status.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"id": map[string]interface{}{"type": "string"},
+		}, "id"),
+		Handler: t.getJob,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "list_boards",
+		Description: "List the configured job boards that scrape_board can scrape.",
+		Handler:     t.listBoards,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "scrape_board",
+		Description: "Scrape job boards for keywords and store the results. Without boards, every enabled source is scraped. This can take minutes.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"keywords": describe(stringList, "Search keywords"),
+			"location": map[string]interface{}{"type": "string", "description": "Search location (default Remote)"},
+			"boards":   describe(stringList, "Names of the boards to scrape, from list_boards"),
+		}, "keywords"),
+		Handler: t.scrapeBoard,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_stats",
+		Description: "Summary statistics of the stored jobs: totals by source, location, category, seniority and industry.",
+		Handler:     t.getStats,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "tag_job",
+		Description: "Set your application status for a job: saved, applied, interviewing, offer, rejected, withdrawn or archived (not a fit).",
+		InputSchema: objectSchema(map[string]interface{}{
+			"id":     map[string]interface{}{"type": "string"},
+			"status": map[string]interface{}{"type": "string", "enum": models.ApplicationStatuses},
+			"notes":  map[string]interface{}{"type": "string"},
+		}, "id", "status"),
+		Handler: t.tagJob,
+	})
+}
+
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func describe(schema map[string]interface{}, description string) map[string]interface{} {
+	described := make(map[string]interface{}, len(schema)+1)
+	for key, value := range schema {
+		described[key] = value
+	}
+	described["description"] = description
+	return described
+}
+
+func decodeArguments(arguments json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(arguments, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// toolJob is a job as search_jobs lists it
+type toolJob struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Company   string     `json:"company"`
+	Location  string     `json:"location"`
+	Salary    string     `json:"salary,omitempty"`
+	Link      string     `json:"link"`
+	Source    string     `json:"source"`
+	ScrapedAt time.Time  `json:"scraped_at"`
+	PostedAt  *time.Time `json:"posted_at,omitempty"`
+	Relevance float64    `json:"relevance"`
+	Category  string     `json:"category,omitempty"`
+	Seniority string     `json:"seniority,omitempty"`
+	// EmploymentType and RemoteType are empty when unknown
+	EmploymentType string `json:"employment_type,omitempty"`
+	RemoteType     string `json:"remote_type,omitempty"`
+}
+
+func (t *mcpTools) searchJobs(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Query            string   `json:"query"`
+		Semantic         string   `json:"semantic"`
+		Keywords         []string `json:"keywords"`
+		KeywordQuery     string   `json:"keyword_query"`
+		Location         string   `json:"location"`
+		Near             string   `json:"near"`
+		Radius           string   `json:"radius"`
+		Sources          []string `json:"sources"`
+		Categories       []string `json:"categories"`
+		Seniorities      []string `json:"seniorities"`
+		Industries       []string `json:"industries"`
+		EmploymentTypes  []string `json:"employment_types"`
+		RemoteTypes      []string `json:"remote_types"`
+		MinSalary        int      `json:"min_salary"`
+		SalaryCurrency   string   `json:"salary_currency"`
+		PostedWithinDays int      `json:"posted_within_days"`
+		ActiveOnly       bool     `json:"active_only"`
+		SortBy           string   `json:"sort_by"`
+		Limit            int      `json:"limit"`
+		Offset           int      `json:"offset"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+	currency := strings.ToUpper(strings.TrimSpace(args.SalaryCurrency))
+	if currency != "" && !models.KnownCurrency(currency) {
+		return nil, fmt.Errorf("unknown salary_currency %s", currency)
+	}
+
+	filter := models.JobFilter{
+		Query:           args.Query,
+		Semantic:        args.Semantic,
+		Keywords:        args.Keywords,
+		KeywordQuery:    args.KeywordQuery,
+		Location:        args.Location,
+		Sources:         args.Sources,
+		MinSalary:       args.MinSalary,
+		SalaryCurrency:  currency,
+		SortBy:          args.SortBy,
+		Limit:           min(args.Limit, maxToolResults),
+		Offset:          max(args.Offset, 0),
+		Categories:      args.Categories,
+		Seniorities:     args.Seniorities,
+		Industries:      args.Industries,
+		EmploymentTypes: args.EmploymentTypes,
+		RemoteTypes:     args.RemoteTypes,
+	}
+	if err := filter.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid keyword_query: %w", err)
+	}
+	if args.PostedWithinDays > 0 {
+		filter.PostedSince = time.Now().AddDate(0, 0, -args.PostedWithinDays)
+	}
+	if args.ActiveOnly {
+		active := true
+		filter.IsActive = &active
+	}
+	if args.Radius != "" {
+		radius, err := geo.ParseRadius(args.Radius)
+		if err != nil {
+			return nil, err
+		}
+		filter.RadiusKm = radius
+	}
+	filter.Near = args.Near
+	if err := t.app.geocoder.ResolveFilter(ctx, &filter); err != nil {
+		return nil, err
+	}
+
+	result, err := t.app.storage.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]toolJob, len(result.Jobs))
+	for i, job := range result.Jobs {
+		jobs[i] = toolJob{
+			ID:             job.ID,
+			Title:          job.Title,
+			Company:        job.Company,
+			Location:       job.Location,
+			Salary:         job.Salary,
+			Link:           job.Link,
+			Source:         job.Source,
+			ScrapedAt:      job.ScrapedAt,
+			PostedAt:       job.PostedAt,
+			EmploymentType: job.EmploymentType,
+			RemoteType:     job.RemoteType,
+			Relevance:      job.Relevance,
+		}
+		if c := job.Classification; c != nil {
+			jobs[i].Category, jobs[i].Seniority = c.Category, c.Seniority
+		}
+	}
+	return map[string]interface{}{"total": result.Total, "jobs": jobs}, nil
+}
+
+func (t *mcpTools) getJob(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	job, err := t.app.storage.GetByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{"job": job}
+	if application, err := t.applications.Get(t.user, job.ID); err == nil {
+		result["application"] = application
+	}
+	return result, nil
+}
+
+func (t *mcpTools) listBoards(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	type board struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+		Method  string `json:"method,omitempty"`
+	}
+	var boards []board
+	for _, b := range t.app.scraper.GetConfig().JobBoards {
+		boards = append(boards, board{Name: b.Name, Enabled: b.Enabled, Method: b.ScrapingMethod})
+	}
+	return map[string]interface{}{"boards": boards}, nil
+}
+
+func (t *mcpTools) scrapeBoard(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Keywords []string `json:"keywords"`
+		Location string   `json:"location"`
+		Boards   []string `json:"boards"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	var keywords []string
+	for _, keyword := range args.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	if len(keywords) == 0 {
+		return nil, fmt.Errorf("keywords are required")
+	}
+	if args.Location == "" {
+		args.Location = "Remote"
+	}
+
+	// Sources report from their own goroutines
+	var mutex sync.Mutex
+	var sources []models.SourceProgress
+	progress := func(update models.SourceProgress) {
+		if update.Status == models.SourceCompleted || update.Status == models.SourceFailed || update.Status == models.SourceDeferred {
+			mutex.Lock()
+			sources = append(sources, update)
+			mutex.Unlock()
+		}
+	}
+	var scraped int
+	var err error
+	if len(args.Boards) == 0 {
+		scraped, err = t.app.ScrapeJobs(ctx, keywords, args.Location, progress)
+	} else {
+		scraped, err = t.app.ScrapeBoards(ctx, args.Boards, keywords, args.Location, progress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"jobs_scraped": scraped, "sources": sources}, nil
+}
+
+func (t *mcpTools) getStats(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	return t.app.storage.GetStats(ctx)
+}
+
+func (t *mcpTools) tagJob(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Notes  string `json:"notes"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if _, err := t.app.storage.GetByID(ctx, args.ID); err != nil {
+		return nil, err
+	}
+
+	application := &models.Application{JobID: args.ID, UserID: t.user, Status: strings.ToLower(args.Status), Notes: args.Notes}
+	if existing, err := t.applications.Get(t.user, args.ID); err == nil && args.Notes == "" {
+		application.Notes = existing.Notes
+	}
+	if err := t.applications.Set(application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
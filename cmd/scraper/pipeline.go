@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/classify"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/relevance"
+	"hire.ai/pkg/salary"
+)
+
+// The scrape pipeline passes batches of jobs from the scrapers through the
+// enrichers and the deduper to the storage writer over bounded channels, so
+// a full stage holds back the ones before it and a run keeps only a few
+// batches in flight however many jobs it finds
+const (
+	pipelineBuffer = 4 // batches waiting between stages
+	enrichWorkers  = 4
+)
+
+// pipelineResult counts what a pipeline run scraped and stored
+type pipelineResult struct {
+	scraped  int
+	inserted int
+}
+
+// storedFunc receives each batch once it is stored and the LLM passes have
+// run over it: the jobs that were new, and the jobs already stored that the
+// batch found again, which are only given when job events are published
+type storedFunc func(newJobs, updated []models.Job)
+
+// storedBatch is a stored batch on its way to the LLM passes
+type storedBatch struct {
+	newJobs, updated []models.Job
+}
+
+// runPipeline streams the jobs scrape sends through scoring, deduplication
+// and storage, telling checkpoint, if not nil, of each stored batch. The new
+// jobs of each batch then go on to the LLM passes, a stage of their own so
+// their slow requests do not hold up the writer, and from there to stored.
+// Jobs not matching expression, when it is not nil, and jobs at companies
+// the allow and deny lists leave out are dropped, and when area is not nil,
+// so are jobs located outside its radius or posted before its PostedSince;
+// job hooks then see the rest and may drop more. A storage failure stops
+// later writes; the batches stored before it have been handed on.
+func (app *Application) runPipeline(keywords []string, expression *models.KeywordExpr, area *models.JobFilter, scrape func(out chan<- []models.Job) error, checkpoint *runCheckpoint, stored storedFunc) (pipelineResult, error) {
+	scraped := make(chan []models.Job, pipelineBuffer)
+	enriched := make(chan []models.Job, pipelineBuffer)
+	deduped := make(chan []models.Job, pipelineBuffer)
+
+	scrapeDone := make(chan error, 1)
+	go func() { scrapeDone <- scrape(scraped) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < enrichWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for jobs := range scraped {
+				app.enrich(jobs, keywords)
+				// Emptied batches still pass on, as the checkpoint counts them
+				if expression != nil {
+					jobs = matchingExpression(jobs, expression)
+				}
+				jobs = allowedCompanies(jobs)
+				if area != nil {
+					jobs = inArea(jobs, area)
+				}
+				jobs = app.hooks.Jobs(context.Background(), jobs)
+				enriched <- jobs
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(enriched)
+	}()
+
+	go app.dedupe(enriched, deduped)
+
+	processed := make(chan storedBatch, pipelineBuffer)
+	processDone := make(chan struct{})
+	go func() {
+		defer close(processDone)
+		for batch := range processed {
+			app.processNewJobs(batch.newJobs)
+			if stored != nil {
+				stored(batch.newJobs, batch.updated)
+			}
+		}
+	}()
+
+	// Storage serializes writes, so a single writer stores the batches.
+	// After a failure the rest are drained so the stages before can finish.
+	var result pipelineResult
+	var storeErr error
+	for jobs := range deduped {
+		result.scraped += len(jobs)
+		if storeErr != nil {
+			continue
+		}
+		newJobs, err := app.storage.Upsert(context.Background(), jobs)
+		if err != nil {
+			storeErr = err
+			continue
+		}
+		result.inserted += len(newJobs)
+		// Jobs are embedded as they are stored so semantic searches need
+		// not wait for them; those left out are embedded by the next search
+		if app.semantic != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			if err := app.semantic.Add(ctx, jobs); err != nil {
+				app.logger.Warnf("Semantic search indexing skipped: %v", err)
+			}
+			cancel()
+		}
+		if checkpoint != nil {
+			if err := checkpoint.batchStored(); err != nil {
+				app.logger.Warnf("Failed to save scrape checkpoint: %v", err)
+			}
+		}
+		var updated []models.Job
+		if len(app.publishers) > 0 {
+			updated = foundAgain(jobs, newJobs)
+		}
+		processed <- storedBatch{newJobs: newJobs, updated: updated}
+	}
+	close(processed)
+	<-processDone
+
+	if err := <-scrapeDone; err != nil {
+		return result, fmt.Errorf("scraping failed: %w", err)
+	}
+	if storeErr != nil {
+		return result, fmt.Errorf("failed to store jobs: %w", storeErr)
+	}
+	return result, nil
+}
+
+// foundAgain returns the jobs of a stored batch that were not new
+func foundAgain(batch, newJobs []models.Job) []models.Job {
+	inserted := make(map[string]bool, len(newJobs))
+	for i := range newJobs {
+		inserted[newJobs[i].ID] = true
+	}
+	var again []models.Job
+	for i := range batch {
+		if !inserted[batch[i].ID] {
+			again = append(again, batch[i])
+		}
+	}
+	return again
+}
+
+// matchingExpression keeps the jobs that satisfy a boolean keyword query
+func matchingExpression(jobs []models.Job, expression *models.KeywordExpr) []models.Job {
+	kept := jobs[:0]
+	for i := range jobs {
+		if expression.Match(&jobs[i]) {
+			kept = append(kept, jobs[i])
+		}
+	}
+	return kept
+}
+
+// allowedCompanies keeps the jobs at companies the allow and deny lists let
+// through
+func allowedCompanies(jobs []models.Job) []models.Job {
+	kept := jobs[:0]
+	for i := range jobs {
+		if models.CompanyAllowed(jobs[i].Company) {
+			kept = append(kept, jobs[i])
+		}
+	}
+	return kept
+}
+
+// inArea keeps the jobs located within area's radius and posted since its
+// PostedSince. Jobs whose source gives no date count as posted when
+// scraped.
+func inArea(jobs []models.Job, area *models.JobFilter) []models.Job {
+	kept := jobs[:0]
+	for i := range jobs {
+		if area.Within(&jobs[i]) && !jobs[i].PostedTime().Before(area.PostedSince) {
+			kept = append(kept, jobs[i])
+		}
+	}
+	return kept
+}
+
+// enrich names companies by their aliases' shown name, geocodes, reads
+// employment and remote types, parses salaries, classifies and scores jobs,
+// boosting the score by resume fit when a resume is given
+func (app *Application) enrich(jobs []models.Job, keywords []string) {
+	for i := range jobs {
+		jobs[i].Company = models.CompanyName(jobs[i].Company)
+		if jobs[i].Coordinates == nil && app.geocoder != nil {
+			jobs[i].Coordinates = app.geocoder.Locate(context.Background(), jobs[i].Location)
+		}
+		jobs[i].DetectWorkTypes()
+		// Boards with locale rules parse their salaries while scraping
+		if jobs[i].SalaryRange == nil {
+			jobs[i].SalaryRange = salary.Parse(jobs[i].Salary)
+		}
+		jobs[i].Classification = classify.Classify(&jobs[i])
+		jobs[i].Relevance = app.scorer.Score(&jobs[i], relevance.Query{Keywords: keywords})
+		if app.profile != nil {
+			jobs[i].Match = app.profile.Match(&jobs[i])
+			jobs[i].Relevance += jobs[i].Match.Score
+		}
+	}
+	app.scorer.Index(jobs)
+}
+
+// dedupe points jobs whose descriptions match a stored job's, or an earlier
+// batch's, at that job, so storage merges reworded cross-board copies.
+// Batches the seen index holds entirely are passed on unchecked, and the
+// stored jobs are only loaded for the first batch with a new job, keeping
+// the recent ones the detector compares with. They are loaded once, as
+// batches still in flight to storage would be missed by reloading; of each
+// batch after, only the new jobs the detector compares are added.
+func (app *Application) dedupe(in <-chan []models.Job, out chan<- []models.Job) {
+	defer close(out)
+
+	enabled := app.duplicates != nil
+	var known []models.Job
+	loaded := false
+	merged := 0
+	for jobs := range in {
+		if enabled && !loaded && app.hasUnseen(jobs) {
+			stored, err := app.storage.GetAll(context.Background())
+			if err != nil {
+				app.logger.Errorf("Failed to load jobs for deduplication: %v", err)
+				enabled = false
+			}
+			known, loaded = app.duplicates.Recent(stored), true
+		}
+		if enabled && loaded {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			count, err := app.duplicates.Resolve(ctx, known, jobs, app.seen)
+			cancel()
+			if err != nil {
+				app.logger.Warnf("Embedding deduplication skipped: %v", err)
+			}
+			merged += count
+			known = append(known, app.duplicates.Recent(app.unseen(jobs))...)
+		}
+		out <- jobs
+	}
+	if merged > 0 {
+		app.logger.Infof("Merged %d near-duplicate jobs", merged)
+	}
+}
+
+// unseen returns the jobs that are certainly not stored
+func (app *Application) unseen(jobs []models.Job) []models.Job {
+	var unseen []models.Job
+	for i := range jobs {
+		if !app.seen.Seen(&jobs[i]) {
+			unseen = append(unseen, jobs[i])
+		}
+	}
+	return unseen
+}
+
+// hasUnseen reports whether any of jobs is certainly not stored
+func (app *Application) hasUnseen(jobs []models.Job) bool {
+	for i := range jobs {
+		if !app.seen.Seen(&jobs[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/prefill"
+	"hire.ai/pkg/storage"
+	"hire.ai/pkg/transport"
+)
+
+// answersFileName is the default answers file in the data directory
+const answersFileName = "answers.json"
+
+// runPrefill reads the application form of a Greenhouse, Lever or Workday
+// posting and answers it from the candidate's saved answers, printing the
+// bundle to paste from or, with -fill, entering it in a browser
+func runPrefill(args []string) error {
+	fs := flag.NewFlagSet("prefill", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	jobFlag := fs.String("job", "", "ID of the stored job to apply to (or give the posting URL as an argument)")
+	answersFlag := fs.String("answers", "", "Answers file (default: answers.json in the data directory)")
+	resumeFlag := fs.String("resume", "", "Resume to upload, overriding the answers file's; its contact details fill gaps in the answers")
+	fillFlag := fs.Bool("fill", false, "Open the form in Chrome and enter the answers; you review and submit it")
+	timeoutFlag := fs.Duration("timeout", 30*time.Second, "Timeout for reading the form")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if (*jobFlag == "") == (fs.NArg() != 1) {
+		return fmt.Errorf("usage: job-scraper prefill (-job <id> | <posting url>) [-answers file] [-resume file] [-fill]")
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	link, jobID := fs.Arg(0), ""
+	if *jobFlag != "" {
+		store, err := storage.NewFileStorage(*common.data)
+		if err != nil {
+			return fmt.Errorf("failed to open storage: %w", err)
+		}
+		job, err := store.GetByID(ctx, *jobFlag)
+		store.Close()
+		if err != nil {
+			return err
+		}
+		link, jobID = job.Link, job.ID
+	}
+
+	answersPath := *answersFlag
+	if answersPath == "" {
+		answersPath = filepath.Join(*common.data, answersFileName)
+	}
+	profile, err := prefill.LoadProfile(answersPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(answersPath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "No answers file at %s; the bundle will only show what the form asks\n", answersPath)
+	}
+	if *resumeFlag != "" {
+		if profile.Resume, err = filepath.Abs(*resumeFlag); err != nil {
+			return err
+		}
+	}
+	resumeText, err := prefill.ResumeText(profile.Resume)
+	if err != nil {
+		return err
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, *timeoutFlag)
+	defer cancel()
+	form, err := prefill.FetchForm(fetchCtx, transport.NewClient(*timeoutFlag), link, defaultUserAgent)
+	if err != nil {
+		return err
+	}
+	bundle := prefill.Build(form, profile, resumeText)
+	bundle.JobID = jobID
+
+	if common.machineReadable() {
+		if err := export.Write(os.Stdout, *common.output, bundle); err != nil {
+			return err
+		}
+	} else {
+		displayBundle(bundle)
+	}
+
+	if !*fillFlag {
+		return nil
+	}
+	return prefill.Fill(ctx, bundle, func(filled int, skipped []string) {
+		fmt.Fprintf(os.Stderr, "\nEntered %d answers at %s\n", filled, bundle.ApplyURL)
+		for _, label := range skipped {
+			fmt.Fprintf(os.Stderr, "  Fill in by hand: %s\n", label)
+		}
+		fmt.Fprintln(os.Stderr, "Review and submit the form in the browser, then close it or press Ctrl-C here.")
+	})
+}
+
+func displayBundle(bundle *prefill.Bundle) {
+	title := bundle.Title
+	if bundle.Company != "" {
+		title += " at " + bundle.Company
+	}
+	fmt.Printf("%s (%s)\n", strings.TrimSpace(title), bundle.ATS)
+	fmt.Printf("Apply: %s\n", bundle.ApplyURL)
+	if bundle.ResumePath != "" {
+		fmt.Printf("Resume: %s\n", bundle.ResumePath)
+	}
+	fmt.Println()
+
+	for _, answer := range bundle.Answers {
+		label := answer.Label
+		if answer.Required {
+			label += " *"
+		}
+		fmt.Printf("%s\n", label)
+		switch {
+		case answer.Value != "":
+			fmt.Printf("  %s  [%s]\n", strings.ReplaceAll(answer.Value, "\n", "\n  "), answer.Source)
+		case len(answer.Options) > 0:
+			fmt.Printf("  (unanswered; choose from: %s)\n", strings.Join(answer.Options, ", "))
+		default:
+			fmt.Println("  (unanswered)")
+		}
+	}
+
+	if len(bundle.Missing) > 0 {
+		fmt.Printf("\n%d required questions have no answer; add them to the answers file's questions by a phrase of their label\n", len(bundle.Missing))
+	}
+	if bundle.Note != "" {
+		fmt.Printf("\nNote: %s\n", bundle.Note)
+	}
+}
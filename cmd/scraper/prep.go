@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+)
+
+// runPrep writes an interview prep pack for one stored job, focused on the
+// skills the resume lacks when one is given
+func runPrep(args []string) error {
+	fs := flag.NewFlagSet("prep", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	jobFlag := fs.String("job", "", "ID of the stored job to prepare for")
+	resumeFlag := fs.String("resume", "", "Resume (PDF, DOCX or text) to find skill gaps against")
+	skillsFlag := fs.String("skills", "", "Skills you have (comma-separated), added to any from -resume")
+	outFlag := fs.String("out", "", "Write the pack to this file (default: interview-prep-<job id>.md in the export path; - for stdout)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *jobFlag == "" {
+		return fmt.Errorf("usage: job-scraper prep -job <id> [-resume file] [flags]")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if app.prep == nil {
+		return fmt.Errorf("interview prep is not enabled in the config")
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	job, err := app.storage.GetByID(ctx, *jobFlag)
+	if err != nil {
+		return err
+	}
+	var match *models.JobMatch
+	if *resumeFlag != "" || *skillsFlag != "" {
+		profile, err := loadProfile(*resumeFlag, *skillsFlag)
+		if err != nil {
+			return err
+		}
+		match = profile.Match(job)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	pack, err := app.prep.Generate(ctx, job, match)
+	if err != nil {
+		return fmt.Errorf("failed to generate interview prep: %w", err)
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, pack)
+	}
+	rendered, err := pack.Markdown()
+	if err != nil {
+		return fmt.Errorf("failed to render interview prep: %w", err)
+	}
+	if *outFlag == "-" {
+		fmt.Print(rendered)
+		return nil
+	}
+
+	path := *outFlag
+	if path == "" {
+		exportPath := app.config.GlobalSettings.ExportPath
+		if exportPath == "" {
+			exportPath = "exports"
+		}
+		if err := os.MkdirAll(exportPath, 0755); err != nil {
+			return fmt.Errorf("failed to create export directory: %w", err)
+		}
+		path = filepath.Join(exportPath, fmt.Sprintf("interview-prep-%s.md", job.ID))
+	}
+	if err := os.WriteFile(path, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write interview prep: %w", err)
+	}
+	fmt.Printf("Wrote %d topics and %d practice questions for %s at %s to %s\n",
+		len(pack.Topics), len(pack.Questions), job.Title, job.Company, path)
+	return nil
+}
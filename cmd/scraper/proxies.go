@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/proxy"
+)
+
+func runProxies(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper proxies <status> [flags]")
+	}
+
+	switch args[0] {
+	case "status":
+		return runProxiesStatus(args[1:])
+	default:
+		return fmt.Errorf("unknown proxies command: %s", args[0])
+	}
+}
+
+// proxyUsageRow is a proxy's usage with its success rate, for output
+type proxyUsageRow struct {
+	proxy.Usage
+	SuccessRate float64 `json:"success_rate"`
+}
+
+// runProxiesStatus reports what each proxy has done across runs, busiest
+// first, so weak paid proxies stand out
+func runProxiesStatus(args []string) error {
+	fs := flag.NewFlagSet("proxies status", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	usage, err := proxy.ReadUsage(filepath.Join(*common.data, proxyUsageFile))
+	if err != nil {
+		return fmt.Errorf("failed to read proxy usage: %w", err)
+	}
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].Requests > usage[j].Requests })
+
+	if common.machineReadable() {
+		rows := make([]proxyUsageRow, len(usage))
+		for i, u := range usage {
+			rows[i] = proxyUsageRow{Usage: u, SuccessRate: u.SuccessRate()}
+		}
+		return export.Write(os.Stdout, *common.output, rows)
+	}
+
+	if len(usage) == 0 {
+		fmt.Println("No proxy usage recorded yet; it is tracked while scraping with proxies enabled.")
+		return nil
+	}
+
+	fmt.Printf("%-36s %9s %8s %7s %9s %10s  %s\n", "PROXY", "REQUESTS", "SUCCESS", "BLOCKS", "FAILURES", "DATA", "LAST USED")
+	for _, u := range usage {
+		fmt.Printf("%-36.36s %9d %7.0f%% %7d %9d %10s  %s\n",
+			u.Proxy, u.Requests, u.SuccessRate()*100, u.Blocks, u.Failures, formatBytes(u.Bytes), models.InDisplayZone(u.LastUsed).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exp])
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+// purgeCriteria selects stored jobs for deletion; a job must match every
+// criterion that is set
+type purgeCriteria struct {
+	olderThan    time.Duration
+	sources      []string
+	status       string
+	minRelevance float64
+	now          time.Time
+}
+
+func (c purgeCriteria) isEmpty() bool {
+	return c.olderThan == 0 && len(c.sources) == 0 && c.status == "" && c.minRelevance == 0
+}
+
+func (c purgeCriteria) matches(job models.Job) bool {
+	if c.olderThan > 0 && !job.ScrapedAt.Before(c.now.Add(-c.olderThan)) {
+		return false
+	}
+
+	if len(c.sources) > 0 {
+		found := false
+		for _, source := range c.sources {
+			if strings.EqualFold(job.Source, source) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	switch c.status {
+	case "active":
+		if !job.IsActive {
+			return false
+		}
+	case "inactive":
+		if job.IsActive {
+			return false
+		}
+	}
+
+	if c.minRelevance > 0 && job.Relevance >= c.minRelevance {
+		return false
+	}
+
+	return true
+}
+
+func runPurge(args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	olderThanFlag := fs.String("older-than", "", "Delete jobs scraped before this age (e.g. 72h, 30d, 2w)")
+	sourceFlag := fs.String("source", "", "Delete jobs from these sources (comma-separated)")
+	statusFlag := fs.String("status", "", "Delete jobs with this status (active, inactive)")
+	relevanceFlag := fs.Float64("min-relevance", 0, "Delete jobs with relevance below this threshold")
+	dryRunFlag := fs.Bool("dry-run", false, "Show what would be deleted without deleting")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	criteria := purgeCriteria{
+		sources:      splitList(*sourceFlag),
+		status:       strings.ToLower(*statusFlag),
+		minRelevance: *relevanceFlag,
+		now:          time.Now(),
+	}
+
+	if *olderThanFlag != "" {
+		age, err := parseAge(*olderThanFlag)
+		if err != nil {
+			return err
+		}
+		criteria.olderThan = age
+	}
+
+	if criteria.status != "" && criteria.status != "active" && criteria.status != "inactive" {
+		return fmt.Errorf("unsupported status: %s (use active or inactive)", *statusFlag)
+	}
+
+	if criteria.isEmpty() {
+		return fmt.Errorf("refusing to purge everything: set at least one of -older-than, -source, -status or -min-relevance")
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := store.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	var selected []models.Job
+	var ids []string
+	for _, job := range jobs {
+		if criteria.matches(job) {
+			selected = append(selected, job)
+			ids = append(ids, job.ID)
+		}
+	}
+
+	removed := 0
+	if !*dryRunFlag {
+		if removed, err = store.Delete(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete jobs: %w", err)
+		}
+	}
+
+	if common.machineReadable() {
+		if selected == nil {
+			selected = []models.Job{}
+		}
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, selected)
+		}
+		return export.Write(os.Stdout, *common.output, struct {
+			DryRun  bool         `json:"dry_run"`
+			Matched int          `json:"matched"`
+			Deleted int          `json:"deleted"`
+			Jobs    []models.Job `json:"jobs"`
+		}{DryRun: *dryRunFlag, Matched: len(selected), Deleted: removed, Jobs: selected})
+	}
+
+	for i, job := range selected {
+		if i >= 20 {
+			fmt.Printf("  ... and %d more\n", len(selected)-20)
+			break
+		}
+		fmt.Printf("  %s  %-40.40s  %-20.20s  %s\n", models.InDisplayZone(job.ScrapedAt).Format("2006-01-02"), job.Title, job.Source, job.ID)
+	}
+
+	if *dryRunFlag {
+		fmt.Printf("\nDry run: %d of %d jobs would be deleted\n", len(selected), len(jobs))
+	} else {
+		fmt.Printf("\nDeleted %d of %d jobs\n", removed, len(jobs))
+	}
+	return nil
+}
+
+// parseAge parses a duration that may also use d (days) and w (weeks) units
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(value, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(value, suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid age %q", value)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 72h, 30d, 2w)", value)
+	}
+	return age, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+func runRuns(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper runs <list|show> [flags]")
+	}
+
+	switch args[0] {
+	case "list":
+		return runRunsList(args[1:])
+	case "show":
+		return runRunsShow(args[1:])
+	default:
+		return fmt.Errorf("unknown runs command: %s", args[0])
+	}
+}
+
+func runRunsList(args []string) error {
+	fs := flag.NewFlagSet("runs list", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	limitFlag := fs.Int("limit", 20, "Number of recent runs to show (0 for all)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	history, err := storage.NewRunHistory(*common.data)
+	if err != nil {
+		return err
+	}
+
+	runs := history.List(*limitFlag)
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, runs)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No scrape runs recorded yet.")
+		return nil
+	}
+
+	fmt.Printf("%-26s %-19s %-9s %6s %6s %-7s %s\n", "ID", "STARTED", "DURATION", "FOUND", "NEW", "FAILED", "KEYWORDS")
+	for _, run := range runs {
+		failed := fmt.Sprintf("%d/%d", len(run.FailedSources()), len(run.Sources))
+		if run.Error != "" {
+			failed = "run"
+		}
+		fmt.Printf("%-26.26s %-19s %-9s %6d %6d %-7s %s\n",
+			run.ID,
+			models.InDisplayZone(run.StartedAt).Format("2006-01-02 15:04:05"),
+			run.FinishedAt.Sub(run.StartedAt).Round(time.Second),
+			run.JobsFound,
+			run.NewJobs,
+			failed,
+			strings.Join(run.Keywords, " "))
+	}
+	return nil
+}
+
+func runRunsShow(args []string) error {
+	fs := flag.NewFlagSet("runs show", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper runs show <id>")
+	}
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	history, err := storage.NewRunHistory(*common.data)
+	if err != nil {
+		return err
+	}
+	run, err := history.Get(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, run)
+	}
+	displayRunReport(run)
+	return nil
+}
+
+func displayRunReport(run *models.RunReport) {
+	fmt.Printf("Run %s\n", run.ID)
+	fmt.Printf("  Keywords:    %s\n", strings.Join(run.Keywords, " "))
+	if run.Location != "" {
+		fmt.Printf("  Location:    %s\n", run.Location)
+	}
+	fmt.Printf("  Started:     %s\n", models.InDisplayZone(run.StartedAt).Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Duration:    %v\n", run.FinishedAt.Sub(run.StartedAt).Round(time.Second))
+	if run.Schedule != "" {
+		fmt.Printf("  Schedule:    %s\n", run.Schedule)
+	}
+	if run.ConfigHash != "" {
+		fmt.Printf("  Config hash: %s\n", run.ConfigHash)
+	}
+	fmt.Printf("  Jobs found:  %d (%d new, %d duplicates)\n", run.JobsFound, run.NewJobs, run.Duplicates)
+	if run.Error != "" {
+		fmt.Printf("  Error:       %s\n", run.Error)
+	}
+
+	if len(run.Sources) == 0 {
+		return
+	}
+	fmt.Printf("\n%-24s %-10s %9s %6s %s\n", "SOURCE", "STATUS", "DURATION", "JOBS", "ERROR")
+	for _, source := range run.Sources {
+		duration := "-"
+		if source.Duration > 0 {
+			duration = source.Duration.Round(100 * time.Millisecond).String()
+		}
+		fmt.Printf("%-24.24s %-10s %9s %6d %s\n", source.Source, source.Status, duration, source.JobsFound, source.Error)
+	}
+}
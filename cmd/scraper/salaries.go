@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/salary"
+	"hire.ai/pkg/storage"
+)
+
+// salaryChartWidth is the width of the text range charts in characters
+const salaryChartWidth = 30
+
+// runSalaries reports what the stored jobs pay, as yearly salary
+// percentiles by title, seniority, location, category or company
+func runSalaries(args []string) error {
+	fs := flag.NewFlagSet("salaries", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	byFlag := fs.String("by", "title,seniority", "Group salaries by these dimensions (comma-separated: "+strings.Join(salary.Dimensions, ", ")+")")
+	keywordsFlag := fs.String("keywords", "", "Only benchmark jobs matching these keywords (comma-separated)")
+	keywordQueryFlag := fs.String("keyword-query", "", "Only benchmark jobs satisfying this boolean query, e.g. \"golang AND (kubernetes OR terraform)\"")
+	locationFlag := fs.String("location", "", "Only benchmark jobs in this location")
+	currencyFlag := fs.String("currency", "", "Only benchmark jobs paying in this currency (e.g. USD)")
+	minJobsFlag := fs.Int("min-jobs", 3, "Leave out groups with fewer jobs than this")
+	limitFlag := fs.Int("limit", 20, "Number of groups to report")
+	sinceFlag := fs.String("since", "", "Only benchmark jobs scraped within this age (e.g. 30d, 2w)")
+	htmlFlag := fs.String("html", "", "Also write the report with charts as an HTML page to this file")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *minJobsFlag <= 0 || *limitFlag <= 0 {
+		return fmt.Errorf("-min-jobs and -limit must be positive")
+	}
+	by := splitList(strings.ToLower(*byFlag))
+	if len(by) == 0 {
+		return fmt.Errorf("-by needs at least one of %s", strings.Join(salary.Dimensions, ", "))
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := store.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	filter := models.JobFilter{Keywords: splitList(*keywordsFlag), KeywordQuery: *keywordQueryFlag, Location: *locationFlag}
+	if err := filter.Compile(); err != nil {
+		return fmt.Errorf("invalid -keyword-query: %w", err)
+	}
+	if *sinceFlag != "" {
+		age, err := parseAge(*sinceFlag)
+		if err != nil {
+			return err
+		}
+		filter.DateFrom = time.Now().Add(-age)
+	}
+	var matching []models.Job
+	for i := range jobs {
+		if filter.Matches(&jobs[i]) {
+			matching = append(matching, jobs[i])
+		}
+	}
+	if len(matching) == 0 {
+		return fmt.Errorf("no stored jobs to benchmark; run a scrape first or widen the filters")
+	}
+
+	benchmark, err := salary.NewBenchmark(matching, by, *currencyFlag, *minJobsFlag)
+	if err != nil {
+		return err
+	}
+	if len(benchmark.Bands) > *limitFlag {
+		benchmark.Bands = benchmark.Bands[:*limitFlag]
+	}
+
+	if *htmlFlag != "" {
+		if err := writeBenchmarkHTML(benchmark, *htmlFlag); err != nil {
+			return err
+		}
+	}
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, benchmark.Bands)
+		}
+		return export.Write(os.Stdout, *common.output, benchmark)
+	}
+	printBenchmark(benchmark)
+	if *htmlFlag != "" {
+		fmt.Printf("\nWrote the report with charts to %s\n", *htmlFlag)
+	}
+	return nil
+}
+
+func writeBenchmarkHTML(benchmark *salary.Benchmark, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := benchmark.WriteHTML(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+func printBenchmark(benchmark *salary.Benchmark) {
+	fmt.Printf("Yearly salaries of %d jobs by %s", benchmark.JobsAnalyzed, strings.Join(benchmark.By, " / "))
+	if benchmark.JobsWithoutSalary > 0 {
+		jobs := "jobs"
+		if benchmark.JobsWithoutSalary == 1 {
+			jobs = "job"
+		}
+		fmt.Printf(" (%d %s without a salary left out)", benchmark.JobsWithoutSalary, jobs)
+	}
+	fmt.Println()
+	if benchmark.JobsAnalyzed == 0 {
+		fmt.Println("\nNone of these jobs give a salary.")
+		return
+	}
+
+	for _, overall := range benchmark.Overall {
+		bands := []salary.Band{overall}
+		for _, band := range benchmark.Bands {
+			if band.Currency == overall.Currency {
+				bands = append(bands, band)
+			}
+		}
+		low, high := salary.Scale(bands, overall.Currency)
+		labelWidth := 0
+		for _, band := range bands {
+			labelWidth = max(labelWidth, len([]rune(band.Label())))
+		}
+		labelWidth = min(labelWidth, 40)
+
+		fmt.Printf("\n%s\n", overall.Currency)
+		fmt.Printf("%-*s %5s %7s %7s %7s %7s %7s  %s\n", labelWidth, "Group", "Jobs", "P10", "P25", "Median", "P75", "P90",
+			fmt.Sprintf("%-*s%s", salaryChartWidth-len(salary.FormatAmount(high)), salary.FormatAmount(low), salary.FormatAmount(high)))
+		for i, band := range bands {
+			if i == 1 {
+				fmt.Println()
+			}
+			fmt.Printf("%-*s %5d %7s %7s %7s %7s %7s  %s\n", labelWidth, truncateLabel(band.Label(), labelWidth), band.Jobs,
+				salary.FormatAmount(band.P10), salary.FormatAmount(band.P25), salary.FormatAmount(band.P50),
+				salary.FormatAmount(band.P75), salary.FormatAmount(band.P90), salary.Chart(band, low, high, salaryChartWidth))
+		}
+		if len(bands) == 1 {
+			fmt.Println("\nNo group has enough jobs; lower -min-jobs or group by fewer dimensions.")
+		}
+	}
+}
+
+// truncateLabel shortens label to width runes, marking the cut with …
+func truncateLabel(label string, width int) string {
+	runes := []rune(label)
+	if len(runes) <= width {
+		return label
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/notify"
+	"hire.ai/pkg/providers"
+)
+
+// searchArea keeps a scrape to the jobs within RadiusKm of Near, when Near
+// is set, and to those posted within PostedWithin, such as 7d, when that is
+type searchArea struct {
+	Near         string  `json:"near"`
+	RadiusKm     float64 `json:"radius_km"`
+	PostedWithin string  `json:"posted_within,omitempty"`
+}
+
+func runScrape(args []string) error {
+	fs := flag.NewFlagSet("scrape", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	keywordsFlag := fs.String("keywords", "", "Job search keywords (comma-separated)")
+	locationFlag := fs.String("location", "", "Job location")
+	nearFlag := fs.String("near", "", "Keep only jobs near this place, e.g. \"Austin, TX\" (searches there unless -location is given)")
+	radiusFlag := fs.String("radius", "", "Distance from -near, e.g. 50mi or 80km (default 25mi)")
+	postedWithinFlag := fs.String("posted-within", "", "Keep only jobs posted in the last 1d, 3d, 7d, 14d or 30d; jobs whose source gives no date are kept")
+	resumeFlag := fs.String("resume", "", "Resume (PDF, DOCX or text) to derive keywords and boost matching jobs")
+	watchFlag := fs.Duration("watch", 0, "Keep scraping at this interval (e.g. 30m) until interrupted")
+	desktopFlag := fs.Bool("desktop", false, "Show desktop notifications for new jobs")
+	desktopRelevanceFlag := fs.Float64("desktop-min-relevance", 1.0, "Minimum relevance of jobs shown as desktop notifications")
+	pprofFlag := fs.String("pprof-addr", "", "Serve pprof profiles on this address while watching (e.g. localhost:6060)")
+	continueFlag := fs.Bool("continue", false, "Continue the last interrupted run with its keywords, location and resume, skipping the sources it finished")
+	summaryFlag := fs.String("summary-file", "", "Write a JSON summary of the run (outcome, exit code, counts and errors per source) to this path")
+	strictFlag := fs.Bool("strict", false, "Exit non-zero when some sources failed (9) or no new jobs were found (10)")
+	sinceLastRunFlag := fs.Bool("since-last-run", false, "Stop reading a board's result pages at the first page with no job found before")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *watchFlag < 0 {
+		return fmt.Errorf("-watch must be a positive interval")
+	}
+	if *pprofFlag != "" && *watchFlag == 0 {
+		return fmt.Errorf("-pprof-addr requires -watch")
+	}
+	if (*summaryFlag != "" || *strictFlag) && *watchFlag > 0 {
+		return fmt.Errorf("-summary-file and -strict apply to single runs, not -watch")
+	}
+	if *continueFlag {
+		if *watchFlag > 0 {
+			return fmt.Errorf("-continue cannot be combined with -watch")
+		}
+		if *keywordsFlag != "" || *locationFlag != "" || *resumeFlag != "" || *nearFlag != "" || *radiusFlag != "" || *postedWithinFlag != "" {
+			return fmt.Errorf("-continue reuses the interrupted run's -keywords, -location, -resume, -near, -radius and -posted-within")
+		}
+	}
+	var area *searchArea
+	if *nearFlag != "" {
+		area = &searchArea{Near: *nearFlag, RadiusKm: geo.DefaultRadiusKm}
+		if *radiusFlag != "" {
+			radius, err := geo.ParseRadius(*radiusFlag)
+			if err != nil {
+				return err
+			}
+			area.RadiusKm = radius
+		}
+		if *locationFlag == "" {
+			*locationFlag = *nearFlag
+		}
+	} else if *radiusFlag != "" {
+		return fmt.Errorf("-radius requires -near")
+	}
+	if *postedWithinFlag != "" {
+		if providers.DatePostedDays(*postedWithinFlag) == 0 {
+			return fmt.Errorf("-posted-within must be 1d, 3d, 7d, 14d or 30d")
+		}
+		if area == nil {
+			area = &searchArea{}
+		}
+		area.PostedWithin = strings.ToLower(*postedWithinFlag)
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return finishRun(nil, err, *strictFlag, *summaryFlag)
+	}
+	defer app.Close()
+	app.output = *common.output
+	app.area = area
+	app.sinceLastRun = *sinceLastRunFlag
+	var report *models.RunReport
+	app.onRun = func(r models.RunReport) {
+		report = &r
+	}
+
+	if *desktopFlag {
+		desktop, err := notify.NewDesktopNotifier(notify.DesktopConfig{Enabled: true, MinRelevance: *desktopRelevanceFlag}, app.logger)
+		if err != nil {
+			return err
+		}
+		app.addNotifier(desktop)
+	}
+
+	if *watchFlag > 0 {
+		ctx, stop := commandContext()
+		defer stop()
+		if *pprofFlag != "" {
+			if err := startPprof(ctx, *pprofFlag, app.logger); err != nil {
+				return fmt.Errorf("failed to start pprof server: %w", err)
+			}
+		}
+		return app.Watch(ctx, *keywordsFlag, *locationFlag, *resumeFlag, *watchFlag)
+	}
+	ctx, stop := commandContext()
+	defer stop()
+	if *continueFlag {
+		err = app.Continue(ctx)
+	} else {
+		err = app.Run(ctx, *keywordsFlag, *locationFlag, *resumeFlag)
+	}
+	return finishRun(report, err, *strictFlag, *summaryFlag)
+}
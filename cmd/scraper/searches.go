@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/notify"
+	"hire.ai/pkg/salary"
+	"hire.ai/pkg/storage"
+)
+
+func runSearches(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper searches <add|list|remove|alerts> [flags]")
+	}
+
+	switch args[0] {
+	case "add":
+		return runSearchesAdd(args[1:])
+	case "list":
+		return runSearchesList(args[1:])
+	case "remove":
+		return runSearchesRemove(args[1:])
+	case "alerts":
+		return runSearchesAlerts(args[1:])
+	default:
+		return fmt.Errorf("unknown searches command: %s", args[0])
+	}
+}
+
+func runSearchesAdd(args []string) error {
+	fs := flag.NewFlagSet("searches add", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "User the search belongs to (default: the shared default profile)")
+	nameFlag := fs.String("name", "", "Search name; the search ID is derived from it")
+	queryFlag := fs.String("q", "", "Full-text query jobs must match, e.g. '\"platform engineer\" kubernetes'")
+	semanticFlag := fs.String("semantic", "", "Natural-language description of the jobs wanted, matched by meaning when semanticSearch is set up and by its keywords otherwise")
+	keywordsFlag := fs.String("keywords", "", "Comma-separated keywords, any of which a job must mention")
+	keywordQueryFlag := fs.String("keyword-query", "", "Boolean query jobs must satisfy, e.g. \"golang AND (kubernetes OR terraform) NOT intern\"")
+	locationFlag := fs.String("location", "", "Text the job's location must contain")
+	nearFlag := fs.String("near", "", "Keep jobs near this place")
+	radiusFlag := fs.String("radius", "", "Distance from -near, e.g. 50mi or 80km (default 25mi)")
+	sourcesFlag := fs.String("sources", "", "Comma-separated sources jobs must come from")
+	minSalaryFlag := fs.String("min-salary", "", "Minimum yearly salary, e.g. 80000 or '80000 USD'; with a currency, salaries in others are converted")
+	maxSalaryFlag := fs.String("max-salary", "", "Maximum yearly salary, e.g. 150k or '120000 EUR'")
+	categoriesFlag := fs.String("categories", "", "Comma-separated role categories")
+	senioritiesFlag := fs.String("seniorities", "", "Comma-separated seniority levels")
+	industriesFlag := fs.String("industries", "", "Comma-separated industries")
+	employmentTypesFlag := fs.String("employment-types", "", "Comma-separated employment types: full-time, part-time, contract, intern")
+	remoteTypesFlag := fs.String("remote-types", "", "Comma-separated remote types: remote, hybrid, onsite")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *nameFlag == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if err := checkUser(*common.data, *userFlag); err != nil {
+		return err
+	}
+	minSalary, maxSalary, salaryCurrency, err := salary.ParseBounds(*minSalaryFlag, *maxSalaryFlag)
+	if err != nil {
+		return err
+	}
+
+	filter := models.JobFilter{
+		Query:           *queryFlag,
+		Semantic:        strings.TrimSpace(*semanticFlag),
+		Keywords:        splitList(*keywordsFlag),
+		KeywordQuery:    strings.TrimSpace(*keywordQueryFlag),
+		Location:        strings.TrimSpace(*locationFlag),
+		Near:            strings.TrimSpace(*nearFlag),
+		Sources:         splitList(*sourcesFlag),
+		MinSalary:       minSalary,
+		MaxSalary:       maxSalary,
+		SalaryCurrency:  salaryCurrency,
+		Categories:      splitList(*categoriesFlag),
+		Seniorities:     splitList(*senioritiesFlag),
+		Industries:      splitList(*industriesFlag),
+		EmploymentTypes: splitList(*employmentTypesFlag),
+		RemoteTypes:     splitList(*remoteTypesFlag),
+	}
+	if err := filter.Compile(); err != nil {
+		return fmt.Errorf("invalid -keyword-query: %w", err)
+	}
+	if *radiusFlag != "" {
+		radius, err := geo.ParseRadius(*radiusFlag)
+		if err != nil {
+			return err
+		}
+		filter.RadiusKm = radius
+	}
+	// The place is located once, so matching the saved search after each
+	// scrape needs no geocoding
+	if filter.Near != "" || filter.RadiusKm > 0 {
+		app, err := NewApplication(*common.config, *common.data, common.logger())
+		if err != nil {
+			return err
+		}
+		defer app.Close()
+		ctx, stop := commandContext()
+		defer stop()
+		if err := app.geocoder.ResolveFilter(ctx, &filter); err != nil {
+			return err
+		}
+	}
+
+	searches, err := storage.NewSavedSearchStore(*common.data)
+	if err != nil {
+		return err
+	}
+	search := models.NewSavedSearch(*nameFlag, filter)
+	search.UserID = *userFlag
+	if err := searches.Create(search); err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, search)
+	}
+	fmt.Printf("Saved search %s (%s): %s\n", search.Name, search.ID, describeFilter(search.Filter))
+	fmt.Println("New jobs it matches are collected after each scrape; read them with: job-scraper searches alerts")
+	return nil
+}
+
+func runSearchesList(args []string) error {
+	fs := flag.NewFlagSet("searches list", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "List this user's searches (default: the shared default profile)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	searches, err := storage.NewSavedSearchStore(*common.data)
+	if err != nil {
+		return err
+	}
+	list := searches.List(*userFlag)
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No saved searches; add one with: job-scraper searches add -name <name> -q <query>")
+		return nil
+	}
+
+	alerts, err := storage.NewAlertStore(*common.data)
+	if err != nil {
+		return err
+	}
+	unread := alerts.Unread(*userFlag)
+	fmt.Printf("%-24s %-24s %-7s %s\n", "ID", "NAME", "UNREAD", "FILTER")
+	for _, search := range list {
+		fmt.Printf("%-24.24s %-24.24s %-7d %s\n", search.ID, search.Name, unread[search.ID], describeFilter(search.Filter))
+	}
+	return nil
+}
+
+func runSearchesRemove(args []string) error {
+	fs := flag.NewFlagSet("searches remove", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "User the search belongs to (default: the shared default profile)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper searches remove [flags] <id>")
+	}
+	id := fs.Arg(0)
+
+	searches, err := storage.NewSavedSearchStore(*common.data)
+	if err != nil {
+		return err
+	}
+	if _, err := searches.Get(*userFlag, id); err != nil {
+		return err
+	}
+	alerts, err := storage.NewAlertStore(*common.data)
+	if err != nil {
+		return err
+	}
+	removedAlerts, err := alerts.DeleteSearch(*userFlag, id)
+	if err != nil {
+		return err
+	}
+	if _, err := searches.Delete(*userFlag, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed saved search %s with %d alerts\n", id, removedAlerts)
+	return nil
+}
+
+// runSearchesAlerts prints the digest of the jobs saved searches matched
+// since it was last read, and marks them read
+func runSearchesAlerts(args []string) error {
+	fs := flag.NewFlagSet("searches alerts", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "Show this user's alerts (default: the shared default profile)")
+	searchFlag := fs.String("search", "", "Only show the alerts of this saved search ID")
+	allFlag := fs.Bool("all", false, "Include alerts already read")
+	keepFlag := fs.Bool("keep", false, "Leave the alerts shown unread")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	searches, err := storage.NewSavedSearchStore(*common.data)
+	if err != nil {
+		return err
+	}
+	list := searches.List(*userFlag)
+	if *searchFlag != "" {
+		search, err := searches.Get(*userFlag, *searchFlag)
+		if err != nil {
+			return err
+		}
+		list = []models.SavedSearch{*search}
+	}
+	alerts, err := storage.NewAlertStore(*common.data)
+	if err != nil {
+		return err
+	}
+	listed := alerts.List(*userFlag, *searchFlag, !*allFlag)
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := alertedJobs(ctx, store, listed)
+	if err != nil {
+		return err
+	}
+	digest := notify.BuildAlertDigest(list, listed, jobs)
+
+	if common.machineReadable() {
+		err = export.Write(os.Stdout, *common.output, digest)
+	} else {
+		var rendered string
+		if rendered, err = digest.Markdown(); err == nil {
+			fmt.Print(rendered)
+		}
+	}
+	if err != nil || *keepFlag {
+		return err
+	}
+	_, err = alerts.MarkRead(*userFlag, *searchFlag)
+	return err
+}
+
+// alertedJobs loads the stored jobs alerts name, by ID
+func alertedJobs(ctx context.Context, store storage.Storage, alerts []models.Alert) (map[string]models.Job, error) {
+	jobs := make(map[string]models.Job, len(alerts))
+	if len(alerts) == 0 {
+		return jobs, nil
+	}
+	wanted := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		wanted[alert.JobID] = true
+	}
+	all, err := store.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	for _, job := range all {
+		if wanted[job.ID] {
+			jobs[job.ID] = job
+		}
+	}
+	return jobs, nil
+}
+
+// checkUser reports an error unless userID is empty or names a user
+func checkUser(dataDir, userID string) error {
+	if userID == "" {
+		return nil
+	}
+	users, err := storage.NewUserStore(dataDir)
+	if err != nil {
+		return err
+	}
+	_, err = users.Get(userID)
+	return err
+}
+
+// describeFilter sums up the criteria of a saved search's filter
+func describeFilter(filter models.JobFilter) string {
+	var parts []string
+	if filter.Query != "" {
+		parts = append(parts, fmt.Sprintf("%q", filter.Query))
+	}
+	if filter.Semantic != "" {
+		parts = append(parts, fmt.Sprintf("like %q", filter.Semantic))
+	}
+	if len(filter.Keywords) > 0 {
+		parts = append(parts, "keywords "+strings.Join(filter.Keywords, ", "))
+	}
+	if filter.KeywordQuery != "" {
+		parts = append(parts, "matching "+filter.KeywordQuery)
+	}
+	if filter.Location != "" {
+		parts = append(parts, "in "+filter.Location)
+	}
+	if filter.Near != "" {
+		parts = append(parts, fmt.Sprintf("within %.0f mi of %s", geo.Miles(filter.RadiusKm), filter.Near))
+	}
+	if len(filter.Sources) > 0 {
+		parts = append(parts, "from "+strings.Join(filter.Sources, ", "))
+	}
+	currency := ""
+	if filter.SalaryCurrency != "" {
+		currency = " " + filter.SalaryCurrency
+	}
+	if filter.MinSalary > 0 {
+		parts = append(parts, fmt.Sprintf("salary ≥ %d%s", filter.MinSalary, currency))
+	}
+	if filter.MaxSalary > 0 {
+		parts = append(parts, fmt.Sprintf("salary ≤ %d%s", filter.MaxSalary, currency))
+	}
+	for _, values := range [][]string{filter.Categories, filter.Seniorities, filter.Industries, filter.EmploymentTypes, filter.RemoteTypes} {
+		if len(values) > 0 {
+			parts = append(parts, strings.Join(values, "/"))
+		}
+	}
+	if len(parts) == 0 {
+		return "all jobs"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// alert records the newly stored jobs each saved search matches and hands
+// the alerts that were new to the notifiers that deliver them
+func (app *Application) alert(runID string, jobs []models.Job) {
+	searches := app.searches.All()
+	if len(searches) == 0 || len(jobs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	byID := make(map[string]models.Job, len(jobs))
+	var matches []models.Alert
+	for _, search := range searches {
+		filter := search.Filter
+		if err := filter.Compile(); err != nil {
+			app.logger.Warnf("Skipping saved search %s: invalid keyword query: %v", search.ID, err)
+			continue
+		}
+		for i := range jobs {
+			if filter.Matches(&jobs[i]) {
+				byID[jobs[i].ID] = jobs[i]
+				matches = append(matches, models.Alert{
+					SearchID: search.ID, UserID: search.UserID, JobID: jobs[i].ID, RunID: runID, MatchedAt: now,
+				})
+			}
+		}
+	}
+	added, err := app.alerts.Add(matches)
+	if err != nil {
+		app.logger.Errorf("Failed to record saved search alerts: %v", err)
+		return
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	digest := notify.BuildAlertDigest(searches, added, byID)
+	app.logger.Infof("Saved searches matched %d new jobs; read them with 'job-scraper searches alerts'", digest.Count())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	for _, notifier := range app.notifiers {
+		if alertNotifier, ok := notifier.(notify.AlertNotifier); ok {
+			if err := alertNotifier.NotifyAlerts(ctx, digest); err != nil {
+				app.logger.Errorf("%s alert notifications failed: %v", notifier.Name(), err)
+			}
+		}
+	}
+}
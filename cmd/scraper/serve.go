@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/server"
+	"hire.ai/pkg/storage"
+	"hire.ai/pkg/webhook"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	addrFlag := fs.String("addr", envOrDefault("SERVER_ADDR", ":8080"), "Address for the API server to listen on")
+	workersFlag := fs.Int("scrape-workers", 1, "Maximum scrape runs to execute at once; more are queued")
+	publicFlag := fs.Bool("public", false, "Serve only read-only routes, without tokens and rate limited per client IP")
+	rateFlag := fs.Int("public-rate", 60, "Requests per minute allowed per client IP in public mode")
+	burstFlag := fs.Int("public-burst", 20, "Requests a client IP may burst above the rate in public mode")
+	trustProxyFlag := fs.Bool("trust-proxy", false, "Take client IPs from X-Forwarded-For in public mode (only behind a reverse proxy)")
+	corsFlag := fs.String("cors-origins", envOrDefault("CORS_ORIGINS", ""), "Comma-separated browser origins allowed to call the API (* for any)")
+	insecureFlag := fs.Bool("insecure", false, "Serve every route as admin without a token while no tokens exist (local use only)")
+	pprofFlag := fs.String("pprof-addr", "", "Serve pprof profiles on this address, apart from the API (e.g. localhost:6060)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	logger := common.logger()
+	app, err := NewApplication(*common.config, *common.data, logger)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	scrape := func(ctx context.Context, keywords []string, location string, progress func(models.SourceProgress)) (int, error) {
+		return app.ScrapeJobs(ctx, keywords, location, progress)
+	}
+	applications, err := storage.NewApplicationStore(*common.data)
+	if err != nil {
+		return err
+	}
+	webhooks, err := storage.NewWebhookStore(*common.data)
+	if err != nil {
+		return err
+	}
+	runs, err := storage.NewRunStore(*common.data)
+	if err != nil {
+		return err
+	}
+	tokens, err := auth.NewTokenStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	if *pprofFlag != "" {
+		if err := startPprof(ctx, *pprofFlag, logger); err != nil {
+			return fmt.Errorf("failed to start pprof server: %w", err)
+		}
+	}
+
+	dispatcher := webhook.NewDispatcher(webhooks, logger)
+	dispatcher.Start(ctx)
+	app.onNewJobs = dispatcher.Dispatch
+	app.expiry.Start(ctx)
+
+	var public *server.PublicConfig
+	if *publicFlag {
+		public = &server.PublicConfig{
+			RequestsPerMinute: *rateFlag,
+			Burst:             *burstFlag,
+			TrustProxy:        *trustProxyFlag,
+		}
+	}
+
+	var corsOrigins []string
+	for _, origin := range strings.Split(*corsFlag, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			corsOrigins = append(corsOrigins, origin)
+		}
+	}
+
+	srv := server.NewServer(server.Config{
+		Storage:       app.storage,
+		SavedSearches: app.searches,
+		Users:         app.users,
+		Applications:  applications,
+		Webhooks:      webhooks,
+		Dispatcher:    dispatcher,
+		Scraper:       app.scraper,
+		Scrape:        scrape,
+		Geocoder:      app.geocoder,
+		Scorer:        app.scorer,
+		Runs:          runs,
+		ScrapeWorkers: *workersFlag,
+		Tokens:        tokens,
+		Insecure:      *insecureFlag,
+		Public:        public,
+		CORSOrigins:   corsOrigins,
+		Logger:        logger,
+	})
+
+	err = srv.ListenAndServe(ctx, *addrFlag)
+	stop()
+	dispatcher.Wait()
+	app.expiry.Wait()
+	return err
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
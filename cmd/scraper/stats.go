@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/storage"
+)
+
+func runStats(args []string) error {
+	if len(args) > 0 && args[0] == "api" {
+		return runStatsAPI(args[1:])
+	}
+
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	rebuildFlag := fs.Bool("rebuild", false, "Recount the stats from every stored job and report whether the kept counts had drifted")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	// Storage keeps its counts current on every write; a rebuild checks
+	// them against a full recount and repairs any drift
+	if *rebuildFlag {
+		if store.RebuildStats() {
+			fmt.Fprintln(os.Stderr, "Stats had drifted from the stored jobs and were rebuilt")
+		} else {
+			fmt.Fprintln(os.Stderr, "Stats match the stored jobs")
+		}
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	stats, err := store.GetStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, stats)
+	}
+	displayStats(stats)
+	return nil
+}
+
+// apiUsageRow is a provider's usage today and this month against its
+// configured quotas, for output
+type apiUsageRow struct {
+	Provider     string          `json:"provider"`
+	Today        api.UsageCounts `json:"today"`
+	DailyQuota   int             `json:"daily_quota,omitempty"`
+	Month        api.UsageCounts `json:"month"`
+	MonthlyQuota int             `json:"monthly_quota,omitempty"`
+	Total        api.UsageCounts `json:"total"`
+	LastUsed     *time.Time      `json:"last_used,omitempty"`
+}
+
+// runStatsAPI reports the API providers' requests across runs against the
+// quotas in their rate_limit config, so a plan running out shows before
+// its searches start failing
+func runStatsAPI(args []string) error {
+	fs := flag.NewFlagSet("stats api", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	usage, err := api.ReadUsage(filepath.Join(*common.data, apiUsageFile))
+	if err != nil {
+		return fmt.Errorf("failed to read API usage: %w", err)
+	}
+	core, err := scraper.NewScraperCoreWithLogger(*common.config, common.logger())
+	if err != nil {
+		return err
+	}
+
+	// Enabled providers are listed even before their first search, so
+	// their quotas show; providers since removed keep their history
+	now := time.Now()
+	rows := make(map[string]*apiUsageRow)
+	for _, provider := range core.GetConfig().APIProviders {
+		if !provider.Enabled {
+			continue
+		}
+		rows[provider.Name] = &apiUsageRow{
+			Provider:     provider.Name,
+			DailyQuota:   provider.RateLimit.RequestsPerDay,
+			MonthlyQuota: provider.RateLimit.RequestsPerMonth,
+		}
+	}
+	for i := range usage {
+		u := &usage[i]
+		row, ok := rows[u.Provider]
+		if !ok {
+			row = &apiUsageRow{Provider: u.Provider}
+			rows[u.Provider] = row
+		}
+		row.Today, row.Month, row.Total, row.LastUsed = u.Day(now), u.Month(now), u.UsageCounts, &u.LastUsed
+	}
+
+	sorted := make([]apiUsageRow, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, *row)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Provider < sorted[j].Provider })
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, sorted)
+	}
+
+	if len(sorted) == 0 {
+		fmt.Println("No API providers configured and no API usage recorded yet.")
+		return nil
+	}
+
+	fmt.Printf("%-12s %18s %18s %9s %7s %9s  %s\n", "PROVIDER", "TODAY", "THIS MONTH", "FAILURES", "JOBS", "TOTAL", "LAST USED")
+	for _, row := range sorted {
+		lastUsed := "never"
+		if row.LastUsed != nil {
+			lastUsed = models.InDisplayZone(*row.LastUsed).Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-12.12s %18s %18s %9d %7d %9d  %s\n",
+			row.Provider, formatQuota(row.Today.Requests, row.DailyQuota), formatQuota(row.Month.Requests, row.MonthlyQuota),
+			row.Month.Failures, row.Month.Jobs, row.Total.Requests, lastUsed)
+	}
+	fmt.Println("\nDays and months are UTC; failures and jobs are this month's.")
+	return nil
+}
+
+// formatQuota renders requests made against a quota, or alone when there
+// is none
+func formatQuota(used, quota int) string {
+	if quota <= 0 {
+		return fmt.Sprint(used)
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", used, quota, float64(used)/float64(quota)*100)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+func runStatus(args []string) error {
+	usage := fmt.Errorf("usage: job-scraper status <list|%s> [flags] [job-id...]", strings.Join(models.JobStatuses, "|"))
+	if len(args) == 0 {
+		return usage
+	}
+
+	if args[0] == "list" {
+		return runStatusList(args[1:])
+	}
+	if err := models.ValidateJobStatus(args[0]); err != nil {
+		return usage
+	}
+	return runStatusSet(args[0], args[1:])
+}
+
+// runStatusSet moves the jobs named by ID to status
+func runStatusSet(status string, args []string) error {
+	fs := flag.NewFlagSet("status "+status, flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: job-scraper status %s [flags] <job-id>...", status)
+	}
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	var updated []models.Job
+	for _, id := range fs.Args() {
+		job, err := store.UpdateStatus(ctx, id, status)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, *job)
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, updated)
+	}
+	for _, job := range updated {
+		fmt.Printf("%s: %s at %s marked %s\n", job.ID, job.Title, job.Company, status)
+	}
+	return nil
+}
+
+// runStatusList shows the jobs in the given states, most recently changed
+// first, with how many jobs are in each state
+func runStatusList(args []string) error {
+	fs := flag.NewFlagSet("status list", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	statusFlag := fs.String("status", strings.Join([]string{models.JobSeen, models.JobApplied}, ","),
+		"Show jobs in these states (comma-separated: "+strings.Join(models.JobStatuses, ", ")+")")
+	limitFlag := fs.Int("limit", 50, "Number of jobs to show (0 for all)")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	statuses := splitList(strings.ToLower(*statusFlag))
+	for _, status := range statuses {
+		if err := models.ValidateJobStatus(status); err != nil {
+			return err
+		}
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := store.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	counts := make(map[string]int, len(models.JobStatuses))
+	filter := models.JobFilter{Statuses: statuses}
+	listed := make([]models.Job, 0)
+	for i := range jobs {
+		counts[jobs[i].CurrentStatus()]++
+		if filter.Matches(&jobs[i]) {
+			listed = append(listed, jobs[i])
+		}
+	}
+	sortByStatusChange(listed)
+	if *limitFlag > 0 && len(listed) > *limitFlag {
+		listed = listed[:*limitFlag]
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, listed)
+	}
+
+	var summary []string
+	for _, status := range models.JobStatuses {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	fmt.Println(strings.Join(summary, ", "))
+	if len(listed) == 0 && len(statuses) > 0 {
+		fmt.Printf("No jobs are %s.\n", strings.Join(statuses, " or "))
+		return nil
+	}
+
+	fmt.Printf("\n%-32s %-9s %-16s %-24s %s\n", "ID", "STATUS", "CHANGED", "COMPANY", "TITLE")
+	for _, job := range listed {
+		changed := "-"
+		if job.StatusChangedAt != nil {
+			changed = models.InDisplayZone(*job.StatusChangedAt).Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-32s %-9s %-16s %-24.24s %s\n", job.ID, job.CurrentStatus(), changed, job.Company, job.Title)
+	}
+	return nil
+}
+
+// sortByStatusChange orders jobs by when their status last changed, most
+// recent first, and jobs never moved by when they were scraped
+func sortByStatusChange(jobs []models.Job) {
+	changed := func(job *models.Job) int64 {
+		if job.StatusChangedAt != nil {
+			return job.StatusChangedAt.UnixNano()
+		}
+		return job.ScrapedAt.UnixNano()
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return changed(&jobs[i]) > changed(&jobs[j]) })
+}
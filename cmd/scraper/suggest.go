@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/storage"
+	"hire.ai/pkg/suggest"
+)
+
+// runSuggest proposes keywords, exclusions and companies from the jobs a
+// user has saved or applied to and the ones they archived
+func runSuggest(args []string) error {
+	fs := flag.NewFlagSet("suggest", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	userFlag := fs.String("user", "", "User whose application statuses to learn from, besides the jobs' own statuses (default: the shared profile)")
+	keywordsFlag := fs.String("keywords", os.Getenv("DEFAULT_KEYWORDS"), "Keywords you already search for (comma-separated), left out of suggestions")
+	limitFlag := fs.Int("limit", 10, "Number of suggestions per list")
+	minJobsFlag := fs.Int("min-jobs", 2, "Jobs a term or company must appear in to be suggested")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag <= 0 || *minJobsFlag <= 0 {
+		return fmt.Errorf("-limit and -min-jobs must be positive")
+	}
+
+	store, err := storage.NewFileStorage(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+	applications, err := storage.NewApplicationStore(*common.data)
+	if err != nil {
+		return fmt.Errorf("failed to open applications: %w", err)
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := store.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	result := suggest.Suggest(jobs, applications.List(*userFlag, ""), splitList(*keywordsFlag), *minJobsFlag, *limitFlag)
+	if result.Liked == 0 {
+		return fmt.Errorf("no saved or applied jobs to learn from; mark jobs with the status command or through the API first")
+	}
+
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, suggestionRows(result))
+		}
+		return export.Write(os.Stdout, *common.output, result)
+	}
+	printSuggestions(result)
+	return nil
+}
+
+// suggestionRow flattens Suggestions for CSV
+type suggestionRow struct {
+	Kind     string `json:"kind"`
+	Value    string `json:"value"`
+	Liked    int    `json:"liked"`
+	Archived int    `json:"archived"`
+}
+
+func suggestionRows(result *suggest.Suggestions) []suggestionRow {
+	var rows []suggestionRow
+	for _, term := range result.Keywords {
+		rows = append(rows, suggestionRow{"keyword", term.Term, term.Liked, term.Archived})
+	}
+	for _, term := range result.Exclusions {
+		rows = append(rows, suggestionRow{"exclusion", term.Term, term.Liked, term.Archived})
+	}
+	for _, company := range result.Companies {
+		rows = append(rows, suggestionRow{"company", company.Name, company.Liked, company.Archived})
+	}
+	for _, company := range result.AvoidCompanies {
+		rows = append(rows, suggestionRow{"avoid_company", company.Name, company.Liked, company.Archived})
+	}
+	return rows
+}
+
+func printSuggestions(result *suggest.Suggestions) {
+	fmt.Printf("Learned from %d liked and %d archived jobs\n", result.Liked, result.Archived)
+
+	fmt.Println("\nKeywords to add:")
+	if len(result.Keywords) == 0 {
+		fmt.Println("  none yet")
+	}
+	for _, term := range result.Keywords {
+		fmt.Printf("  %-20s in %d of your liked jobs (%+.0f%%)\n", term.Term, term.Liked, term.Lift*100)
+	}
+
+	fmt.Println("\nExclusions to add:")
+	if len(result.Exclusions) == 0 {
+		if result.Archived == 0 {
+			fmt.Println("  archive jobs that don't fit to learn exclusions")
+		} else {
+			fmt.Println("  none yet")
+		}
+	}
+	for _, term := range result.Exclusions {
+		fmt.Printf("  %-20s in %d of your archived jobs (%+.0f%%)\n", term.Term, term.Archived, term.Lift*100)
+	}
+
+	fmt.Println("\nCompanies to follow:")
+	for _, company := range result.Companies {
+		fmt.Printf("  %-30s %d liked", company.Name, company.Liked)
+		if company.Unreviewed > 0 {
+			fmt.Printf(", %d more to review", company.Unreviewed)
+		}
+		fmt.Println()
+	}
+
+	if len(result.AvoidCompanies) > 0 {
+		fmt.Println("\nCompanies to skip:")
+		for _, company := range result.AvoidCompanies {
+			fmt.Printf("  %-30s %d archived\n", company.Name, company.Archived)
+		}
+	}
+}
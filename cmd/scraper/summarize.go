@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+)
+
+// runSummarize backfills LLM summaries for stored jobs that have none
+func runSummarize(args []string) error {
+	fs := flag.NewFlagSet("summarize", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	limitFlag := fs.Int("limit", 50, "Maximum number of jobs to summarize")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *limitFlag <= 0 {
+		return fmt.Errorf("-limit must be positive")
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if app.summarizer == nil {
+		return fmt.Errorf("summarization is not enabled in the config")
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	llmCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+	summary := func(job *models.Job) interface{} { return job.Summary }
+	before := fieldValues(jobs, summary)
+	count := app.summarizer.SummarizeAll(llmCtx, jobs, *limitFlag)
+	if count > 0 {
+		if _, err := app.storage.Upsert(ctx, jobs); err != nil {
+			return fmt.Errorf("failed to store summaries: %w", err)
+		}
+	}
+
+	if common.machineReadable() {
+		summarized := updatedJobs(jobs, before, summary)
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, summarized)
+		}
+		return export.Write(os.Stdout, *common.output, struct {
+			Summarized int          `json:"summarized"`
+			Jobs       []models.Job `json:"jobs"`
+		}{Summarized: count, Jobs: summarized})
+	}
+	if count == 0 {
+		fmt.Println("No jobs were summarized")
+		return nil
+	}
+	fmt.Printf("Summarized %d jobs\n", count)
+	return nil
+}
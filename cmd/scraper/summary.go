@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+// Outcomes -strict turns into failures; a run without -strict that ends in
+// one of them still exits 0
+var (
+	errPartialFailure = errors.New("some sources failed")
+	errNoNewJobs      = errors.New("no new jobs")
+)
+
+// Run outcomes written to -summary-file
+const (
+	outcomeOK          = "ok"
+	outcomeNoNewJobs   = "no_new_jobs"
+	outcomePartial     = "partial"
+	outcomeAllFailed   = "all_failed"
+	outcomeConfigError = "config_error"
+	outcomeInterrupted = "interrupted"
+	outcomeFailed      = "failed"
+)
+
+// runSummary is what -summary-file holds once a scrape ends, so cron and CI
+// wrappers can tell a partial failure from a quiet day without reading logs
+type runSummary struct {
+	Outcome  string `json:"outcome"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	// DurationSeconds is the whole run's; each source's is in Run
+	DurationSeconds float64 `json:"duration_seconds"`
+	SourcesFailed   int     `json:"sources_failed"`
+	// Run is the run's report, missing when it never started, as with a
+	// config error
+	Run *models.RunReport `json:"run,omitempty"`
+}
+
+// finishRun settles a scrape's result: with strict, a run that left sources
+// failed or found no new jobs becomes an error, and with summaryPath the
+// summary is written there. It returns the error the command exits with.
+func finishRun(report *models.RunReport, err error, strict bool, summaryPath string) error {
+	outcome := runOutcome(report, err)
+	if err == nil && strict {
+		switch outcome {
+		case outcomePartial:
+			err = fmt.Errorf("%w: %d of %d", errPartialFailure, len(report.FailedSources()), len(report.Sources))
+		case outcomeNoNewJobs:
+			err = errNoNewJobs
+		}
+	}
+	if summaryPath == "" {
+		return err
+	}
+
+	summary := runSummary{Outcome: outcome, Run: report}
+	if err != nil {
+		summary.ExitCode = exitCode(err)
+		summary.Error = err.Error()
+	}
+	if report != nil {
+		summary.DurationSeconds = report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond).Seconds()
+		summary.SourcesFailed = len(report.FailedSources())
+	}
+	if writeErr := writeRunSummary(summaryPath, summary); writeErr != nil {
+		if err == nil {
+			return writeErr
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", writeErr)
+	}
+	return err
+}
+
+// runOutcome names how a scrape ended
+func runOutcome(report *models.RunReport, err error) string {
+	switch {
+	case errors.Is(err, errs.ErrConfig):
+		return outcomeConfigError
+	case errors.Is(err, context.Canceled):
+		return outcomeInterrupted
+	case errors.Is(err, errs.ErrAllSourcesFailed):
+		return outcomeAllFailed
+	case err != nil || report == nil:
+		return outcomeFailed
+	case len(report.FailedSources()) > 0:
+		return outcomePartial
+	case report.NewJobs == 0:
+		return outcomeNoNewJobs
+	}
+	return outcomeOK
+}
+
+// writeRunSummary writes summary to path through a temporary file, so a
+// wrapper never reads half of one
+func writeRunSummary(path string, summary runSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to write run summary: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+	return nil
+}
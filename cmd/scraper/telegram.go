@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/notify"
+	"hire.ai/pkg/telegram"
+)
+
+// runTelegram answers Telegram bot commands about the stored jobs until
+// interrupted. The bot uses the token and chats of the telegram
+// notification settings, whether or not the notifications are enabled.
+func runTelegram(args []string) error {
+	fs := flag.NewFlagSet("telegram", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	notifications := app.config.GlobalSettings.Notifications
+	if notifications == nil || notifications.Telegram == nil {
+		return errs.WithCause(fmt.Errorf("no telegram settings; add them to globalSettings.notifications.telegram"), errs.ErrConfig)
+	}
+	config, err := notify.ResolveTelegram(*notifications.Telegram)
+	if err != nil {
+		return errs.WithCause(err, errs.ErrConfig)
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+	app.logger.Infof("Answering Telegram commands from %d chats; interrupt to stop", len(config.ChatIDs))
+	bot := telegram.NewBot(telegram.NewClient(config.Token), app.storage, config.ChatIDs, app.logger)
+	if err := bot.Run(ctx); err != nil {
+		return err
+	}
+	app.logger.Info("Telegram bot stopped")
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+func runTokens(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper tokens <create|list|revoke> [flags]")
+	}
+
+	switch args[0] {
+	case "create":
+		return runTokensCreate(args[1:])
+	case "list":
+		return runTokensList(args[1:])
+	case "revoke":
+		return runTokensRevoke(args[1:])
+	default:
+		return fmt.Errorf("unknown tokens command: %s", args[0])
+	}
+}
+
+func runTokensCreate(args []string) error {
+	fs := flag.NewFlagSet("tokens create", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	nameFlag := fs.String("name", "", "Name describing who or what uses the token")
+	scopeFlag := fs.String("scope", auth.ScopeRead, "Token scope (read, ingest, admin)")
+	userFlag := fs.String("user", "", "User the token acts for (see 'users add'); empty uses the shared default profile")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *nameFlag == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	if *userFlag != "" {
+		users, err := storage.NewUserStore(*common.data)
+		if err != nil {
+			return err
+		}
+		if _, err := users.Get(*userFlag); err != nil {
+			return err
+		}
+	}
+
+	store, err := auth.NewTokenStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	token, secret, err := store.Create(*nameFlag, *scopeFlag, *userFlag)
+	if err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			User   string `json:"user,omitempty"`
+			Scope  string `json:"scope"`
+			Secret string `json:"secret"`
+		}{token.ID, token.Name, token.User, token.Scope, secret})
+	}
+
+	if token.User != "" {
+		fmt.Printf("Created %s token %s (%s) for user %s\n\n", token.Scope, token.Name, token.ID, token.User)
+	} else {
+		fmt.Printf("Created %s token %s (%s)\n\n", token.Scope, token.Name, token.ID)
+	}
+	fmt.Printf("  %s\n\n", secret)
+	fmt.Println("Store it now; it cannot be shown again. Send it as 'Authorization: Bearer <token>'.")
+	return nil
+}
+
+func runTokensList(args []string) error {
+	fs := flag.NewFlagSet("tokens list", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	store, err := auth.NewTokenStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		type tokenInfo struct {
+			ID        string `json:"id"`
+			Name      string `json:"name"`
+			User      string `json:"user,omitempty"`
+			Scope     string `json:"scope"`
+			CreatedAt string `json:"created_at"`
+		}
+		infos := make([]tokenInfo, 0, len(tokens))
+		for _, token := range tokens {
+			infos = append(infos, tokenInfo{token.ID, token.Name, token.User, token.Scope, token.CreatedAt.Format(time.RFC3339)})
+		}
+		return export.Write(os.Stdout, *common.output, infos)
+	}
+
+	if len(tokens) == 0 {
+		fmt.Println("No tokens; the API server accepts unauthenticated requests.")
+		return nil
+	}
+
+	fmt.Printf("%-10s %-24s %-16s %-7s %s\n", "ID", "NAME", "USER", "SCOPE", "CREATED")
+	for _, token := range tokens {
+		user := token.User
+		if user == "" {
+			user = "-"
+		}
+		fmt.Printf("%-10s %-24.24s %-16.16s %-7s %s\n", token.ID, token.Name, user, token.Scope, models.InDisplayZone(token.CreatedAt).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func runTokensRevoke(args []string) error {
+	fs := flag.NewFlagSet("tokens revoke", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper tokens revoke <id|name>")
+	}
+
+	store, err := auth.NewTokenStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Revoke(fs.Arg(0)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked token %s\n", fs.Arg(0))
+	return nil
+}
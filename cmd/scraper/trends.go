@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/analytics"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+)
+
+// sparkBlocks draw the weekly counts as a line of text, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// runTrends reports how the market moves over the stored weeks: postings a
+// week by skill, company or location, how long postings stay up and how
+// often they are reposted
+func runTrends(args []string) error {
+	fs := flag.NewFlagSet("trends", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	byFlag := fs.String("by", analytics.BySkill, "Count weekly postings by this dimension ("+strings.Join(analytics.Dimensions, ", ")+")")
+	topFlag := fs.Int("top", 8, "Number of series and reposting companies to report")
+	weeksFlag := fs.Int("weeks", 12, "Number of weeks to cover, ending with the latest scrape")
+	keywordsFlag := fs.String("keywords", "", "Only count jobs matching these keywords (comma-separated)")
+	keywordQueryFlag := fs.String("keyword-query", "", "Only count jobs satisfying this boolean query, e.g. \"golang AND (kubernetes OR terraform)\"")
+	locationFlag := fs.String("location", "", "Only count jobs in this location")
+	goneAfterFlag := fs.String("gone-after", "7d", "Count a posting as taken down once unseen for this long")
+	repostAfterFlag := fs.String("repost-after", "3d", "Count a posting as reposted when its date moves this far past when it was first seen")
+	htmlFlag := fs.String("html", "", "Also write a dashboard of charts as an HTML page to this file")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *topFlag <= 0 || *weeksFlag <= 0 {
+		return fmt.Errorf("-top and -weeks must be positive")
+	}
+	goneAfter, err := parseAge(*goneAfterFlag)
+	if err != nil {
+		return err
+	}
+	repostAfter, err := parseAge(*repostAfterFlag)
+	if err != nil {
+		return err
+	}
+
+	// The application loads the display timezone the weeks start in and the
+	// company aliases
+	app, err := NewApplication(*common.config, *common.data, common.logger())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	ctx, stop := commandContext()
+	defer stop()
+	jobs, err := app.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	filter := models.JobFilter{Keywords: splitList(*keywordsFlag), KeywordQuery: *keywordQueryFlag, Location: *locationFlag}
+	if err := filter.Compile(); err != nil {
+		return fmt.Errorf("invalid -keyword-query: %w", err)
+	}
+	var matching []models.Job
+	for i := range jobs {
+		if filter.Matches(&jobs[i]) {
+			matching = append(matching, jobs[i])
+		}
+	}
+	if len(matching) == 0 {
+		return fmt.Errorf("no stored jobs to analyse; run a scrape first or widen the filters")
+	}
+
+	report, err := analytics.Trends(matching, analytics.Options{
+		By:          strings.ToLower(*byFlag),
+		Top:         *topFlag,
+		Weeks:       *weeksFlag,
+		GoneAfter:   goneAfter,
+		RepostAfter: repostAfter,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *htmlFlag != "" {
+		if err := writeTrendsHTML(report, *htmlFlag); err != nil {
+			return err
+		}
+	}
+	if common.machineReadable() {
+		if *common.output == export.FormatCSV {
+			return export.Write(os.Stdout, *common.output, report.Points())
+		}
+		return export.Write(os.Stdout, *common.output, report)
+	}
+	printTrends(report)
+	if *htmlFlag != "" {
+		fmt.Printf("\nWrote the dashboard to %s\n", *htmlFlag)
+	}
+	return nil
+}
+
+func writeTrendsHTML(report *analytics.Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := report.WriteHTML(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+func printTrends(report *analytics.Report) {
+	fmt.Printf("Postings a week by %s, %d weeks to %s\n", report.By, len(report.Weeks), report.AsOf.Format(time.DateOnly))
+	if len(report.Weeks) == 0 {
+		return
+	}
+
+	labelWidth := len("All postings")
+	for _, s := range report.Series {
+		labelWidth = max(labelWidth, len([]rune(s.Name)))
+	}
+	labelWidth = min(labelWidth, 30)
+	fmt.Printf("\n%-*s %6s  Weeks %s to %s\n", labelWidth, "", "Total", report.Weeks[0], report.Weeks[len(report.Weeks)-1])
+	total := 0
+	for _, count := range report.Postings {
+		total += count
+	}
+	fmt.Printf("%-*s %6d  %s  %s\n", labelWidth, "All postings", total, sparkline(report.Postings), joinCounts(report.Postings))
+	for _, s := range report.Series {
+		fmt.Printf("%-*s %6d  %s  %s\n", labelWidth, truncateLabel(s.Name, labelWidth), s.Total, sparkline(s.Counts), joinCounts(s.Counts))
+	}
+
+	fmt.Println("\nHow long postings stay up")
+	if report.Lifetime.Disappeared == 0 {
+		fmt.Println("  No postings have been taken down yet.")
+	} else {
+		fmt.Printf("  %d taken down after a median of %.1f days (middle half %.1f–%.1f, mean %.1f)\n",
+			report.Lifetime.Disappeared, report.Lifetime.MedianDays, report.Lifetime.P25Days, report.Lifetime.P75Days, report.Lifetime.MeanDays)
+		for _, week := range report.LifetimeByWeek {
+			if week.Disappeared > 0 {
+				fmt.Printf("  %s  %4d taken down, median %.1f days\n", week.Week, week.Disappeared, week.MedianDays)
+			}
+		}
+	}
+
+	fmt.Println("\nReposts")
+	fmt.Printf("  %d of %d postings reposted (%.0f%%)\n", report.Reposts.Reposted, report.Reposts.Postings, report.Reposts.Rate*100)
+	for _, company := range report.RepostingCompanies {
+		fmt.Printf("  %-*s %4d of %4d (%.0f%%)\n", labelWidth, truncateLabel(company.Name, labelWidth), company.Reposted, company.Postings, company.Rate*100)
+	}
+}
+
+// sparkline draws counts as one block per week, scaled to the largest
+func sparkline(counts []int) string {
+	top := 0
+	for _, count := range counts {
+		top = max(top, count)
+	}
+	line := make([]rune, len(counts))
+	for i, count := range counts {
+		if top == 0 {
+			line[i] = sparkBlocks[0]
+			continue
+		}
+		line[i] = sparkBlocks[count*(len(sparkBlocks)-1)/top]
+	}
+	return string(line)
+}
+
+func joinCounts(counts []int) string {
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprint(count)
+	}
+	return strings.Join(parts, " ")
+}
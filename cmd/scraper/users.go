@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/export"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+func runUsers(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: job-scraper users <add|list|remove> [flags]")
+	}
+
+	switch args[0] {
+	case "add":
+		return runUsersAdd(args[1:])
+	case "list":
+		return runUsersList(args[1:])
+	case "remove":
+		return runUsersRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown users command: %s", args[0])
+	}
+}
+
+func runUsersAdd(args []string) error {
+	fs := flag.NewFlagSet("users add", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	nameFlag := fs.String("name", "", "Display name; the user ID is derived from it")
+	emailFlag := fs.String("email", "", "Email address for notifications")
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+	if *nameFlag == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	store, err := storage.NewUserStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	user := models.NewUser(*nameFlag, *emailFlag)
+	user.Notifications.Enabled = user.Email != ""
+	if err := store.Create(user); err != nil {
+		return err
+	}
+
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, user)
+	}
+
+	fmt.Printf("Created user %s (%s)\n", user.Name, user.ID)
+	fmt.Printf("Give them a token with: job-scraper tokens create -name %s-laptop -user %s\n", user.ID, user.ID)
+	return nil
+}
+
+func runUsersList(args []string) error {
+	fs := flag.NewFlagSet("users list", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if err := common.validate(); err != nil {
+		return err
+	}
+
+	store, err := storage.NewUserStore(*common.data)
+	if err != nil {
+		return err
+	}
+
+	users := store.List()
+	if common.machineReadable() {
+		return export.Write(os.Stdout, *common.output, users)
+	}
+
+	if len(users) == 0 {
+		fmt.Println("No users; every token shares the default profile.")
+		return nil
+	}
+
+	fmt.Printf("%-16s %-24s %-32s %s\n", "ID", "NAME", "EMAIL", "NOTIFY")
+	for _, user := range users {
+		notify := "off"
+		if user.Notifications.Enabled {
+			notify = "on"
+		}
+		fmt.Printf("%-16.16s %-24.24s %-32.32s %s\n", user.ID, user.Name, user.Email, notify)
+	}
+	return nil
+}
+
+func runUsersRemove(args []string) error {
+	fs := flag.NewFlagSet("users remove", flag.ExitOnError)
+	common := registerCommonFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: job-scraper users remove <id>")
+	}
+	id := fs.Arg(0)
+
+	users, err := storage.NewUserStore(*common.data)
+	if err != nil {
+		return err
+	}
+	if _, err := users.Get(id); err != nil {
+		return err
+	}
+
+	// Revoke tokens first so a half-finished cleanup cannot leave the user's
+	// data reachable
+	tokens, err := auth.NewTokenStore(*common.data)
+	if err != nil {
+		return err
+	}
+	revoked, err := tokens.RevokeUser(id)
+	if err != nil {
+		return err
+	}
+
+	searches, err := storage.NewSavedSearchStore(*common.data)
+	if err != nil {
+		return err
+	}
+	deletedSearches, err := searches.DeleteUser(id)
+	if err != nil {
+		return err
+	}
+	alerts, err := storage.NewAlertStore(*common.data)
+	if err != nil {
+		return err
+	}
+	if _, err := alerts.DeleteUser(id); err != nil {
+		return err
+	}
+
+	applications, err := storage.NewApplicationStore(*common.data)
+	if err != nil {
+		return err
+	}
+	deletedApplications, err := applications.DeleteUser(id)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := storage.NewWebhookStore(*common.data)
+	if err != nil {
+		return err
+	}
+	deletedWebhooks, err := webhooks.DeleteUser(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := users.Delete(id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed user %s with %d tokens, %d saved searches, %d applications and %d webhooks\n",
+		id, revoked, deletedSearches, deletedApplications, deletedWebhooks)
+	return nil
+}
@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version requests are written for
+const anthropicVersion = "2023-06-01"
+
+// anthropicMaxTokens bounds replies; the features ask for short JSON
+const anthropicMaxTokens = 1024
+
+// anthropicProvider speaks the Anthropic Messages API. Anthropic has no
+// embeddings API.
+type anthropicProvider struct {
+	config Config
+	http   *http.Client
+}
+
+func (p *anthropicProvider) complete(ctx context.Context, system, user string) (string, Usage, error) {
+	request := struct {
+		Model       string        `json:"model"`
+		MaxTokens   int           `json:"max_tokens"`
+		System      string        `json:"system"`
+		Messages    []chatMessage `json:"messages"`
+		Temperature float64       `json:"temperature"`
+	}{
+		Model:       p.config.Model,
+		MaxTokens:   anthropicMaxTokens,
+		System:      system,
+		Messages:    []chatMessage{{Role: "user", Content: user}},
+		Temperature: 0.2,
+	}
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{
+		"x-api-key":         p.config.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+	if err := postJSON(ctx, p.http, p.config.Endpoint+"/messages", headers, request, &response); err != nil {
+		return "", Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: response.Usage.InputTokens, CompletionTokens: response.Usage.OutputTokens}
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", usage, fmt.Errorf("no completion returned")
+	}
+	return text.String(), usage, nil
+}
+
+func (p *anthropicProvider) embed(context.Context, []string) ([][]float64, Usage, error) {
+	return nil, Usage{}, ErrEmbeddingsUnsupported
+}
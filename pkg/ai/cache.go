@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cacheEntries bounds the replies kept in memory
+const cacheEntries = 512
+
+// cache keeps replies in memory and, with a directory, one file per reply on
+// disk. Disk writes are best effort; a failed write only costs a request.
+type cache struct {
+	mutex   sync.Mutex
+	dir     string
+	entries map[string][]byte
+	order   []string
+}
+
+func newCache(dir string) (*cache, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create LLM cache directory: %w", err)
+		}
+	}
+	return &cache{dir: dir, entries: make(map[string][]byte)}, nil
+}
+
+// cacheKey digests parts into a key safe to use as a file name
+func cacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// get decodes the value cached under key into v
+func (c *cache) get(key string, v interface{}) bool {
+	c.mutex.Lock()
+	data, ok := c.entries[key]
+	c.mutex.Unlock()
+
+	if !ok && c.dir != "" {
+		var err error
+		if data, err = os.ReadFile(filepath.Join(c.dir, key+".json")); err != nil {
+			return false
+		}
+		c.remember(key, data)
+	} else if !ok {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// put caches v under key
+func (c *cache) put(key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.remember(key, data)
+
+	if c.dir != "" {
+		path := filepath.Join(c.dir, key+".json")
+		if os.WriteFile(path+".tmp", data, 0644) == nil {
+			os.Rename(path+".tmp", path)
+		}
+	}
+}
+
+// remember keeps data in memory, evicting the oldest entry when full
+func (c *cache) remember(key string, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		if len(c.order) >= cacheEntries {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = data
+}
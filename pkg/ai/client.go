@@ -0,0 +1,357 @@
+// Package ai is the model client the AI features build on. It talks to
+// OpenAI-compatible servers (OpenAI, LM Studio, vLLM, llama.cpp), Ollama and
+// Anthropic, and adds retries, rate limiting, token accounting and response
+// caching on top.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"hire.ai/pkg/transport"
+)
+
+// LLMClient is what AI features need from a model
+type LLMClient interface {
+	// Complete sends one system and one user message and returns the reply
+	Complete(ctx context.Context, system, user string) (string, error)
+	// Embed returns one embedding vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+	// Model names the model replies come from
+	Model() string
+}
+
+// Config selects the provider and model. It is embedded in the config of
+// each feature that uses a model.
+type Config struct {
+	// Provider is openai (any OpenAI-compatible server), anthropic or
+	// ollama; lmstudio, vllm and llamacpp are OpenAI-compatible with their
+	// usual local address. Without an endpoint or provider, a local Ollama
+	// server is used.
+	Provider string `json:"provider,omitempty"`
+	// Endpoint is the API base URL, e.g. https://api.openai.com/v1 or
+	// http://localhost:11434/v1. An endpoint without a provider is taken
+	// to be OpenAI-compatible.
+	Endpoint string `json:"endpoint,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// APIKey falls back to ANTHROPIC_API_KEY for Anthropic, then to
+	// LLM_API_KEY; local servers usually need none
+	APIKey string `json:"apiKey,omitempty"`
+	// Timeout is per request in seconds and defaults to 60
+	Timeout int `json:"timeout,omitempty"`
+	// MaxRetries is how often a rate-limited, failed or timed-out request
+	// is retried. Defaults to 2; -1 disables retries.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// RequestsPerMinute throttles requests; 0 means no limit
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	// CacheDir, when set, keeps replies and embeddings on disk so the same
+	// prompt is never paid for twice. Replies are cached in memory either
+	// way.
+	CacheDir string `json:"cacheDir,omitempty"`
+}
+
+// providerEndpoints are the default API base URLs of known providers
+var providerEndpoints = map[string]string{
+	"openai":    "https://api.openai.com/v1",
+	"anthropic": "https://api.anthropic.com/v1",
+	"ollama":    "http://localhost:11434",
+	"lmstudio":  "http://localhost:1234/v1",
+	"vllm":      "http://localhost:8000/v1",
+	"llamacpp":  "http://localhost:8080/v1",
+}
+
+// Defaults is the shared llm section of GlobalSettings. Each feature takes
+// whatever its own section leaves out from here, so one server and key can
+// serve them all while each picks its own model.
+type Defaults struct {
+	Provider          string `json:"provider,omitempty"`
+	Endpoint          string `json:"endpoint,omitempty"`
+	APIKey            string `json:"apiKey,omitempty"`
+	Timeout           int    `json:"timeout,omitempty"`
+	MaxRetries        int    `json:"maxRetries,omitempty"`
+	RequestsPerMinute int    `json:"requestsPerMinute,omitempty"`
+	CacheDir          string `json:"cacheDir,omitempty"`
+	// Model is the chat model for summaries, salaries and classification
+	Model string `json:"model,omitempty"`
+	// EmbeddingModel is the model deduplication embeds descriptions with
+	EmbeddingModel string `json:"embeddingModel,omitempty"`
+}
+
+// Apply fills the fields config leaves empty from d; embeddings selects
+// EmbeddingModel instead of Model. A nil Defaults changes nothing.
+func (d *Defaults) Apply(config *Config, embeddings bool) {
+	if d == nil {
+		return
+	}
+	// A feature naming its own server keeps it
+	if config.Endpoint == "" && config.Provider == "" {
+		config.Provider, config.Endpoint = d.Provider, d.Endpoint
+	}
+	if config.APIKey == "" {
+		config.APIKey = d.APIKey
+	}
+	if config.Timeout == 0 {
+		config.Timeout = d.Timeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = d.MaxRetries
+	}
+	if config.RequestsPerMinute == 0 {
+		config.RequestsPerMinute = d.RequestsPerMinute
+	}
+	if config.CacheDir == "" {
+		config.CacheDir = d.CacheDir
+	}
+	if config.Model == "" {
+		config.Model = d.Model
+		if embeddings {
+			config.Model = d.EmbeddingModel
+		}
+	}
+}
+
+// provider is one model API
+type provider interface {
+	complete(ctx context.Context, system, user string) (string, Usage, error)
+	embed(ctx context.Context, texts []string) ([][]float64, Usage, error)
+}
+
+// ErrEmbeddingsUnsupported is returned by Embed for providers without an
+// embeddings API
+var ErrEmbeddingsUnsupported = errors.New("provider has no embeddings API")
+
+// StatusError is an error status from a provider
+type StatusError struct {
+	Status  string
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status + ": " + e.Message
+}
+
+// retryable reports whether a request that failed with err may succeed later
+func retryable(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code == http.StatusTooManyRequests || status.Code >= 500
+	}
+	// Connection failures and timeouts; malformed replies would fail again
+	var network net.Error
+	return errors.As(err, &network) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Client wraps a provider with retries, rate limiting, token accounting and
+// caching. It is safe for concurrent use.
+type Client struct {
+	config   Config
+	task     string
+	provider provider
+	limiter  *rate.Limiter
+	cache    *cache
+}
+
+// NewClient validates config. task names the feature, e.g. summarization,
+// in usage accounting.
+func NewClient(config Config, task string) (*Client, error) {
+	config.Provider = strings.ToLower(config.Provider)
+	switch {
+	case config.Provider == "" && config.Endpoint == "":
+		config.Provider = "ollama"
+	case config.Provider == "":
+		config.Provider = "openai"
+	}
+	if config.Endpoint == "" {
+		endpoint, ok := providerEndpoints[config.Provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown LLM provider %q (use openai, anthropic, ollama, lmstudio, vllm or llamacpp, or set an endpoint)", config.Provider)
+		}
+		config.Endpoint = endpoint
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.Model == "" {
+		return nil, fmt.Errorf("an LLM model is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 60
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 2
+	}
+
+	httpClient := transport.NewClient(time.Duration(config.Timeout) * time.Second)
+	client := &Client{config: config, task: task}
+	switch config.Provider {
+	case "anthropic":
+		if config.APIKey == "" {
+			config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+		if config.APIKey == "" {
+			config.APIKey = os.Getenv("LLM_API_KEY")
+		}
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("anthropic needs an API key")
+		}
+		client.provider = &anthropicProvider{config: config, http: httpClient}
+	case "ollama":
+		// The native API lives beside the OpenAI-compatible one under /v1
+		config.Endpoint = strings.TrimSuffix(config.Endpoint, "/v1")
+		client.provider = &ollamaProvider{config: config, http: httpClient}
+	default:
+		if config.APIKey == "" {
+			config.APIKey = os.Getenv("LLM_API_KEY")
+		}
+		client.provider = &openAIProvider{config: config, http: httpClient}
+	}
+	client.config = config
+
+	if config.RequestsPerMinute > 0 {
+		client.limiter = rate.NewLimiter(rate.Limit(float64(config.RequestsPerMinute)/60), 1)
+	}
+	var err error
+	if client.cache, err = newCache(config.CacheDir); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Model names the model replies come from
+func (c *Client) Model() string {
+	return c.config.Model
+}
+
+// Complete sends one system and one user message and returns the reply,
+// from the cache when the same prompt was answered before
+func (c *Client) Complete(ctx context.Context, system, user string) (string, error) {
+	key := cacheKey("complete", c.config.Provider, c.config.Model, system, user)
+	var reply string
+	if c.cache.get(key, &reply) {
+		record(c.task, Usage{CachedRequests: 1})
+		return reply, nil
+	}
+
+	err := c.do(ctx, func() (Usage, error) {
+		var usage Usage
+		var err error
+		reply, usage, err = c.provider.complete(ctx, system, user)
+		return usage, err
+	})
+	if err != nil {
+		return "", err
+	}
+	c.cache.put(key, reply)
+	return reply, nil
+}
+
+// Embed returns one embedding vector per text, in order. Only texts not
+// embedded before are sent.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	keys := make([]string, len(texts))
+	var missing []int
+	for i, text := range texts {
+		keys[i] = cacheKey("embed", c.config.Provider, c.config.Model, text)
+		if !c.cache.get(keys[i], &vectors[i]) {
+			missing = append(missing, i)
+		}
+	}
+	if cached := len(texts) - len(missing); cached > 0 {
+		record(c.task, Usage{CachedRequests: cached})
+	}
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	batch := make([]string, len(missing))
+	for j, i := range missing {
+		batch[j] = texts[i]
+	}
+	var embedded [][]float64
+	err := c.do(ctx, func() (Usage, error) {
+		var usage Usage
+		var err error
+		embedded, usage, err = c.provider.embed(ctx, batch)
+		return usage, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(batch) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(embedded), len(batch))
+	}
+	for j, i := range missing {
+		vectors[i] = embedded[j]
+		c.cache.put(keys[i], embedded[j])
+	}
+	return vectors, nil
+}
+
+// do runs request under the rate limit, retrying with backoff, and records
+// its usage
+func (c *Client) do(ctx context.Context, request func() (Usage, error)) error {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		usage, err := request()
+		if errors.Is(err, ErrEmbeddingsUnsupported) {
+			return err
+		}
+		usage.Requests = 1
+		if err != nil {
+			usage.Failures = 1
+		}
+		record(c.task, usage)
+		if err == nil {
+			return nil
+		}
+		if attempt >= c.config.MaxRetries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// DecodeJSON reads the JSON object in a reply into v, tolerating the code
+// fences and preambles some models add
+func DecodeJSON(reply string, v interface{}) error {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start == -1 || end < start {
+		return fmt.Errorf("reply has no JSON object: %.200s", reply)
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), v); err != nil {
+		return fmt.Errorf("invalid JSON in reply: %w", err)
+	}
+	return nil
+}
+
+// Truncate shortens text to limit runes to keep prompts within small
+// models' context
+func Truncate(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return text
+}
@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ollamaProvider speaks Ollama's native API, which reports token counts the
+// OpenAI-compatible one leaves out for embeddings
+type ollamaProvider struct {
+	config Config
+	http   *http.Client
+}
+
+func (p *ollamaProvider) complete(ctx context.Context, system, user string) (string, Usage, error) {
+	request := struct {
+		Model    string             `json:"model"`
+		Messages []chatMessage      `json:"messages"`
+		Stream   bool               `json:"stream"`
+		Options  map[string]float64 `json:"options"`
+	}{
+		Model:    p.config.Model,
+		Messages: []chatMessage{{Role: "system", Content: system}, {Role: "user", Content: user}},
+		Options:  map[string]float64{"temperature": 0.2},
+	}
+	var response struct {
+		Message         chatMessage `json:"message"`
+		PromptEvalCount int         `json:"prompt_eval_count"`
+		EvalCount       int         `json:"eval_count"`
+	}
+	if err := postJSON(ctx, p.http, p.config.Endpoint+"/api/chat", nil, request, &response); err != nil {
+		return "", Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: response.PromptEvalCount, CompletionTokens: response.EvalCount}
+	if response.Message.Content == "" {
+		return "", usage, fmt.Errorf("no completion returned")
+	}
+	return response.Message.Content, usage, nil
+}
+
+func (p *ollamaProvider) embed(ctx context.Context, texts []string) ([][]float64, Usage, error) {
+	request := struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}{Model: p.config.Model, Input: texts}
+	var response struct {
+		Embeddings      [][]float64 `json:"embeddings"`
+		PromptEvalCount int         `json:"prompt_eval_count"`
+	}
+	if err := postJSON(ctx, p.http, p.config.Endpoint+"/api/embed", nil, request, &response); err != nil {
+		return nil, Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: response.PromptEvalCount}
+	if len(response.Embeddings) != len(texts) {
+		return nil, usage, fmt.Errorf("got %d embeddings for %d texts", len(response.Embeddings), len(texts))
+	}
+	return response.Embeddings, usage, nil
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// openAIProvider speaks the OpenAI chat completions and embeddings API,
+// which LM Studio, vLLM and llama.cpp servers also serve
+type openAIProvider struct {
+	config Config
+	http   *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func (p *openAIProvider) complete(ctx context.Context, system, user string) (string, Usage, error) {
+	request := struct {
+		Model       string        `json:"model"`
+		Messages    []chatMessage `json:"messages"`
+		Temperature float64       `json:"temperature"`
+	}{
+		Model:       p.config.Model,
+		Messages:    []chatMessage{{Role: "system", Content: system}, {Role: "user", Content: user}},
+		Temperature: 0.2,
+	}
+	var response struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+		Usage openAIUsage `json:"usage"`
+	}
+	if err := postJSON(ctx, p.http, p.config.Endpoint+"/chat/completions", p.headers(), request, &response); err != nil {
+		return "", Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: response.Usage.PromptTokens, CompletionTokens: response.Usage.CompletionTokens}
+	if len(response.Choices) == 0 {
+		return "", usage, fmt.Errorf("no completion returned")
+	}
+	return response.Choices[0].Message.Content, usage, nil
+}
+
+func (p *openAIProvider) embed(ctx context.Context, texts []string) ([][]float64, Usage, error) {
+	request := struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}{Model: p.config.Model, Input: texts}
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Usage openAIUsage `json:"usage"`
+	}
+	if err := postJSON(ctx, p.http, p.config.Endpoint+"/embeddings", p.headers(), request, &response); err != nil {
+		return nil, Usage{}, err
+	}
+
+	usage := Usage{PromptTokens: response.Usage.PromptTokens}
+	if len(response.Data) != len(texts) {
+		return nil, usage, fmt.Errorf("got %d embeddings for %d texts", len(response.Data), len(texts))
+	}
+	vectors := make([][]float64, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(texts) || len(item.Embedding) == 0 {
+			return nil, usage, fmt.Errorf("invalid embedding at index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, usage, nil
+}
+
+func (p *openAIProvider) headers() map[string]string {
+	if p.config.APIKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.config.APIKey}
+}
+
+// postJSON sends body as JSON and decodes the reply into out. Error statuses
+// become a *StatusError carrying the provider's message.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Embeddings of a full batch run to a few megabytes
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Status: resp.Status, Code: resp.StatusCode, Message: errorMessage(reply)}
+	}
+	if err := json.Unmarshal(reply, out); err != nil {
+		return fmt.Errorf("%s: unexpected response: %.200s", resp.Status, reply)
+	}
+	return nil
+}
+
+// errorMessage finds the message in an error body. OpenAI and Anthropic
+// nest it in an object; Ollama sends a plain string.
+func errorMessage(body []byte) string {
+	var failure struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &failure) == nil && len(failure.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(failure.Error, &detail) == nil && detail.Message != "" {
+			return detail.Message
+		}
+		var message string
+		if json.Unmarshal(failure.Error, &message) == nil && message != "" {
+			return message
+		}
+	}
+	return fmt.Sprintf("%.200s", strings.TrimSpace(string(body)))
+}
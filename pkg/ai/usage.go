@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"sort"
+	"sync"
+)
+
+// Usage counts requests and tokens
+type Usage struct {
+	Requests int `json:"requests"`
+	// Failures are requests that returned an error, retries included
+	Failures int `json:"failures"`
+	// CachedRequests were answered from the cache without a request
+	CachedRequests   int `json:"cached_requests"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// add sums other into u
+func (u *Usage) add(other Usage) {
+	u.Requests += other.Requests
+	u.Failures += other.Failures
+	u.CachedRequests += other.CachedRequests
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+}
+
+var (
+	usageMutex sync.Mutex
+	usageTotal = make(map[string]*Usage)
+)
+
+func record(task string, usage Usage) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	total, ok := usageTotal[task]
+	if !ok {
+		total = &Usage{}
+		usageTotal[task] = total
+	}
+	total.add(usage)
+}
+
+// UsageByTask returns the usage of every task since the process started
+func UsageByTask() map[string]Usage {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	usage := make(map[string]Usage, len(usageTotal))
+	for task, total := range usageTotal {
+		usage[task] = *total
+	}
+	return usage
+}
+
+// Tasks lists the tasks with recorded usage, sorted
+func Tasks() []string {
+	usage := UsageByTask()
+	tasks := make([]string, 0, len(usage))
+	for task := range usage {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	return tasks
+}
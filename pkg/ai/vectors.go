@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Normalize scales an embedding to unit length and float32 precision, which
+// is all cosine similarity needs
+func Normalize(vector []float64) []float32 {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	unit := make([]float32, len(vector))
+	if norm == 0 {
+		return unit
+	}
+	for i, v := range vector {
+		unit[i] = float32(v / norm)
+	}
+	return unit
+}
+
+// Dot is the cosine similarity of two unit vectors; vectors from different
+// models never match
+func Dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// EncodeVector stores a vector as base64 of little-endian float32s, a
+// quarter the size of JSON numbers
+func EncodeVector(vector []float32) string {
+	raw := make([]byte, len(vector)*4)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(raw[i*4:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// DecodeVector reverses EncodeVector
+func DecodeVector(value string) ([]float32, error) {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%4 != 0 {
+		return nil, errors.New("vector length is not a multiple of 4 bytes")
+	}
+	vector := make([]float32, len(raw)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	return vector, nil
+}
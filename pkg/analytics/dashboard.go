@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+//go:embed dashboard.html
+var dashboardTemplate string
+
+// Chart dimensions in SVG units; the plot leaves room for the axis labels
+const (
+	chartWidth  = 640.0
+	chartHeight = 200.0
+	plotLeft    = 40.0
+	plotBottom  = 180.0
+	plotTop     = 10.0
+)
+
+// seriesColors are cycled through by the lines of a chart
+var seriesColors = []string{"#1a5fb4", "#e66100", "#2b7a0b", "#c01c28", "#813d9c", "#1c71d8", "#986a44", "#5e5c64"}
+
+type line struct {
+	Name   string
+	Color  string
+	Points string
+	Total  int
+}
+
+type bar struct {
+	X, Y, Width, Height float64
+	Label               string
+}
+
+type axisLabel struct {
+	X, Y float64
+	Text string
+}
+
+// chart is a line or bar chart laid out in SVG units
+type chart struct {
+	Title  string
+	Lines  []line
+	Bars   []bar
+	XTicks []axisLabel
+	YTicks []axisLabel
+}
+
+// WriteHTML renders the report as a dashboard page of charts
+func (r *Report) WriteHTML(w io.Writer) error {
+	tmpl, err := template.New("dashboard").Parse(dashboardTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse dashboard template: %w", err)
+	}
+
+	lines := []line{{Name: "All postings", Color: "#222", Points: r.polyline(intValues(r.Postings), maxInt(r.Postings)), Total: sum(r.Postings)}}
+	top := maxInt(r.Postings)
+	for i, s := range r.Series {
+		lines = append(lines, line{Name: s.Name, Color: seriesColors[i%len(seriesColors)], Points: r.polyline(intValues(s.Counts), top), Total: s.Total})
+	}
+	postings := chart{Title: "Postings a week by " + r.By, Lines: lines, XTicks: r.xTicks(), YTicks: yTicks(float64(top), "%.0f")}
+
+	var rates, days []float64
+	for i := range r.Weeks {
+		rates = append(rates, r.RepostsByWeek[i].Rate*100)
+		days = append(days, r.LifetimeByWeek[i].MedianDays)
+	}
+	repostChart := chart{Title: "Reposted postings, % of the week's", Bars: r.bars(rates, "%.0f%%"), XTicks: r.xTicks(), YTicks: yTicks(maxFloat(rates), "%.0f%%")}
+	lifetimeChart := chart{Title: "Median days up of postings taken down, by week first seen", Bars: r.bars(days, "%.1f days"), XTicks: r.xTicks(), YTicks: yTicks(maxFloat(days), "%.0f")}
+
+	return tmpl.Execute(w, struct {
+		*Report
+		Charts        []chart
+		Width, Height float64
+		AsOfDate      string
+	}{r, []chart{postings, repostChart, lifetimeChart}, chartWidth, chartHeight, r.AsOf.Format("2006-01-02")})
+}
+
+// x places week i across the plot
+func (r *Report) x(i int) float64 {
+	if len(r.Weeks) < 2 {
+		return plotLeft
+	}
+	return plotLeft + float64(i)*(chartWidth-plotLeft-10)/float64(len(r.Weeks)-1)
+}
+
+// y places value on the plot scaled to top
+func y(value, top float64) float64 {
+	if top <= 0 {
+		return plotBottom
+	}
+	return plotBottom - value/top*(plotBottom-plotTop)
+}
+
+func (r *Report) polyline(values []float64, top int) string {
+	points := make([]string, len(values))
+	for i, value := range values {
+		points[i] = fmt.Sprintf("%.1f,%.1f", r.x(i), y(value, float64(top)))
+	}
+	return strings.Join(points, " ")
+}
+
+func (r *Report) bars(values []float64, format string) []bar {
+	top := maxFloat(values)
+	width := (chartWidth - plotLeft - 10) / float64(max(len(values), 1)) * 0.7
+	bars := make([]bar, len(values))
+	for i, value := range values {
+		bars[i] = bar{
+			X:      r.x(i) - width/2,
+			Y:      y(value, top),
+			Width:  width,
+			Height: plotBottom - y(value, top),
+			Label:  r.Weeks[i] + ": " + fmt.Sprintf(format, value),
+		}
+	}
+	return bars
+}
+
+// xTicks label every few weeks so the dates do not overlap
+func (r *Report) xTicks() []axisLabel {
+	step := max(1, len(r.Weeks)/6)
+	var ticks []axisLabel
+	for i := 0; i < len(r.Weeks); i += step {
+		ticks = append(ticks, axisLabel{X: r.x(i), Y: chartHeight - 4, Text: r.Weeks[i][5:]})
+	}
+	return ticks
+}
+
+func yTicks(top float64, format string) []axisLabel {
+	if top <= 0 {
+		return nil
+	}
+	var ticks []axisLabel
+	for _, share := range []float64{0, 0.5, 1} {
+		ticks = append(ticks, axisLabel{X: plotLeft - 6, Y: y(top*share, top) + 4, Text: fmt.Sprintf(format, top*share)})
+	}
+	return ticks
+}
+
+func intValues(counts []int) []float64 {
+	values := make([]float64, len(counts))
+	for i, count := range counts {
+		values[i] = float64(count)
+	}
+	return values
+}
+
+func maxInt(values []int) int {
+	top := 0
+	for _, value := range values {
+		top = max(top, value)
+	}
+	return top
+}
+
+func maxFloat(values []float64) float64 {
+	top := 0.0
+	for _, value := range values {
+		top = max(top, value)
+	}
+	return top
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
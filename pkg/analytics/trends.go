@@ -0,0 +1,347 @@
+// Package analytics turns the stored jobs into market trends over time:
+// postings a week by skill, company or location, how long postings stay up
+// and how often they are reposted
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// Dimensions the weekly posting counts can be split by
+const (
+	BySkill    = "skill"
+	ByCompany  = "company"
+	ByLocation = "location"
+	ByCategory = "category"
+)
+
+// Dimensions lists the dimensions postings can be counted by
+var Dimensions = []string{BySkill, ByCompany, ByLocation, ByCategory}
+
+// weekFormat names a week by the date its Monday falls on
+const weekFormat = "2006-01-02"
+
+// Options choose what Trends reports
+type Options struct {
+	// By splits the weekly posting counts; defaults to skill
+	By string
+	// Top is the number of series reported, the largest; defaults to 8
+	Top int
+	// Weeks is the number of weeks covered, ending with the latest;
+	// defaults to 12
+	Weeks int
+	// GoneAfter is how long a posting must go unseen to count as taken
+	// down; defaults to 7 days
+	GoneAfter time.Duration
+	// RepostAfter is how much later than first seen a posting's date must
+	// move to count as a repost; defaults to 3 days
+	RepostAfter time.Duration
+}
+
+// Series is the number of postings first seen each week with one value of
+// the dimension
+type Series struct {
+	Name   string `json:"name"`
+	Total  int    `json:"total"`
+	Counts []int  `json:"counts"`
+}
+
+// Lifetime is how many days postings stayed up, from posted to last seen,
+// among those taken down
+type Lifetime struct {
+	Week        string  `json:"week,omitempty"`
+	Disappeared int     `json:"disappeared"`
+	P25Days     float64 `json:"p25_days"`
+	MedianDays  float64 `json:"median_days"`
+	P75Days     float64 `json:"p75_days"`
+	MeanDays    float64 `json:"mean_days"`
+}
+
+// Reposts counts the postings whose date was refreshed after they were
+// first seen, which boards do to bump a job that has not been filled
+type Reposts struct {
+	Week     string  `json:"week,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Postings int     `json:"postings"`
+	Reposted int     `json:"reposted"`
+	Rate     float64 `json:"rate"`
+}
+
+// Report is the trends over the weeks covered
+type Report struct {
+	By string `json:"by"`
+	// Weeks name the weeks covered, oldest first, by their Monday in the
+	// display timezone
+	Weeks []string `json:"weeks"`
+	// Postings counts all postings first seen each week
+	Postings []int    `json:"postings"`
+	Series   []Series `json:"series"`
+	// Lifetime covers every posting taken down; LifetimeByWeek splits
+	// them by the week they were first seen
+	Lifetime       Lifetime   `json:"lifetime"`
+	LifetimeByWeek []Lifetime `json:"lifetime_by_week"`
+	Reposts        Reposts    `json:"reposts"`
+	RepostsByWeek  []Reposts  `json:"reposts_by_week"`
+	// RepostingCompanies repost the most, most reposts first
+	RepostingCompanies []Reposts `json:"reposting_companies"`
+	// AsOf is the latest time a job was seen, which "taken down" is
+	// measured from so a pause in scraping does not take everything down
+	AsOf        time.Time `json:"as_of"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Point is one value of a report, flattened for CSV
+type Point struct {
+	Metric string  `json:"metric"`
+	Week   string  `json:"week"`
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+}
+
+// Trends computes the report over jobs
+func Trends(jobs []models.Job, options Options) (*Report, error) {
+	if options.By == "" {
+		options.By = BySkill
+	}
+	if !containsDimension(options.By) {
+		return nil, fmt.Errorf("unknown dimension %q (use %s)", options.By, strings.Join(Dimensions, ", "))
+	}
+	if options.Top <= 0 {
+		options.Top = 8
+	}
+	if options.Weeks <= 0 {
+		options.Weeks = 12
+	}
+	if options.GoneAfter <= 0 {
+		options.GoneAfter = 7 * 24 * time.Hour
+	}
+	if options.RepostAfter <= 0 {
+		options.RepostAfter = 3 * 24 * time.Hour
+	}
+
+	report := &Report{By: options.By, GeneratedAt: time.Now().UTC()}
+	for i := range jobs {
+		if seen := lastSeen(&jobs[i]); seen.After(report.AsOf) {
+			report.AsOf = seen
+		}
+	}
+	if report.AsOf.IsZero() {
+		return report, nil
+	}
+
+	// The weeks end with the one the latest job was seen in
+	last := weekStart(report.AsOf)
+	first := last.AddDate(0, 0, -7*(options.Weeks-1))
+	index := make(map[string]int, options.Weeks)
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		index[week.Format(weekFormat)] = len(report.Weeks)
+		report.Weeks = append(report.Weeks, week.Format(weekFormat))
+	}
+	report.Postings = make([]int, len(report.Weeks))
+
+	series := make(map[string]*Series)
+	lifetimes := make([][]float64, len(report.Weeks))
+	var allLifetimes []float64
+	reposts := make([]Reposts, len(report.Weeks))
+	companies := make(map[string]*Reposts)
+	cutoff := report.AsOf.Add(-options.GoneAfter)
+	for i := range jobs {
+		job := &jobs[i]
+		week, ok := index[weekStart(job.ScrapedAt).Format(weekFormat)]
+		if !ok {
+			continue
+		}
+		report.Postings[week]++
+
+		for _, name := range dimensionValues(job, options.By) {
+			if series[name] == nil {
+				series[name] = &Series{Name: name, Counts: make([]int, len(report.Weeks))}
+			}
+			series[name].Total++
+			series[name].Counts[week]++
+		}
+
+		if seen := lastSeen(job); seen.Before(cutoff) {
+			days := seen.Sub(job.PostedTime()).Hours() / 24
+			lifetimes[week] = append(lifetimes[week], math.Max(days, 0))
+			allLifetimes = append(allLifetimes, math.Max(days, 0))
+		}
+
+		company := models.CompanyName(job.Company)
+		if company != "" && companies[company] == nil {
+			companies[company] = &Reposts{Name: company}
+		}
+		reposted := reposted(job, options.RepostAfter)
+		for _, counts := range []*Reposts{&reposts[week], &report.Reposts, companies[company]} {
+			if counts == nil {
+				continue
+			}
+			counts.Postings++
+			if reposted {
+				counts.Reposted++
+			}
+		}
+	}
+
+	for _, s := range series {
+		report.Series = append(report.Series, *s)
+	}
+	sort.Slice(report.Series, func(i, j int) bool {
+		if report.Series[i].Total != report.Series[j].Total {
+			return report.Series[i].Total > report.Series[j].Total
+		}
+		return report.Series[i].Name < report.Series[j].Name
+	})
+	if len(report.Series) > options.Top {
+		report.Series = report.Series[:options.Top]
+	}
+
+	report.Lifetime = newLifetime("", allLifetimes)
+	report.Reposts.Rate = rate(report.Reposts.Reposted, report.Reposts.Postings)
+	for i, week := range report.Weeks {
+		report.LifetimeByWeek = append(report.LifetimeByWeek, newLifetime(week, lifetimes[i]))
+		reposts[i].Week = week
+		reposts[i].Rate = rate(reposts[i].Reposted, reposts[i].Postings)
+		report.RepostsByWeek = append(report.RepostsByWeek, reposts[i])
+	}
+	for _, company := range companies {
+		if company.Reposted > 0 {
+			company.Rate = rate(company.Reposted, company.Postings)
+			report.RepostingCompanies = append(report.RepostingCompanies, *company)
+		}
+	}
+	sort.Slice(report.RepostingCompanies, func(i, j int) bool {
+		a, b := report.RepostingCompanies[i], report.RepostingCompanies[j]
+		if a.Reposted != b.Reposted {
+			return a.Reposted > b.Reposted
+		}
+		return a.Name < b.Name
+	})
+	if len(report.RepostingCompanies) > options.Top {
+		report.RepostingCompanies = report.RepostingCompanies[:options.Top]
+	}
+	return report, nil
+}
+
+// Points flattens the report into one row per value
+func (r *Report) Points() []Point {
+	var points []Point
+	for i, week := range r.Weeks {
+		points = append(points, Point{Metric: "postings", Week: week, Value: float64(r.Postings[i])})
+		for _, s := range r.Series {
+			points = append(points, Point{Metric: "postings_by_" + r.By, Week: week, Name: s.Name, Value: float64(s.Counts[i])})
+		}
+		if lifetime := r.LifetimeByWeek[i]; lifetime.Disappeared > 0 {
+			points = append(points,
+				Point{Metric: "disappeared", Week: week, Value: float64(lifetime.Disappeared)},
+				Point{Metric: "median_days_up", Week: week, Value: lifetime.MedianDays})
+		}
+		points = append(points, Point{Metric: "repost_rate", Week: week, Value: r.RepostsByWeek[i].Rate})
+	}
+	for _, company := range r.RepostingCompanies {
+		points = append(points, Point{Metric: "company_reposts", Name: company.Name, Value: float64(company.Reposted)})
+	}
+	return points
+}
+
+// weekStart returns the Monday of t's week in the display timezone
+func weekStart(t time.Time) time.Time {
+	t = models.InDisplayZone(t)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// lastSeen is when a scrape last found the job; storage refreshes
+// UpdatedAt each time it does
+func lastSeen(job *models.Job) time.Time {
+	if job.UpdatedAt.After(job.ScrapedAt) {
+		return job.UpdatedAt
+	}
+	return job.ScrapedAt
+}
+
+// reposted reports whether the job's posted date moved past when it was
+// first seen, as it does when a board bumps an old posting
+func reposted(job *models.Job, after time.Duration) bool {
+	return job.PostedAt != nil && job.PostedAt.Sub(job.ScrapedAt) > after
+}
+
+func dimensionValues(job *models.Job, dimension string) []string {
+	switch dimension {
+	case BySkill:
+		seen := make(map[string]bool, len(job.Keywords))
+		var skills []string
+		for _, keyword := range job.Keywords {
+			if keyword = strings.ToLower(keyword); !seen[keyword] {
+				seen[keyword] = true
+				skills = append(skills, keyword)
+			}
+		}
+		return skills
+	case ByCompany:
+		if company := models.CompanyName(job.Company); company != "" {
+			return []string{company}
+		}
+	case ByLocation:
+		if location := strings.TrimSpace(job.Location); location != "" {
+			return []string{location}
+		}
+	case ByCategory:
+		if job.Classification != nil && job.Classification.Category != "" {
+			return []string{job.Classification.Category}
+		}
+	}
+	return nil
+}
+
+func newLifetime(week string, days []float64) Lifetime {
+	lifetime := Lifetime{Week: week, Disappeared: len(days)}
+	if len(days) == 0 {
+		return lifetime
+	}
+	sort.Float64s(days)
+	sum := 0.0
+	for _, d := range days {
+		sum += d
+	}
+	lifetime.P25Days = round(percentile(days, 0.25))
+	lifetime.MedianDays = round(percentile(days, 0.5))
+	lifetime.P75Days = round(percentile(days, 0.75))
+	lifetime.MeanDays = round(sum / float64(len(days)))
+	return lifetime
+}
+
+// percentile interpolates the p-th quantile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+func rate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round(float64(part) / float64(total))
+}
+
+// round keeps two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func containsDimension(dimension string) bool {
+	for _, known := range Dimensions {
+		if dimension == known {
+			return true
+		}
+	}
+	return false
+}
@@ -28,9 +28,11 @@ func (a *ProviderAdapter) Search(ctx context.Context, query SearchQuery) (*Searc
 		Keywords:   query.Keywords,
 		Location:   query.Location,
 		Remote:     query.Remote,
+		Experience: query.Experience,
 		JobType:    query.JobType,
 		Company:    query.Company,
 		DatePosted: query.DatePosted,
+		RadiusKm:   query.RadiusKm,
 		Limit:      query.Limit,
 		Offset:     query.Offset,
 	}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/providers"
+)
+
+// Circuit breaker defaults, for when GlobalSettings.ProviderBreaker leaves
+// them unset
+const (
+	defaultFailureThreshold = 5
+	defaultOpenPeriod       = 5 * time.Minute
+)
+
+// Circuit breaker states
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed = "closed"
+	// CircuitOpen turns requests away until the open period ends
+	CircuitOpen = "open"
+	// CircuitHalfOpen lets one probe through; its outcome closes the
+	// circuit or opens it again
+	CircuitHalfOpen = "half-open"
+)
+
+// BreakerConfig sets when a provider failing with server errors or
+// timeouts is rested instead of being called on every search
+type BreakerConfig struct {
+	// FailureThreshold is the number of failures in a row that opens a
+	// provider's circuit. Defaults to 5.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// OpenSeconds is how long an open circuit turns searches away before
+	// one probe is let through. Defaults to 300.
+	OpenSeconds int `json:"openSeconds,omitempty"`
+}
+
+// circuitBreaker tracks one provider's run of failures
+type circuitBreaker struct {
+	state     string
+	failures  int
+	openUntil time.Time
+	// probing is set while the one half-open request is out
+	probing bool
+}
+
+// SetBreaker configures the providers' circuit breakers; nil restores the
+// defaults
+func (m *APIManager) SetBreaker(config *BreakerConfig) {
+	threshold, period := defaultFailureThreshold, defaultOpenPeriod
+	if config != nil {
+		if config.FailureThreshold > 0 {
+			threshold = config.FailureThreshold
+		}
+		if config.OpenSeconds > 0 {
+			period = time.Duration(config.OpenSeconds) * time.Second
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.failureThreshold, m.openPeriod = threshold, period
+}
+
+// allow reports whether a request to the named provider may go out,
+// moving an open circuit whose period has ended to half-open and
+// returning an error matching errs.ErrCircuitOpen otherwise
+func (m *APIManager) allow(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	breaker := m.breaker(name)
+	switch breaker.state {
+	case CircuitOpen:
+		if time.Now().Before(breaker.openUntil) {
+			return fmt.Errorf("%w until %s", errs.ErrCircuitOpen, breaker.openUntil.Format(time.RFC3339))
+		}
+		breaker.state = CircuitHalfOpen
+		fallthrough
+	case CircuitHalfOpen:
+		if breaker.probing {
+			return fmt.Errorf("%w: a probe request is in flight", errs.ErrCircuitOpen)
+		}
+		breaker.probing = true
+	}
+	return nil
+}
+
+// record updates the named provider's circuit with a request's outcome.
+// Only server errors and timeouts count as failures; a request the caller
+// cancelled says nothing about the provider and leaves the circuit as it
+// was, and any other outcome closes it.
+func (m *APIManager) record(ctx context.Context, name string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	breaker := m.breaker(name)
+	breaker.probing = false
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	if !providerFault(ctx, err) {
+		if breaker.state != CircuitClosed {
+			m.logger.Infof("Circuit for API provider %s closed", name)
+		}
+		breaker.state, breaker.failures = CircuitClosed, 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.state == CircuitHalfOpen || breaker.failures >= m.failureThreshold {
+		breaker.state = CircuitOpen
+		breaker.openUntil = time.Now().Add(m.openPeriod)
+		m.logger.Warnf("Circuit for API provider %s opened for %s after %d failures in a row",
+			name, m.openPeriod, breaker.failures)
+	}
+}
+
+// release gives back a half-open probe that never reached the provider
+func (m *APIManager) release(name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.breaker(name).probing = false
+}
+
+// breaker returns the named provider's circuit breaker; callers hold mutex
+func (m *APIManager) breaker(name string) *circuitBreaker {
+	breaker, ok := m.breakers[name]
+	if !ok {
+		breaker = &circuitBreaker{state: CircuitClosed}
+		m.breakers[name] = breaker
+	}
+	return breaker
+}
+
+// providerFault reports whether err is the provider's fault: a 5xx
+// response, or a request that timed out while the caller was still waiting
+func providerFault(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var apiErr *providers.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
 )
 
 type APIClient struct {
@@ -89,11 +90,9 @@ type USAJob struct {
 // NewAPIClient creates a new API client with the specified user agent and API keys
 func NewAPIClient(userAgent string, apiKeys map[string]string) *APIClient {
 	return &APIClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: userAgent,
-		apiKeys:   apiKeys,
+		httpClient: transport.NewClient(30 * time.Second),
+		userAgent:  userAgent,
+		apiKeys:    apiKeys,
 	}
 }
 
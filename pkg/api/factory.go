@@ -7,6 +7,9 @@ import (
 	"hire.ai/pkg/providers"
 )
 
+// SupportedProviders lists the provider types CreateProvider understands
+var SupportedProviders = []string{"usajobs", "reed", "jsearch", "arbeitnow", "jooble", "themuse"}
+
 // ProviderFactory creates API providers based on configuration
 type ProviderFactory struct{}
 
@@ -47,6 +50,12 @@ func (f *ProviderFactory) CreateProvider(config APIConfig) (JobAPIProvider, erro
 		provider = providers.NewReedProvider(providerConfig, timeout)
 	case "jsearch":
 		provider = providers.NewJSearchProvider(providerConfig, timeout)
+	case "arbeitnow":
+		provider = providers.NewArbeitnowProvider(providerConfig, timeout)
+	case "jooble":
+		provider = providers.NewJoobleProvider(providerConfig, timeout)
+	case "themuse":
+		provider = providers.NewTheMuseProvider(providerConfig, timeout)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", config.Provider)
 	}
@@ -57,8 +66,34 @@ func (f *ProviderFactory) CreateProvider(config APIConfig) (JobAPIProvider, erro
 
 // RegisterProviders registers all configured providers with the API manager
 func RegisterProviders(manager *APIManager, configs []APIConfig) error {
+	providers, err := createEnabledProviders(configs)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range providers {
+		if err := manager.RegisterProvider(provider); err != nil {
+			return fmt.Errorf("failed to register provider %s: %w", provider.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// ReloadProviders replaces the manager's providers with the enabled ones in
+// configs. Nothing changes unless every provider can be created.
+func ReloadProviders(manager *APIManager, configs []APIConfig) error {
+	providers, err := createEnabledProviders(configs)
+	if err != nil {
+		return err
+	}
+	return manager.ReplaceProviders(providers)
+}
+
+func createEnabledProviders(configs []APIConfig) ([]JobAPIProvider, error) {
 	factory := NewProviderFactory()
 
+	var providers []JobAPIProvider
 	for _, config := range configs {
 		if !config.Enabled {
 			continue
@@ -66,13 +101,10 @@ func RegisterProviders(manager *APIManager, configs []APIConfig) error {
 
 		provider, err := factory.CreateProvider(config)
 		if err != nil {
-			return fmt.Errorf("failed to create provider %s: %w", config.Name, err)
-		}
-
-		if err := manager.RegisterProvider(provider); err != nil {
-			return fmt.Errorf("failed to register provider %s: %w", config.Name, err)
+			return nil, fmt.Errorf("failed to create provider %s: %w", config.Name, err)
 		}
+		providers = append(providers, provider)
 	}
 
-	return nil
+	return providers, nil
 }
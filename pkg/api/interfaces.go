@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"hire.ai/pkg/errs"
 	"hire.ai/pkg/models"
 )
 
@@ -37,6 +38,10 @@ type SearchQuery struct {
 	DatePosted string   `json:"date_posted,omitempty"` // 1d, 3d, 7d, 14d, 30d
 	Limit      int      `json:"limit"`
 	Offset     int      `json:"offset"`
+
+	// RadiusKm asks providers that support it for jobs within this
+	// distance of Location
+	RadiusKm float64 `json:"radius_km,omitempty"`
 }
 
 // Salary represents salary range for job search
@@ -72,13 +77,16 @@ type RateLimitConfig struct {
 	RequestsPerHour   int    `json:"requests_per_hour"`
 	RequestsPerDay    int    `json:"requests_per_day"`
 	CooldownPeriod    string `json:"cooldown_period"` // Duration string like "2s"
+	// RequestsPerMonth is the plan's monthly quota, shown against usage by
+	// the stats api command rather than enforced
+	RequestsPerMonth int `json:"requests_per_month,omitempty"`
 }
 
 // APIConfig represents configuration for an API provider
 type APIConfig struct {
 	Name        string            `json:"name"`
 	Enabled     bool              `json:"enabled"`
-	Provider    string            `json:"provider"` // usajobs, reed, jsearch, etc.
+	Provider    string            `json:"provider"` // usajobs, reed, jsearch, arbeitnow, jooble, themuse
 	BaseURL     string            `json:"base_url"`
 	APIKey      string            `json:"api_key"`
 	SecretKey   string            `json:"secret_key,omitempty"`
@@ -98,6 +106,29 @@ type RetryConfig struct {
 	Multiplier  float64 `json:"multiplier"`
 }
 
+// ValidationStatus is the outcome of checking one provider's credentials
+type ValidationStatus string
+
+const (
+	ValidationValid         ValidationStatus = "valid"
+	ValidationNotConfigured ValidationStatus = "not_configured"
+	// ValidationInvalid means the provider rejected the credentials
+	ValidationInvalid ValidationStatus = "invalid"
+	// ValidationTimeout means the provider did not answer in time
+	ValidationTimeout ValidationStatus = "timeout"
+	// ValidationFailed means the check failed for another reason, such as
+	// a rate limit or a server error, saying nothing about the credentials
+	ValidationFailed ValidationStatus = "failed"
+)
+
+// ProviderValidation is the result of validating one provider's credentials
+type ProviderValidation struct {
+	Provider string           `json:"provider"`
+	Status   ValidationStatus `json:"status"`
+	Err      error            `json:"-"`
+	Latency  time.Duration    `json:"latency"`
+}
+
 // APIError represents an error from an API provider
 type APIError struct {
 	Provider   string `json:"provider"`
@@ -111,6 +142,13 @@ func (e *APIError) Error() string {
 	return e.Message
 }
 
+// Is matches the cause the response status signals, such as errs.ErrAuth
+// for a 401
+func (e *APIError) Is(target error) bool {
+	cause := errs.APIStatus(e.StatusCode)
+	return cause != nil && target == cause
+}
+
 // APIStats represents statistics for API usage
 type APIStats struct {
 	Provider        string        `json:"provider"`
@@ -120,4 +158,9 @@ type APIStats struct {
 	TotalJobs       int           `json:"total_jobs"`
 	AverageLatency  time.Duration `json:"average_latency"`
 	LastUsed        time.Time     `json:"last_used"`
+	// CircuitState is the provider's circuit breaker state: closed, open
+	// or half-open
+	CircuitState        string     `json:"circuit_state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CircuitOpenUntil    *time.Time `json:"circuit_open_until,omitempty"`
 }
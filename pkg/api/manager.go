@@ -2,11 +2,15 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
 )
 
 // APIManager manages multiple job API providers
@@ -15,6 +19,24 @@ type APIManager struct {
 	stats     map[string]*APIStats
 	logger    *logrus.Logger
 	mutex     sync.RWMutex
+
+	// breakers rest the providers failing with server errors or timeouts
+	breakers         map[string]*circuitBreaker
+	failureThreshold int
+	openPeriod       time.Duration
+
+	// limits are each provider's token buckets, kept across reloads while
+	// its rate limit stays the same
+	limits      map[string]*providerLimiter
+	limitsMutex sync.Mutex
+
+	// usage is each provider's request counts across runs, saved to
+	// usagePath once TrackUsage has set it
+	usage     map[string]*ProviderUsage
+	usagePath string
+
+	// observe is told of every request counted in the stats
+	observe func(provider string, duration time.Duration, err error)
 }
 
 // NewAPIManager creates a new API manager
@@ -23,6 +45,12 @@ func NewAPIManager(logger *logrus.Logger) *APIManager {
 		providers: make(map[string]JobAPIProvider),
 		stats:     make(map[string]*APIStats),
 		logger:    logger,
+		limits:    make(map[string]*providerLimiter),
+		usage:     make(map[string]*ProviderUsage),
+
+		breakers:         make(map[string]*circuitBreaker),
+		failureThreshold: defaultFailureThreshold,
+		openPeriod:       defaultOpenPeriod,
 	}
 }
 
@@ -45,6 +73,43 @@ func (m *APIManager) RegisterProvider(provider JobAPIProvider) error {
 	return nil
 }
 
+// ReplaceProviders swaps the registered providers for the given set. Stats
+// and circuit breakers are kept for providers that remain registered and
+// dropped for the rest.
+func (m *APIManager) ReplaceProviders(providers []JobAPIProvider) error {
+	replaced := make(map[string]JobAPIProvider, len(providers))
+	for _, provider := range providers {
+		name := provider.GetName()
+		if _, exists := replaced[name]; exists {
+			return fmt.Errorf("provider %s registered twice", name)
+		}
+		replaced[name] = provider
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stats := make(map[string]*APIStats, len(replaced))
+	for name := range replaced {
+		if existing, ok := m.stats[name]; ok {
+			stats[name] = existing
+		} else {
+			stats[name] = &APIStats{Provider: name}
+		}
+	}
+
+	for name := range m.breakers {
+		if _, ok := replaced[name]; !ok {
+			delete(m.breakers, name)
+		}
+	}
+
+	m.providers = replaced
+	m.stats = stats
+	m.logger.Infof("Reloaded API providers: %d registered", len(replaced))
+	return nil
+}
+
 // GetProvider returns a specific provider by name
 func (m *APIManager) GetProvider(name string) (JobAPIProvider, error) {
 	m.mutex.RLock()
@@ -73,81 +138,11 @@ func (m *APIManager) GetConfiguredProviders() []JobAPIProvider {
 	return configured
 }
 
-// SearchAll searches all configured providers concurrently
+// SearchAll searches all configured providers concurrently, returning once
+// every one has answered. Jobs repeating one of an earlier provider are
+// dropped; use SearchAllStream to have each result as it arrives.
 func (m *APIManager) SearchAll(ctx context.Context, query SearchQuery) ([]*SearchResult, error) {
-	providers := m.GetConfiguredProviders()
-	if len(providers) == 0 {
-		return nil, fmt.Errorf("no configured API providers available")
-	}
-
-	resultChan := make(chan *SearchResult, len(providers))
-	errorChan := make(chan error, len(providers))
-
-	// Launch searches concurrently
-	var wg sync.WaitGroup
-	for _, provider := range providers {
-		wg.Add(1)
-		go func(p JobAPIProvider) {
-			defer wg.Done()
-
-			start := time.Now()
-			result, err := m.searchWithStats(ctx, p, query)
-			duration := time.Since(start)
-
-			// Update stats
-			m.updateStats(p.GetName(), err == nil, duration, result)
-
-			if err != nil {
-				m.logger.Warnf("Provider %s search failed: %v", p.GetName(), err)
-				errorChan <- fmt.Errorf("provider %s: %w", p.GetName(), err)
-				return
-			}
-
-			resultChan <- result
-		}(provider)
-	}
-
-	// Wait for all goroutines to complete
-	go func() {
-		wg.Wait()
-		close(resultChan)
-		close(errorChan)
-	}()
-
-	// Collect results
-	var results []*SearchResult
-	var errors []error
-
-	for {
-		select {
-		case result, ok := <-resultChan:
-			if !ok {
-				resultChan = nil
-			} else {
-				results = append(results, result)
-			}
-		case err, ok := <-errorChan:
-			if !ok {
-				errorChan = nil
-			} else {
-				errors = append(errors, err)
-			}
-		}
-
-		if resultChan == nil && errorChan == nil {
-			break
-		}
-	}
-
-	// Log summary
-	m.logger.Infof("API search completed: %d successful, %d failed providers",
-		len(results), len(errors))
-
-	if len(results) == 0 && len(errors) > 0 {
-		return nil, fmt.Errorf("all providers failed: %v", errors)
-	}
-
-	return results, nil
+	return collectResults(m.SearchAllStream(ctx, query))
 }
 
 // SearchProvider searches a specific provider
@@ -163,24 +158,56 @@ func (m *APIManager) SearchProvider(ctx context.Context, providerName string, qu
 
 	start := time.Now()
 	result, err := m.searchWithStats(ctx, provider, query)
-	duration := time.Since(start)
-
-	// Update stats
-	m.updateStats(providerName, err == nil, duration, result)
+	m.finishRequest(providerName, time.Since(start), result, err)
 
 	return result, err
 }
 
-// searchWithStats performs a search with rate limiting and error handling
+// ObserveRequests has observe called after every provider request with how
+// long it took, rate limit waits included, and the error it failed with
+func (m *APIManager) ObserveRequests(observe func(provider string, duration time.Duration, err error)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observe = observe
+}
+
+// finishRequest updates the stats with a request's outcome and tells the
+// observer, leaving out searches an open circuit turned away
+func (m *APIManager) finishRequest(name string, duration time.Duration, result *SearchResult, err error) {
+	if errors.Is(err, errs.ErrCircuitOpen) {
+		return
+	}
+	m.updateStats(name, err == nil, duration, result)
+
+	m.mutex.RLock()
+	observe := m.observe
+	m.mutex.RUnlock()
+	if observe != nil {
+		observe(name, duration, err)
+	}
+}
+
+// searchWithStats performs a search with circuit breaking, rate limiting
+// and error handling
 func (m *APIManager) searchWithStats(ctx context.Context, provider JobAPIProvider, query SearchQuery) (*SearchResult, error) {
-	// Apply rate limiting
-	rateLimit := provider.GetRateLimit()
-	if rateLimit.CooldownPeriod > 0 {
-		time.Sleep(rateLimit.CooldownPeriod)
+	name := provider.GetName()
+	if err := m.allow(name); err != nil {
+		return nil, err
+	}
+
+	// Wait for the provider's rate limit to allow the request
+	waited, err := m.limiter(provider).wait(ctx)
+	if err != nil {
+		m.release(name)
+		return nil, err
+	}
+	if waited > 0 {
+		m.logger.Debugf("Waited %s for %s rate limit", waited.Round(time.Millisecond), name)
 	}
 
 	// Perform search
 	result, err := provider.Search(ctx, query)
+	m.record(ctx, name, err)
 	if err != nil {
 		return nil, err
 	}
@@ -206,14 +233,17 @@ func (m *APIManager) updateStats(providerName string, success bool, duration tim
 	stats.TotalRequests++
 	stats.LastUsed = time.Now()
 
+	jobs := 0
 	if success {
 		stats.SuccessRequests++
 		if result != nil {
-			stats.TotalJobs += len(result.Jobs)
+			jobs = len(result.Jobs)
+			stats.TotalJobs += jobs
 		}
 	} else {
 		stats.FailedRequests++
 	}
+	m.recordUsage(providerName, success, jobs)
 
 	// Update average latency
 	if stats.TotalRequests == 1 {
@@ -242,14 +272,28 @@ func (m *APIManager) GetStats() map[string]*APIStats {
 			TotalJobs:       stats.TotalJobs,
 			AverageLatency:  stats.AverageLatency,
 			LastUsed:        stats.LastUsed,
+			CircuitState:    CircuitClosed,
+		}
+		if breaker, ok := m.breakers[name]; ok {
+			statsCopy[name].CircuitState = breaker.state
+			statsCopy[name].ConsecutiveFailures = breaker.failures
+			if breaker.state == CircuitOpen {
+				openUntil := breaker.openUntil
+				statsCopy[name].CircuitOpenUntil = &openUntil
+			}
 		}
 	}
 
 	return statsCopy
 }
 
-// ValidateAllProviders validates credentials for all providers
-func (m *APIManager) ValidateAllProviders(ctx context.Context) map[string]error {
+// DefaultValidationTimeout bounds how long one provider's credential check
+// may take
+const DefaultValidationTimeout = 15 * time.Second
+
+// ValidateAllProviders checks every provider's credentials concurrently,
+// giving each up to timeout, and returns the results sorted by provider
+func (m *APIManager) ValidateAllProviders(ctx context.Context, timeout time.Duration) []ProviderValidation {
 	m.mutex.RLock()
 	providers := make([]JobAPIProvider, 0, len(m.providers))
 	for _, provider := range m.providers {
@@ -257,25 +301,49 @@ func (m *APIManager) ValidateAllProviders(ctx context.Context) map[string]error
 	}
 	m.mutex.RUnlock()
 
-	results := make(map[string]error)
-	var wg sync.WaitGroup
+	if timeout <= 0 {
+		timeout = DefaultValidationTimeout
+	}
 
+	validations := make(chan ProviderValidation, len(providers))
 	for _, provider := range providers {
-		wg.Add(1)
 		go func(p JobAPIProvider) {
-			defer wg.Done()
-			name := p.GetName()
-
-			if !p.IsConfigured() {
-				results[name] = fmt.Errorf("provider not configured")
-				return
-			}
-
-			err := p.ValidateCredentials(ctx)
-			results[name] = err
+			validations <- validateProvider(ctx, p, timeout)
 		}(provider)
 	}
 
-	wg.Wait()
+	results := make([]ProviderValidation, 0, len(providers))
+	for range providers {
+		results = append(results, <-validations)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Provider < results[j].Provider })
 	return results
 }
+
+// validateProvider checks one provider's credentials within timeout
+func validateProvider(ctx context.Context, p JobAPIProvider, timeout time.Duration) ProviderValidation {
+	result := ProviderValidation{Provider: p.GetName()}
+	if !p.IsConfigured() {
+		result.Status = ValidationNotConfigured
+		result.Err = fmt.Errorf("provider not configured: %w", errs.ErrAuth)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	result.Err = p.ValidateCredentials(ctx)
+	result.Latency = time.Since(start)
+
+	switch {
+	case result.Err == nil:
+		result.Status = ValidationValid
+	case errors.Is(result.Err, errs.ErrAuth):
+		result.Status = ValidationInvalid
+	case errors.Is(result.Err, context.DeadlineExceeded):
+		result.Status = ValidationTimeout
+	default:
+		result.Status = ValidationFailed
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// SearchAllPaged searches all configured providers concurrently, following
+// each one's HasMore for up to maxPages pages of query.Limit jobs. Each
+// provider's pages are merged into one result, sorted by provider, and a
+// job repeating one from an earlier page or provider is dropped. A page
+// failing after the first ends that provider's paging with the jobs it
+// has; the search fails only when every provider fails on its first page.
+func (m *APIManager) SearchAllPaged(ctx context.Context, query SearchQuery, maxPages int) ([]*SearchResult, error) {
+	results, err := collectResults(m.SearchAllPagedStream(ctx, query, maxPages))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Provider < results[j].Provider })
+	return results, nil
+}
+
+// searchPages fetches up to maxPages pages from one provider and merges
+// them into one result
+func (m *APIManager) searchPages(ctx context.Context, provider JobAPIProvider, query SearchQuery, maxPages int) (*SearchResult, error) {
+	name := provider.GetName()
+	var merged *SearchResult
+	for page := 0; page < maxPages; page++ {
+		pageQuery := query
+		pageQuery.Offset = query.Offset + page*query.Limit
+
+		start := time.Now()
+		result, err := m.searchWithStats(ctx, provider, pageQuery)
+		m.finishRequest(name, time.Since(start), result, err)
+		if err != nil {
+			if merged == nil {
+				return nil, err
+			}
+			m.logger.Warnf("Stopped paging %s after %d pages: %v", name, page, err)
+			break
+		}
+
+		if merged == nil {
+			merged = result
+		} else {
+			merged.Jobs = append(merged.Jobs, result.Jobs...)
+			merged.HasMore = result.HasMore
+			merged.Total = max(merged.Total, result.Total)
+		}
+		// A page short of the limit is the last whatever HasMore says
+		if !result.HasMore || len(result.Jobs) == 0 || (query.Limit > 0 && len(result.Jobs) < query.Limit) {
+			break
+		}
+	}
+	return merged, nil
+}
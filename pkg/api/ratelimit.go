@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"hire.ai/pkg/errs"
+)
+
+// providerLimiter holds a provider's token buckets: one for each of its
+// per-minute, per-hour and per-day allowances, each refilling evenly over
+// its window and holding up to the whole allowance, and one spacing
+// requests by the cooldown period
+type providerLimiter struct {
+	limit   RateLimit
+	buckets []*rate.Limiter
+}
+
+func newProviderLimiter(limit RateLimit) *providerLimiter {
+	limiter := &providerLimiter{limit: limit}
+	windows := []struct {
+		requests int
+		window   time.Duration
+	}{
+		{limit.RequestsPerMinute, time.Minute},
+		{limit.RequestsPerHour, time.Hour},
+		{limit.RequestsPerDay, 24 * time.Hour},
+	}
+	for _, w := range windows {
+		if w.requests > 0 {
+			limiter.buckets = append(limiter.buckets, rate.NewLimiter(rate.Every(w.window/time.Duration(w.requests)), w.requests))
+		}
+	}
+	if limit.CooldownPeriod > 0 {
+		limiter.buckets = append(limiter.buckets, rate.NewLimiter(rate.Every(limit.CooldownPeriod), 1))
+	}
+	return limiter
+}
+
+// wait takes a token from each bucket, blocking until all of them have one.
+// Callers queue in the order they ask. When ctx is done first, or its
+// deadline comes before the tokens would, the tokens are given back and
+// the error matches errs.ErrRateLimited or is ctx's.
+func (l *providerLimiter) wait(ctx context.Context) (time.Duration, error) {
+	now := time.Now()
+	reservations := make([]*rate.Reservation, 0, len(l.buckets))
+	cancel := func() {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+
+	var delay time.Duration
+	for _, bucket := range l.buckets {
+		reservation := bucket.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		delay = max(delay, reservation.DelayFrom(now))
+	}
+	if delay == 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		cancel()
+		return 0, fmt.Errorf("%w: next request allowed in %s", errs.ErrRateLimited, delay.Round(time.Second))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		cancel()
+		return 0, ctx.Err()
+	}
+}
+
+// limiter returns the token buckets of provider, replacing them when its
+// rate limit has changed since they were made
+func (m *APIManager) limiter(provider JobAPIProvider) *providerLimiter {
+	name, limit := provider.GetName(), provider.GetRateLimit()
+
+	m.limitsMutex.Lock()
+	defer m.limitsMutex.Unlock()
+	limiter, ok := m.limits[name]
+	if !ok || limiter.limit != limit {
+		limiter = newProviderLimiter(limit)
+		m.limits[name] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+// ProviderResult is one provider's answer to a streamed search: its
+// result, or the error it failed with
+type ProviderResult struct {
+	Provider string
+	Result   *SearchResult
+	Err      error
+	Duration time.Duration
+}
+
+// SearchAllStream searches all configured providers concurrently and sends
+// each one's result as soon as it answers, so callers need not wait for
+// the slowest. Jobs repeating one an earlier provider sent are dropped. The
+// channel is closed once every provider has answered, at once when none
+// are configured.
+func (m *APIManager) SearchAllStream(ctx context.Context, query SearchQuery) <-chan ProviderResult {
+	return m.SearchAllPagedStream(ctx, query, 1)
+}
+
+// SearchAllPagedStream streams like SearchAllStream, with each provider's
+// result holding up to maxPages pages as SearchAllPaged fetches them
+func (m *APIManager) SearchAllPagedStream(ctx context.Context, query SearchQuery, maxPages int) <-chan ProviderResult {
+	providers := m.GetConfiguredProviders()
+	if maxPages < 1 {
+		maxPages = 1
+	}
+
+	answers := make(chan ProviderResult, len(providers))
+	var wg sync.WaitGroup
+	for _, provider := range providers {
+		wg.Add(1)
+		go func(p JobAPIProvider) {
+			defer wg.Done()
+			start := time.Now()
+			result, err := m.searchPages(ctx, p, query, maxPages)
+			answers <- ProviderResult{Provider: p.GetName(), Result: result, Err: err, Duration: time.Since(start)}
+		}(provider)
+	}
+	go func() {
+		wg.Wait()
+		close(answers)
+	}()
+
+	results := make(chan ProviderResult, len(providers))
+	go func() {
+		defer close(results)
+		seen := newJobSet()
+		succeeded, failed, dropped := 0, 0, 0
+		for answer := range answers {
+			if answer.Err != nil {
+				m.logger.Warnf("Provider %s search failed: %v", answer.Provider, answer.Err)
+				failed++
+			} else {
+				dropped += seen.dropRepeats(answer.Result)
+				succeeded++
+			}
+			results <- answer
+		}
+		if len(providers) > 0 {
+			m.logger.Infof("API search completed: %d successful, %d failed providers, %d duplicate jobs dropped",
+				succeeded, failed, dropped)
+		}
+	}()
+	return results
+}
+
+// collectResults gathers a stream's results, failing only when no provider
+// succeeded
+func collectResults(stream <-chan ProviderResult) ([]*SearchResult, error) {
+	var results []*SearchResult
+	var failed []error
+	answered := 0
+	for answer := range stream {
+		answered++
+		if answer.Err != nil {
+			failed = append(failed, fmt.Errorf("provider %s: %w", answer.Provider, answer.Err))
+			continue
+		}
+		results = append(results, answer.Result)
+	}
+
+	if answered == 0 {
+		return nil, fmt.Errorf("no configured API providers available")
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("all providers failed: %w", errs.Join(failed))
+	}
+	return results, nil
+}
+
+// jobSet holds the jobs a search has returned so far, indexed by the keys
+// Job.IsDuplicate compares
+type jobSet struct {
+	ids       map[string]bool
+	byPosting map[string][]*models.Job
+	byLink    map[string][]*models.Job
+}
+
+func newJobSet() *jobSet {
+	return &jobSet{
+		ids:       make(map[string]bool),
+		byPosting: make(map[string][]*models.Job),
+		byLink:    make(map[string][]*models.Job),
+	}
+}
+
+// dropRepeats removes from result the jobs that repeat one already in the
+// set, or an earlier job of result, and adds the rest. It returns how many
+// it removed.
+func (s *jobSet) dropRepeats(result *SearchResult) int {
+	dropped := 0
+	kept := make([]models.Job, 0, len(result.Jobs))
+	for i := range result.Jobs {
+		job := &result.Jobs[i]
+		posting, link := models.PostingKey(job), models.LinkKey(job.Link)
+		if s.ids[job.ID] || duplicateIn(s.byPosting[posting], job) || (link != "" && duplicateIn(s.byLink[link], job)) {
+			dropped++
+			continue
+		}
+		kept = append(kept, *job)
+		// The set keeps its own copy of what IsDuplicate compares, as
+		// callers may change the jobs sent on
+		stored := &models.Job{ID: job.ID, Title: job.Title, Company: job.Company, Location: job.Location, Link: job.Link}
+		s.ids[job.ID] = true
+		s.byPosting[posting] = append(s.byPosting[posting], stored)
+		if link != "" {
+			s.byLink[link] = append(s.byLink[link], stored)
+		}
+	}
+	result.Jobs = kept
+	return dropped
+}
+
+// duplicateIn reports whether job repeats any of candidates
+func duplicateIn(candidates []*models.Job, job *models.Job) bool {
+	for _, candidate := range candidates {
+		if candidate.IsDuplicate(job) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+// usageRetention is how long daily counts are kept: long enough to cover
+// the current and the previous month
+const usageRetention = 62 * 24 * time.Hour
+
+// usageDay keys a day's counts; days are UTC, as API quotas reset
+const usageDay = "2006-01-02"
+
+// UsageCounts are the requests made to a provider, how many of them
+// failed, and the jobs the rest returned
+type UsageCounts struct {
+	Requests int `json:"requests"`
+	Failures int `json:"failures"`
+	Jobs     int `json:"jobs"`
+}
+
+func (c *UsageCounts) add(other UsageCounts) {
+	c.Requests += other.Requests
+	c.Failures += other.Failures
+	c.Jobs += other.Jobs
+}
+
+// ProviderUsage is what searches of one provider have used across runs:
+// totals since tracking began and the counts of each recent day
+type ProviderUsage struct {
+	Provider string `json:"provider"`
+	UsageCounts
+	LastUsed time.Time `json:"last_used"`
+	// Days holds the counts of each UTC day, keyed 2006-01-02, for the
+	// last 62 days
+	Days map[string]UsageCounts `json:"days,omitempty"`
+}
+
+// Day returns the counts of the UTC day t falls on
+func (u *ProviderUsage) Day(t time.Time) UsageCounts {
+	return u.Days[t.UTC().Format(usageDay)]
+}
+
+// Month returns the counts of the UTC calendar month t falls in
+func (u *ProviderUsage) Month(t time.Time) UsageCounts {
+	var month UsageCounts
+	prefix := t.UTC().Format("2006-01-")
+	for day, counts := range u.Days {
+		if len(day) == len(usageDay) && day[:len(prefix)] == prefix {
+			month.add(counts)
+		}
+	}
+	return month
+}
+
+// record counts one request made at now
+func (u *ProviderUsage) record(now time.Time, success bool, jobs int) {
+	counts := UsageCounts{Requests: 1, Jobs: jobs}
+	if !success {
+		counts.Failures = 1
+	}
+	u.UsageCounts.add(counts)
+	u.LastUsed = now
+
+	if u.Days == nil {
+		u.Days = make(map[string]UsageCounts)
+	}
+	key := now.UTC().Format(usageDay)
+	day := u.Days[key]
+	day.add(counts)
+	u.Days[key] = day
+}
+
+// merge adds other's counts to u
+func (u *ProviderUsage) merge(other ProviderUsage) {
+	u.UsageCounts.add(other.UsageCounts)
+	if other.LastUsed.After(u.LastUsed) {
+		u.LastUsed = other.LastUsed
+	}
+	if u.Days == nil {
+		u.Days = make(map[string]UsageCounts, len(other.Days))
+	}
+	for key, counts := range other.Days {
+		day := u.Days[key]
+		day.add(counts)
+		u.Days[key] = day
+	}
+}
+
+// prune drops the days older than usageRetention
+func (u *ProviderUsage) prune(now time.Time) {
+	cutoff := now.UTC().Add(-usageRetention).Format(usageDay)
+	for key := range u.Days {
+		if key < cutoff {
+			delete(u.Days, key)
+		}
+	}
+}
+
+// recordUsage counts a request to the named provider; callers hold mutex
+func (m *APIManager) recordUsage(name string, success bool, jobs int) {
+	usage, ok := m.usage[name]
+	if !ok {
+		usage = &ProviderUsage{Provider: name}
+		m.usage[name] = usage
+	}
+	usage.record(time.Now(), success, jobs)
+}
+
+// Usage returns the usage of every provider searched, including what
+// earlier runs saved to the file given to TrackUsage
+func (m *APIManager) Usage() []ProviderUsage {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	usage := make([]ProviderUsage, 0, len(m.usage))
+	for _, u := range m.usage {
+		copied := *u
+		copied.Days = make(map[string]UsageCounts, len(u.Days))
+		for key, counts := range u.Days {
+			copied.Days[key] = counts
+		}
+		usage = append(usage, copied)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Provider < usage[j].Provider })
+	return usage
+}
+
+// TrackUsage loads the usage saved at path, which SaveUsage adds this
+// run's requests to
+func (m *APIManager) TrackUsage(path string) error {
+	usage, err := ReadUsage(path)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.usagePath = path
+	for _, saved := range usage {
+		if current, ok := m.usage[saved.Provider]; ok {
+			saved.merge(*current)
+		}
+		saved := saved
+		m.usage[saved.Provider] = &saved
+	}
+	return nil
+}
+
+// SaveUsage writes the usage to the file given to TrackUsage, dropping the
+// days too old to keep
+func (m *APIManager) SaveUsage() error {
+	m.mutex.Lock()
+	path := m.usagePath
+	now := time.Now()
+	for _, u := range m.usage {
+		u.prune(now)
+	}
+	m.mutex.Unlock()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m.Usage(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ReadUsage reads saved provider usage; a missing file has none
+func ReadUsage(path string) ([]ProviderUsage, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var usage []ProviderUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
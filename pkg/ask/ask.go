@@ -0,0 +1,190 @@
+// Package ask answers questions about the stored jobs. It retrieves the
+// postings most relevant to a question by keyword and embedding similarity
+// and has an LLM answer from those alone, citing them by ID.
+package ask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the ask section of GlobalSettings. Model is the chat model that
+// answers; EmbeddingModel, from the llm section when left out, retrieves by
+// meaning. Without one, retrieval is by keyword only.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	EmbeddingModel string `json:"embeddingModel,omitempty"`
+	// Results is how many postings the answer is drawn from. Defaults to 8.
+	Results int `json:"results,omitempty"`
+	// BatchSize is the number of texts per embeddings request. Defaults to 32.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// maxPromptDescription keeps the retrieved postings within small local
+// models' context
+const maxPromptDescription = 1500
+
+const systemPrompt = `You answer questions about a job seeker's collection of job postings. Use only the postings given; do not rely on outside knowledge of companies. Cite every posting you draw on by its ID in square brackets, e.g. [3f2a9c]. If the postings do not answer the question, say so plainly. Be concise.`
+
+// Assistant answers questions over jobs
+type Assistant struct {
+	config   Config
+	chat     ai.LLMClient
+	embedder ai.LLMClient
+	index    *index
+	logger   *logrus.Logger
+}
+
+// Answer is the reply to a question and the postings it was drawn from
+type Answer struct {
+	Question string   `json:"question"`
+	Answer   string   `json:"answer"`
+	Model    string   `json:"model"`
+	Sources  []Source `json:"sources"`
+}
+
+// Source is a retrieved posting
+type Source struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Company  string `json:"company"`
+	Location string `json:"location"`
+	Link     string `json:"link"`
+	// Cited is set when the answer refers to the posting
+	Cited bool `json:"cited"`
+}
+
+// New validates config, filling in what it leaves out from defaults, and
+// loads the embeddings indexed at indexPath; a nil or disabled config
+// returns a nil Assistant
+func New(config *Config, defaults *ai.Defaults, indexPath string, logger *logrus.Logger) (*Assistant, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	embedding := c.Config
+	embedding.Model = c.EmbeddingModel
+	defaults.Apply(&c.Config, false)
+	defaults.Apply(&embedding, true)
+	chat, err := ai.NewClient(c.Config, "ask")
+	if err != nil {
+		return nil, err
+	}
+	if c.Results <= 0 {
+		c.Results = 8
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 32
+	}
+
+	assistant := &Assistant{config: c, chat: chat, logger: logger}
+	if embedding.Model != "" {
+		if assistant.embedder, err = ai.NewClient(embedding, "ask"); err != nil {
+			return nil, err
+		}
+		if assistant.index, err = loadIndex(indexPath, embedding.Model); err != nil {
+			return nil, err
+		}
+	}
+	return assistant, nil
+}
+
+// Ask answers question from the results jobs most relevant to it (Results
+// when results is 0). When embeddings fail, it falls back to keyword
+// retrieval.
+func (a *Assistant) Ask(ctx context.Context, question string, jobs []models.Job, results int) (*Answer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, errors.New("the question is empty")
+	}
+
+	rankings := [][]*models.Job{keywordRanking(question, jobs)}
+	if a.embedder != nil {
+		ranking, err := a.vectorRanking(ctx, question, jobs)
+		if err != nil {
+			a.logger.Warnf("Searching by keyword only: %v", err)
+		} else {
+			rankings = append(rankings, ranking)
+		}
+	}
+	if results <= 0 {
+		results = a.config.Results
+	}
+	retrieved := fuse(rankings, results)
+	if len(retrieved) == 0 {
+		return nil, errors.New("no stored jobs relate to the question")
+	}
+
+	reply, err := a.chat.Complete(ctx, systemPrompt, prompt(question, retrieved))
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &Answer{Question: question, Answer: strings.TrimSpace(reply), Model: a.chat.Model()}
+	cited := citations(answer.Answer)
+	for _, job := range retrieved {
+		answer.Sources = append(answer.Sources, Source{
+			ID:       job.ID,
+			Title:    job.Title,
+			Company:  job.Company,
+			Location: job.Location,
+			Link:     job.Link,
+			Cited:    cited[job.ID],
+		})
+	}
+	return answer, nil
+}
+
+func prompt(question string, jobs []*models.Job) string {
+	var text strings.Builder
+	text.WriteString("Job postings:\n")
+	for _, job := range jobs {
+		fmt.Fprintf(&text, "\n[%s] %s at %s\n", job.ID, job.Title, job.Company)
+		details := []string{"Location: " + orUnknown(job.Location)}
+		if job.Salary != "" {
+			details = append(details, "Salary: "+job.Salary)
+		}
+		if c := job.Classification; c != nil {
+			details = append(details, "Seniority: "+c.Seniority, "Industry: "+c.Industry)
+		}
+		details = append(details, "Posted: "+models.InDisplayZone(job.PostedTime()).Format("2006-01-02"))
+		fmt.Fprintf(&text, "%s\n", strings.Join(details, " | "))
+		if job.Summary != nil {
+			fmt.Fprintf(&text, "%s\n", strings.Join(job.Summary.Bullets(), "\n"))
+		}
+		fmt.Fprintf(&text, "%s\n", ai.Truncate(strings.TrimSpace(job.Description), maxPromptDescription))
+	}
+	fmt.Fprintf(&text, "\nQuestion: %s", question)
+	return text.String()
+}
+
+func orUnknown(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// citationPattern matches bracketed references such as [id] or [id1, id2]
+var citationPattern = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// citations collects the job IDs an answer cites
+func citations(answer string) map[string]bool {
+	cited := make(map[string]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		for _, id := range strings.Split(match[1], ",") {
+			cited[strings.TrimSpace(id)] = true
+		}
+	}
+	return cited
+}
@@ -0,0 +1,273 @@
+package ask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// candidates is how deep each ranking goes before fusion
+const candidates = 50
+
+// maxEmbedDescription keeps texts within embedding models' context
+const maxEmbedDescription = 4000
+
+// stopWords are left out of keyword matching; they are mostly the phrasing
+// of questions about the jobs rather than what the jobs say
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "any": true, "are": true, "at": true, "be": true,
+	"by": true, "can": true, "do": true, "does": true, "for": true, "from": true, "have": true,
+	"how": true, "i": true, "in": true, "is": true, "it": true, "job": true, "jobs": true,
+	"me": true, "my": true, "of": true, "on": true, "or": true, "posting": true, "postings": true,
+	"role": true, "roles": true, "show": true, "that": true, "the": true, "there": true,
+	"to": true, "what": true, "which": true, "who": true, "with": true, "dataset": true,
+	"company": true, "companies": true, "hire": true, "hiring": true, "hires": true,
+	"offer": true, "offers": true, "list": true, "find": true, "many": true,
+}
+
+// keywordRanking orders the jobs that contain the question's terms by how
+// rare the terms are and where they appear: title over company and location
+// over description
+func keywordRanking(question string, jobs []models.Job) []*models.Job {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range words(question) {
+		if term := fold(word); !stopWords[word] && !seen[term] {
+			terms = append(terms, term)
+			seen[term] = true
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type fields struct{ title, meta, body map[string]bool }
+	indexed := make([]fields, len(jobs))
+	frequency := make(map[string]int)
+	for i := range jobs {
+		job := &jobs[i]
+		indexed[i] = fields{
+			title: wordSet(job.Title),
+			meta:  wordSet(job.Company + " " + job.Location + " " + strings.Join(job.Keywords, " ")),
+			body:  wordSet(job.Description),
+		}
+		for _, term := range terms {
+			if indexed[i].title[term] || indexed[i].meta[term] || indexed[i].body[term] {
+				frequency[term]++
+			}
+		}
+	}
+
+	type scored struct {
+		job   *models.Job
+		score float64
+	}
+	var matches []scored
+	for i := range jobs {
+		score := 0.0
+		for _, term := range terms {
+			weight := 0.0
+			switch {
+			case indexed[i].title[term]:
+				weight = 3
+			case indexed[i].meta[term]:
+				weight = 2
+			case indexed[i].body[term]:
+				weight = 1
+			}
+			if weight > 0 {
+				score += weight * math.Log(1+float64(len(jobs))/float64(frequency[term]))
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{&jobs[i], score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	ranking := make([]*models.Job, 0, min(len(matches), candidates))
+	for _, match := range matches[:min(len(matches), candidates)] {
+		ranking = append(ranking, match.job)
+	}
+	return ranking
+}
+
+// words lowercases text and splits it into words. Symbols that name
+// technologies, as in c++ and c#, stay part of the word.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+}
+
+// fold drops a plural s so "engineers" matches "engineer"
+func fold(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// wordSet is the folded words of text
+func wordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range words(text) {
+		set[fold(word)] = true
+	}
+	return set
+}
+
+// vectorRanking orders the jobs by the similarity of their embedding to the
+// question's, embedding and indexing the jobs not seen before
+func (a *Assistant) vectorRanking(ctx context.Context, question string, jobs []models.Job) ([]*models.Job, error) {
+	if err := a.indexJobs(ctx, jobs); err != nil {
+		return nil, err
+	}
+	vectors, err := a.embedder.Embed(ctx, []string{question})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the question: %w", err)
+	}
+	query := ai.Normalize(vectors[0])
+
+	ranking := make([]*models.Job, 0, len(jobs))
+	similarity := make(map[string]float64, len(jobs))
+	for i := range jobs {
+		if vector := a.index.vectors[jobs[i].ID]; vector != nil {
+			ranking = append(ranking, &jobs[i])
+			similarity[jobs[i].ID] = ai.Dot(query, vector)
+		}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool { return similarity[ranking[i].ID] > similarity[ranking[j].ID] })
+	return ranking[:min(len(ranking), candidates)], nil
+}
+
+// indexJobs embeds the jobs missing from the index, drops jobs no longer
+// stored and saves it
+func (a *Assistant) indexJobs(ctx context.Context, jobs []models.Job) error {
+	var missing []*models.Job
+	keep := make(map[string]bool, len(jobs))
+	for i := range jobs {
+		keep[jobs[i].ID] = true
+		if a.index.vectors[jobs[i].ID] == nil {
+			missing = append(missing, &jobs[i])
+		}
+	}
+	changed := len(missing) > 0
+	for id := range a.index.vectors {
+		if !keep[id] {
+			delete(a.index.vectors, id)
+			changed = true
+		}
+	}
+	if len(missing) > 0 {
+		a.logger.Infof("Indexing %d jobs for search", len(missing))
+	}
+
+	for start := 0; start < len(missing); start += a.config.BatchSize {
+		batch := missing[start:min(start+a.config.BatchSize, len(missing))]
+		texts := make([]string, len(batch))
+		for i, job := range batch {
+			texts[i] = job.Title + "\n" + job.Company + "\n" + job.Location + "\n\n" + ai.Truncate(job.Description, maxEmbedDescription)
+		}
+		vectors, err := a.embedder.Embed(ctx, texts)
+		if err != nil {
+			// Keep what was indexed so far for next time
+			if saveErr := a.index.save(); saveErr != nil {
+				a.logger.Errorf("Failed to save search index: %v", saveErr)
+			}
+			return fmt.Errorf("failed to embed jobs: %w", err)
+		}
+		for i, job := range batch {
+			a.index.vectors[job.ID] = ai.Normalize(vectors[i])
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := a.index.save(); err != nil {
+		a.logger.Errorf("Failed to save search index: %v", err)
+	}
+	return nil
+}
+
+// fuse merges rankings by reciprocal rank, which needs no common scale
+// between keyword scores and similarities, and returns the top limit jobs
+func fuse(rankings [][]*models.Job, limit int) []*models.Job {
+	const k = 60
+	scores := make(map[string]float64)
+	var jobs []*models.Job
+	for _, ranking := range rankings {
+		for rank, job := range ranking {
+			if _, ok := scores[job.ID]; !ok {
+				jobs = append(jobs, job)
+			}
+			scores[job.ID] += 1 / float64(k+rank+1)
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return scores[jobs[i].ID] > scores[jobs[j].ID] })
+	return jobs[:min(len(jobs), limit)]
+}
+
+// index holds job embeddings by ID. It is tied to the model that made them
+// and starts over when the model changes.
+type index struct {
+	path    string
+	model   string
+	vectors map[string][]float32
+}
+
+type indexFile struct {
+	Model   string            `json:"model"`
+	Vectors map[string]string `json:"vectors"`
+}
+
+func loadIndex(path, model string) (*index, error) {
+	idx := &index{path: path, model: model, vectors: make(map[string][]float32)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse search index: %w", err)
+	}
+	if file.Model != model {
+		return idx, nil
+	}
+	for id, value := range file.Vectors {
+		if vector, err := ai.DecodeVector(value); err == nil {
+			idx.vectors[id] = vector
+		}
+	}
+	return idx, nil
+}
+
+func (idx *index) save() error {
+	file := indexFile{Model: idx.model, Vectors: make(map[string]string, len(idx.vectors))}
+	for id, vector := range idx.vectors {
+		file.Vectors[id] = ai.EncodeVector(vector)
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(idx.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(idx.path+".tmp", idx.path)
+}
@@ -0,0 +1,272 @@
+// Package auth manages the bearer tokens that protect the API server
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token scopes. Admin covers everything; ingest can read and push postings
+// through POST /ingest, which suits a browser extension.
+const (
+	ScopeRead   = "read"
+	ScopeIngest = "ingest"
+	ScopeAdmin  = "admin"
+)
+
+const (
+	tokensFileName = "tokens.json"
+	tokenPrefix    = "hai_"
+)
+
+// Token is a stored API token. Only a SHA-256 hash of the secret is kept, so
+// a leaked tokens.json cannot be replayed. User is empty for tokens that act
+// on the shared default profile.
+type Token struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	User      string    `json:"user,omitempty"`
+	Hash      string    `json:"hash"`
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Allows reports whether the token grants the given scope. Admin grants
+// everything and ingest also grants read.
+func (t *Token) Allows(scope string) bool {
+	return t.Scope == ScopeAdmin || t.Scope == scope || (t.Scope == ScopeIngest && scope == ScopeRead)
+}
+
+// ValidateScope checks that scope is one of the known scopes
+func ValidateScope(scope string) error {
+	switch scope {
+	case ScopeRead, ScopeIngest, ScopeAdmin:
+		return nil
+	}
+	return fmt.Errorf("unknown scope %q (use %s, %s or %s)", scope, ScopeRead, ScopeIngest, ScopeAdmin)
+}
+
+// TokenStore keeps tokens in the data directory. It reloads the file when it
+// changes on disk, so tokens created or revoked with the CLI take effect in a
+// running server without a restart.
+type TokenStore struct {
+	filePath string
+	tokens   []Token
+	modTime  time.Time
+	mutex    sync.RWMutex
+}
+
+// NewTokenStore creates a new token store in the specified data directory
+func NewTokenStore(dataDir string) (*TokenStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &TokenStore{filePath: filepath.Join(dataDir, tokensFileName)}
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// reload rereads tokens.json if it changed since the last read
+func (s *TokenStore) reload() error {
+	info, err := os.Stat(s.filePath)
+	if os.IsNotExist(err) {
+		s.mutex.Lock()
+		s.tokens, s.modTime = nil, time.Time{}
+		s.mutex.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", s.filePath, err)
+	}
+
+	s.mutex.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mutex.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", s.filePath, err)
+	}
+
+	var tokens []Token
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tokens); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", s.filePath, err)
+		}
+	}
+
+	s.mutex.Lock()
+	s.tokens, s.modTime = tokens, info.ModTime()
+	s.mutex.Unlock()
+	return nil
+}
+
+// Create generates a new token for user and returns it with its secret,
+// which is shown once and cannot be recovered later
+func (s *TokenStore) Create(name, scope, user string) (*Token, string, error) {
+	if err := ValidateScope(scope); err != nil {
+		return nil, "", err
+	}
+	if err := s.reload(); err != nil {
+		return nil, "", err
+	}
+
+	secretBytes := make([]byte, 24)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	secret := tokenPrefix + hex.EncodeToString(secretBytes)
+
+	idBytes := make([]byte, 4)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	token := Token{
+		ID:        hex.EncodeToString(idBytes),
+		Name:      strings.TrimSpace(name),
+		User:      user,
+		Hash:      hashSecret(secret),
+		Scope:     scope,
+		CreatedAt: time.Now(),
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tokens = append(s.tokens, token)
+	if err := s.save(); err != nil {
+		return nil, "", err
+	}
+	return &token, secret, nil
+}
+
+// List returns all tokens, oldest first
+func (s *TokenStore) List() ([]Token, error) {
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tokens := make([]Token, len(s.tokens))
+	copy(tokens, s.tokens)
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.Before(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+// Revoke deletes the token with the given ID or name
+func (s *TokenStore) Revoke(idOrName string) error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, token := range s.tokens {
+		if token.ID == idOrName || token.Name == idOrName {
+			s.tokens = append(s.tokens[:i], s.tokens[i+1:]...)
+			return s.save()
+		}
+	}
+	return fmt.Errorf("token %s not found", idOrName)
+}
+
+// RevokeUser deletes every token belonging to user and returns how many
+// were removed
+func (s *TokenStore) RevokeUser(user string) (int, error) {
+	if user == "" {
+		return 0, fmt.Errorf("user is required")
+	}
+	if err := s.reload(); err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.tokens[:0]
+	for _, token := range s.tokens {
+		if token.User != user {
+			kept = append(kept, token)
+		}
+	}
+	removed := len(s.tokens) - len(kept)
+	s.tokens = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+// Enabled reports whether any tokens exist; with none, the server runs open
+func (s *TokenStore) Enabled() bool {
+	s.reload()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.tokens) > 0
+}
+
+// Authenticate returns the token matching the presented secret
+func (s *TokenStore) Authenticate(secret string) (*Token, error) {
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	hash := hashSecret(secret)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.tokens {
+		if s.tokens[i].Hash == hash {
+			token := s.tokens[i]
+			return &token, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid token")
+}
+
+// save writes the tokens with owner-only permissions; callers hold the lock
+func (s *TokenStore) save() error {
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tokens: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tokens: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		return fmt.Errorf("failed to write tokens: %w", err)
+	}
+
+	if info, err := os.Stat(s.filePath); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the classification section of GlobalSettings. Rule-based
+// classification always runs; enabling this adds the LLM pass.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// MaxJobsPerRun bounds the LLM calls after one scrape. Defaults to 50.
+	MaxJobsPerRun int `json:"maxJobsPerRun,omitempty"`
+}
+
+// maxPromptDescription keeps prompts within small local models' context
+const maxPromptDescription = 6000
+
+// Classifier asks the configured LLM to classify jobs
+type Classifier struct {
+	config Config
+	client ai.LLMClient
+	logger *logrus.Logger
+	system string
+}
+
+// New validates config, filling in what it leaves out from defaults; a nil
+// or disabled config returns a nil Classifier
+func New(config *Config, defaults *ai.Defaults, logger *logrus.Logger) (*Classifier, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, false)
+	client, err := ai.NewClient(c.Config, "classification")
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxJobsPerRun <= 0 {
+		c.MaxJobsPerRun = 50
+	}
+
+	system := fmt.Sprintf(`You classify job postings. Reply with a single JSON object and nothing else:
+{"category": "...", "seniority": "...", "industry": "..."}
+category is one of: %s.
+seniority is one of: %s.
+industry is the employer's industry, one of: %s.`,
+		strings.Join(Categories(), ", "), strings.Join(seniorities, ", "), strings.Join(Industries(), ", "))
+
+	return &Classifier{config: c, client: client, logger: logger, system: system}, nil
+}
+
+// ClassifyAll replaces rule-based or missing classifications with the LLM's,
+// up to limit jobs (MaxJobsPerRun when limit is 0), and returns how many it
+// classified. A job whose request fails keeps its rule-based labels.
+func (c *Classifier) ClassifyAll(ctx context.Context, jobs []models.Job, limit int) int {
+	if limit <= 0 {
+		limit = c.config.MaxJobsPerRun
+	}
+
+	var pending []int
+	for i := range jobs {
+		if jobs[i].Classification == nil || jobs[i].Classification.Source != models.ClassificationSourceLLM {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) > limit {
+		c.logger.Warnf("Classifying %d of %d jobs with the LLM; the rest keep rule-based labels", limit, len(pending))
+		pending = pending[:limit]
+	}
+
+	classified := 0
+	for _, i := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		job := &jobs[i]
+		classification, err := c.Classify(ctx, job)
+		if err != nil {
+			c.logger.WithField("job_id", job.ID).Warnf("Failed to classify %s: %v", job.Title, err)
+			if job.Classification == nil {
+				job.Classification = Classify(job)
+			}
+			continue
+		}
+		job.Classification = classification
+		classified++
+	}
+	return classified
+}
+
+// Classify asks the LLM for one job's labels. A label outside the known set
+// falls back to the rule-based one.
+func (c *Classifier) Classify(ctx context.Context, job *models.Job) (*models.JobClassification, error) {
+	reply, err := c.client.Complete(ctx, c.system, prompt(job))
+	if err != nil {
+		return nil, err
+	}
+
+	var answer models.JobClassification
+	if err := ai.DecodeJSON(reply, &answer); err != nil {
+		return nil, err
+	}
+
+	rules := Classify(job)
+	classification := &models.JobClassification{
+		Category:  known(answer.Category, Categories(), rules.Category),
+		Seniority: known(answer.Seniority, seniorities, rules.Seniority),
+		Industry:  known(answer.Industry, Industries(), rules.Industry),
+		Source:    models.ClassificationSourceLLM,
+	}
+	return classification, nil
+}
+
+func prompt(job *models.Job) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Title: %s\nCompany: %s\n", job.Title, job.Company)
+	fmt.Fprintf(&text, "\nDescription:\n%s", ai.Truncate(job.Description, maxPromptDescription))
+	return text.String()
+}
+
+// known returns label normalised if it is one of labels, else fallback
+func known(label string, labels []string, fallback string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, l := range labels {
+		if label == l {
+			return l
+		}
+	}
+	return fallback
+}
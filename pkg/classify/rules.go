@@ -0,0 +1,173 @@
+// Package classify tags jobs with a role category, seniority and industry:
+// keyword rules for every job, with an optional LLM pass for better labels.
+package classify
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+// Other is the category or industry of jobs no rule fits
+const Other = "other"
+
+// seniorities run from least to most senior; mid is the default
+var seniorities = []string{"intern", "junior", "mid", "senior", "lead", "director"}
+
+// rule labels jobs that mention its terms
+type rule struct {
+	label string
+	terms []string
+}
+
+// categoryRules come first to last as tie-breaks, so the more specific
+// roles precede backend and frontend
+var categoryRules = []rule{
+	{"management", []string{"engineering manager", "head of engineering", "director of engineering", "vp of engineering", "vp engineering", "cto"}},
+	{"product", []string{"product manager", "product owner", "technical program manager", "program manager"}},
+	{"design", []string{"designer", "ux", "ui/ux", "user research", "figma"}},
+	{"ml", []string{"machine learning", "ml engineer", "data scientist", "deep learning", "nlp", "computer vision", "llm", "pytorch", "tensorflow"}},
+	{"data", []string{"data engineer", "data analyst", "analytics engineer", "etl", "data warehouse", "data pipeline", "spark", "airflow", "dbt", "snowflake", "tableau"}},
+	{"sre", []string{"sre", "site reliability", "devops", "platform engineer", "infrastructure", "kubernetes", "terraform", "cloud engineer"}},
+	{"security", []string{"security engineer", "appsec", "application security", "infosec", "penetration", "soc analyst", "cybersecurity"}},
+	{"qa", []string{"qa", "quality assurance", "test engineer", "sdet", "test automation"}},
+	{"mobile", []string{"ios", "android", "mobile", "react native", "flutter", "swift", "kotlin"}},
+	{"embedded", []string{"embedded", "firmware", "fpga", "rtos"}},
+	{"fullstack", []string{"full stack", "full-stack", "fullstack"}},
+	{"frontend", []string{"frontend", "front-end", "front end", "react", "vue", "angular", "css", "typescript"}},
+	{"backend", []string{"backend", "back-end", "back end", "api", "microservices", "golang", "java", "django", "rails", "spring", "node.js"}},
+}
+
+// industryRules avoid terms job ads use about benefits, such as "health
+// insurance" or "learning budget"
+var industryRules = []rule{
+	{"fintech", []string{"fintech", "banking", "payments", "financial services", "trading", "lending", "crypto", "blockchain"}},
+	{"healthcare", []string{"healthcare", "medical", "clinical", "hospital", "patients", "pharma", "biotech"}},
+	{"ecommerce", []string{"e-commerce", "ecommerce", "retail", "marketplace", "shopping"}},
+	{"education", []string{"edtech", "education", "students", "teachers", "university"}},
+	{"gaming", []string{"gaming", "game studio", "video games", "game developer"}},
+	{"media", []string{"streaming", "publishing", "newsroom", "advertising", "adtech", "media company"}},
+	{"government", []string{"government", "public sector", "federal", "defense", "security clearance"}},
+	{"logistics", []string{"logistics", "supply chain", "shipping", "fleet", "warehouse management"}},
+	{"travel", []string{"travel", "hospitality", "airline", "hotel"}},
+	{"energy", []string{"energy", "climate", "renewable", "solar", "cleantech"}},
+}
+
+// seniorityRules read the title, most senior first
+var seniorityRules = []rule{
+	{"intern", []string{"intern", "internship", "trainee", "apprentice"}},
+	{"director", []string{"director", "vp", "vice president", "head of", "chief", "cto"}},
+	{"lead", []string{"lead", "principal", "staff", "architect", "engineering manager"}},
+	{"senior", []string{"senior", "sr", "sr."}},
+	{"junior", []string{"junior", "jr", "jr.", "entry level", "entry-level", "graduate", "associate"}},
+}
+
+var (
+	termPatterns = compileTerms(categoryRules, industryRules, seniorityRules)
+	yearsPattern = regexp.MustCompile(`(\d{1,2})\+?\s*(?:-\s*\d{1,2}\s*)?years?`)
+)
+
+// Classify labels a job by keyword rules. Terms in the title count three
+// times as much as terms in the description.
+func Classify(job *models.Job) *models.JobClassification {
+	title := strings.ToLower(job.Title)
+	description := strings.ToLower(job.Description)
+
+	return &models.JobClassification{
+		Category:  best(categoryRules, title, description, 1),
+		Seniority: seniority(title, description),
+		// One stray mention is not enough to place a company
+		Industry: best(industryRules, strings.ToLower(job.Company)+" "+title, description, 2),
+		Source:   models.ClassificationSourceRules,
+	}
+}
+
+// best returns the label whose terms score highest, or Other when none
+// reaches threshold. Each term counts once per text.
+func best(rules []rule, title, description string, threshold int) string {
+	label, top := Other, threshold-1
+	for _, r := range rules {
+		score := 0
+		for _, term := range r.terms {
+			if termPatterns[term].MatchString(title) {
+				score += 3
+			}
+			if termPatterns[term].MatchString(description) {
+				score++
+			}
+		}
+		if score > top {
+			label, top = r.label, score
+		}
+	}
+	return label
+}
+
+// seniority reads the title, then the years of experience asked for
+func seniority(title, description string) string {
+	for _, r := range seniorityRules {
+		for _, term := range r.terms {
+			if termPatterns[term].MatchString(title) {
+				return r.label
+			}
+		}
+	}
+
+	years := 0
+	for _, match := range yearsPattern.FindAllStringSubmatch(description, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n > years && n < 30 {
+			years = n
+		}
+	}
+	switch {
+	case years >= 8:
+		return "lead"
+	case years >= 5:
+		return "senior"
+	case years > 0 && years <= 1:
+		return "junior"
+	}
+	return "mid"
+}
+
+// Categories lists the labels Classify assigns as a category
+func Categories() []string {
+	return labels(categoryRules)
+}
+
+// Seniorities lists the seniority labels from least to most senior
+func Seniorities() []string {
+	return append([]string(nil), seniorities...)
+}
+
+// Industries lists the labels Classify assigns as an industry
+func Industries() []string {
+	return labels(industryRules)
+}
+
+func labels(rules []rule) []string {
+	names := make([]string, 0, len(rules)+1)
+	for _, r := range rules {
+		names = append(names, r.label)
+	}
+	return append(names, Other)
+}
+
+func compileTerms(ruleSets ...[]rule) map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, rules := range ruleSets {
+		for _, r := range rules {
+			for _, term := range r.terms {
+				// \b does not follow a trailing dot, as in "sr."
+				pattern := `\b` + regexp.QuoteMeta(term)
+				if !strings.HasSuffix(term, ".") {
+					pattern += `\b`
+				}
+				patterns[term] = regexp.MustCompile(pattern)
+			}
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,487 @@
+// Package client is a typed Go client for the REST API served by
+// `job-scraper serve`. The endpoints are documented in the OpenAPI spec the
+// server publishes at /openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+)
+
+// Client calls the hire.ai REST API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// Error is returned for non-2xx responses
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// NewClient creates a new client for the server at baseURL. A nil httpClient
+// uses one with a 30 second timeout.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// WithToken returns a copy of the client that authenticates with the given
+// bearer token (see `job-scraper tokens create`)
+func (c *Client) WithToken(token string) *Client {
+	copied := *c
+	copied.token = token
+	return &copied
+}
+
+// JobQuery holds the filters, sorting and paging accepted by ListJobs; zero
+// values are omitted
+type JobQuery struct {
+	Q           string
+	Semantic    string // natural-language query ranked by meaning
+	Keywords    []string
+	Location    string
+	Sources     []string
+	Categories  []string
+	Seniorities []string
+	Industries  []string
+	// EmploymentTypes and RemoteTypes keep jobs of any of these types
+	EmploymentTypes []string
+	RemoteTypes     []string
+	KeywordQuery    string // boolean query, e.g. "golang AND (kubernetes OR terraform)"
+	MinSalary       int
+	MaxSalary       int
+	// SalaryCurrency is the ISO code MinSalary and MaxSalary are in
+	SalaryCurrency string
+	Since          time.Time
+	Until          time.Time
+	Active         *bool
+	Sort           string // relevance, match, date, posted, title or company
+	Order          string // asc or desc
+	Limit          int
+	Offset         int
+	Page           int
+}
+
+func (q JobQuery) values() url.Values {
+	values := url.Values{}
+	setString := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	setInt := func(key string, value int) {
+		if value > 0 {
+			values.Set(key, strconv.Itoa(value))
+		}
+	}
+
+	setString("q", q.Q)
+	setString("semantic", q.Semantic)
+	setString("keywords", strings.Join(q.Keywords, ","))
+	setString("keyword_query", q.KeywordQuery)
+	setString("location", q.Location)
+	setString("source", strings.Join(q.Sources, ","))
+	setString("category", strings.Join(q.Categories, ","))
+	setString("seniority", strings.Join(q.Seniorities, ","))
+	setString("industry", strings.Join(q.Industries, ","))
+	setString("employment_type", strings.Join(q.EmploymentTypes, ","))
+	setString("remote_type", strings.Join(q.RemoteTypes, ","))
+	setInt("min_salary", q.MinSalary)
+	setInt("max_salary", q.MaxSalary)
+	setString("salary_currency", q.SalaryCurrency)
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Active != nil {
+		values.Set("active", strconv.FormatBool(*q.Active))
+	}
+	setString("sort", q.Sort)
+	setString("order", q.Order)
+	setInt("limit", q.Limit)
+	setInt("offset", q.Offset)
+	setInt("page", q.Page)
+
+	return values
+}
+
+// ListJobs searches stored jobs
+func (c *Client) ListJobs(ctx context.Context, query JobQuery) (*models.JobSearchResult, error) {
+	var result models.JobSearchResult
+	if err := c.do(ctx, http.MethodGet, "/jobs", query.values(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetJob returns a single job by ID
+func (c *Client) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	var job models.Job
+	if err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(id), nil, nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Ingest pushes a single posting into the store; it needs the ingest or
+// admin scope. The result reports whether the posting was already stored.
+func (c *Client) Ingest(ctx context.Context, req models.IngestRequest) (*models.IngestResult, error) {
+	var result models.IngestResult
+	if err := c.do(ctx, http.MethodPost, "/ingest", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetStats returns aggregate statistics over stored jobs
+func (c *Client) GetStats(ctx context.Context) (*models.JobStats, error) {
+	var stats models.JobStats
+	if err := c.do(ctx, http.MethodGet, "/stats", nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// StartScrape queues an asynchronous scrape. When the queue is full the
+// returned error is an *Error with status 429.
+func (c *Client) StartScrape(ctx context.Context, req models.ScrapeRequest) (*models.ScrapeRun, error) {
+	var run models.ScrapeRun
+	if err := c.do(ctx, http.MethodPost, "/scrape", nil, req, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListScrapeRuns returns scrape runs started through the API, newest first
+func (c *Client) ListScrapeRuns(ctx context.Context) ([]models.ScrapeRun, error) {
+	var runs []models.ScrapeRun
+	if err := c.do(ctx, http.MethodGet, "/scrape", nil, nil, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// GetScrapeRun returns the current state of a scrape run
+func (c *Client) GetScrapeRun(ctx context.Context, id string) (*models.ScrapeRun, error) {
+	var run models.ScrapeRun
+	if err := c.do(ctx, http.MethodGet, "/scrape/"+url.PathEscape(id), nil, nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// WaitForScrape polls a run every interval until it is done
+func (c *Client) WaitForScrape(ctx context.Context, id string, interval time.Duration) (*models.ScrapeRun, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		run, err := c.GetScrapeRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Done() {
+			return run, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return run, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CancelScrape withdraws a queued run. Runs that already started cannot be
+// cancelled and yield an *Error with status 409.
+func (c *Client) CancelScrape(ctx context.Context, id string) (*models.ScrapeRun, error) {
+	var run models.ScrapeRun
+	if err := c.do(ctx, http.MethodDelete, "/scrape/"+url.PathEscape(id), nil, nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListProviders returns configured API providers; validate also checks
+// their credentials
+func (c *Client) ListProviders(ctx context.Context, validate bool) ([]models.ProviderStatus, error) {
+	var query url.Values
+	if validate {
+		query = url.Values{"validate": {"true"}}
+	}
+
+	var providers []models.ProviderStatus
+	if err := c.do(ctx, http.MethodGet, "/providers/status", query, nil, &providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// ListBoards returns the configured job boards
+func (c *Client) ListBoards(ctx context.Context) ([]models.BoardSummary, error) {
+	var boards []models.BoardSummary
+	if err := c.do(ctx, http.MethodGet, "/boards", nil, nil, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// GetBoard returns a board's full config entry
+func (c *Client) GetBoard(ctx context.Context, name string) (*scraper.JobBoard, error) {
+	var board scraper.JobBoard
+	if err := c.do(ctx, http.MethodGet, "/boards/"+url.PathEscape(name), nil, nil, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// SaveBoard creates or replaces a board; it needs an admin token
+func (c *Client) SaveBoard(ctx context.Context, board scraper.JobBoard) (*scraper.JobBoard, error) {
+	var saved scraper.JobBoard
+	if err := c.do(ctx, http.MethodPut, "/boards/"+url.PathEscape(board.Name), nil, board, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteBoard removes a board; it needs an admin token
+func (c *Client) DeleteBoard(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/boards/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// ListProviderConfigs returns API provider configs with their keys redacted;
+// it needs an admin token
+func (c *Client) ListProviderConfigs(ctx context.Context) ([]api.APIConfig, error) {
+	var providers []api.APIConfig
+	if err := c.do(ctx, http.MethodGet, "/providers", nil, nil, &providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// SaveProvider creates or replaces an API provider; empty keys keep the
+// stored ones. It needs an admin token.
+func (c *Client) SaveProvider(ctx context.Context, provider api.APIConfig) (*api.APIConfig, error) {
+	var saved api.APIConfig
+	if err := c.do(ctx, http.MethodPut, "/providers/"+url.PathEscape(provider.Name), nil, provider, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteProvider removes an API provider; it needs an admin token
+func (c *Client) DeleteProvider(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/providers/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// Me returns the profile of the user the client's token belongs to
+func (c *Client) Me(ctx context.Context) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodGet, "/me", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile changes the caller's name, email or notification settings
+func (c *Client) UpdateProfile(ctx context.Context, update models.ProfileUpdate) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPatch, "/me", nil, update, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListUsers returns all user accounts; it needs an admin token
+func (c *Client) ListUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	if err := c.do(ctx, http.MethodGet, "/users", nil, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListApplications returns the caller's application statuses; a non-empty
+// status keeps only applications in that state
+func (c *Client) ListApplications(ctx context.Context, status string) ([]models.Application, error) {
+	var query url.Values
+	if status != "" {
+		query = url.Values{"status": {status}}
+	}
+
+	var applications []models.Application
+	if err := c.do(ctx, http.MethodGet, "/applications", query, nil, &applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// SetApplication records the caller's application status for a job
+func (c *Client) SetApplication(ctx context.Context, jobID string, req models.ApplicationRequest) (*models.Application, error) {
+	var application models.Application
+	if err := c.do(ctx, http.MethodPut, "/applications/"+url.PathEscape(jobID), nil, req, &application); err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// DeleteApplication clears the caller's application status for a job
+func (c *Client) DeleteApplication(ctx context.Context, jobID string) error {
+	return c.do(ctx, http.MethodDelete, "/applications/"+url.PathEscape(jobID), nil, nil, nil)
+}
+
+// ListWebhooks returns the caller's webhook subscriptions without their secrets
+func (c *Client) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook registers a webhook. The returned webhook carries the signing
+// secret, which later calls do not reveal.
+func (c *Client) CreateWebhook(ctx context.Context, req models.WebhookRequest) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := c.do(ctx, http.MethodPost, "/webhooks", nil, req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook removes one of the caller's webhooks
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// TestWebhook sends a ping delivery to a webhook and reports the outcome
+func (c *Client) TestWebhook(ctx context.Context, id string) (*models.WebhookTestResult, error) {
+	var result models.WebhookTestResult
+	if err := c.do(ctx, http.MethodPost, "/webhooks/"+url.PathEscape(id)+"/test", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GraphQLError is one entry of a GraphQL response's errors list
+type GraphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// GraphQL runs a query or mutation and decodes its data into out. Field
+// errors are returned alongside whatever data was resolved.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) ([]GraphQLError, error) {
+	body := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		body["variables"] = variables
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", nil, body, &response); err != nil {
+		return nil, err
+	}
+
+	if out != nil && len(response.Data) > 0 && string(response.Data) != "null" {
+		if err := json.Unmarshal(response.Data, out); err != nil {
+			return response.Errors, fmt.Errorf("failed to decode GraphQL data: %w", err)
+		}
+	}
+	return response.Errors, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		var decoded struct {
+			Error  string         `json:"error"`
+			Errors []GraphQLError `json:"errors"`
+		}
+		if json.Unmarshal(data, &decoded) == nil {
+			switch {
+			case decoded.Error != "":
+				apiErr.Message = decoded.Error
+			case len(decoded.Errors) > 0:
+				apiErr.Message = decoded.Errors[0].Message
+			default:
+				apiErr.Message = http.StatusText(resp.StatusCode)
+			}
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
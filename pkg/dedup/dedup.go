@@ -0,0 +1,277 @@
+// Package dedup finds cross-board duplicates whose wording differs too much
+// for title and company matching, by comparing description embeddings.
+package dedup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the deduplication section of GlobalSettings. Model names an
+// embedding model, e.g. nomic-embed-text on Ollama or
+// text-embedding-3-small on OpenAI, and defaults to the llm section's
+// embeddingModel.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// Threshold is the cosine similarity at or above which two postings are
+	// the same job. Defaults to 0.93.
+	Threshold float64 `json:"threshold,omitempty"`
+	// WindowDays limits the comparison to jobs seen in that many days.
+	// Defaults to 30.
+	WindowDays int `json:"windowDays,omitempty"`
+	// BatchSize is the number of texts per embeddings request. Defaults to 32.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// maxEmbedDescription keeps texts within embedding models' context
+const maxEmbedDescription = 4000
+
+// Detector maps near-duplicate postings onto the stored job they repeat
+type Detector struct {
+	config    Config
+	client    ai.LLMClient
+	cachePath string
+	logger    *logrus.Logger
+
+	mutex sync.Mutex
+	// vectors are unit-length embeddings by job ID
+	vectors map[string][]float32
+}
+
+// New validates config, filling in what it leaves out from defaults, and
+// loads the embeddings cached at cachePath; a nil or disabled config returns
+// a nil Detector
+func New(config *Config, defaults *ai.Defaults, cachePath string, logger *logrus.Logger) (*Detector, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, true)
+	client, err := ai.NewClient(c.Config, "deduplication")
+	if err != nil {
+		return nil, err
+	}
+	if c.Threshold <= 0 {
+		c.Threshold = 0.93
+	}
+	if c.Threshold > 1 {
+		return nil, fmt.Errorf("dedup threshold must be at most 1")
+	}
+	if c.WindowDays <= 0 {
+		c.WindowDays = 30
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 32
+	}
+
+	detector := &Detector{config: c, client: client, cachePath: cachePath, logger: logger}
+	if detector.vectors, err = loadVectors(cachePath); err != nil {
+		return nil, err
+	}
+	return detector, nil
+}
+
+// Recent returns the jobs Resolve compares scraped jobs with: those with a
+// description seen within the window
+func (d *Detector) Recent(jobs []models.Job) []models.Job {
+	cutoff := time.Now().AddDate(0, 0, -d.config.WindowDays)
+	var recent []models.Job
+	for i := range jobs {
+		if lastSeen(&jobs[i]).After(cutoff) && strings.TrimSpace(jobs[i].Description) != "" {
+			recent = append(recent, jobs[i])
+		}
+	}
+	return recent
+}
+
+// Resolve gives each scraped job that is new by title and company but whose
+// description embedding is within the threshold of a stored job, or of an
+// earlier job in the same scrape, that job's ID, so storage merges the two.
+// It returns how many jobs it remapped. Jobs are left as they are when the
+// embeddings cannot be fetched.
+//
+// Jobs are new when not in stored and, if seen is not nil, certainly not in
+// the index; stored then need only hold the Recent jobs and those of the
+// scrape not yet stored.
+func (d *Detector) Resolve(ctx context.Context, stored, scraped []models.Job, seen *SeenIndex) (int, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	known := make(map[string]bool, len(stored)*2)
+	for i := range stored {
+		known[stored[i].ID] = true
+		known[postingKey(&stored[i])] = true
+	}
+
+	var candidates []*models.Job
+	for i := range scraped {
+		job := &scraped[i]
+		if known[job.ID] || known[postingKey(job)] || strings.TrimSpace(job.Description) == "" {
+			continue
+		}
+		if seen == nil || !seen.Seen(job) {
+			candidates = append(candidates, job)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	recentJobs := d.Recent(stored)
+	recent := make([]*models.Job, len(recentJobs))
+	for i := range recentJobs {
+		recent[i] = &recentJobs[i]
+	}
+
+	if err := d.embed(ctx, append(recent, candidates...)); err != nil {
+		return 0, err
+	}
+
+	// Candidates are compared with recent stored jobs and with the
+	// candidates before them, since boards often repeat a posting in one run
+	remapped := 0
+	pool := recent
+	for _, job := range candidates {
+		vector := d.vectors[job.ID]
+		if vector == nil {
+			continue
+		}
+		var match *models.Job
+		best := d.config.Threshold
+		for _, other := range pool {
+			if other.ID == job.ID {
+				continue
+			}
+			if similarity := ai.Dot(vector, d.vectors[other.ID]); similarity >= best {
+				match, best = other, similarity
+			}
+		}
+		if match == nil {
+			pool = append(pool, job)
+			continue
+		}
+
+		d.logger.WithField("job_id", job.ID).Debugf("%s at %s (%s) duplicates %s at %s (%s), similarity %.3f",
+			job.Title, job.Company, job.Source, match.Title, match.Company, match.Source, best)
+		delete(d.vectors, job.ID)
+		job.ID = match.ID
+		remapped++
+	}
+
+	d.prune(stored, scraped)
+	if err := d.save(); err != nil {
+		d.logger.Errorf("Failed to save embedding cache: %v", err)
+	}
+	return remapped, nil
+}
+
+// embed fetches the embeddings missing for jobs
+func (d *Detector) embed(ctx context.Context, jobs []*models.Job) error {
+	var missing []*models.Job
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		if d.vectors[job.ID] == nil && !seen[job.ID] {
+			missing = append(missing, job)
+			seen[job.ID] = true
+		}
+	}
+
+	for start := 0; start < len(missing); start += d.config.BatchSize {
+		batch := missing[start:min(start+d.config.BatchSize, len(missing))]
+		texts := make([]string, len(batch))
+		for i, job := range batch {
+			texts[i] = embeddingText(job)
+		}
+		vectors, err := d.client.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed job descriptions: %w", err)
+		}
+		for i, job := range batch {
+			d.vectors[job.ID] = ai.Normalize(vectors[i])
+		}
+	}
+	return nil
+}
+
+// prune drops the embeddings of jobs no longer stored or scraped
+func (d *Detector) prune(stored, scraped []models.Job) {
+	keep := make(map[string]bool, len(stored)+len(scraped))
+	for _, jobs := range [][]models.Job{stored, scraped} {
+		for i := range jobs {
+			keep[jobs[i].ID] = true
+		}
+	}
+	for id := range d.vectors {
+		if !keep[id] {
+			delete(d.vectors, id)
+		}
+	}
+}
+
+func embeddingText(job *models.Job) string {
+	return job.Title + "\n" + job.Company + "\n\n" + ai.Truncate(job.Description, maxEmbedDescription)
+}
+
+// postingKey is the title and company key storage matches postings by
+func postingKey(job *models.Job) string {
+	return models.PostingKey(job)
+}
+
+func lastSeen(job *models.Job) time.Time {
+	if job.UpdatedAt.After(job.ScrapedAt) {
+		return job.UpdatedAt
+	}
+	return job.ScrapedAt
+}
+
+// The cache maps job IDs to vectors encoded with ai.EncodeVector
+func loadVectors(path string) (map[string][]float32, error) {
+	vectors := make(map[string][]float32)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return vectors, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedding cache: %w", err)
+	}
+
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding cache: %w", err)
+	}
+	for id, value := range encoded {
+		if vector, err := ai.DecodeVector(value); err == nil {
+			vectors[id] = vector
+		}
+	}
+	return vectors, nil
+}
+
+func (d *Detector) save() error {
+	encoded := make(map[string]string, len(d.vectors))
+	for id, vector := range d.vectors {
+		encoded[id] = ai.EncodeVector(vector)
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(d.cachePath+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(d.cachePath+".tmp", d.cachePath)
+}
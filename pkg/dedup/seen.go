@@ -0,0 +1,179 @@
+package dedup
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"sync"
+
+	"hire.ai/pkg/models"
+)
+
+// seenFalsePositives is the false positive rate the index is sized for
+const seenFalsePositives = 0.01
+
+// minSeenCapacity keeps a small store's index from filling up at once
+const minSeenCapacity = 10000
+
+// seenVersion is bumped when the keys added for a job change, so indexes
+// saved before are rebuilt
+const seenVersion = 2
+
+// SeenIndex is a bloom filter of the IDs, title and company keys and links
+// of stored jobs, so scrapes and storage can tell jobs that are certainly
+// new from ones that may be stored without scanning the store. Seen may
+// wrongly report about one job in a hundred as stored, but never misses one
+// that was added.
+type SeenIndex struct {
+	path string
+
+	mutex    sync.RWMutex
+	bits     []byte
+	hashes   int
+	capacity int // keys the index was sized for
+	count    int // keys added
+}
+
+// seenFile is the persisted form of a SeenIndex
+type seenFile struct {
+	Version  int    `json:"version"`
+	Bits     []byte `json:"bits"`
+	Hashes   int    `json:"hashes"`
+	Capacity int    `json:"capacity"`
+	Count    int    `json:"count"`
+}
+
+// BuildSeenIndex creates an index of jobs, sized with room to grow, that
+// saves to path
+func BuildSeenIndex(path string, jobs []models.Job) *SeenIndex {
+	// Each job adds up to three keys, and the store may grow to twice its
+	// size
+	capacity := max(6*len(jobs), minSeenCapacity)
+	bits := int(math.Ceil(-float64(capacity) * math.Log(seenFalsePositives) / (math.Ln2 * math.Ln2)))
+	s := &SeenIndex{
+		path:     path,
+		bits:     make([]byte, (bits+7)/8),
+		hashes:   max(1, int(math.Round(float64(bits)/float64(capacity)*math.Ln2))),
+		capacity: capacity,
+	}
+	s.Add(jobs)
+	return s
+}
+
+// LoadSeenIndex reads the index saved at path. It returns nil when there is
+// none yet, or when the index is full or outdated and should be rebuilt.
+func LoadSeenIndex(path string) (*SeenIndex, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seen index: %w", err)
+	}
+
+	var file seenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse seen index: %w", err)
+	}
+	if file.Version != seenVersion || len(file.Bits) == 0 || file.Hashes <= 0 || file.Count > file.Capacity {
+		return nil, nil
+	}
+	return &SeenIndex{
+		path:     path,
+		bits:     file.Bits,
+		hashes:   file.Hashes,
+		capacity: file.Capacity,
+		count:    file.Count,
+	}, nil
+}
+
+// Add records jobs as stored, under their ID, their title and company and
+// their link
+func (s *SeenIndex) Add(jobs []models.Job) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range jobs {
+		for _, key := range seenKeys(&jobs[i]) {
+			added := false
+			for _, bit := range s.positions(key) {
+				if s.bits[bit/8]&(1<<(bit%8)) == 0 {
+					s.bits[bit/8] |= 1 << (bit % 8)
+					added = true
+				}
+			}
+			// Jobs found again are not counted twice
+			if added {
+				s.count++
+			}
+		}
+	}
+}
+
+// Seen reports whether job may be stored, by ID, by title and company or by
+// link, the ways storage matches a job to its stored copy; a false answer
+// is certain
+func (s *SeenIndex) Seen(job *models.Job) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, key := range seenKeys(job) {
+		if s.contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// seenKeys are the keys a job is indexed under. Links are prefixed, so one
+// cannot collide with an ID.
+func seenKeys(job *models.Job) []string {
+	keys := []string{job.ID, postingKey(job)}
+	if link := models.LinkKey(job.Link); link != "" {
+		keys = append(keys, "link:"+link)
+	}
+	return keys
+}
+
+func (s *SeenIndex) contains(key string) bool {
+	for _, bit := range s.positions(key) {
+		if s.bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// positions are the bits for key, derived from one 128-bit hash by double
+// hashing
+func (s *SeenIndex) positions(key string) []uint64 {
+	h := fnv.New128a()
+	h.Write([]byte(key))
+	sum := h.Sum(nil)
+	h1, h2 := binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])|1
+
+	size := uint64(len(s.bits)) * 8
+	positions := make([]uint64, s.hashes)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % size
+	}
+	return positions
+}
+
+// Save writes the index to its path
+func (s *SeenIndex) Save() error {
+	s.mutex.RLock()
+	data, err := json.Marshal(seenFile{Version: seenVersion, Bits: s.bits, Hashes: s.hashes, Capacity: s.capacity, Count: s.count})
+	s.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(s.path+".tmp", s.path)
+}
@@ -0,0 +1,37 @@
+package dedup
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"hire.ai/pkg/models"
+)
+
+// seenJobs makes n postings numbered from start, with the fields the seen
+// index keys on
+func seenJobs(start, n int) []models.Job {
+	jobs := make([]models.Job, n)
+	for i := range jobs {
+		k := start + i
+		jobs[i] = models.Job{
+			ID:      fmt.Sprintf("job-%d", k),
+			Title:   fmt.Sprintf("Backend Engineer %d", k),
+			Company: fmt.Sprintf("Company %d", k%500),
+			Link:    fmt.Sprintf("https://jobs.example.com/postings/%d", k),
+		}
+	}
+	return jobs
+}
+
+// BenchmarkSeenIndex times telling scraped jobs, half of them stored, from
+// new ones against an index of 100,000 jobs
+func BenchmarkSeenIndex(b *testing.B) {
+	seen := BuildSeenIndex(filepath.Join(b.TempDir(), "seen.json"), seenJobs(0, 100000))
+	scraped := seenJobs(50000, 100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		seen.Seen(&scraped[i%len(scraped)])
+	}
+}
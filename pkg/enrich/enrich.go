@@ -0,0 +1,258 @@
+// Package enrich reads structured details from job descriptions with an
+// LLM: required skills, tech stack, years of experience, visa sponsorship
+// and seniority. Postings are sent several to a request, and each run
+// stops at a job, token or cost cap.
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/classify"
+	"hire.ai/pkg/models"
+)
+
+// Config is the enrichment section of GlobalSettings
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// BatchSize is how many postings one request carries. Defaults to 5.
+	BatchSize int `json:"batchSize,omitempty"`
+	// MaxJobsPerRun bounds the jobs enriched after one scrape. Defaults
+	// to 50.
+	MaxJobsPerRun int `json:"maxJobsPerRun,omitempty"`
+	// MaxTokensPerRun stops a run before a request would take its prompt
+	// and completion tokens past this. Defaults to 200000.
+	MaxTokensPerRun int `json:"maxTokensPerRun,omitempty"`
+	// MaxCostPerRun, in the currency the prices are in, stops a run the
+	// same way once spending reaches it. It needs the model's prices per
+	// million prompt and completion tokens; zero leaves cost uncapped.
+	MaxCostPerRun            float64 `json:"maxCostPerRun,omitempty"`
+	PromptCostPerMillion     float64 `json:"promptCostPerMillion,omitempty"`
+	CompletionCostPerMillion float64 `json:"completionCostPerMillion,omitempty"`
+}
+
+const (
+	// maxPromptDescription keeps a batch within small local models' context
+	maxPromptDescription = 4000
+	// maxListItems bounds the skills and tools kept per job
+	maxListItems = 15
+	// replyTokensPerJob is the completion tokens budgeted for each job's
+	// answer before a request is sent
+	replyTokensPerJob = 150
+)
+
+// task is the name the ai package accounts the requests under
+const task = "enrichment"
+
+var systemPrompt = fmt.Sprintf(`You read job postings and extract details. Each posting starts with a line "ID: ..." and you answer for every posting. Reply with a single JSON object and nothing else:
+{"jobs": [{"id": "...", "required_skills": ["..."], "tech_stack": ["..."], "years_experience": 3, "visa_sponsorship": "yes", "seniority": "senior"}]}
+required_skills are the skills the posting says a candidate must have, as short names. tech_stack is the languages, frameworks, databases and tools the team uses. years_experience is the fewest years of experience asked for, or 0 when none is stated. visa_sponsorship is yes, no or unknown when the posting does not say. seniority is one of: %s.`,
+	strings.Join(classify.Seniorities(), ", "))
+
+// Enricher calls the configured LLM
+type Enricher struct {
+	config Config
+	client ai.LLMClient
+	logger *logrus.Logger
+}
+
+// New validates config, filling in what it leaves out from defaults; a nil
+// or disabled config returns a nil Enricher
+func New(config *Config, defaults *ai.Defaults, logger *logrus.Logger) (*Enricher, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, false)
+	client, err := ai.NewClient(c.Config, task)
+	if err != nil {
+		return nil, err
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 5
+	}
+	if c.MaxJobsPerRun <= 0 {
+		c.MaxJobsPerRun = 50
+	}
+	if c.MaxTokensPerRun <= 0 {
+		c.MaxTokensPerRun = 200000
+	}
+	if c.MaxCostPerRun < 0 || c.PromptCostPerMillion < 0 || c.CompletionCostPerMillion < 0 {
+		return nil, fmt.Errorf("enrichment costs must not be negative")
+	}
+	if c.MaxCostPerRun > 0 && c.PromptCostPerMillion == 0 && c.CompletionCostPerMillion == 0 {
+		return nil, fmt.Errorf("enrichment maxCostPerRun needs promptCostPerMillion or completionCostPerMillion")
+	}
+
+	return &Enricher{config: c, client: client, logger: logger}, nil
+}
+
+// EnrichAll fills in Enrichment for jobs that have a description but no
+// enrichment yet, up to limit (MaxJobsPerRun when limit is 0), and returns
+// how many it enriched. It stops early at the token or cost cap. Failed
+// batches are logged and leave their jobs as they were.
+func (e *Enricher) EnrichAll(ctx context.Context, jobs []models.Job, limit int) int {
+	if limit <= 0 {
+		limit = e.config.MaxJobsPerRun
+	}
+
+	var pending []*models.Job
+	for i := range jobs {
+		if jobs[i].Enrichment == nil && strings.TrimSpace(jobs[i].Description) != "" {
+			pending = append(pending, &jobs[i])
+		}
+	}
+	if len(pending) > limit {
+		e.logger.Warnf("Enriching %d of %d jobs; the rest stay unenriched", limit, len(pending))
+		pending = pending[:limit]
+	}
+
+	// Spending is measured from the ai package's accounting of this task,
+	// which caching leaves untouched
+	start := ai.UsageByTask()[task]
+	enriched := 0
+	for offset := 0; offset < len(pending); offset += e.config.BatchSize {
+		if ctx.Err() != nil {
+			break
+		}
+		batch := pending[offset:min(offset+e.config.BatchSize, len(pending))]
+		text := prompt(batch)
+
+		used := ai.UsageByTask()[task]
+		promptTokens := used.PromptTokens - start.PromptTokens + estimateTokens(systemPrompt+text)
+		completionTokens := used.CompletionTokens - start.CompletionTokens + replyTokensPerJob*len(batch)
+		if promptTokens+completionTokens > e.config.MaxTokensPerRun {
+			e.logger.Warnf("Enrichment stopped at its cap of %d tokens with %d jobs left", e.config.MaxTokensPerRun, len(pending)-offset)
+			break
+		}
+		if cost := e.cost(promptTokens, completionTokens); e.config.MaxCostPerRun > 0 && cost > e.config.MaxCostPerRun {
+			e.logger.Warnf("Enrichment stopped at its cost cap of %.2f with %d jobs left", e.config.MaxCostPerRun, len(pending)-offset)
+			break
+		}
+
+		count, err := e.enrichBatch(ctx, batch, text)
+		if err != nil {
+			e.logger.Warnf("Failed to enrich %d jobs: %v", len(batch), err)
+			continue
+		}
+		enriched += count
+	}
+	return enriched
+}
+
+// cost prices tokens at the configured rates
+func (e *Enricher) cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*e.config.PromptCostPerMillion + float64(completionTokens)*e.config.CompletionCostPerMillion) / 1e6
+}
+
+// estimateTokens guesses the tokens of text at about four characters each
+func estimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// answer is one job's details as the LLM gives them
+type answer struct {
+	ID              string   `json:"id"`
+	RequiredSkills  []string `json:"required_skills"`
+	TechStack       []string `json:"tech_stack"`
+	YearsExperience float64  `json:"years_experience"`
+	VisaSponsorship string   `json:"visa_sponsorship"`
+	Seniority       string   `json:"seniority"`
+}
+
+// enrichBatch asks the LLM about the batch's jobs at once and returns how
+// many it answered for
+func (e *Enricher) enrichBatch(ctx context.Context, batch []*models.Job, text string) (int, error) {
+	reply, err := e.client.Complete(ctx, systemPrompt, text)
+	if err != nil {
+		return 0, err
+	}
+	var answers struct {
+		Jobs []answer `json:"jobs"`
+	}
+	if err := ai.DecodeJSON(reply, &answers); err != nil {
+		return 0, err
+	}
+
+	byID := make(map[string]*answer, len(answers.Jobs))
+	for i := range answers.Jobs {
+		byID[strings.TrimSpace(answers.Jobs[i].ID)] = &answers.Jobs[i]
+	}
+	now := time.Now()
+	count := 0
+	for _, job := range batch {
+		a, ok := byID[job.ID]
+		if !ok {
+			e.logger.WithField("job_id", job.ID).Debugf("No enrichment returned for %s", job.Title)
+			continue
+		}
+		job.Enrichment = &models.JobEnrichment{
+			RequiredSkills:  cleanList(a.RequiredSkills),
+			TechStack:       cleanList(a.TechStack),
+			YearsExperience: max(0, int(a.YearsExperience)),
+			VisaSponsorship: visaSponsorship(a.VisaSponsorship),
+			Seniority:       seniority(a.Seniority),
+			Model:           e.client.Model(),
+			CreatedAt:       now,
+		}
+		count++
+	}
+	return count, nil
+}
+
+func prompt(batch []*models.Job) string {
+	var text strings.Builder
+	for i, job := range batch {
+		if i > 0 {
+			text.WriteString("\n\n---\n\n")
+		}
+		fmt.Fprintf(&text, "ID: %s\nTitle: %s\nCompany: %s\n", job.ID, job.Title, job.Company)
+		fmt.Fprintf(&text, "\nDescription:\n%s", ai.Truncate(job.Description, maxPromptDescription))
+	}
+	return text.String()
+}
+
+// cleanList trims the items of list and drops empty and repeated ones
+func cleanList(list []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if key := strings.ToLower(item); item != "" && !seen[key] {
+			seen[key] = true
+			cleaned = append(cleaned, item)
+		}
+	}
+	if len(cleaned) > maxListItems {
+		cleaned = cleaned[:maxListItems]
+	}
+	return cleaned
+}
+
+func visaSponsorship(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "true":
+		return models.VisaSponsorshipYes
+	case "no", "false":
+		return models.VisaSponsorshipNo
+	}
+	return models.VisaSponsorshipUnknown
+}
+
+// seniority returns value if it is a known seniority, else ""
+func seniority(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, s := range classify.Seniorities() {
+		if value == s {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+// Package errs names the causes of failed scrapes, API searches and storage
+// lookups, so callers can react with errors.Is instead of matching messages.
+// Errors from the scraper, providers and storage wrap or match these.
+package errs
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrRateLimited means the site or API asked us to slow down
+	ErrRateLimited = errors.New("rate limited")
+	// ErrBlocked means the site refused the client, as it does scrapers
+	ErrBlocked = errors.New("blocked")
+	// ErrSelectorMiss means the page loaded but the board's selectors found
+	// no jobs, usually because the site's markup changed
+	ErrSelectorMiss = errors.New("selectors matched nothing")
+	// ErrAuth means credentials were missing or rejected
+	ErrAuth = errors.New("authentication failed")
+	// ErrQuotaExhausted means an API plan's request allowance is used up
+	ErrQuotaExhausted = errors.New("quota exhausted")
+	// ErrCircuitOpen means an API provider failed too often in a row and
+	// is rested until its circuit breaker lets a probe through
+	ErrCircuitOpen = errors.New("circuit open")
+	// ErrBudgetSpent means a domain's hourly request budget is used up, so
+	// its requests wait for a later run
+	ErrBudgetSpent = errors.New("request budget spent")
+	// ErrNotFound means the requested record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrAllSourcesFailed means a scrape run found no jobs because every
+	// source it tried failed
+	ErrAllSourcesFailed = errors.New("all sources failed")
+	// ErrConfig means the configuration, or an environment override of it,
+	// is invalid
+	ErrConfig = errors.New("invalid configuration")
+)
+
+// APIStatus returns the cause an API's HTTP status signals, or nil for
+// statuses without one
+func APIStatus(status int) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrAuth
+	case http.StatusPaymentRequired:
+		return ErrQuotaExhausted
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// SiteStatus returns the cause a scraped site's HTTP status signals, or nil
+// for statuses without one. Sites answer scrapers they refuse with 403 or
+// 503 rather than asking for credentials.
+func SiteStatus(status int) error {
+	switch status {
+	case http.StatusForbidden, http.StatusServiceUnavailable:
+		return ErrBlocked
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// multiError is several failures reported as one, each still reachable
+// through errors.Is and errors.As
+type multiError []error
+
+func (m multiError) Error() string {
+	messages := make([]string, len(m))
+	for i, err := range m {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (m multiError) Unwrap() []error {
+	return m
+}
+
+// Join combines failures into one error whose message separates theirs
+// with semicolons, or returns nil when there are none
+func Join(errors []error) error {
+	if len(errors) == 0 {
+		return nil
+	}
+	return multiError(append([]error(nil), errors...))
+}
+
+// causeError is an error that also matches a cause its message leaves out
+type causeError struct {
+	err   error
+	cause error
+}
+
+func (e causeError) Error() string {
+	return e.err.Error()
+}
+
+func (e causeError) Unwrap() []error {
+	return []error{e.err, e.cause}
+}
+
+// WithCause returns err matching cause through errors.Is as well, with its
+// message unchanged, or nil when err is nil
+func WithCause(err, cause error) error {
+	if err == nil {
+		return nil
+	}
+	return causeError{err: err, cause: cause}
+}
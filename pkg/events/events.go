@@ -0,0 +1,116 @@
+// Package events publishes an event for each job a scrape stores or finds
+// again to Kafka or NATS, so downstream systems can follow the job stream
+// without polling exports. Brokers are configured in the "events" section of
+// the config file's globalSettings.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// Event types
+const (
+	// JobCreated is sent for a job stored for the first time
+	JobCreated = "job_created"
+	// JobUpdated is sent for a stored job that a scrape found again
+	JobUpdated = "job_updated"
+)
+
+// defaultTopic prefixes the topics events go to when Config.Topic is unset
+const defaultTopic = "hire.jobs"
+
+// Config is the events section of GlobalSettings
+type Config struct {
+	// Topic prefixes the Kafka topic or NATS subject of each event: created
+	// jobs go to <topic>.created and updated ones to <topic>.updated.
+	// Defaults to "hire.jobs".
+	Topic string       `json:"topic,omitempty"`
+	Kafka *KafkaConfig `json:"kafka,omitempty"`
+	NATS  *NATSConfig  `json:"nats,omitempty"`
+}
+
+// Event is the JSON message published for one job
+type Event struct {
+	ID    string     `json:"id"`
+	Type  string     `json:"type"`
+	Time  time.Time  `json:"time"`
+	RunID string     `json:"run_id,omitempty"`
+	Job   models.Job `json:"job"`
+}
+
+// Publisher sends events to a broker
+type Publisher interface {
+	// Name identifies the publisher in logs
+	Name() string
+
+	// Publish sends events of one type to the topic for that type
+	Publish(ctx context.Context, eventType string, events []Event) error
+}
+
+// New creates the publishers enabled in config; a nil config enables none
+func New(config *Config) ([]Publisher, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	topic := config.Topic
+	if topic == "" {
+		topic = defaultTopic
+	}
+
+	var publishers []Publisher
+	if config.Kafka != nil && config.Kafka.Enabled {
+		kafka, err := NewKafkaPublisher(*config.Kafka, topic)
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, kafka)
+	}
+	if config.NATS != nil && config.NATS.Enabled {
+		nats, err := NewNATSPublisher(*config.NATS, topic)
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, nats)
+	}
+	return publishers, nil
+}
+
+// NewEvents wraps jobs in events of the given type, stamped with the run
+// that stored them
+func NewEvents(eventType, runID string, jobs []models.Job) []Event {
+	now := time.Now().UTC()
+	events := make([]Event, len(jobs))
+	for i := range jobs {
+		events[i] = Event{ID: newEventID(), Type: eventType, Time: now, RunID: runID, Job: jobs[i]}
+	}
+	return events
+}
+
+// topicFor returns the topic events of eventType go to under prefix
+func topicFor(prefix, eventType string) (string, error) {
+	switch eventType {
+	case JobCreated:
+		return prefix + ".created", nil
+	case JobUpdated:
+		return prefix + ".updated", nil
+	default:
+		return "", fmt.Errorf("unknown event type %q", eventType)
+	}
+}
+
+// newEventID returns a random identifier consumers can deduplicate
+// redelivered events by
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/transport"
+)
+
+// kafkaBatchSize caps the records sent to the REST proxy in one request
+const kafkaBatchSize = 100
+
+// KafkaConfig configures publishing to Kafka through a Confluent-compatible
+// REST proxy, which keeps the Kafka wire protocol out of the scraper
+type KafkaConfig struct {
+	Enabled bool `json:"enabled"`
+	// RestProxy is the base URL of the REST proxy, e.g.
+	// http://localhost:8082
+	RestProxy string `json:"restProxy"`
+	// Username and Password authenticate to the proxy with HTTP basic auth.
+	// Password falls back to the KAFKA_REST_PASSWORD environment variable.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// KafkaPublisher produces events as JSON records keyed by job ID, so a
+// job's events stay in order on one partition
+type KafkaPublisher struct {
+	config KafkaConfig
+	topic  string
+	client *http.Client
+}
+
+// NewKafkaPublisher validates config
+func NewKafkaPublisher(config KafkaConfig, topic string) (*KafkaPublisher, error) {
+	if _, err := url.ParseRequestURI(config.RestProxy); err != nil {
+		return nil, fmt.Errorf("invalid Kafka REST proxy %q: %w", config.RestProxy, err)
+	}
+	if config.Password == "" {
+		config.Password = os.Getenv("KAFKA_REST_PASSWORD")
+	}
+	return &KafkaPublisher{config: config, topic: topic, client: transport.NewClient(30 * time.Second)}, nil
+}
+
+func (p *KafkaPublisher) Name() string {
+	return "kafka"
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, eventType string, events []Event) error {
+	topic, err := topicFor(p.topic, eventType)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(events); start += kafkaBatchSize {
+		end := min(start+kafkaBatchSize, len(events))
+		if err := p.produce(ctx, topic, events[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+// produce sends one batch of records and checks the offset the proxy
+// reports for each, since a request can succeed with some records failed
+func (p *KafkaPublisher) produce(ctx context.Context, topic string, events []Event) error {
+	records := make([]kafkaRecord, len(events))
+	for i, event := range events {
+		records[i] = kafkaRecord{Key: event.Job.ID, Value: event}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	endpoint := strings.TrimRight(p.config.RestProxy, "/") + "/topics/" + url.PathEscape(topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if p.config.Username != "" {
+		req.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var result struct {
+		Message string `json:"message"`
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	parsed := json.Unmarshal(data, &result) == nil
+	if resp.StatusCode >= 300 {
+		if parsed && result.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, result.Message)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+
+	failed := 0
+	var firstErr string
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			if failed == 0 {
+				firstErr = offset.Error
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d records to %s failed: %s", failed, len(records), topic, firstErr)
+	}
+	return nil
+}
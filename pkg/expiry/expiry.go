@@ -0,0 +1,301 @@
+// Package expiry revisits the links of stored jobs and expires the postings
+// that have been taken down, so searches and exports stop listing them.
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+// Config is the expiry section of GlobalSettings
+type Config struct {
+	// Enabled revisits links in the background of serve and scrape -watch;
+	// the expire command works either way
+	Enabled bool `json:"enabled"`
+	// IntervalMinutes is the time between rounds of checks. Defaults to 360.
+	IntervalMinutes int `json:"intervalMinutes,omitempty"`
+	// BatchSize is the number of links revisited each round. Defaults to 50.
+	BatchSize int `json:"batchSize,omitempty"`
+	// RecheckHours is how long a job seen by a scrape or a check is left
+	// before its link is revisited. Defaults to 24.
+	RecheckHours int `json:"recheckHours,omitempty"`
+	// HeadOnly sends HEAD requests, which only catch removed pages (404 and
+	// 410) but download nothing; by default pages are read for closed
+	// notices too
+	HeadOnly bool `json:"headOnly,omitempty"`
+	// Phrases are added to the built-in notices of a closed posting, such
+	// as "no longer accepting applications"; they match case-insensitively
+	Phrases []string `json:"phrases,omitempty"`
+}
+
+// closedPhrases are the notices job boards and ATS pages show on postings
+// that have been filled or taken down
+var closedPhrases = []string{
+	"no longer accepting applications",
+	"no longer accepting candidates",
+	"this job is no longer available",
+	"this job is no longer open",
+	"this position is no longer available",
+	"this position has been filled",
+	"this job has expired",
+	"this job posting has expired",
+	"this posting has expired",
+	"job has been closed",
+	"the job you are looking for is no longer",
+	"position is closed",
+}
+
+// Fetch requests link and returns the response status with the page, which
+// is empty when head is set. The scraper's Revisit is one.
+type Fetch func(ctx context.Context, link string, head bool) (int, []byte, error)
+
+// Expiry is one job found taken down
+type Expiry struct {
+	JobID   string `json:"job_id"`
+	Title   string `json:"title"`
+	Company string `json:"company"`
+	Link    string `json:"link"`
+	Reason  string `json:"reason"`
+}
+
+// Result counts what a round of checks found
+type Result struct {
+	Checked int      `json:"checked"`
+	Expired []Expiry `json:"expired"`
+	// Failed links gave an error or an unexpected status, such as a block;
+	// they are retried once RecheckHours pass
+	Failed int `json:"failed"`
+	// Deferred links were left for a later round because their domain's
+	// request budget is spent
+	Deferred int `json:"deferred"`
+}
+
+// Checker revisits stored job links and expires the postings taken down
+type Checker struct {
+	config  Config
+	phrases []string
+	fetch   Fetch
+	store   storage.Storage
+	logger  *logrus.Logger
+	wg      sync.WaitGroup
+}
+
+// New creates a checker for the jobs in store, filling in what config leaves
+// out from defaults; a nil config checks with the defaults but never in the
+// background
+func New(config *Config, fetch Fetch, store storage.Storage, logger *logrus.Logger) *Checker {
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.IntervalMinutes <= 0 {
+		c.IntervalMinutes = 360
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 50
+	}
+	if c.RecheckHours <= 0 {
+		c.RecheckHours = 24
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	phrases := append([]string(nil), closedPhrases...)
+	for _, phrase := range c.Phrases {
+		if phrase = strings.ToLower(strings.TrimSpace(phrase)); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return &Checker{config: c, phrases: phrases, fetch: fetch, store: store, logger: logger}
+}
+
+// BatchSize is the number of links revisited each round
+func (c *Checker) BatchSize() int {
+	return c.config.BatchSize
+}
+
+// Start checks a batch of links at once and then every interval until ctx
+// is cancelled, when the config enables it. Wait blocks until it has
+// stopped.
+func (c *Checker) Start(ctx context.Context) {
+	if !c.config.Enabled {
+		return
+	}
+	interval := time.Duration(c.config.IntervalMinutes) * time.Minute
+	c.logger.Infof("Checking stored job links for expired postings every %v", interval)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := c.Check(ctx, c.config.BatchSize); err != nil && ctx.Err() == nil {
+				c.logger.Errorf("Checking job links failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the background checks started by Start have stopped
+func (c *Checker) Wait() {
+	c.wg.Wait()
+}
+
+// Check revisits the links of up to limit active jobs that are due, those
+// neither scraped nor checked for longest first, and expires the postings
+// taken down
+func (c *Checker) Check(ctx context.Context, limit int) (*Result, error) {
+	jobs, err := c.store.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	due := c.due(jobs, time.Now())
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	result := &Result{}
+	var checked, expired []string
+	spent := make(map[string]bool)
+	for _, job := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		host := hostOf(job.Link)
+		if spent[host] {
+			result.Deferred++
+			continue
+		}
+
+		gone, reason, err := c.check(ctx, job.Link)
+		if errors.Is(err, errs.ErrBudgetSpent) {
+			spent[host] = true
+			result.Deferred++
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		checked = append(checked, job.ID)
+		result.Checked++
+		if err != nil {
+			result.Failed++
+			c.logger.WithField("job", job.ID).Debugf("Could not check %s: %v", job.Link, err)
+			continue
+		}
+		if gone {
+			expired = append(expired, job.ID)
+			result.Expired = append(result.Expired, Expiry{
+				JobID: job.ID, Title: job.Title, Company: job.Company, Link: job.Link, Reason: reason,
+			})
+		}
+	}
+
+	// What was checked is recorded even when the round was cut short
+	if err := c.store.MarkChecked(context.WithoutCancel(ctx), checked, expired, time.Now()); err != nil {
+		return result, fmt.Errorf("failed to record checks: %w", err)
+	}
+	if result.Checked > 0 || result.Deferred > 0 {
+		c.logger.Infof("Checked %d job links: %d expired, %d failed, %d deferred by request budgets",
+			result.Checked, len(result.Expired), result.Failed, result.Deferred)
+	}
+	return result, ctx.Err()
+}
+
+// due returns the active jobs with links not seen by a scrape or a check
+// within RecheckHours, the longest unseen first
+func (c *Checker) due(jobs []models.Job, now time.Time) []models.Job {
+	cutoff := now.Add(-time.Duration(c.config.RecheckHours) * time.Hour)
+	var due []models.Job
+	for _, job := range jobs {
+		if !job.IsActive || job.ExpiredAt != nil || hostOf(job.Link) == "" {
+			continue
+		}
+		if lastSeen(&job).After(cutoff) {
+			continue
+		}
+		due = append(due, job)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return lastSeen(&due[i]).Before(lastSeen(&due[j]))
+	})
+	return due
+}
+
+// check revisits link and reports whether the posting is gone, and why
+func (c *Checker) check(ctx context.Context, link string) (bool, string, error) {
+	status, body, err := c.fetch(ctx, link, c.config.HeadOnly)
+	if err == nil && c.config.HeadOnly && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		// Some servers refuse HEAD; their status only comes with the page
+		status, _, err = c.fetch(ctx, link, false)
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return true, fmt.Sprintf("status %d", status), nil
+	case status < 200 || status >= 300:
+		return false, "", fmt.Errorf("unexpected status %d", status)
+	}
+	if phrase := c.closedNotice(body); phrase != "" {
+		return true, fmt.Sprintf("page says %q", phrase), nil
+	}
+	return false, "", nil
+}
+
+// closedNotice returns the closed-posting phrase the page shows, if any
+func (c *Checker) closedNotice(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	text := strings.Join(strings.Fields(strings.ToLower(string(body))), " ")
+	for _, phrase := range c.phrases {
+		if strings.Contains(text, phrase) {
+			return phrase
+		}
+	}
+	return ""
+}
+
+// lastSeen is when a scrape or a check last looked at the job
+func lastSeen(job *models.Job) time.Time {
+	seen := job.UpdatedAt
+	if job.ScrapedAt.After(seen) {
+		seen = job.ScrapedAt
+	}
+	if job.CheckedAt != nil && job.CheckedAt.After(seen) {
+		seen = *job.CheckedAt
+	}
+	return seen
+}
+
+// hostOf returns the host of an http or https link, or "" for anything else
+func hostOf(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
@@ -1,6 +1,7 @@
 package export
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"os"
@@ -23,7 +24,14 @@ func NewCSVExporter(outputDir string) *CSVExporter {
 	}
 }
 
-func (e *CSVExporter) ExportJobs(jobs []models.Job, filename string) (string, error) {
+// ExportJobs writes jobs to filename in the output directory and returns its
+// path. If ctx is done before every row is written, the partial file is
+// removed and ctx's error returned.
+func (e *CSVExporter) ExportJobs(ctx context.Context, jobs []models.Job, filename string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(e.outputDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create output directory: %w", err)
@@ -65,7 +73,10 @@ func (e *CSVExporter) ExportJobs(jobs []models.Job, filename string) (string, er
 		"Keywords",
 		"Experience Level",
 		"Is Remote",
+		"Employment Type",
+		"Remote Type",
 		"Relevance Score",
+		"Posted At",
 		"Scraped At",
 		"Updated At",
 		"Is Active",
@@ -77,21 +88,33 @@ func (e *CSVExporter) ExportJobs(jobs []models.Job, filename string) (string, er
 
 	// Write job data
 	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			file.Close()
+			os.Remove(filePath)
+			return "", err
+		}
+		var postedAt string
+		if job.PostedAt != nil {
+			postedAt = models.InDisplayZone(*job.PostedAt).Format("2006-01-02 15:04:05")
+		}
 		record := []string{
 			job.ID,
 			job.Title,
 			job.Company,
 			job.Location,
 			job.Salary,
-			cleanDescription(job.Description),
+			jobDescription(job),
 			job.Link,
 			job.Source,
 			strings.Join(job.Keywords, "; "),
 			job.GetExperienceLevel(),
 			strconv.FormatBool(job.IsRemote()),
+			job.EmploymentType,
+			job.RemoteType,
 			fmt.Sprintf("%.2f", job.Relevance),
-			job.ScrapedAt.Format("2006-01-02 15:04:05"),
-			job.UpdatedAt.Format("2006-01-02 15:04:05"),
+			postedAt,
+			models.InDisplayZone(job.ScrapedAt).Format("2006-01-02 15:04:05"),
+			models.InDisplayZone(job.UpdatedAt).Format("2006-01-02 15:04:05"),
 			strconv.FormatBool(job.IsActive),
 		}
 
@@ -103,9 +126,9 @@ func (e *CSVExporter) ExportJobs(jobs []models.Job, filename string) (string, er
 	return filePath, nil
 }
 
-func (e *CSVExporter) ExportJobsWithStats(jobs []models.Job, stats *models.JobStats, filename string) (string, error) {
+func (e *CSVExporter) ExportJobsWithStats(ctx context.Context, jobs []models.Job, stats *models.JobStats, filename string) (string, error) {
 	// First export the jobs
-	jobsFile, err := e.ExportJobs(jobs, filename)
+	jobsFile, err := e.ExportJobs(ctx, jobs, filename)
 	if err != nil {
 		return "", err
 	}
@@ -132,7 +155,7 @@ func (e *CSVExporter) ExportJobsWithStats(jobs []models.Job, stats *models.JobSt
 	writer.Write([]string{"Metric", "Value"})
 	writer.Write([]string{"Total Jobs", strconv.Itoa(stats.TotalJobs)})
 	writer.Write([]string{"Recent Jobs (24h)", strconv.Itoa(stats.RecentJobs)})
-	writer.Write([]string{"Last Scraped", stats.LastScraped.Format("2006-01-02 15:04:05")})
+	writer.Write([]string{"Last Scraped", models.InDisplayZone(stats.LastScraped).Format("2006-01-02 15:04:05")})
 
 	// Empty row
 	writer.Write([]string{})
@@ -174,6 +197,14 @@ func (e *CSVExporter) ExportJobsWithStats(jobs []models.Job, stats *models.JobSt
 	return jobsFile, nil
 }
 
+// jobDescription prefers the short LLM summary over the full description
+func jobDescription(job models.Job) string {
+	if job.Summary != nil {
+		return strings.Join(job.Summary.Bullets(), " | ")
+	}
+	return cleanDescription(job.Description)
+}
+
 func cleanDescription(description string) string {
 	// Remove newlines and excessive whitespace for CSV
 	cleaned := strings.ReplaceAll(description, "\n", " ")
@@ -0,0 +1,332 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Output formats understood by Write. "table" is rendered by the caller
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatCSV   = "csv"
+)
+
+// ValidateFormat checks that format is one of the supported output formats
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML, FormatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s (use table, json, yaml or csv)", format)
+	}
+}
+
+// Write renders v in a machine-readable format. Field names always come from
+// the JSON tags, so json, yaml and csv output share the same stable names.
+func Write(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case FormatYAML:
+		node, err := toNode(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		writeYAML(&buf, node, 0, false)
+		_, err = w.Write(buf.Bytes())
+		return err
+	case FormatCSV:
+		node, err := toNode(v)
+		if err != nil {
+			return err
+		}
+		return writeCSV(w, node)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// field is one key of a JSON object, kept in document order
+type field struct {
+	key   string
+	value interface{}
+}
+
+// object is a JSON object whose fields keep their declaration order
+type object []field
+
+// toNode round-trips v through JSON into objects, slices and scalars so the
+// original struct field order is preserved (maps come out sorted)
+func toNode(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeNode(decoder)
+}
+
+func decodeNode(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var obj object
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeNode(decoder)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, field{key: keyToken.(string), value: value})
+			}
+			_, err := decoder.Token()
+			return obj, err
+		case '[':
+			list := []interface{}{}
+			for decoder.More() {
+				value, err := decodeNode(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			_, err := decoder.Token()
+			return list, err
+		}
+	}
+
+	return token, nil
+}
+
+func writeYAML(buf *bytes.Buffer, node interface{}, indent int, inList bool) {
+	pad := strings.Repeat("  ", indent)
+
+	switch n := node.(type) {
+	case object:
+		if len(n) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		if !inList {
+			buf.WriteString("\n")
+		}
+		for i, f := range n {
+			// The first key of a list item shares the "- " line
+			if !(inList && i == 0) {
+				buf.WriteString(pad)
+			}
+			buf.WriteString(yamlKey(f.key) + ":")
+			writeYAMLValue(buf, f.value, indent+1)
+		}
+	case []interface{}:
+		if len(n) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		for _, item := range n {
+			buf.WriteString(pad + "- ")
+			if obj, ok := item.(object); ok && len(obj) > 0 {
+				writeYAML(buf, obj, indent+1, true)
+				continue
+			}
+			writeYAMLScalarLine(buf, item, indent+1)
+		}
+	default:
+		writeYAMLScalarLine(buf, n, indent)
+	}
+}
+
+func writeYAMLValue(buf *bytes.Buffer, value interface{}, indent int) {
+	switch value.(type) {
+	case object, []interface{}:
+		writeYAML(buf, value, indent, false)
+	default:
+		buf.WriteString(" ")
+		writeYAMLScalarLine(buf, value, indent)
+	}
+}
+
+func writeYAMLScalarLine(buf *bytes.Buffer, value interface{}, indent int) {
+	switch v := value.(type) {
+	case object, []interface{}:
+		writeYAML(buf, v, indent, false)
+		return
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		buf.WriteString(yamlString(v))
+	default:
+		buf.WriteString(yamlString(fmt.Sprint(v)))
+	}
+	buf.WriteString("\n")
+}
+
+func yamlKey(key string) string {
+	if key == "" || strings.ContainsAny(key, ":#{}[],&*!|>'\"%@` ") {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// yamlString quotes strings that YAML would otherwise misread
+func yamlString(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+
+	if strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") ||
+		strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") ||
+		strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// writeCSV writes a list of objects as rows with a header taken from the
+// object keys; any other value is flattened into field/value pairs
+func writeCSV(w io.Writer, node interface{}) error {
+	writer := csv.NewWriter(w)
+
+	if list, ok := node.([]interface{}); ok {
+		var headers []string
+		seen := make(map[string]bool)
+		for _, item := range list {
+			if obj, ok := item.(object); ok {
+				for _, f := range obj {
+					if !seen[f.key] {
+						seen[f.key] = true
+						headers = append(headers, f.key)
+					}
+				}
+			}
+		}
+
+		if len(headers) == 0 {
+			headers = []string{"value"}
+		}
+		if err := writer.Write(headers); err != nil {
+			return err
+		}
+
+		for _, item := range list {
+			record := make([]string, len(headers))
+			obj, ok := item.(object)
+			if !ok {
+				record[0] = csvValue(item)
+			} else {
+				values := make(map[string]interface{}, len(obj))
+				for _, f := range obj {
+					values[f.key] = f.value
+				}
+				for i, header := range headers {
+					record[i] = csvValue(values[header])
+				}
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	} else {
+		rows := make(map[string]string)
+		flatten("", node, rows)
+
+		keys := make([]string, 0, len(rows))
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		writer.Write([]string{"field", "value"})
+		for _, key := range keys {
+			if err := writer.Write([]string{key, rows[key]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func flatten(prefix string, node interface{}, rows map[string]string) {
+	obj, ok := node.(object)
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		rows[prefix] = csvValue(node)
+		return
+	}
+
+	for _, f := range obj {
+		key := f.key
+		if prefix != "" {
+			key = prefix + "." + f.key
+		}
+		flatten(key, f.value, rows)
+	}
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, csvValue(item))
+		}
+		return strings.Join(parts, "; ")
+	case object:
+		var parts []string
+		for _, f := range v {
+			parts = append(parts, f.key+"="+csvValue(f.value))
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
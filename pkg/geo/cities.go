@@ -0,0 +1,236 @@
+package geo
+
+import "hire.ai/pkg/models"
+
+// city is an entry of the built-in gazetteer. Region is a US state's
+// postal code or, elsewhere, the country.
+type city struct {
+	name   string
+	region string
+	lat    float64
+	lon    float64
+}
+
+// cities are the places most postings name, so common searches and job
+// locations are placed without a geocoding service. Where two share a
+// name, the one listed first is taken for the bare name.
+var cities = []city{
+	// United States
+	{"New York", "NY", 40.7128, -74.0060},
+	{"Los Angeles", "CA", 34.0522, -118.2437},
+	{"Chicago", "IL", 41.8781, -87.6298},
+	{"Houston", "TX", 29.7604, -95.3698},
+	{"Phoenix", "AZ", 33.4484, -112.0740},
+	{"Philadelphia", "PA", 39.9526, -75.1652},
+	{"San Antonio", "TX", 29.4241, -98.4936},
+	{"San Diego", "CA", 32.7157, -117.1611},
+	{"Dallas", "TX", 32.7767, -96.7970},
+	{"San Jose", "CA", 37.3382, -121.8863},
+	{"Austin", "TX", 30.2672, -97.7431},
+	{"Jacksonville", "FL", 30.3322, -81.6557},
+	{"Fort Worth", "TX", 32.7555, -97.3308},
+	{"Columbus", "OH", 39.9612, -82.9988},
+	{"Charlotte", "NC", 35.2271, -80.8431},
+	{"San Francisco", "CA", 37.7749, -122.4194},
+	{"Indianapolis", "IN", 39.7684, -86.1581},
+	{"Seattle", "WA", 47.6062, -122.3321},
+	{"Denver", "CO", 39.7392, -104.9903},
+	{"Washington", "DC", 38.9072, -77.0369},
+	{"Boston", "MA", 42.3601, -71.0589},
+	{"Nashville", "TN", 36.1627, -86.7816},
+	{"Detroit", "MI", 42.3314, -83.0458},
+	{"Portland", "OR", 45.5152, -122.6784},
+	{"Las Vegas", "NV", 36.1699, -115.1398},
+	{"Baltimore", "MD", 39.2904, -76.6122},
+	{"Milwaukee", "WI", 43.0389, -87.9065},
+	{"Albuquerque", "NM", 35.0844, -106.6504},
+	{"Tucson", "AZ", 32.2226, -110.9747},
+	{"Sacramento", "CA", 38.5816, -121.4944},
+	{"Kansas City", "MO", 39.0997, -94.5786},
+	{"Atlanta", "GA", 33.7490, -84.3880},
+	{"Miami", "FL", 25.7617, -80.1918},
+	{"Raleigh", "NC", 35.7796, -78.6382},
+	{"Omaha", "NE", 41.2565, -95.9345},
+	{"Minneapolis", "MN", 44.9778, -93.2650},
+	{"Tampa", "FL", 27.9506, -82.4572},
+	{"Orlando", "FL", 28.5383, -81.3792},
+	{"New Orleans", "LA", 29.9511, -90.0715},
+	{"Cleveland", "OH", 41.4993, -81.6944},
+	{"Pittsburgh", "PA", 40.4406, -79.9959},
+	{"Cincinnati", "OH", 39.1031, -84.5120},
+	{"St. Louis", "MO", 38.6270, -90.1994},
+	{"Salt Lake City", "UT", 40.7608, -111.8910},
+	{"Oakland", "CA", 37.8044, -122.2712},
+	{"Palo Alto", "CA", 37.4419, -122.1430},
+	{"Mountain View", "CA", 37.3861, -122.0839},
+	{"Sunnyvale", "CA", 37.3688, -122.0363},
+	{"Santa Clara", "CA", 37.3541, -121.9552},
+	{"Menlo Park", "CA", 37.4530, -122.1817},
+	{"Redwood City", "CA", 37.4852, -122.2364},
+	{"Cupertino", "CA", 37.3230, -122.0322},
+	{"Irvine", "CA", 33.6846, -117.8265},
+	{"Santa Monica", "CA", 34.0195, -118.4912},
+	{"Bellevue", "WA", 47.6101, -122.2015},
+	{"Redmond", "WA", 47.6740, -122.1215},
+	{"Boulder", "CO", 40.0150, -105.2705},
+	{"Cambridge", "MA", 42.3736, -71.1097},
+	{"Durham", "NC", 35.9940, -78.8986},
+	{"Arlington", "VA", 38.8816, -77.0910},
+	{"Reston", "VA", 38.9586, -77.3570},
+	{"Plano", "TX", 33.0198, -96.6989},
+	{"Round Rock", "TX", 30.5083, -97.6789},
+	{"Jersey City", "NJ", 40.7178, -74.0431},
+	{"Newark", "NJ", 40.7357, -74.1724},
+	{"Brooklyn", "NY", 40.6782, -73.9442},
+	{"Madison", "WI", 43.0731, -89.4012},
+	{"Ann Arbor", "MI", 42.2808, -83.7430},
+	{"Richmond", "VA", 37.5407, -77.4360},
+	{"Honolulu", "HI", 21.3069, -157.8583},
+	{"Anchorage", "AK", 61.2181, -149.9003},
+
+	// Canada
+	{"Toronto", "Canada", 43.6532, -79.3832},
+	{"Vancouver", "Canada", 49.2827, -123.1207},
+	{"Montreal", "Canada", 45.5017, -73.5673},
+	{"Ottawa", "Canada", 45.4215, -75.6972},
+	{"Calgary", "Canada", 51.0447, -114.0719},
+	{"Waterloo", "Canada", 43.4643, -80.5204},
+
+	// Europe
+	{"London", "United Kingdom", 51.5074, -0.1278},
+	{"Manchester", "United Kingdom", 53.4808, -2.2426},
+	{"Edinburgh", "United Kingdom", 55.9533, -3.1883},
+	{"Birmingham", "United Kingdom", 52.4862, -1.8904},
+	{"Bristol", "United Kingdom", 51.4545, -2.5879},
+	{"Leeds", "United Kingdom", 53.8008, -1.5491},
+	{"Glasgow", "United Kingdom", 55.8642, -4.2518},
+	{"Dublin", "Ireland", 53.3498, -6.2603},
+	{"Paris", "France", 48.8566, 2.3522},
+	{"Lyon", "France", 45.7640, 4.8357},
+	{"Berlin", "Germany", 52.5200, 13.4050},
+	{"Munich", "Germany", 48.1351, 11.5820},
+	{"Hamburg", "Germany", 53.5511, 9.9937},
+	{"Frankfurt", "Germany", 50.1109, 8.6821},
+	{"Cologne", "Germany", 50.9375, 6.9603},
+	{"Amsterdam", "Netherlands", 52.3676, 4.9041},
+	{"Rotterdam", "Netherlands", 51.9244, 4.4777},
+	{"Brussels", "Belgium", 50.8503, 4.3517},
+	{"Zurich", "Switzerland", 47.3769, 8.5417},
+	{"Geneva", "Switzerland", 46.2044, 6.1432},
+	{"Vienna", "Austria", 48.2082, 16.3738},
+	{"Madrid", "Spain", 40.4168, -3.7038},
+	{"Barcelona", "Spain", 41.3874, 2.1686},
+	{"Lisbon", "Portugal", 38.7223, -9.1393},
+	{"Milan", "Italy", 45.4642, 9.1900},
+	{"Rome", "Italy", 41.9028, 12.4964},
+	{"Stockholm", "Sweden", 59.3293, 18.0686},
+	{"Copenhagen", "Denmark", 55.6761, 12.5683},
+	{"Oslo", "Norway", 59.9139, 10.7522},
+	{"Helsinki", "Finland", 60.1699, 24.9384},
+	{"Warsaw", "Poland", 52.2297, 21.0122},
+	{"Krakow", "Poland", 50.0647, 19.9450},
+	{"Prague", "Czech Republic", 50.0755, 14.4378},
+	{"Budapest", "Hungary", 47.4979, 19.0402},
+	{"Bucharest", "Romania", 44.4268, 26.1025},
+	{"Athens", "Greece", 37.9838, 23.7275},
+	{"Tallinn", "Estonia", 59.4370, 24.7536},
+
+	// Asia and Oceania
+	{"Bangalore", "India", 12.9716, 77.5946},
+	{"Bengaluru", "India", 12.9716, 77.5946},
+	{"Mumbai", "India", 19.0760, 72.8777},
+	{"Delhi", "India", 28.7041, 77.1025},
+	{"New Delhi", "India", 28.6139, 77.2090},
+	{"Gurgaon", "India", 28.4595, 77.0266},
+	{"Gurugram", "India", 28.4595, 77.0266},
+	{"Noida", "India", 28.5355, 77.3910},
+	{"Hyderabad", "India", 17.3850, 78.4867},
+	{"Chennai", "India", 13.0827, 80.2707},
+	{"Pune", "India", 18.5204, 73.8567},
+	{"Kolkata", "India", 22.5726, 88.3639},
+	{"Ahmedabad", "India", 23.0225, 72.5714},
+	{"Singapore", "Singapore", 1.3521, 103.8198},
+	{"Hong Kong", "Hong Kong", 22.3193, 114.1694},
+	{"Tokyo", "Japan", 35.6762, 139.6503},
+	{"Seoul", "South Korea", 37.5665, 126.9780},
+	{"Shanghai", "China", 31.2304, 121.4737},
+	{"Beijing", "China", 39.9042, 116.4074},
+	{"Shenzhen", "China", 22.5431, 114.0579},
+	{"Taipei", "Taiwan", 25.0330, 121.5654},
+	{"Manila", "Philippines", 14.5995, 120.9842},
+	{"Jakarta", "Indonesia", -6.2088, 106.8456},
+	{"Kuala Lumpur", "Malaysia", 3.1390, 101.6869},
+	{"Bangkok", "Thailand", 13.7563, 100.5018},
+	{"Ho Chi Minh City", "Vietnam", 10.8231, 106.6297},
+	{"Dubai", "United Arab Emirates", 25.2048, 55.2708},
+	{"Tel Aviv", "Israel", 32.0853, 34.7818},
+	{"Sydney", "Australia", -33.8688, 151.2093},
+	{"Melbourne", "Australia", -37.8136, 144.9631},
+	{"Brisbane", "Australia", -27.4698, 153.0251},
+	{"Auckland", "New Zealand", -36.8485, 174.7633},
+
+	// Latin America and Africa
+	{"Mexico City", "Mexico", 19.4326, -99.1332},
+	{"Sao Paulo", "Brazil", -23.5505, -46.6333},
+	{"Buenos Aires", "Argentina", -34.6037, -58.3816},
+	{"Bogota", "Colombia", 4.7110, -74.0721},
+	{"Santiago", "Chile", -33.4489, -70.6693},
+	{"Lagos", "Nigeria", 6.5244, 3.3792},
+	{"Nairobi", "Kenya", -1.2921, 36.8219},
+	{"Cape Town", "South Africa", -33.9249, 18.4241},
+	{"Johannesburg", "South Africa", -26.2041, 28.0473},
+	{"Cairo", "Egypt", 30.0444, 31.2357},
+}
+
+// usStates maps postal codes to state names, so "Austin, Texas" and
+// "Austin, TX" both match
+var usStates = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia",
+	"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois",
+	"IN": "Indiana", "IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana",
+	"ME": "Maine", "MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+	"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+	"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+	"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon",
+	"PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina", "SD": "South Dakota",
+	"TN": "Tennessee", "TX": "Texas", "UT": "Utah", "VT": "Vermont", "VA": "Virginia",
+	"WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+}
+
+// countryAliases are other names postings give countries of the gazetteer
+var countryAliases = map[string][]string{
+	"United Kingdom":       {"UK", "GB", "England", "Scotland", "Great Britain"},
+	"United Arab Emirates": {"UAE"},
+	"Czech Republic":       {"Czechia"},
+	"Netherlands":          {"The Netherlands", "NL"},
+	"Germany":              {"DE", "Deutschland"},
+	"India":                {"IN"},
+}
+
+// gazetteer maps the normalized names of each city, with and without its
+// region, to its coordinates
+var gazetteer = buildGazetteer()
+
+func buildGazetteer() map[string]models.GeoPoint {
+	index := make(map[string]models.GeoPoint, len(cities)*4)
+	add := func(key string, point models.GeoPoint) {
+		key = normalizePlace(key)
+		if _, taken := index[key]; !taken {
+			index[key] = point
+		}
+	}
+	for _, c := range cities {
+		point := models.GeoPoint{Lat: c.lat, Lon: c.lon}
+		add(c.name, point)
+		add(c.name+", "+c.region, point)
+		if state, ok := usStates[c.region]; ok {
+			add(c.name+", "+state, point)
+			continue
+		}
+		for _, alias := range countryAliases[c.region] {
+			add(c.name+", "+alias, point)
+		}
+	}
+	return index
+}
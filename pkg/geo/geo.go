@@ -0,0 +1,285 @@
+// Package geo places job locations on the map, so searches can keep the
+// jobs within a radius of a place
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// DefaultNominatimURL is OpenStreetMap's public geocoding service. Its
+// usage policy allows one request a second from an identified application.
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org/search"
+
+// requestInterval spaces requests to the geocoding service
+const requestInterval = time.Second
+
+// Config is the geocoding section of GlobalSettings. Places are looked up
+// in a built-in table of cities first; when Enabled, the rest are asked of
+// a Nominatim server and the answers cached.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// URL is the Nominatim search endpoint. Defaults to DefaultNominatimURL.
+	URL string `json:"url,omitempty"`
+	// UserAgent identifies the application to the server, as
+	// OpenStreetMap's policy asks
+	UserAgent string `json:"userAgent,omitempty"`
+	// Timeout bounds each request, e.g. "10s". Defaults to 10 seconds.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Geocoder turns place names into coordinates. Answers from the service,
+// including "not found", are cached by place so no place is asked twice.
+type Geocoder struct {
+	config    Config
+	client    *http.Client
+	cachePath string
+	logger    *logrus.Logger
+
+	mutex sync.Mutex
+	cache map[string]*models.GeoPoint
+	dirty bool
+	last  time.Time
+}
+
+// New validates config and loads the cache at cachePath. A nil or disabled
+// config still returns a Geocoder, which knows only the built-in cities.
+func New(config *Config, cachePath string, logger *logrus.Logger) (*Geocoder, error) {
+	geocoder := &Geocoder{
+		cachePath: cachePath,
+		logger:    logger,
+		cache:     make(map[string]*models.GeoPoint),
+	}
+	if config == nil || !config.Enabled {
+		return geocoder, nil
+	}
+
+	c := *config
+	if c.URL == "" {
+		c.URL = DefaultNominatimURL
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return nil, fmt.Errorf("invalid geocoding url %q: %w", c.URL, err)
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = "hire.ai job scraper"
+	}
+	timeout := 10 * time.Second
+	if c.Timeout != "" {
+		parsed, err := time.ParseDuration(c.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid geocoding timeout %q", c.Timeout)
+		}
+		timeout = parsed
+	}
+	geocoder.config = c
+	geocoder.client = transport.NewClient(timeout)
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read geocoding cache: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &geocoder.cache); err != nil {
+			return nil, fmt.Errorf("failed to parse geocoding cache: %w", err)
+		}
+	}
+	return geocoder, nil
+}
+
+// Locate returns the coordinates of place, or nil when it cannot be
+// placed. Of a list such as "Austin, TX; Remote" the first place found is
+// taken, and remote locations are never placed.
+func (g *Geocoder) Locate(ctx context.Context, place string) *models.GeoPoint {
+	for _, part := range placeSeparators.Split(place, -1) {
+		key := normalizePlace(part)
+		if key == "" || isRemote(key) {
+			continue
+		}
+		if point, ok := lookupCity(key); ok {
+			return &point
+		}
+		if g.client == nil {
+			continue
+		}
+		if point := g.lookup(ctx, key); point != nil {
+			return point
+		}
+	}
+	return nil
+}
+
+// lookup asks the service for key, or answers from the cache
+func (g *Geocoder) lookup(ctx context.Context, key string) *models.GeoPoint {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if point, ok := g.cache[key]; ok {
+		return point
+	}
+
+	if wait := requestInterval - time.Since(g.last); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+	g.last = time.Now()
+
+	point, err := g.search(ctx, key)
+	if err != nil {
+		// Failures are not cached, so the place is asked again next time
+		g.logger.Debugf("Geocoding %q failed: %v", key, err)
+		return nil
+	}
+	g.cache[key] = point
+	g.dirty = true
+	return point
+}
+
+// search asks the Nominatim server for the best match of query
+func (g *Geocoder) search(ctx context.Context, query string) (*models.GeoPoint, error) {
+	params := url.Values{"q": {query}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.config.URL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.config.UserAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding service returned status: %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	lat, latErr := strconv.ParseFloat(results[0].Lat, 64)
+	lon, lonErr := strconv.ParseFloat(results[0].Lon, 64)
+	if latErr != nil || lonErr != nil {
+		return nil, fmt.Errorf("invalid coordinates %q, %q", results[0].Lat, results[0].Lon)
+	}
+	return &models.GeoPoint{Lat: lat, Lon: lon}, nil
+}
+
+// Save writes the answers got from the service since the last save
+func (g *Geocoder) Save() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if !g.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(g.cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(g.cachePath+".tmp", data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(g.cachePath+".tmp", g.cachePath); err != nil {
+		return err
+	}
+	g.dirty = false
+	return nil
+}
+
+// ResolveFilter places the filter's Near on the map as its Center, leaving
+// a filter without Near unchanged. A radius without a place, or a place
+// that cannot be found, is an error.
+func (g *Geocoder) ResolveFilter(ctx context.Context, filter *models.JobFilter) error {
+	if strings.TrimSpace(filter.Near) == "" {
+		if filter.RadiusKm > 0 && filter.Center == nil {
+			return fmt.Errorf("a radius needs a place to be near")
+		}
+		return nil
+	}
+	if filter.RadiusKm <= 0 {
+		filter.RadiusKm = DefaultRadiusKm
+	}
+	center := g.Locate(ctx, filter.Near)
+	if center == nil {
+		return fmt.Errorf("cannot locate %q", filter.Near)
+	}
+	filter.Center = center
+	return nil
+}
+
+// placeSeparators split locations listing several places
+var placeSeparators = regexp.MustCompile(`\s*[;|/]\s*`)
+
+// parenthetical drops notes such as "(Hybrid)" from a place
+var parenthetical = regexp.MustCompile(`\([^)]*\)`)
+
+// normalizePlace lowercases a place, drops notes in parentheses and the
+// United States after a state, and tidies punctuation and spaces
+func normalizePlace(place string) string {
+	place = parenthetical.ReplaceAllString(strings.ToLower(place), " ")
+	place = strings.NewReplacer(".", "", "-", " ").Replace(place)
+	var parts []string
+	for _, part := range strings.Split(place, ",") {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if n := len(parts); n > 2 {
+		switch parts[n-1] {
+		case "us", "usa", "united states", "united states of america":
+			parts = parts[:n-1]
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func isRemote(key string) bool {
+	for _, word := range []string{"remote", "anywhere", "worldwide", "work from home", "wfh"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupCity finds key in the gazetteer, trying "city, country" for
+// "city, state, country"
+func lookupCity(key string) (models.GeoPoint, bool) {
+	if point, ok := gazetteer[key]; ok {
+		return point, true
+	}
+	parts := strings.Split(key, ", ")
+	if len(parts) > 2 {
+		for _, region := range []string{parts[len(parts)-1], parts[1]} {
+			if point, ok := gazetteer[parts[0]+", "+region]; ok {
+				return point, true
+			}
+		}
+	}
+	return models.GeoPoint{}, false
+}
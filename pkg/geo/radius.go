@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// kmPerMile converts miles to kilometres
+const kmPerMile = 1.609344
+
+// DefaultRadiusKm is the radius of a search near a place that gives none,
+// 25 miles
+const DefaultRadiusKm = 25 * kmPerMile
+
+// ParseRadius reads a distance such as 50mi, 50 miles or 80km and returns
+// it in kilometres. The unit is required.
+func ParseRadius(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz ")
+	unit := strings.TrimSpace(value[len(number):])
+
+	distance, err := strconv.ParseFloat(number, 64)
+	if err != nil || distance <= 0 {
+		return 0, fmt.Errorf("invalid radius %q (use e.g. 50mi or 80km)", value)
+	}
+	switch unit {
+	case "mi", "mile", "miles":
+		return distance * kmPerMile, nil
+	case "km", "kms", "kilometer", "kilometers", "kilometre", "kilometres":
+		return distance, nil
+	default:
+		return 0, fmt.Errorf("invalid radius %q (use e.g. 50mi or 80km)", value)
+	}
+}
+
+// Miles converts a distance in kilometres to miles
+func Miles(km float64) float64 {
+	return km / kmPerMile
+}
@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+)
+
+// String returns a string argument, or "" when it is absent or null
+func (p ResolveParams) String(name string) (string, error) {
+	switch v := p.Args[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	return "", fmt.Errorf("argument %s must be a string", name)
+}
+
+// Int returns an integer argument, or defaultValue when it is absent or null.
+// Variables decoded from JSON arrive as float64 and are accepted when whole.
+func (p ResolveParams) Int(name string, defaultValue int) (int, error) {
+	switch v := p.Args[name].(type) {
+	case nil:
+		return defaultValue, nil
+	case int:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %s must be an integer", name)
+}
+
+// Float returns a numeric argument, or defaultValue when it is absent or null
+func (p ResolveParams) Float(name string, defaultValue float64) (float64, error) {
+	switch v := p.Args[name].(type) {
+	case nil:
+		return defaultValue, nil
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("argument %s must be a number", name)
+}
+
+// Bool returns a boolean argument; the second result is false when it is
+// absent or null
+func (p ResolveParams) Bool(name string) (bool, bool, error) {
+	switch v := p.Args[name].(type) {
+	case nil:
+		return false, false, nil
+	case bool:
+		return v, true, nil
+	}
+	return false, false, fmt.Errorf("argument %s must be a boolean", name)
+}
+
+// Strings returns a list-of-strings argument. A single string is accepted as
+// a one-element list, as GraphQL input coercion allows.
+func (p ResolveParams) Strings(name string) ([]string, error) {
+	switch v := p.Args[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("argument %s must be a list of strings", name)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	case []string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("argument %s must be a list of strings", name)
+}
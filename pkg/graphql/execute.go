@@ -0,0 +1,411 @@
+// Package graphql is a small GraphQL executor for the hire.ai API. It parses
+// query and mutation documents (fields, aliases, arguments, variables,
+// fragments, @skip and @include) and resolves them against a schema of Go
+// resolver functions. Type checking beyond field and argument names is left
+// to the resolvers, and introspection is limited to __typename.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Schema holds the root types for queries and mutations
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+}
+
+// Object is a GraphQL object type
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Field describes one field of an object. Type is nil for scalars and lists
+// of scalars; otherwise the resolved value (or each element, for slices) is
+// executed against Type. A nil Resolve reads the struct field with the same
+// name, ignoring case.
+type Field struct {
+	Type    *Object
+	Args    []string
+	Resolve func(p ResolveParams) (interface{}, error)
+}
+
+// ResolveParams is passed to field resolvers
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of executing a request
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a request or field error; Path locates field errors in Data
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Execute parses and runs a request against the schema
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	root := s.Query
+	switch op.Type {
+	case "mutation":
+		root = s.Mutation
+	case "subscription":
+		root = nil
+	}
+	if root == nil {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("%s operations are not supported", op.Type)}}}
+	}
+
+	variables, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	exec := &executor{ctx: ctx, doc: doc, variables: variables}
+	data := exec.executeSelectionSet(root, nil, op.SelectionSet, nil)
+	return &Response{Data: data, Errors: exec.errors}
+}
+
+// IsMutation reports whether the operation a request would run is a mutation.
+// Unparseable requests report false and fail later in Execute.
+func IsMutation(req Request) bool {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return false
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	return err == nil && op.Type == "mutation"
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+func coerceVariables(op *Operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	for _, def := range op.Variables {
+		value, ok := provided[def.Name]
+		switch {
+		case ok:
+			variables[def.Name] = value
+		case def.HasDefault:
+			variables[def.Name] = def.Default
+		case strings.HasSuffix(def.Type, "!"):
+			return nil, fmt.Errorf("variable $%s of type %s was not provided", def.Name, def.Type)
+		}
+	}
+	return variables, nil
+}
+
+type executor struct {
+	ctx       context.Context
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message: fmt.Sprintf(format, args...),
+		Path:    append([]interface{}(nil), path...),
+	})
+}
+
+// fieldGroup is every selection sharing one response key, merged in order
+type fieldGroup struct {
+	key        string
+	selections []*Selection
+}
+
+func (e *executor) executeSelectionSet(object *Object, source interface{}, selections []*Selection, path []interface{}) *OrderedMap {
+	result := &OrderedMap{}
+	for _, group := range e.collectFields(object, selections, nil, map[string]bool{}) {
+		fieldPath := append(path, group.key)
+		result.Set(group.key, e.executeField(object, source, group, fieldPath))
+	}
+	return result
+}
+
+// collectFields flattens fragments and applies @skip/@include, grouping
+// fields by response key in the order they first appear
+func (e *executor) collectFields(object *Object, selections []*Selection, groups []*fieldGroup, visited map[string]bool) []*fieldGroup {
+	for _, sel := range selections {
+		if !e.included(sel.Directives) {
+			continue
+		}
+
+		switch {
+		case sel.FragmentSpread != "":
+			if visited[sel.FragmentSpread] {
+				continue
+			}
+			visited[sel.FragmentSpread] = true
+
+			fragment, ok := e.doc.Fragments[sel.FragmentSpread]
+			if !ok {
+				e.fail(nil, "unknown fragment %s", sel.FragmentSpread)
+				continue
+			}
+			if fragment.TypeCondition == object.Name {
+				groups = e.collectFields(object, fragment.SelectionSet, groups, visited)
+			}
+		case sel.Inline:
+			if sel.TypeCondition == "" || sel.TypeCondition == object.Name {
+				groups = e.collectFields(object, sel.SelectionSet, groups, visited)
+			}
+		default:
+			key := sel.ResponseKey()
+			found := false
+			for _, group := range groups {
+				if group.key == key {
+					group.selections = append(group.selections, sel)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, &fieldGroup{key: key, selections: []*Selection{sel}})
+			}
+		}
+	}
+	return groups
+}
+
+func (e *executor) included(directives []Directive) bool {
+	for _, directive := range directives {
+		value, ok := e.resolveValue(directive.Arguments["if"]).(bool)
+		if !ok {
+			continue
+		}
+		if directive.Name == "skip" && value || directive.Name == "include" && !value {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *executor) executeField(object *Object, source interface{}, group *fieldGroup, path []interface{}) interface{} {
+	sel := group.selections[0]
+	if sel.Name == "__typename" {
+		return object.Name
+	}
+
+	field, ok := object.Fields[sel.Name]
+	if !ok {
+		e.fail(path, "cannot query field %q on type %s", sel.Name, object.Name)
+		return nil
+	}
+
+	args := make(map[string]interface{}, len(sel.Arguments))
+	for name, value := range sel.Arguments {
+		if !contains(field.Args, name) {
+			e.fail(path, "unknown argument %q on field %s.%s", name, object.Name, sel.Name)
+			return nil
+		}
+		args[name] = e.resolveValue(value)
+	}
+
+	var subSelections []*Selection
+	for _, s := range group.selections {
+		subSelections = append(subSelections, s.SelectionSet...)
+	}
+	if field.Type != nil && len(subSelections) == 0 {
+		e.fail(path, "field %s of type %s must have a selection of subfields", sel.Name, field.Type.Name)
+		return nil
+	}
+	if field.Type == nil && len(subSelections) > 0 {
+		e.fail(path, "field %s is a scalar and cannot have a selection", sel.Name)
+		return nil
+	}
+
+	var value interface{}
+	var err error
+	if field.Resolve != nil {
+		value, err = field.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+	} else {
+		value, err = defaultResolve(source, sel.Name)
+	}
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+
+	return e.completeValue(field.Type, value, subSelections, path)
+}
+
+func (e *executor) completeValue(object *Object, value interface{}, selections []*Selection, path []interface{}) interface{} {
+	if object == nil || value == nil {
+		return value
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return []interface{}{}
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = e.completeValue(object, rv.Index(i).Interface(), selections, append(path, i))
+		}
+		return items
+	}
+
+	return e.executeSelectionSet(object, value, selections, path)
+}
+
+// resolveValue substitutes variables and enum names in an argument value
+func (e *executor) resolveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case variableRef:
+		return e.variables[string(v)]
+	case enumValue:
+		return string(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = e.resolveValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = e.resolveValue(item)
+		}
+		return out
+	}
+	return value
+}
+
+// defaultResolve reads the struct field or map entry matching name
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		field := rv.FieldByNameFunc(func(fieldName string) bool { return strings.EqualFold(fieldName, name) })
+		if field.IsValid() {
+			return field.Interface(), nil
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			if value := rv.MapIndex(reflect.ValueOf(name)); value.IsValid() {
+				return value.Interface(), nil
+			}
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no resolver for field %s", name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderedMap is a JSON object that keeps the order fields were selected in
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Set adds or replaces a key, keeping the position of existing keys
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored under key
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// MarshalJSON writes the fields in insertion order
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// Events a command hook can subscribe to
+const (
+	// EventPreScrape runs the command before each scrape with the search
+	EventPreScrape = "pre_scrape"
+	// EventJobs runs the command on each batch of scraped jobs
+	EventJobs = "jobs"
+	// EventPostRun runs the command after each scrape with its report and
+	// new jobs
+	EventPostRun = "post_run"
+)
+
+// CommandConfig runs an external program at the points of a run it
+// subscribes to. The program gets a JSON object on stdin with "event" and
+// one of "scrape", "jobs" or "run" and "new_jobs". For pre_scrape it may
+// print {"scrape": {...}} to change the search and exits non-zero to stop
+// the run; for jobs it may print {"jobs": [...]} to keep only those jobs,
+// as changed. Printing nothing changes nothing.
+type CommandConfig struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name,omitempty"`
+	// Command is the program and its arguments, run without a shell
+	Command []string `json:"command"`
+	// Events defaults to post_run only
+	Events []string `json:"events,omitempty"`
+	// TimeoutSeconds bounds each invocation. Defaults to 30.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// Env adds variables to the program's environment; values may
+	// reference environment variables, e.g. "${API_TOKEN}"
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// CommandHook runs an external program as a hook
+type CommandHook struct {
+	config  CommandConfig
+	events  map[string]bool
+	timeout time.Duration
+}
+
+// commandInput is what a command hook reads on stdin
+type commandInput struct {
+	Event   string            `json:"event"`
+	Scrape  *Scrape           `json:"scrape,omitempty"`
+	Jobs    []models.Job      `json:"jobs,omitempty"`
+	Run     *models.RunReport `json:"run,omitempty"`
+	NewJobs []models.Job      `json:"new_jobs,omitempty"`
+}
+
+// commandOutput is what a command hook may print on stdout
+type commandOutput struct {
+	Scrape *Scrape       `json:"scrape,omitempty"`
+	Jobs   *[]models.Job `json:"jobs,omitempty"`
+}
+
+// NewCommandHook validates config
+func NewCommandHook(config CommandConfig) (*CommandHook, error) {
+	if len(config.Command) == 0 || config.Command[0] == "" {
+		return nil, fmt.Errorf("hook %q needs a command", config.Name)
+	}
+	if config.Name == "" {
+		config.Name = config.Command[0]
+	}
+	if len(config.Events) == 0 {
+		config.Events = []string{EventPostRun}
+	}
+	if config.TimeoutSeconds <= 0 {
+		config.TimeoutSeconds = 30
+	}
+
+	events := make(map[string]bool, len(config.Events))
+	for _, event := range config.Events {
+		switch event {
+		case EventPreScrape, EventJobs, EventPostRun:
+			events[event] = true
+		default:
+			return nil, fmt.Errorf("hook %s: unknown event %q", config.Name, event)
+		}
+	}
+	return &CommandHook{config: config, events: events, timeout: time.Duration(config.TimeoutSeconds) * time.Second}, nil
+}
+
+func (h *CommandHook) Name() string {
+	return "command " + h.config.Name
+}
+
+// PreScrape runs the command with the search and takes any search it
+// prints
+func (h *CommandHook) PreScrape(ctx context.Context, scrape *Scrape) error {
+	if !h.events[EventPreScrape] {
+		return nil
+	}
+	output, err := h.run(ctx, commandInput{Event: EventPreScrape, Scrape: scrape})
+	if err != nil {
+		return err
+	}
+	if output.Scrape != nil {
+		// The run ID is not the hook's to change
+		output.Scrape.RunID = scrape.RunID
+		*scrape = *output.Scrape
+	}
+	return nil
+}
+
+// Jobs runs the command on a batch and keeps the jobs it prints. Printed
+// jobs are matched to the batch by ID; any the batch did not have are
+// ignored.
+func (h *CommandHook) Jobs(ctx context.Context, jobs []models.Job) ([]models.Job, error) {
+	if !h.events[EventJobs] {
+		return jobs, nil
+	}
+	output, err := h.run(ctx, commandInput{Event: EventJobs, Jobs: jobs})
+	if err != nil {
+		return nil, err
+	}
+	if output.Jobs == nil {
+		return jobs, nil
+	}
+
+	inBatch := make(map[string]bool, len(jobs))
+	for i := range jobs {
+		inBatch[jobs[i].ID] = true
+	}
+	kept := make([]models.Job, 0, len(*output.Jobs))
+	for _, job := range *output.Jobs {
+		if inBatch[job.ID] {
+			kept = append(kept, job)
+		}
+	}
+	return kept, nil
+}
+
+// PostRun runs the command with the run's report and new jobs
+func (h *CommandHook) PostRun(ctx context.Context, report models.RunReport, newJobs []models.Job) error {
+	if !h.events[EventPostRun] {
+		return nil
+	}
+	_, err := h.run(ctx, commandInput{Event: EventPostRun, Run: &report, NewJobs: newJobs})
+	return err
+}
+
+// run starts the command with input on stdin and decodes what it prints.
+// A non-zero exit is an error carrying the end of its stderr.
+func (h *CommandHook) run(ctx context.Context, input commandInput) (commandOutput, error) {
+	var output commandOutput
+	data, err := json.Marshal(input)
+	if err != nil {
+		return output, fmt.Errorf("failed to encode %s input: %w", input.Event, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, h.config.Command[0], h.config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = os.Environ()
+	for name, value := range h.config.Env {
+		cmd.Env = append(cmd.Env, name+"="+os.ExpandEnv(value))
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return output, fmt.Errorf("%s timed out after %v", input.Event, h.timeout)
+		}
+		if message := lastLine(stderr.String()); message != "" {
+			return output, fmt.Errorf("%s: %w: %s", input.Event, err, message)
+		}
+		return output, fmt.Errorf("%s: %w", input.Event, err)
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return output, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return output, fmt.Errorf("%s: invalid output: %w", input.Event, err)
+	}
+	return output, nil
+}
+
+// lastLine returns the last non-empty line of text
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,185 @@
+// Package hooks lets users plug their own filtering, enrichment and side
+// effects into the scrape pipeline without changing it. Go hooks implement
+// Hook and one or more of PreScrapeHook, JobHook and PostRunHook, and are
+// added with Register, typically from an init function in a file of the
+// scraper's main package. External programs are configured in the "hooks"
+// section of the config file's globalSettings and receive JSON on stdin.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+)
+
+// Config is the hooks section of GlobalSettings
+type Config struct {
+	Commands []CommandConfig `json:"commands,omitempty"`
+}
+
+// Scrape is the search a run is about to make. Pre-scrape hooks may change
+// it.
+type Scrape struct {
+	RunID    string   `json:"run_id"`
+	Keywords []string `json:"keywords"`
+	Location string   `json:"location"`
+}
+
+// Hook is a plugin for the scrape pipeline. It does nothing unless it also
+// implements one of the interfaces below.
+type Hook interface {
+	// Name identifies the hook in logs
+	Name() string
+}
+
+// PreScrapeHook is implemented by hooks that run before each scrape. They
+// may change the search; an error stops the run before anything is
+// fetched.
+type PreScrapeHook interface {
+	PreScrape(ctx context.Context, scrape *Scrape) error
+}
+
+// JobHook is implemented by hooks that see every scraped job once it is
+// enriched and before it is deduplicated and stored. They may change the
+// job, and drop it by returning false. Jobs from several sources pass
+// through at once, so it must be safe for concurrent use.
+type JobHook interface {
+	Job(ctx context.Context, job *models.Job) (bool, error)
+}
+
+// BatchHook is implemented by hooks that see scraped jobs a batch at a time
+// rather than one by one, as command hooks do to start one process per
+// batch. They return the jobs to keep.
+type BatchHook interface {
+	Jobs(ctx context.Context, jobs []models.Job) ([]models.Job, error)
+}
+
+// PostRunHook is implemented by hooks that run after each scrape, whether
+// it succeeded or not, with its report and the jobs it newly stored
+type PostRunHook interface {
+	PostRun(ctx context.Context, report models.RunReport, newJobs []models.Job) error
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      []Hook
+)
+
+// Register adds a Go hook to every pipeline built afterwards, ahead of the
+// configured command hooks
+func Register(hook Hook) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = append(registry, hook)
+}
+
+// Runner calls the registered and configured hooks at each point of a run.
+// A nil Runner has no hooks.
+type Runner struct {
+	hooks  []Hook
+	logger *logrus.Logger
+}
+
+// New builds a runner from the registered hooks and the command hooks
+// enabled in config; it returns nil when there are none
+func New(config *Config, logger *logrus.Logger) (*Runner, error) {
+	registryMutex.Lock()
+	hooks := append([]Hook(nil), registry...)
+	registryMutex.Unlock()
+
+	if config != nil {
+		for _, c := range config.Commands {
+			if !c.Enabled {
+				continue
+			}
+			command, err := NewCommandHook(c)
+			if err != nil {
+				return nil, err
+			}
+			hooks = append(hooks, command)
+		}
+	}
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Runner{hooks: hooks, logger: logger}, nil
+}
+
+// Len is the number of hooks
+func (r *Runner) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.hooks)
+}
+
+// PreScrape runs the pre-scrape hooks in order, each seeing the changes of
+// the ones before, and stops at the first error
+func (r *Runner) PreScrape(ctx context.Context, scrape *Scrape) error {
+	if r == nil {
+		return nil
+	}
+	for _, hook := range r.hooks {
+		if pre, ok := hook.(PreScrapeHook); ok {
+			if err := pre.PreScrape(ctx, scrape); err != nil {
+				return fmt.Errorf("hook %s stopped the run: %w", hook.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// Jobs passes a batch of scraped jobs through the job and batch hooks in
+// order and returns those kept. A hook that fails is logged and keeps the
+// jobs it was given, so a broken hook never loses jobs.
+func (r *Runner) Jobs(ctx context.Context, jobs []models.Job) []models.Job {
+	if r == nil || len(jobs) == 0 {
+		return jobs
+	}
+	for _, hook := range r.hooks {
+		switch h := hook.(type) {
+		case BatchHook:
+			kept, err := h.Jobs(ctx, jobs)
+			if err != nil {
+				r.logger.Errorf("Hook %s failed on %d jobs: %v", hook.Name(), len(jobs), err)
+				continue
+			}
+			jobs = kept
+		case JobHook:
+			kept := jobs[:0]
+			for i := range jobs {
+				keep, err := h.Job(ctx, &jobs[i])
+				if err != nil {
+					r.logger.WithField("job", jobs[i].ID).Errorf("Hook %s failed: %v", hook.Name(), err)
+					keep = true
+				}
+				if keep {
+					kept = append(kept, jobs[i])
+				}
+			}
+			jobs = kept
+		}
+	}
+	return jobs
+}
+
+// PostRun runs the post-run hooks, logging their failures
+func (r *Runner) PostRun(ctx context.Context, report models.RunReport, newJobs []models.Job) {
+	if r == nil {
+		return
+	}
+	for _, hook := range r.hooks {
+		if post, ok := hook.(PostRunHook); ok {
+			if err := post.PostRun(ctx, report, newJobs); err != nil {
+				r.logger.Errorf("Hook %s failed after run %s: %v", hook.Name(), report.ID, err)
+			}
+		}
+	}
+}
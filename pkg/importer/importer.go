@@ -0,0 +1,361 @@
+// Package importer reads jobs kept outside the scraper, such as LinkedIn's
+// saved jobs export or any CSV or JSON list, so they can be stored and
+// tracked with the scraped ones
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+)
+
+// Formats understood by Parse. FormatAuto picks one from the data.
+const (
+	FormatAuto     = "auto"
+	FormatLinkedIn = "linkedin"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+)
+
+// Job fields a column can be mapped to
+const (
+	FieldTitle       = "title"
+	FieldCompany     = "company"
+	FieldLocation    = "location"
+	FieldSalary      = "salary"
+	FieldDescription = "description"
+	FieldLink        = "link"
+	FieldPosted      = "posted"
+	FieldStatus      = "status"
+	FieldNotes       = "notes"
+)
+
+// Fields lists the fields a Mapping can name
+var Fields = []string{
+	FieldTitle, FieldCompany, FieldLocation, FieldSalary, FieldDescription,
+	FieldLink, FieldPosted, FieldStatus, FieldNotes,
+}
+
+// aliases are the column names each field is found under when the mapping
+// does not name one, compared by normalizeColumn
+var aliases = map[string][]string{
+	FieldTitle:       {"title", "jobtitle", "position", "role", "jobname"},
+	FieldCompany:     {"company", "companyname", "employer", "organization", "organisation"},
+	FieldLocation:    {"location", "joblocation", "city"},
+	FieldSalary:      {"salary", "salaryrange", "compensation", "pay"},
+	FieldDescription: {"description", "jobdescription", "summary", "details"},
+	FieldLink:        {"link", "url", "joburl", "joblink", "applyurl", "applicationurl"},
+	FieldPosted:      {"posted", "postedat", "dateposted", "posteddate", "publishedat", "postingdate"},
+	FieldStatus:      {"status", "applicationstatus", "stage"},
+	FieldNotes:       {"notes", "note", "comments"},
+}
+
+// LinkedIn's data export holds saved jobs in "Saved Jobs.csv" and
+// applications in "Job Applications.csv", with these columns
+var linkedInMapping = Mapping{
+	FieldTitle:   "Job Title",
+	FieldCompany: "Company Name",
+	FieldLink:    "Job Url",
+}
+
+const linkedInApplied = "Application Date"
+
+// spreadsheetLayouts are the dates spreadsheets write that
+// models.ParseTimestamp does not read
+var spreadsheetLayouts = []string{
+	"1/2/2006",
+	"1/2/06",
+	"1/2/2006 15:04",
+	"1/2/06, 3:04 PM",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// Mapping names the CSV column or JSON key holding each field
+type Mapping map[string]string
+
+// ParseMapping reads a mapping written as field=column pairs separated by
+// commas, e.g. "title=Position,link=Posting URL"
+func ParseMapping(spec string) (Mapping, error) {
+	mapping := make(Mapping)
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, column, found := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !found || column == "" {
+			return nil, fmt.Errorf("invalid mapping %q (use field=column)", pair)
+		}
+		if _, known := aliases[field]; !known {
+			return nil, fmt.Errorf("unknown field %q (use %s)", field, strings.Join(Fields, ", "))
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+// Record is an imported job with the tracking state the export gave it
+type Record struct {
+	Job models.Job
+	// Status is the application status read from the export, lowercased,
+	// or "" when it had none
+	Status string
+	Notes  string
+}
+
+// Result is what Parse read. Skipped describes the rows left out.
+type Result struct {
+	Format  string
+	Records []Record
+	Skipped []string
+}
+
+// Detect picks the format of data: JSON when it starts as an array or
+// object, LinkedIn when its header has LinkedIn's export columns, and CSV
+// otherwise
+func Detect(data []byte) string {
+	trimmed := bytes.TrimLeft(data, "\ufeff \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return FormatJSON
+	}
+	reader := newCSVReader(data)
+	var header []string
+	for len(header) < 2 {
+		record, err := reader.Read()
+		if err != nil {
+			return FormatCSV
+		}
+		header = record
+	}
+	columns := make(map[string]bool, len(header))
+	for _, column := range header {
+		columns[normalizeColumn(column)] = true
+	}
+	for _, column := range linkedInMapping {
+		if !columns[normalizeColumn(column)] {
+			return FormatCSV
+		}
+	}
+	return FormatLinkedIn
+}
+
+// Parse reads the jobs in data. The columns mapping names are read in place
+// of those found by their usual names; source names where the jobs came from and
+// defaults to "LinkedIn" for LinkedIn exports and "Import" otherwise.
+func Parse(data []byte, format string, mapping Mapping, source string) (*Result, error) {
+	if format == "" || format == FormatAuto {
+		format = Detect(data)
+	}
+
+	var rows []map[string]string
+	var err error
+	switch format {
+	case FormatJSON:
+		rows, err = jsonRows(data)
+	case FormatCSV, FormatLinkedIn:
+		rows, err = csvRows(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s (use auto, linkedin, csv or json)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatLinkedIn {
+		merged := make(Mapping, len(linkedInMapping)+len(mapping))
+		for field, column := range linkedInMapping {
+			merged[field] = column
+		}
+		for field, column := range mapping {
+			merged[field] = column
+		}
+		mapping = merged
+		if source == "" {
+			source = "LinkedIn"
+		}
+	}
+	if source == "" {
+		source = "Import"
+	}
+
+	result := &Result{Format: format}
+	for i, row := range rows {
+		values := make(map[string]string, len(Fields))
+		for _, field := range Fields {
+			values[field] = lookup(row, field, mapping)
+		}
+		if values[FieldTitle] == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("row %d: no title", i+1))
+			continue
+		}
+		if values[FieldCompany] == "" && values[FieldLink] != "" {
+			values[FieldCompany] = scraper.CompanyFromURL(values[FieldLink])
+		}
+		if values[FieldCompany] == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("row %d: %s has no company or link", i+1, values[FieldTitle]))
+			continue
+		}
+
+		job := models.NewJob(values[FieldTitle], values[FieldCompany], values[FieldLocation], values[FieldSalary],
+			values[FieldDescription], values[FieldLink], source)
+		if values[FieldPosted] != "" {
+			if posted, ok := parseDate(values[FieldPosted]); ok {
+				job.SetPosted(posted)
+			}
+		}
+		job.ExtractKeywords()
+
+		record := Record{
+			Job:    *job,
+			Status: strings.ToLower(values[FieldStatus]),
+			Notes:  values[FieldNotes],
+		}
+		// A row of LinkedIn's applications export was applied to
+		if format == FormatLinkedIn && record.Status == "" && lookupColumn(row, linkedInApplied) != "" {
+			record.Status = models.ApplicationApplied
+		}
+		result.Records = append(result.Records, record)
+	}
+	return result, nil
+}
+
+// lookup returns the value of field in row, from the mapped column if
+// there is one and otherwise from the first of its usual names present
+func lookup(row map[string]string, field string, mapping Mapping) string {
+	if column, ok := mapping[field]; ok {
+		return lookupColumn(row, column)
+	}
+	for _, alias := range aliases[field] {
+		if value, ok := row[alias]; ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func lookupColumn(row map[string]string, column string) string {
+	return row[normalizeColumn(column)]
+}
+
+// normalizeColumn compares column names ignoring case, spaces and
+// punctuation, so "Job URL", "job_url" and "jobUrl" match
+func normalizeColumn(column string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(column) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func newCSVReader(data []byte) *csv.Reader {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// csvRows reads each row after the header into a map keyed by normalized
+// column name. LinkedIn exports start with notes above the header, so
+// leading rows with a single column are skipped.
+func csvRows(data []byte) ([]map[string]string, error) {
+	reader := newCSVReader(data)
+	var header []string
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if header == nil {
+			if len(record) > 1 {
+				header = record
+			}
+			continue
+		}
+		row := make(map[string]string, len(header))
+		empty := true
+		for i, column := range header {
+			if i < len(record) {
+				value := strings.TrimSpace(record[i])
+				row[normalizeColumn(column)] = value
+				empty = empty && value == ""
+			}
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+	if header == nil {
+		return nil, fmt.Errorf("no header row in CSV")
+	}
+	return rows, nil
+}
+
+// jsonRows reads an array of objects, or an object holding one under
+// "jobs", into maps keyed by normalized key. Numbers and booleans are
+// written as text and lists of strings joined with commas.
+func jsonRows(data []byte) ([]map[string]string, error) {
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		var wrapped struct {
+			Jobs []map[string]interface{} `json:"jobs"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		objects = wrapped.Jobs
+	}
+
+	rows := make([]map[string]string, 0, len(objects))
+	for _, object := range objects {
+		row := make(map[string]string, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case string:
+				row[normalizeColumn(key)] = strings.TrimSpace(v)
+			case float64, bool:
+				row[normalizeColumn(key)] = fmt.Sprint(v)
+			case []interface{}:
+				var parts []string
+				for _, item := range v {
+					if s, ok := item.(string); ok {
+						parts = append(parts, s)
+					}
+				}
+				row[normalizeColumn(key)] = strings.Join(parts, ", ")
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseDate reads a date as feeds or spreadsheets write it, in the display
+// timezone when it has none
+func parseDate(value string) (time.Time, bool) {
+	location := models.DisplayLocation()
+	if t, ok := models.ParseTimestamp(value, location); ok {
+		return t, true
+	}
+	for _, layout := range spreadsheetLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
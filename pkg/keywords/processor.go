@@ -3,6 +3,8 @@ package keywords
 import (
 	"regexp"
 	"strings"
+
+	"hire.ai/pkg/models"
 )
 
 type KeywordProcessor struct {
@@ -16,6 +18,10 @@ type SearchQuery struct {
 	Location   string   `json:"location"`
 	Synonyms   []string `json:"synonyms"`
 	Exclusions []string `json:"exclusions"`
+	// Expression is the boolean query the keywords came from, with each
+	// term expanded by its synonyms; scraped jobs must satisfy it. It is
+	// nil for plain keywords.
+	Expression *models.KeywordExpr `json:"-"`
 }
 
 // NewKeywordProcessor creates a new keyword processor instance
@@ -51,6 +57,34 @@ func (kp *KeywordProcessor) ProcessKeywords(input string) SearchQuery {
 	}
 }
 
+// ProcessQuery processes keywords, any of which may match, as a boolean
+// query when one of them uses AND, OR or NOT, and otherwise as
+// ProcessKeywords processes their words. The query's terms and their
+// synonyms become the search keywords, and the terms under a NOT join the
+// exclusions.
+func (kp *KeywordProcessor) ProcessQuery(keywords []string) (SearchQuery, error) {
+	expr, err := models.KeywordExpression(keywords)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+	if expr == nil {
+		return kp.ProcessKeywords(strings.Join(keywords, " ")), nil
+	}
+
+	terms, excluded := expr.Terms()
+	expanded := expr.Expand(func(term string) []string {
+		return kp.synonyms[term]
+	})
+	expandedTerms, _ := expanded.Terms()
+
+	return SearchQuery{
+		Keywords:   kp.filterExclusions(expandedTerms),
+		Synonyms:   kp.extractJobPatterns(strings.Join(terms, " ")),
+		Exclusions: append(append([]string(nil), kp.exclusions...), excluded...),
+		Expression: expanded,
+	}, nil
+}
+
 func (kp *KeywordProcessor) cleanAndSplit(input string) []string {
 	// Remove special characters and normalize
 	input = strings.ToLower(input)
@@ -0,0 +1,22 @@
+package keywords
+
+import "testing"
+
+func BenchmarkProcessKeywords(b *testing.B) {
+	kp := NewKeywordProcessor()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		kp.ProcessKeywords("senior golang developer, kubernetes, remote backend engineer")
+	}
+}
+
+func BenchmarkProcessQuery(b *testing.B) {
+	kp := NewKeywordProcessor()
+	keywords := []string{`golang AND (kubernetes OR terraform) NOT intern`, `"site reliability" OR devops`}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := kp.ProcessQuery(keywords); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
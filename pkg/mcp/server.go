@@ -0,0 +1,258 @@
+// Package mcp serves tools over the Model Context Protocol, so LLM agents
+// can call into hire.ai. It speaks JSON-RPC 2.0 over newline-delimited
+// stdio, the transport MCP clients use to launch local servers.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// protocolVersions are the MCP revisions the server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Handler runs a tool with the arguments the client sent. Its result is
+// returned to the client as JSON; an error is reported to the agent as a
+// failed tool call rather than a protocol error.
+type Handler func(ctx context.Context, arguments json.RawMessage) (interface{}, error)
+
+// Tool is a function agents can call
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments object
+	InputSchema map[string]interface{}
+	Handler     Handler
+}
+
+// Server answers MCP requests with its tools
+type Server struct {
+	name    string
+	version string
+	tools   map[string]Tool
+	logger  *logrus.Logger
+
+	writeMutex sync.Mutex
+	out        *json.Encoder
+}
+
+// NewServer creates a server that introduces itself with name and version
+func NewServer(name, version string, logger *logrus.Logger) *Server {
+	return &Server{name: name, version: version, tools: make(map[string]Tool), logger: logger}
+}
+
+// AddTool registers tool, replacing any tool of the same name
+func (s *Server) AddTool(tool Tool) {
+	if tool.InputSchema == nil {
+		tool.InputSchema = map[string]interface{}{"type": "object"}
+	}
+	s.tools[tool.Name] = tool
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Serve reads requests from r and writes responses to w until r ends or
+// ctx is cancelled. Requests run concurrently, so a long scrape does not
+// hold up other calls.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = json.NewEncoder(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			if len(line) == 0 {
+				continue
+			}
+			var req request
+			if err := json.Unmarshal(line, &req); err != nil {
+				s.write(response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "invalid JSON"}})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.handle(ctx, req)
+			}()
+		}
+	}
+}
+
+// handle answers one request; notifications, which have no ID, get no reply
+func (s *Server) handle(ctx context.Context, req request) {
+	result, err := s.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		return
+	}
+	resp := response{ID: req.ID, Result: result}
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{codeInvalidRequest, err.Error()}
+		}
+		resp.Result, resp.Error = nil, rpcErr
+	}
+	s.write(resp)
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		version := protocolVersions[0]
+		for _, supported := range protocolVersions {
+			if params.ProtocolVersion == supported {
+				version = supported
+			}
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		names := make([]string, 0, len(s.tools))
+		for name := range s.tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		tools := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			tool := s.tools[name]
+			tools = append(tools, map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": tool.InputSchema,
+			})
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid tools/call params"}
+		}
+		tool, ok := s.tools[params.Name]
+		if !ok {
+			return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)}
+		}
+		if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+			params.Arguments = json.RawMessage("{}")
+		}
+		return s.call(ctx, tool, params.Arguments), nil
+
+	default:
+		if len(req.ID) == 0 {
+			// Notifications such as notifications/initialized need no action
+			return nil, nil
+		}
+		return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// call runs a tool and wraps its result, or its error, as tool content
+func (s *Server) call(ctx context.Context, tool Tool, arguments json.RawMessage) map[string]interface{} {
+	result, err := tool.Handler(ctx, arguments)
+	if err != nil {
+		s.logger.WithField("tool", tool.Name).Warnf("Tool call failed: %v", err)
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": string(text)}},
+	}
+}
+
+func (s *Server) write(resp response) {
+	resp.JSONRPC = "2.0"
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	if err := s.out.Encode(resp); err != nil {
+		s.logger.Errorf("Failed to write MCP response: %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+// Package metrics implements the subset of Prometheus instrumentation the
+// server needs: labelled counters, gauges and histograms, plus callback
+// collectors for values read at scrape time, exposed in the text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets suit request and scrape durations in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Registry holds metrics in registration order
+type Registry struct {
+	mutex   sync.RWMutex
+	metrics []metric
+	names   map[string]bool
+}
+
+type metric interface {
+	header() (name, help, kind string)
+	write(w io.Writer)
+}
+
+// NewRegistry creates a new, empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(m metric) {
+	name, _, _ := m.header()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, m := range r.metrics {
+		name, help, kind := m.header()
+		fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+		m.write(w)
+	}
+}
+
+// Handler serves the registry for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// series is the shared label bookkeeping for counters and gauges
+type series struct {
+	name, help, kind string
+	labelNames       []string
+
+	mutex  sync.Mutex
+	values map[string]*float64
+	labels map[string][]string
+}
+
+func newSeries(name, help, kind string, labelNames []string) *series {
+	return &series{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		values:     make(map[string]*float64),
+		labels:     make(map[string][]string),
+	}
+}
+
+func (s *series) header() (string, string, string) {
+	return s.name, s.help, s.kind
+}
+
+func (s *series) value(labelValues []string) *float64 {
+	if len(labelValues) != len(s.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", s.name, len(s.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	value, ok := s.values[key]
+	if !ok {
+		value = new(float64)
+		s.values[key] = value
+		s.labels[key] = append([]string(nil), labelValues...)
+	}
+	return value
+}
+
+func (s *series) write(w io.Writer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, key := range sortedKeys(s.values) {
+		fmt.Fprintf(w, "%s%s %s\n", s.name, formatLabels(s.labelNames, s.labels[key]), formatValue(*s.values[key]))
+	}
+}
+
+// Counter is a monotonically increasing value partitioned by labels
+type Counter struct {
+	*series
+}
+
+// NewCounter creates a new counter and registers it
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	c := &Counter{newSeries(name, help, "counter", labelNames)}
+	r.register(c)
+	return c
+}
+
+// Add increases the counter for the given label values
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.mutex.Lock()
+	*c.value(labelValues) += delta
+	c.mutex.Unlock()
+}
+
+// Inc increments the counter for the given label values by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Gauge is a value that can go up and down, partitioned by labels
+type Gauge struct {
+	*series
+}
+
+// NewGauge creates a new gauge and registers it
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	g := &Gauge{newSeries(name, help, "gauge", labelNames)}
+	r.register(g)
+	return g
+}
+
+// Set sets the gauge for the given label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.mutex.Lock()
+	*g.value(labelValues) = value
+	g.mutex.Unlock()
+}
+
+// Add adjusts the gauge for the given label values by delta
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.mutex.Lock()
+	*g.value(labelValues) += delta
+	g.mutex.Unlock()
+}
+
+// Histogram counts observations into cumulative buckets, partitioned by labels
+type Histogram struct {
+	name, help string
+	labelNames []string
+	buckets    []float64
+
+	mutex  sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a new histogram and registers it; nil buckets use
+// DefaultBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	h := &Histogram{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+func (h *Histogram) header() (string, string, string) {
+	return h.name, h.help, "histogram"
+}
+
+// Observe records one observation for the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", h.name, len(h.labelNames), len(labelValues)))
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bucketLabels := append(append([]string(nil), h.labelNames...), "le")
+	for _, key := range keys {
+		s := h.series[key]
+		for i, bound := range h.buckets {
+			labels := append(append([]string(nil), s.labels...), formatValue(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(bucketLabels, labels), s.counts[i])
+		}
+		labels := append(append([]string(nil), s.labels...), "+Inf")
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(bucketLabels, labels), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, s.labels), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, s.labels), s.count)
+	}
+}
+
+// Sample is one labelled value produced by a collector function
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+type funcMetric struct {
+	name, help, kind string
+	labelNames       []string
+	collect          func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose samples are computed at scrape time
+func (r *Registry) NewGaugeFunc(name, help string, collect func() []Sample, labelNames ...string) {
+	r.register(&funcMetric{name: name, help: help, kind: "gauge", labelNames: labelNames, collect: collect})
+}
+
+// NewCounterFunc registers a counter whose samples are read at scrape time
+// from an existing cumulative source
+func (r *Registry) NewCounterFunc(name, help string, collect func() []Sample, labelNames ...string) {
+	r.register(&funcMetric{name: name, help: help, kind: "counter", labelNames: labelNames, collect: collect})
+}
+
+func (f *funcMetric) header() (string, string, string) {
+	return f.name, f.help, f.kind
+}
+
+func (f *funcMetric) write(w io.Writer) {
+	samples := f.collect()
+	sort.SliceStable(samples, func(i, j int) bool {
+		return strings.Join(samples[i].LabelValues, "\xff") < strings.Join(samples[j].LabelValues, "\xff")
+	})
+	for _, sample := range samples {
+		fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(f.labelNames, sample.LabelValues), formatValue(sample.Value))
+	}
+}
+
+func sortedKeys(values map[string]*float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,167 @@
+package models
+
+import "time"
+
+// Scrape run states reported by the REST API. Queued runs wait for a free
+// worker; completed, failed and cancelled runs are done.
+const (
+	RunStatusQueued    = "queued"
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+	RunStatusCancelled = "cancelled"
+)
+
+// ScrapeRun describes a scrape requested through POST /scrape
+type ScrapeRun struct {
+	ID         string           `json:"id"`
+	Status     string           `json:"status"`
+	Keywords   []string         `json:"keywords"`
+	Location   string           `json:"location"`
+	QueuedAt   time.Time        `json:"queued_at"`
+	StartedAt  *time.Time       `json:"started_at,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	JobsFound  int              `json:"jobs_found"`
+	Sources    []SourceProgress `json:"sources,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// Done reports whether the run has reached a final state
+func (r *ScrapeRun) Done() bool {
+	return r.Status == RunStatusCompleted || r.Status == RunStatusFailed || r.Status == RunStatusCancelled
+}
+
+// Source progress states within a scrape run
+const (
+	SourcePending   = "pending"
+	SourceRunning   = "running"
+	SourceCompleted = "completed"
+	SourceFailed    = "failed"
+	// SourceDeferred sources stopped at a spent request budget and are
+	// left for a later run
+	SourceDeferred = "deferred"
+)
+
+// SourceProgress is the state of one job board, or of the API providers as a
+// group, within a scrape run
+type SourceProgress struct {
+	Source    string `json:"source"`
+	Status    string `json:"status"`
+	JobsFound int    `json:"jobs_found"`
+	Error     string `json:"error,omitempty"`
+	// Duration is how long the source took, set once it completed or failed
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// RunReport summarises one finished scrape run, whether started from the
+// CLI, the watch loop or the API
+type RunReport struct {
+	ID         string   `json:"id"`
+	Keywords   []string `json:"keywords"`
+	Location   string   `json:"location"`
+	ConfigHash string   `json:"config_hash,omitempty"`
+	// Schedule names the daemon schedule that started the run
+	Schedule   string    `json:"schedule,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	JobsFound  int       `json:"jobs_found"`
+	NewJobs    int       `json:"new_jobs"`
+	// Duplicates are the jobs found that were already stored, or found
+	// twice in the run, and were merged into the stored copy
+	Duplicates int              `json:"duplicates"`
+	Sources    []SourceProgress `json:"sources,omitempty"`
+	// Error is set when the run failed as a whole
+	Error string `json:"error,omitempty"`
+}
+
+// FailedSources returns the sources that failed during the run
+func (r RunReport) FailedSources() []SourceProgress {
+	var failed []SourceProgress
+	for _, source := range r.Sources {
+		if source.Status == SourceFailed {
+			failed = append(failed, source)
+		}
+	}
+	return failed
+}
+
+// ScrapeRequest is the body accepted by POST /scrape
+type ScrapeRequest struct {
+	Keywords []string `json:"keywords"`
+	Location string   `json:"location"`
+}
+
+// ProviderStatus is one entry of GET /providers/status
+type ProviderStatus struct {
+	Name     string         `json:"name"`
+	Provider string         `json:"provider"`
+	Enabled  bool           `json:"enabled"`
+	Stats    *ProviderStats `json:"stats,omitempty"`
+	Valid    *bool          `json:"valid,omitempty"`
+	// ValidationStatus is valid, not_configured, invalid, timeout or failed
+	ValidationStatus string `json:"validation_status,omitempty"`
+	Validation       string `json:"validation_error,omitempty"`
+}
+
+// ProviderStats is the usage of one API provider since the server started
+type ProviderStats struct {
+	TotalRequests   int           `json:"total_requests"`
+	SuccessRequests int           `json:"success_requests"`
+	FailedRequests  int           `json:"failed_requests"`
+	TotalJobs       int           `json:"total_jobs"`
+	AverageLatency  time.Duration `json:"average_latency"`
+	LastUsed        time.Time     `json:"last_used"`
+	// CircuitState is closed, open while the provider is rested after
+	// failing repeatedly, or half-open while one probe is let through
+	CircuitState        string     `json:"circuit_state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CircuitOpenUntil    *time.Time `json:"circuit_open_until,omitempty"`
+}
+
+// BoardSummary is one entry of GET /boards. Selectors are left out; GET
+// /boards/{name} returns the full entry.
+type BoardSummary struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Method     string `json:"method"`
+	BaseURL    string `json:"base_url"`
+	SearchPath string `json:"search_path,omitempty"`
+	RateLimit  int    `json:"rate_limit"`
+	MaxResults int    `json:"max_results"`
+}
+
+// ProfileUpdate is the body accepted by PATCH /me; nil fields are left as is
+type ProfileUpdate struct {
+	Name          *string               `json:"name"`
+	Email         *string               `json:"email"`
+	Notifications *NotificationSettings `json:"notifications"`
+}
+
+// ApplicationRequest is the body accepted by PUT /applications/{job_id}
+type ApplicationRequest struct {
+	Status string `json:"status"`
+	Notes  string `json:"notes"`
+}
+
+// IngestRequest is the body accepted by POST /ingest. URL is required. HTML
+// is parsed for the posting, Text is the user's selection and becomes the
+// description; with neither, the server fetches URL itself. The remaining
+// fields override whatever was extracted.
+type IngestRequest struct {
+	URL      string   `json:"url"`
+	HTML     string   `json:"html,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Company  string   `json:"company,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Salary   string   `json:"salary,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// IngestResult is returned by POST /ingest. Duplicate is set, and Job is the
+// stored copy, when the posting was already in the store.
+type IngestResult struct {
+	Job       Job  `json:"job"`
+	Duplicate bool `json:"duplicate"`
+}
@@ -0,0 +1,435 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Operators of keyword expressions
+const (
+	ExprTerm = iota
+	ExprAnd
+	ExprOr
+	ExprNot
+)
+
+// KeywordExpr is a parsed boolean keyword query: a term, or AND, OR or NOT
+// over its children. NOT has one child; AND and OR have two or more.
+type KeywordExpr struct {
+	Op int
+	// Term is a lowercased word or phrase, split into words as Tokenize
+	// splits them
+	Term     string
+	Children []*KeywordExpr
+
+	// words are Term's words, split once when the term is made
+	words []string
+}
+
+// newTerm makes a term node of words
+func newTerm(words []string) *KeywordExpr {
+	return &KeywordExpr{Op: ExprTerm, Term: strings.Join(words, " "), words: words}
+}
+
+// IsBooleanQuery reports whether query uses the AND, OR or NOT operators,
+// which are written in capitals, outside quotes. Parentheses alone do not
+// make a query boolean, so plain keywords such as "C++ (modern)" keep
+// their meaning.
+func IsBooleanQuery(query string) bool {
+	tokens, err := lexKeywordExpr(query)
+	if err != nil {
+		// An unclosed quote is reported by parsing if the query has
+		// operators
+		tokens = nil
+		for _, word := range strings.Fields(query) {
+			tokens = append(tokens, exprToken{text: word})
+		}
+	}
+	for _, token := range tokens {
+		switch token.text {
+		case "AND", "OR", "NOT":
+			if !token.quoted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseKeywordExpr parses a boolean keyword query, such as
+// `golang AND (kubernetes OR terraform) NOT intern`. Terms are words or
+// "quoted phrases"; terms next to each other must both match, as with AND,
+// which binds tighter than OR; and NOT, or a leading -, excludes the term
+// or group after it.
+func ParseKeywordExpr(query string) (*KeywordExpr, error) {
+	tokens, err := lexKeywordExpr(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty keyword query")
+	}
+	p := &exprParser{tokens: tokens}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in keyword query", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+// KeywordExpression reads a list of scrape keywords, any of which may
+// match, as one expression when any of them is a boolean query, and returns
+// nil when none is. Stored-job filters take boolean queries only in
+// JobFilter.KeywordQuery.
+func KeywordExpression(keywords []string) (*KeywordExpr, error) {
+	boolean := false
+	for _, keyword := range keywords {
+		if IsBooleanQuery(keyword) {
+			boolean = true
+			break
+		}
+	}
+	if !boolean {
+		return nil, nil
+	}
+
+	var alternatives []*KeywordExpr
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword) == "" {
+			continue
+		}
+		expr, err := ParseKeywordExpr(keyword)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, expr)
+	}
+	return combine(ExprOr, alternatives), nil
+}
+
+// Eval evaluates the expression, asking has whether each term matches
+func (e *KeywordExpr) Eval(has func(term string) bool) bool {
+	switch e.Op {
+	case ExprTerm:
+		return has(e.Term)
+	case ExprNot:
+		return !e.Children[0].Eval(has)
+	case ExprAnd:
+		for _, child := range e.Children {
+			if !child.Eval(has) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, child := range e.Children {
+			if child.Eval(has) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Match reports whether job's title, description and keywords satisfy the
+// expression, a phrase matching where its words appear in order. The
+// fields are tokenized once per job; single words, most terms, are then
+// looked up in a set and only phrases scan the tokens.
+func (e *KeywordExpr) Match(job *Job) bool {
+	fields := TextFields(job)
+	var tokens [][]string
+	present := make(map[string]bool)
+	for _, field := range []int{FieldTitle, FieldDescription, FieldKeywords} {
+		words := Tokenize(fields[field])
+		tokens = append(tokens, words)
+		for _, word := range words {
+			present[word] = true
+		}
+	}
+	return e.match(tokens, present)
+}
+
+func (e *KeywordExpr) match(tokens [][]string, present map[string]bool) bool {
+	switch e.Op {
+	case ExprTerm:
+		words := e.words
+		if words == nil {
+			words = strings.Fields(e.Term)
+		}
+		for _, word := range words {
+			if !present[word] {
+				return false
+			}
+		}
+		if len(words) == 1 {
+			return true
+		}
+		phrase := QueryTerm{Words: words}
+		for _, field := range tokens {
+			for i := range field {
+				if phrase.MatchesAt(field, i) {
+					return true
+				}
+			}
+		}
+		return false
+	case ExprNot:
+		return !e.Children[0].match(tokens, present)
+	case ExprAnd:
+		for _, child := range e.Children {
+			if !child.match(tokens, present) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, child := range e.Children {
+			if child.match(tokens, present) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Terms returns the terms a match may rest on, and those under a NOT,
+// each once in the order they appear
+func (e *KeywordExpr) Terms() (include, exclude []string) {
+	seen := make(map[string]bool)
+	var walk func(e *KeywordExpr, negated bool)
+	walk = func(e *KeywordExpr, negated bool) {
+		switch e.Op {
+		case ExprTerm:
+			key := fmt.Sprintf("%t %s", negated, e.Term)
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+			if negated {
+				exclude = append(exclude, e.Term)
+			} else {
+				include = append(include, e.Term)
+			}
+		case ExprNot:
+			walk(e.Children[0], !negated)
+		default:
+			for _, child := range e.Children {
+				walk(child, negated)
+			}
+		}
+	}
+	walk(e, false)
+	return include, exclude
+}
+
+// Expand returns a copy of the expression in which each term also matches
+// the alternatives returned for it, such as its synonyms
+func (e *KeywordExpr) Expand(alternatives func(term string) []string) *KeywordExpr {
+	if e.Op != ExprTerm {
+		children := make([]*KeywordExpr, len(e.Children))
+		for i, child := range e.Children {
+			children[i] = child.Expand(alternatives)
+		}
+		return &KeywordExpr{Op: e.Op, Children: children}
+	}
+
+	terms := []*KeywordExpr{newTerm(strings.Fields(e.Term))}
+	seen := map[string]bool{e.Term: true}
+	for _, alternative := range alternatives(e.Term) {
+		if words := Tokenize(alternative); len(words) > 0 && !seen[strings.Join(words, " ")] {
+			seen[strings.Join(words, " ")] = true
+			terms = append(terms, newTerm(words))
+		}
+	}
+	return combine(ExprOr, terms)
+}
+
+// String writes the expression back as a query, parenthesizing every group
+func (e *KeywordExpr) String() string {
+	switch e.Op {
+	case ExprTerm:
+		if strings.Contains(e.Term, " ") {
+			return `"` + e.Term + `"`
+		}
+		return e.Term
+	case ExprNot:
+		return "NOT " + e.Children[0].group()
+	}
+	op := " AND "
+	if e.Op == ExprOr {
+		op = " OR "
+	}
+	parts := make([]string, len(e.Children))
+	for i, child := range e.Children {
+		parts[i] = child.group()
+	}
+	return strings.Join(parts, op)
+}
+
+func (e *KeywordExpr) group() string {
+	if e.Op == ExprAnd || e.Op == ExprOr {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// combine joins expressions under op, flattening nested groups of the same
+// operator; a single expression is returned as it is
+func combine(op int, exprs []*KeywordExpr) *KeywordExpr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	combined := &KeywordExpr{Op: op}
+	for _, expr := range exprs {
+		if expr.Op == op {
+			combined.Children = append(combined.Children, expr.Children...)
+		} else {
+			combined.Children = append(combined.Children, expr)
+		}
+	}
+	return combined
+}
+
+// exprToken is a word, phrase, parenthesis or leading - of a keyword query
+type exprToken struct {
+	text   string
+	quoted bool
+}
+
+func lexKeywordExpr(query string) ([]exprToken, error) {
+	var tokens []exprToken
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		switch rest[0] {
+		case '(', ')':
+			tokens = append(tokens, exprToken{text: rest[:1]})
+			rest = rest[1:]
+			continue
+		case '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed quote in keyword query")
+			}
+			tokens = append(tokens, exprToken{text: rest[1 : end+1], quoted: true})
+			rest = rest[end+2:]
+			continue
+		case '-':
+			// A - excludes what follows it only at the start of a word
+			tokens = append(tokens, exprToken{text: "-"})
+			rest = rest[1:]
+			continue
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+		})
+		if end < 0 {
+			end = len(rest)
+		}
+		tokens = append(tokens, exprToken{text: rest[:end]})
+		rest = rest[end:]
+	}
+	return tokens, nil
+}
+
+// exprParser parses the tokens of a keyword query by recursive descent:
+// OR of ANDs of NOTs of terms and parenthesized groups
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+// peek returns the next operator or parenthesis, or "" for a term or the
+// end of the query
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return ""
+	}
+	switch text := p.tokens[p.pos].text; text {
+	case "AND", "OR", "NOT", "(", ")", "-":
+		return text
+	}
+	return ""
+}
+
+func (p *exprParser) or() (*KeywordExpr, error) {
+	var alternatives []*KeywordExpr
+	for {
+		expr, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, expr)
+		if p.peek() != "OR" {
+			return combine(ExprOr, alternatives), nil
+		}
+		p.pos++
+	}
+}
+
+func (p *exprParser) and() (*KeywordExpr, error) {
+	var operands []*KeywordExpr
+	for {
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, expr)
+
+		switch p.peek() {
+		case "AND":
+			p.pos++
+		case "OR", ")":
+			return combine(ExprAnd, operands), nil
+		default:
+			if p.pos >= len(p.tokens) {
+				return combine(ExprAnd, operands), nil
+			}
+		}
+	}
+}
+
+func (p *exprParser) not() (*KeywordExpr, error) {
+	switch p.peek() {
+	case "NOT", "-":
+		p.pos++
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &KeywordExpr{Op: ExprNot, Children: []*KeywordExpr{expr}}, nil
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (*KeywordExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("keyword query ends where a term was expected")
+	}
+	switch op := p.peek(); op {
+	case "(":
+		p.pos++
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ) in keyword query")
+		}
+		p.pos++
+		return expr, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("unexpected %q in keyword query", op)
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+	words := Tokenize(token.text)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("keyword query term %q has no words", token.text)
+	}
+	return newTerm(words), nil
+}
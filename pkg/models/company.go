@@ -0,0 +1,217 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CompanyConfig is the companies section of GlobalSettings. Boards name one
+// company in several ways, so Aliases groups the names of each company,
+// the first being the one shown; "Meta", "Facebook" and "Meta Platforms,
+// Inc." can then be deduplicated, counted and listed as one company.
+type CompanyConfig struct {
+	Aliases [][]string `json:"aliases,omitempty"`
+	// Allow, when not empty, keeps only the jobs of these companies, and
+	// Deny drops the jobs of these; a company matches by any of its aliases
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// The company settings apply to every job, like the display zone
+var (
+	companyMutex     sync.RWMutex
+	companyCanonical = map[string]string{}
+	companyAllow     = map[string]bool{}
+	companyDeny      = map[string]bool{}
+)
+
+// SetCompanies applies the company aliases and allow and deny lists of
+// config, replacing any set before; a nil config clears them
+func SetCompanies(config *CompanyConfig) error {
+	canonical := make(map[string]string)
+	allow := make(map[string]bool)
+	deny := make(map[string]bool)
+	if config != nil {
+		for _, group := range config.Aliases {
+			if len(group) < 2 {
+				return fmt.Errorf("company alias group %q needs at least two names", group)
+			}
+			name := strings.Join(strings.Fields(group[0]), " ")
+			for _, alias := range group {
+				key := normalizeCompany(alias)
+				if key == "" {
+					return fmt.Errorf("company alias group %q has an empty name", group)
+				}
+				if other, ok := canonical[key]; ok && other != name {
+					return fmt.Errorf("company %q is an alias of both %q and %q", alias, other, name)
+				}
+				canonical[key] = name
+			}
+		}
+		companyKey := func(name string) string {
+			key := normalizeCompany(name)
+			if name, ok := canonical[key]; ok {
+				return normalizeCompany(name)
+			}
+			return key
+		}
+		for _, name := range config.Allow {
+			allow[companyKey(name)] = true
+		}
+		for _, name := range config.Deny {
+			key := companyKey(name)
+			if allow[key] {
+				return fmt.Errorf("company %q is both allowed and denied", name)
+			}
+			deny[key] = true
+		}
+	}
+
+	companyMutex.Lock()
+	companyCanonical, companyAllow, companyDeny = canonical, allow, deny
+	companyMutex.Unlock()
+	return nil
+}
+
+// CompanyName returns the name a company is shown under: the first name of
+// its alias group, or else name with its spacing tidied and any legal form
+// such as LLC or GmbH dropped
+func CompanyName(name string) string {
+	companyMutex.RLock()
+	defer companyMutex.RUnlock()
+	if canonical, ok := companyCanonical[normalizeCompany(name)]; ok {
+		return canonical
+	}
+	return trimLegalForm(strings.Join(strings.Fields(name), " "))
+}
+
+// CompanyKey identifies a company regardless of case, spacing and which
+// of its aliases name is
+func CompanyKey(name string) string {
+	return normalizeCompany(CompanyName(name))
+}
+
+// CompanyAllowed reports whether jobs at the company pass the allow and
+// deny lists
+func CompanyAllowed(name string) bool {
+	key := CompanyKey(name)
+	companyMutex.RLock()
+	defer companyMutex.RUnlock()
+	if companyDeny[key] {
+		return false
+	}
+	return len(companyAllow) == 0 || companyAllow[key]
+}
+
+// normalizeCompany lowercases a company name, tidies its spacing and drops
+// its legal form
+func normalizeCompany(name string) string {
+	return strings.ToLower(trimLegalForm(strings.Join(strings.Fields(name), " ")))
+}
+
+// legalForm matches a legal form ending a company name, as in "Google
+// LLC", "Acme, Inc." or "Siemens AG"
+var legalForm = regexp.MustCompile(`(?i)(,|\s)\s*(inc|incorporated|llc|l\.l\.c|ltd|limited|corp|corporation|co|plc|gmbh|ag|kg|se|sa|s\.a|sas|sarl|srl|s\.r\.l|spa|s\.p\.a|bv|b\.v|nv|n\.v|oy|ab|pty|pvt|lp|llp)\.?$`)
+
+// trimLegalForm drops the legal forms ending name, as in "Foo Pvt. Ltd." or
+// "Bar GmbH & Co. KG", unless nothing would be left
+func trimLegalForm(name string) string {
+	for {
+		trimmed := strings.TrimRight(legalForm.ReplaceAllString(name, ""), " ,&")
+		if trimmed == name || trimmed == "" {
+			return name
+		}
+		name = trimmed
+	}
+}
+
+// Company is an employer with the stored jobs posted under any of its
+// names
+type Company struct {
+	// Name is the name the company is shown under and Key identifies it,
+	// as CompanyName and CompanyKey give them
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	// Aliases are the other names its jobs gave it
+	Aliases []string `json:"aliases,omitempty"`
+	// Website and Logo are URLs, when a source gave them
+	Website     string    `json:"website,omitempty"`
+	Logo        string    `json:"logo,omitempty"`
+	JobCount    int       `json:"job_count"`
+	ActiveJobs  int       `json:"active_jobs"`
+	Sources     []string  `json:"sources"`
+	Locations   []string  `json:"locations,omitempty"`
+	LatestJobAt time.Time `json:"latest_job_at"`
+}
+
+// GroupCompanies groups jobs by company, counting a company's aliases
+// together, largest employers first. Jobs without a company are left out.
+func GroupCompanies(jobs []Job) []Company {
+	index := make(map[string]int)
+	var companies []Company
+	for i := range jobs {
+		job := &jobs[i]
+		key := CompanyKey(job.Company)
+		if key == "" {
+			continue
+		}
+		n, ok := index[key]
+		if !ok {
+			n = len(companies)
+			index[key] = n
+			companies = append(companies, Company{Name: CompanyName(job.Company), Key: key})
+		}
+		c := &companies[n]
+
+		c.JobCount++
+		if job.IsActive {
+			c.ActiveJobs++
+		}
+		if name := strings.Join(strings.Fields(job.Company), " "); name != c.Name {
+			c.Aliases = appendNew(c.Aliases, name)
+		}
+		c.Sources = appendNew(c.Sources, job.Source)
+		if job.Location != "" {
+			c.Locations = appendNew(c.Locations, job.Location)
+		}
+		// The latest job's website and logo win
+		if !job.ScrapedAt.Before(c.LatestJobAt) {
+			c.LatestJobAt = job.ScrapedAt
+			if job.CompanyWebsite != "" {
+				c.Website = job.CompanyWebsite
+			}
+			if job.CompanyLogo != "" {
+				c.Logo = job.CompanyLogo
+			}
+		} else {
+			if c.Website == "" {
+				c.Website = job.CompanyWebsite
+			}
+			if c.Logo == "" {
+				c.Logo = job.CompanyLogo
+			}
+		}
+	}
+
+	sort.SliceStable(companies, func(i, j int) bool {
+		if companies[i].JobCount != companies[j].JobCount {
+			return companies[i].JobCount > companies[j].JobCount
+		}
+		return strings.ToLower(companies[i].Name) < strings.ToLower(companies[j].Name)
+	})
+	return companies
+}
+
+// appendNew appends value unless values already holds it
+func appendNew(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
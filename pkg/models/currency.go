@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultSalaryCurrency is the currency of salaries that state none; most
+// boards that leave it out are American
+const DefaultSalaryCurrency = "USD"
+
+// defaultExchangeRates are units of each currency per euro, the way the
+// ECB quotes them. They are approximate and only serve until live rates
+// are loaded or when none can be fetched.
+var defaultExchangeRates = map[string]float64{
+	"EUR": 1,
+	"USD": 1.08,
+	"GBP": 0.85,
+	"INR": 90,
+	"CAD": 1.47,
+	"AUD": 1.64,
+	"NZD": 1.79,
+	"SGD": 1.45,
+	"CHF": 0.95,
+	"JPY": 162,
+	"BRL": 5.8,
+	"SEK": 11.4,
+	"NOK": 11.6,
+	"DKK": 7.46,
+	"PLN": 4.3,
+	"CZK": 25,
+	"ZAR": 20,
+	"MXN": 19.5,
+	"HKD": 8.45,
+	"CNY": 7.8,
+}
+
+// The exchange rates apply to every filter, like the display zone
+var (
+	ratesMutex    sync.RWMutex
+	exchangeRates = defaultExchangeRates
+)
+
+// DefaultExchangeRates returns a copy of the built-in rates, in units per
+// euro
+func DefaultExchangeRates() map[string]float64 {
+	rates := make(map[string]float64, len(defaultExchangeRates))
+	for currency, rate := range defaultExchangeRates {
+		rates[currency] = rate
+	}
+	return rates
+}
+
+// SetExchangeRates replaces the rates salaries are converted with. Rates
+// are units per euro; currencies left out keep their built-in rate, and a
+// nil map restores the built-in rates.
+func SetExchangeRates(rates map[string]float64) error {
+	merged := DefaultExchangeRates()
+	for currency, rate := range rates {
+		if rate <= 0 {
+			return fmt.Errorf("exchange rate for %q must be positive", currency)
+		}
+		merged[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+	merged["EUR"] = 1
+	ratesMutex.Lock()
+	exchangeRates = merged
+	ratesMutex.Unlock()
+	return nil
+}
+
+// KnownCurrency reports whether salaries can be converted to and from the
+// ISO 4217 code currency
+func KnownCurrency(currency string) bool {
+	ratesMutex.RLock()
+	defer ratesMutex.RUnlock()
+	_, ok := exchangeRates[strings.ToUpper(currency)]
+	return ok
+}
+
+// Currencies lists the currencies salaries can be converted between
+func Currencies() []string {
+	ratesMutex.RLock()
+	defer ratesMutex.RUnlock()
+	currencies := make([]string, 0, len(exchangeRates))
+	for currency := range exchangeRates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// ConvertCurrency converts amount from one currency to another. It reports
+// false when either currency has no rate.
+func ConvertCurrency(amount float64, from, to string) (float64, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, true
+	}
+	ratesMutex.RLock()
+	defer ratesMutex.RUnlock()
+	fromRate, ok := exchangeRates[from]
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := exchangeRates[to]
+	if !ok {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// minTitleSimilarity is the share of title words two postings behind the
+// same link must have in common to be one posting. A careers page that
+// links every job to itself is not enough to merge them.
+const minTitleSimilarity = 0.5
+
+// titleNoise matches gender tags such as (m/w/d) and (f/m/x) that boards in
+// some countries append to titles
+var titleNoise = regexp.MustCompile(`\(\s*[mwfdx]\s*/\s*[mwfdx]\s*(/\s*[mwfdx]\s*)?\)`)
+
+// titleAbbreviations spell out the words boards abbreviate differently
+var titleAbbreviations = map[string]string{
+	"sr":    "senior",
+	"snr":   "senior",
+	"jr":    "junior",
+	"jnr":   "junior",
+	"eng":   "engineer",
+	"engr":  "engineer",
+	"dev":   "developer",
+	"mgr":   "manager",
+	"assoc": "associate",
+}
+
+// trackingParams are query parameters that say where a link was shared
+// rather than which posting it leads to
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "ref": true, "refid": true, "trk": true,
+	"trackingid": true, "src": true, "source": true, "from": true,
+}
+
+// TitleKey normalizes a job title for matching: lowercased, punctuation and
+// gender tags dropped and common abbreviations spelled out, so
+// "Sr. Go Engineer (m/w/d)" and "Senior Go Engineer" match
+func TitleKey(title string) string {
+	title = titleNoise.ReplaceAllString(strings.ToLower(title), " ")
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '#' || r > 127)
+	})
+	for i, word := range words {
+		if full, ok := titleAbbreviations[word]; ok {
+			words[i] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// PostingKey identifies a posting across sources by its title and company
+func PostingKey(job *Job) string {
+	return TitleKey(job.Title) + "|" + CompanyKey(job.Company)
+}
+
+// LinkKey normalizes a job link for matching: the scheme, a leading www.,
+// the fragment, a trailing slash and tracking parameters are dropped and
+// the rest of the query sorted. Links that are not http or https give "".
+func LinkKey(link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
+	}
+	query := parsed.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	key := host + strings.TrimSuffix(parsed.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+	return key
+}
+
+// LocationsMatch reports whether two locations may be the same place: one
+// is unknown, or the words of one are all in the other, as with "Berlin"
+// and "Berlin, Germany"
+func LocationsMatch(a, b string) bool {
+	wordsA, wordsB := locationWords(a), locationWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return true
+	}
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	for word := range wordsA {
+		if !wordsB[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// TitlesSimilar reports whether two titles share enough words to name the
+// same job
+func TitlesSimilar(a, b string) bool {
+	wordsA, wordsB := strings.Fields(TitleKey(a)), strings.Fields(TitleKey(b))
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return false
+	}
+	set := make(map[string]bool, len(wordsA))
+	for _, word := range wordsA {
+		set[word] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(wordsB))
+	for _, word := range wordsB {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared)/float64(union) >= minTitleSimilarity
+}
+
+func locationWords(location string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(location), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	})
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
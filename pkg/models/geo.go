@@ -0,0 +1,31 @@
+package models
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// GeoPoint is a place on the map in decimal degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// DistanceKm returns the great-circle distance to other in kilometres
+func (p GeoPoint) DistanceKm(other GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Lon - p.Lon) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Within reports whether the job's location lies within the filter's
+// radius. Jobs whose location could not be placed on the map, remote ones
+// among them, are not within any radius.
+func (f JobFilter) Within(job *Job) bool {
+	if f.Center == nil || f.RadiusKm <= 0 {
+		return true
+	}
+	return job.Coordinates != nil && job.Coordinates.DistanceKm(*f.Center) <= f.RadiusKm
+}
@@ -22,19 +22,242 @@ type Job struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsActive    bool      `json:"is_active"`
 	Relevance   float64   `json:"relevance"`
+	// PostedAt is when the source says the job was posted, in UTC, and
+	// PostedZone the zone it gave the date in, e.g. Europe/London, EST or
+	// +09:00. Both are empty when the source gives no date.
+	PostedAt   *time.Time `json:"posted_at,omitempty"`
+	PostedZone string     `json:"posted_zone,omitempty"`
+	// CompanyWebsite and CompanyLogo are URLs, when the source gives them
+	CompanyWebsite string `json:"company_website,omitempty"`
+	CompanyLogo    string `json:"company_logo,omitempty"`
+	// EmploymentType is full-time, part-time, contract or intern, and
+	// RemoteType remote, hybrid or onsite; each comes from the source when
+	// it says, is otherwise read from the posting, and is empty when
+	// neither tells
+	EmploymentType string `json:"employment_type,omitempty"`
+	RemoteType     string `json:"remote_type,omitempty"`
+	// Coordinates place Location on the map, when it could be geocoded
+	Coordinates *GeoPoint `json:"coordinates,omitempty"`
+	// NotifiedAt records when the job was sent to each notification
+	// channel, so it is never announced there twice
+	NotifiedAt map[string]time.Time `json:"notified_at,omitempty"`
+	// Summary is an optional LLM digest of Description
+	Summary *JobSummary `json:"summary,omitempty"`
+	// Enrichment holds the details an LLM read from Description
+	Enrichment *JobEnrichment `json:"enrichment,omitempty"`
+	// Match is set when jobs are scored against a resume
+	Match *JobMatch `json:"match,omitempty"`
+	// SalaryRange is Salary as numbers, when it could be worked out
+	SalaryRange *SalaryRange `json:"salary_range,omitempty"`
+	// Classification tags the job's role, seniority and industry
+	Classification *JobClassification `json:"classification,omitempty"`
+	// CheckedAt is when the job's link was last revisited to see whether
+	// the posting is still up, and ExpiredAt when it was found taken down.
+	// An expired job is no longer active; scraping it again revives it.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	// Status is where the job stands in your search: new, seen, applied,
+	// rejected or archived. StatusChangedAt is when it was last set.
+	Status          string     `json:"status,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+}
+
+// Where a JobClassification came from
+const (
+	ClassificationSourceRules = "rules"
+	ClassificationSourceLLM   = "llm"
+)
+
+// JobClassification tags a job for faceted filtering and stats
+type JobClassification struct {
+	// Category is the role, e.g. backend, data or sre
+	Category string `json:"category"`
+	// Seniority is intern, junior, mid, senior, lead or director
+	Seniority string `json:"seniority"`
+	Industry  string `json:"industry"`
+	// Source is rules or llm
+	Source string `json:"source"`
+}
+
+// Where a SalaryRange came from
+const (
+	SalarySourceParsed = "parsed"
+	SalarySourceLLM    = "llm"
+)
+
+// salaryPeriods converts each pay period to a yearly figure
+var salaryPeriods = map[string]float64{
+	"hour":  2080,
+	"day":   260,
+	"week":  52,
+	"month": 12,
+	"year":  1,
+}
+
+// SalaryRange is a salary normalised to numbers
+type SalaryRange struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency,omitempty"`
+	// Period is hour, day, week, month or year
+	Period string `json:"period"`
+	// Source is parsed when read from Salary and llm when extracted from
+	// the description
+	Source string `json:"source"`
+}
+
+// ValidSalaryPeriod reports whether period is one SalaryRange supports
+func ValidSalaryPeriod(period string) bool {
+	_, ok := salaryPeriods[period]
+	return ok
+}
+
+// Yearly returns the range as yearly figures
+func (r *SalaryRange) Yearly() (min, max float64) {
+	factor, ok := salaryPeriods[r.Period]
+	if !ok {
+		factor = 1
+	}
+	return r.Min * factor, r.Max * factor
+}
+
+func (r *SalaryRange) String() string {
+	amount := fmt.Sprintf("%.0f", r.Min)
+	if r.Max > r.Min {
+		amount += fmt.Sprintf("-%.0f", r.Max)
+	}
+	if r.Currency != "" {
+		amount = r.Currency + " " + amount
+	}
+	return amount + " per " + r.Period
+}
+
+// JobMatch is how well a job fits a resume, with the gaps that lowered the
+// score
+type JobMatch struct {
+	// Score runs from 0 (no fit) to 1
+	Score         float64  `json:"score"`
+	MatchedSkills []string `json:"matched_skills,omitempty"`
+	MissingSkills []string `json:"missing_skills,omitempty"`
+	RequiredYears int      `json:"required_years,omitempty"`
+	TitleMatch    bool     `json:"title_match"`
+	Gaps          []string `json:"gaps,omitempty"`
+}
+
+// JobSummary condenses a job description into three short bullets
+type JobSummary struct {
+	Responsibilities string    `json:"responsibilities"`
+	MustHaveSkills   string    `json:"must_have_skills"`
+	RedFlags         string    `json:"red_flags"`
+	Model            string    `json:"model,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Bullets returns the summary as labelled lines
+func (s *JobSummary) Bullets() []string {
+	return []string{
+		"Responsibilities: " + s.Responsibilities,
+		"Must-have skills: " + s.MustHaveSkills,
+		"Red flags: " + s.RedFlags,
+	}
+}
+
+// Whether a job's employer sponsors visas, as JobEnrichment reads it
+const (
+	VisaSponsorshipYes     = "yes"
+	VisaSponsorshipNo      = "no"
+	VisaSponsorshipUnknown = "unknown"
+)
+
+// JobEnrichment is what an LLM read from a job description. Details the
+// posting does not state are left empty, and VisaSponsorship unknown.
+type JobEnrichment struct {
+	RequiredSkills []string `json:"required_skills,omitempty"`
+	// TechStack is the languages, frameworks and tools the team works with
+	TechStack []string `json:"tech_stack,omitempty"`
+	// YearsExperience is the fewest years of experience asked for
+	YearsExperience int `json:"years_experience,omitempty"`
+	// VisaSponsorship is yes, no or unknown
+	VisaSponsorship string `json:"visa_sponsorship"`
+	// Seniority is intern, junior, mid, senior, lead or director
+	Seniority string    `json:"seniority,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type JobFilter struct {
-	Keywords  []string  `json:"keywords"`
+	// Keywords keep jobs mentioning any of them
+	Keywords []string `json:"keywords"`
+	// KeywordQuery is a boolean query, as ParseKeywordExpr reads it, such
+	// as "golang AND (kubernetes OR terraform) NOT intern", that jobs must
+	// satisfy
+	KeywordQuery string `json:"keyword_query,omitempty"`
+	// Semantic is a natural-language query, as in "remote backend work at
+	// a climate startup". Storage ranks jobs by their meaning's similarity
+	// to it when semantic search is set up, and otherwise keeps and ranks
+	// the jobs mentioning any of its KeywordTerms. Given with Query, the
+	// two rankings are fused.
+	Semantic string `json:"semantic,omitempty"`
+	// Query is a full-text query, as ParseQuery reads it, that jobs must
+	// match. Storage ranks matches by it when sorting by relevance.
+	Query     string    `json:"query,omitempty"`
 	Location  string    `json:"location"`
 	Sources   []string  `json:"sources"`
 	MinSalary int       `json:"min_salary"`
 	MaxSalary int       `json:"max_salary"`
-	DateFrom  time.Time `json:"date_from"`
-	DateTo    time.Time `json:"date_to"`
-	IsActive  *bool     `json:"is_active"`
-	Limit     int       `json:"limit"`
-	Offset    int       `json:"offset"`
+	DateFrom  time.Time `json:"date_from"` // scraped at or after
+	DateTo    time.Time `json:"date_to"`   // scraped at or before
+	// SalaryCurrency is the currency of MinSalary and MaxSalary. When set,
+	// salaries in other currencies are converted to it before comparing;
+	// when empty, figures are compared as stated.
+	SalaryCurrency string `json:"salary_currency,omitempty"`
+	// PostedSince keeps jobs posted at or after it, taking jobs whose
+	// source gives no date as posted when first scraped
+	PostedSince time.Time `json:"posted_since,omitempty"`
+	IsActive    *bool     `json:"is_active"`
+	SortBy      string    `json:"sort_by"`    // relevance (default, by Query first when set), match, date (scraped), posted, title, company
+	SortOrder   string    `json:"sort_order"` // asc or desc; defaults to desc for relevance, date and posted
+	Limit       int       `json:"limit"`
+	Offset      int       `json:"offset"`
+
+	// Categories, Seniorities and Industries match the job's
+	// classification; unclassified jobs fail them
+	Categories  []string `json:"categories,omitempty"`
+	Seniorities []string `json:"seniorities,omitempty"`
+	Industries  []string `json:"industries,omitempty"`
+
+	// Statuses keeps jobs in any of these lifecycle states
+	Statuses []string `json:"statuses,omitempty"`
+
+	// EmploymentTypes and RemoteTypes keep jobs of any of these types; jobs
+	// whose type is unknown fail them
+	EmploymentTypes []string `json:"employment_types,omitempty"`
+	RemoteTypes     []string `json:"remote_types,omitempty"`
+
+	// Center and RadiusKm keep jobs located within RadiusKm of Center;
+	// Near names the place Center was geocoded from
+	Near     string    `json:"near,omitempty"`
+	RadiusKm float64   `json:"radius_km,omitempty"`
+	Center   *GeoPoint `json:"center,omitempty"`
+
+	// keywordExpr is KeywordQuery as Compile parsed it
+	keywordExpr *KeywordExpr
+}
+
+// Compile parses KeywordQuery, returning its error, so Matches need not
+// parse it again for every job. Callers matching many jobs compile the
+// filter first; without it Matches parses the query on each call.
+func (f *JobFilter) Compile() error {
+	f.keywordExpr = nil
+	if strings.TrimSpace(f.KeywordQuery) == "" {
+		return nil
+	}
+	expr, err := ParseKeywordExpr(f.KeywordQuery)
+	if err != nil {
+		return err
+	}
+	f.keywordExpr = expr
+	return nil
 }
 
 type JobSearchResult struct {
@@ -43,6 +266,9 @@ type JobSearchResult struct {
 	Page       int   `json:"page"`
 	PerPage    int   `json:"per_page"`
 	TotalPages int   `json:"total_pages"`
+	// Facets counts all matching jobs, not just this page, by category,
+	// seniority and industry
+	Facets map[string]map[string]int `json:"facets,omitempty"`
 }
 
 type JobStats struct {
@@ -52,10 +278,166 @@ type JobStats struct {
 	RecentJobs     int            `json:"recent_jobs"`
 	LastScraped    time.Time      `json:"last_scraped"`
 	Keywords       map[string]int `json:"keywords"`
+	// JobsByDay counts jobs by the date they were first scraped in the
+	// display timezone
+	JobsByDay map[string]int `json:"jobs_by_day"`
+	// The By-classification counts leave out unclassified jobs
+	JobsByCategory  map[string]int `json:"jobs_by_category"`
+	JobsBySeniority map[string]int `json:"jobs_by_seniority"`
+	JobsByIndustry  map[string]int `json:"jobs_by_industry"`
+	// JobsByCompany counts jobs under the names companies are shown by, so
+	// a company's aliases count together
+	JobsByCompany map[string]int `json:"jobs_by_company"`
+}
+
+// Matches reports whether job passes every criterion of the filter; paging
+// and sorting fields are ignored
+func (f JobFilter) Matches(job *Job) bool {
+	if len(f.Keywords) > 0 {
+		text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Keywords, " "))
+		found := false
+		for _, keyword := range f.Keywords {
+			if strings.Contains(text, strings.ToLower(keyword)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Query != "" && !ParseQuery(f.Query).Match(job) {
+		return false
+	}
+
+	if strings.TrimSpace(f.KeywordQuery) != "" {
+		expr := f.keywordExpr
+		if expr == nil {
+			// Filters are checked with Compile when they are made; an
+			// invalid query matches nothing
+			if f.Compile() != nil {
+				return false
+			}
+			expr = f.keywordExpr
+		}
+		if !expr.Match(job) {
+			return false
+		}
+	}
+
+	if f.Semantic != "" && !KeywordTerms(f.Semantic).MatchAny(job) {
+		return false
+	}
+
+	if f.Location != "" && !strings.Contains(strings.ToLower(job.Location), strings.ToLower(f.Location)) {
+		return false
+	}
+
+	if len(f.Sources) > 0 {
+		found := false
+		for _, source := range f.Sources {
+			if strings.EqualFold(job.Source, source) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.MinSalary > 0 || f.MaxSalary > 0 {
+		min, max := job.GetSalaryRange()
+		if f.SalaryCurrency != "" {
+			var ok bool
+			if min, max, ok = job.SalaryIn(f.SalaryCurrency); !ok {
+				return false
+			}
+		}
+		if min == 0 && max == 0 {
+			return false
+		}
+		if f.MinSalary > 0 && max < f.MinSalary {
+			return false
+		}
+		if f.MaxSalary > 0 && min > f.MaxSalary {
+			return false
+		}
+	}
+
+	if !f.DateFrom.IsZero() && job.ScrapedAt.Before(f.DateFrom) {
+		return false
+	}
+	if !f.DateTo.IsZero() && job.ScrapedAt.After(f.DateTo) {
+		return false
+	}
+	if !f.PostedSince.IsZero() && job.PostedTime().Before(f.PostedSince) {
+		return false
+	}
+
+	if f.IsActive != nil && job.IsActive != *f.IsActive {
+		return false
+	}
+
+	if !f.Within(job) {
+		return false
+	}
+
+	if !matchesAny(job.CurrentStatus(), f.Statuses) {
+		return false
+	}
+
+	if !matchesWorkType(job.EmploymentType, f.EmploymentTypes, NormalizeEmploymentType) ||
+		!matchesWorkType(job.RemoteType, f.RemoteTypes, NormalizeRemoteType) {
+		return false
+	}
+
+	if len(f.Categories) > 0 || len(f.Seniorities) > 0 || len(f.Industries) > 0 {
+		c := job.Classification
+		if c == nil {
+			return false
+		}
+		if !matchesAny(c.Category, f.Categories) || !matchesAny(c.Seniority, f.Seniorities) || !matchesAny(c.Industry, f.Industries) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAny reports whether value is one of values, ignoring case; an
+// empty list matches everything
+func matchesAny(value string, values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWorkType reports whether value is one of values, which may be
+// written any way normalize reads; an empty list matches everything and an
+// unknown value nothing else
+func matchesWorkType(value string, values []string, normalize func(string) string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if value != "" && normalize(v) == value {
+			return true
+		}
+	}
+	return false
 }
 
 // NewJob creates a new job instance with the provided details
 func NewJob(title, company, location, salary, description, link, source string) *Job {
+	now := time.Now().UTC()
 	job := &Job{
 		Title:       strings.TrimSpace(title),
 		Company:     strings.TrimSpace(company),
@@ -64,8 +446,8 @@ func NewJob(title, company, location, salary, description, link, source string)
 		Description: strings.TrimSpace(description),
 		Link:        strings.TrimSpace(link),
 		Source:      strings.TrimSpace(source),
-		ScrapedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ScrapedAt:   now,
+		UpdatedAt:   now,
 		IsActive:    true,
 		Relevance:   0.0,
 	}
@@ -85,14 +467,28 @@ func (j *Job) GenerateID() string {
 	return fmt.Sprintf("%x", hash)
 }
 
+// Notified reports whether the job was already sent to channel
+func (j *Job) Notified(channel string) bool {
+	_, ok := j.NotifiedAt[channel]
+	return ok
+}
+
 func (j *Job) IsValid() bool {
 	return j.Title != "" && j.Company != "" && j.Link != ""
 }
 
+// IsDuplicate reports whether other is the same posting: the same ID, the
+// same title and company in a matching location, or the same link with a
+// similar title
 func (j *Job) IsDuplicate(other *Job) bool {
-	return j.ID == other.ID ||
-		(strings.EqualFold(j.Title, other.Title) &&
-			strings.EqualFold(j.Company, other.Company))
+	if j.ID == other.ID {
+		return true
+	}
+	if PostingKey(j) == PostingKey(other) && LocationsMatch(j.Location, other.Location) {
+		return true
+	}
+	link := LinkKey(j.Link)
+	return link != "" && link == LinkKey(other.Link) && TitlesSimilar(j.Title, other.Title)
 }
 
 func (j *Job) ExtractKeywords() []string {
@@ -152,6 +548,11 @@ func (j *Job) FromJSON(data []byte) error {
 }
 
 func (j *Job) GetSalaryRange() (min, max int) {
+	if j.SalaryRange != nil {
+		yearlyMin, yearlyMax := j.SalaryRange.Yearly()
+		return int(yearlyMin), int(yearlyMax)
+	}
+
 	// Simple salary parsing - can be enhanced
 	if j.Salary == "" {
 		return 0, 0
@@ -174,7 +575,34 @@ func (j *Job) GetSalaryRange() (min, max int) {
 	return 0, 0
 }
 
+// SalaryCurrency returns the currency the job pays in, taking salaries that
+// state none to be in DefaultSalaryCurrency
+func (j *Job) SalaryCurrency() string {
+	if j.SalaryRange != nil && j.SalaryRange.Currency != "" {
+		return strings.ToUpper(j.SalaryRange.Currency)
+	}
+	return DefaultSalaryCurrency
+}
+
+// SalaryIn returns GetSalaryRange converted to currency. It reports false
+// when the job's currency has no exchange rate.
+func (j *Job) SalaryIn(currency string) (min, max int, ok bool) {
+	min, max = j.GetSalaryRange()
+	from := j.SalaryCurrency()
+	convertedMin, ok := ConvertCurrency(float64(min), from, currency)
+	if !ok {
+		return 0, 0, false
+	}
+	convertedMax, _ := ConvertCurrency(float64(max), from, currency)
+	return int(convertedMin), int(convertedMax), true
+}
+
+// IsRemote reports whether the job is fully remote, by its RemoteType when
+// known and its location otherwise
 func (j *Job) IsRemote() bool {
+	if j.RemoteType != "" {
+		return j.RemoteType == RemoteFull
+	}
 	location := strings.ToLower(j.Location)
 	return strings.Contains(location, "remote") ||
 		strings.Contains(location, "anywhere") ||
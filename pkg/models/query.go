@@ -0,0 +1,190 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The text fields of a job that full-text queries search, in the order
+// TextFields returns them
+const (
+	FieldTitle = iota
+	FieldCompany
+	FieldDescription
+	FieldKeywords
+	TextFieldCount
+)
+
+// QueryTerm is one word, phrase or prefix of a full-text query
+type QueryTerm struct {
+	// Words are matched in order and next to each other in one field
+	Words []string
+	// Prefix lets the last word match any word it begins, as in "kube*"
+	Prefix bool
+	// Exclude keeps only jobs the term does not match
+	Exclude bool
+}
+
+// TextQuery is a parsed full-text query. A job matches when every term
+// matches it, and no excluded one does.
+type TextQuery []QueryTerm
+
+// ParseQuery parses a full-text query: words separated by spaces, all of
+// which must match, "quoted phrases" matched word for word, a trailing *
+// to match words by prefix and a leading - to exclude a word or phrase,
+// e.g. `"site reliability" kube* -manager`. Words are split as Tokenize
+// splits them, so node.js is the phrase "node js".
+func ParseQuery(query string) TextQuery {
+	var terms TextQuery
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		var term QueryTerm
+		if rest[0] == '-' {
+			term.Exclude = true
+			rest = rest[1:]
+		}
+
+		var text string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				// An unclosed quote runs to the end of the query
+				text, rest = rest[1:], ""
+			} else {
+				text, rest = rest[1:end+1], rest[end+2:]
+			}
+			if strings.HasPrefix(rest, "*") {
+				text += "*"
+				rest = rest[1:]
+			}
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			text, rest = rest[:end], rest[end:]
+		}
+
+		term.Prefix = strings.HasSuffix(text, "*")
+		term.Words = Tokenize(text)
+		if len(term.Words) > 0 {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// keywordStopWords are the words of natural-language queries, as in
+// "remote jobs at a fintech startup", that say nothing about the jobs
+var keywordStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "any": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "i": true, "in": true, "is": true,
+	"it": true, "job": true, "jobs": true, "like": true, "looking": true, "me": true,
+	"my": true, "of": true, "on": true, "or": true, "position": true, "positions": true,
+	"role": true, "roles": true, "something": true, "that": true, "the": true, "to": true,
+	"want": true, "where": true, "which": true, "with": true, "work": true,
+}
+
+// KeywordTerms reads a natural-language query as the words a keyword search
+// for it matches any of, leaving out words such as "jobs" and "the"
+// unless the query has no others
+func KeywordTerms(query string) TextQuery {
+	words := Tokenize(query)
+	var terms TextQuery
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if !keywordStopWords[word] && !seen[word] {
+			seen[word] = true
+			terms = append(terms, QueryTerm{Words: []string{word}})
+		}
+	}
+	if len(terms) == 0 {
+		for _, word := range words {
+			if !seen[word] {
+				seen[word] = true
+				terms = append(terms, QueryTerm{Words: []string{word}})
+			}
+		}
+	}
+	return terms
+}
+
+// Tokenize splits text into the lowercased words full-text search indexes:
+// runs of letters and digits, keeping + and # so C++ and C# stay words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#')
+	})
+}
+
+// TextFields returns the text of job that full-text queries search, by
+// field
+func TextFields(job *Job) [TextFieldCount]string {
+	var fields [TextFieldCount]string
+	fields[FieldTitle] = job.Title
+	fields[FieldCompany] = job.Company
+	fields[FieldDescription] = job.Description
+	fields[FieldKeywords] = strings.Join(job.Keywords, " ")
+	return fields
+}
+
+// Match reports whether job matches the query, by scanning its text.
+// Storage answers queries from an index instead.
+func (q TextQuery) Match(job *Job) bool {
+	if len(q) == 0 {
+		return true
+	}
+	var tokens [TextFieldCount][]string
+	for field, text := range TextFields(job) {
+		tokens[field] = Tokenize(text)
+	}
+	for _, term := range q {
+		found := false
+		for _, words := range tokens {
+			for i := range words {
+				if term.MatchesAt(words, i) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if found == term.Exclude {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchAny reports whether any of the query's terms matches job, or the
+// query is empty
+func (q TextQuery) MatchAny(job *Job) bool {
+	if len(q) == 0 {
+		return true
+	}
+	for _, term := range q {
+		if (TextQuery{term}).Match(job) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAt reports whether the term's words start at tokens[i]
+func (t QueryTerm) MatchesAt(tokens []string, i int) bool {
+	if i+len(t.Words) > len(tokens) {
+		return false
+	}
+	last := len(t.Words) - 1
+	for j, word := range t.Words {
+		if t.Prefix && j == last {
+			if !strings.HasPrefix(tokens[i+j], word) {
+				return false
+			}
+		} else if tokens[i+j] != word {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// SavedSearch is a named job filter kept in the data directory. UserID is
+// empty for searches belonging to the shared default profile.
+type SavedSearch struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id,omitempty"`
+	Name      string    `json:"name"`
+	Filter    JobFilter `json:"filter"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewSavedSearch creates a saved search whose ID is derived from its name
+func NewSavedSearch(name string, filter JobFilter) *SavedSearch {
+	now := time.Now()
+	return &SavedSearch{
+		ID:        SavedSearchID(name),
+		Name:      strings.TrimSpace(name),
+		Filter:    filter,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SavedSearchID turns a search name into a URL- and CLI-friendly ID such as
+// "remote-go-jobs"
+func SavedSearchID(name string) string {
+	return slug(name)
+}
+
+// slug lowercases name and joins its runs of letters and digits with dashes
+func slug(name string) string {
+	var id strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			id.WriteRune(r)
+			dash = false
+		} else if !dash && id.Len() > 0 {
+			id.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(id.String(), "-")
+}
+
+// Alert records a job a saved search matched when the job was first
+// stored. Alerts stay unread until the alert digest that lists them is read.
+type Alert struct {
+	SearchID  string    `json:"search_id"`
+	UserID    string    `json:"user_id,omitempty"`
+	JobID     string    `json:"job_id"`
+	RunID     string    `json:"run_id,omitempty"`
+	MatchedAt time.Time `json:"matched_at"`
+	Read      bool      `json:"read,omitempty"`
+}
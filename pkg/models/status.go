@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Job lifecycle states, tracking where a job stands in your own search.
+// Stored jobs start out new.
+const (
+	JobNew      = "new"
+	JobSeen     = "seen"
+	JobApplied  = "applied"
+	JobRejected = "rejected"
+	// JobArchived dismisses a job without applying
+	JobArchived = "archived"
+)
+
+// JobStatuses lists the valid job states
+var JobStatuses = []string{JobNew, JobSeen, JobApplied, JobRejected, JobArchived}
+
+// ValidateJobStatus checks that status is one of JobStatuses
+func ValidateJobStatus(status string) error {
+	for _, valid := range JobStatuses {
+		if status == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid job status %q (use %s)", status, strings.Join(JobStatuses, ", "))
+}
+
+// CurrentStatus returns the job's lifecycle state; jobs stored before
+// states were tracked are new
+func (j *Job) CurrentStatus() string {
+	if j.Status == "" {
+		return JobNew
+	}
+	return j.Status
+}
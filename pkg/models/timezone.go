@@ -0,0 +1,166 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timestamps are kept in UTC. displayLocation is the zone they are shown
+// in, stats group them by day in and dates given without a zone are read
+// in; it defaults to the machine's local zone.
+var (
+	displayMutex    sync.RWMutex
+	displayLocation = time.Local
+)
+
+// SetDisplayTimezone sets the display zone from an IANA name such as
+// Europe/Berlin, UTC or Local; an empty name keeps the current zone
+func SetDisplayTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	location, err := LoadTimezone(name)
+	if err != nil {
+		return err
+	}
+	displayMutex.Lock()
+	displayLocation = location
+	displayMutex.Unlock()
+	return nil
+}
+
+// LoadTimezone looks up an IANA zone name
+func LoadTimezone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name such as Europe/Berlin)", name)
+	}
+	return location, nil
+}
+
+// DisplayLocation returns the zone dates are shown in
+func DisplayLocation() *time.Location {
+	displayMutex.RLock()
+	defer displayMutex.RUnlock()
+	return displayLocation
+}
+
+// InDisplayZone returns t in the display zone
+func InDisplayZone(t time.Time) time.Time {
+	return t.In(DisplayLocation())
+}
+
+// SetPosted records when the job was posted, in UTC, keeping the zone the
+// source gave it in
+func (j *Job) SetPosted(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	posted := t.UTC()
+	j.PostedAt = &posted
+	j.PostedZone = zoneName(t)
+}
+
+// PostedTime returns when the job was posted, or when it was first scraped
+// if its source gave no date
+func (j *Job) PostedTime() time.Time {
+	if j.PostedAt != nil {
+		return *j.PostedAt
+	}
+	return j.ScrapedAt
+}
+
+// PostedDate returns the day the job was posted in the display zone, such
+// as "02 Jan 2006", or "" when its source gave no date
+func (j *Job) PostedDate() string {
+	if j.PostedAt == nil {
+		return ""
+	}
+	return InDisplayZone(*j.PostedAt).Format("02 Jan 2006")
+}
+
+// NormalizeTimes converts the job's timestamps to UTC, recording the zone
+// the posted date came in if it was not already
+func (j *Job) NormalizeTimes() {
+	if j.PostedAt != nil {
+		if j.PostedZone == "" {
+			j.PostedZone = zoneName(*j.PostedAt)
+		}
+		posted := j.PostedAt.UTC()
+		j.PostedAt = &posted
+	}
+	j.ScrapedAt = j.ScrapedAt.UTC()
+	j.UpdatedAt = j.UpdatedAt.UTC()
+	// NotifiedAt is replaced rather than modified as copies of the job may
+	// share it
+	if len(j.NotifiedAt) > 0 {
+		notified := make(map[string]time.Time, len(j.NotifiedAt))
+		for channel, at := range j.NotifiedAt {
+			notified[channel] = at.UTC()
+		}
+		j.NotifiedAt = notified
+	}
+}
+
+// zoneName names t's zone: the IANA name when it has one, an abbreviation
+// such as EST when it was parsed from one, or else the offset
+func zoneName(t time.Time) string {
+	if name := t.Location().String(); name == "UTC" || strings.Contains(name, "/") {
+		return name
+	}
+	if abbreviation, _ := t.Zone(); abbreviation != "" && !strings.ContainsAny(abbreviation, "+-") {
+		return abbreviation
+	}
+	return t.Format("-07:00")
+}
+
+// rfc822Zones gives the offsets of the zone abbreviations RFC 822 allows,
+// which time.Parse only knows when they are the local zone's
+var rfc822Zones = map[string]int{
+	"UT": 0, "GMT": 0, "Z": 0,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5,
+	"MST": -7, "MDT": -6, "PST": -8, "PDT": -7,
+}
+
+// timestampLayouts are the forms feeds and APIs write dates in
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a date as feeds and APIs write it. Dates without a
+// zone are read in location. ok is false when value matches no known form.
+func ParseTimestamp(value string, location *time.Location) (t time.Time, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range timestampLayouts {
+		parsed, err := time.ParseInLocation(layout, value, location)
+		if err != nil {
+			continue
+		}
+		// An abbreviation the time package does not know parses with a
+		// zero offset
+		if abbreviation, offset := parsed.Zone(); offset == 0 && strings.Contains(layout, "MST") {
+			if hours, known := rfc822Zones[abbreviation]; known {
+				parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), parsed.Hour(), parsed.Minute(),
+					parsed.Second(), parsed.Nanosecond(), time.FixedZone(abbreviation, hours*3600))
+			}
+		}
+		return parsed, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// User is an account on a shared deployment. Each user keeps their own saved
+// searches, application statuses and notification settings.
+type User struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Email         string               `json:"email,omitempty"`
+	Notifications NotificationSettings `json:"notifications"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// NotificationSettings controls which new jobs a user wants to hear about
+type NotificationSettings struct {
+	Enabled bool `json:"enabled"`
+	// Email overrides the user's email as the delivery address
+	Email string `json:"email,omitempty"`
+	// MinRelevance skips jobs scored below this value
+	MinRelevance float64 `json:"min_relevance,omitempty"`
+	// SavedSearches limits notifications to these saved search IDs; empty
+	// means all of the user's saved searches
+	SavedSearches []string `json:"saved_searches,omitempty"`
+}
+
+// NewUser creates a user whose ID is derived from their name
+func NewUser(name, email string) *User {
+	now := time.Now()
+	return &User{
+		ID:        UserID(name),
+		Name:      strings.TrimSpace(name),
+		Email:     strings.TrimSpace(email),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// UserID turns a user name into an ID such as "priya-k"
+func UserID(name string) string {
+	return slug(name)
+}
+
+// Application tracking states, in the order a candidate usually moves
+// through them
+const (
+	ApplicationSaved        = "saved"
+	ApplicationApplied      = "applied"
+	ApplicationInterviewing = "interviewing"
+	ApplicationOffer        = "offer"
+	ApplicationRejected     = "rejected"
+	ApplicationWithdrawn    = "withdrawn"
+	// ApplicationArchived dismisses a job as not a fit without applying
+	ApplicationArchived = "archived"
+)
+
+// ApplicationStatuses lists the valid application states
+var ApplicationStatuses = []string{
+	ApplicationSaved, ApplicationApplied, ApplicationInterviewing,
+	ApplicationOffer, ApplicationRejected, ApplicationWithdrawn,
+	ApplicationArchived,
+}
+
+// ValidateApplicationStatus checks that status is one of ApplicationStatuses
+func ValidateApplicationStatus(status string) error {
+	for _, valid := range ApplicationStatuses {
+		if status == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid application status %q (use %s)", status, strings.Join(ApplicationStatuses, ", "))
+}
+
+// Application records where one user stands with one job. UserID is empty
+// for the shared default profile.
+type Application struct {
+	JobID     string    `json:"job_id"`
+	UserID    string    `json:"user_id,omitempty"`
+	Status    string    `json:"status"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook is an outbound subscription: newly stored jobs that match Filter
+// are POSTed to URL, signed with Secret. UserID is empty for the shared
+// default profile.
+type Webhook struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id,omitempty"`
+	URL       string        `json:"url"`
+	Secret    string        `json:"secret,omitempty"`
+	Filter    WebhookFilter `json:"filter"`
+	Enabled   bool          `json:"enabled"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// WebhookFilter selects the jobs a webhook receives. Empty fields match
+// every job.
+type WebhookFilter struct {
+	// Keywords match when any appears in the job's title or description
+	Keywords []string `json:"keywords,omitempty"`
+	// KeywordQuery is a boolean query, as ParseKeywordExpr reads it, that
+	// jobs must satisfy
+	KeywordQuery string   `json:"keyword_query,omitempty"`
+	Sources      []string `json:"sources,omitempty"`
+	MinRelevance float64  `json:"min_relevance,omitempty"`
+
+	// keywordExpr is KeywordQuery parsed by Compile, and compileErr why it
+	// could not be
+	keywordExpr *KeywordExpr
+	compileErr  error
+}
+
+// Compile parses the filter's KeywordQuery once, so Matches need not parse
+// it for every job. Webhooks are compiled as they are created and loaded.
+func (f *WebhookFilter) Compile() error {
+	f.keywordExpr, f.compileErr = nil, nil
+	if strings.TrimSpace(f.KeywordQuery) == "" {
+		return nil
+	}
+	f.keywordExpr, f.compileErr = ParseKeywordExpr(f.KeywordQuery)
+	return f.compileErr
+}
+
+// CompileError returns the error the last Compile met, if any
+func (f WebhookFilter) CompileError() error {
+	return f.compileErr
+}
+
+// Matches reports whether job passes the filter. A KeywordQuery that was
+// not compiled, because it is invalid, matches nothing.
+func (f WebhookFilter) Matches(job *Job) bool {
+	if job.Relevance < f.MinRelevance {
+		return false
+	}
+
+	if len(f.Sources) > 0 {
+		found := false
+		for _, source := range f.Sources {
+			if strings.EqualFold(source, job.Source) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if strings.TrimSpace(f.KeywordQuery) != "" && (f.keywordExpr == nil || !f.keywordExpr.Match(job)) {
+		return false
+	}
+
+	if len(f.Keywords) > 0 {
+		text := strings.ToLower(job.Title + " " + job.Description)
+		for _, keyword := range f.Keywords {
+			if strings.Contains(text, strings.ToLower(keyword)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// Webhook events
+const (
+	WebhookEventJobsMatched = "jobs.matched"
+	WebhookEventPing        = "ping"
+)
+
+// WebhookPayload is the JSON body POSTed to webhook URLs. DeliveryID is the
+// same across the retries of one delivery, so receivers can drop repeats.
+type WebhookPayload struct {
+	Event      string    `json:"event"`
+	WebhookID  string    `json:"webhook_id"`
+	DeliveryID string    `json:"delivery_id"`
+	SentAt     time.Time `json:"sent_at"`
+	Jobs       []Job     `json:"jobs"`
+}
+
+// WebhookRequest is the body accepted by POST /webhooks
+type WebhookRequest struct {
+	URL          string   `json:"url"`
+	Keywords     []string `json:"keywords"`
+	KeywordQuery string   `json:"keyword_query"`
+	Sources      []string `json:"sources"`
+	MinRelevance float64  `json:"min_relevance"`
+}
+
+// WebhookTestResult is returned by POST /webhooks/{id}/test
+type WebhookTestResult struct {
+	Delivered bool   `json:"delivered"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Employment types
+const (
+	EmploymentFullTime = "full-time"
+	EmploymentPartTime = "part-time"
+	EmploymentContract = "contract"
+	EmploymentIntern   = "intern"
+)
+
+// EmploymentTypes lists the values Job.EmploymentType takes
+var EmploymentTypes = []string{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentIntern}
+
+// Remote types
+const (
+	RemoteFull   = "remote"
+	RemoteHybrid = "hybrid"
+	RemoteOnsite = "onsite"
+)
+
+// RemoteTypes lists the values Job.RemoteType takes
+var RemoteTypes = []string{RemoteFull, RemoteHybrid, RemoteOnsite}
+
+// employmentAliases maps the ways sources name employment types, with
+// spaces, hyphens and underscores dropped, to EmploymentTypes
+var employmentAliases = map[string]string{
+	"fulltime":    EmploymentFullTime,
+	"permanent":   EmploymentFullTime,
+	"regular":     EmploymentFullTime,
+	"parttime":    EmploymentPartTime,
+	"contract":    EmploymentContract,
+	"contractor":  EmploymentContract,
+	"temporary":   EmploymentContract,
+	"temp":        EmploymentContract,
+	"freelance":   EmploymentContract,
+	"fixedterm":   EmploymentContract,
+	"intern":      EmploymentIntern,
+	"internship":  EmploymentIntern,
+	"workstudent": EmploymentIntern,
+	"werkstudent": EmploymentIntern,
+	"trainee":     EmploymentIntern,
+	"apprentice":  EmploymentIntern,
+}
+
+// NormalizeEmploymentType maps a source's employment type, such as
+// FULLTIME, "Full Time" or Contractor, to one of EmploymentTypes, or ""
+// when it is not one
+func NormalizeEmploymentType(value string) string {
+	key := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(value)))
+	return employmentAliases[key]
+}
+
+// NormalizeRemoteType maps a remote type such as "Remote", "on-site" or
+// "Hybrid" to one of RemoteTypes, or "" when it is not one
+func NormalizeRemoteType(value string) string {
+	switch strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(value))) {
+	case "remote", "fullyremote", "wfh", "workfromhome":
+		return RemoteFull
+	case "hybrid":
+		return RemoteHybrid
+	case "onsite", "inoffice", "office", "onpremises":
+		return RemoteOnsite
+	}
+	return ""
+}
+
+var (
+	// legacyEmploymentPrefix is the "[FULLTIME] " JSearch descriptions were
+	// once stored with
+	legacyEmploymentPrefix = regexp.MustCompile(`^\[([A-Z_]+)\]\s*`)
+
+	// titlePatterns read titles, which name the type in a word or two
+	titlePatterns = []workTypePattern{
+		{regexp.MustCompile(`(?i)\b(intern|internship|werkstudent|working student|trainee|apprentice(ship)?)\b`), EmploymentIntern},
+		{regexp.MustCompile(`(?i)\b(contract(or)?|freelance|temporary|fixed[- ]term|b2b)\b`), EmploymentContract},
+		{regexp.MustCompile(`(?i)\b(part[- ]time|teilzeit)\b`), EmploymentPartTime},
+		{regexp.MustCompile(`(?i)\b(full[- ]time|permanent|vollzeit)\b`), EmploymentFullTime},
+	}
+	// descriptionPatterns only take phrases that state the type, as
+	// descriptions mention interns and contracts in passing
+	descriptionPatterns = []workTypePattern{
+		{regexp.MustCompile(`(?i)\b(this|paid|summer) internship\b`), EmploymentIntern},
+		{regexp.MustCompile(`(?i)\b(contract|freelance|temporary|fixed[- ]term) (role|position|basis|assignment)\b`), EmploymentContract},
+		{regexp.MustCompile(`(?i)\bpart[- ]time (role|position|job)\b`), EmploymentPartTime},
+		{regexp.MustCompile(`(?i)\b(full[- ]time|permanent) (role|position|job|employment)\b`), EmploymentFullTime},
+	}
+
+	// Remote work is read from the title and location, where a word says
+	// it; descriptions need a stronger phrase
+	hybridPattern            = regexp.MustCompile(`(?i)\bhybrid\b`)
+	remotePattern            = regexp.MustCompile(`(?i)\b(remote|anywhere|work from home|wfh|telecommute|home[- ]based)\b`)
+	onsitePattern            = regexp.MustCompile(`(?i)\b(on[- ]?site|in[- ]office)\b`)
+	descriptionRemotePattern = regexp.MustCompile(`(?i)\b(fully remote|100% remote|remote[- ]first|remote position|remote role)\b`)
+)
+
+type workTypePattern struct {
+	pattern *regexp.Regexp
+	value   string
+}
+
+// DetectWorkTypes fills in EmploymentType and RemoteType when the source
+// gave neither, from the title, then the location and description. A
+// description prefix such as "[FULLTIME] " left by older versions is moved
+// into EmploymentType.
+func (j *Job) DetectWorkTypes() {
+	if match := legacyEmploymentPrefix.FindStringSubmatch(j.Description); match != nil {
+		if employmentType := NormalizeEmploymentType(match[1]); employmentType != "" {
+			j.Description = j.Description[len(match[0]):]
+			if j.EmploymentType == "" {
+				j.EmploymentType = employmentType
+			}
+		}
+	}
+
+	if j.EmploymentType == "" {
+		j.EmploymentType = matchWorkType(titlePatterns, j.Title)
+	}
+	if j.EmploymentType == "" {
+		j.EmploymentType = matchWorkType(descriptionPatterns, j.Description)
+	}
+
+	if j.RemoteType == "" {
+		j.RemoteType = detectRemoteType(j.Title + " " + j.Location)
+	}
+	if j.RemoteType == "" && descriptionRemotePattern.MatchString(j.Description) {
+		j.RemoteType = RemoteFull
+	}
+}
+
+func matchWorkType(patterns []workTypePattern, text string) string {
+	for _, p := range patterns {
+		if p.pattern.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+// detectRemoteType reads hybrid before remote, as hybrid postings mention
+// both
+func detectRemoteType(text string) string {
+	switch {
+	case hybridPattern.MatchString(text):
+		return RemoteHybrid
+	case remotePattern.MatchString(text):
+		return RemoteFull
+	case onsitePattern.MatchString(text):
+		return RemoteOnsite
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	texttemplate "text/template"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+//go:embed alerts.md
+var alertsMarkdownTemplate string
+
+// AlertNotifier is implemented by notifiers that also deliver the jobs
+// saved searches newly matched
+type AlertNotifier interface {
+	NotifyAlerts(ctx context.Context, digest *AlertDigest) error
+}
+
+// AlertDigest lists the jobs saved searches matched, grouped by search
+type AlertDigest struct {
+	Time   time.Time    `json:"time"`
+	Groups []AlertGroup `json:"searches"`
+}
+
+// AlertGroup is one saved search and the jobs it matched, best match first
+type AlertGroup struct {
+	Search models.SavedSearch `json:"search"`
+	Jobs   []models.Job       `json:"jobs"`
+}
+
+// BuildAlertDigest groups alerts under their saved searches. jobs holds the
+// alerted jobs by ID; alerts whose search or job is gone are left out.
+func BuildAlertDigest(searches []models.SavedSearch, alerts []models.Alert, jobs map[string]models.Job) *AlertDigest {
+	groups := make(map[string]*AlertGroup, len(searches))
+	for _, search := range searches {
+		groups[search.UserID+"\x00"+search.ID] = &AlertGroup{Search: search}
+	}
+	for _, alert := range alerts {
+		group, ok := groups[alert.UserID+"\x00"+alert.SearchID]
+		job, found := jobs[alert.JobID]
+		if ok && found {
+			group.Jobs = append(group.Jobs, job)
+		}
+	}
+
+	d := &AlertDigest{Time: time.Now()}
+	for _, search := range searches {
+		group := groups[search.UserID+"\x00"+search.ID]
+		if len(group.Jobs) == 0 {
+			continue
+		}
+		byRelevance(group.Jobs)
+		d.Groups = append(d.Groups, *group)
+	}
+	return d
+}
+
+// Count returns the number of alerted jobs, counting a job once per search
+// that matched it
+func (d *AlertDigest) Count() int {
+	count := 0
+	for _, group := range d.Groups {
+		count += len(group.Jobs)
+	}
+	return count
+}
+
+// Title names the digest, e.g. "Saved search alerts: 3 new matches"
+func (d *AlertDigest) Title() string {
+	if count := d.Count(); count != 1 {
+		return fmt.Sprintf("Saved search alerts: %d new matches", count)
+	}
+	return "Saved search alerts: 1 new match"
+}
+
+// Markdown renders the digest as a Markdown document
+func (d *AlertDigest) Markdown() (string, error) {
+	tmpl, err := texttemplate.New("alerts").Funcs(texttemplate.FuncMap(digestFuncs)).Parse(alertsMarkdownTemplate)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+)
+
+// DesktopConfig configures native desktop notifications, meant for
+// scrape -watch running on the user's own machine
+type DesktopConfig struct {
+	Enabled      bool    `json:"enabled"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+	// MaxPerRun caps the notifications shown after one scrape; the rest are
+	// summed up in a final one. Defaults to 3.
+	MaxPerRun int `json:"maxPerRun,omitempty"`
+}
+
+// DesktopNotifier shows one notification per new job; clicking it opens the
+// job's link. It uses terminal-notifier (falling back to osascript, which
+// cannot open links) on macOS, notify-send on Linux and a PowerShell toast on
+// Windows.
+type DesktopNotifier struct {
+	config DesktopConfig
+	show   func(title, body, link string) error
+	logger *logrus.Logger
+}
+
+// NewDesktopNotifier picks the notification tool for this platform and fails
+// if none is installed
+func NewDesktopNotifier(config DesktopConfig, logger *logrus.Logger) (*DesktopNotifier, error) {
+	if config.MaxPerRun <= 0 {
+		config.MaxPerRun = defaultMaxPushes
+	}
+
+	n := &DesktopNotifier{config: config, logger: logger}
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("terminal-notifier"); err == nil {
+			n.show = showTerminalNotifier
+		} else if _, err := exec.LookPath("osascript"); err == nil {
+			logger.Warn("terminal-notifier not found; desktop notifications will not open links when clicked")
+			n.show = showOSAScript
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			n.show = n.notifySend(supportsActions())
+		}
+	case "windows":
+		if _, err := exec.LookPath("powershell"); err == nil {
+			n.show = showToast
+		}
+	}
+	if n.show == nil {
+		return nil, fmt.Errorf("desktop notifications are not supported on %s without terminal-notifier, notify-send or PowerShell", runtime.GOOS)
+	}
+	return n, nil
+}
+
+func (n *DesktopNotifier) Name() string {
+	return "desktop"
+}
+
+// Notify shows the best jobs first, up to MaxPerRun, and a summary of the
+// rest
+func (n *DesktopNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	return pushJobs(ctx, jobs, n.config.MinRelevance, n.config.MaxPerRun, func(_ context.Context, title, body, link string) error {
+		return n.show(title, body, webLink(link))
+	})
+}
+
+// webLink returns link if it is an http or https URL, and "" otherwise.
+// Links come from scraped and ingested jobs and are opened by xdg-open,
+// terminal-notifier and Windows, which would launch any other scheme's
+// handler, such as file: or a custom protocol.
+func webLink(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func showTerminalNotifier(title, body, link string) error {
+	args := []string{"-title", "hire.ai", "-subtitle", title, "-message", body}
+	if link != "" {
+		args = append(args, "-open", link)
+	}
+	return runNotifier(exec.Command("terminal-notifier", args...))
+}
+
+func showOSAScript(title, body, _ string) error {
+	// Passing the text as arguments avoids quoting it inside the script
+	return runNotifier(exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title \"hire.ai\" subtitle (item 1 of argv)",
+		"-e", "end run",
+		title, body))
+}
+
+// supportsActions reports whether notify-send can report clicks, which
+// libnotify added in 0.7.9
+func supportsActions() bool {
+	help, err := exec.Command("notify-send", "--help").CombinedOutput()
+	return err == nil && bytes.Contains(help, []byte("--action"))
+}
+
+// notifySend shows a notification with notify-send. With action support it
+// waits in the background for a click and opens the link with xdg-open;
+// otherwise the link is appended to the body.
+func (n *DesktopNotifier) notifySend(actions bool) func(title, body, link string) error {
+	return func(title, body, link string) error {
+		args := []string{"--app-name=hire.ai", title}
+		if link == "" || !actions {
+			if link != "" {
+				body += "\n" + link
+			}
+			return runNotifier(exec.Command("notify-send", append(args, body)...))
+		}
+
+		cmd := exec.Command("notify-send", append([]string{"--action=default=Open", "--wait"}, append(args, body)...)...)
+		var clicked bytes.Buffer
+		cmd.Stdout = &clicked
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go func() {
+			if cmd.Wait() == nil && strings.TrimSpace(clicked.String()) == "default" {
+				if err := exec.Command("xdg-open", link).Run(); err != nil {
+					n.logger.Warnf("Failed to open %s: %v", link, err)
+				}
+			}
+		}()
+		return nil
+	}
+}
+
+// toastScript shows a Windows toast that opens HIRE_LINK, if set, when
+// clicked. The text is read from the environment so it needs no PowerShell
+// quoting.
+const toastScript = `
+$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$e = [Security.SecurityElement]
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$launch = ''
+if ($env:HIRE_LINK) { $launch = ' activationType="protocol" launch="' + $e::Escape($env:HIRE_LINK) + '"' }
+$xml.LoadXml(('<toast{0}><visual><binding template="ToastGeneric"><text>{1}</text><text>{2}</text></binding></visual></toast>' -f $launch, $e::Escape($env:HIRE_TITLE), $e::Escape($env:HIRE_BODY)))
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)
+`
+
+func showToast(title, body, link string) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(os.Environ(), "HIRE_TITLE="+title, "HIRE_BODY="+body, "HIRE_LINK="+link)
+	return runNotifier(cmd)
+}
+
+// runNotifier runs a notification command, including its output in the
+// error since these tools explain failures there
+func runNotifier(cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%s failed: %w: %s", cmd.Path, err, message)
+		}
+		return fmt.Errorf("%s failed: %w", cmd.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+//go:embed digest.md
+var digestMarkdownTemplate string
+
+//go:embed digest.html
+var digestHTMLTemplate string
+
+// Digest periods accepted by ParsePeriod besides plain durations
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+const (
+	digestTopMatches = 10
+	digestHighlights = 5
+	// digestSourceJobs is how many of each source's best jobs are listed
+	digestSourceJobs = 5
+)
+
+// Digest summarises the jobs first stored during a period
+type Digest struct {
+	Period string
+	Since  time.Time
+	Until  time.Time
+	// Jobs are all new jobs, best match first
+	Jobs             []models.Job
+	TopMatches       []models.Job
+	NewCompanies     []CompanySummary
+	SalaryHighlights []models.Job
+	// Sources groups the new jobs by where they were found, busiest first
+	Sources []SourceSummary
+}
+
+// CompanySummary is a company seen for the first time during the period
+type CompanySummary struct {
+	Name string
+	Jobs int
+}
+
+// SourceSummary is one source's best new jobs and how many it found
+type SourceSummary struct {
+	Source string
+	Total  int
+	Jobs   []models.Job
+}
+
+// ParsePeriod turns "daily", "weekly" or a duration such as "12h" into a
+// duration
+func ParsePeriod(period string) (time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(period)) {
+	case PeriodDaily, "":
+		return 24 * time.Hour, nil
+	case PeriodWeekly:
+		return 7 * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid digest period %q: use daily, weekly or a duration like 12h", period)
+	}
+	return duration, nil
+}
+
+// BuildDigest picks the jobs in history first stored in [since, until).
+// history is every stored job, which is needed to tell new companies from
+// ones seen before.
+func BuildDigest(period string, history []models.Job, since, until time.Time) *Digest {
+	firstSeen := make(map[string]models.Job)
+	companySeen := make(map[string]time.Time)
+	for _, job := range history {
+		if first, ok := firstSeen[job.ID]; !ok || job.ScrapedAt.Before(first.ScrapedAt) {
+			firstSeen[job.ID] = job
+		}
+		company := models.CompanyKey(job.Company)
+		if seen, ok := companySeen[company]; !ok || job.ScrapedAt.Before(seen) {
+			companySeen[company] = job.ScrapedAt
+		}
+	}
+
+	var jobs []models.Job
+	for _, job := range firstSeen {
+		if !job.ScrapedAt.Before(since) && job.ScrapedAt.Before(until) {
+			jobs = append(jobs, job)
+		}
+	}
+	newCompanies := make(map[string]bool)
+	for company, seen := range companySeen {
+		if company != "" && !seen.Before(since) && seen.Before(until) {
+			newCompanies[company] = true
+		}
+	}
+
+	return newDigest(period, since, until, jobs, newCompanies)
+}
+
+// Subset returns a digest over the same period limited to jobs, as sent to
+// a recipient who only wants some of them
+func (d *Digest) Subset(jobs []models.Job) *Digest {
+	newCompanies := make(map[string]bool, len(d.NewCompanies))
+	for _, company := range d.NewCompanies {
+		newCompanies[models.CompanyKey(company.Name)] = true
+	}
+	return newDigest(d.Period, d.Since, d.Until, jobs, newCompanies)
+}
+
+func newDigest(period string, since, until time.Time, jobs []models.Job, newCompanies map[string]bool) *Digest {
+	jobs = append([]models.Job(nil), jobs...)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Relevance != jobs[j].Relevance {
+			return jobs[i].Relevance > jobs[j].Relevance
+		}
+		return jobs[i].ScrapedAt.After(jobs[j].ScrapedAt)
+	})
+
+	d := &Digest{Period: period, Since: since, Until: until, Jobs: jobs}
+	d.TopMatches = jobs[:min(len(jobs), digestTopMatches)]
+
+	counts := make(map[string]*CompanySummary)
+	for _, job := range jobs {
+		key := models.CompanyKey(job.Company)
+		if !newCompanies[key] {
+			continue
+		}
+		if counts[key] == nil {
+			counts[key] = &CompanySummary{Name: models.CompanyName(job.Company)}
+		}
+		counts[key].Jobs++
+	}
+	for _, company := range counts {
+		d.NewCompanies = append(d.NewCompanies, *company)
+	}
+	sort.Slice(d.NewCompanies, func(i, j int) bool {
+		if d.NewCompanies[i].Jobs != d.NewCompanies[j].Jobs {
+			return d.NewCompanies[i].Jobs > d.NewCompanies[j].Jobs
+		}
+		return d.NewCompanies[i].Name < d.NewCompanies[j].Name
+	})
+
+	var paid []models.Job
+	for _, job := range jobs {
+		if SalaryCeiling(job.Salary) > 0 {
+			paid = append(paid, job)
+		}
+	}
+	sort.SliceStable(paid, func(i, j int) bool { return SalaryCeiling(paid[i].Salary) > SalaryCeiling(paid[j].Salary) })
+	d.SalaryHighlights = paid[:min(len(paid), digestHighlights)]
+
+	bySource := make(map[string]*SourceSummary)
+	for _, job := range jobs {
+		if bySource[job.Source] == nil {
+			bySource[job.Source] = &SourceSummary{Source: job.Source}
+		}
+		group := bySource[job.Source]
+		group.Total++
+		// jobs are sorted best first, so each source keeps its best
+		if len(group.Jobs) < digestSourceJobs {
+			group.Jobs = append(group.Jobs, job)
+		}
+	}
+	for _, group := range bySource {
+		d.Sources = append(d.Sources, *group)
+	}
+	sort.Slice(d.Sources, func(i, j int) bool {
+		if d.Sources[i].Total != d.Sources[j].Total {
+			return d.Sources[i].Total > d.Sources[j].Total
+		}
+		return d.Sources[i].Source < d.Sources[j].Source
+	})
+	return d
+}
+
+var salaryFigure = regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?)\s*(k\b)?`)
+
+// SalaryCeiling estimates the top of a free-text salary as a yearly figure,
+// e.g. "$90k - $120k" gives 120000 and "$60/hour" gives 124800. It returns 0
+// when no figure is found.
+func SalaryCeiling(salary string) float64 {
+	text := strings.ToLower(salary)
+	ceiling := 0.0
+	for _, match := range salaryFigure.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		if match[2] != "" {
+			value *= 1000
+		}
+		if value > ceiling {
+			ceiling = value
+		}
+	}
+
+	switch {
+	case strings.Contains(text, "hour") || strings.Contains(text, "/hr"):
+		ceiling *= 2080
+	case strings.Contains(text, "month"):
+		ceiling *= 12
+	case strings.Contains(text, "week"):
+		ceiling *= 52
+	}
+	return ceiling
+}
+
+// Markdown renders the digest as a Markdown document
+func (d *Digest) Markdown() (string, error) {
+	tmpl, err := texttemplate.New("digest").Funcs(texttemplate.FuncMap(digestFuncs)).Parse(digestMarkdownTemplate)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// HTML renders the digest as an HTML email body
+func (d *Digest) HTML() (string, error) {
+	tmpl, err := template.New("digest").Funcs(template.FuncMap(digestFuncs)).Parse(digestHTMLTemplate)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Title names the digest, e.g. "Daily job digest: 12 new jobs"
+func (d *Digest) Title() string {
+	name := "Job digest"
+	switch d.Period {
+	case PeriodDaily:
+		name = "Daily job digest"
+	case PeriodWeekly:
+		name = "Weekly job digest"
+	}
+	count := len(d.Jobs)
+	if count == 1 {
+		return name + ": 1 new job"
+	}
+	return fmt.Sprintf("%s: %d new jobs", name, count)
+}
+
+var digestFuncs = map[string]interface{}{
+	"relevance": func(score float64) string { return strconv.FormatFloat(score, 'f', 2, 64) },
+	"date":      func(t time.Time) string { return models.InDisplayZone(t).Format("Mon 02 Jan 2006") },
+	"plural": func(count int, word string) string {
+		if count == 1 {
+			return "1 " + word
+		}
+		return fmt.Sprintf("%d %ss", count, word)
+	},
+}
+
+// DigestHTML renders the digest with the configured digest template, or the
+// built-in one
+func (n *EmailNotifier) DigestHTML(d *Digest) (string, error) {
+	var out bytes.Buffer
+	if err := n.digestTemplate.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// SendDigest emails the digest to the configured recipients and to users who
+// enabled notifications, each limited to the jobs their settings select.
+// Recipients with nothing new get no email.
+func (n *EmailNotifier) SendDigest(ctx context.Context, d *Digest) error {
+	var errs []error
+	for recipient, selected := range n.recipients(d.Jobs) {
+		digest := d.Subset(selected)
+		body, err := n.DigestHTML(digest)
+		if err != nil {
+			return fmt.Errorf("failed to render digest: %w", err)
+		}
+		if err := n.sendHTML(ctx, recipient, digest.Title(), body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", recipient, err))
+			continue
+		}
+		n.logger.Infof("Emailed digest of %d jobs to %s", len(digest.Jobs), recipient)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,275 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// DiscordConfig configures the Discord notifier, which posts job cards
+// through channel webhooks
+type DiscordConfig struct {
+	Enabled bool `json:"enabled"`
+	// WebhookURL receives every new job at or above MinRelevance; it may be
+	// left out when only routes are wanted
+	WebhookURL   string  `json:"webhookUrl,omitempty"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+	// Username overrides the name the webhook posts as
+	Username string `json:"username,omitempty"`
+	// Routes post the jobs matching a saved search to their own channel's
+	// webhook, in addition to the main one
+	Routes []DiscordRoute `json:"routes,omitempty"`
+}
+
+// DiscordRoute sends the jobs matching one saved search to a channel
+type DiscordRoute struct {
+	// SavedSearch is the saved search ID; User is its owner, empty for the
+	// shared default profile
+	SavedSearch string `json:"savedSearch"`
+	User        string `json:"user,omitempty"`
+	WebhookURL  string `json:"webhookUrl"`
+}
+
+const (
+	// discordEmbedsPerMessage is Discord's limit on embeds in one message
+	discordEmbedsPerMessage = 10
+	// maxDiscordJobs bounds the messages one run posts to a channel
+	maxDiscordJobs = 30
+	// discordMaxWait bounds how long a rate-limited post waits to retry
+	discordMaxWait = 30 * time.Second
+	discordColor   = 0x5865F2
+)
+
+// DiscordNotifier posts newly stored jobs to Discord channels
+type DiscordNotifier struct {
+	config DiscordConfig
+	client *http.Client
+	stores Stores
+	logger *logrus.Logger
+}
+
+// NewDiscordNotifier validates config
+func NewDiscordNotifier(config DiscordConfig, stores Stores, logger *logrus.Logger) (*DiscordNotifier, error) {
+	if config.WebhookURL == "" && len(config.Routes) == 0 {
+		return nil, fmt.Errorf("discord notifications need a webhookUrl or routes")
+	}
+	if config.WebhookURL != "" {
+		if err := checkDiscordWebhook(config.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+	for i, route := range config.Routes {
+		if route.SavedSearch == "" || route.WebhookURL == "" {
+			return nil, fmt.Errorf("discord route %d needs a savedSearch and a webhookUrl", i)
+		}
+		if err := checkDiscordWebhook(route.WebhookURL); err != nil {
+			return nil, fmt.Errorf("discord route %d: %w", i, err)
+		}
+	}
+
+	return &DiscordNotifier{
+		config: config,
+		client: transport.NewClient(15 * time.Second),
+		stores: stores,
+		logger: logger,
+	}, nil
+}
+
+func checkDiscordWebhook(webhookURL string) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("discord webhookUrl must be an https url")
+	}
+	return nil
+}
+
+func (n *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// Notify posts the jobs for each webhook as cards, ten to a message. A
+// failed webhook does not stop the others.
+func (n *DiscordNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	var errs []error
+	for webhook, selected := range n.webhooks(jobs) {
+		byRelevance(selected)
+		label := n.label(webhook)
+		if err := n.postJobs(ctx, webhook, selected); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+			continue
+		}
+		n.logger.Infof("Posted %d new jobs to Discord %s", len(selected), label)
+	}
+	return errors.Join(errs...)
+}
+
+// webhooks maps each webhook URL to the jobs it should receive
+func (n *DiscordNotifier) webhooks(jobs []models.Job) map[string][]models.Job {
+	jobs = aboveRelevance(jobs, n.config.MinRelevance)
+	deliveries := make(map[string][]models.Job)
+	add := func(webhook string, selected []models.Job) {
+		if merged := mergeJobs(deliveries[webhook], selected); len(merged) > 0 {
+			deliveries[webhook] = merged
+		}
+	}
+
+	if n.config.WebhookURL != "" {
+		add(n.config.WebhookURL, jobs)
+	}
+	for _, route := range n.config.Routes {
+		matched, err := searchJobs(n.stores, route.User, route.SavedSearch, jobs)
+		if err != nil {
+			n.logger.Warnf("Skipping Discord route for %s: %v", route.SavedSearch, err)
+			continue
+		}
+		add(route.WebhookURL, matched)
+	}
+	return deliveries
+}
+
+// label names a webhook in logs without its secret token
+func (n *DiscordNotifier) label(webhook string) string {
+	for _, route := range n.config.Routes {
+		if route.WebhookURL == webhook {
+			return "route " + route.SavedSearch
+		}
+	}
+	return "webhook"
+}
+
+// discordMessage is the body of a webhook execution
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Content  string         `json:"content,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// postJobs posts up to maxDiscordJobs cards, the first message saying how
+// many jobs there are
+func (n *DiscordNotifier) postJobs(ctx context.Context, webhook string, jobs []models.Job) error {
+	summary := fmt.Sprintf("**%d new job%s**", len(jobs), plural(len(jobs)))
+	if len(jobs) > maxDiscordJobs {
+		summary += fmt.Sprintf(" (showing the best %d)", maxDiscordJobs)
+		jobs = jobs[:maxDiscordJobs]
+	}
+
+	for start := 0; start < len(jobs); start += discordEmbedsPerMessage {
+		batch := jobs[start:min(start+discordEmbedsPerMessage, len(jobs))]
+		message := discordMessage{Username: n.config.Username}
+		if start == 0 {
+			message.Content = summary
+		}
+		for _, job := range batch {
+			message.Embeds = append(message.Embeds, discordJobEmbed(job))
+		}
+		if err := n.post(ctx, webhook, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discordJobEmbed is a job's card: title linking to the posting, company,
+// location, salary, posted date and relevance
+func discordJobEmbed(job models.Job) discordEmbed {
+	embed := discordEmbed{
+		Title:  truncateRunes(job.Title, 256),
+		URL:    job.Link,
+		Color:  discordColor,
+		Footer: &discordFooter{Text: job.Source},
+	}
+	if job.Summary != nil && job.Summary.Responsibilities != "" {
+		embed.Description = truncateRunes(job.Summary.Responsibilities, 300)
+	}
+	add := func(name, value string) {
+		if value != "" {
+			embed.Fields = append(embed.Fields, discordField{Name: name, Value: truncateRunes(value, 1024), Inline: true})
+		}
+	}
+	add("Company", job.Company)
+	add("Location", job.Location)
+	add("Salary", job.Salary)
+	add("Posted", job.PostedDate())
+	add("Relevance", strconv.FormatFloat(job.Relevance, 'f', 2, 64))
+	return embed
+}
+
+// post executes the webhook, waiting out one rate limit response
+func (n *DiscordNotifier) post(ctx context.Context, webhook string, message discordMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return err
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 1 {
+			wait := discordRetryAfter(resp.Header.Get("Retry-After"))
+			if wait <= discordMaxWait {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+				continue
+			}
+		}
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("discord returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		}
+		return nil
+	}
+}
+
+// discordRetryAfter reads the seconds a Retry-After header asks to wait
+func discordRetryAfter(header string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(header), 64)
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
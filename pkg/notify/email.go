@@ -0,0 +1,348 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+)
+
+//go:embed email.html
+var defaultEmailTemplate string
+
+// EmailConfig configures the SMTP notifier
+type EmailConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	// Port defaults to 587. Port 465 uses implicit TLS; other ports upgrade
+	// with STARTTLS when the server offers it.
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password falls back to the SMTP_PASSWORD environment variable
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
+	// To receives every new job at or above MinRelevance. Users who enable
+	// notifications are emailed as well, filtered by their own settings.
+	To           []string `json:"to,omitempty"`
+	MinRelevance float64  `json:"minRelevance,omitempty"`
+	// Template is the path of an HTML template replacing the built-in one.
+	// It receives .Jobs, .Recipient and .SentAt.
+	Template string `json:"template,omitempty"`
+	// Digest, "daily" or "weekly", replaces the email after every run with
+	// one ranked summary per period
+	Digest string `json:"digest,omitempty"`
+	// DigestTemplate is the path of an HTML template replacing the built-in
+	// digest layout. It receives the Digest.
+	DigestTemplate string `json:"digestTemplate,omitempty"`
+	// Searches switch saved searches on or off for email, as unsubscribe
+	// links would. The To recipients get only the jobs of the searches
+	// switched on, or every new job when none is; users never get the jobs
+	// of their searches switched off.
+	Searches []EmailSearch `json:"searches,omitempty"`
+}
+
+// EmailSearch switches one saved search's jobs on or off for email
+type EmailSearch struct {
+	// SavedSearch is the saved search ID; User is its owner, empty for the
+	// shared default profile
+	SavedSearch string `json:"savedSearch"`
+	User        string `json:"user,omitempty"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// emailTimeout bounds one SMTP conversation
+const emailTimeout = 30 * time.Second
+
+// EmailNotifier emails newly stored jobs over SMTP, one message per
+// recipient
+type EmailNotifier struct {
+	config         EmailConfig
+	sender         string // bare address of config.From for the SMTP envelope
+	template       *template.Template
+	digestTemplate *template.Template
+	stores         Stores
+	logger         *logrus.Logger
+}
+
+// emailData is what the email template renders
+type emailData struct {
+	Jobs      []models.Job
+	Recipient string
+	SentAt    time.Time
+}
+
+// NewEmailNotifier validates config and loads the email template
+func NewEmailNotifier(config EmailConfig, stores Stores, logger *logrus.Logger) (*EmailNotifier, error) {
+	if config.Host == "" || config.From == "" {
+		return nil, fmt.Errorf("email notifications need a host and a from address")
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", config.From, err)
+	}
+	if config.Digest != "" {
+		if _, err := ParsePeriod(config.Digest); err != nil {
+			return nil, err
+		}
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Password == "" {
+		config.Password = os.Getenv("SMTP_PASSWORD")
+	}
+	for i, search := range config.Searches {
+		if search.SavedSearch == "" {
+			return nil, fmt.Errorf("email search toggle %d needs a savedSearch", i)
+		}
+	}
+
+	text := defaultEmailTemplate
+	if config.Template != "" {
+		data, err := os.ReadFile(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template: %w", err)
+		}
+		text = string(data)
+	}
+	tmpl, err := template.New("email").Funcs(template.FuncMap{
+		"relevance": func(score float64) string { return strconv.FormatFloat(score, 'f', 2, 64) },
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	text = digestHTMLTemplate
+	if config.DigestTemplate != "" {
+		data, err := os.ReadFile(config.DigestTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read digest template: %w", err)
+		}
+		text = string(data)
+	}
+	digestTmpl, err := template.New("digest").Funcs(template.FuncMap(digestFuncs)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest template: %w", err)
+	}
+
+	return &EmailNotifier{
+		config:         config,
+		sender:         from.Address,
+		template:       tmpl,
+		digestTemplate: digestTmpl,
+		stores:         stores,
+		logger:         logger,
+	}, nil
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// DigestPeriod returns the configured digest period, empty when every run
+// is emailed
+func (n *EmailNotifier) DigestPeriod() string {
+	return n.config.Digest
+}
+
+// Notify sends each recipient one message listing the jobs meant for them.
+// A failed recipient does not stop the others. In digest mode it sends
+// nothing; see SendDigest.
+func (n *EmailNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	if n.config.Digest != "" {
+		return nil
+	}
+
+	var errs []error
+	for recipient, selected := range n.recipients(jobs) {
+		byRelevance(selected)
+		if err := n.send(ctx, recipient, selected); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", recipient, err))
+			continue
+		}
+		n.logger.Infof("Emailed %d new jobs to %s", len(selected), recipient)
+	}
+	return errors.Join(errs...)
+}
+
+// recipients maps each address to the jobs it should receive, merging the
+// configured list with users who enabled notifications
+func (n *EmailNotifier) recipients(jobs []models.Job) map[string][]models.Job {
+	deliveries := make(map[string][]models.Job)
+	add := func(address string, selected []models.Job) {
+		address = strings.TrimSpace(address)
+		if address == "" || len(selected) == 0 {
+			return
+		}
+		seen := make(map[string]bool, len(deliveries[address]))
+		for _, job := range deliveries[address] {
+			seen[job.ID] = true
+		}
+		for _, job := range selected {
+			if !seen[job.ID] {
+				deliveries[address] = append(deliveries[address], job)
+				seen[job.ID] = true
+			}
+		}
+	}
+
+	shared := n.subscribedJobs(aboveRelevance(jobs, n.config.MinRelevance))
+	for _, address := range n.config.To {
+		add(address, shared)
+	}
+
+	if n.stores.Users != nil {
+		for _, user := range n.stores.Users.List() {
+			if !user.Notifications.Enabled {
+				continue
+			}
+			address := user.Notifications.Email
+			if address == "" {
+				address = user.Email
+			}
+			add(address, userJobs(user, n.stores.Searches, n.switchedOff, jobs, n.logger))
+		}
+	}
+	return deliveries
+}
+
+// subscribedJobs keeps the jobs of the saved searches switched on for the
+// To recipients, or all jobs when none is
+func (n *EmailNotifier) subscribedJobs(jobs []models.Job) []models.Job {
+	var subscribed []models.Job
+	on := false
+	for _, search := range n.config.Searches {
+		if !search.Enabled {
+			continue
+		}
+		on = true
+		matched, err := searchJobs(n.stores, search.User, search.SavedSearch, jobs)
+		if err != nil {
+			n.logger.Warnf("Skipping email for saved search %s: %v", search.SavedSearch, err)
+			continue
+		}
+		subscribed = mergeJobs(subscribed, matched)
+	}
+	if !on {
+		return jobs
+	}
+	return subscribed
+}
+
+// switchedOff reports whether a saved search's jobs are switched off for
+// email
+func (n *EmailNotifier) switchedOff(search models.SavedSearch) bool {
+	for _, toggle := range n.config.Searches {
+		if toggle.SavedSearch == search.ID && toggle.User == search.UserID {
+			return !toggle.Enabled
+		}
+	}
+	return false
+}
+
+func (n *EmailNotifier) send(ctx context.Context, recipient string, jobs []models.Job) error {
+	var html bytes.Buffer
+	if err := n.template.Execute(&html, emailData{Jobs: jobs, Recipient: recipient, SentAt: time.Now()}); err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	subject := fmt.Sprintf("%d new job", len(jobs))
+	if len(jobs) != 1 {
+		subject += "s"
+	}
+	return n.sendHTML(ctx, recipient, subject, html.String())
+}
+
+// sendHTML wraps an HTML body in a MIME message and delivers it
+func (n *EmailNotifier) sendHTML(ctx context.Context, recipient, subject, html string) error {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", recipient)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	body := quotedprintable.NewWriter(&message)
+	body.Write([]byte(html))
+	body.Close()
+
+	return n.deliver(ctx, recipient, message.Bytes())
+}
+
+// deliver runs one SMTP conversation. net/smtp.SendMail takes no context
+// and cannot do implicit TLS, so the client is driven by hand.
+func (n *EmailNotifier) deliver(ctx context.Context, recipient string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	tlsConfig := &tls.Config{ServerName: n.config.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if n.config.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && n.config.Port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(recipient); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,254 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// Events a hook can subscribe to
+const (
+	// EventNewJobs fires once per run with the newly stored jobs
+	EventNewJobs = "new_jobs"
+	// EventBoardFailed fires for each source that failed during a run
+	EventBoardFailed = "board_failed"
+	// EventRunComplete fires after every run, successful or not
+	EventRunComplete = "run_complete"
+	// EventSearchAlerts fires once per run with the jobs saved searches
+	// newly matched, in .Alerts
+	EventSearchAlerts = "search_alerts"
+)
+
+// HookConfig configures a generic HTTP hook, such as a Home Assistant
+// webhook or a custom automation. The body is rendered from a text/template
+// that receives .Event, .Time, .Jobs, .Job, .Run, .Source and .Alerts; the json
+// function encodes a value as JSON, e.g. {"title": {{json .Job.Title}}}.
+type HookConfig struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name,omitempty"`
+	URL     string `json:"url"`
+	// Method defaults to POST
+	Method string `json:"method,omitempty"`
+	// Header values may reference environment variables, e.g.
+	// "Bearer ${HA_TOKEN}"
+	Headers map[string]string `json:"headers,omitempty"`
+	// ContentType defaults to application/json
+	ContentType string `json:"contentType,omitempty"`
+	// Events defaults to new_jobs only
+	Events []string `json:"events,omitempty"`
+	// Template is an inline body template and TemplateFile the path of one.
+	// Without either the event data is sent as JSON.
+	Template     string `json:"template,omitempty"`
+	TemplateFile string `json:"templateFile,omitempty"`
+	// PerJob sends one request per new job, with .Job set, instead of one
+	// per run listing them in .Jobs
+	PerJob       bool    `json:"perJob,omitempty"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+}
+
+const (
+	defaultHookTemplate = "{{json .}}"
+	hookAttempts        = 3
+	hookBackoff         = 2 * time.Second
+)
+
+// hookData is what hook templates render
+type hookData struct {
+	Event  string                 `json:"event"`
+	Time   time.Time              `json:"time"`
+	Jobs   []models.Job           `json:"jobs,omitempty"`
+	Job    *models.Job            `json:"job,omitempty"`
+	Run    *RunReport             `json:"run,omitempty"`
+	Source *models.SourceProgress `json:"source,omitempty"`
+	Alerts []AlertGroup           `json:"alerts,omitempty"`
+}
+
+// HookNotifier sends rendered templates to a URL for the events it
+// subscribes to
+type HookNotifier struct {
+	config   HookConfig
+	events   map[string]bool
+	template *template.Template
+	client   *http.Client
+	logger   *logrus.Logger
+}
+
+// NewHookNotifier validates config and parses its template
+func NewHookNotifier(config HookConfig, logger *logrus.Logger) (*HookNotifier, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("hook %q needs an http or https url", config.Name)
+	}
+	if config.Name == "" {
+		config.Name = parsed.Host
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+	if len(config.Events) == 0 {
+		config.Events = []string{EventNewJobs}
+	}
+
+	events := make(map[string]bool, len(config.Events))
+	for _, event := range config.Events {
+		switch event {
+		case EventNewJobs, EventBoardFailed, EventRunComplete, EventSearchAlerts:
+			events[event] = true
+		default:
+			return nil, fmt.Errorf("hook %s: unknown event %q", config.Name, event)
+		}
+	}
+
+	text := config.Template
+	if config.TemplateFile != "" {
+		data, err := os.ReadFile(config.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s: failed to read template: %w", config.Name, err)
+		}
+		text = string(data)
+	}
+	if text == "" {
+		text = defaultHookTemplate
+	}
+	tmpl, err := template.New(config.Name).Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("hook %s: failed to parse template: %w", config.Name, err)
+	}
+
+	return &HookNotifier{
+		config:   config,
+		events:   events,
+		template: tmpl,
+		client:   transport.NewClient(15 * time.Second),
+		logger:   logger,
+	}, nil
+}
+
+func (n *HookNotifier) Name() string {
+	return "hook " + n.config.Name
+}
+
+// Notify sends the new_jobs event
+func (n *HookNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	if !n.events[EventNewJobs] {
+		return nil
+	}
+	jobs = aboveRelevance(jobs, n.config.MinRelevance)
+	if len(jobs) == 0 {
+		return nil
+	}
+	byRelevance(jobs)
+
+	if !n.config.PerJob {
+		return n.send(ctx, hookData{Event: EventNewJobs, Time: time.Now(), Jobs: jobs})
+	}
+	var errs []error
+	for i := range jobs {
+		if err := n.send(ctx, hookData{Event: EventNewJobs, Time: time.Now(), Job: &jobs[i]}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyRun sends board_failed for each failed source and then run_complete
+func (n *HookNotifier) NotifyRun(ctx context.Context, report RunReport) error {
+	var errs []error
+	if n.events[EventBoardFailed] {
+		for _, source := range report.FailedSources() {
+			source := source
+			if err := n.send(ctx, hookData{Event: EventBoardFailed, Time: time.Now(), Run: &report, Source: &source}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if n.events[EventRunComplete] {
+		if err := n.send(ctx, hookData{Event: EventRunComplete, Time: time.Now(), Run: &report}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyAlerts sends the search_alerts event
+func (n *HookNotifier) NotifyAlerts(ctx context.Context, digest *AlertDigest) error {
+	if !n.events[EventSearchAlerts] || len(digest.Groups) == 0 {
+		return nil
+	}
+	return n.send(ctx, hookData{Event: EventSearchAlerts, Time: digest.Time, Alerts: digest.Groups})
+}
+
+// send renders data and delivers it, retrying network errors and 5xx
+// responses with a growing delay
+func (n *HookNotifier) send(ctx context.Context, data hookData) error {
+	var body bytes.Buffer
+	if err := n.template.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", data.Event, err)
+	}
+
+	var err error
+	for attempt := 1; attempt <= hookAttempts; attempt++ {
+		var retry bool
+		if retry, err = n.deliver(ctx, body.Bytes()); err == nil || !retry {
+			break
+		}
+		if attempt < hookAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(hookBackoff * time.Duration(attempt)):
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", data.Event, err)
+	}
+	return nil
+}
+
+// deliver makes one request, reporting whether a failure is worth retrying
+func (n *HookNotifier) deliver(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, n.config.Method, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", n.config.ContentType)
+	req.Header.Set("User-Agent", "hire.ai")
+	for name, value := range n.config.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+			fmt.Errorf("%s returned %s: %s", n.config.URL, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return false, nil
+}
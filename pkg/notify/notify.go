@@ -0,0 +1,232 @@
+// Package notify tells people about newly stored jobs after each scrape run.
+// Notifiers are configured in the "notifications" section of the config
+// file's globalSettings.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+// Config is the notifications section of GlobalSettings
+type Config struct {
+	Email    *EmailConfig    `json:"email,omitempty"`
+	Slack    *SlackConfig    `json:"slack,omitempty"`
+	Discord  *DiscordConfig  `json:"discord,omitempty"`
+	Telegram *TelegramConfig `json:"telegram,omitempty"`
+	Desktop  *DesktopConfig  `json:"desktop,omitempty"`
+	Ntfy     *NtfyConfig     `json:"ntfy,omitempty"`
+	Pushover *PushoverConfig `json:"pushover,omitempty"`
+	Hooks    []HookConfig    `json:"hooks,omitempty"`
+}
+
+// Notifier delivers newly stored jobs to its recipients
+type Notifier interface {
+	// Name identifies the notifier in logs
+	Name() string
+
+	// Notify delivers jobs to whoever should hear about them. Jobs no
+	// recipient wants are dropped silently.
+	Notify(ctx context.Context, jobs []models.Job) error
+}
+
+// RunNotifier is implemented by notifiers that also report on scrape runs
+// as a whole
+type RunNotifier interface {
+	NotifyRun(ctx context.Context, report RunReport) error
+}
+
+// RunReport summarises one scrape run
+type RunReport = models.RunReport
+
+// Stores are the user data notifiers use to honour each user's notification
+// settings. Either may be nil, in which case only the recipients in the
+// config are notified.
+type Stores struct {
+	Users    *storage.UserStore
+	Searches *storage.SavedSearchStore
+}
+
+// New creates the notifiers enabled in config; a nil config enables none
+func New(config *Config, stores Stores, logger *logrus.Logger) ([]Notifier, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	var notifiers []Notifier
+	if config.Email != nil && config.Email.Enabled {
+		email, err := NewEmailNotifier(*config.Email, stores, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	if config.Slack != nil && config.Slack.Enabled {
+		slack, err := NewSlackNotifier(*config.Slack, stores, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, slack)
+	}
+	if config.Discord != nil && config.Discord.Enabled {
+		discord, err := NewDiscordNotifier(*config.Discord, stores, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, discord)
+	}
+	if config.Telegram != nil && config.Telegram.Enabled {
+		telegram, err := NewTelegramNotifier(*config.Telegram, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, telegram)
+	}
+	if config.Desktop != nil && config.Desktop.Enabled {
+		desktop, err := NewDesktopNotifier(*config.Desktop, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, desktop)
+	}
+	if config.Ntfy != nil && config.Ntfy.Enabled {
+		ntfy, err := NewNtfyNotifier(*config.Ntfy)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, ntfy)
+	}
+	if config.Pushover != nil && config.Pushover.Enabled {
+		pushover, err := NewPushoverNotifier(*config.Pushover)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, pushover)
+	}
+	for _, hookConfig := range config.Hooks {
+		if !hookConfig.Enabled {
+			continue
+		}
+		hook, err := NewHookNotifier(hookConfig, logger)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, hook)
+	}
+	return notifiers, nil
+}
+
+// aboveRelevance keeps jobs scored at least min
+func aboveRelevance(jobs []models.Job, min float64) []models.Job {
+	var kept []models.Job
+	for _, job := range jobs {
+		if job.Relevance >= min {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
+
+// userJobs picks the jobs a user's notification settings ask for: those at
+// or above their relevance threshold that match one of the chosen saved
+// searches, or any of their saved searches when none are chosen. Users
+// without saved searches get every job above the threshold. Searches muted
+// reports true for are left out, so a user whose searches are all muted
+// gets nothing; muted may be nil. Searches whose keyword query does not
+// parse are logged and left out.
+func userJobs(user models.User, searches *storage.SavedSearchStore, muted func(models.SavedSearch) bool, jobs []models.Job, logger *logrus.Logger) []models.Job {
+	settings := user.Notifications
+	jobs = aboveRelevance(jobs, settings.MinRelevance)
+	if searches == nil {
+		return jobs
+	}
+
+	owned := searches.List(user.ID)
+	var filters []models.JobFilter
+	for _, search := range owned {
+		if len(settings.SavedSearches) > 0 && !contains(settings.SavedSearches, search.ID) {
+			continue
+		}
+		if muted != nil && muted(search) {
+			continue
+		}
+		filter := search.Filter
+		if err := filter.Compile(); err != nil {
+			logger.Warnf("Skipping saved search %s: invalid keyword query: %v", search.ID, err)
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	if len(owned) == 0 && len(settings.SavedSearches) == 0 {
+		return jobs
+	}
+
+	var matched []models.Job
+	for i := range jobs {
+		for _, filter := range filters {
+			if filter.Matches(&jobs[i]) {
+				matched = append(matched, jobs[i])
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// searchJobs picks the jobs matching a user's saved search, for notifiers
+// that route saved searches to their own channels
+func searchJobs(stores Stores, userID, searchID string, jobs []models.Job) ([]models.Job, error) {
+	if stores.Searches == nil {
+		return nil, fmt.Errorf("saved searches are not available")
+	}
+	search, err := stores.Searches.Get(userID, searchID)
+	if err != nil {
+		return nil, err
+	}
+	filter := search.Filter
+	if err := filter.Compile(); err != nil {
+		return nil, fmt.Errorf("saved search %s has an invalid keyword query: %w", searchID, err)
+	}
+	var matched []models.Job
+	for i := range jobs {
+		if filter.Matches(&jobs[i]) {
+			matched = append(matched, jobs[i])
+		}
+	}
+	return matched, nil
+}
+
+// mergeJobs appends to queued the jobs it does not have yet
+func mergeJobs(queued, jobs []models.Job) []models.Job {
+	seen := make(map[string]bool, len(queued))
+	for _, job := range queued {
+		seen[job.ID] = true
+	}
+	for _, job := range jobs {
+		if !seen[job.ID] {
+			queued = append(queued, job)
+			seen[job.ID] = true
+		}
+	}
+	return queued
+}
+
+// byRelevance sorts jobs best first
+func byRelevance(jobs []models.Job) {
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Relevance > jobs[j].Relevance })
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// defaultMaxPushes caps the push notifications sent after one scrape
+const defaultMaxPushes = 3
+
+// NtfyConfig configures pushes to an ntfy topic
+type NtfyConfig struct {
+	Enabled bool `json:"enabled"`
+	// Server defaults to https://ntfy.sh
+	Server string `json:"server,omitempty"`
+	Topic  string `json:"topic"`
+	// Token is an access token for protected topics and falls back to the
+	// NTFY_TOKEN environment variable
+	Token        string  `json:"token,omitempty"`
+	Priority     int     `json:"priority,omitempty"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+	// MaxPerRun caps the pushes after one scrape; the rest are summed up in
+	// a final one. Defaults to 3.
+	MaxPerRun int `json:"maxPerRun,omitempty"`
+}
+
+// PushoverConfig configures Pushover pushes
+type PushoverConfig struct {
+	Enabled bool `json:"enabled"`
+	// Token is the application token and User the user or group key. They
+	// fall back to the PUSHOVER_TOKEN and PUSHOVER_USER environment
+	// variables.
+	Token        string  `json:"token,omitempty"`
+	User         string  `json:"user,omitempty"`
+	Device       string  `json:"device,omitempty"`
+	Priority     int     `json:"priority,omitempty"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+	MaxPerRun    int     `json:"maxPerRun,omitempty"`
+}
+
+const pushoverMessagesURL = "https://api.pushover.net/1/messages.json"
+
+// NtfyNotifier publishes one message per new job to an ntfy topic; tapping
+// it opens the job's link
+type NtfyNotifier struct {
+	config NtfyConfig
+	client *http.Client
+}
+
+// NewNtfyNotifier validates config
+func NewNtfyNotifier(config NtfyConfig) (*NtfyNotifier, error) {
+	if strings.TrimSpace(config.Topic) == "" {
+		return nil, fmt.Errorf("ntfy notifications need a topic")
+	}
+	if config.Server == "" {
+		config.Server = "https://ntfy.sh"
+	}
+	if _, err := url.ParseRequestURI(config.Server); err != nil {
+		return nil, fmt.Errorf("invalid ntfy server %q: %w", config.Server, err)
+	}
+	if config.Token == "" {
+		config.Token = os.Getenv("NTFY_TOKEN")
+	}
+	if config.MaxPerRun <= 0 {
+		config.MaxPerRun = defaultMaxPushes
+	}
+	return &NtfyNotifier{config: config, client: transport.NewClient(15 * time.Second)}, nil
+}
+
+func (n *NtfyNotifier) Name() string {
+	return "ntfy"
+}
+
+func (n *NtfyNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	return pushJobs(ctx, jobs, n.config.MinRelevance, n.config.MaxPerRun, n.publish)
+}
+
+func (n *NtfyNotifier) publish(ctx context.Context, title, body, link string) error {
+	endpoint := strings.TrimRight(n.config.Server, "/") + "/" + url.PathEscape(n.config.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// ntfy headers are latin-1 unless RFC 2047 encoded
+	req.Header.Set("Title", mime.QEncoding.Encode("utf-8", title))
+	req.Header.Set("Tags", "briefcase")
+	if link != "" {
+		req.Header.Set("Click", link)
+	}
+	if n.config.Priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(n.config.Priority))
+	}
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+	return doPush(n.client, req)
+}
+
+// PushoverNotifier sends one Pushover message per new job with the job's
+// link attached
+type PushoverNotifier struct {
+	config PushoverConfig
+	client *http.Client
+}
+
+// NewPushoverNotifier validates config
+func NewPushoverNotifier(config PushoverConfig) (*PushoverNotifier, error) {
+	if config.Token == "" {
+		config.Token = os.Getenv("PUSHOVER_TOKEN")
+	}
+	if config.User == "" {
+		config.User = os.Getenv("PUSHOVER_USER")
+	}
+	if config.Token == "" || config.User == "" {
+		return nil, fmt.Errorf("pushover notifications need an application token and a user key")
+	}
+	if config.Priority < -2 || config.Priority > 1 {
+		return nil, fmt.Errorf("pushover priority must be between -2 and 1")
+	}
+	if config.MaxPerRun <= 0 {
+		config.MaxPerRun = defaultMaxPushes
+	}
+	return &PushoverNotifier{config: config, client: transport.NewClient(15 * time.Second)}, nil
+}
+
+func (n *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+func (n *PushoverNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	return pushJobs(ctx, jobs, n.config.MinRelevance, n.config.MaxPerRun, n.send)
+}
+
+func (n *PushoverNotifier) send(ctx context.Context, title, body, link string) error {
+	form := url.Values{
+		"token":   {n.config.Token},
+		"user":    {n.config.User},
+		"title":   {truncateRunes(title, 250)},
+		"message": {truncateRunes(body, 1024)},
+	}
+	if link != "" {
+		form.Set("url", truncateRunes(link, 512))
+		form.Set("url_title", "Open posting")
+	}
+	if n.config.Device != "" {
+		form.Set("device", n.config.Device)
+	}
+	if n.config.Priority != 0 {
+		form.Set("priority", strconv.Itoa(n.config.Priority))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushoverMessagesURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doPush(n.client, req)
+}
+
+// pushJobs sends the best jobs at or above minRelevance, one push each up to
+// limit, and a summary of the rest
+func pushJobs(ctx context.Context, jobs []models.Job, minRelevance float64, limit int, push func(ctx context.Context, title, body, link string) error) error {
+	jobs = aboveRelevance(jobs, minRelevance)
+	byRelevance(jobs)
+
+	var errs []error
+	for i, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i == limit {
+			more := len(jobs) - limit
+			errs = append(errs, push(ctx, "hire.ai", fmt.Sprintf("%d more new matching job%s", more, plural(more)), ""))
+			break
+		}
+		errs = append(errs, push(ctx, job.Title, pushBody(job), job.Link))
+	}
+	return errors.Join(errs...)
+}
+
+// pushBody is the one-line summary shown under a job's title
+func pushBody(job models.Job) string {
+	parts := []string{job.Company}
+	for _, part := range []string{job.Location, job.Salary} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func plural(count int) string {
+	if count == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// doPush sends req and turns an error status into an error, including the
+// message push services put in the body
+func doPush(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var result struct {
+		Error  string   `json:"error"`
+		Errors []string `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil {
+		if result.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, result.Error)
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, strings.Join(result.Errors, "; "))
+		}
+	}
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
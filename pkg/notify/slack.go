@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// SlackConfig configures the Slack notifier. It posts either through an
+// incoming webhook, which is tied to one channel, or as a bot with
+// chat.postMessage, which can post to any channel the bot was invited to.
+type SlackConfig struct {
+	Enabled    bool   `json:"enabled"`
+	WebhookURL string `json:"webhookUrl,omitempty"`
+	// Token is a bot token (xoxb-...) and falls back to the SLACK_BOT_TOKEN
+	// environment variable. It takes precedence over WebhookURL.
+	Token string `json:"token,omitempty"`
+	// Channel receives every new job at or above MinRelevance. It is
+	// required with a token and ignored with a webhook.
+	Channel      string  `json:"channel,omitempty"`
+	MinRelevance float64 `json:"minRelevance,omitempty"`
+	// Routes post the jobs matching a saved search to their own channel, in
+	// addition to the main one
+	Routes []SlackRoute `json:"routes,omitempty"`
+}
+
+// SlackRoute sends the jobs matching one saved search to a channel, either
+// by name with the bot token or through the channel's own incoming webhook
+type SlackRoute struct {
+	// SavedSearch is the saved search ID; User is its owner, empty for the
+	// shared default profile
+	SavedSearch string `json:"savedSearch"`
+	User        string `json:"user,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	WebhookURL  string `json:"webhookUrl,omitempty"`
+}
+
+const (
+	slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+	// maxSlackJobs keeps messages well under Slack's 50 block limit
+	maxSlackJobs = 20
+)
+
+// SlackNotifier posts newly stored jobs to Slack channels
+type SlackNotifier struct {
+	config SlackConfig
+	client *http.Client
+	stores Stores
+	logger *logrus.Logger
+}
+
+// NewSlackNotifier validates config
+func NewSlackNotifier(config SlackConfig, stores Stores, logger *logrus.Logger) (*SlackNotifier, error) {
+	if config.Token == "" {
+		config.Token = os.Getenv("SLACK_BOT_TOKEN")
+	}
+	switch {
+	case config.Token == "" && config.WebhookURL == "":
+		return nil, fmt.Errorf("slack notifications need a webhookUrl or a bot token")
+	case config.Token != "" && config.Channel == "" && len(config.Routes) == 0:
+		return nil, fmt.Errorf("slack notifications with a bot token need a channel or routes")
+	}
+	for i, route := range config.Routes {
+		switch {
+		case route.SavedSearch == "" || (route.Channel == "" && route.WebhookURL == ""):
+			return nil, fmt.Errorf("slack route %d needs a savedSearch and a channel or webhookUrl", i)
+		case route.WebhookURL == "" && config.Token == "":
+			return nil, fmt.Errorf("slack route %d to channel %s needs a bot token or its own webhookUrl", i, route.Channel)
+		}
+	}
+
+	return &SlackNotifier{
+		config: config,
+		client: transport.NewClient(15 * time.Second),
+		stores: stores,
+		logger: logger,
+	}, nil
+}
+
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// slackTarget is where a message goes: a channel posted to with the bot
+// token, or a route's own webhook. The main webhook's channel is fixed by
+// Slack, so it is the zero target.
+type slackTarget struct {
+	channel    string
+	webhookURL string
+}
+
+func (t slackTarget) String() string {
+	if t.channel == "" {
+		return "webhook"
+	}
+	return t.channel
+}
+
+// Notify posts one message per channel. A failed channel does not stop the
+// others.
+func (n *SlackNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	var errs []error
+	for target, selected := range n.targets(jobs) {
+		byRelevance(selected)
+		if err := n.post(ctx, target, selected); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target, err))
+			continue
+		}
+		n.logger.Infof("Posted %d new jobs to Slack %s", len(selected), target)
+	}
+	return errors.Join(errs...)
+}
+
+// targets maps each channel to the jobs it should receive
+func (n *SlackNotifier) targets(jobs []models.Job) map[slackTarget][]models.Job {
+	jobs = aboveRelevance(jobs, n.config.MinRelevance)
+	deliveries := make(map[slackTarget][]models.Job)
+	add := func(target slackTarget, selected []models.Job) {
+		if merged := mergeJobs(deliveries[target], selected); len(merged) > 0 {
+			deliveries[target] = merged
+		}
+	}
+
+	if n.config.Token == "" || n.config.Channel != "" {
+		add(slackTarget{channel: n.config.Channel}, jobs)
+	}
+	for _, route := range n.config.Routes {
+		matched, err := searchJobs(n.stores, route.User, route.SavedSearch, jobs)
+		if err != nil {
+			n.logger.Warnf("Skipping Slack route to %s: %v", slackTarget{route.Channel, route.WebhookURL}, err)
+			continue
+		}
+		add(slackTarget{channel: route.Channel, webhookURL: route.WebhookURL}, matched)
+	}
+	return deliveries
+}
+
+// slackMessage is the body of both webhook and chat.postMessage requests
+type slackMessage struct {
+	Channel     string       `json:"channel,omitempty"`
+	Text        string       `json:"text"`
+	Blocks      []slackBlock `json:"blocks"`
+	UnfurlLinks bool         `json:"unfurl_links"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (n *SlackNotifier) post(ctx context.Context, target slackTarget, jobs []models.Job) error {
+	endpoint, token, channel := n.config.WebhookURL, n.config.Token, target.channel
+	switch {
+	case target.webhookURL != "":
+		endpoint, token, channel = target.webhookURL, "", ""
+	case token != "":
+		endpoint = slackPostMessageURL
+	}
+	body, err := json.Marshal(slackJobsMessage(channel, jobs))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	// chat.postMessage reports failures in the body with a 200
+	if token != "" {
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("invalid slack response: %w", err)
+		}
+		if !result.OK {
+			return fmt.Errorf("slack error: %s", result.Error)
+		}
+	}
+	return nil
+}
+
+// slackJobsMessage lists jobs as one section each, best first, with a note
+// for those left out
+func slackJobsMessage(channel string, jobs []models.Job) slackMessage {
+	summary := fmt.Sprintf("%d new job", len(jobs))
+	if len(jobs) != 1 {
+		summary += "s"
+	}
+
+	blocks := []slackBlock{{Type: "header", Text: &slackText{Type: "plain_text", Text: summary}}}
+	for i, job := range jobs {
+		if i == maxSlackJobs {
+			blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("…and %d more", len(jobs)-maxSlackJobs)},
+			}})
+			break
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: slackJobText(job)}})
+	}
+
+	return slackMessage{Channel: channel, Text: summary, Blocks: blocks}
+}
+
+func slackJobText(job models.Job) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*<%s|%s>*\n%s", job.Link, slackEscape(job.Title), slackEscape(job.Company))
+	if job.Location != "" {
+		fmt.Fprintf(&text, " · %s", slackEscape(job.Location))
+	}
+	if job.Salary != "" {
+		fmt.Fprintf(&text, "\n:moneybag: %s", slackEscape(job.Salary))
+	}
+	if posted := job.PostedDate(); posted != "" {
+		fmt.Fprintf(&text, "\n_%s · posted %s · relevance %.2f_", slackEscape(job.Source), posted, job.Relevance)
+	} else {
+		fmt.Fprintf(&text, "\n_%s · relevance %.2f_", slackEscape(job.Source), job.Relevance)
+	}
+	return text.String()
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control
+// sequences
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
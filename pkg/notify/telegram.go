@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/telegram"
+)
+
+// TelegramConfig configures the Telegram notifier and the bot the telegram
+// command runs with the same token
+type TelegramConfig struct {
+	Enabled bool `json:"enabled"`
+	// Token is the bot token from @BotFather and falls back to the
+	// TELEGRAM_BOT_TOKEN environment variable
+	Token string `json:"token,omitempty"`
+	// ChatIDs receive every new job at or above MinRelevance and the saved
+	// search alerts; only they may send the bot commands
+	ChatIDs      []string `json:"chatIds"`
+	MinRelevance float64  `json:"minRelevance,omitempty"`
+}
+
+// maxTelegramJobs bounds the job cards one run sends to a chat
+const maxTelegramJobs = 20
+
+// TelegramNotifier sends newly stored jobs to Telegram chats
+type TelegramNotifier struct {
+	config TelegramConfig
+	client *telegram.Client
+	logger *logrus.Logger
+}
+
+// ResolveTelegram fills in the token from the environment and validates
+// config
+func ResolveTelegram(config TelegramConfig) (TelegramConfig, error) {
+	if config.Token == "" {
+		config.Token = os.Getenv("TELEGRAM_BOT_TOKEN")
+	}
+	if config.Token == "" {
+		return config, fmt.Errorf("telegram notifications need a bot token")
+	}
+	if len(config.ChatIDs) == 0 {
+		return config, fmt.Errorf("telegram notifications need chatIds")
+	}
+	return config, nil
+}
+
+// NewTelegramNotifier validates config
+func NewTelegramNotifier(config TelegramConfig, logger *logrus.Logger) (*TelegramNotifier, error) {
+	config, err := ResolveTelegram(config)
+	if err != nil {
+		return nil, err
+	}
+	return &TelegramNotifier{config: config, client: telegram.NewClient(config.Token), logger: logger}, nil
+}
+
+func (n *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+// Notify sends each chat the new jobs as cards, best first
+func (n *TelegramNotifier) Notify(ctx context.Context, jobs []models.Job) error {
+	jobs = aboveRelevance(jobs, n.config.MinRelevance)
+	if len(jobs) == 0 {
+		return nil
+	}
+	byRelevance(jobs)
+
+	heading := fmt.Sprintf("<b>%d new job%s</b>", len(jobs), plural(len(jobs)))
+	if len(jobs) > maxTelegramJobs {
+		heading += fmt.Sprintf(" (showing the best %d)", maxTelegramJobs)
+		jobs = jobs[:maxTelegramJobs]
+	}
+	return n.send(ctx, telegram.Messages(heading, jobs))
+}
+
+// NotifyAlerts sends each chat the jobs saved searches newly matched, one
+// heading per search
+func (n *TelegramNotifier) NotifyAlerts(ctx context.Context, digest *AlertDigest) error {
+	var messages []string
+	for _, group := range digest.Groups {
+		jobs := group.Jobs[:min(len(group.Jobs), maxTelegramJobs)]
+		heading := fmt.Sprintf("🔔 <b>%s</b>: %d new match%s", html.EscapeString(group.Search.Name), len(group.Jobs), pluralEs(len(group.Jobs)))
+		messages = append(messages, telegram.Messages(heading, jobs)...)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return n.send(ctx, messages)
+}
+
+// send delivers messages to every chat. A failed chat does not stop the
+// others.
+func (n *TelegramNotifier) send(ctx context.Context, messages []string) error {
+	var errs []error
+	for _, chatID := range n.config.ChatIDs {
+		sent := 0
+		for _, message := range messages {
+			if err := n.client.SendMessage(ctx, chatID, message); err != nil {
+				errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+				break
+			}
+			sent++
+		}
+		if sent == len(messages) {
+			n.logger.Infof("Sent %d Telegram messages to chat %s", sent, chatID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func pluralEs(count int) string {
+	if count == 1 {
+		return ""
+	}
+	return "es"
+}
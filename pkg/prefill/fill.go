@@ -0,0 +1,118 @@
+package prefill
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// fieldTimeout bounds filling one field, so a field missing from the page
+// does not stall the rest
+const fieldTimeout = 5 * time.Second
+
+// chooseScript picks value in a <select>, radio group or checkboxes found
+// by selector, firing the events forms listen for. It returns "" on
+// success or why it could not.
+const chooseScript = `(function(selector, value) {
+	const inputs = document.querySelectorAll(selector);
+	if (inputs.length === 0) return "not on the page";
+	const wanted = value.toLowerCase();
+	const fire = (el) => {
+		el.dispatchEvent(new Event("input", {bubbles: true}));
+		el.dispatchEvent(new Event("change", {bubbles: true}));
+	};
+	const first = inputs[0];
+	if (first.tagName === "SELECT") {
+		for (const option of first.options) {
+			if (option.text.trim().toLowerCase() === wanted) {
+				first.value = option.value;
+				fire(first);
+				return "";
+			}
+		}
+		return "no option " + value;
+	}
+	if (first.type === "checkbox" && inputs.length === 1) {
+		if (first.checked !== ["yes", "true", "1", "y"].includes(wanted)) first.click();
+		return "";
+	}
+	for (const input of inputs) {
+		const label = (input.closest("label") || input.parentElement).innerText.trim().toLowerCase();
+		if (label === wanted || input.value.toLowerCase() === wanted) {
+			if (!input.checked) input.click();
+			return "";
+		}
+	}
+	return "no option " + value;
+})(%q, %q)`
+
+// Fill opens the bundle's apply page in a visible browser and enters the
+// answers. It never submits: once the form is filled, ready is called with
+// the number of answers entered and the labels of those that could not be,
+// and Fill waits for ctx to end or the browser to close while the
+// candidate reviews and submits the form.
+func Fill(ctx context.Context, bundle *Bundle, ready func(filled int, skipped []string)) error {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.Flag("headless", false))
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+
+	if err := chromedp.Run(browserCtx, chromedp.Navigate(bundle.ApplyURL), chromedp.WaitReady("body", chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to open %s: %w", bundle.ApplyURL, err)
+	}
+
+	filled := 0
+	var skipped []string
+	for _, answer := range bundle.Answers {
+		if answer.Value == "" || answer.Selector == "" {
+			continue
+		}
+		if err := fillField(browserCtx, answer); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", answer.Label, err))
+			continue
+		}
+		filled++
+	}
+	ready(filled, skipped)
+
+	<-browserCtx.Done()
+	return nil
+}
+
+func fillField(ctx context.Context, answer Answer) error {
+	ctx, cancel := context.WithTimeout(ctx, fieldTimeout)
+	defer cancel()
+
+	switch answer.Type {
+	case FieldSelect, FieldMulti, FieldCheckbox:
+		var problem string
+		if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(chooseScript, answer.Selector, answer.Value), &problem)); err != nil {
+			return err
+		}
+		if problem != "" {
+			return fmt.Errorf("%s", problem)
+		}
+		return nil
+	}
+
+	var count int
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf("document.querySelectorAll(%q).length", answer.Selector), &count)); err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("not on the page")
+	}
+	if answer.Type == FieldFile {
+		return chromedp.Run(ctx, chromedp.SetUploadFiles(answer.Selector, []string{answer.Value}, chromedp.ByQuery))
+	}
+	// Typing rather than setting the value keeps script-driven forms in
+	// step with the input
+	return chromedp.Run(ctx,
+		chromedp.Focus(answer.Selector, chromedp.ByQuery),
+		chromedp.SendKeys(answer.Selector, strings.TrimSpace(answer.Value), chromedp.ByQuery),
+	)
+}
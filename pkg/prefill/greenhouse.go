@@ -0,0 +1,138 @@
+package prefill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// greenhouseAPI serves a board's postings with their application questions
+const greenhouseAPI = "https://boards-api.greenhouse.io/v1/boards/"
+
+type greenhouseJob struct {
+	Title       string               `json:"title"`
+	AbsoluteURL string               `json:"absolute_url"`
+	CompanyName string               `json:"company_name"`
+	Questions   []greenhouseQuestion `json:"questions"`
+	// LocationQuestions are asked when the posting collects an address
+	LocationQuestions []greenhouseQuestion `json:"location_questions"`
+	Compliance        []struct {
+		Questions []greenhouseQuestion `json:"questions"`
+	} `json:"compliance"`
+}
+
+type greenhouseQuestion struct {
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+	Fields   []struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Values []struct {
+			Label string `json:"label"`
+		} `json:"values"`
+	} `json:"fields"`
+}
+
+// greenhouseTypes maps Greenhouse field types to Field types
+var greenhouseTypes = map[string]string{
+	"input_text":                FieldText,
+	"input_file":                FieldFile,
+	"textarea":                  FieldTextarea,
+	"multi_value_single_select": FieldSelect,
+	"multi_value_multi_select":  FieldMulti,
+}
+
+// fetchGreenhouseForm reads the form from the job board API, which lists
+// every question the hosted form asks
+func fetchGreenhouseForm(ctx context.Context, client *http.Client, link, userAgent string) (*Form, error) {
+	board, id, err := greenhousePosting(link)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := greenhouseAPI + url.PathEscape(board) + "/jobs/" + url.PathEscape(id) + "?questions=true"
+	body, err := fetch(ctx, client, endpoint, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	var job greenhouseJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse Greenhouse posting: %w", err)
+	}
+
+	form := &Form{ATS: ATSGreenhouse, ApplyURL: job.AbsoluteURL, Title: job.Title, Company: job.CompanyName}
+	if form.ApplyURL == "" {
+		form.ApplyURL = link
+	}
+	questions := append(append([]greenhouseQuestion(nil), job.Questions...), job.LocationQuestions...)
+	for _, compliance := range job.Compliance {
+		questions = append(questions, compliance.Questions...)
+	}
+	for _, question := range questions {
+		if field, ok := greenhouseField(question); ok {
+			form.Fields = append(form.Fields, field)
+		}
+	}
+	return form, nil
+}
+
+// greenhouseField turns a question into one field. Questions offering a
+// file or pasted text, like the resume, keep only the file input.
+func greenhouseField(question greenhouseQuestion) (Field, bool) {
+	chosen := -1
+	for i, field := range question.Fields {
+		if field.Type == "input_hidden" {
+			continue
+		}
+		if chosen < 0 || field.Type == "input_file" {
+			chosen = i
+		}
+	}
+	if chosen < 0 {
+		return Field{}, false
+	}
+
+	source := question.Fields[chosen]
+	field := Field{
+		Name:     source.Name,
+		Label:    strings.TrimSpace(question.Label),
+		Type:     greenhouseTypes[source.Type],
+		Required: question.Required,
+		Selector: nameSelector(source.Name),
+	}
+	if field.Type == "" {
+		field.Type = FieldText
+	}
+	switch strings.ToLower(source.Name) {
+	case "email":
+		field.Type = FieldEmail
+	case "phone":
+		field.Type = FieldPhone
+	}
+	for _, value := range source.Values {
+		field.Options = append(field.Options, value.Label)
+	}
+	return field, true
+}
+
+// greenhousePosting finds the board token and job ID in a posting link,
+// either boards.greenhouse.io/<board>/jobs/<id> or an embedded form's
+// for= and token= parameters
+func greenhousePosting(link string) (board, id string, err error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid Greenhouse link %q", link)
+	}
+	if board, id = parsed.Query().Get("for"), parsed.Query().Get("token"); board != "" && id != "" {
+		return board, id, nil
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 1; i+1 < len(parts); i++ {
+		if parts[i] == "jobs" {
+			return parts[i-1], parts[i+1], nil
+		}
+	}
+	return "", "", fmt.Errorf("%s does not link to a Greenhouse posting", link)
+}
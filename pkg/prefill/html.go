@@ -0,0 +1,168 @@
+package prefill
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"hire.ai/pkg/errs"
+)
+
+// fetch GETs link and returns its body
+func fetch(ctx context.Context, client *http.Client, link, userAgent string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", link, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("posting %s %w", link, errs.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned status: %d", link, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// nameSelector finds an input by its name
+func nameSelector(name string) string {
+	return fmt.Sprintf(`[name="%s"]`, strings.ReplaceAll(name, `"`, `\"`))
+}
+
+// htmlFields lists the inputs of a form. Radio buttons sharing a name
+// become one select field and checkboxes sharing one a multi-select.
+// label names an input's question when no <label for> does.
+func htmlFields(form *goquery.Selection, label func(*goquery.Selection) string) []Field {
+	var fields []Field
+	index := make(map[string]int)
+	form.Find("input, textarea, select").Each(func(_ int, input *goquery.Selection) {
+		name, _ := input.Attr("name")
+		if name == "" {
+			return
+		}
+		kind := strings.ToLower(input.AttrOr("type", "text"))
+		switch goquery.NodeName(input) {
+		case "textarea":
+			kind = FieldTextarea
+		case "select":
+			kind = FieldSelect
+		}
+		switch kind {
+		case "hidden", "submit", "button", "reset", "image":
+			return
+		case "radio", "checkbox":
+			option := strings.TrimSpace(input.Parent().Text())
+			if option == "" {
+				option = input.AttrOr("value", "")
+			}
+			if i, ok := index[name]; ok {
+				fields[i].Options = append(fields[i].Options, option)
+				if kind == "checkbox" {
+					fields[i].Type = FieldMulti
+				}
+				return
+			}
+			text, marked := inputLabel(form, input, label)
+			field := Field{
+				Name:     name,
+				Label:    text,
+				Type:     FieldSelect,
+				Required: marked || isRequired(input),
+				Options:  []string{option},
+				Selector: nameSelector(name),
+			}
+			if kind == "checkbox" {
+				field.Type = FieldCheckbox
+			}
+			index[name] = len(fields)
+			fields = append(fields, field)
+			return
+		case FieldTextarea, FieldSelect, FieldFile, FieldEmail, FieldURL:
+		case "tel":
+			kind = FieldPhone
+		default:
+			kind = FieldText
+		}
+
+		text, marked := inputLabel(form, input, label)
+		field := Field{
+			Name:     name,
+			Label:    text,
+			Type:     kind,
+			Required: marked || isRequired(input),
+			Selector: nameSelector(name),
+		}
+		input.Find("option").Each(func(_ int, option *goquery.Selection) {
+			if value, _ := option.Attr("value"); value != "" {
+				field.Options = append(field.Options, strings.TrimSpace(option.Text()))
+			}
+		})
+		index[name] = len(fields)
+		fields = append(fields, field)
+	})
+
+	// A lone checkbox is a yes/no question rather than a list, often
+	// labelled only by the text beside it
+	for i := range fields {
+		if fields[i].Type == FieldCheckbox {
+			if fields[i].Label == fields[i].Name && fields[i].Options[0] != "" {
+				fields[i].Label = fields[i].Options[0]
+			}
+			fields[i].Options = nil
+		}
+	}
+	return fields
+}
+
+// inputLabel finds the text naming input: its <label for>, the form's own
+// question label, the label around it, or its placeholder. marked reports
+// a required marker in the label.
+func inputLabel(form, input *goquery.Selection, label func(*goquery.Selection) string) (text string, marked bool) {
+	var candidates []string
+	if id, ok := input.Attr("id"); ok && id != "" {
+		candidates = append(candidates, form.Find(fmt.Sprintf(`label[for="%s"]`, id)).First().Text())
+	}
+	if label != nil {
+		candidates = append(candidates, label(input))
+	}
+	if !input.Is("[type=radio], [type=checkbox]") {
+		candidates = append(candidates, input.Closest("label").Text())
+	}
+	candidates = append(candidates, input.AttrOr("placeholder", ""))
+	for _, candidate := range candidates {
+		if text, marked = cleanLabel(candidate); text != "" {
+			return text, marked
+		}
+	}
+	return input.AttrOr("name", ""), false
+}
+
+// cleanLabel drops required markers, reporting whether there was one, and
+// collapses whitespace
+func cleanLabel(text string) (string, bool) {
+	marked := strings.ContainsAny(text, "✱*")
+	text = strings.NewReplacer("✱", "", "*", "").Replace(text)
+	return strings.Join(strings.Fields(text), " "), marked
+}
+
+func isRequired(input *goquery.Selection) bool {
+	_, required := input.Attr("required")
+	return required || input.AttrOr("aria-required", "") == "true"
+}
@@ -0,0 +1,65 @@
+package prefill
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fetchLeverForm reads the form from the posting's apply page. Lever's
+// postings API leaves out the questions, so the page itself is parsed.
+func fetchLeverForm(ctx context.Context, client *http.Client, link, userAgent string) (*Form, error) {
+	applyURL, err := leverApplyURL(link)
+	if err != nil {
+		return nil, err
+	}
+	body, err := fetch(ctx, client, applyURL, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	application := doc.Find("form#application-form, form.application-form").First()
+	if application.Length() == 0 {
+		application = doc.Find("form").First()
+	}
+	if application.Length() == 0 {
+		return nil, fmt.Errorf("no application form on %s", applyURL)
+	}
+
+	form := &Form{
+		ATS:      ATSLever,
+		ApplyURL: applyURL,
+		Title:    strings.TrimSpace(doc.Find(".posting-headline h2").First().Text()),
+		Fields: htmlFields(application, func(input *goquery.Selection) string {
+			return input.Closest(".application-question").Find(".application-label").First().Text()
+		}),
+	}
+	// Pages are titled "Company - Title"
+	if company, _, found := strings.Cut(doc.Find("title").First().Text(), " - "); found {
+		form.Company = strings.TrimSpace(company)
+	}
+	return form, nil
+}
+
+// leverApplyURL turns a posting link into its apply page,
+// jobs.lever.co/<company>/<id>/apply
+func leverApplyURL(link string) (string, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid Lever link %q", link)
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("%s does not link to a Lever posting", link)
+	}
+	return fmt.Sprintf("%s://%s/%s/%s/apply", parsed.Scheme, parsed.Host, parts[0], parts[1]), nil
+}
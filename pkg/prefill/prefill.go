@@ -0,0 +1,381 @@
+// Package prefill reads the application forms of Greenhouse, Lever and
+// Workday postings and matches the candidate's saved answers to their
+// fields, producing a bundle that can be pasted from or filled into the
+// form by the browser.
+package prefill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/resume"
+)
+
+// The applicant tracking systems forms can be read from
+const (
+	ATSGreenhouse = "greenhouse"
+	ATSLever      = "lever"
+	ATSWorkday    = "workday"
+)
+
+// Field types, normalised across systems
+const (
+	FieldText     = "text"
+	FieldTextarea = "textarea"
+	FieldEmail    = "email"
+	FieldPhone    = "tel"
+	FieldURL      = "url"
+	FieldFile     = "file"
+	FieldSelect   = "select"
+	FieldMulti    = "multi_select"
+	FieldCheckbox = "checkbox"
+)
+
+// Where an Answer came from
+const (
+	SourceProfile  = "profile"
+	SourceQuestion = "question"
+	SourceResume   = "resume"
+)
+
+// Field is one input of an application form
+type Field struct {
+	// Name is the form's name for the input
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"`
+	// Selector finds the input on the apply page
+	Selector string `json:"selector,omitempty"`
+}
+
+// Form is the structure of a posting's application form
+type Form struct {
+	ATS      string  `json:"ats"`
+	ApplyURL string  `json:"apply_url"`
+	Title    string  `json:"title,omitempty"`
+	Company  string  `json:"company,omitempty"`
+	Fields   []Field `json:"fields"`
+	// Note explains anything the form could not include
+	Note string `json:"note,omitempty"`
+}
+
+// Profile holds the candidate's answers to the questions most forms ask.
+// Questions answers any other question by a phrase of its label, such as
+// "authorized to work": "Yes"; the longest matching phrase wins.
+type Profile struct {
+	FirstName      string            `json:"first_name"`
+	LastName       string            `json:"last_name"`
+	Email          string            `json:"email"`
+	Phone          string            `json:"phone"`
+	Location       string            `json:"location,omitempty"`
+	CurrentCompany string            `json:"current_company,omitempty"`
+	CurrentTitle   string            `json:"current_title,omitempty"`
+	LinkedIn       string            `json:"linkedin,omitempty"`
+	GitHub         string            `json:"github,omitempty"`
+	Website        string            `json:"website,omitempty"`
+	Resume         string            `json:"resume,omitempty"`
+	CoverLetter    string            `json:"cover_letter,omitempty"`
+	Questions      map[string]string `json:"questions,omitempty"`
+}
+
+// Answer is the value for one field of the form
+type Answer struct {
+	Field
+	Value string `json:"value,omitempty"`
+	// Source is profile, question or resume; empty when unanswered
+	Source string `json:"source,omitempty"`
+}
+
+// Bundle is a form with the candidate's answers, ready to paste or fill
+type Bundle struct {
+	JobID      string   `json:"job_id,omitempty"`
+	ATS        string   `json:"ats"`
+	ApplyURL   string   `json:"apply_url"`
+	Title      string   `json:"title,omitempty"`
+	Company    string   `json:"company,omitempty"`
+	ResumePath string   `json:"resume_path,omitempty"`
+	Answers    []Answer `json:"answers"`
+	// Missing lists the labels of required fields left unanswered
+	Missing   []string  `json:"missing,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoadProfile reads a profile from a JSON file. A missing file yields an
+// empty profile, so a bundle still shows what the form asks. Resume and
+// CoverLetter paths are read relative to the file.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Profile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	// Files are relative to the profile, and made absolute as the browser
+	// uploads them from its own working directory
+	for _, file := range []*string{&profile.Resume, &profile.CoverLetter} {
+		if *file != "" && !filepath.IsAbs(*file) && looksLikeFile(*file) {
+			*file = filepath.Join(filepath.Dir(path), *file)
+		}
+	}
+	return &profile, nil
+}
+
+// Detect names the applicant tracking system serving link, or returns ""
+func Detect(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case strings.HasSuffix(host, "greenhouse.io"):
+		return ATSGreenhouse
+	case strings.HasSuffix(host, "lever.co"):
+		return ATSLever
+	case strings.HasSuffix(host, "myworkdayjobs.com"), strings.HasSuffix(host, "myworkdaysite.com"):
+		return ATSWorkday
+	}
+	return ""
+}
+
+// FetchForm reads the application form of the posting at link
+func FetchForm(ctx context.Context, client *http.Client, link, userAgent string) (*Form, error) {
+	switch Detect(link) {
+	case ATSGreenhouse:
+		return fetchGreenhouseForm(ctx, client, link, userAgent)
+	case ATSLever:
+		return fetchLeverForm(ctx, client, link, userAgent)
+	case ATSWorkday:
+		return workdayForm(link)
+	}
+	return nil, fmt.Errorf("%s is not a Greenhouse, Lever or Workday posting", link)
+}
+
+// Build answers the form's fields from profile. Contact details missing
+// from the profile are taken from the resume text, when given.
+func Build(form *Form, profile *Profile, resumeText string) *Bundle {
+	bundle := &Bundle{
+		ATS:        form.ATS,
+		ApplyURL:   form.ApplyURL,
+		Title:      form.Title,
+		Company:    form.Company,
+		ResumePath: profile.Resume,
+		Note:       form.Note,
+		CreatedAt:  time.Now().UTC(),
+	}
+	fromResume := contactsFromResume(resumeText)
+	for _, field := range form.Fields {
+		answer := Answer{Field: field}
+		answer.Value, answer.Source = profile.answer(field, fromResume)
+		if answer.Value != "" && len(field.Options) > 0 {
+			answer.Value = matchOption(answer.Value, field.Options)
+			if answer.Value == "" {
+				answer.Source = ""
+			}
+		}
+		if answer.Value == "" && field.Required {
+			bundle.Missing = append(bundle.Missing, field.Label)
+		}
+		bundle.Answers = append(bundle.Answers, answer)
+	}
+	return bundle
+}
+
+// Standard fields are recognised by the input's name or, failing that, its
+// label
+var (
+	standardNames = map[string]string{
+		"first_name": "first_name", "firstname": "first_name", "legalnamesection_firstname": "first_name",
+		"last_name": "last_name", "lastname": "last_name", "legalnamesection_lastname": "last_name",
+		"name": "name", "full_name": "name",
+		"email": "email", "phone": "phone", "phone-number": "phone",
+		"location": "location", "org": "current_company", "current_company": "current_company",
+		"resume": "resume", "file-upload-input-ref": "resume", "cover_letter": "cover_letter",
+		"urls[linkedin]": "linkedin", "linkedinquestion": "linkedin",
+		"urls[github]": "github", "urls[portfolio]": "website", "urls[other]": "website",
+	}
+	standardLabels = []struct {
+		pattern *regexp.Regexp
+		key     string
+	}{
+		{regexp.MustCompile(`(?i)linked\s*in`), "linkedin"},
+		{regexp.MustCompile(`(?i)github`), "github"},
+		{regexp.MustCompile(`(?i)website|portfolio|personal (site|url)`), "website"},
+		{regexp.MustCompile(`(?i)^\s*(legal\s+)?first\s+name`), "first_name"},
+		{regexp.MustCompile(`(?i)^\s*(legal\s+)?(last|family)\s+name|^\s*surname`), "last_name"},
+		{regexp.MustCompile(`(?i)^\s*(full\s+)?name\s*\*?\s*$`), "name"},
+		{regexp.MustCompile(`(?i)^\s*e-?mail`), "email"},
+		{regexp.MustCompile(`(?i)^\s*(phone|mobile|telephone)`), "phone"},
+		{regexp.MustCompile(`(?i)^\s*(resume|cv)\b`), "resume"},
+		{regexp.MustCompile(`(?i)^\s*cover\s+letter`), "cover_letter"},
+		{regexp.MustCompile(`(?i)current\s+(company|employer)`), "current_company"},
+		{regexp.MustCompile(`(?i)current\s+(job\s+)?title`), "current_title"},
+		{regexp.MustCompile(`(?i)^\s*(current\s+)?(location|city)\s*\*?\s*$`), "location"},
+	}
+)
+
+// standardKey names the standard field a form field asks for, or ""
+func standardKey(field Field) string {
+	if key, ok := standardNames[strings.ToLower(field.Name)]; ok {
+		return key
+	}
+	for _, label := range standardLabels {
+		if label.pattern.MatchString(field.Label) {
+			return label.key
+		}
+	}
+	return ""
+}
+
+// answer finds the value for field and where it came from
+func (p *Profile) answer(field Field, fromResume map[string]string) (string, string) {
+	if key := standardKey(field); key != "" {
+		value := p.standard(key)
+		// File inputs take paths and other inputs text, so a resume path
+		// is not pasted into a text box nor a letter's text uploaded
+		if key == "resume" || key == "cover_letter" {
+			if (field.Type == FieldFile) != looksLikeFile(value) {
+				value = ""
+			}
+		}
+		if value != "" {
+			return value, SourceProfile
+		}
+		if value := fromResume[key]; value != "" {
+			return value, SourceResume
+		}
+	}
+
+	best := ""
+	for phrase := range p.Questions {
+		if len(phrase) > len(best) && strings.Contains(strings.ToLower(field.Label), strings.ToLower(phrase)) {
+			best = phrase
+		}
+	}
+	if best != "" {
+		return p.Questions[best], SourceQuestion
+	}
+	return "", ""
+}
+
+func (p *Profile) standard(key string) string {
+	switch key {
+	case "first_name":
+		return p.FirstName
+	case "last_name":
+		return p.LastName
+	case "name":
+		return strings.TrimSpace(p.FirstName + " " + p.LastName)
+	case "email":
+		return p.Email
+	case "phone":
+		return p.Phone
+	case "location":
+		return p.Location
+	case "current_company":
+		return p.CurrentCompany
+	case "current_title":
+		return p.CurrentTitle
+	case "linkedin":
+		return p.LinkedIn
+	case "github":
+		return p.GitHub
+	case "website":
+		return p.Website
+	case "resume":
+		return p.Resume
+	case "cover_letter":
+		return p.CoverLetter
+	}
+	return ""
+}
+
+var (
+	emailPattern    = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern    = regexp.MustCompile(`\+?\d[\d\s().-]{7,}\d`)
+	linkedInPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z]{2,3}\.)?linkedin\.com/in/[A-Za-z0-9_-]+/?`)
+	gitHubPattern   = regexp.MustCompile(`(?i)(?:https?://)?github\.com/[A-Za-z0-9_-]+/?`)
+)
+
+// contactsFromResume finds the contact details resumes usually open with
+func contactsFromResume(text string) map[string]string {
+	contacts := make(map[string]string)
+	if text == "" {
+		return contacts
+	}
+	contacts["email"] = emailPattern.FindString(text)
+	contacts["phone"] = strings.TrimSpace(phonePattern.FindString(text))
+	for key, pattern := range map[string]*regexp.Regexp{"linkedin": linkedInPattern, "github": gitHubPattern} {
+		if link := pattern.FindString(text); link != "" {
+			if !strings.HasPrefix(strings.ToLower(link), "http") {
+				link = "https://" + link
+			}
+			contacts[key] = link
+		}
+	}
+	// Resumes start with the candidate's name
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		if len(words) <= 4 && len(words) >= 2 && !strings.ContainsAny(line, "@:/|0123456789") {
+			contacts["name"] = strings.Join(words, " ")
+			contacts["first_name"] = words[0]
+			contacts["last_name"] = strings.Join(words[1:], " ")
+		}
+		break
+	}
+	return contacts
+}
+
+// matchOption returns the option value stands for: an exact match, else
+// the only option starting with it. It returns "" when none fits.
+func matchOption(value string, options []string) string {
+	var prefixed []string
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option
+		}
+		if strings.HasPrefix(strings.ToLower(option), strings.ToLower(value)) {
+			prefixed = append(prefixed, option)
+		}
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0]
+	}
+	return ""
+}
+
+// ResumeText extracts the text of the resume at path for Build, or returns
+// "" when there is none
+func ResumeText(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("resume %s: %w", path, err)
+	}
+	return resume.ExtractText(path)
+}
+
+// looksLikeFile tells a cover letter file from cover letter text
+func looksLikeFile(value string) bool {
+	return !strings.ContainsAny(value, "\n") && filepath.Ext(value) != ""
+}
@@ -0,0 +1,56 @@
+package prefill
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// workdayFields are the inputs of the "My Information" and "My Experience"
+// steps every Workday application starts with, found by Workday's
+// data-automation-id attributes
+var workdayFields = []Field{
+	{Name: "file-upload-input-ref", Label: "Resume/CV", Type: FieldFile},
+	{Name: "legalNameSection_firstName", Label: "First Name", Type: FieldText, Required: true},
+	{Name: "legalNameSection_lastName", Label: "Last Name", Type: FieldText, Required: true},
+	{Name: "email", Label: "Email Address", Type: FieldEmail, Required: true},
+	{Name: "phone-number", Label: "Phone Number", Type: FieldPhone, Required: true},
+	{Name: "addressSection_addressLine1", Label: "Address Line 1", Type: FieldText},
+	{Name: "addressSection_city", Label: "City", Type: FieldText},
+	{Name: "addressSection_postalCode", Label: "Postal Code", Type: FieldText},
+	{Name: "linkedinQuestion", Label: "LinkedIn", Type: FieldURL},
+}
+
+// workdayRequisition strips the requisition ID from a posting's path
+// segment, as in Senior-Engineer_R12345
+var workdayRequisition = regexp.MustCompile(`_[A-Za-z]*-?\d[\w-]*$`)
+
+// workdayForm describes the standard Workday form. Workday renders forms
+// in the browser behind a sign-in, so the employer's own questions, asked
+// on later steps, cannot be read beforehand.
+func workdayForm(link string) (*Form, error) {
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Workday link %q", link)
+	}
+	path := strings.TrimSuffix(strings.TrimRight(parsed.Path, "/"), "/apply")
+	if !strings.Contains(path, "/job/") {
+		return nil, fmt.Errorf("%s does not link to a Workday posting", link)
+	}
+
+	form := &Form{
+		ATS:      ATSWorkday,
+		ApplyURL: fmt.Sprintf("%s://%s%s/apply", parsed.Scheme, parsed.Host, path),
+		Company:  strings.Split(parsed.Host, ".")[0],
+		Note:     "Workday forms need a sign-in, so only its standard fields are included; employer questions on later steps are not",
+	}
+	segment := path[strings.LastIndex(path, "/")+1:]
+	form.Title = strings.ReplaceAll(workdayRequisition.ReplaceAllString(segment, ""), "-", " ")
+
+	for _, field := range workdayFields {
+		field.Selector = fmt.Sprintf(`[data-automation-id="%s"]`, field.Name)
+		form.Fields = append(form.Fields, field)
+	}
+	return form, nil
+}
@@ -0,0 +1,64 @@
+package prep
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const markdownTemplate = `# Interview prep: {{.Title}} at {{.Company}}
+
+{{if .Link}}Posting: {{.Link}}
+{{end}}Prepared {{.CreatedAt.Format "Mon 02 Jan 2006"}} with {{.Model}}
+{{with .Match}}
+## Your fit
+
+- Match: {{percent .Score}}
+- Skills you have: {{list .MatchedSkills}}
+- Skills to brush up on: {{list .MissingSkills}}
+{{range .Gaps}}- {{.}}
+{{end}}{{end}}
+## Likely topics
+{{range .Topics}}
+- **{{.Name}}**{{if .Why}}: {{.Why}}{{end}}{{end}}
+
+## Practice questions
+{{range $i, $q := .Questions}}
+{{inc $i}}. {{$q.Question}}{{if or $q.Topic $q.Kind}}
+   _{{join $q.Topic $q.Kind}}_{{end}}{{if $q.Tip}}
+   Tip: {{$q.Tip}}{{end}}
+{{end}}`
+
+var markdownFuncs = template.FuncMap{
+	"percent": func(score float64) string { return fmt.Sprintf("%.0f%%", score*100) },
+	"list": func(values []string) string {
+		if len(values) == 0 {
+			return "none"
+		}
+		return strings.Join(values, ", ")
+	},
+	"inc": func(i int) int { return i + 1 },
+	"join": func(parts ...string) string {
+		var kept []string
+		for _, part := range parts {
+			if part != "" {
+				kept = append(kept, part)
+			}
+		}
+		return strings.Join(kept, " · ")
+	},
+}
+
+// Markdown renders the pack as a Markdown document
+func (p *Pack) Markdown() (string, error) {
+	tmpl, err := template.New("prep").Funcs(markdownFuncs).Parse(markdownTemplate)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, p); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
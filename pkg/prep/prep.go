@@ -0,0 +1,173 @@
+// Package prep drafts interview preparation packs: the topics a job's
+// interviews are likely to cover and questions to practise, weighted
+// towards the skills the candidate's resume lacks.
+package prep
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the interviewPrep section of GlobalSettings
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// Questions is how many practice questions a pack asks for. Defaults
+	// to 12.
+	Questions int `json:"questions,omitempty"`
+}
+
+// maxPromptDescription keeps prompts within small local models' context
+const maxPromptDescription = 12000
+
+const systemPrompt = `You help a candidate prepare for job interviews. From the job posting and the candidate's skills, predict what the interviews will cover. Reply with a single JSON object and nothing else:
+{"topics": [{"name": "...", "why": "..."}], "questions": [{"question": "...", "topic": "...", "kind": "technical|behavioral|system design|role", "tip": "..."}]}
+List 4 to 8 topics, each with one sentence on why the posting suggests it. Questions should be ones an interviewer for this role would really ask; the tip is one sentence on what a strong answer covers. Give extra attention to skills the candidate is missing.`
+
+// Pack is the preparation material for one job
+type Pack struct {
+	JobID   string `json:"job_id"`
+	Title   string `json:"title"`
+	Company string `json:"company"`
+	Link    string `json:"link"`
+	// Match is set when the pack was made against a resume or skill list
+	Match     *models.JobMatch `json:"match,omitempty"`
+	Topics    []Topic          `json:"topics"`
+	Questions []Question       `json:"questions"`
+	Model     string           `json:"model"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// Topic is a subject the interviews are likely to cover
+type Topic struct {
+	Name string `json:"name"`
+	Why  string `json:"why"`
+}
+
+// Question is a practice question
+type Question struct {
+	Question string `json:"question"`
+	Topic    string `json:"topic"`
+	// Kind is technical, behavioral, system design or role
+	Kind string `json:"kind"`
+	Tip  string `json:"tip"`
+}
+
+// Generator calls the configured LLM
+type Generator struct {
+	config Config
+	client ai.LLMClient
+	logger *logrus.Logger
+}
+
+// New validates config, filling in what it leaves out from defaults; a nil
+// or disabled config returns a nil Generator
+func New(config *Config, defaults *ai.Defaults, logger *logrus.Logger) (*Generator, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, false)
+	client, err := ai.NewClient(c.Config, "interview_prep")
+	if err != nil {
+		return nil, err
+	}
+	if c.Questions <= 0 {
+		c.Questions = 12
+	}
+
+	return &Generator{config: c, client: client, logger: logger}, nil
+}
+
+// Generate drafts a pack for job. match, from resume.Profile.Match, may be
+// nil when the candidate's skills are unknown.
+func (g *Generator) Generate(ctx context.Context, job *models.Job, match *models.JobMatch) (*Pack, error) {
+	if strings.TrimSpace(job.Description) == "" {
+		return nil, fmt.Errorf("job %s has no description to prepare from", job.ID)
+	}
+
+	reply, err := g.client.Complete(ctx, systemPrompt, g.prompt(job, match))
+	if err != nil {
+		return nil, err
+	}
+
+	pack := &Pack{
+		JobID:     job.ID,
+		Title:     job.Title,
+		Company:   job.Company,
+		Link:      job.Link,
+		Match:     match,
+		Model:     g.client.Model(),
+		CreatedAt: time.Now(),
+	}
+	if err := parseReply(reply, pack); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+func (g *Generator) prompt(job *models.Job, match *models.JobMatch) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Write %d questions.\n\nTitle: %s\nCompany: %s\n", g.config.Questions, job.Title, job.Company)
+	if c := job.Classification; c != nil {
+		fmt.Fprintf(&text, "Seniority: %s\nIndustry: %s\n", c.Seniority, c.Industry)
+	}
+	fmt.Fprintf(&text, "\nDescription:\n%s\n", ai.Truncate(job.Description, maxPromptDescription))
+
+	if match == nil {
+		text.WriteString("\nThe candidate's skills are unknown.")
+		return text.String()
+	}
+	fmt.Fprintf(&text, "\nCandidate's matching skills: %s\n", listOrNone(match.MatchedSkills))
+	fmt.Fprintf(&text, "Skills the candidate is missing: %s\n", listOrNone(match.MissingSkills))
+	if len(match.Gaps) > 0 {
+		fmt.Fprintf(&text, "Other gaps: %s\n", strings.Join(match.Gaps, "; "))
+	}
+	return text.String()
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// parseReply reads the topics and questions in a reply into pack
+func parseReply(reply string, pack *Pack) error {
+	var answer struct {
+		Topics    []Topic    `json:"topics"`
+		Questions []Question `json:"questions"`
+	}
+	if err := ai.DecodeJSON(reply, &answer); err != nil {
+		return err
+	}
+
+	for _, topic := range answer.Topics {
+		topic.Name, topic.Why = strings.TrimSpace(topic.Name), strings.TrimSpace(topic.Why)
+		if topic.Name != "" {
+			pack.Topics = append(pack.Topics, topic)
+		}
+	}
+	for _, question := range answer.Questions {
+		question.Question = strings.TrimSpace(question.Question)
+		question.Topic = strings.TrimSpace(question.Topic)
+		question.Kind = strings.ToLower(strings.TrimSpace(question.Kind))
+		question.Tip = strings.TrimSpace(question.Tip)
+		if question.Question != "" {
+			pack.Questions = append(pack.Questions, question)
+		}
+	}
+	if len(pack.Questions) == 0 {
+		return fmt.Errorf("reply has no questions")
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// arbeitnowMaxPages bounds how many pages of the Arbeitnow feed one search
+// reads while looking for matching jobs
+const arbeitnowMaxPages = 5
+
+// arbeitnowPageSize is how many jobs a page of the feed lists, and so the
+// page size of searches that set no limit
+const arbeitnowPageSize = 100
+
+// ArbeitnowProvider implements the JobAPIProvider interface for the Arbeitnow
+// job board API, which lists jobs in Germany and across Europe without an API
+// key. The API takes no search terms, so keywords and location are matched
+// against each page of the feed.
+type ArbeitnowProvider struct {
+	config APIConfig
+	client *http.Client
+}
+
+// NewArbeitnowProvider creates a new Arbeitnow API provider
+func NewArbeitnowProvider(config APIConfig, timeout time.Duration) *ArbeitnowProvider {
+	return &ArbeitnowProvider{
+		config: config,
+		client: transport.NewClient(timeout),
+	}
+}
+
+// GetName returns the provider name
+func (p *ArbeitnowProvider) GetName() string {
+	return "arbeitnow"
+}
+
+// Search searches for jobs using the Arbeitnow API, reading pages of the feed
+// until Offset+Limit matching jobs are found or the feed ends
+func (p *ArbeitnowProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Arbeitnow provider not configured: %w", errs.ErrAuth)
+	}
+
+	result, err := pageMatches(query, arbeitnowPageSize, arbeitnowMaxPages, func(page int) ([]models.Job, bool, error) {
+		apiResp, err := p.fetchPage(ctx, page+1)
+		if err != nil {
+			return nil, false, err
+		}
+		var matched []models.Job
+		for _, arbeitnowJob := range apiResp.Data {
+			if p.matches(arbeitnowJob, query) {
+				matched = append(matched, p.convertJob(arbeitnowJob))
+			}
+		}
+		return matched, apiResp.Links.Next != "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Jobs:       result.jobs,
+		Total:      result.matched,
+		Page:       result.page,
+		PerPage:    result.perPage,
+		HasMore:    result.hasMore,
+		Provider:   p.GetName(),
+		SearchedAt: time.Now(),
+	}, nil
+}
+
+// IsConfigured checks if the provider is properly configured. Arbeitnow needs
+// no API key, so enabling it is enough.
+func (p *ArbeitnowProvider) IsConfigured() bool {
+	return p.config.Enabled
+}
+
+// GetRateLimit returns the rate limit information
+func (p *ArbeitnowProvider) GetRateLimit() RateLimit {
+	// Parse the cooldown period from string to duration
+	cooldown, err := time.ParseDuration(p.config.RateLimit.CooldownPeriod)
+	if err != nil {
+		cooldown = 1 * time.Second // default
+	}
+
+	return RateLimit{
+		RequestsPerMinute: p.config.RateLimit.RequestsPerMinute,
+		RequestsPerHour:   p.config.RateLimit.RequestsPerHour,
+		RequestsPerDay:    p.config.RateLimit.RequestsPerDay,
+		CooldownPeriod:    cooldown,
+	}
+}
+
+// ValidateCredentials checks that the API answers; there are no credentials
+// to check
+func (p *ArbeitnowProvider) ValidateCredentials(ctx context.Context) error {
+	_, err := p.fetchPage(ctx, 1)
+	return err
+}
+
+// fetchPage requests one page of the Arbeitnow feed
+func (p *ArbeitnowProvider) fetchPage(ctx context.Context, page int) (*ArbeitnowResponse, error) {
+	baseURL := p.config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://www.arbeitnow.com/api/job-board-api"
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search URL: %w", err)
+	}
+	params := u.Query()
+	params.Set("page", strconv.Itoa(page))
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if userAgent, ok := p.config.Headers["User-Agent"]; ok {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Provider:   p.GetName(),
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("API request failed with status %d", resp.StatusCode),
+			Retryable:  resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+
+	var apiResp ArbeitnowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &apiResp, nil
+}
+
+// matches reports whether a job in the feed fits query. Any keyword may
+// match the title, tags or description; the location matches as a
+// substring, or "remote" matches the jobs that are remote.
+func (p *ArbeitnowProvider) matches(job ArbeitnowJob, query SearchQuery) bool {
+	if query.Remote && !job.Remote {
+		return false
+	}
+
+	if location := strings.ToLower(strings.TrimSpace(query.Location)); location != "" {
+		if location == "remote" {
+			if !job.Remote {
+				return false
+			}
+		} else if !strings.Contains(strings.ToLower(job.Location), location) {
+			return false
+		}
+	}
+
+	if query.Company != "" && !strings.Contains(strings.ToLower(job.CompanyName), strings.ToLower(query.Company)) {
+		return false
+	}
+
+	if query.JobType != "" {
+		found := false
+		for _, jobType := range job.JobTypes {
+			if strings.EqualFold(strings.ReplaceAll(jobType, " ", "-"), query.JobType) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if days := DatePostedDays(query.DatePosted); days > 0 && job.CreatedAt > 0 {
+		if time.Since(time.Unix(job.CreatedAt, 0)) > time.Duration(days)*24*time.Hour {
+			return false
+		}
+	}
+
+	if len(query.Keywords) == 0 {
+		return true
+	}
+	text := strings.ToLower(job.Title + " " + strings.Join(job.Tags, " ") + " " + job.Description)
+	for _, keyword := range query.Keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertJob converts an Arbeitnow job to our standard Job format
+func (p *ArbeitnowProvider) convertJob(arbeitnowJob ArbeitnowJob) models.Job {
+	location := arbeitnowJob.Location
+	if arbeitnowJob.Remote {
+		if location == "" {
+			location = "Remote"
+		} else {
+			location += " (Remote)"
+		}
+	}
+
+	job := models.Job{
+		ID:          "arbeitnow_" + arbeitnowJob.Slug,
+		Title:       arbeitnowJob.Title,
+		Company:     arbeitnowJob.CompanyName,
+		Location:    location,
+		Description: htmlText(arbeitnowJob.Description),
+		Source:      "Arbeitnow",
+		Link:        arbeitnowJob.URL,
+		ScrapedAt:   time.Now().UTC(),
+	}
+
+	for _, jobType := range arbeitnowJob.JobTypes {
+		if job.EmploymentType = models.NormalizeEmploymentType(jobType); job.EmploymentType != "" {
+			break
+		}
+	}
+	if arbeitnowJob.Remote {
+		job.RemoteType = models.RemoteFull
+	}
+
+	// Arbeitnow gives the time posted in Unix seconds
+	if arbeitnowJob.CreatedAt > 0 {
+		job.SetPosted(time.Unix(arbeitnowJob.CreatedAt, 0))
+	}
+
+	// Add keywords from the job title and description, and the job's tags
+	job.Keywords = extractKeywords(job.Title, job.Description)
+	for _, tag := range arbeitnowJob.Tags {
+		if tag = strings.ToLower(tag); !slices.Contains(job.Keywords, tag) {
+			job.Keywords = append(job.Keywords, tag)
+		}
+	}
+
+	return job
+}
+
+// htmlText returns the text of an HTML fragment, with runs of whitespace
+// collapsed
+func htmlText(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return fragment
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// Arbeitnow API response structures
+type ArbeitnowResponse struct {
+	Data  []ArbeitnowJob `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+type ArbeitnowJob struct {
+	Slug        string   `json:"slug"`
+	CompanyName string   `json:"company_name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Remote      bool     `json:"remote"`
+	URL         string   `json:"url"`
+	Tags        []string `json:"tags"`
+	JobTypes    []string `json:"job_types"`
+	Location    string   `json:"location"`
+	CreatedAt   int64    `json:"created_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"hire.ai/pkg/errs"
 	"hire.ai/pkg/models"
 )
 
@@ -37,6 +38,10 @@ type SearchQuery struct {
 	DatePosted string   `json:"date_posted,omitempty"` // 1d, 3d, 7d, 14d, 30d
 	Limit      int      `json:"limit"`
 	Offset     int      `json:"offset"`
+
+	// RadiusKm asks providers that support it for jobs within this
+	// distance of Location
+	RadiusKm float64 `json:"radius_km,omitempty"`
 }
 
 // Salary represents salary range for job search
@@ -72,13 +77,16 @@ type RateLimitConfig struct {
 	RequestsPerHour   int    `json:"requests_per_hour"`
 	RequestsPerDay    int    `json:"requests_per_day"`
 	CooldownPeriod    string `json:"cooldown_period"` // Duration string like "2s"
+	// RequestsPerMonth is the plan's monthly quota, shown against usage by
+	// the stats api command rather than enforced
+	RequestsPerMonth int `json:"requests_per_month,omitempty"`
 }
 
 // APIConfig represents configuration for an API provider
 type APIConfig struct {
 	Name        string            `json:"name"`
 	Enabled     bool              `json:"enabled"`
-	Provider    string            `json:"provider"` // usajobs, reed, jsearch, etc.
+	Provider    string            `json:"provider"` // usajobs, reed, jsearch, arbeitnow, jooble, themuse
 	BaseURL     string            `json:"base_url"`
 	APIKey      string            `json:"api_key"`
 	SecretKey   string            `json:"secret_key,omitempty"`
@@ -110,3 +118,10 @@ type APIError struct {
 func (e *APIError) Error() string {
 	return e.Message
 }
+
+// Is matches the cause the response status signals, such as errs.ErrAuth
+// for a 401
+func (e *APIError) Is(target error) bool {
+	cause := errs.APIStatus(e.StatusCode)
+	return cause != nil && target == cause
+}
@@ -0,0 +1,259 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// joobleRadii are the search radii in km Jooble accepts
+var joobleRadii = []int{4, 8, 16, 26, 40, 80}
+
+// JoobleProvider implements the JobAPIProvider interface for the Jooble API,
+// which aggregates job boards across Europe and beyond
+type JoobleProvider struct {
+	config APIConfig
+	client *http.Client
+}
+
+// NewJoobleProvider creates a new Jooble API provider
+func NewJoobleProvider(config APIConfig, timeout time.Duration) *JoobleProvider {
+	// The key is part of the URL path, where recordings must not keep it
+	transport.AddSecret(config.APIKey)
+	return &JoobleProvider{
+		config: config,
+		client: transport.NewClient(timeout),
+	}
+}
+
+// GetName returns the provider name
+func (p *JoobleProvider) GetName() string {
+	return "jooble"
+}
+
+// Search searches for jobs using the Jooble API
+func (p *JoobleProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Jooble provider not configured: %w", errs.ErrAuth)
+	}
+
+	// Jooble takes the API key as the last part of the URL path
+	baseURL := p.config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://jooble.org/api/"
+	}
+	apiURL := strings.TrimSuffix(baseURL, "/") + "/" + url.PathEscape(p.config.APIKey)
+
+	body, err := json.Marshal(p.buildSearchRequest(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search request: %w", err)
+	}
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		// The error would quote the URL, which holds the API key
+		return nil, fmt.Errorf("failed to create request: invalid base_url %q", baseURL)
+	}
+
+	// Add headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if userAgent, ok := p.config.Headers["User-Agent"]; ok {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	// Execute the request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Provider:   p.GetName(),
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("API request failed with status %d", resp.StatusCode),
+			Retryable:  resp.StatusCode >= 500,
+		}
+	}
+
+	// Parse the response
+	var apiResp JoobleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Convert to our standard format
+	jobs := p.convertJobs(apiResp.Jobs)
+
+	page := 1
+	if query.Limit > 0 {
+		page = query.Offset/query.Limit + 1
+	}
+	return &SearchResult{
+		Jobs:       jobs,
+		Total:      apiResp.TotalCount,
+		Page:       page,
+		PerPage:    query.Limit,
+		HasMore:    len(jobs) == query.Limit && apiResp.TotalCount > query.Offset+query.Limit,
+		Provider:   p.GetName(),
+		SearchedAt: time.Now(),
+	}, nil
+}
+
+// IsConfigured checks if the provider is properly configured
+func (p *JoobleProvider) IsConfigured() bool {
+	return p.config.Enabled && p.config.APIKey != ""
+}
+
+// GetRateLimit returns the rate limit information
+func (p *JoobleProvider) GetRateLimit() RateLimit {
+	// Parse the cooldown period from string to duration
+	cooldown, err := time.ParseDuration(p.config.RateLimit.CooldownPeriod)
+	if err != nil {
+		cooldown = 1 * time.Second // default
+	}
+
+	return RateLimit{
+		RequestsPerMinute: p.config.RateLimit.RequestsPerMinute,
+		RequestsPerHour:   p.config.RateLimit.RequestsPerHour,
+		RequestsPerDay:    p.config.RateLimit.RequestsPerDay,
+		CooldownPeriod:    cooldown,
+	}
+}
+
+// ValidateCredentials validates the API credentials
+func (p *JoobleProvider) ValidateCredentials(ctx context.Context) error {
+	// Test with a simple search
+	testQuery := SearchQuery{
+		Keywords: []string{"software"},
+		Location: "Berlin",
+		Limit:    1,
+		Offset:   0,
+	}
+
+	_, err := p.Search(ctx, testQuery)
+	return err
+}
+
+// buildSearchRequest builds the body of a Jooble search
+func (p *JoobleProvider) buildSearchRequest(query SearchQuery) JoobleRequest {
+	request := JoobleRequest{
+		Keywords: strings.Join(query.Keywords, " "),
+		Location: query.Location,
+		Page:     "1",
+	}
+	// Without a limit Jooble's own page size applies
+	if query.Limit > 0 {
+		request.ResultOnPage = strconv.Itoa(query.Limit)
+		request.Page = strconv.Itoa(query.Offset/query.Limit + 1)
+	}
+
+	// Jooble searches company names through the keywords
+	if query.Company != "" {
+		request.Keywords = strings.TrimSpace(request.Keywords + " " + query.Company)
+	}
+
+	// Jooble has no remote filter, but lists remote jobs under "Remote"
+	if query.Remote && request.Location == "" {
+		request.Location = "Remote"
+	}
+
+	// Add the smallest radius Jooble accepts that covers the one asked for
+	if query.Location != "" && query.RadiusKm > 0 {
+		radius := joobleRadii[len(joobleRadii)-1]
+		for _, km := range joobleRadii {
+			if float64(km) >= query.RadiusKm {
+				radius = km
+				break
+			}
+		}
+		request.Radius = strconv.Itoa(radius)
+	}
+
+	// Add salary filter
+	if query.Salary != nil && query.Salary.Min > 0 {
+		request.Salary = query.Salary.Min
+	}
+
+	// Add date posted filter
+	if days := DatePostedDays(query.DatePosted); days > 0 {
+		request.DateCreatedFrom = time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+
+	return request
+}
+
+// convertJobs converts Jooble API response to our standard Job format
+func (p *JoobleProvider) convertJobs(results []JoobleJob) []models.Job {
+	var jobs []models.Job
+
+	for _, joobleJob := range results {
+		job := models.Job{
+			ID:          fmt.Sprintf("jooble_%s", joobleJob.ID.String()),
+			Title:       htmlText(joobleJob.Title),
+			Company:     joobleJob.Company,
+			Location:    joobleJob.Location,
+			Description: htmlText(joobleJob.Snippet),
+			Source:      "Jooble",
+			Link:        joobleJob.Link,
+			ScrapedAt:   time.Now().UTC(),
+			Salary:      joobleJob.Salary,
+		}
+
+		job.EmploymentType = models.NormalizeEmploymentType(joobleJob.Type)
+
+		// Jooble gives the time a job was last updated, without a zone
+		if parsed, ok := models.ParseTimestamp(joobleJob.Updated, time.UTC); ok {
+			job.SetPosted(parsed)
+		}
+
+		// Add keywords from the job title and description
+		job.Keywords = extractKeywords(job.Title, job.Description)
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs
+}
+
+// Jooble API request and response structures
+type JoobleRequest struct {
+	Keywords        string `json:"keywords"`
+	Location        string `json:"location,omitempty"`
+	Radius          string `json:"radius,omitempty"`
+	Salary          int    `json:"salary,omitempty"`
+	DateCreatedFrom string `json:"datecreatedfrom,omitempty"`
+	Page            string `json:"page"`
+	ResultOnPage    string `json:"ResultOnPage,omitempty"`
+}
+
+type JoobleResponse struct {
+	TotalCount int         `json:"totalCount"`
+	Jobs       []JoobleJob `json:"jobs"`
+}
+
+type JoobleJob struct {
+	ID       json.Number `json:"id"`
+	Title    string      `json:"title"`
+	Location string      `json:"location"`
+	Snippet  string      `json:"snippet"`
+	Salary   string      `json:"salary"`
+	Source   string      `json:"source"`
+	Type     string      `json:"type"`
+	Link     string      `json:"link"`
+	Company  string      `json:"company"`
+	Updated  string      `json:"updated"`
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"hire.ai/pkg/errs"
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
 )
 
 // JSearchProvider implements the JobAPIProvider interface for JSearch API (RapidAPI)
@@ -23,9 +26,7 @@ type JSearchProvider struct {
 func NewJSearchProvider(config APIConfig, timeout time.Duration) *JSearchProvider {
 	return &JSearchProvider{
 		config: config,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client: transport.NewClient(timeout),
 	}
 }
 
@@ -37,7 +38,7 @@ func (p *JSearchProvider) GetName() string {
 // Search searches for jobs using the JSearch API
 func (p *JSearchProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
 	if !p.IsConfigured() {
-		return nil, fmt.Errorf("JSearch provider not configured")
+		return nil, fmt.Errorf("JSearch provider not configured: %w", errs.ErrAuth)
 	}
 
 	// Build the API URL
@@ -165,6 +166,9 @@ func (p *JSearchProvider) buildSearchURL(query SearchQuery) (string, error) {
 	if len(queryParts) > 0 {
 		params.Set("query", strings.Join(queryParts, " "))
 	}
+	if query.Location != "" && query.RadiusKm > 0 {
+		params.Set("radius", strconv.Itoa(int(math.Ceil(query.RadiusKm))))
+	}
 
 	// Add pagination
 	params.Set("num_pages", "1")
@@ -220,20 +224,23 @@ func (p *JSearchProvider) convertJobs(data []JSearchJob) []models.Job {
 			Description: jsJob.JobDescription,
 			Source:      "JSearch",
 			Link:        jsJob.JobApplyLink,
-			ScrapedAt:   time.Now(),
+			ScrapedAt:   time.Now().UTC(),
 			Salary:      p.formatSalary(jsJob),
 		}
 
-		// Parse date
-		if jsJob.JobPostedAtDatetimeUTC != "" {
-			if parsed, err := time.Parse(time.RFC3339, jsJob.JobPostedAtDatetimeUTC); err == nil {
-				job.ScrapedAt = parsed
-			}
+		if parsed, ok := models.ParseTimestamp(jsJob.JobPostedAtDatetimeUTC, time.UTC); ok {
+			job.SetPosted(parsed)
 		}
 
-		// Add employment type info
-		if jsJob.JobEmploymentType != "" {
-			job.Description = fmt.Sprintf("[%s] %s", jsJob.JobEmploymentType, job.Description)
+		if jsJob.EmployerWebsite != nil {
+			job.CompanyWebsite = *jsJob.EmployerWebsite
+		}
+		if jsJob.EmployerLogo != nil {
+			job.CompanyLogo = *jsJob.EmployerLogo
+		}
+		job.EmploymentType = models.NormalizeEmploymentType(jsJob.JobEmploymentType)
+		if jsJob.JobIsRemote {
+			job.RemoteType = models.RemoteFull
 		}
 
 		// Add keywords from the job title and description
@@ -0,0 +1,56 @@
+package providers
+
+import "hire.ai/pkg/models"
+
+// matchedPage is the window of matching jobs a search filtered on the
+// client returns
+type matchedPage struct {
+	jobs []models.Job
+	// matched counts the matching jobs read, which may run past the window
+	matched int
+	page    int
+	perPage int
+	hasMore bool
+}
+
+// pageMatches pages through a provider whose API cannot filter as query
+// asks, for providers that match jobs on the client. It calls fetch with
+// page indexes from 0 until more than Offset+Limit matching jobs are read,
+// fetch reports no more pages, or maxPages are read, and returns the Limit
+// jobs from Offset. A Limit of 0 means pageSize, the provider's own page
+// size, as it does for providers that page on the server.
+func pageMatches(query SearchQuery, pageSize, maxPages int, fetch func(page int) (matched []models.Job, more bool, err error)) (matchedPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = pageSize
+	}
+
+	var matched []models.Job
+	hasMore := false
+	for page := 0; page < maxPages; page++ {
+		jobs, more, err := fetch(page)
+		if err != nil {
+			return matchedPage{}, err
+		}
+		matched = append(matched, jobs...)
+		hasMore = more
+		if !hasMore || len(matched) > query.Offset+limit {
+			break
+		}
+	}
+
+	jobs := []models.Job{}
+	if query.Offset < len(matched) {
+		jobs = matched[query.Offset:]
+	}
+	if len(jobs) > limit {
+		jobs, hasMore = jobs[:limit], true
+	}
+	return matchedPage{
+		jobs:    jobs,
+		matched: len(matched),
+		page:    query.Offset/limit + 1,
+		perPage: limit,
+		hasMore: hasMore,
+	}, nil
+}
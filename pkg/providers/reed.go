@@ -4,15 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/geo"
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
 )
 
+// reedLocation is the zone Reed's posting dates are in
+var reedLocation = loadLocation("Europe/London")
+
 // ReedProvider implements the JobAPIProvider interface for Reed Jobs API
 type ReedProvider struct {
 	config APIConfig
@@ -23,9 +30,7 @@ type ReedProvider struct {
 func NewReedProvider(config APIConfig, timeout time.Duration) *ReedProvider {
 	return &ReedProvider{
 		config: config,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client: transport.NewClient(timeout),
 	}
 }
 
@@ -37,7 +42,7 @@ func (p *ReedProvider) GetName() string {
 // Search searches for jobs using the Reed API
 func (p *ReedProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
 	if !p.IsConfigured() {
-		return nil, fmt.Errorf("Reed provider not configured")
+		return nil, fmt.Errorf("Reed provider not configured: %w", errs.ErrAuth)
 	}
 
 	// Build the API URL
@@ -154,6 +159,9 @@ func (p *ReedProvider) buildSearchURL(query SearchQuery) (string, error) {
 	// Add location
 	if query.Location != "" {
 		params.Set("locationName", query.Location)
+		if query.RadiusKm > 0 {
+			params.Set("distanceFromLocation", strconv.Itoa(int(math.Ceil(geo.Miles(query.RadiusKm)))))
+		}
 	}
 
 	// Add remote work option
@@ -193,7 +201,7 @@ func (p *ReedProvider) buildSearchURL(query SearchQuery) (string, error) {
 
 	// Add date posted filter
 	if query.DatePosted != "" {
-		days := parseDatePosted(query.DatePosted)
+		days := DatePostedDays(query.DatePosted)
 		if days > 0 {
 			params.Set("postedByDays", strconv.Itoa(days))
 		}
@@ -216,14 +224,16 @@ func (p *ReedProvider) convertJobs(results []ReedJob) []models.Job {
 			Description: reedJob.JobDescription,
 			Source:      "Reed",
 			Link:        reedJob.JobURL,
-			ScrapedAt:   time.Now(),
+			ScrapedAt:   time.Now().UTC(),
 			Salary:      p.formatSalary(reedJob),
 		}
 
-		// Parse date
+		job.EmploymentType = models.NormalizeEmploymentType(reedJob.JobType)
+
+		// Reed gives the day posted, in UK time
 		if reedJob.Date != "" {
-			if parsed, err := time.Parse("02/01/2006", reedJob.Date); err == nil {
-				job.ScrapedAt = parsed
+			if parsed, err := time.ParseInLocation("02/01/2006", reedJob.Date, reedLocation); err == nil {
+				job.SetPosted(parsed)
 			}
 		}
 
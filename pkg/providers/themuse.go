@@ -0,0 +1,386 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// museMaxPages bounds how many pages of musePageSize jobs one search reads
+const museMaxPages = 10
+
+// musePageSize is how many jobs a page of results lists, and so the page
+// size of searches that set no limit
+const musePageSize = 20
+
+// museRemoteLocation is the location The Muse lists remote jobs under
+const museRemoteLocation = "Flexible / Remote"
+
+// museLevels maps SearchQuery.Experience values to The Muse's levels
+var museLevels = map[string][]string{
+	"internship": {"Internship"},
+	"intern":     {"Internship"},
+	"entry":      {"Entry Level"},
+	"junior":     {"Entry Level"},
+	"mid":        {"Mid Level"},
+	"senior":     {"Senior Level"},
+	"lead":       {"Senior Level", "management"},
+	"management": {"management"},
+}
+
+// museCategories maps keyword words to The Muse's job categories, which it
+// searches by in place of keywords
+var museCategories = map[string]string{
+	"software":    "Software Engineering",
+	"developer":   "Software Engineering",
+	"engineer":    "Software Engineering",
+	"engineering": "Software Engineering",
+	"programmer":  "Software Engineering",
+	"backend":     "Software Engineering",
+	"frontend":    "Software Engineering",
+	"fullstack":   "Software Engineering",
+	"devops":      "Software Engineering",
+	"data":        "Data and Analytics",
+	"analytics":   "Data and Analytics",
+	"analyst":     "Data and Analytics",
+	"scientist":   "Data Science",
+	"science":     "Data Science",
+	"design":      "Design and UX",
+	"designer":    "Design and UX",
+	"ux":          "Design and UX",
+	"product":     "Product Management",
+	"project":     "Project Management",
+	"it":          "IT",
+	"sales":       "Sales",
+	"marketing":   "Advertising and Marketing",
+	"accounting":  "Accounting and Finance",
+	"finance":     "Accounting and Finance",
+	"recruiter":   "Human Resources and Recruitment",
+	"recruiting":  "Human Resources and Recruitment",
+	"support":     "Customer Service",
+	"writer":      "Writing and Editing",
+	"editor":      "Writing and Editing",
+	"teacher":     "Education",
+	"nurse":       "Healthcare",
+}
+
+// TheMuseProvider implements the JobAPIProvider interface for The Muse API.
+// An API key is optional and only raises the rate limit. The Muse has no
+// keyword search, so keywords are mapped to its categories and those that
+// map to none are matched against the jobs returned.
+type TheMuseProvider struct {
+	config APIConfig
+	client *http.Client
+}
+
+// NewTheMuseProvider creates a new The Muse API provider
+func NewTheMuseProvider(config APIConfig, timeout time.Duration) *TheMuseProvider {
+	return &TheMuseProvider{
+		config: config,
+		client: transport.NewClient(timeout),
+	}
+}
+
+// GetName returns the provider name
+func (p *TheMuseProvider) GetName() string {
+	return "themuse"
+}
+
+// Search searches for jobs using The Muse API, reading pages until
+// Offset+Limit matching jobs are found or the results end
+func (p *TheMuseProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("The Muse provider not configured: %w", errs.ErrAuth)
+	}
+
+	params, unmapped := p.buildSearchParams(query)
+	filtered := len(unmapped) > 0 || query.Company != "" || p.filtersLocation(query)
+
+	var cutoff time.Time
+	if days := DatePostedDays(query.DatePosted); days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days)
+	}
+
+	total := 0
+	result, err := pageMatches(query, musePageSize, museMaxPages, func(page int) ([]models.Job, bool, error) {
+		apiResp, err := p.fetchPage(ctx, params, page)
+		if err != nil {
+			return nil, false, err
+		}
+		total = apiResp.Total
+
+		var matched []models.Job
+		for _, museJob := range apiResp.Results {
+			job := p.convertJob(museJob)
+			// Results come newest first, so the rest are older still
+			if !cutoff.IsZero() && job.PostedAt != nil && job.PostedAt.Before(cutoff) {
+				return matched, false, nil
+			}
+			if p.matches(museJob, query, unmapped) {
+				matched = append(matched, job)
+			}
+		}
+		return matched, page+1 < apiResp.PageCount, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The Muse's total counts jobs the filters here may drop
+	if filtered || !cutoff.IsZero() {
+		total = result.matched
+	}
+
+	return &SearchResult{
+		Jobs:       result.jobs,
+		Total:      total,
+		Page:       result.page,
+		PerPage:    result.perPage,
+		HasMore:    result.hasMore,
+		Provider:   p.GetName(),
+		SearchedAt: time.Now(),
+	}, nil
+}
+
+// IsConfigured checks if the provider is properly configured. The Muse works
+// without an API key, so enabling it is enough.
+func (p *TheMuseProvider) IsConfigured() bool {
+	return p.config.Enabled
+}
+
+// GetRateLimit returns the rate limit information
+func (p *TheMuseProvider) GetRateLimit() RateLimit {
+	// Parse the cooldown period from string to duration
+	cooldown, err := time.ParseDuration(p.config.RateLimit.CooldownPeriod)
+	if err != nil {
+		cooldown = 1 * time.Second // default
+	}
+
+	return RateLimit{
+		RequestsPerMinute: p.config.RateLimit.RequestsPerMinute,
+		RequestsPerHour:   p.config.RateLimit.RequestsPerHour,
+		RequestsPerDay:    p.config.RateLimit.RequestsPerDay,
+		CooldownPeriod:    cooldown,
+	}
+}
+
+// ValidateCredentials validates the API key, when one is set
+func (p *TheMuseProvider) ValidateCredentials(ctx context.Context) error {
+	_, err := p.fetchPage(ctx, url.Values{}, 0)
+	return err
+}
+
+// buildSearchParams maps query to The Muse's parameters, returning the
+// keywords that map to no category
+func (p *TheMuseProvider) buildSearchParams(query SearchQuery) (url.Values, []string) {
+	params := url.Values{}
+	params.Set("descending", "true")
+
+	// Map keywords to categories
+	var unmapped []string
+	categories := make(map[string]bool)
+	for _, keyword := range query.Keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			continue
+		}
+		mapped := false
+		for _, word := range strings.Fields(keyword) {
+			if category, ok := museCategories[word]; ok {
+				mapped = true
+				if !categories[category] {
+					categories[category] = true
+					params.Add("category", category)
+				}
+			}
+		}
+		if !mapped {
+			unmapped = append(unmapped, keyword)
+		}
+	}
+
+	// Map experience to levels
+	for _, experience := range strings.Split(strings.ToLower(query.Experience), ",") {
+		for _, level := range museLevels[strings.TrimSpace(experience)] {
+			params.Add("level", level)
+		}
+	}
+
+	// The Muse names locations "City, ST" or "City, Country" and matches
+	// them exactly; other locations are matched against the jobs returned
+	if strings.Contains(query.Location, ",") {
+		params.Add("location", strings.TrimSpace(query.Location))
+	}
+	if query.Remote || strings.EqualFold(strings.TrimSpace(query.Location), "remote") {
+		params.Add("location", museRemoteLocation)
+	}
+
+	return params, unmapped
+}
+
+// filtersLocation reports whether the query's location is matched against
+// the jobs returned rather than sent to The Muse
+func (p *TheMuseProvider) filtersLocation(query SearchQuery) bool {
+	location := strings.TrimSpace(query.Location)
+	return location != "" && !strings.Contains(location, ",") && !strings.EqualFold(location, "remote")
+}
+
+// fetchPage requests one page of results, counting pages from 0
+func (p *TheMuseProvider) fetchPage(ctx context.Context, params url.Values, page int) (*TheMuseResponse, error) {
+	baseURL := p.config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://www.themuse.com/api/public/jobs"
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search URL: %w", err)
+	}
+	values := url.Values{}
+	for key, list := range params {
+		values[key] = list
+	}
+	values.Set("page", strconv.Itoa(page))
+	if p.config.APIKey != "" {
+		values.Set("api_key", p.config.APIKey)
+	}
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if userAgent, ok := p.config.Headers["User-Agent"]; ok {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Provider:   p.GetName(),
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("API request failed with status %d", resp.StatusCode),
+			Retryable:  resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+
+	var apiResp TheMuseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &apiResp, nil
+}
+
+// matches reports whether a job fits the parts of query The Muse could not
+// search by: a location without a region, the company, and the keywords
+// that map to no category, any of which may match the name or contents
+func (p *TheMuseProvider) matches(job TheMuseJob, query SearchQuery, unmapped []string) bool {
+	if p.filtersLocation(query) {
+		location := strings.ToLower(strings.TrimSpace(query.Location))
+		found := false
+		for _, jobLocation := range job.Locations {
+			if strings.Contains(strings.ToLower(jobLocation.Name), location) ||
+				(query.Remote && jobLocation.Name == museRemoteLocation) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if query.Company != "" && !strings.Contains(strings.ToLower(job.Company.Name), strings.ToLower(query.Company)) {
+		return false
+	}
+
+	if len(unmapped) == 0 {
+		return true
+	}
+	text := strings.ToLower(job.Name + " " + job.Contents)
+	for _, keyword := range unmapped {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertJob converts a The Muse job to our standard Job format
+func (p *TheMuseProvider) convertJob(museJob TheMuseJob) models.Job {
+	var locations []string
+	for _, location := range museJob.Locations {
+		locations = append(locations, location.Name)
+	}
+
+	job := models.Job{
+		ID:          fmt.Sprintf("themuse_%d", museJob.ID),
+		Title:       museJob.Name,
+		Company:     museJob.Company.Name,
+		Location:    strings.Join(locations, "; "),
+		Description: htmlText(museJob.Contents),
+		Source:      "The Muse",
+		Link:        museJob.Refs.LandingPage,
+		ScrapedAt:   time.Now().UTC(),
+	}
+
+	if parsed, ok := models.ParseTimestamp(museJob.PublicationDate, time.UTC); ok {
+		job.SetPosted(parsed)
+	}
+	for _, level := range museJob.Levels {
+		if level.ShortName == "internship" {
+			job.EmploymentType = models.EmploymentIntern
+		}
+	}
+
+	// Add keywords from the job title and description
+	job.Keywords = extractKeywords(job.Title, job.Description)
+
+	return job
+}
+
+// The Muse API response structures
+type TheMuseResponse struct {
+	Page      int          `json:"page"`
+	PageCount int          `json:"page_count"`
+	Total     int          `json:"total"`
+	Results   []TheMuseJob `json:"results"`
+}
+
+type TheMuseJob struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Contents        string `json:"contents"`
+	PublicationDate string `json:"publication_date"`
+	Locations       []struct {
+		Name string `json:"name"`
+	} `json:"locations"`
+	Categories []struct {
+		Name string `json:"name"`
+	} `json:"categories"`
+	Levels []struct {
+		Name      string `json:"name"`
+		ShortName string `json:"short_name"`
+	} `json:"levels"`
+	Refs struct {
+		LandingPage string `json:"landing_page"`
+	} `json:"refs"`
+	Company struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"company"`
+}
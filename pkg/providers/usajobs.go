@@ -3,16 +3,24 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/geo"
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
 )
 
+// usaJobsLocation is the zone USAJobs dates are in
+var usaJobsLocation = loadLocation("America/New_York")
+
 // USAJobsProvider implements the JobAPIProvider interface for USAJobs API
 type USAJobsProvider struct {
 	config APIConfig
@@ -23,9 +31,7 @@ type USAJobsProvider struct {
 func NewUSAJobsProvider(config APIConfig, timeout time.Duration) *USAJobsProvider {
 	return &USAJobsProvider{
 		config: config,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client: transport.NewClient(timeout),
 	}
 }
 
@@ -37,7 +43,7 @@ func (p *USAJobsProvider) GetName() string {
 // Search searches for jobs using the USAJobs API
 func (p *USAJobsProvider) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
 	if !p.IsConfigured() {
-		return nil, fmt.Errorf("USAJobs provider not configured")
+		return nil, fmt.Errorf("USAJobs provider not configured: %w", errs.ErrAuth)
 	}
 
 	// Build the API URL
@@ -150,6 +156,9 @@ func (p *USAJobsProvider) buildSearchURL(query SearchQuery) (string, error) {
 	// Add location
 	if query.Location != "" {
 		params.Set("LocationName", query.Location)
+		if query.RadiusKm > 0 {
+			params.Set("Radius", strconv.Itoa(int(math.Ceil(geo.Miles(query.RadiusKm)))))
+		}
 	}
 
 	// Add remote work option
@@ -175,7 +184,7 @@ func (p *USAJobsProvider) buildSearchURL(query SearchQuery) (string, error) {
 
 	// Add date posted filter
 	if query.DatePosted != "" {
-		days := parseDatePosted(query.DatePosted)
+		days := DatePostedDays(query.DatePosted)
 		if days > 0 {
 			params.Set("DatePosted", strconv.Itoa(days))
 		}
@@ -198,10 +207,21 @@ func (p *USAJobsProvider) convertJobs(items []USAJobsItem) []models.Job {
 			Description: item.MatchedObjectDescriptor.UserArea.Details.JobSummary,
 			Source:      "USAJobs",
 			Link:        item.MatchedObjectDescriptor.PositionURI,
-			ScrapedAt:   time.Now(),
+			ScrapedAt:   time.Now().UTC(),
 			Salary:      p.formatSalary(item.MatchedObjectDescriptor),
 		}
 
+		for _, schedule := range item.MatchedObjectDescriptor.PositionSchedule {
+			if job.EmploymentType = models.NormalizeEmploymentType(schedule.Name); job.EmploymentType != "" {
+				break
+			}
+		}
+
+		// USAJobs dates carry no zone and are Eastern time
+		if parsed, ok := models.ParseTimestamp(item.MatchedObjectDescriptor.PublicationStartDate, usaJobsLocation); ok {
+			job.SetPosted(parsed)
+		}
+
 		// Add keywords from the job title and description
 		job.Keywords = extractKeywords(job.Title, job.Description)
 
@@ -235,8 +255,9 @@ func (p *USAJobsProvider) formatSalary(descriptor USAJobsDescriptor) string {
 	return ""
 }
 
-// parseDatePosted converts date posted string to days
-func parseDatePosted(datePosted string) int {
+// DatePostedDays converts a date posted window such as 7d to days, or 0
+// when it is not one providers understand
+func DatePostedDays(datePosted string) int {
 	switch strings.ToLower(datePosted) {
 	case "1d", "today":
 		return 1
@@ -253,6 +274,26 @@ func parseDatePosted(datePosted string) int {
 	}
 }
 
+// loadLocation looks up a provider's zone, falling back to UTC when the
+// zone database is missing
+func loadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// redactURL drops the URL from a failed request's error, for APIs that take
+// the API key in the URL
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
 // extractKeywords extracts keywords from title and description
 func extractKeywords(title, description string) []string {
 	// Simple keyword extraction - can be enhanced with NLP
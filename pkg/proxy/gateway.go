@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GatewayConfig is a rotating proxy service reached through a single
+// endpoint, where the username carries a session ID that pins the exit IP.
+// Each board keeps its own session for a run, or until it is blocked.
+type GatewayConfig struct {
+	// Provider picks the session format: brightdata, oxylabs, scraperapi,
+	// or custom
+	Provider string `json:"provider"`
+	// URL is the gateway endpoint, e.g. http://brd.superproxy.io:22225
+	URL string `json:"url"`
+	// Username is the account username. Custom providers mark where the
+	// session goes with {session}. It may reference environment variables.
+	Username string `json:"username,omitempty"`
+	// PasswordEnv names the environment variable holding the password or
+	// API key
+	PasswordEnv string `json:"passwordEnv"`
+}
+
+// sessionFormats place the session in the username for each provider
+var sessionFormats = map[string]string{
+	"brightdata": "{username}-session-{session}",
+	"oxylabs":    "{username}-sessid-{session}",
+	"scraperapi": "scraperapi.session_number={session}",
+	"custom":     "{username}",
+}
+
+// gateway hands out per-board sessions on one endpoint
+type gateway struct {
+	endpoint *url.URL
+	username string // with {session} to be filled in
+	password string
+	numeric  bool // session IDs must be numbers
+	sessions map[string]string
+}
+
+func newGateway(name string, config GatewayConfig) (*gateway, error) {
+	provider := strings.ToLower(config.Provider)
+	format, ok := sessionFormats[provider]
+	if !ok {
+		return nil, fmt.Errorf("proxy gateway %s: unknown provider %q", name, config.Provider)
+	}
+
+	endpointURL, err := expandEnv(config.URL, func(value string) string { return value })
+	if err != nil {
+		return nil, fmt.Errorf("proxy gateway %s: URL %w", name, err)
+	}
+	endpoint, err := checkProxyURL(endpointURL, config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("proxy gateway %s: %w", name, err)
+	}
+	endpoint.User = nil
+
+	username, err := expandEnv(config.Username, func(value string) string { return value })
+	if err != nil {
+		return nil, fmt.Errorf("proxy gateway %s: username %w", name, err)
+	}
+	username = strings.ReplaceAll(format, "{username}", username)
+	if !strings.Contains(username, "{session}") {
+		return nil, fmt.Errorf("proxy gateway %s: username must contain {session}", name)
+	}
+
+	if config.PasswordEnv == "" {
+		return nil, fmt.Errorf("proxy gateway %s: passwordEnv is required", name)
+	}
+	password, ok := os.LookupEnv(config.PasswordEnv)
+	if !ok {
+		return nil, fmt.Errorf("proxy gateway %s: password variable %s is not set", name, config.PasswordEnv)
+	}
+
+	return &gateway{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		numeric:  provider == "scraperapi",
+		sessions: make(map[string]string),
+	}, nil
+}
+
+// proxyFor returns the endpoint with key's session, starting one if needed
+func (g *gateway) proxyFor(key string) *url.URL {
+	session, ok := g.sessions[key]
+	if !ok {
+		session = g.newSession(key)
+	}
+	proxyURL := *g.endpoint
+	proxyURL.User = url.UserPassword(strings.ReplaceAll(g.username, "{session}", session), g.password)
+	return &proxyURL
+}
+
+// newSession replaces key's session, so its next requests leave from a
+// different IP
+func (g *gateway) newSession(key string) string {
+	var session string
+	if g.numeric {
+		n, _ := rand.Int(rand.Reader, big.NewInt(1_000_000_000))
+		session = n.String()
+	} else {
+		b := make([]byte, 6)
+		rand.Read(b)
+		session = hex.EncodeToString(b)
+	}
+	g.sessions[key] = session
+	return session
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxProxyListSize bounds the proxy list download
+const maxProxyListSize = 1 << 20
+
+// defaultRefreshInterval is how often the proxy list is fetched when the
+// config does not say
+const defaultRefreshInterval = 30 * time.Minute
+
+// RefreshInterval returns how often Refresh should run, or zero when no
+// proxy list URL is configured
+func (pm *ProxyManager) RefreshInterval() time.Duration {
+	if pm.config.ProxyListURL == "" {
+		return 0
+	}
+	if pm.config.RefreshInterval > 0 {
+		return time.Duration(pm.config.RefreshInterval) * time.Minute
+	}
+	return defaultRefreshInterval
+}
+
+// ListHost names the proxy list's host for logs, leaving out any token in
+// its path or query
+func (pm *ProxyManager) ListHost() string {
+	listURL, err := url.Parse(pm.config.ProxyListURL)
+	if err != nil {
+		return "proxy list"
+	}
+	return listURL.Host
+}
+
+// Refresh fetches the proxy list and swaps it into the pool alongside the
+// configured proxies, returning the pool size. The old pool is kept when
+// the list cannot be fetched or is empty.
+func (pm *ProxyManager) Refresh(ctx context.Context) (int, error) {
+	if pm.config.ProxyListURL == "" {
+		return 0, fmt.Errorf("no proxy list URL configured")
+	}
+	listURL, err := expandEnv(pm.config.ProxyListURL, func(value string) string { return value })
+	if err != nil {
+		return 0, fmt.Errorf("proxy list URL %w", err)
+	}
+
+	listed, err := pm.fetchList(ctx, listURL)
+	if err != nil {
+		return 0, err
+	}
+	if len(listed) == 0 {
+		return 0, fmt.Errorf("proxy list from %s is empty", pm.ListHost())
+	}
+
+	proxies := shuffled(append(append([]*url.URL(nil), pm.static...), listed...))
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	// Stay on the current proxy if it survived the refresh
+	current := ""
+	if proxy := pm.pool.current(); proxy != nil {
+		current = proxy.String()
+	}
+	pm.pool = &pool{proxies: proxies}
+	for i, proxy := range proxies {
+		if proxy.String() == current {
+			pm.pool.currentIndex = i
+			break
+		}
+	}
+	return len(proxies), nil
+}
+
+// fetchList downloads and parses the proxy list. Lines hold proxy URLs or
+// bare host:port pairs, taken as HTTP proxies; blank lines and lines
+// starting with # are skipped, as are entries that do not parse.
+func (pm *ProxyManager) fetchList(ctx context.Context, listURL string) ([]*url.URL, error) {
+	timeout := time.Duration(pm.config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy list URL for %s", pm.ListHost())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch proxy list from %s: %w", pm.ListHost(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy list from %s returned status %d", pm.ListHost(), resp.StatusCode)
+	}
+
+	var proxies []*url.URL
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxProxyListSize))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.Contains(line, "://") {
+			line = "http://" + line
+		}
+		// Listed proxies are not expanded, so a list cannot read the environment
+		proxyURL, err := checkProxyURL(line, "from proxy list")
+		if err != nil || seen[proxyURL.String()] {
+			continue
+		}
+		seen[proxyURL.String()] = true
+		if proxyURL.User == nil {
+			proxyURL.User = pm.credentials
+		}
+		proxies = append(proxies, proxyURL)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read proxy list from %s: %w", pm.ListHost(), err)
+	}
+	return proxies, nil
+}
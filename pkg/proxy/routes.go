@@ -0,0 +1,234 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Direct is the route that bypasses proxies
+const Direct = "direct"
+
+// Route sends requests for some boards or domains through a proxy pool or
+// directly, e.g. proxies only for the boards that block scrapers
+type Route struct {
+	// Boards are job board names, matched case-insensitively
+	Boards []string `json:"boards,omitempty"`
+	// Domains match their host and its subdomains, so indeed.com matches
+	// www.indeed.com
+	Domains []string `json:"domains,omitempty"`
+	// Pool is "direct", a name from Pools or Gateways, "tor" when Tor is
+	// configured, or empty for the default pool
+	Pool string `json:"pool"`
+}
+
+// route is a parsed Route
+type route struct {
+	boards  map[string]bool
+	domains []string
+	pool    string
+}
+
+func (r route) matches(board, host string) bool {
+	if r.boards[strings.ToLower(board)] {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range r.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRoutes normalizes the configured routes, checking that the pools they
+// name exist
+func (pm *ProxyManager) parseRoutes(config ProxyConfig) ([]route, error) {
+	if err := pm.checkPool(config.DefaultRoute); err != nil {
+		return nil, fmt.Errorf("proxy default route: %w", err)
+	}
+
+	routes := make([]route, 0, len(config.Routes))
+	for i, r := range config.Routes {
+		if err := pm.checkPool(r.Pool); err != nil {
+			return nil, fmt.Errorf("proxy route %d: %w", i+1, err)
+		}
+		if len(r.Boards) == 0 && len(r.Domains) == 0 {
+			return nil, fmt.Errorf("proxy route %d matches no boards or domains", i+1)
+		}
+		parsed := route{boards: make(map[string]bool, len(r.Boards)), pool: r.Pool}
+		for _, board := range r.Boards {
+			parsed.boards[strings.ToLower(board)] = true
+		}
+		for _, domain := range r.Domains {
+			domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "*."))
+			if domain != "" {
+				parsed.domains = append(parsed.domains, domain)
+			}
+		}
+		routes = append(routes, parsed)
+	}
+	return routes, nil
+}
+
+func (pm *ProxyManager) checkPool(name string) error {
+	if name == "" || name == Direct || pm.pools[name] != nil || pm.gateways[name] != nil || (name == TorPool && pm.tor != nil) {
+		return nil
+	}
+	return fmt.Errorf("unknown proxy pool %q", name)
+}
+
+// route returns the pool name for a request to host while scraping board
+func (pm *ProxyManager) route(board, host string) string {
+	for _, r := range pm.routes {
+		if r.matches(board, host) {
+			return r.pool
+		}
+	}
+	return pm.config.DefaultRoute
+}
+
+// ProxyFor returns the proxy for a request to host while scraping board,
+// following the routes, or nil when the request should go direct. Each
+// call counts as a request towards rotating the chosen pool, unless the
+// manager is sticky; gateways keep one session per board.
+func (pm *ProxyManager) ProxyFor(board, host string) *url.URL {
+	if !pm.config.Enabled {
+		return nil
+	}
+	poolName := pm.route(board, host)
+	if poolName == Direct {
+		return nil
+	}
+	if poolName == TorPool && pm.tor != nil {
+		return pm.tor.proxy()
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if gw := pm.gateways[poolName]; gw != nil {
+		return gw.proxyFor(sessionKey(board, host))
+	}
+	p := pm.pool
+	if poolName != "" {
+		p = pm.pools[poolName]
+	}
+	var proxy *url.URL
+	if pm.config.Sticky {
+		proxy = pm.pin(p, poolName, board, host)
+	} else {
+		proxy = p.use(pm.config.RotateEvery)
+	}
+	if proxy == nil {
+		return nil
+	}
+	proxyURL := *proxy
+	return &proxyURL
+}
+
+// ReportFailure records that a request to host while scraping board failed
+// through proxyURL, because the site blocked it or, when blocked is false,
+// the proxy could not be reached. Gateways move the board to a new session
+// and Tor builds new circuits; pools demote the proxy to the back of the
+// rotation and drop it after MaxFailures failures in a row, keeping at
+// least one. It returns false when there is no other exit to retry through.
+func (pm *ProxyManager) ReportFailure(board, host, proxyURL string, blocked bool) bool {
+	if !pm.config.Enabled || proxyURL == "" {
+		return false
+	}
+	poolName := pm.route(board, host)
+	failure := func(u *Usage) {
+		if blocked {
+			u.Blocks++
+		} else {
+			u.Failures++
+		}
+	}
+
+	// NEWNYM may wait on Tor's rate limit, so it runs without the mutex
+	if poolName == TorPool && pm.tor != nil {
+		pm.mutex.Lock()
+		pm.recordUsage(proxyURL, failure)
+		pm.mutex.Unlock()
+		return pm.tor.newCircuit() == nil
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.recordUsage(proxyURL, failure)
+
+	if gw := pm.gateways[poolName]; gw != nil {
+		gw.newSession(sessionKey(board, host))
+		return true
+	}
+
+	// A sticky board moves on to a new proxy and user agent together
+	if pm.config.Sticky {
+		delete(pm.pinned, pinKey(poolName, board, host))
+		delete(pm.agents, sessionKey(board, host))
+	}
+
+	pm.failures[proxyURL]++
+	drop := pm.failures[proxyURL] >= pm.maxFailures()
+	failed := func(proxy *url.URL) bool { return proxy.String() == proxyURL }
+	retry := false
+	for _, p := range append([]*pool{pm.pool}, pm.poolList()...) {
+		if !p.contains(proxyURL) || len(p.proxies) <= 1 {
+			continue
+		}
+		retry = true
+		if drop {
+			p.remove(failed)
+		} else {
+			p.demote(proxyURL)
+		}
+	}
+	if drop {
+		delete(pm.failures, proxyURL)
+	}
+	return retry
+}
+
+// ReportSuccess records a request that got through proxyURL and the bytes
+// it received, clearing the proxy's failures
+func (pm *ProxyManager) ReportSuccess(proxyURL string, bytes int64) {
+	if proxyURL == "" {
+		return
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	delete(pm.failures, proxyURL)
+	pm.recordUsage(proxyURL, func(u *Usage) {
+		u.Successes++
+		u.Bytes += bytes
+	})
+}
+
+func (pm *ProxyManager) maxFailures() int {
+	if pm.config.MaxFailures > 0 {
+		return pm.config.MaxFailures
+	}
+	return defaultMaxFailures
+}
+
+func (pm *ProxyManager) poolList() []*pool {
+	pools := make([]*pool, 0, len(pm.pools))
+	for _, p := range pm.pools {
+		pools = append(pools, p)
+	}
+	return pools
+}
+
+// sessionKey groups requests into gateway sessions by board, or by host
+// outside a board scrape
+func sessionKey(board, host string) string {
+	if board != "" {
+		return strings.ToLower(board)
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+)
+
+// Usage is what requests through one proxy have done. Proxies are told
+// apart by scheme and host, so credentials and gateway sessions never
+// reach the stats file.
+type Usage struct {
+	Proxy     string `json:"proxy"`
+	Requests  int    `json:"requests"`
+	Successes int    `json:"successes"`
+	// Blocks are requests the site refused; Failures are requests that
+	// never got through the proxy
+	Blocks   int       `json:"blocks"`
+	Failures int       `json:"failures"`
+	Bytes    int64     `json:"bytes"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// SuccessRate is the share of requests that succeeded
+func (u Usage) SuccessRate() float64 {
+	if u.Requests == 0 {
+		return 0
+	}
+	return float64(u.Successes) / float64(u.Requests)
+}
+
+// usageKey names a proxy in the stats
+func usageKey(proxyURL string) string {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return "invalid"
+	}
+	return (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host}).String()
+}
+
+// recordUsage counts a request through proxyURL; the caller holds the mutex
+func (pm *ProxyManager) recordUsage(proxyURL string, record func(*Usage)) {
+	key := usageKey(proxyURL)
+	usage := pm.usage[key]
+	if usage == nil {
+		usage = &Usage{Proxy: key}
+		pm.usage[key] = usage
+	}
+	usage.Requests++
+	usage.LastUsed = time.Now()
+	record(usage)
+}
+
+// Usage returns the usage of every proxy used, including totals loaded
+// with TrackUsage
+func (pm *ProxyManager) Usage() []Usage {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	usage := make([]Usage, 0, len(pm.usage))
+	for _, u := range pm.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Proxy < usage[j].Proxy })
+	return usage
+}
+
+// TrackUsage loads the usage totals saved at path, which SaveUsage adds
+// this run's requests to
+func (pm *ProxyManager) TrackUsage(path string) error {
+	usage, err := ReadUsage(path)
+	if err != nil {
+		return err
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.usagePath = path
+	for i := range usage {
+		u := usage[i]
+		if current := pm.usage[u.Proxy]; current != nil {
+			u.Requests += current.Requests
+			u.Successes += current.Successes
+			u.Blocks += current.Blocks
+			u.Failures += current.Failures
+			u.Bytes += current.Bytes
+			if current.LastUsed.After(u.LastUsed) {
+				u.LastUsed = current.LastUsed
+			}
+		}
+		pm.usage[u.Proxy] = &u
+	}
+	return nil
+}
+
+// SaveUsage writes the usage totals to the file given to TrackUsage
+func (pm *ProxyManager) SaveUsage() error {
+	pm.mutex.RLock()
+	path := pm.usagePath
+	pm.mutex.RUnlock()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(pm.Usage(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ReadUsage reads saved usage totals; a missing file has none
+func ReadUsage(path string) ([]Usage, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var usage []Usage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
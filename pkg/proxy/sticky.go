@@ -0,0 +1,76 @@
+package proxy
+
+import "net/url"
+
+// StartRun begins a scrape run: sticky boards are pinned afresh, gateway
+// sessions restart and Tor builds new circuits, so each run leaves from new
+// IPs. It fails only when Tor refuses new circuits.
+func (pm *ProxyManager) StartRun() error {
+	pm.resetRun()
+	if pm.tor != nil && pm.config.Enabled {
+		return pm.tor.newCircuit()
+	}
+	return nil
+}
+
+func (pm *ProxyManager) resetRun() {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.pinned = make(map[string]*url.URL)
+	pm.agents = make(map[string]string)
+	// Each run starts pinning one proxy further on, so boards trade
+	// proxies between runs
+	if pm.config.Sticky {
+		for _, p := range append([]*pool{pm.pool}, pm.poolList()...) {
+			p.runs++
+			if len(p.proxies) > 0 {
+				p.currentIndex, p.requestCount = p.runs%len(p.proxies), 0
+			}
+		}
+	}
+	for _, gw := range pm.gateways {
+		gw.sessions = make(map[string]string)
+	}
+}
+
+// UserAgentFor returns the user agent to scrape board with: the board's
+// pinned one when sticky, otherwise a random one
+func (pm *ProxyManager) UserAgentFor(board string) string {
+	if !pm.config.Sticky {
+		return pm.GetRandomUserAgent()
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	key := sessionKey(board, "")
+	agent, ok := pm.agents[key]
+	if !ok {
+		agent = pm.GetRandomUserAgent()
+		pm.agents[key] = agent
+	}
+	return agent
+}
+
+// pin returns board's proxy from p for this run, handing the pool's next
+// proxy to a board that has none so boards spread across the pool. The
+// caller holds the mutex.
+func (pm *ProxyManager) pin(p *pool, poolName, board, host string) *url.URL {
+	key := pinKey(poolName, board, host)
+	if proxy := pm.pinned[key]; proxy != nil && p.contains(proxy.String()) {
+		return proxy
+	}
+	if len(p.proxies) == 0 {
+		return nil
+	}
+
+	proxy := p.current()
+	p.rotate()
+	pm.pinned[key] = proxy
+	return proxy
+}
+
+func pinKey(poolName, board, host string) string {
+	return poolName + "/" + sessionKey(board, host)
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TorPool is the route name for sending requests through Tor
+const TorPool = "tor"
+
+// newnymInterval is how often Tor honours NEWNYM; sooner requests are
+// delayed by Tor, so the manager waits itself before retrying
+const newnymInterval = 10 * time.Second
+
+// TorConfig sends requests through a local Tor client's SOCKS port and asks
+// its control port for new circuits (NEWNYM) at the start of each run and
+// when a site blocks the current exit. It suits low-volume, polite
+// scraping; Tor exits are slow and often blocked by the larger boards.
+type TorConfig struct {
+	// SocksAddr defaults to 127.0.0.1:9050
+	SocksAddr string `json:"socksAddr,omitempty"`
+	// ControlAddr defaults to 127.0.0.1:9051
+	ControlAddr string `json:"controlAddr,omitempty"`
+	// ControlPasswordEnv names the environment variable holding the
+	// control port password (HashedControlPassword in torrc)
+	ControlPasswordEnv string `json:"controlPasswordEnv,omitempty"`
+	// CookieFile is Tor's control auth cookie, for CookieAuthentication;
+	// with neither password nor cookie the control port must need no auth
+	CookieFile string `json:"cookieFile,omitempty"`
+}
+
+// tor is a Tor client used as a proxy
+type tor struct {
+	socks       *url.URL
+	controlAddr string
+	auth        string // AUTHENTICATE argument
+
+	mutex      sync.Mutex
+	lastNewnym time.Time
+}
+
+func newTor(config TorConfig) (*tor, error) {
+	socksAddr := config.SocksAddr
+	if socksAddr == "" {
+		socksAddr = "127.0.0.1:9050"
+	}
+	controlAddr := config.ControlAddr
+	if controlAddr == "" {
+		controlAddr = "127.0.0.1:9051"
+	}
+
+	t := &tor{
+		socks:       &url.URL{Scheme: "socks5", Host: socksAddr},
+		controlAddr: controlAddr,
+	}
+	switch {
+	case config.ControlPasswordEnv != "":
+		password, ok := os.LookupEnv(config.ControlPasswordEnv)
+		if !ok {
+			return nil, fmt.Errorf("tor control password variable %s is not set", config.ControlPasswordEnv)
+		}
+		t.auth = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(password) + `"`
+	case config.CookieFile != "":
+		cookie, err := os.ReadFile(config.CookieFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tor control cookie: %w", err)
+		}
+		t.auth = hex.EncodeToString(cookie)
+	}
+	return t, nil
+}
+
+// proxy returns the SOCKS proxy URL
+func (t *tor) proxy() *url.URL {
+	proxyURL := *t.socks
+	return &proxyURL
+}
+
+// newCircuit signals NEWNYM so new connections use fresh circuits and, most
+// likely, a different exit
+func (t *tor) newCircuit() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if wait := newnymInterval - time.Since(t.lastNewnym); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	conn, err := net.DialTimeout("tcp", t.controlAddr, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to reach tor control port: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	command := func(line string) error {
+		if _, err := fmt.Fprintf(conn, "%s\r\n", line); err != nil {
+			return err
+		}
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(reply, "250") {
+			return fmt.Errorf("tor refused %s: %s", strings.Fields(line)[0], strings.TrimSpace(reply))
+		}
+		return nil
+	}
+
+	authenticate := "AUTHENTICATE"
+	if t.auth != "" {
+		authenticate += " " + t.auth
+	}
+	if err := command(authenticate); err != nil {
+		return err
+	}
+	if err := command("SIGNAL NEWNYM"); err != nil {
+		return err
+	}
+	fmt.Fprintf(conn, "QUIT\r\n")
+	t.lastNewnym = time.Now()
+	return nil
+}
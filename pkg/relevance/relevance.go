@@ -0,0 +1,133 @@
+// Package relevance scores how well jobs match the keywords of a search.
+package relevance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+)
+
+// Scorers New can build
+const (
+	ScorerTFIDF   = "tfidf"
+	ScorerKeyword = "keyword"
+)
+
+// Config is the relevance section of GlobalSettings
+type Config struct {
+	// Scorer is tfidf, the default, or keyword for the share of keywords a
+	// job mentions, a title mention counting twice
+	Scorer string `json:"scorer,omitempty"`
+	// Weights weigh a keyword found in each field of a job
+	Weights *Weights `json:"weights,omitempty"`
+	// RecencyHalfLife is the posting age, e.g. "720h", at which the recent
+	// share of a score has halved. Defaults to 30 days; "0" turns decay off.
+	RecencyHalfLife string `json:"recencyHalfLife,omitempty"`
+	// ExcludedTerms lower the score of every job that mentions them, by
+	// ExcludedPenalty of the score per term. The penalty defaults to 0.5.
+	ExcludedTerms   []string `json:"excludedTerms,omitempty"`
+	ExcludedPenalty float64  `json:"excludedPenalty,omitempty"`
+}
+
+// Weights are the field weights of the tfidf scorer. A keyword found once
+// in a field weighing as much as the description scores 1 of at most 2.
+type Weights struct {
+	Title       float64 `json:"title"`
+	Keywords    float64 `json:"keywords"`
+	Description float64 `json:"description"`
+}
+
+// DefaultWeights rank a title mention above a listed skill above a
+// description mention
+var DefaultWeights = Weights{Title: 3, Keywords: 2, Description: 1}
+
+const (
+	defaultHalfLife        = 30 * 24 * time.Hour
+	defaultExcludedPenalty = 0.5
+)
+
+// Query is what a job is scored against
+type Query struct {
+	Keywords []string
+	// Excluded adds to the configured ExcludedTerms
+	Excluded []string
+}
+
+// Scorer rates how well a job matches a query. Scores run from 0 to about
+// 2, the scale notification minRelevance settings are given in.
+type Scorer interface {
+	Score(job *models.Job, query Query) float64
+	// Index adds jobs to the corpus scores are weighed against, once each
+	Index(jobs []models.Job)
+}
+
+// CorpusFunc loads the stored jobs a scorer's corpus starts from
+type CorpusFunc func(ctx context.Context) ([]models.Job, error)
+
+// New validates config and returns its scorer; a nil config gives the tfidf
+// scorer with default settings. The stored jobs are loaded with corpus when
+// the first job is scored.
+func New(config *Config, corpus CorpusFunc, logger *logrus.Logger) (Scorer, error) {
+	c := Config{}
+	if config != nil {
+		c = *config
+	}
+
+	switch strings.ToLower(c.Scorer) {
+	case ScorerKeyword:
+		return Keyword{}, nil
+	case "", ScorerTFIDF:
+	default:
+		return nil, fmt.Errorf("unknown relevance scorer %q (use %s or %s)", c.Scorer, ScorerTFIDF, ScorerKeyword)
+	}
+
+	weights := DefaultWeights
+	if c.Weights != nil {
+		weights = *c.Weights
+	}
+	if weights.Title < 0 || weights.Keywords < 0 || weights.Description <= 0 {
+		return nil, fmt.Errorf("relevance weights must not be negative and the description weight must be positive")
+	}
+
+	halfLife := defaultHalfLife
+	if c.RecencyHalfLife != "" {
+		parsed, err := time.ParseDuration(c.RecencyHalfLife)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid relevance recencyHalfLife %q", c.RecencyHalfLife)
+		}
+		halfLife = parsed
+	}
+
+	penalty := c.ExcludedPenalty
+	if penalty == 0 {
+		penalty = defaultExcludedPenalty
+	}
+	if penalty < 0 || penalty > 1 {
+		return nil, fmt.Errorf("relevance excludedPenalty must be between 0 and 1")
+	}
+
+	return &TFIDF{
+		weights:  weights,
+		halfLife: halfLife,
+		excluded: c.ExcludedTerms,
+		penalty:  penalty,
+		load:     corpus,
+		logger:   logger,
+		corpus:   newCorpus(),
+	}, nil
+}
+
+// Keyword is the share of the query's keywords a job's title or
+// description mentions, a title mention counting twice
+type Keyword struct{}
+
+func (Keyword) Score(job *models.Job, query Query) float64 {
+	return job.CalculateRelevance(query.Keywords)
+}
+
+func (Keyword) Index(jobs []models.Job) {}
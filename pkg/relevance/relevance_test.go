@@ -0,0 +1,48 @@
+package relevance
+
+import (
+	"fmt"
+	"testing"
+
+	"hire.ai/pkg/models"
+)
+
+// scoredJobs makes n postings mixing a few titles and skills in the fields
+// scorers read
+func scoredJobs(n int) []models.Job {
+	titles := []string{"Senior Go Developer", "Backend Engineer", "DevOps Engineer", "Frontend Developer", "Data Engineer"}
+	skills := []string{"golang", "kubernetes", "terraform", "react", "python", "postgres", "aws"}
+	jobs := make([]models.Job, n)
+	for i := range jobs {
+		jobs[i] = models.Job{
+			ID:          fmt.Sprintf("job-%d", i),
+			Title:       titles[i%len(titles)],
+			Description: fmt.Sprintf("We build services in %s and run them on %s.", skills[i%len(skills)], skills[(i+1)%len(skills)]),
+			Keywords:    []string{skills[i%len(skills)], skills[(i+2)%len(skills)]},
+		}
+	}
+	return jobs
+}
+
+func benchmarkScorer(b *testing.B, scorer Scorer) {
+	jobs := scoredJobs(10000)
+	scorer.Index(jobs)
+	query := Query{Keywords: []string{"golang", "kubernetes", "backend engineer"}, Excluded: []string{"php"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scorer.Score(&jobs[i%len(jobs)], query)
+	}
+}
+
+func BenchmarkTFIDFScore(b *testing.B) {
+	scorer, err := New(nil, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkScorer(b, scorer)
+}
+
+func BenchmarkKeywordScore(b *testing.B) {
+	benchmarkScorer(b, Keyword{})
+}
@@ -0,0 +1,204 @@
+package relevance
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+)
+
+// corpusTimeout bounds loading the stored jobs for the corpus
+const corpusTimeout = time.Minute
+
+// TFIDF scores a job by the keywords it mentions, weighed by the field they
+// are in and by how rare they are among the indexed jobs, so a search for
+// "golang kubernetes" ranks a Go posting above one of the many that only
+// mention Kubernetes. Older postings keep at least half their score, and
+// each excluded term a posting mentions takes a share of it.
+type TFIDF struct {
+	weights  Weights
+	halfLife time.Duration
+	excluded []string
+	penalty  float64
+	load     CorpusFunc
+	logger   *logrus.Logger
+
+	loadOnce sync.Once
+	corpus   *corpus
+}
+
+// corpus holds the document frequency of every token among the indexed jobs
+type corpus struct {
+	mutex sync.RWMutex
+	ids   map[string]bool
+	docs  int
+	df    map[string]int
+}
+
+func newCorpus() *corpus {
+	return &corpus{ids: make(map[string]bool), df: make(map[string]int)}
+}
+
+func (c *corpus) add(jobs []models.Job) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := range jobs {
+		job := &jobs[i]
+		if job.ID != "" {
+			if c.ids[job.ID] {
+				continue
+			}
+			c.ids[job.ID] = true
+		}
+		c.docs++
+		seen := make(map[string]bool)
+		for _, text := range append([]string{job.Title, job.Description}, job.Keywords...) {
+			for _, token := range tokenize(text) {
+				if !seen[token] {
+					seen[token] = true
+					c.df[token]++
+				}
+			}
+		}
+	}
+}
+
+// idf is the inverse document frequency of a keyword, smoothed so it is
+// defined for an empty corpus. A phrase counts as often as its rarest
+// token, which overstates it a little.
+func (c *corpus) idf(tokens []string) float64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	df := c.docs
+	for _, token := range tokens {
+		df = min(df, c.df[token])
+	}
+	return math.Log(float64(c.docs+1)/float64(df+1)) + 1
+}
+
+func (t *TFIDF) Index(jobs []models.Job) {
+	t.loadCorpus()
+	t.corpus.add(jobs)
+}
+
+// loadCorpus indexes the stored jobs the first time it is called. A failed
+// load is logged and scoring goes on with the jobs indexed later.
+func (t *TFIDF) loadCorpus() {
+	t.loadOnce.Do(func() {
+		if t.load == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), corpusTimeout)
+		defer cancel()
+		jobs, err := t.load(ctx)
+		if err != nil {
+			t.logger.Warnf("Scoring relevance without the stored jobs: %v", err)
+			return
+		}
+		t.corpus.add(jobs)
+	})
+}
+
+func (t *TFIDF) Score(job *models.Job, query Query) float64 {
+	t.loadCorpus()
+
+	keywordSegments := make([][]string, len(job.Keywords))
+	for i, keyword := range job.Keywords {
+		keywordSegments[i] = tokenize(keyword)
+	}
+	fields := []struct {
+		segments [][]string
+		weight   float64
+	}{
+		{[][]string{tokenize(job.Title)}, t.weights.Title},
+		{keywordSegments, t.weights.Keywords},
+		{[][]string{tokenize(job.Description)}, t.weights.Description},
+	}
+
+	// Each keyword scores 2(1 - 2^-x), where x adds up its sublinear term
+	// frequency in each field relative to the description's weight, and
+	// the keywords' scores are averaged weighing each by its idf
+	var total, idfs float64
+	seen := make(map[string]bool)
+	for _, keyword := range query.Keywords {
+		tokens := tokenize(keyword)
+		key := strings.Join(tokens, " ")
+		if len(tokens) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var x float64
+		for _, f := range fields {
+			if tf := occurrences(f.segments, tokens); tf > 0 {
+				x += f.weight / t.weights.Description * (1 + math.Log(float64(tf)))
+			}
+		}
+		idf := t.corpus.idf(tokens)
+		total += idf * 2 * (1 - math.Exp2(-x))
+		idfs += idf
+	}
+	if idfs == 0 {
+		return 0
+	}
+	score := total / idfs
+
+	if t.halfLife > 0 && !job.PostedTime().IsZero() {
+		if age := time.Since(job.PostedTime()); age > 0 {
+			score *= 0.5 + 0.5*math.Exp2(-float64(age)/float64(t.halfLife))
+		}
+	}
+
+	text := [][]string{fields[0].segments[0], fields[2].segments[0]}
+	excluded := make(map[string]bool)
+	for _, term := range append(append([]string(nil), t.excluded...), query.Excluded...) {
+		tokens := tokenize(term)
+		key := strings.Join(tokens, " ")
+		if len(tokens) == 0 || excluded[key] {
+			continue
+		}
+		excluded[key] = true
+		if occurrences(text, tokens) > 0 {
+			score *= 1 - t.penalty
+		}
+	}
+	return score
+}
+
+// occurrences counts the times phrase appears in the segments
+func occurrences(segments [][]string, phrase []string) int {
+	count := 0
+	for _, tokens := range segments {
+	next:
+		for i := 0; i+len(phrase) <= len(tokens); i++ {
+			for j, token := range phrase {
+				if tokens[i+j] != token {
+					continue next
+				}
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// tokenize lowercases text and splits it into words, keeping the +, # and
+// dots of names such as c++, c# and node.js
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.'
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		if word = strings.Trim(word, "."); word != "" {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
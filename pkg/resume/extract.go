@@ -0,0 +1,93 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractText returns the plain text of a resume file. PDF, DOCX and plain
+// text (.txt, .md) are supported; the format is chosen by file extension.
+func ExtractText(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read resume: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		return extractPDF(data)
+	case ".docx":
+		return extractDOCX(data)
+	case ".txt", ".md", ".text", "":
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported resume format: %s (use .pdf, .docx or .txt)", filepath.Ext(path))
+	}
+}
+
+// extractDOCX reads word/document.xml from the archive and joins the text
+// runs, breaking lines at paragraph boundaries
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open DOCX: %w", err)
+	}
+
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to read DOCX body: %w", err)
+		}
+		defer rc.Close()
+
+		var text strings.Builder
+		decoder := xml.NewDecoder(rc)
+		inText := false
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to parse DOCX body: %w", err)
+			}
+
+			switch t := token.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					inText = true
+				case "tab":
+					text.WriteString("\t")
+				case "br":
+					text.WriteString("\n")
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					inText = false
+				case "p":
+					text.WriteString("\n")
+				}
+			case xml.CharData:
+				if inText {
+					text.Write(t)
+				}
+			}
+		}
+
+		return text.String(), nil
+	}
+
+	return "", fmt.Errorf("DOCX has no word/document.xml")
+}
@@ -0,0 +1,94 @@
+package resume
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fixtureResume = `Jane Doe
+jane.doe@example.com | +1 555 0100
+Senior Software Engineer
+Skills: Go, Kubernetes, PostgreSQL, AWS
+Experience
+Acme Corp — Backend Engineer, 2019–2024
+• Built résumé parsing services in Go.
+`
+
+// The fixtures in testdata are the same resume written the way each
+// exporter writes its PDFs:
+//
+//   - word.pdf: a WinAnsi TrueType font in literal strings with TJ kerning,
+//     a Type0 Identity-H font for the bullet, and objects packed in an
+//     object stream indexed by a cross-reference stream, as Microsoft Word
+//     saves
+//   - chrome.pdf: a Type0 Identity-H font whose glyph IDs are shown as hex
+//     strings and read only through its ToUnicode map, in a flipped
+//     coordinate system with part of the page in a form XObject, as Skia
+//     prints for Chrome and Google Docs
+//   - libreoffice.pdf: a subset TrueType font renumbered to one-byte codes
+//     in hex strings in TJ arrays, indirect stream lengths and resources
+//     inherited across two pages, as LibreOffice exports
+func TestExtractPDF(t *testing.T) {
+	for _, name := range []string{"word.pdf", "chrome.pdf", "libreoffice.pdf"} {
+		t.Run(name, func(t *testing.T) {
+			text, err := ExtractText(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatalf("ExtractText: %v", err)
+			}
+			if text != fixtureResume {
+				t.Errorf("got text\n%s\nwant\n%s", text, fixtureResume)
+			}
+		})
+	}
+}
+
+func TestExtractPDFScanned(t *testing.T) {
+	_, err := ExtractText(filepath.Join("testdata", "scanned.pdf"))
+	if err == nil || !strings.Contains(err.Error(), "scanned") {
+		t.Errorf("got error %v, want one saying the PDF may be scanned", err)
+	}
+}
+
+func TestExtractPDFNotPDF(t *testing.T) {
+	if _, err := extractPDF([]byte("Jane Doe\nSenior Software Engineer\n")); err == nil {
+		t.Error("got no error for text that is not a PDF")
+	}
+}
+
+func TestPDFLiteralString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`(plain)`, "plain"},
+		{`(nested (parentheses) kept)`, "nested (parentheses) kept"},
+		{`(escaped \( and \) and \\)`, `escaped ( and ) and \`},
+		{`(r\351sum\351)`, "r\xe9sum\xe9"},
+		{`(\0501\051)`, "(1)"},
+		{"(line \\\ncontinued)", "line continued"},
+		{`(tab\tnewline\n)`, "tab\tnewline\n"},
+	}
+	for _, test := range tests {
+		p := &pdfParser{data: []byte(test.in)}
+		if got := string(p.literal()); got != test.want {
+			t.Errorf("literal(%s) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseCMap(t *testing.T) {
+	cmap := parseCMap([]byte(`1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0003> <0020>
+<0A8C> <2022>
+endbfchar
+2 beginbfrange
+<0024> <0026> <0041>
+<0030> <0031> [<0066006C> <00E9>]
+endbfrange`))
+
+	font := &pdfFont{toUnicode: cmap, composite: true}
+	if got, want := font.decode(pdfString("\x0a\x8c\x00\x03\x00\x24\x00\x25\x00\x26\x00\x30\x00\x31")), "• ABCflé"; got != want {
+		t.Errorf("decode = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package resume
+
+import (
+	"sort"
+
+	"hire.ai/pkg/models"
+)
+
+// GapReport sums up the skills the best-matching jobs ask for
+type GapReport struct {
+	JobsAnalyzed int      `json:"jobs_analyzed"`
+	ResumeSkills []string `json:"resume_skills"`
+	// Missing are the skills the resume lacks, most often asked for first
+	Missing []SkillDemand `json:"missing"`
+	// Covered are the skills the resume has, most often asked for first
+	Covered []SkillDemand `json:"covered"`
+}
+
+// SkillDemand is how many of the analysed jobs ask for a skill
+type SkillDemand struct {
+	Skill string `json:"skill"`
+	Jobs  int    `json:"jobs"`
+	// Share is Jobs as a fraction of the jobs analysed
+	Share    float64      `json:"share"`
+	Examples []GapExample `json:"examples,omitempty"`
+}
+
+// GapExample is a posting that asks for a skill
+type GapExample struct {
+	Title   string  `json:"title"`
+	Company string  `json:"company"`
+	Link    string  `json:"link"`
+	Score   float64 `json:"score"`
+}
+
+// SkillGaps matches every job against the profile, keeps the top best
+// matches and counts the skills they ask for. Each missing skill lists up
+// to examples of the best-matching postings that ask for it.
+func (p *Profile) SkillGaps(jobs []models.Job, top, examples int) *GapReport {
+	type scored struct {
+		job   *models.Job
+		match *models.JobMatch
+	}
+	ranked := make([]scored, len(jobs))
+	for i := range jobs {
+		ranked[i] = scored{job: &jobs[i], match: p.Match(&jobs[i])}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].match.Score > ranked[j].match.Score })
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+
+	report := &GapReport{JobsAnalyzed: len(ranked), ResumeSkills: p.Skills}
+	missing := make(map[string]*SkillDemand)
+	covered := make(map[string]*SkillDemand)
+	count := func(demand map[string]*SkillDemand, skill string, job *models.Job, score float64, withExamples bool) {
+		entry, ok := demand[skill]
+		if !ok {
+			entry = &SkillDemand{Skill: skill}
+			demand[skill] = entry
+		}
+		entry.Jobs++
+		if withExamples && len(entry.Examples) < examples {
+			entry.Examples = append(entry.Examples, GapExample{Title: job.Title, Company: job.Company, Link: job.Link, Score: score})
+		}
+	}
+	for _, r := range ranked {
+		for _, skill := range r.match.MissingSkills {
+			count(missing, skill, r.job, r.match.Score, true)
+		}
+		for _, skill := range r.match.MatchedSkills {
+			count(covered, skill, r.job, r.match.Score, false)
+		}
+	}
+
+	report.Missing = byDemand(missing, len(ranked))
+	report.Covered = byDemand(covered, len(ranked))
+	return report
+}
+
+// byDemand sorts skills by the number of jobs asking for them, then by name
+func byDemand(demand map[string]*SkillDemand, total int) []SkillDemand {
+	skills := make([]SkillDemand, 0, len(demand))
+	for _, entry := range demand {
+		if total > 0 {
+			entry.Share = float64(entry.Jobs) / float64(total)
+		}
+		skills = append(skills, *entry)
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Jobs != skills[j].Jobs {
+			return skills[i].Jobs > skills[j].Jobs
+		}
+		return skills[i].Skill < skills[j].Skill
+	})
+	return skills
+}
@@ -0,0 +1,111 @@
+package resume
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+// Weights of the three parts of a match score
+const (
+	skillsWeight = 0.6
+	yearsWeight  = 0.2
+	titleWeight  = 0.2
+)
+
+// Match compares the profile against a job. The score runs from 0 to 1:
+// mostly the share of the skills the posting asks for that the resume
+// has, plus whether the resume's experience covers the years asked for and
+// whether one of its titles fits the job's. Gaps explain what was missing.
+func (p *Profile) Match(job *models.Job) *models.JobMatch {
+	text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Keywords, " "))
+	match := &models.JobMatch{}
+
+	have := make(map[string]bool, len(p.Skills))
+	for _, skill := range p.Skills {
+		have[skill] = true
+	}
+	for _, skill := range JobSkills(text) {
+		if have[skill] || (skill == "go" && have["golang"]) || (skill == "golang" && have["go"]) {
+			match.MatchedSkills = append(match.MatchedSkills, skill)
+		} else {
+			match.MissingSkills = append(match.MissingSkills, skill)
+		}
+	}
+
+	// A posting naming no known skills earns nothing for skills
+	skillScore := 0.0
+	if asked := len(match.MatchedSkills) + len(match.MissingSkills); asked > 0 {
+		skillScore = float64(len(match.MatchedSkills)) / float64(asked)
+	}
+	if len(match.MissingSkills) > 0 {
+		match.Gaps = append(match.Gaps, "Missing skills: "+strings.Join(match.MissingSkills, ", "))
+	}
+
+	yearsScore := 1.0
+	match.RequiredYears = requiredYears(text)
+	if match.RequiredYears > 0 && p.YearsExperience < match.RequiredYears {
+		yearsScore = float64(p.YearsExperience) / float64(match.RequiredYears)
+		match.Gaps = append(match.Gaps, fmt.Sprintf("Asks for %d+ years of experience; resume shows %d",
+			match.RequiredYears, p.YearsExperience))
+	}
+
+	title := strings.ToLower(job.Title)
+	for _, profileTitle := range p.Titles {
+		if strings.Contains(title, profileTitle) {
+			match.TitleMatch = true
+			break
+		}
+	}
+	titleScore := 0.0
+	if match.TitleMatch {
+		titleScore = 1
+	} else if len(p.Titles) > 0 {
+		match.Gaps = append(match.Gaps, "Title differs from your roles ("+strings.Join(p.Titles[:min(len(p.Titles), 3)], ", ")+")")
+	}
+
+	match.Score = skillsWeight*skillScore + yearsWeight*yearsScore + titleWeight*titleScore
+	return match
+}
+
+// JobSkills lists the vocabulary skills a posting mentions
+func JobSkills(text string) []string {
+	var skills []string
+	for _, skill := range skillVocabulary {
+		if countTerm(text, skill) > 0 {
+			skills = append(skills, skill)
+		}
+	}
+	// "golang" and "go" describe the same skill, as in ParseText
+	for i, skill := range skills {
+		if skill == "go" && contains(skills, "golang") {
+			skills = append(skills[:i], skills[i+1:]...)
+			break
+		}
+	}
+	return skills
+}
+
+// requiredYears is the largest experience requirement a posting states,
+// e.g. 6 for "6+ years of Go and 3 years of AWS"
+func requiredYears(text string) int {
+	required := 0
+	for _, match := range yearsPattern.FindAllStringSubmatch(text, -1) {
+		years, err := strconv.Atoi(match[1])
+		if err == nil && years > required && years < 30 {
+			required = years
+		}
+	}
+	return required
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1070 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Values of a PDF file. Numbers are float64, booleans bool, null nil and
+// arrays []interface{}.
+type (
+	pdfName   string
+	pdfString string
+	pdfDict   map[pdfName]interface{}
+	// pdfOp is an operator of a content stream or CMap, such as Tj
+	pdfOp  string
+	pdfRef struct{ num, gen int }
+)
+
+// pdfStream is a stream object: its dictionary and its data as stored,
+// still encoded by the stream's filters
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfRootRef      = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfEncrypt      = regexp.MustCompile(`/Encrypt\s*(?:\d+\s+\d+\s+R|<<)`)
+)
+
+// extractPDF returns the text of a PDF's pages, read from their content
+// streams in page order. Strings are decoded through the ToUnicode map of
+// their font, which Word, Google Docs, Chrome and LibreOffice all write for
+// the subset fonts of their exports, and through the font's encoding when
+// it has none. Scanned PDFs have no text, and encrypted ones are not read.
+func extractPDF(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("%PDF")) {
+		return "", fmt.Errorf("file is not a PDF")
+	}
+	if pdfEncrypt.Match(data) {
+		return "", fmt.Errorf("PDF is encrypted; export it again without a password")
+	}
+
+	doc := parsePDF(data)
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", fmt.Errorf("no pages found in PDF")
+	}
+
+	var lines []string
+	for _, page := range pages {
+		text := &pdfText{doc: doc}
+		text.run(doc.contents(page.dict["Contents"]), page.resources)
+		for _, line := range strings.Split(text.out.String(), "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no extractable text found in PDF (is it scanned?)")
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// pdfDoc holds the objects of a PDF by number
+type pdfDoc struct {
+	data    []byte
+	objects map[int]interface{}
+	fonts   map[pdfRef]*pdfFont
+}
+
+// parsePDF finds the objects of a PDF by scanning for their headers rather
+// than trusting the cross-reference table, which damaged and hand-edited
+// files often get wrong. Later definitions replace earlier ones, as
+// incremental updates do, and objects packed in object streams are read
+// last.
+func parsePDF(data []byte) *pdfDoc {
+	doc := &pdfDoc{data: data, objects: make(map[int]interface{}), fonts: make(map[pdfRef]*pdfFont)}
+
+	end := 0
+	for _, match := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		if match[0] < end {
+			// Inside the data of the stream before
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		p := &pdfParser{data: data, pos: match[1]}
+		value, err := p.value(true)
+		if err != nil {
+			continue
+		}
+		end = p.pos
+
+		if dict, ok := value.(pdfDict); ok {
+			p.skipSpace()
+			if bytes.HasPrefix(data[p.pos:], []byte("stream")) {
+				stream := doc.readStream(dict, p.pos+len("stream"))
+				end = p.pos + len("stream") + len(stream.raw)
+				value = stream
+			}
+		}
+		doc.objects[num] = value
+	}
+
+	var packed []*pdfStream
+	for _, value := range doc.objects {
+		if stream, ok := value.(*pdfStream); ok && stream.dict["Type"] == pdfName("ObjStm") {
+			packed = append(packed, stream)
+		}
+	}
+	for _, stream := range packed {
+		doc.unpack(stream)
+	}
+	return doc
+}
+
+// readStream reads the data of a stream starting after its stream keyword,
+// going by its Length when that is given directly and lands on endstream
+func (d *pdfDoc) readStream(dict pdfDict, start int) *pdfStream {
+	if bytes.HasPrefix(d.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(d.data) && (d.data[start] == '\n' || d.data[start] == '\r') {
+		start++
+	}
+
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && start+int(length) <= len(d.data) {
+		end := start + int(length)
+		if bytes.HasPrefix(bytes.TrimLeft(d.data[end:], "\r\n \t"), []byte("endstream")) {
+			return &pdfStream{dict: dict, raw: d.data[start:end]}
+		}
+	}
+
+	end := bytes.Index(d.data[start:], []byte("endstream"))
+	if end < 0 {
+		return &pdfStream{dict: dict, raw: d.data[start:]}
+	}
+	raw := d.data[start : start+end]
+	if bytes.HasSuffix(raw, []byte("\r\n")) {
+		raw = raw[:len(raw)-2]
+	} else if bytes.HasSuffix(raw, []byte("\n")) || bytes.HasSuffix(raw, []byte("\r")) {
+		raw = raw[:len(raw)-1]
+	}
+	return &pdfStream{dict: dict, raw: raw}
+}
+
+// unpack reads the objects of an object stream that are not defined
+// directly in the file
+func (d *pdfDoc) unpack(stream *pdfStream) {
+	data, err := d.decode(stream)
+	if err != nil {
+		return
+	}
+	count, _ := d.resolve(stream.dict["N"]).(float64)
+	first, _ := d.resolve(stream.dict["First"]).(float64)
+
+	header := &pdfParser{data: data}
+	for i := 0; i < int(count); i++ {
+		num, err1 := header.value(false)
+		offset, err2 := header.value(false)
+		if err1 != nil || err2 != nil {
+			return
+		}
+		n, ok1 := num.(float64)
+		off, ok2 := offset.(float64)
+		if !ok1 || !ok2 {
+			return
+		}
+		if _, defined := d.objects[int(n)]; defined {
+			continue
+		}
+		if pos := int(first) + int(off); pos >= 0 && pos < len(data) {
+			p := &pdfParser{data: data, pos: pos}
+			if value, err := p.value(true); err == nil {
+				d.objects[int(n)] = value
+			}
+		}
+	}
+}
+
+// resolve follows references to the value they name
+func (d *pdfDoc) resolve(value interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		value = d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDoc) dict(value interface{}) pdfDict {
+	switch v := d.resolve(value).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// decode undoes the filters of a stream. Only the filters text and fonts
+// are stored with are supported: image filters and Flate predictors, which
+// only images and cross-reference streams use, are not.
+func (d *pdfDoc) decode(stream *pdfStream) ([]byte, error) {
+	var filters []interface{}
+	switch filter := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{filter}
+	case []interface{}:
+		filters = filter
+	}
+
+	data := stream.raw
+	for _, filter := range filters {
+		var err error
+		switch d.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data, err = decodeASCIIHex(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			err = fmt.Errorf("unsupported PDF filter %v", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses Flate data, keeping what was read of a stream that
+// is cut short
+func inflate(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	out, err := io.ReadAll(reader)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if isHexDigit(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	return hex.DecodeString(string(digits))
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// pdfPage is a page with the resources it has or inherits from the page
+// tree above it
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the pages of the document in order
+func (d *pdfDoc) pages() []pdfPage {
+	var catalog pdfDict
+	if roots := pdfRootRef.FindAllSubmatch(d.data, -1); len(roots) > 0 {
+		num, _ := strconv.Atoi(string(roots[len(roots)-1][1]))
+		catalog = d.dict(pdfRef{num: num})
+	}
+	if catalog == nil {
+		for _, value := range d.objects {
+			if dict, ok := value.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				catalog = dict
+				break
+			}
+		}
+	}
+
+	var pages []pdfPage
+	var walk func(node pdfDict, resources pdfDict, depth int)
+	walk = func(node pdfDict, resources pdfDict, depth int) {
+		if node == nil || depth > 64 {
+			return
+		}
+		if own := d.dict(node["Resources"]); own != nil {
+			resources = own
+		}
+		kids, ok := d.resolve(node["Kids"]).([]interface{})
+		if !ok {
+			pages = append(pages, pdfPage{dict: node, resources: resources})
+			return
+		}
+		for _, kid := range kids {
+			walk(d.dict(kid), resources, depth+1)
+		}
+	}
+	if catalog != nil {
+		walk(d.dict(catalog["Pages"]), nil, 0)
+	}
+	return pages
+}
+
+// contents returns the decoded content streams of a page, joined
+func (d *pdfDoc) contents(value interface{}) []byte {
+	var streams []interface{}
+	switch v := d.resolve(value).(type) {
+	case *pdfStream:
+		streams = []interface{}{v}
+	case []interface{}:
+		streams = v
+	}
+
+	var content []byte
+	for _, value := range streams {
+		if stream, ok := d.resolve(value).(*pdfStream); ok {
+			if data, err := d.decode(stream); err == nil {
+				content = append(append(content, data...), '\n')
+			}
+		}
+	}
+	return content
+}
+
+// pdfText writes out the text a content stream shows, breaking lines where
+// the text moves down the page and spacing runs that move along it
+type pdfText struct {
+	doc  *pdfDoc
+	out  strings.Builder
+	font *pdfFont
+	// lineY is the baseline text is placed on, and shownY the baseline of
+	// the text last shown; moved is set when text was placed since
+	lineY, shownY float64
+	moved         bool
+	depth         int
+}
+
+func (t *pdfText) run(content []byte, resources pdfDict) {
+	p := &pdfParser{data: content}
+	var operands []interface{}
+	for {
+		value, err := p.value(false)
+		if err != nil {
+			return
+		}
+		op, ok := value.(pdfOp)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch op {
+		case "BI":
+			p.skipInlineImage()
+		case "Tf":
+			if len(operands) == 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					t.font = t.doc.font(resources, name)
+				}
+			}
+		case "BT":
+			t.lineY = 0
+		case "Td", "TD":
+			if len(operands) == 2 {
+				ty, _ := operands[1].(float64)
+				t.lineY += ty
+				t.moved = true
+			}
+		case "Tm":
+			if len(operands) == 6 {
+				t.lineY, _ = operands[5].(float64)
+				t.moved = true
+			}
+		case "T*":
+			t.newline()
+		case "Tj":
+			t.show(operands)
+		case "'", "\"":
+			t.newline()
+			t.show(operands)
+		case "TJ":
+			if len(operands) == 1 {
+				parts, _ := operands[0].([]interface{})
+				t.place()
+				for _, part := range parts {
+					switch v := part.(type) {
+					case pdfString:
+						t.out.WriteString(t.font.decode(v))
+					case float64:
+						// Adjustments are in thousandths of the font size;
+						// one of about a space's width or more is a gap
+						// between words rather than kerning
+						if v < -200 {
+							t.space()
+						}
+					}
+				}
+			}
+		case "Do":
+			if len(operands) == 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					t.form(resources, name)
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// show writes the string operand of a text-showing operator
+func (t *pdfText) show(operands []interface{}) {
+	if len(operands) > 0 {
+		if s, ok := operands[len(operands)-1].(pdfString); ok {
+			t.place()
+			t.out.WriteString(t.font.decode(s))
+		}
+	}
+}
+
+// place starts a new line before text placed off the baseline of the text
+// before it, and a new word before text placed along it
+func (t *pdfText) place() {
+	if t.moved {
+		if math.Abs(t.lineY-t.shownY) > 1 {
+			t.newline()
+		} else {
+			t.space()
+		}
+	}
+	t.moved = false
+	t.shownY = t.lineY
+}
+
+// form runs the content of a form XObject, which may hold text of its own
+func (t *pdfText) form(resources pdfDict, name pdfName) {
+	stream, ok := t.doc.resolve(t.doc.dict(resources["XObject"])[name]).(*pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") || t.depth >= 8 {
+		return
+	}
+	data, err := t.doc.decode(stream)
+	if err != nil {
+		return
+	}
+	if own := t.doc.dict(stream.dict["Resources"]); own != nil {
+		resources = own
+	}
+	font := t.font
+	t.depth++
+	t.run(data, resources)
+	t.depth--
+	t.font = font
+}
+
+func (t *pdfText) newline() {
+	t.moved = false
+	if s := t.out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		t.out.WriteByte('\n')
+	}
+}
+
+func (t *pdfText) space() {
+	if s := t.out.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		t.out.WriteByte(' ')
+	}
+}
+
+// pdfFont decodes the strings shown in a font to text
+type pdfFont struct {
+	toUnicode *pdfCMap
+	// composite fonts take two bytes a code unless a CMap says otherwise
+	composite bool
+	// utf16 is set for composite fonts whose encoding is already Unicode
+	utf16 bool
+	// encoding maps the codes of a simple font
+	encoding [256]string
+}
+
+// font loads the font resources name, caching fonts by reference
+func (d *pdfDoc) font(resources pdfDict, name pdfName) *pdfFont {
+	value := d.dict(resources["Font"])[name]
+	ref, isRef := value.(pdfRef)
+	if font, ok := d.fonts[ref]; isRef && ok {
+		return font
+	}
+	dict := d.dict(value)
+	if dict == nil {
+		return nil
+	}
+
+	font := &pdfFont{composite: dict["Subtype"] == pdfName("Type0")}
+	if stream, ok := d.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := d.decode(stream); err == nil {
+			font.toUnicode = parseCMap(data)
+		}
+	}
+	if font.composite {
+		encoding, _ := d.resolve(dict["Encoding"]).(pdfName)
+		font.utf16 = strings.Contains(string(encoding), "UCS2") || strings.Contains(string(encoding), "UTF16")
+	} else {
+		font.encoding = d.simpleEncoding(d.resolve(dict["Encoding"]))
+	}
+
+	if isRef {
+		d.fonts[ref] = font
+	}
+	return font
+}
+
+// simpleEncoding builds the code table of a simple font from its base
+// encoding and any Differences. Standard and symbolic fonts are read as
+// WinAnsi, which agrees with them on letters and digits.
+func (d *pdfDoc) simpleEncoding(value interface{}) [256]string {
+	base := charmap.Windows1252
+	var differences []interface{}
+	switch v := value.(type) {
+	case pdfName:
+		if v == "MacRomanEncoding" {
+			base = charmap.Macintosh
+		}
+	case pdfDict:
+		if d.resolve(v["BaseEncoding"]) == pdfName("MacRomanEncoding") {
+			base = charmap.Macintosh
+		}
+		differences, _ = d.resolve(v["Differences"]).([]interface{})
+	}
+
+	var encoding [256]string
+	for i := range encoding {
+		encoding[i] = string(base.DecodeByte(byte(i)))
+	}
+	code := 0
+	for _, item := range differences {
+		switch v := item.(type) {
+		case float64:
+			code = int(v)
+		case pdfName:
+			if code >= 0 && code < 256 {
+				if text, ok := glyphText(string(v)); ok {
+					encoding[code] = text
+				}
+			}
+			code++
+		}
+	}
+	return encoding
+}
+
+// glyphNames are the glyph names of Differences arrays that are not a
+// single letter or uniXXXX
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "parenleft": "(",
+	"parenright": ")", "asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-",
+	"period": ".", "slash": "/", "zero": "0", "one": "1", "two": "2", "three": "3",
+	"four": "4", "five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"colon": ":", "semicolon": ";", "less": "<", "equal": "=", "greater": ">",
+	"question": "?", "at": "@", "bracketleft": "[", "backslash": "\\",
+	"bracketright": "]", "underscore": "_", "bar": "|", "quoteleft": "‘",
+	"quoteright": "’", "quotedblleft": "“", "quotedblright": "”",
+	"endash": "–", "emdash": "—", "bullet": "•", "ellipsis": "…",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+}
+
+func glyphText(name string) (string, bool) {
+	if text, ok := glyphNames[name]; ok {
+		return text, true
+	}
+	if len(name) == 1 {
+		return name, true
+	}
+	if len(name) == 7 && strings.HasPrefix(name, "uni") {
+		if code, err := strconv.ParseUint(name[3:], 16, 16); err == nil {
+			return string(rune(code)), true
+		}
+	}
+	return "", false
+}
+
+// decode returns the text of a string shown in the font. A nil font, where
+// a content stream shows text before choosing one, is read as WinAnsi.
+func (f *pdfFont) decode(s pdfString) string {
+	if f == nil {
+		f = &pdfFont{encoding: (&pdfDoc{}).simpleEncoding(nil)}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		n := 1
+		if f.toUnicode != nil {
+			n = f.toUnicode.codeLength(s[i:], f.composite)
+		} else if f.composite {
+			n = 2
+		}
+		if i+n > len(s) {
+			n = len(s) - i
+		}
+		code := s[i : i+n]
+		i += n
+
+		if f.toUnicode != nil {
+			if text, ok := f.toUnicode.chars[string(code)]; ok {
+				out.WriteString(text)
+				continue
+			}
+		}
+		switch {
+		case f.utf16:
+			out.WriteString(decodeUTF16([]byte(code)))
+		case !f.composite:
+			out.WriteString(f.encoding[code[0]])
+		}
+		// Glyph IDs of a composite font without a map cannot be read
+	}
+	return out.String()
+}
+
+// pdfCMap is a ToUnicode CMap: the byte lengths of codes and the text each
+// maps to
+type pdfCMap struct {
+	ranges []pdfCodeRange
+	chars  map[string]string
+}
+
+type pdfCodeRange struct {
+	low, high []byte
+}
+
+// codeLength returns the length of the code at the start of s, as the
+// first codespace range that takes it says
+func (c *pdfCMap) codeLength(s pdfString, composite bool) int {
+	for _, r := range c.ranges {
+		if len(r.low) > len(s) {
+			continue
+		}
+		in := true
+		for i := range r.low {
+			if s[i] < r.low[i] || s[i] > r.high[i] {
+				in = false
+				break
+			}
+		}
+		if in {
+			return len(r.low)
+		}
+	}
+	if composite {
+		return 2
+	}
+	return 1
+}
+
+// maxCMapRange bounds the codes one bfrange entry may map
+const maxCMapRange = 1 << 16
+
+// parseCMap reads the codespace ranges and bfchar and bfrange mappings of a
+// ToUnicode CMap
+func parseCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{chars: make(map[string]string)}
+	p := &pdfParser{data: data}
+	var operands []interface{}
+	for {
+		value, err := p.value(false)
+		if err != nil {
+			break
+		}
+		op, ok := value.(pdfOp)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 && len(low) == len(high) && len(low) > 0 {
+					cmap.ranges = append(cmap.ranges, pdfCodeRange{low: []byte(low), high: []byte(high)})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				code, ok := operands[i].(pdfString)
+				if !ok {
+					continue
+				}
+				switch dst := operands[i+1].(type) {
+				case pdfString:
+					cmap.chars[string(code)] = decodeUTF16([]byte(dst))
+				case pdfName:
+					if text, ok := glyphText(string(dst)); ok {
+						cmap.chars[string(code)] = text
+					}
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(low) != len(high) || len(low) == 0 || len(low) > 4 {
+					continue
+				}
+				start, stop := codeValue(low), codeValue(high)
+				for code := start; code <= stop && code-start < maxCMapRange; code++ {
+					key := string(codeBytes(code, len(low)))
+					switch dst := operands[i+2].(type) {
+					case pdfString:
+						cmap.chars[key] = decodeUTF16(offsetUTF16([]byte(dst), code-start))
+					case []interface{}:
+						if k := int(code - start); k < len(dst) {
+							if text, ok := dst[k].(pdfString); ok {
+								cmap.chars[key] = decodeUTF16([]byte(text))
+							}
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return cmap
+}
+
+func codeValue(code pdfString) uint32 {
+	var value uint32
+	for i := 0; i < len(code); i++ {
+		value = value<<8 | uint32(code[i])
+	}
+	return value
+}
+
+func codeBytes(value uint32, n int) []byte {
+	code := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		code[i] = byte(value)
+		value >>= 8
+	}
+	return code
+}
+
+// offsetUTF16 adds offset to the last code unit of UTF-16BE text, as the
+// destinations of a bfrange count up from its first
+func offsetUTF16(text []byte, offset uint32) []byte {
+	text = append([]byte(nil), text...)
+	if n := len(text); n >= 2 {
+		unit := uint32(text[n-2])<<8 | uint32(text[n-1])
+		unit += offset
+		text[n-2], text[n-1] = byte(unit>>8), byte(unit)
+	} else if n == 1 {
+		text[0] += byte(offset)
+	}
+	return text
+}
+
+// decodeUTF16 decodes the UTF-16BE text of CMap destinations, dropping a
+// byte order mark
+func decodeUTF16(text []byte) string {
+	if len(text) == 1 {
+		return string(rune(text[0]))
+	}
+	units := make([]uint16, 0, len(text)/2)
+	for i := 0; i+1 < len(text); i += 2 {
+		units = append(units, uint16(text[i])<<8|uint16(text[i+1]))
+	}
+	if len(units) > 0 && units[0] == 0xfeff {
+		units = units[1:]
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfParser reads PDF values from file data, object streams, content
+// streams and CMaps
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// skipSpace skips white space and comments
+func (p *pdfParser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// value reads the next value, or the next operator of a content stream as
+// a pdfOp. With refs set, "n g R" is read as a reference, as it is outside
+// content streams.
+func (p *pdfParser) value(refs bool) (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, io.EOF
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		return p.name(), nil
+	case c == '(':
+		return p.literal(), nil
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		p.pos += 2
+		return p.dict(refs)
+	case c == '<':
+		return p.hex(), nil
+	case c == '[':
+		p.pos++
+		return p.array(refs)
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		return p.number(refs), nil
+	case isPDFDelimiter(c):
+		// A stray closing delimiter
+		p.pos++
+		return pdfOp([]byte{c}), nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.data) && !isPDFSpace(p.data[p.pos]) && !isPDFDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	switch word := string(p.data[start:p.pos]); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return pdfOp(word), nil
+	}
+}
+
+func (p *pdfParser) name() pdfName {
+	p.pos++
+	var name []byte
+	for p.pos < len(p.data) && !isPDFSpace(p.data[p.pos]) && !isPDFDelimiter(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) && isHexDigit(p.data[p.pos+1]) && isHexDigit(p.data[p.pos+2]) {
+			value, _ := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8)
+			name = append(name, byte(value))
+			p.pos += 3
+			continue
+		}
+		name = append(name, c)
+		p.pos++
+	}
+	return pdfName(name)
+}
+
+// literal reads a (string), which may hold balanced parentheses and
+// backslash escapes
+func (p *pdfParser) literal() pdfString {
+	p.pos++
+	var out []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return pdfString(out)
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				return pdfString(out)
+			}
+			c = p.data[p.pos]
+			p.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A line continuation
+				if c == '\r' && p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			default:
+				// Octal escapes such as \050
+				if c >= '0' && c <= '7' {
+					value := int(c - '0')
+					for j := 0; j < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; j++ {
+						value = value*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(value)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return pdfString(out)
+}
+
+// hex reads a <hex string>
+func (p *pdfParser) hex() pdfString {
+	p.pos++
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		end = len(p.data) - p.pos
+	}
+	data, _ := decodeASCIIHex(p.data[p.pos : p.pos+end])
+	p.pos += end + 1
+	return pdfString(data)
+}
+
+func (p *pdfParser) dict(refs bool) (pdfDict, error) {
+	dict := make(pdfDict)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+			p.pos += 2
+			return dict, nil
+		}
+		key, err := p.value(refs)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			continue
+		}
+		value, err := p.value(refs)
+		if err != nil {
+			return nil, err
+		}
+		dict[name] = value
+	}
+}
+
+func (p *pdfParser) array(refs bool) ([]interface{}, error) {
+	var array []interface{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return array, nil
+		}
+		value, err := p.value(refs)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+	}
+}
+
+func (p *pdfParser) number(refs bool) interface{} {
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c != '+' && c != '-' && c != '.' && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	text := string(p.data[start:p.pos])
+	value, _ := strconv.ParseFloat(text, 64)
+	if !refs || strings.ContainsAny(text, ".+-") {
+		return value
+	}
+
+	// "n g R" is a reference
+	mark := p.pos
+	p.skipSpace()
+	genStart := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos > genStart {
+		gen, _ := strconv.Atoi(string(p.data[genStart:p.pos]))
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == 'R' &&
+			(p.pos+1 == len(p.data) || isPDFSpace(p.data[p.pos+1]) || isPDFDelimiter(p.data[p.pos+1])) {
+			p.pos++
+			return pdfRef{num: int(value), gen: gen}
+		}
+	}
+	p.pos = mark
+	return value
+}
+
+// skipInlineImage skips the data of an inline image, from after its BI to
+// after its EI
+func (p *pdfParser) skipInlineImage() {
+	for p.pos < len(p.data) {
+		end := bytes.Index(p.data[p.pos:], []byte("EI"))
+		if end < 0 {
+			p.pos = len(p.data)
+			return
+		}
+		p.pos += end + 2
+		before := p.pos - 3
+		if before >= 0 && isPDFSpace(p.data[before]) &&
+			(p.pos == len(p.data) || isPDFSpace(p.data[p.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package resume
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+// Profile is the structured view of a resume used to drive searches
+type Profile struct {
+	Skills          []string `json:"skills"`
+	Titles          []string `json:"titles"`
+	YearsExperience int      `json:"years_experience"`
+	Text            string   `json:"-"`
+}
+
+// skillVocabulary lists the skills recognised in resumes and postings. Entries
+// are matched on word boundaries, so short names like "go" stay precise.
+var skillVocabulary = []string{
+	"go", "golang", "python", "java", "javascript", "typescript", "rust", "c++", "c#", "ruby", "php",
+	"scala", "kotlin", "swift", "elixir", "haskell", "sql", "bash",
+	"react", "vue", "angular", "svelte", "next.js", "node.js", "express", "django", "flask", "fastapi",
+	"spring", "spring boot", "rails", "laravel", ".net",
+	"kubernetes", "docker", "terraform", "ansible", "helm", "aws", "azure", "gcp",
+	"postgresql", "mysql", "mongodb", "redis", "elasticsearch", "kafka", "rabbitmq", "cassandra",
+	"graphql", "grpc", "rest", "microservices", "linux", "git", "ci/cd",
+	"machine learning", "deep learning", "pytorch", "tensorflow", "nlp", "data science", "spark", "airflow",
+	"ios", "android", "flutter", "react native",
+}
+
+var titlePattern = regexp.MustCompile(`(?i)\b((?:senior|sr\.?|junior|jr\.?|lead|staff|principal)\s+)?` +
+	`((?:software|backend|back-end|frontend|front-end|full[- ]?stack|platform|data|devops|site reliability|` +
+	`machine learning|ml|cloud|mobile|infrastructure|security|qa|test)\s+)` +
+	`(engineer|developer|scientist|architect|analyst|manager)\b`)
+
+var yearsPattern = regexp.MustCompile(`(?i)(\d{1,2})\+?\s*(?:years|yrs)`)
+
+// ParseFile extracts text from a resume file and builds a profile from it
+func ParseFile(path string) (*Profile, error) {
+	text, err := ExtractText(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseText(text), nil
+}
+
+// ParseText builds a profile from resume text
+func ParseText(text string) *Profile {
+	lower := strings.ToLower(text)
+	profile := &Profile{Text: text}
+
+	skillCounts := make(map[string]int)
+	for _, skill := range skillVocabulary {
+		if count := countTerm(lower, skill); count > 0 {
+			skillCounts[skill] = count
+		}
+	}
+	// "golang" and "go" describe the same skill; keep the unambiguous one
+	if skillCounts["golang"] > 0 {
+		skillCounts["golang"] += skillCounts["go"]
+		delete(skillCounts, "go")
+	}
+	profile.Skills = rankByCount(skillCounts)
+
+	titleCounts := make(map[string]int)
+	for _, match := range titlePattern.FindAllStringSubmatch(lower, -1) {
+		title := strings.Join(strings.Fields(match[2]+" "+match[3]), " ")
+		titleCounts[title]++
+	}
+	profile.Titles = rankByCount(titleCounts)
+
+	for _, match := range yearsPattern.FindAllStringSubmatch(lower, -1) {
+		if years, err := strconv.Atoi(match[1]); err == nil && years > profile.YearsExperience && years < 50 {
+			profile.YearsExperience = years
+		}
+	}
+
+	return profile
+}
+
+// Keywords builds a search query from the most prominent titles and skills
+func (p *Profile) Keywords(maxTitles, maxSkills int) []string {
+	var keywords []string
+	for i, title := range p.Titles {
+		if i >= maxTitles {
+			break
+		}
+		keywords = append(keywords, title)
+	}
+	for i, skill := range p.Skills {
+		if i >= maxSkills {
+			break
+		}
+		keywords = append(keywords, skill)
+	}
+	return keywords
+}
+
+// MatchedSkills returns the profile skills that appear in the job posting
+func (p *Profile) MatchedSkills(job *models.Job) []string {
+	text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Keywords, " "))
+
+	var matched []string
+	for _, skill := range p.Skills {
+		if countTerm(text, skill) > 0 {
+			matched = append(matched, skill)
+		}
+	}
+	return matched
+}
+
+// RelevanceBoost is the job's match score against the profile, added on top
+// of the keyword relevance
+func (p *Profile) RelevanceBoost(job *models.Job) float64 {
+	if len(p.Skills) == 0 && len(p.Titles) == 0 {
+		return 0
+	}
+	return p.Match(job).Score
+}
+
+// countTerm counts whole-word occurrences of term in text (both lowercase)
+func countTerm(text, term string) int {
+	count := 0
+	for start := 0; start < len(text); {
+		idx := strings.Index(text[start:], term)
+		if idx == -1 {
+			break
+		}
+		idx += start
+		end := idx + len(term)
+		if isBoundary(text, idx-1) && isBoundary(text, end) {
+			count++
+		}
+		start = idx + 1
+	}
+	return count
+}
+
+func isBoundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	c := text[i]
+	return !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '#')
+}
+
+func rankByCount(counts map[string]int) []string {
+	ranked := make([]string, 0, len(counts))
+	for term := range counts {
+		ranked = append(ranked, term)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if counts[ranked[i]] != counts[ranked[j]] {
+			return counts[ranked[i]] > counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
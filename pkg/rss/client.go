@@ -1,6 +1,7 @@
 package rss
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -8,7 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/net/html/charset"
+
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
 )
 
 type RSSFeed struct {
@@ -45,6 +49,7 @@ type AtomEntry struct {
 		Href string `xml:"href,attr"`
 	} `xml:"link"`
 	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
 	ID        string `xml:"id"`
 }
 
@@ -65,10 +70,8 @@ type RSSClient struct {
 // NewRSSClient creates a new RSS client with the specified user agent
 func NewRSSClient(userAgent string) *RSSClient {
 	return &RSSClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: userAgent,
+		httpClient: transport.NewClient(30 * time.Second),
+		userAgent:  userAgent,
 	}
 }
 
@@ -108,9 +111,17 @@ func (c *RSSClient) FetchJobs(board RSSJobBoard, keywords []string) ([]models.Jo
 	return filteredJobs, nil
 }
 
+// unmarshalFeed decodes a feed in whatever encoding its XML declaration
+// names, such as Shift_JIS or ISO-8859-1, which encoding/xml alone rejects
+func unmarshalFeed(body []byte, feed interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder.Decode(feed)
+}
+
 func (c *RSSClient) parseRSSFeed(body []byte, board RSSJobBoard, keywords []string) ([]models.Job, error) {
 	var feed RSSFeed
-	if err := xml.Unmarshal(body, &feed); err != nil {
+	if err := unmarshalFeed(body, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
 	}
 
@@ -127,7 +138,7 @@ func (c *RSSClient) parseRSSFeed(body []byte, board RSSJobBoard, keywords []stri
 
 func (c *RSSClient) parseAtomFeed(body []byte, board RSSJobBoard, keywords []string) ([]models.Job, error) {
 	var feed AtomFeed
-	if err := xml.Unmarshal(body, &feed); err != nil {
+	if err := unmarshalFeed(body, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
 	}
 
@@ -160,6 +171,9 @@ func (c *RSSClient) itemToJob(item Item, source string) *models.Job {
 		item.Link,
 		source,
 	)
+	if posted, ok := models.ParseTimestamp(item.PubDate, time.UTC); ok {
+		job.SetPosted(posted)
+	}
 
 	return job
 }
@@ -181,6 +195,11 @@ func (c *RSSClient) entryToJob(entry AtomEntry, source string) *models.Job {
 		entry.Link.Href,
 		source,
 	)
+	if posted, ok := models.ParseTimestamp(entry.Published, time.UTC); ok {
+		job.SetPosted(posted)
+	} else if posted, ok := models.ParseTimestamp(entry.Updated, time.UTC); ok {
+		job.SetPosted(posted)
+	}
 
 	return job
 }
@@ -0,0 +1,350 @@
+package salary
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+//go:embed benchmark.html
+var benchmarkHTMLTemplate string
+
+// Dimensions a benchmark groups salaries by
+const (
+	ByTitle     = "title"
+	BySeniority = "seniority"
+	ByLocation  = "location"
+	ByCategory  = "category"
+	ByCompany   = "company"
+)
+
+// Dimensions lists the dimensions a benchmark can group by
+var Dimensions = []string{ByTitle, BySeniority, ByLocation, ByCategory, ByCompany}
+
+// Band is the spread of the yearly salaries of one group of jobs. Each
+// job counts once, at the middle of its range.
+type Band struct {
+	// Group holds the group's value of each dimension, in the order of
+	// Benchmark.By
+	Group    []string `json:"group"`
+	Currency string   `json:"currency"`
+	Jobs     int      `json:"jobs"`
+	P10      float64  `json:"p10"`
+	P25      float64  `json:"p25"`
+	P50      float64  `json:"p50"`
+	P75      float64  `json:"p75"`
+	P90      float64  `json:"p90"`
+	Mean     float64  `json:"mean"`
+}
+
+// Label joins the band's group values
+func (b Band) Label() string {
+	return strings.Join(b.Group, " / ")
+}
+
+// Benchmark is the salary distribution of stored jobs by group
+type Benchmark struct {
+	By []string `json:"by"`
+	// JobsAnalyzed have a salary; JobsWithoutSalary were left out
+	JobsAnalyzed      int `json:"jobs_analyzed"`
+	JobsWithoutSalary int `json:"jobs_without_salary"`
+	// Overall has a band per currency across every group
+	Overall []Band `json:"overall"`
+	// Bands have at least the minimum number of jobs, largest first
+	Bands       []Band    `json:"bands"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// seniorityWords are left out of titles, so "Senior Go Engineer" and "Go
+// Engineer" fall in one title group that seniority can split
+var seniorityWords = regexp.MustCompile(`(?i)\b(senior|sr|junior|jr|lead|principal|staff|head of|intern|entry level|mid level|associate|ii|iii|iv|i)\b`)
+
+// NewBenchmark groups the yearly salaries of jobs by the dimensions in by,
+// keeping groups of at least minJobs jobs. Jobs in different currencies
+// are never mixed, and with currency set only jobs paid in it count.
+func NewBenchmark(jobs []models.Job, by []string, currency string, minJobs int) (*Benchmark, error) {
+	for _, dimension := range by {
+		if !containsDimension(dimension) {
+			return nil, fmt.Errorf("unknown dimension %q (use %s)", dimension, strings.Join(Dimensions, ", "))
+		}
+	}
+	currency = strings.ToUpper(currency)
+
+	benchmark := &Benchmark{By: by, GeneratedAt: time.Now().UTC()}
+	type group struct {
+		values   []string
+		currency string
+		salaries []float64
+	}
+	groups := make(map[string]*group)
+	overall := make(map[string]*group)
+	for i := range jobs {
+		yearly, jobCurrency, ok := yearlySalary(&jobs[i])
+		if !ok {
+			benchmark.JobsWithoutSalary++
+			continue
+		}
+		if currency != "" && jobCurrency != currency {
+			continue
+		}
+		benchmark.JobsAnalyzed++
+
+		values := make([]string, len(by))
+		for d, dimension := range by {
+			values[d] = dimensionValue(&jobs[i], dimension)
+		}
+		key := jobCurrency + "\x00" + strings.Join(values, "\x00")
+		if groups[key] == nil {
+			groups[key] = &group{values: values, currency: jobCurrency}
+		}
+		groups[key].salaries = append(groups[key].salaries, yearly)
+		if overall[jobCurrency] == nil {
+			overall[jobCurrency] = &group{values: []string{"All jobs"}, currency: jobCurrency}
+		}
+		overall[jobCurrency].salaries = append(overall[jobCurrency].salaries, yearly)
+	}
+
+	for _, g := range groups {
+		if len(g.salaries) >= minJobs {
+			benchmark.Bands = append(benchmark.Bands, newBand(g.values, g.currency, g.salaries))
+		}
+	}
+	for _, g := range overall {
+		benchmark.Overall = append(benchmark.Overall, newBand(g.values, g.currency, g.salaries))
+	}
+	sortBands(benchmark.Bands)
+	sortBands(benchmark.Overall)
+	return benchmark, nil
+}
+
+func sortBands(bands []Band) {
+	sort.Slice(bands, func(i, j int) bool {
+		if bands[i].Jobs != bands[j].Jobs {
+			return bands[i].Jobs > bands[j].Jobs
+		}
+		if bands[i].P50 != bands[j].P50 {
+			return bands[i].P50 > bands[j].P50
+		}
+		return bands[i].Label() < bands[j].Label()
+	})
+}
+
+func newBand(group []string, currency string, salaries []float64) Band {
+	sort.Float64s(salaries)
+	sum := 0.0
+	for _, salary := range salaries {
+		sum += salary
+	}
+	return Band{
+		Group:    group,
+		Currency: currency,
+		Jobs:     len(salaries),
+		P10:      percentile(salaries, 0.10),
+		P25:      percentile(salaries, 0.25),
+		P50:      percentile(salaries, 0.50),
+		P75:      percentile(salaries, 0.75),
+		P90:      percentile(salaries, 0.90),
+		Mean:     sum / float64(len(salaries)),
+	}
+}
+
+// percentile interpolates the p-th quantile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// yearlySalary returns the middle of a job's yearly salary range, parsing
+// the salary text of jobs stored before ranges were kept. Jobs without a
+// currency are taken to pay in USD.
+func yearlySalary(job *models.Job) (float64, string, bool) {
+	salaryRange := job.SalaryRange
+	if salaryRange == nil {
+		salaryRange = Parse(job.Salary)
+	}
+	if salaryRange == nil {
+		return 0, "", false
+	}
+	low, high := salaryRange.Yearly()
+	if high < low {
+		high = low
+	}
+	if low <= 0 {
+		return 0, "", false
+	}
+	currency := strings.ToUpper(salaryRange.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+	return (low + high) / 2, currency, true
+}
+
+func dimensionValue(job *models.Job, dimension string) string {
+	var value string
+	switch dimension {
+	case ByTitle:
+		value = normalizeTitle(job.Title)
+	case BySeniority:
+		if job.Classification != nil {
+			value = job.Classification.Seniority
+		}
+	case ByCategory:
+		if job.Classification != nil {
+			value = job.Classification.Category
+		}
+	case ByLocation:
+		value = strings.TrimSpace(job.Location)
+		if strings.Contains(strings.ToLower(value), "remote") {
+			value = "Remote"
+		}
+	case ByCompany:
+		value = strings.TrimSpace(job.Company)
+	}
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// normalizeTitle drops seniority, parenthesised notes and punctuation from
+// a title and lowercases it
+func normalizeTitle(title string) string {
+	if cut := strings.IndexAny(title, "(|,–"); cut > 0 {
+		title = title[:cut]
+	}
+	if before, _, found := strings.Cut(title, " - "); found {
+		title = before
+	}
+	title = seniorityWords.ReplaceAllString(strings.ToLower(title), " ")
+	title = strings.NewReplacer(".", " ", "/", " ", "-", " ").Replace(title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+func containsDimension(dimension string) bool {
+	for _, known := range Dimensions {
+		if dimension == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Chart draws a band as a text range bar width characters wide over the
+// salaries from low to high: the P10 to P90 whiskers as ─, the P25 to P75
+// box as █ and the median as │
+func Chart(band Band, low, high float64, width int) string {
+	if high <= low || width <= 0 {
+		return ""
+	}
+	position := func(value float64) int {
+		p := int(math.Round((value - low) / (high - low) * float64(width-1)))
+		return max(0, min(width-1, p))
+	}
+	cells := []rune(strings.Repeat(" ", width))
+	for i := position(band.P10); i <= position(band.P90); i++ {
+		cells[i] = '─'
+	}
+	for i := position(band.P25); i <= position(band.P75); i++ {
+		cells[i] = '█'
+	}
+	cells[position(band.P50)] = '│'
+	return string(cells)
+}
+
+// Scale returns the lowest P10 and highest P90 of bands paid in currency,
+// the range their charts share
+func Scale(bands []Band, currency string) (low, high float64) {
+	first := true
+	for _, band := range bands {
+		if band.Currency != currency {
+			continue
+		}
+		if first || band.P10 < low {
+			low = band.P10
+		}
+		if first || band.P90 > high {
+			high = band.P90
+		}
+		first = false
+	}
+	return low, high
+}
+
+// FormatAmount writes a yearly salary compactly, e.g. 125k
+func FormatAmount(amount float64) string {
+	if amount >= 1000 {
+		return fmt.Sprintf("%.0fk", amount/1000)
+	}
+	return fmt.Sprintf("%.0f", amount)
+}
+
+// chartWidth is the width of the HTML report's charts in SVG units
+const chartWidth = 400.0
+
+// htmlRow is a band laid out on its currency's chart scale
+type htmlRow struct {
+	Band
+	X10, X25, X50, X75, X90 float64
+	BoxWidth                float64
+}
+
+// htmlSection groups the rows of one currency, which share a scale
+type htmlSection struct {
+	Currency  string
+	Low, High float64
+	Rows      []htmlRow
+}
+
+// WriteHTML renders the benchmark as an HTML page with a range chart per
+// band
+func (b *Benchmark) WriteHTML(w io.Writer) error {
+	tmpl, err := template.New("benchmark").Funcs(template.FuncMap{
+		"amount": FormatAmount,
+	}).Parse(benchmarkHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse benchmark template: %w", err)
+	}
+
+	var sections []htmlSection
+	for _, overall := range b.Overall {
+		bands := append([]Band{overall}, b.Bands...)
+		low, high := Scale(bands, overall.Currency)
+		section := htmlSection{Currency: overall.Currency, Low: low, High: high}
+		x := func(value float64) float64 {
+			if high <= low {
+				return chartWidth / 2
+			}
+			return (value - low) / (high - low) * chartWidth
+		}
+		for _, band := range bands {
+			if band.Currency != overall.Currency {
+				continue
+			}
+			section.Rows = append(section.Rows, htmlRow{
+				Band: band,
+				X10:  x(band.P10), X25: x(band.P25), X50: x(band.P50), X75: x(band.P75), X90: x(band.P90),
+				BoxWidth: x(band.P75) - x(band.P25),
+			})
+		}
+		sections = append(sections, section)
+	}
+
+	return tmpl.Execute(w, struct {
+		*Benchmark
+		Sections []htmlSection
+		Width    float64
+	}{b, sections, chartWidth})
+}
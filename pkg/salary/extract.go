@@ -0,0 +1,220 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the salaryExtraction section of GlobalSettings
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// Budget caps the LLM calls per run. Defaults to 20.
+	Budget int `json:"budget,omitempty"`
+}
+
+// maxPromptDescription keeps prompts within small local models' context
+const maxPromptDescription = 8000
+
+const systemPrompt = `You extract the salary from job postings. Reply with a single JSON object and nothing else:
+{"min": 90000, "max": 120000, "currency": "USD", "period": "year"}
+Use plain numbers, an ISO 4217 currency code, and one of hour, day, week, month or year for period. Use the same number for min and max when one figure is given. When the posting states no salary, reply {"min": null}.`
+
+// Extractor fills in SalaryRange, asking the LLM only for postings Parse
+// cannot read. Answers, including "no salary", are cached by job ID so no
+// posting is sent twice.
+type Extractor struct {
+	config    Config
+	client    ai.LLMClient
+	cachePath string
+	logger    *logrus.Logger
+
+	mutex sync.Mutex
+	cache map[string]*models.SalaryRange
+}
+
+// New validates config, filling in what it leaves out from defaults, and
+// loads the cache at cachePath; a nil or disabled config returns a nil
+// Extractor
+func New(config *Config, defaults *ai.Defaults, cachePath string, logger *logrus.Logger) (*Extractor, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, false)
+	client, err := ai.NewClient(c.Config, "salary")
+	if err != nil {
+		return nil, err
+	}
+	if c.Budget <= 0 {
+		c.Budget = 20
+	}
+
+	extractor := &Extractor{
+		config:    c,
+		client:    client,
+		cachePath: cachePath,
+		logger:    logger,
+		cache:     make(map[string]*models.SalaryRange),
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read salary cache: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &extractor.cache); err != nil {
+			return nil, fmt.Errorf("failed to parse salary cache: %w", err)
+		}
+	}
+	return extractor, nil
+}
+
+// ExtractAll fills in SalaryRange for jobs without one, from Salary where
+// Parse can read it, else from the cache, else from the LLM while the run's
+// budget lasts. It returns how many jobs it filled in.
+func (e *Extractor) ExtractAll(ctx context.Context, jobs []models.Job) int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	filled := 0
+	var pending []int
+	for i := range jobs {
+		job := &jobs[i]
+		if job.SalaryRange != nil {
+			continue
+		}
+		if salary := Parse(job.Salary); salary != nil {
+			job.SalaryRange = salary
+			filled++
+			continue
+		}
+		if cached, ok := e.cache[job.ID]; ok {
+			if cached != nil {
+				salary := *cached
+				job.SalaryRange = &salary
+				filled++
+			}
+			continue
+		}
+		if strings.TrimSpace(job.Description) != "" {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) > e.config.Budget {
+		e.logger.Warnf("Extracting salaries for %d of %d jobs; the rest wait for a later run", e.config.Budget, len(pending))
+		pending = pending[:e.config.Budget]
+	}
+	if len(pending) == 0 {
+		return filled
+	}
+
+	asked := 0
+	for _, i := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		job := &jobs[i]
+		salary, err := e.Extract(ctx, job)
+		if err != nil {
+			e.logger.WithField("job_id", job.ID).Warnf("Failed to extract salary for %s: %v", job.Title, err)
+			continue
+		}
+		asked++
+		e.cache[job.ID] = salary
+		if salary != nil {
+			copied := *salary
+			job.SalaryRange = &copied
+			filled++
+		}
+	}
+
+	if asked > 0 {
+		if err := e.saveCache(); err != nil {
+			e.logger.Errorf("Failed to save salary cache: %v", err)
+		}
+	}
+	return filled
+}
+
+// Extract asks the LLM for one job's salary. It returns nil without an error
+// when the posting states none.
+func (e *Extractor) Extract(ctx context.Context, job *models.Job) (*models.SalaryRange, error) {
+	reply, err := e.client.Complete(ctx, systemPrompt, prompt(job))
+	if err != nil {
+		return nil, err
+	}
+	return parseReply(reply)
+}
+
+func prompt(job *models.Job) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Title: %s\nCompany: %s\n", job.Title, job.Company)
+	if job.Location != "" {
+		fmt.Fprintf(&text, "Location: %s\n", job.Location)
+	}
+	if job.Salary != "" {
+		fmt.Fprintf(&text, "Salary: %s\n", job.Salary)
+	}
+	fmt.Fprintf(&text, "\nDescription:\n%s", ai.Truncate(job.Description, maxPromptDescription))
+	return text.String()
+}
+
+// parseReply reads the salary JSON in a reply, rejecting figures the model
+// could not have meant
+func parseReply(reply string) (*models.SalaryRange, error) {
+	var answer struct {
+		Min      *float64 `json:"min"`
+		Max      *float64 `json:"max"`
+		Currency string   `json:"currency"`
+		Period   string   `json:"period"`
+	}
+	if err := ai.DecodeJSON(reply, &answer); err != nil {
+		return nil, err
+	}
+	if answer.Min == nil || *answer.Min <= 0 {
+		return nil, nil
+	}
+
+	salary := &models.SalaryRange{
+		Min:      *answer.Min,
+		Max:      *answer.Min,
+		Currency: strings.ToUpper(strings.TrimSpace(answer.Currency)),
+		Period:   strings.ToLower(strings.TrimSpace(answer.Period)),
+		Source:   models.SalarySourceLLM,
+	}
+	if answer.Max != nil && *answer.Max > 0 {
+		salary.Max = *answer.Max
+	}
+	if salary.Min > salary.Max {
+		salary.Min, salary.Max = salary.Max, salary.Min
+	}
+	if !models.ValidSalaryPeriod(salary.Period) {
+		return nil, fmt.Errorf("unknown salary period %q", answer.Period)
+	}
+	if len(salary.Currency) != 3 {
+		salary.Currency = ""
+	}
+	return salary, nil
+}
+
+func (e *Extractor) saveCache() error {
+	data, err := json.MarshalIndent(e.cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(e.cachePath+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(e.cachePath+".tmp", e.cachePath)
+}
@@ -0,0 +1,128 @@
+// Package salary turns free-text salaries into structured ranges: a regex
+// parser first, with an optional LLM pass over the description for postings
+// the parser cannot read.
+package salary
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+var (
+	figurePattern   = regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?)\s*(k\b|lakhs?\b|lpa\b|l\b|crores?\b|cr\b)?`)
+	currencyPattern = regexp.MustCompile(`\b(usd|eur|gbp|inr|cad|aud|sgd|chf|jpy|nzd|brl|sek|nok|dkk|pln|czk|zar|mxn|hkd|cny)\b`)
+)
+
+// currencySymbols are checked after ISO codes, so "CAD $90k" is CAD
+var currencySymbols = []struct {
+	symbol   string
+	currency string
+}{
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"₹", "INR"},
+	{"rs.", "INR"},
+	{"r$", "BRL"},
+	{"¥", "JPY"},
+	{"$", "USD"},
+}
+
+// Parse reads a salary such as "$90k - $120k", "£45,000 per annum",
+// "$60/hour" or "12-18 LPA". It returns nil when it finds no figure or
+// cannot tell the pay period.
+func Parse(text string) *models.SalaryRange {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil
+	}
+
+	var figures []float64
+	indian := false
+	for _, match := range figurePattern.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil || value == 0 {
+			continue
+		}
+		switch unit := match[2]; {
+		case unit == "k":
+			value *= 1000
+		case strings.HasPrefix(unit, "cr"):
+			value *= 10000000
+			indian = true
+		case unit != "":
+			value *= 100000
+			indian = true
+		}
+		figures = append(figures, value)
+		if len(figures) == 2 {
+			break
+		}
+	}
+	if len(figures) == 0 {
+		return nil
+	}
+	// "12-18 LPA" puts the unit on the last figure only
+	if indian && len(figures) == 2 && figures[0] < 1000 {
+		figures[0] *= 100000
+	}
+
+	salary := &models.SalaryRange{Min: figures[0], Max: figures[len(figures)-1], Source: models.SalarySourceParsed}
+	if salary.Min > salary.Max {
+		salary.Min, salary.Max = salary.Max, salary.Min
+	}
+
+	salary.Period = period(text)
+	if indian {
+		salary.Period = "year"
+	}
+	if salary.Period == "" {
+		// Without a stated period, only clearly hourly or yearly figures
+		// are safe to read
+		switch {
+		case salary.Max >= 10000:
+			salary.Period = "year"
+		case salary.Max <= 300:
+			salary.Period = "hour"
+		default:
+			return nil
+		}
+	}
+
+	salary.Currency = currency(text)
+	if salary.Currency == "" && indian {
+		salary.Currency = "INR"
+	}
+	return salary
+}
+
+func period(text string) string {
+	switch {
+	case strings.Contains(text, "hour") || strings.Contains(text, "/hr"):
+		return "hour"
+	case strings.Contains(text, "day") || strings.Contains(text, "daily"):
+		return "day"
+	case strings.Contains(text, "week"):
+		return "week"
+	case strings.Contains(text, "month") || strings.Contains(text, "/mo"):
+		return "month"
+	case strings.Contains(text, "year") || strings.Contains(text, "annum") ||
+		strings.Contains(text, "annual") || strings.Contains(text, "/yr") || strings.Contains(text, "p.a.") || strings.HasSuffix(text, " pa"):
+		return "year"
+	}
+	return ""
+}
+
+func currency(text string) string {
+	if match := currencyPattern.FindStringSubmatch(text); match != nil {
+		return strings.ToUpper(match[1])
+	}
+	for _, symbol := range currencySymbols {
+		if strings.Contains(text, symbol.symbol) {
+			return symbol.currency
+		}
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package salary
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+// DefaultRatesURL is the European Central Bank's daily reference rates,
+// published each working day around 16:00 CET
+const DefaultRatesURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// RatesConfig is the exchangeRates section of GlobalSettings. Salaries are
+// converted with a built-in table of approximate rates unless Live fetches
+// the ECB's daily rates, which are cached for MaxAge.
+type RatesConfig struct {
+	Live bool `json:"live"`
+	// URL serves rates in the ECB's eurofxref XML. Defaults to
+	// DefaultRatesURL.
+	URL string `json:"url,omitempty"`
+	// MaxAge is how long fetched rates are used before fetching again,
+	// e.g. "12h". Defaults to 24 hours.
+	MaxAge string `json:"maxAge,omitempty"`
+	// Rates fixes the units per euro of currencies, overriding both the
+	// built-in and the fetched rates
+	Rates map[string]float64 `json:"rates,omitempty"`
+}
+
+// RatesCache is what LoadRates saves of fetched rates
+type RatesCache struct {
+	// Date is the day the rates are for; FetchedAt is when they were got
+	Date      string             `json:"date"`
+	FetchedAt time.Time          `json:"fetchedAt"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+// LoadRates sets the exchange rates salaries are converted with. With Live
+// rates, the cache at cachePath is used while fresh and refreshed from the
+// ECB otherwise; a failed fetch falls back to the stale cache or the
+// built-in table with a warning, so scraping never stops for it. A nil
+// config keeps the built-in table.
+func LoadRates(ctx context.Context, config *RatesConfig, cachePath string, logger *logrus.Logger) error {
+	if config == nil {
+		return models.SetExchangeRates(nil)
+	}
+	rates := make(map[string]float64)
+	if config.Live {
+		live, err := liveRates(ctx, *config, cachePath, logger)
+		if err != nil {
+			return err
+		}
+		for currency, rate := range live {
+			rates[currency] = rate
+		}
+	}
+	for currency, rate := range config.Rates {
+		rates[currency] = rate
+	}
+	return models.SetExchangeRates(rates)
+}
+
+// liveRates returns the cached rates, fetching new ones when they are
+// older than MaxAge. Only a bad config is an error.
+func liveRates(ctx context.Context, config RatesConfig, cachePath string, logger *logrus.Logger) (map[string]float64, error) {
+	if config.URL == "" {
+		config.URL = DefaultRatesURL
+	}
+	maxAge := 24 * time.Hour
+	if config.MaxAge != "" {
+		parsed, err := time.ParseDuration(config.MaxAge)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid exchangeRates maxAge %q", config.MaxAge)
+		}
+		maxAge = parsed
+	}
+
+	cache, err := readRatesCache(cachePath)
+	if err != nil {
+		logger.Warnf("Ignoring exchange rates cache: %v", err)
+	}
+	if cache != nil && (time.Since(cache.FetchedAt) < maxAge || transport.Offline()) {
+		return cache.Rates, nil
+	}
+	if transport.Offline() {
+		return nil, nil
+	}
+
+	fetched, err := FetchRates(ctx, config.URL)
+	if err != nil {
+		if cache != nil {
+			logger.Warnf("Failed to fetch exchange rates, using those of %s: %v", cache.Date, err)
+			return cache.Rates, nil
+		}
+		logger.Warnf("Failed to fetch exchange rates, using built-in rates: %v", err)
+		return nil, nil
+	}
+	logger.Infof("Fetched %d exchange rates for %s", len(fetched.Rates), fetched.Date)
+	if err := writeRatesCache(cachePath, fetched); err != nil {
+		logger.Warnf("Failed to cache exchange rates: %v", err)
+	}
+	return fetched.Rates, nil
+}
+
+// ecbEnvelope is the eurofxref XML: a Cube of the day's Cube with one Cube
+// per currency
+type ecbEnvelope struct {
+	Cube struct {
+		Day struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// FetchRates downloads reference rates in the ECB's eurofxref XML
+func FetchRates(ctx context.Context, ratesURL string) (*RatesCache, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ratesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := transport.NewClient(15 * time.Second).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rates service returned status: %d", resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse rates: %w", err)
+	}
+	cache := &RatesCache{Date: envelope.Cube.Day.Time, FetchedAt: time.Now().UTC(), Rates: make(map[string]float64)}
+	for _, rate := range envelope.Cube.Day.Rates {
+		value, err := strconv.ParseFloat(rate.Rate, 64)
+		if err != nil || value <= 0 || rate.Currency == "" {
+			continue
+		}
+		cache.Rates[strings.ToUpper(rate.Currency)] = value
+	}
+	if len(cache.Rates) == 0 {
+		return nil, fmt.Errorf("rates service returned no rates")
+	}
+	return cache, nil
+}
+
+func readRatesCache(path string) (*RatesCache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cache RatesCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cache, nil
+}
+
+func writeRatesCache(path string, cache *RatesCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+var amountPattern = regexp.MustCompile(`^([a-z]{3})?\s*(\d[\d,]*(?:\.\d+)?)\s*(k)?\s*([a-z]{3})?$`)
+
+// ParseAmount reads a salary bound such as "80000", "80k", "80000 USD" or
+// "GBP 60k", returning the currency in upper case or empty when none is
+// given
+func ParseAmount(text string) (amount int, currency string, err error) {
+	match := amountPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(text)))
+	if match == nil || (match[1] != "" && match[4] != "") {
+		return 0, "", fmt.Errorf("invalid salary %q (use e.g. 80000, 80k or 80000 USD)", text)
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(match[2], ",", ""), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid salary %q", text)
+	}
+	if match[3] != "" {
+		value *= 1000
+	}
+	currency = strings.ToUpper(match[1] + match[4])
+	if currency != "" && !models.KnownCurrency(currency) {
+		return 0, "", fmt.Errorf("unknown currency %s (known: %s)", currency, strings.Join(models.Currencies(), ", "))
+	}
+	return int(value), currency, nil
+}
+
+// ParseBounds reads a minimum and maximum salary as ParseAmount does,
+// either of which may be empty, and returns the one currency they give
+func ParseBounds(minText, maxText string) (min, max int, currency string, err error) {
+	var minCurrency, maxCurrency string
+	if strings.TrimSpace(minText) != "" {
+		if min, minCurrency, err = ParseAmount(minText); err != nil {
+			return 0, 0, "", err
+		}
+	}
+	if strings.TrimSpace(maxText) != "" {
+		if max, maxCurrency, err = ParseAmount(maxText); err != nil {
+			return 0, 0, "", err
+		}
+	}
+	if minCurrency != "" && maxCurrency != "" && minCurrency != maxCurrency {
+		return 0, 0, "", fmt.Errorf("minimum and maximum salary are in different currencies (%s, %s)", minCurrency, maxCurrency)
+	}
+	currency = minCurrency
+	if currency == "" {
+		currency = maxCurrency
+	}
+	return min, max, currency, nil
+}
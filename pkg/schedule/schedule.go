@@ -0,0 +1,97 @@
+// Package schedule parses the cron expressions of scheduled scrapes and
+// works out when each is next due.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Entry is one scheduled scrape in the schedules list of GlobalSettings
+type Entry struct {
+	// Name tells the entry apart in logs and the run history
+	Name string `json:"name"`
+	// Cron is a five-field expression or descriptor such as @daily,
+	// evaluated in the display timezone
+	Cron string `json:"cron"`
+	// Keywords and Location are searched as by scrape -keywords and
+	// -location; empty ones fall back the same way
+	Keywords []string `json:"keywords,omitempty"`
+	Location string   `json:"location,omitempty"`
+	// Resume is a resume file to derive keywords from and boost matching
+	// jobs with, as by scrape -resume
+	Resume string `json:"resume,omitempty"`
+	// Disabled entries are kept in the config but never run
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Schedule is an entry with its parsed expression and next due time
+type Schedule struct {
+	Entry
+	Cron *Cron
+	Next time.Time
+}
+
+// Parse validates entries and returns the enabled ones as schedules due at
+// their first time after now, in now's location
+func Parse(entries []Entry, now time.Time) ([]*Schedule, error) {
+	names := make(map[string]bool, len(entries))
+	var schedules []*Schedule
+	for i, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("schedule %d: name is required", i+1)
+		}
+		if names[entry.Name] {
+			return nil, fmt.Errorf("schedule %q: name is used twice", entry.Name)
+		}
+		names[entry.Name] = true
+
+		cron, err := ParseCron(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", entry.Name, err)
+		}
+		next := cron.Next(now)
+		if next.IsZero() {
+			return nil, fmt.Errorf("schedule %q: cron %q never matches", entry.Name, entry.Cron)
+		}
+		if !entry.Disabled {
+			schedules = append(schedules, &Schedule{Entry: entry, Cron: cron, Next: next})
+		}
+	}
+	return schedules, nil
+}
+
+// Due returns the schedules due at or before now, earliest first
+func Due(schedules []*Schedule, now time.Time) []*Schedule {
+	var due []*Schedule
+	for _, s := range schedules {
+		if !s.Next.After(now) {
+			due = append(due, s)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Next.Before(due[j].Next) })
+	return due
+}
+
+// NextDue returns the earliest next due time of schedules
+func NextDue(schedules []*Schedule) time.Time {
+	var next time.Time
+	for _, s := range schedules {
+		if next.IsZero() || s.Next.Before(next) {
+			next = s.Next
+		}
+	}
+	return next
+}
+
+// Advance moves s to its first time after now, returning how many due
+// times it skipped on the way, which passed while an earlier run went on
+func (s *Schedule) Advance(now time.Time) int {
+	skipped := 0
+	for next := s.Cron.Next(s.Next); !next.IsZero() && !next.After(now); next = s.Cron.Next(next) {
+		skipped++
+	}
+	s.Next = s.Cron.Next(now)
+	return skipped
+}
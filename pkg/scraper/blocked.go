@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+// proxyError reports a scrape that another proxy may fix: the site blocked
+// the exit IP, or the request never got through the proxy
+type proxyError struct {
+	proxy  string // proxy URL the request went through, empty when direct
+	status int    // blocking HTTP status, or 0 when the connection failed
+	err    error
+}
+
+func (e *proxyError) Error() string {
+	if e.status != 0 {
+		return fmt.Sprintf("blocked by the site (status %d)", e.status)
+	}
+	return fmt.Sprintf("proxy connection failed: %v", e.err)
+}
+
+func (e *proxyError) Unwrap() error {
+	return e.err
+}
+
+// Is matches the cause the blocking status signals, errs.ErrBlocked or
+// errs.ErrRateLimited
+func (e *proxyError) Is(target error) bool {
+	cause := errs.SiteStatus(e.status)
+	return cause != nil && target == cause
+}
+
+// isBlockStatus reports whether an HTTP status looks like the site blocking
+// the client rather than a missing or broken page
+func isBlockStatus(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// isNavigationError reports whether a browser error means the page never
+// loaded, such as net::ERR_PROXY_CONNECTION_FAILED
+func isNavigationError(err error) bool {
+	return strings.Contains(err.Error(), "net::ERR_")
+}
+
+// redactProxy hides the password in a proxy URL for logs
+func redactProxy(proxyURL string) string {
+	parsed, err := neturl.Parse(proxyURL)
+	if err != nil {
+		return "proxy"
+	}
+	return parsed.Redacted()
+}
+
+// retryThroughProxies runs scrape, and while it fails through a proxy
+// reports the failure to the proxy manager, which demotes the proxy or
+// starts a new gateway session, and tries again, up to RetryAttempts more
+// times
+func (sc *ScraperCore) retryThroughProxies(board JobBoard, searchURL string, log *logrus.Entry, scrape func() ([]models.Job, error)) ([]models.Job, error) {
+	host := ""
+	if target, err := neturl.Parse(searchURL); err == nil {
+		host = target.Hostname()
+	}
+
+	for attempt := 1; ; attempt++ {
+		jobs, err := scrape()
+		var failure *proxyError
+		if err == nil || sc.proxyManager == nil || !errors.As(err, &failure) || failure.proxy == "" {
+			return jobs, err
+		}
+		retry := sc.proxyManager.ReportFailure(board.Name, host, failure.proxy, failure.status != 0)
+		if !retry || attempt > sc.config.GlobalSettings.RetryAttempts {
+			return jobs, err
+		}
+		log.Warnf("%s failed through %s (%v); retrying through another proxy", board.Name, redactProxy(failure.proxy), failure)
+	}
+}
@@ -0,0 +1,213 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// DefaultMinCoverage is the share of containers that must yield a title,
+// company and link for a board's selectors to pass
+const DefaultMinCoverage = 0.8
+
+// requiredFields are the fields a scrape cannot keep a job without, or that
+// make the job useless when missing
+var requiredFields = []string{"title", "company", "link"}
+
+// SelectorTest is how a board's selectors fared on one page, its fixture
+// or the live search page
+type SelectorTest struct {
+	Board string `json:"board"`
+	// Page is fixture or live, and Source the fixture's path or the URL
+	Page   string `json:"page"`
+	Source string `json:"source"`
+	// Containers is the number of elements jobContainer matched, and
+	// Coverage the share of them each configured field was found in
+	Containers int                `json:"containers"`
+	Coverage   map[string]float64 `json:"coverage,omitempty"`
+	// Jobs is the number of containers a scrape would keep, those with a
+	// title and company
+	Jobs     int      `json:"jobs"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Passed reports whether the selectors found jobs with every required field
+// often enough
+func (t SelectorTest) Passed() bool {
+	return len(t.Problems) == 0
+}
+
+// LintBoard returns what is wrong with a board entry without fetching
+// anything: validation errors, selectors that do not parse and fields a
+// scrape needs that have no selector
+func LintBoard(board JobBoard) []string {
+	var problems []string
+	if err := ValidateBoard(board); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if board.ScrapingMethod == "rss" {
+		return problems
+	}
+
+	for _, field := range selectorFields(board.Selectors) {
+		if field.selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(field.selector); err != nil {
+			problems = append(problems, fmt.Sprintf("selectors.%s %q does not parse: %v", field.name, field.selector, err))
+		}
+	}
+	pageSelectors := []selectorField{{"selectors.nextPage", board.Selectors.NextPage}}
+	if board.Details != nil {
+		pageSelectors = append(pageSelectors,
+			selectorField{"details.description", board.Details.Description},
+			selectorField{"details.salary", board.Details.Salary},
+			selectorField{"details.postedDate", board.Details.PostedDate})
+	}
+	for _, field := range pageSelectors {
+		if field.selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(field.selector); err != nil {
+			problems = append(problems, fmt.Sprintf("%s %q does not parse: %v", field.name, field.selector, err))
+		}
+	}
+	if board.Selectors.Title == "" {
+		problems = append(problems, "selectors.title is empty, so no job is kept")
+	}
+	if board.Selectors.Company == "" && board.CompanyName == "" {
+		problems = append(problems, "neither selectors.company nor companyName is set, so no job is kept")
+	}
+	if board.Selectors.Link == "" {
+		problems = append(problems, "selectors.link is empty, so jobs have no links")
+	}
+	return problems
+}
+
+// TestSelectors runs a board's selectors over a page the way a scrape does
+// and reports the share of containers each field was found in. Title,
+// company and link must reach minCoverage to pass.
+func TestSelectors(board JobBoard, page, source string, body []byte, minCoverage float64) SelectorTest {
+	test := SelectorTest{Board: board.Name, Page: page, Source: source}
+	if board.ScrapingMethod == "rss" {
+		test.Problems = append(test.Problems, "rss boards have no selectors to test")
+		return test
+	}
+	body, _ = decodeBody(body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		test.Problems = append(test.Problems, fmt.Sprintf("failed to parse HTML: %v", err))
+		return test
+	}
+	if board.Selectors.JobContainer == "" {
+		test.Problems = append(test.Problems, "selectors.jobContainer is empty")
+		return test
+	}
+
+	containers := doc.Find(board.Selectors.JobContainer)
+	test.Containers = containers.Length()
+	if test.Containers == 0 {
+		test.Problems = append(test.Problems, fmt.Sprintf("jobContainer %q matched nothing", board.Selectors.JobContainer))
+		return test
+	}
+
+	hits := make(map[string]int)
+	containers.Each(func(_ int, container *goquery.Selection) {
+		found := make(map[string]bool)
+		for _, field := range selectorFields(board.Selectors) {
+			if field.name == "jobContainer" || field.selector == "" {
+				continue
+			}
+			target := container.Find(field.selector)
+			if field.name == "link" {
+				href, _ := target.Attr("href")
+				found["link"] = strings.TrimSpace(href) != ""
+			} else {
+				found[field.name] = strings.TrimSpace(target.Text()) != ""
+			}
+		}
+		if board.Selectors.Company == "" && board.CompanyName != "" {
+			found["company"] = true
+		}
+		for name, ok := range found {
+			if ok {
+				hits[name]++
+			}
+		}
+		if found["title"] && found["company"] {
+			test.Jobs++
+		}
+	})
+
+	test.Coverage = make(map[string]float64)
+	for _, field := range selectorFields(board.Selectors) {
+		if field.name == "jobContainer" {
+			continue
+		}
+		if field.selector != "" || (field.name == "company" && board.CompanyName != "") {
+			test.Coverage[field.name] = float64(hits[field.name]) / float64(test.Containers)
+		}
+	}
+	for _, name := range requiredFields {
+		coverage, ok := test.Coverage[name]
+		switch {
+		case !ok:
+			test.Problems = append(test.Problems, fmt.Sprintf("no %s selector", name))
+		case coverage < minCoverage:
+			test.Problems = append(test.Problems, fmt.Sprintf("%s found in %.0f%% of containers, below %.0f%%", name, coverage*100, minCoverage*100))
+		}
+	}
+	return test
+}
+
+// FixturePath is where a board's saved page is kept: its fixture setting,
+// or fixtures/<name>.html, relative to the config file's directory
+func FixturePath(configPath string, board JobBoard) string {
+	path := board.Fixture
+	if path == "" {
+		path = filepath.Join("fixtures", board.Name+".html")
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
+// FetchSearchPage downloads a board's search results page for keywords
+// and location, within its domain's rate limit and request budget, and
+// returns its URL and body. Boards scraped with a browser are fetched
+// without running their scripts.
+func (sc *ScraperCore) FetchSearchPage(ctx context.Context, board JobBoard, keywords, location string) (string, []byte, error) {
+	url := sc.buildSearchURL(board, keywords, location)
+	if err := sc.limiters.wait(ctx, url, sc.requestInterval(board)); err != nil {
+		return url, nil, err
+	}
+	body, _, err := fetchPage(ctx, sc.client, url, sc.config.GlobalSettings.UserAgent)
+	return url, body, err
+}
+
+// RequiresJavaScript reports whether scrapes render the board in a browser
+func (sc *ScraperCore) RequiresJavaScript(board JobBoard) bool {
+	return sc.requiresJavaScript(board)
+}
+
+type selectorField struct {
+	name, selector string
+}
+
+func selectorFields(selectors Selectors) []selectorField {
+	return []selectorField{
+		{"jobContainer", selectors.JobContainer},
+		{"title", selectors.Title},
+		{"company", selectors.Company},
+		{"location", selectors.Location},
+		{"salary", selectors.Salary},
+		{"description", selectors.Description},
+		{"link", selectors.Link},
+	}
+}
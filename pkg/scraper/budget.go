@@ -0,0 +1,181 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+// budgetWindow is the span a domain's request budget covers
+const budgetWindow = time.Hour
+
+// BudgetError reports a request held back because its domain's hourly
+// budget is spent. It matches errs.ErrBudgetSpent.
+type BudgetError struct {
+	Domain string
+	Limit  int
+	// Resets is when the oldest counted request leaves the window
+	Resets time.Time
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("request budget of %s (%d an hour) spent until %s",
+		e.Domain, e.Limit, models.InDisplayZone(e.Resets).Format("15:04"))
+}
+
+func (e *BudgetError) Is(target error) bool {
+	return target == errs.ErrBudgetSpent
+}
+
+// requestBudgets caps the requests to each domain in any hour. Every
+// request counts, whichever board, retry or posting fetch makes it, and
+// the times are saved so separate runs share the budget.
+type requestBudgets struct {
+	perHour   int
+	perDomain map[string]int
+
+	mutex sync.Mutex
+	path  string
+	// spent holds, oldest first, the times of each domain's requests
+	// within the window
+	spent map[string][]time.Time
+}
+
+func newRequestBudgets(perHour int, perDomain map[string]int) *requestBudgets {
+	limits := make(map[string]int, len(perDomain))
+	for domain, limit := range perDomain {
+		limits[budgetDomain(domain)] = limit
+	}
+	return &requestBudgets{perHour: perHour, perDomain: limits, spent: make(map[string][]time.Time)}
+}
+
+// limit returns the hourly budget of domain, or 0 when it has none
+func (b *requestBudgets) limit(domain string) int {
+	if limit, ok := b.perDomain[domain]; ok {
+		return limit
+	}
+	return b.perHour
+}
+
+// spend counts a request to rawURL, or returns a *BudgetError when its
+// domain's budget for the last hour is used up
+func (b *requestBudgets) spend(rawURL string) error {
+	domain := hostOf(rawURL)
+	if domain == "" {
+		return nil
+	}
+	limit := b.limit(domain)
+	if limit <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	spent := recent(b.spent[domain], now)
+	if len(spent) >= limit {
+		b.spent[domain] = spent
+		return &BudgetError{Domain: domain, Limit: limit, Resets: spent[len(spent)-limit].Add(budgetWindow)}
+	}
+	b.spent[domain] = append(spent, now)
+	return nil
+}
+
+// recent drops the times that have left the window
+func recent(times []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-budgetWindow)
+	expired := sort.Search(len(times), func(i int) bool { return times[i].After(cutoff) })
+	return times[expired:]
+}
+
+// track loads the request times saved at path, which save writes back
+func (b *requestBudgets) track(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	saved := make(map[string][]time.Time)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return err
+		}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.path = path
+	for domain, times := range saved {
+		times = append(times, b.spent[domain]...)
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		b.spent[domain] = times
+	}
+	return nil
+}
+
+// save writes the request times still within the window to the file given
+// to track
+func (b *requestBudgets) save() error {
+	b.mutex.Lock()
+	path := b.path
+	now := time.Now()
+	saved := make(map[string][]time.Time, len(b.spent))
+	for domain, times := range b.spent {
+		if times = recent(times, now); len(times) > 0 {
+			saved[domain] = times
+		}
+	}
+	b.mutex.Unlock()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// hostOf returns the domain budgets and limiters key rawURL by
+func hostOf(rawURL string) string {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return budgetDomain(parsed.Hostname())
+}
+
+func budgetDomain(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "www.")
+}
+
+// TrackRequestBudgets shares the domains' hourly request budgets with
+// earlier runs through the file at path
+func (sc *ScraperCore) TrackRequestBudgets(path string) error {
+	return sc.limiters.budgets.track(path)
+}
+
+// SaveRequestBudgets writes the requests made in the last hour to the file
+// given to TrackRequestBudgets
+func (sc *ScraperCore) SaveRequestBudgets() error {
+	return sc.limiters.budgets.save()
+}
+
+// SpendRequest counts a request to rawURL made outside a scrape, such as
+// fetching a single posting, against its domain's budget. It returns a
+// *BudgetError, and the request should not be made, when none is left.
+func (sc *ScraperCore) SpendRequest(rawURL string) error {
+	return sc.limiters.budgets.spend(rawURL)
+}
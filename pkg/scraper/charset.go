@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"unicode/utf8"
+
+	"github.com/saintfish/chardet"
+	"golang.org/x/net/html/charset"
+)
+
+// minCharsetConfidence is the chardet confidence, out of 100, needed to
+// trust a guessed encoding over windows-1252
+const minCharsetConfidence = 50
+
+// decodeBody returns body as UTF-8. colly only transcodes pages whose
+// Content-Type names a charset, so pages declaring theirs in a <meta> tag,
+// or not at all, reach the selectors undecoded. Bodies that are already
+// valid UTF-8 are returned as they are; others are decoded by their byte
+// order mark or <meta> charset, or failing that by a statistical guess.
+func decodeBody(body []byte) ([]byte, string) {
+	if utf8.Valid(body) {
+		return body, "utf-8"
+	}
+
+	encoding, name, _ := charset.DetermineEncoding(body, "")
+	// windows-1252 is both a common declaration and the fallback when
+	// nothing is declared, so a confident guess overrides it
+	if name == "windows-1252" {
+		if guess, err := chardet.NewTextDetector().DetectBest(body); err == nil && guess.Confidence >= minCharsetConfidence {
+			if guessed, guessedName := charset.Lookup(guess.Charset); guessed != nil {
+				encoding, name = guessed, guessedName
+			}
+		}
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, "utf-8"
+	}
+	return decoded, name
+}
@@ -0,0 +1,483 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/language"
+
+	"hire.ai/pkg/api"
+)
+
+// Errors returned when editing boards and providers. Validation failures
+// wrap ErrInvalidConfig.
+var (
+	ErrInvalidConfig    = errors.New("invalid config")
+	ErrBoardNotFound    = errors.New("board not found")
+	ErrProviderNotFound = errors.New("provider not found")
+)
+
+// ValidateBoard checks that a board entry can be scraped
+func ValidateBoard(board JobBoard) error {
+	if err := validateName(board.Name); err != nil {
+		return err
+	}
+	if board.RateLimit < 0 || board.MaxResults < 0 || board.MaxPages < 0 {
+		return invalid("rateLimit, maxResults and maxPages cannot be negative")
+	}
+
+	if scroll := board.Scroll; scroll != nil && (scroll.MaxScrolls < 0 || scroll.ScrollDelayMs < 0 || scroll.IdleScrolls < 0) {
+		return invalid("scroll.maxScrolls, scroll.scrollDelayMs and scroll.idleScrolls cannot be negative")
+	}
+
+	if details := board.Details; details != nil && details.Concurrency < 0 {
+		return invalid("details.concurrency cannot be negative")
+	}
+	if board.Locale != "" {
+		if _, err := language.Parse(board.Locale); err != nil {
+			return invalid("locale %q is not a language tag", board.Locale)
+		}
+	}
+	if board.Charset != "" {
+		if encoding, _ := charset.Lookup(board.Charset); encoding == nil {
+			return invalid("unknown charset %q", board.Charset)
+		}
+	}
+
+	switch board.ScrapingMethod {
+	case "", "scraping":
+		if err := validateURL("baseUrl", board.BaseURL); err != nil {
+			return err
+		}
+		if board.Selectors.JobContainer == "" {
+			return invalid("selectors.jobContainer is required")
+		}
+	case "rss":
+		if board.RSSConfig == nil {
+			return invalid("rssConfig is required for rss boards")
+		}
+		if err := validateURL("rssConfig.feedUrl", board.RSSConfig.FeedURL); err != nil {
+			return err
+		}
+		switch board.RSSConfig.FeedType {
+		case "", "rss", "atom":
+		default:
+			return invalid("rssConfig.feedType must be rss or atom")
+		}
+	case "api":
+		return invalid("api boards are no longer supported; configure an API provider instead")
+	default:
+		return invalid("unknown scrapingMethod %q (use scraping or rss)", board.ScrapingMethod)
+	}
+
+	return nil
+}
+
+// ValidateProvider checks that an API provider entry can be created
+func ValidateProvider(config api.APIConfig) error {
+	if err := validateName(config.Name); err != nil {
+		return err
+	}
+
+	supported := false
+	for _, provider := range api.SupportedProviders {
+		if config.Provider == provider {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return invalid("unknown provider %q (use %s)", config.Provider, strings.Join(api.SupportedProviders, ", "))
+	}
+
+	if config.BaseURL != "" {
+		if err := validateURL("base_url", config.BaseURL); err != nil {
+			return err
+		}
+	}
+	if config.MaxResults < 0 {
+		return invalid("max_results cannot be negative")
+	}
+
+	durations := map[string]string{
+		"timeout":                    config.Timeout,
+		"rate_limit.cooldown_period": config.RateLimit.CooldownPeriod,
+		"retry_config.initial_wait":  config.RetryConfig.InitialWait,
+		"retry_config.max_wait":      config.RetryConfig.MaxWait,
+	}
+	for field, value := range durations {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return invalid("%s %q is not a duration", field, value)
+		}
+	}
+
+	return nil
+}
+
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return invalid("name is required")
+	}
+	if name != strings.TrimSpace(name) || strings.Contains(name, "/") {
+		return invalid("name %q cannot contain slashes or surrounding spaces", name)
+	}
+	return nil
+}
+
+func validateURL(field, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return invalid("%s must be an absolute http or https URL", field)
+	}
+	return nil
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, fmt.Sprintf(format, args...))
+}
+
+// Board returns the named board's config as the config file has it, with
+// ${VAR} references and HIREAI_ overrides left unapplied, so it can be
+// edited and saved back without writing out the values they stand for
+func (sc *ScraperCore) Board(name string) (JobBoard, error) {
+	sc.configMutex.RLock()
+	defer sc.configMutex.RUnlock()
+
+	var boards []JobBoard
+	if err := readConfigList(sc.configPath, "jobBoards", &boards); err != nil {
+		return JobBoard{}, err
+	}
+	for _, board := range boards {
+		if board.Name == name {
+			return board, nil
+		}
+	}
+	return JobBoard{}, fmt.Errorf("%w: %s", ErrBoardNotFound, name)
+}
+
+// SaveBoard validates board, writes it to the config file in place of any
+// board with the same name and applies it to the running scraper, with its
+// ${VAR} references expanded and HIREAI_ overrides applied as at startup.
+// Scrapes already in progress keep the boards they started with. created
+// reports whether the board is new.
+func (sc *ScraperCore) SaveBoard(board JobBoard) (created bool, err error) {
+	applied, err := applyBoardEnv(board)
+	if err != nil {
+		return false, invalid("%v", err)
+	}
+	if err := ValidateBoard(applied); err != nil {
+		return false, err
+	}
+
+	sc.configMutex.Lock()
+	defer sc.configMutex.Unlock()
+
+	err = editConfigList(sc.configPath, "jobBoards", func(entries []json.RawMessage) ([]json.RawMessage, error) {
+		return putConfigEntry(entries, board.Name, board)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	boards := make([]JobBoard, 0, len(sc.config.JobBoards)+1)
+	created = true
+	for _, existing := range sc.config.JobBoards {
+		if existing.Name == board.Name {
+			existing = applied
+			created = false
+		}
+		boards = append(boards, existing)
+	}
+	if created {
+		boards = append(boards, applied)
+	}
+	sc.config.JobBoards = boards
+
+	sc.logger.Infof("Saved board %s (enabled: %v)", board.Name, board.Enabled)
+	return created, nil
+}
+
+// DeleteBoard removes the named board from the config file and the running
+// scraper
+func (sc *ScraperCore) DeleteBoard(name string) error {
+	sc.configMutex.Lock()
+	defer sc.configMutex.Unlock()
+
+	boards := make([]JobBoard, 0, len(sc.config.JobBoards))
+	for _, board := range sc.config.JobBoards {
+		if board.Name != name {
+			boards = append(boards, board)
+		}
+	}
+	if len(boards) == len(sc.config.JobBoards) {
+		return fmt.Errorf("%w: %s", ErrBoardNotFound, name)
+	}
+
+	if err := editConfigList(sc.configPath, "jobBoards", func(entries []json.RawMessage) ([]json.RawMessage, error) {
+		return removeConfigEntry(entries, name), nil
+	}); err != nil {
+		return err
+	}
+	sc.config.JobBoards = boards
+
+	sc.logger.Infof("Deleted board %s", name)
+	return nil
+}
+
+// Providers returns the API provider configs as the config file has them,
+// like Board
+func (sc *ScraperCore) Providers() ([]api.APIConfig, error) {
+	sc.configMutex.RLock()
+	defer sc.configMutex.RUnlock()
+
+	var providers []api.APIConfig
+	if err := readConfigList(sc.configPath, "apiProviders", &providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// Provider returns the named API provider's config as the config file has
+// it, like Board
+func (sc *ScraperCore) Provider(name string) (api.APIConfig, error) {
+	providers, err := sc.Providers()
+	if err != nil {
+		return api.APIConfig{}, err
+	}
+	for _, provider := range providers {
+		if provider.Name == name {
+			return provider, nil
+		}
+	}
+	return api.APIConfig{}, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+}
+
+// SaveProvider validates config, writes it to the config file in place of
+// any provider with the same name and re-registers the API providers, with
+// references and overrides applied as SaveBoard does. Empty api_key and
+// secret_key keep the values already in the file, so callers never need to
+// echo secrets back. created reports whether the provider is new.
+func (sc *ScraperCore) SaveProvider(config api.APIConfig) (created bool, err error) {
+	provider, err := applyProviderEnv(config)
+	if err != nil {
+		return false, invalid("%v", err)
+	}
+	if err := ValidateProvider(provider); err != nil {
+		return false, err
+	}
+
+	sc.configMutex.Lock()
+	defer sc.configMutex.Unlock()
+
+	if config.Enabled {
+		for _, existing := range sc.config.APIProviders {
+			if existing.Name != config.Name && existing.Enabled && existing.Provider == config.Provider {
+				return false, invalid("provider %s already uses %s; disable it first", existing.Name, config.Provider)
+			}
+		}
+	}
+
+	stored := config
+	err = editConfigList(sc.configPath, "apiProviders", func(entries []json.RawMessage) ([]json.RawMessage, error) {
+		for _, entry := range entries {
+			if configEntryName(entry) != config.Name {
+				continue
+			}
+			var existing api.APIConfig
+			if json.Unmarshal(entry, &existing) == nil {
+				if stored.APIKey == "" {
+					stored.APIKey = existing.APIKey
+				}
+				if stored.SecretKey == "" {
+					stored.SecretKey = existing.SecretKey
+				}
+			}
+		}
+		return putConfigEntry(entries, stored.Name, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// The kept keys may be references too
+	if provider, err = applyProviderEnv(stored); err != nil {
+		return false, invalid("%v", err)
+	}
+	applied := []api.APIConfig{provider}
+	loadAPIKeysFromEnv(applied, sc.logger)
+
+	providers := make([]api.APIConfig, 0, len(sc.config.APIProviders)+1)
+	created = true
+	for _, existing := range sc.config.APIProviders {
+		if existing.Name == config.Name {
+			existing = applied[0]
+			created = false
+		}
+		providers = append(providers, existing)
+	}
+	if created {
+		providers = append(providers, applied[0])
+	}
+
+	if err := sc.applyProviders(providers); err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// DeleteProvider removes the named API provider from the config file and
+// unregisters it
+func (sc *ScraperCore) DeleteProvider(name string) error {
+	sc.configMutex.Lock()
+	defer sc.configMutex.Unlock()
+
+	providers := make([]api.APIConfig, 0, len(sc.config.APIProviders))
+	for _, provider := range sc.config.APIProviders {
+		if provider.Name != name {
+			providers = append(providers, provider)
+		}
+	}
+	if len(providers) == len(sc.config.APIProviders) {
+		return fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+
+	if err := editConfigList(sc.configPath, "apiProviders", func(entries []json.RawMessage) ([]json.RawMessage, error) {
+		return removeConfigEntry(entries, name), nil
+	}); err != nil {
+		return err
+	}
+	return sc.applyProviders(providers)
+}
+
+// applyProviders swaps in a new provider list; callers hold configMutex
+func (sc *ScraperCore) applyProviders(providers []api.APIConfig) error {
+	sc.config.APIProviders = providers
+	if err := api.ReloadProviders(sc.apiManager, providers); err != nil {
+		return fmt.Errorf("config saved but providers were not reloaded: %w", err)
+	}
+	return nil
+}
+
+// AppendBoard adds a job board entry to the config file at configPath
+func AppendBoard(configPath string, board JobBoard) error {
+	if err := ValidateBoard(board); err != nil {
+		return err
+	}
+
+	return editConfigList(configPath, "jobBoards", func(entries []json.RawMessage) ([]json.RawMessage, error) {
+		for _, entry := range entries {
+			if configEntryName(entry) == board.Name {
+				return nil, fmt.Errorf("board %s already exists in %s", board.Name, configPath)
+			}
+		}
+		return putConfigEntry(entries, board.Name, board)
+	})
+}
+
+// readConfigList decodes one top-level list of the config file at
+// configPath into v
+func readConfigList(configPath, section string, v interface{}) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if existing, ok := raw[section]; ok {
+		if err := json.Unmarshal(existing, v); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", section, err)
+		}
+	}
+	return nil
+}
+
+// editConfigList rewrites one top-level list of the config file at
+// configPath. The file is decoded generically so sections this package does
+// not model survive the rewrite.
+func editConfigList(configPath, section string, edit func([]json.RawMessage) ([]json.RawMessage, error)) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	var entries []json.RawMessage
+	if existing, ok := raw[section]; ok {
+		if err := json.Unmarshal(existing, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", section, err)
+		}
+	}
+
+	if entries, err = edit(entries); err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []json.RawMessage{}
+	}
+
+	if raw[section], err = json.Marshal(entries); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", section, err)
+	}
+
+	output, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	// Write through a temporary file so a crash never leaves half a config
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, append(output, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return os.Rename(tmpPath, configPath)
+}
+
+// putConfigEntry replaces the entry named name with value, or appends it
+func putConfigEntry(entries []json.RawMessage, name string, value interface{}) ([]json.RawMessage, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	for i, entry := range entries {
+		if configEntryName(entry) == name {
+			entries[i] = encoded
+			return entries, nil
+		}
+	}
+	return append(entries, encoded), nil
+}
+
+func removeConfigEntry(entries []json.RawMessage, name string) []json.RawMessage {
+	kept := entries[:0]
+	for _, entry := range entries {
+		if configEntryName(entry) != name {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+func configEntryName(entry json.RawMessage) string {
+	var named struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal(entry, &named)
+	return named.Name
+}
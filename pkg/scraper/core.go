@@ -2,25 +2,49 @@ package scraper
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
+	neturl "net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/gocolly/colly/v2"
-	"github.com/gocolly/colly/v2/debug"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 
+	"hire.ai/pkg/ai"
 	"hire.ai/pkg/api"
+	"hire.ai/pkg/ask"
+	"hire.ai/pkg/classify"
+	"hire.ai/pkg/dedup"
+	"hire.ai/pkg/enrich"
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/events"
+	"hire.ai/pkg/expiry"
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/hooks"
 	"hire.ai/pkg/models"
+	"hire.ai/pkg/notify"
+	"hire.ai/pkg/prep"
 	"hire.ai/pkg/proxy"
+	"hire.ai/pkg/relevance"
 	"hire.ai/pkg/rss"
+	"hire.ai/pkg/salary"
+	"hire.ai/pkg/schedule"
+	"hire.ai/pkg/semantic"
+	"hire.ai/pkg/summarize"
+	"hire.ai/pkg/transport"
 )
 
 type JobBoard struct {
@@ -36,6 +60,34 @@ type JobBoard struct {
 	ScrapingMethod string           `json:"scrapingMethod,omitempty"` // "scraping", "api", "rss"
 	APIConfig      *api.APIJobBoard `json:"apiConfig,omitempty"`
 	RSSConfig      *rss.RSSJobBoard `json:"rssConfig,omitempty"`
+	// Static employer name for single-company career pages without a company selector
+	CompanyName string `json:"companyName,omitempty"`
+	// MaxExtractBytes caps the text taken from a browser-rendered page;
+	// extraction stops once it is reached. Defaults to 8 MiB.
+	MaxExtractBytes int `json:"maxExtractBytes,omitempty"`
+	// Charset forces the encoding of the board's pages, for boards that
+	// declare the wrong one. By default it is read from the response.
+	Charset string `json:"charset,omitempty"`
+	// Locale is the board's language, such as "de" or "ja-JP". It is sent
+	// as Accept-Language and selects the built-in rules for reading
+	// localized salaries and locations, which LocaleRules adds to.
+	Locale      string       `json:"locale,omitempty"`
+	LocaleRules *LocaleRules `json:"localeRules,omitempty"`
+	// MaxPages bounds how many pages of results are read by following
+	// selectors.nextPage. Defaults to 5 when nextPage is set.
+	MaxPages int `json:"maxPages,omitempty"`
+	// FetchDetails visits each job's link after the results page to fill
+	// in the full description, salary and posted date, which results pages
+	// often truncate or leave out. Details says how to read the pages.
+	FetchDetails bool          `json:"fetchDetails,omitempty"`
+	Details      *DetailConfig `json:"details,omitempty"`
+	// Scroll loads more results on boards rendered in a browser by
+	// scrolling the page, for boards that load them as it is scrolled
+	Scroll *ScrollConfig `json:"scroll,omitempty"`
+	// Fixture is a saved search results page, relative to the config file,
+	// that boards test runs the selectors against. Defaults to
+	// fixtures/<name>.html.
+	Fixture string `json:"fixture,omitempty"`
 }
 
 type Selectors struct {
@@ -46,6 +98,9 @@ type Selectors struct {
 	Salary       string `json:"salary"`
 	Description  string `json:"description"`
 	Link         string `json:"link"`
+	// NextPage selects the link to the next page of results, for boards
+	// scraped without a browser. Only the first page is read without it.
+	NextPage string `json:"nextPage,omitempty"`
 	// Fallback selectors
 	TitleFallback    []string `json:"titleFallback,omitempty"`
 	CompanyFallback  []string `json:"companyFallback,omitempty"`
@@ -63,8 +118,60 @@ type GlobalSettings struct {
 	ExportFormats      []string           `json:"exportFormats"`
 	ExportPath         string             `json:"exportPath"`
 	ProxyConfig        *proxy.ProxyConfig `json:"proxyConfig,omitempty"`
+	HTTP               *transport.Config  `json:"http,omitempty"`
 	APIKeys            map[string]string  `json:"apiKeys,omitempty"`
-	Delay              struct {
+	Notifications      *notify.Config     `json:"notifications,omitempty"`
+	Events             *events.Config     `json:"events,omitempty"`
+	LLM                *ai.Defaults       `json:"llm,omitempty"`
+	Summarization      *summarize.Config  `json:"summarization,omitempty"`
+	SalaryExtraction   *salary.Config     `json:"salaryExtraction,omitempty"`
+	Classification     *classify.Config   `json:"classification,omitempty"`
+	Deduplication      *dedup.Config      `json:"deduplication,omitempty"`
+	Ask                *ask.Config        `json:"ask,omitempty"`
+	InterviewPrep      *prep.Config       `json:"interviewPrep,omitempty"`
+	Geocoding          *geo.Config        `json:"geocoding,omitempty"`
+	Hooks              *hooks.Config      `json:"hooks,omitempty"`
+	// Enrichment has an LLM read skills, experience, visa sponsorship and
+	// seniority from new jobs' descriptions
+	Enrichment *enrich.Config `json:"enrichment,omitempty"`
+	// SemanticSearch embeds stored jobs so searches can rank them by
+	// meaning
+	SemanticSearch *semantic.Config `json:"semanticSearch,omitempty"`
+	// Relevance picks and tunes the scorer ranking jobs against the search
+	// keywords
+	Relevance *relevance.Config `json:"relevance,omitempty"`
+	// ExchangeRates converts salaries between currencies for filters that
+	// name one
+	ExchangeRates *salary.RatesConfig `json:"exchangeRates,omitempty"`
+	// MaxRequestsPerSecond caps the scrape requests of all boards together,
+	// on top of each domain's spacing by its boards' rateLimit. Defaults to 5.
+	MaxRequestsPerSecond float64 `json:"maxRequestsPerSecond,omitempty"`
+	// MaxRequestsPerHour caps the requests to each domain in any hour,
+	// counting boards, retries and posting fetches together across runs;
+	// what is over it is deferred to a later run. Zero leaves domains
+	// uncapped.
+	MaxRequestsPerHour int `json:"maxRequestsPerHour,omitempty"`
+	// DomainRequestsPerHour overrides MaxRequestsPerHour for single
+	// domains, e.g. {"indeed.com": 60}; 0 leaves a domain uncapped
+	DomainRequestsPerHour map[string]int `json:"domainRequestsPerHour,omitempty"`
+	// APIMaxPages is how many pages of results each API provider is asked
+	// for while it reports more. Defaults to 1.
+	APIMaxPages int `json:"apiMaxPages,omitempty"`
+	// ProviderBreaker rests API providers that keep failing with server
+	// errors or timeouts instead of calling them on every search
+	ProviderBreaker *api.BreakerConfig `json:"providerBreaker,omitempty"`
+	// Timezone is the IANA zone, e.g. Europe/Berlin, that dates are shown,
+	// grouped by day and read without a zone in. Defaults to the local
+	// zone; stored timestamps are always UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Companies groups the names a company goes by and lists the
+	// companies whose jobs are kept or dropped
+	Companies *models.CompanyConfig `json:"companies,omitempty"`
+	// Expiry revisits stored job links to expire the postings taken down
+	Expiry *expiry.Config `json:"expiry,omitempty"`
+	// Schedules are the scrapes the daemon command runs on cron schedules
+	Schedules []schedule.Entry `json:"schedules,omitempty"`
+	Delay     struct {
 		Min int `json:"min"`
 		Max int `json:"max"`
 	} `json:"delay"`
@@ -79,8 +186,12 @@ type Config struct {
 // Import the Job type from models package
 
 type ScraperCore struct {
+	// configMutex guards the board and provider lists, which the admin API
+	// can replace while scrapes run
+	configMutex  sync.RWMutex
 	config       Config
-	rateLimiter  *rate.Limiter
+	configPath   string
+	limiters     *domainLimiters
 	logger       *logrus.Logger
 	client       *http.Client
 	proxyManager *proxy.ProxyManager
@@ -89,35 +200,78 @@ type ScraperCore struct {
 }
 
 type ScrapeResult struct {
-	Jobs   []models.Job
-	Error  error
-	Source string
+	Jobs     []models.Job
+	Error    error
+	Source   string
+	Duration time.Duration
 }
 
 // NewScraperCore creates a new scraper core instance with the specified configuration
 func NewScraperCore(configPath string) (*ScraperCore, error) {
+	return NewScraperCoreWithLogger(configPath, nil)
+}
+
+// NewScraperCoreWithLogger creates a scraper core that logs through the given
+// logger, so callers control format and destination. A nil logger gets the
+// default one whose level follows GlobalSettings.EnableLogging.
+func NewScraperCoreWithLogger(configPath string, logger *logrus.Logger) (*ScraperCore, error) {
 	config, err := loadConfig(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, errs.WithCause(fmt.Errorf("failed to load config: %w", err), errs.ErrConfig)
+	}
+	env, err := applyEnv(&config)
+	if err != nil {
+		return nil, errs.WithCause(fmt.Errorf("failed to apply config overrides from the environment: %w", err), errs.ErrConfig)
 	}
 
-	logger := logrus.New()
-	if config.GlobalSettings.EnableLogging {
-		logger.SetLevel(logrus.InfoLevel)
-	} else {
-		logger.SetLevel(logrus.WarnLevel)
+	if logger == nil {
+		logger = logrus.New()
+		if config.GlobalSettings.EnableLogging {
+			logger.SetLevel(logrus.InfoLevel)
+		} else {
+			logger.SetLevel(logrus.WarnLevel)
+		}
+	}
+
+	if len(env.overridden) > 0 {
+		logger.Infof("Applied config overrides from %s", strings.Join(env.overridden, ", "))
+	}
+	if len(env.unknown) > 0 {
+		logger.Warnf("Ignoring environment variables that match no config key: %s", strings.Join(env.unknown, ", "))
+	}
+	if len(env.unset) > 0 {
+		logger.Warnf("Config references unset environment variables: %s", strings.Join(env.unset, ", "))
+	}
+
+	if err := models.SetDisplayTimezone(config.GlobalSettings.Timezone); err != nil {
+		return nil, errs.WithCause(fmt.Errorf("invalid globalSettings.timezone: %w", err), errs.ErrConfig)
+	}
+	if err := models.SetCompanies(config.GlobalSettings.Companies); err != nil {
+		return nil, errs.WithCause(fmt.Errorf("invalid globalSettings.companies: %w", err), errs.ErrConfig)
 	}
 
 	// Initialize proxy manager if configured
+	// Clients made from here on share one tuned transport
+	if err := transport.Configure(config.GlobalSettings.HTTP); err != nil {
+		return nil, errs.WithCause(fmt.Errorf("invalid http settings: %w", err), errs.ErrConfig)
+	}
+
+	// Recordings are replayed without proxies, which would only be tested
+	// and refreshed over the network
 	var proxyManager *proxy.ProxyManager
-	if config.GlobalSettings.ProxyConfig != nil && config.GlobalSettings.ProxyConfig.Enabled {
+	if config.GlobalSettings.ProxyConfig != nil && config.GlobalSettings.ProxyConfig.Enabled && !transport.Offline() {
 		proxyManager, err = proxy.NewProxyManager(*config.GlobalSettings.ProxyConfig)
 		if err != nil {
 			logger.Warnf("Failed to initialize proxy manager: %v", err)
 		} else {
 			logger.Infof("Initialized proxy manager with %d proxies", len(config.GlobalSettings.ProxyConfig.ProxyList))
-			// Test proxies in background
-			go proxyManager.TestAllProxies()
+			if proxyManager.RefreshInterval() > 0 {
+				// Fetch the proxy list, then test the pool, in background
+				go refreshProxies(proxyManager, logger)
+			} else {
+				// Test proxies in background
+				go proxyManager.TestAllProxies()
+			}
 		}
 	}
 
@@ -125,27 +279,19 @@ func NewScraperCore(configPath string) (*ScraperCore, error) {
 	var client *http.Client
 	if proxyManager != nil {
 		client = proxyManager.GetHTTPClient()
-	} else {
-		client = &http.Client{
-			Timeout: time.Duration(config.GlobalSettings.Timeout) * time.Millisecond,
+		if client.Transport == nil {
+			client.Transport = http.DefaultTransport
 		}
+		client.Transport = transport.Wrap(client.Transport)
+	} else {
+		client = transport.NewClient(time.Duration(config.GlobalSettings.Timeout) * time.Millisecond)
 	}
 
-	rateLimiter := rate.NewLimiter(rate.Every(time.Millisecond*time.Duration(config.GlobalSettings.Delay.Min)), 1)
-
 	// Initialize API manager
 	apiManager := api.NewAPIManager(logger)
+	apiManager.SetBreaker(config.GlobalSettings.ProviderBreaker)
 
-	// Load API keys from environment variables if not set in config
-	for i := range config.APIProviders {
-		if config.APIProviders[i].APIKey == "" {
-			envKey := getAPIKeyEnvVar(config.APIProviders[i].Provider)
-			if envValue := os.Getenv(envKey); envValue != "" {
-				config.APIProviders[i].APIKey = envValue
-				logger.Infof("Loaded API key for %s from environment variable %s", config.APIProviders[i].Provider, envKey)
-			}
-		}
-	}
+	loadAPIKeysFromEnv(config.APIProviders, logger)
 
 	// Register API providers
 	if err := api.RegisterProviders(apiManager, config.APIProviders); err != nil {
@@ -165,7 +311,8 @@ func NewScraperCore(configPath string) (*ScraperCore, error) {
 
 	return &ScraperCore{
 		config:       config,
-		rateLimiter:  rateLimiter,
+		configPath:   configPath,
+		limiters:     newDomainLimiters(config.GlobalSettings.MaxRequestsPerSecond, newRequestBudgets(config.GlobalSettings.MaxRequestsPerHour, config.GlobalSettings.DomainRequestsPerHour)),
 		logger:       logger,
 		client:       client,
 		proxyManager: proxyManager,
@@ -174,20 +321,117 @@ func NewScraperCore(configPath string) (*ScraperCore, error) {
 	}, nil
 }
 
+// refreshProxies keeps the proxy pool in step with the configured proxy
+// list for the life of the process
+func refreshProxies(proxyManager *proxy.ProxyManager, logger *logrus.Logger) {
+	refresh := func() {
+		size, err := proxyManager.Refresh(context.Background())
+		if err != nil {
+			logger.Warnf("Failed to refresh proxy list: %v", err)
+			return
+		}
+		logger.Infof("Refreshed proxy list from %s: %d proxies in pool", proxyManager.ListHost(), size)
+	}
+
+	refresh()
+	proxyManager.TestAllProxies()
+
+	ticker := time.NewTicker(proxyManager.RefreshInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		refresh()
+	}
+}
+
+// TrackProxyUsage keeps per-proxy usage totals in the file at path; it does
+// nothing when proxies are off
+func (sc *ScraperCore) TrackProxyUsage(path string) error {
+	if sc.proxyManager == nil {
+		return nil
+	}
+	return sc.proxyManager.TrackUsage(path)
+}
+
+// SaveProxyUsage writes the proxy usage totals to the file given to
+// TrackProxyUsage
+func (sc *ScraperCore) SaveProxyUsage() error {
+	if sc.proxyManager == nil {
+		return nil
+	}
+	return sc.proxyManager.SaveUsage()
+}
+
+// ProxyUsage returns what requests through each proxy have done, including
+// totals saved by earlier runs; it is empty when proxies are off
+func (sc *ScraperCore) ProxyUsage() []proxy.Usage {
+	if sc.proxyManager == nil {
+		return nil
+	}
+	return sc.proxyManager.Usage()
+}
+
+// ObserveAPIRequests has observe called after every API provider request
+func (sc *ScraperCore) ObserveAPIRequests(observe func(provider string, duration time.Duration, err error)) {
+	sc.apiManager.ObserveRequests(observe)
+}
+
+// TrackAPIUsage keeps the API providers' request counts in the file at
+// path, so quota use adds up across runs
+func (sc *ScraperCore) TrackAPIUsage(path string) error {
+	return sc.apiManager.TrackUsage(path)
+}
+
+// SaveAPIUsage writes the API providers' request counts to the file given
+// to TrackAPIUsage
+func (sc *ScraperCore) SaveAPIUsage() error {
+	return sc.apiManager.SaveUsage()
+}
+
 func (sc *ScraperCore) GetConfig() Config {
+	sc.configMutex.RLock()
+	defer sc.configMutex.RUnlock()
 	return sc.config
 }
 
+// ConfigHash returns a short fingerprint of the current configuration, so
+// runs made under different boards or settings can be told apart
+func (sc *ScraperCore) ConfigHash() string {
+	sc.configMutex.RLock()
+	data, err := json.Marshal(sc.config)
+	sc.configMutex.RUnlock()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
 // GetAPIStats returns statistics for all API providers
 func (sc *ScraperCore) GetAPIStats() map[string]*api.APIStats {
 	return sc.apiManager.GetStats()
 }
 
-// ValidateAPICredentials validates all configured API providers
-func (sc *ScraperCore) ValidateAPICredentials() map[string]error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	return sc.apiManager.ValidateAllProviders(ctx)
+// ValidateAPICredentials validates all configured API providers, giving
+// each api.DefaultValidationTimeout
+func (sc *ScraperCore) ValidateAPICredentials(ctx context.Context) []api.ProviderValidation {
+	return sc.apiManager.ValidateAllProviders(ctx, api.DefaultValidationTimeout)
+}
+
+// loadAPIKeysFromEnv fills in API keys missing from the config from
+// environment variables. Offline, a provider still without one gets a
+// placeholder, since recordings keep keys redacted and any key replays them.
+func loadAPIKeysFromEnv(providers []api.APIConfig, logger *logrus.Logger) {
+	for i := range providers {
+		if providers[i].APIKey == "" {
+			envKey := getAPIKeyEnvVar(providers[i].Provider)
+			if envValue := os.Getenv(envKey); envValue != "" {
+				providers[i].APIKey = envValue
+				logger.Infof("Loaded API key for %s from environment variable %s", providers[i].Provider, envKey)
+			} else if transport.Offline() && providers[i].Enabled {
+				providers[i].APIKey = "offline"
+			}
+		}
+	}
 }
 
 // getAPIKeyEnvVar returns the environment variable name for the given provider
@@ -199,6 +443,10 @@ func getAPIKeyEnvVar(provider string) string {
 		return "REED_API_KEY"
 	case "jsearch":
 		return "JSEARCH_API_KEY"
+	case "jooble":
+		return "JOOBLE_API_KEY"
+	case "themuse":
+		return "THEMUSE_API_KEY"
 	default:
 		return strings.ToUpper(provider) + "_API_KEY"
 	}
@@ -218,90 +466,298 @@ func loadConfig(configPath string) (Config, error) {
 	return config, err
 }
 
+// ProgressFunc receives per-source updates during a scrape run. It may be
+// called from several goroutines at once.
+type ProgressFunc func(update models.SourceProgress)
+
+// APIProgressSource names the API providers, as a group, in progress updates
+const APIProgressSource = "api"
+
+// StreamBatchSize is the most jobs sent to a stream at once, so large
+// sources reach storage in pieces
+const StreamBatchSize = 500
+
 func (sc *ScraperCore) ScrapeAllBoards(keywords []string, location string) ([]models.Job, error) {
-	var allJobs []models.Job
-	var errors []string
+	return sc.ScrapeAllBoardsWithProgress(keywords, location, nil)
+}
 
-	// First, try API providers
-	sc.logger.Info("Attempting to fetch jobs using API providers...")
-	apiJobs, apiErrors := sc.fetchFromAPIs(keywords, location)
-	if len(apiJobs) > 0 {
-		allJobs = append(allJobs, apiJobs...)
-		sc.logger.Infof("Fetched %d jobs from API providers", len(apiJobs))
+// ScrapeAllBoardsWithProgress scrapes like ScrapeAllBoards and reports each
+// source moving from pending through running to completed or failed
+func (sc *ScraperCore) ScrapeAllBoardsWithProgress(keywords []string, location string, progress ProgressFunc) ([]models.Job, error) {
+	return collect(func(out chan<- []models.Job) error {
+		return sc.StreamAllBoards(context.Background(), keywords, location, nil, progress, out)
+	})
+}
+
+// StreamAllBoards scrapes every enabled source like
+// ScrapeAllBoardsWithProgress, but sends each source's jobs to out, in
+// batches of at most StreamBatchSize, as soon as the source finishes. A full
+// out holds back the scrape. out is closed when the scrape ends.
+//
+// Sources named in skip, by board name or APIProgressSource, are left out,
+// so an interrupted run can continue where it stopped. Once ctx is done no
+// more sources are started; those already running finish and send their
+// jobs, and ctx's error is returned.
+func (sc *ScraperCore) StreamAllBoards(ctx context.Context, keywords []string, location string, skip []string, progress ProgressFunc, out chan<- []models.Job) error {
+	defer close(out)
+	var failures []error
+	found := 0
+
+	if progress == nil {
+		progress = func(models.SourceProgress) {}
+	}
+
+	// Every log line of this run carries the run ID for correlation
+	log := sc.logger.WithField("run_id", RunID(ctx))
+	log.Infof("Starting scrape run for keywords %v in %s", keywords, location)
+
+	skipped := make(map[string]bool, len(skip))
+	for _, source := range skip {
+		skipped[source] = true
 	}
-	if len(apiErrors) > 0 {
-		for _, err := range apiErrors {
-			errors = append(errors, fmt.Sprintf("API: %v", err))
+	var enabledBoards []JobBoard
+	for _, board := range sc.getEnabledBoards() {
+		if !skipped[board.Name] {
+			enabledBoards = append(enabledBoards, board)
 		}
 	}
+	if len(skip) > 0 {
+		log.Infof("Skipping %d sources finished by an earlier run", len(skip))
+	}
+
+	// Without configured providers the API source is left out rather than
+	// reported failed, so runs of boards alone are not partial failures
+	fetchAPIs := !skipped[APIProgressSource] && len(sc.apiManager.GetConfiguredProviders()) > 0
+	if fetchAPIs {
+		progress(models.SourceProgress{Source: APIProgressSource, Status: models.SourcePending})
+	}
+	for _, board := range enabledBoards {
+		progress(models.SourceProgress{Source: board.Name, Status: models.SourcePending})
+	}
+
+	// The API providers are searched alongside the boards, each provider's
+	// jobs streaming out as it answers like each board's do
+	type apiOutcome struct {
+		found    int
+		failures []error
+	}
+	apiDone := make(chan apiOutcome, 1)
+	if fetchAPIs {
+		log.Info("Fetching jobs from API providers...")
+		progress(models.SourceProgress{Source: APIProgressSource, Status: models.SourceRunning})
+		go func() {
+			apiStart := time.Now()
+			apiFound, apiErrors := sc.fetchFromAPIs(ctx, keywords, location, log, out)
+			if apiFound > 0 {
+				log.Infof("Fetched %d jobs from API providers", apiFound)
+			}
+			apiUpdate := models.SourceProgress{Source: APIProgressSource, Status: models.SourceCompleted, JobsFound: apiFound, Duration: time.Since(apiStart)}
+			if len(apiErrors) > 0 {
+				var messages []string
+				for _, err := range apiErrors {
+					messages = append(messages, err.Error())
+				}
+				apiUpdate.Error = strings.Join(messages, "; ")
+				if apiFound == 0 {
+					apiUpdate.Status = models.SourceFailed
+				}
+			}
+			progress(apiUpdate)
+			apiDone <- apiOutcome{found: apiFound, failures: apiErrors}
+		}()
+	}
 
-	// Then, fallback to scraping if needed or if APIs didn't provide enough results
-	enabledBoards := sc.getEnabledBoards()
 	if len(enabledBoards) > 0 {
-		sc.logger.Info("Falling back to web scraping...")
-		scraperJobs, scraperErrors := sc.scrapeBoards(enabledBoards, keywords, location)
-		allJobs = append(allJobs, scraperJobs...)
-		errors = append(errors, scraperErrors...)
+		log.Info("Scraping job boards...")
+		scraped, scraperErrors := sc.scrapeBoards(ctx, enabledBoards, keywords, location, log, progress, out)
+		found += scraped
+		failures = append(failures, scraperErrors...)
 	}
 
-	if len(allJobs) == 0 && len(errors) > 0 {
-		return nil, fmt.Errorf("all sources failed: %s", strings.Join(errors, "; "))
+	if fetchAPIs {
+		outcome := <-apiDone
+		found += outcome.found
+		for _, err := range outcome.failures {
+			failures = append(failures, fmt.Errorf("API: %w", err))
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		log.Warnf("Scrape run interrupted after finding %d jobs", found)
+		return fmt.Errorf("scrape run interrupted: %w", err)
+	}
+	if found == 0 && len(failures) > 0 {
+		return fmt.Errorf("%w: %w", errs.ErrAllSourcesFailed, errs.Join(failures))
 	}
 
-	return allJobs, nil
+	return nil
 }
 
-// fetchFromAPIs attempts to fetch jobs from all configured API providers
-func (sc *ScraperCore) fetchFromAPIs(keywords []string, location string) ([]models.Job, []error) {
-	// Build search query
-	query := api.SearchQuery{
-		Keywords: keywords,
-		Location: location,
-		Limit:    100, // Default limit per provider
-		Offset:   0,
+// ScrapeBoardsWithProgress scrapes only the named job boards, skipping the
+// API providers. Names match case-insensitively and may name disabled
+// boards; an unknown name is an error.
+func (sc *ScraperCore) ScrapeBoardsWithProgress(names []string, keywords []string, location string, progress ProgressFunc) ([]models.Job, error) {
+	return collect(func(out chan<- []models.Job) error {
+		return sc.StreamBoards(context.Background(), names, keywords, location, progress, out)
+	})
+}
+
+// StreamBoards scrapes the named job boards like ScrapeBoardsWithProgress,
+// sending their jobs to out and stopping early on ctx as StreamAllBoards
+// does
+func (sc *ScraperCore) StreamBoards(ctx context.Context, names []string, keywords []string, location string, progress ProgressFunc, out chan<- []models.Job) error {
+	defer close(out)
+	if progress == nil {
+		progress = func(models.SourceProgress) {}
 	}
 
-	// Search all configured providers
-	results, err := sc.apiManager.SearchAll(context.Background(), query)
-	if err != nil {
-		return nil, []error{err}
+	sc.configMutex.RLock()
+	var boards []JobBoard
+	for _, name := range names {
+		found := false
+		for _, board := range sc.config.JobBoards {
+			if strings.EqualFold(board.Name, name) {
+				boards = append(boards, board)
+				found = true
+				break
+			}
+		}
+		if !found {
+			sc.configMutex.RUnlock()
+			return fmt.Errorf("unknown job board %q", name)
+		}
 	}
+	sc.configMutex.RUnlock()
 
-	// Aggregate jobs from all providers
-	var allJobs []models.Job
-	var errors []error
+	log := sc.logger.WithField("run_id", RunID(ctx))
+	log.Infof("Starting scrape run of %d boards for keywords %v in %s", len(boards), keywords, location)
+	for _, board := range boards {
+		progress(models.SourceProgress{Source: board.Name, Status: models.SourcePending})
+	}
 
-	for _, result := range results {
-		if result != nil {
-			allJobs = append(allJobs, result.Jobs...)
-			sc.logger.Infof("API provider %s returned %d jobs", result.Provider, len(result.Jobs))
-		}
+	found, failures := sc.scrapeBoards(ctx, boards, keywords, location, log, progress, out)
+	if err := ctx.Err(); err != nil {
+		log.Warnf("Scrape run interrupted after finding %d jobs", found)
+		return fmt.Errorf("scrape run interrupted: %w", err)
 	}
+	if found == 0 && len(failures) > 0 {
+		return fmt.Errorf("%w: %w", errs.ErrAllSourcesFailed, errs.Join(failures))
+	}
+	return nil
+}
+
+// collect runs a streaming scrape and gathers its batches into one slice
+func collect(stream func(out chan<- []models.Job) error) ([]models.Job, error) {
+	batches := make(chan []models.Job)
+	done := make(chan error, 1)
+	go func() { done <- stream(batches) }()
+
+	var jobs []models.Job
+	for batch := range batches {
+		jobs = append(jobs, batch...)
+	}
+	if err := <-done; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// sendJobs sends jobs to out in batches of at most StreamBatchSize
+func sendJobs(out chan<- []models.Job, jobs []models.Job) {
+	for start := 0; start < len(jobs); start += StreamBatchSize {
+		out <- jobs[start:min(start+StreamBatchSize, len(jobs))]
+	}
+}
 
-	return allJobs, errors
+type radiusKey struct{}
+
+// WithRadius returns a copy of ctx asking the API providers that support a
+// location radius for jobs within km of the run's location
+func WithRadius(ctx context.Context, km float64) context.Context {
+	return context.WithValue(ctx, radiusKey{}, km)
+}
+
+// radius returns the location radius carried by ctx, or 0 when it has none
+func radius(ctx context.Context) float64 {
+	km, _ := ctx.Value(radiusKey{}).(float64)
+	return km
 }
 
-func (sc *ScraperCore) scrapeBoards(enabledBoards []JobBoard, keywords []string, location string) ([]models.Job, []string) {
+type datePostedKey struct{}
+
+// WithDatePosted returns a copy of ctx asking the API providers that
+// support it for jobs posted within window, such as 7d
+func WithDatePosted(ctx context.Context, window string) context.Context {
+	return context.WithValue(ctx, datePostedKey{}, window)
+}
+
+// datePosted returns the date posted window carried by ctx, or "" when it
+// has none
+func datePosted(ctx context.Context) string {
+	window, _ := ctx.Value(datePostedKey{}).(string)
+	return window
+}
+
+// fetchFromAPIs searches the configured API providers, sending each one's
+// jobs to out as soon as it answers, and returns how many jobs they found
+func (sc *ScraperCore) fetchFromAPIs(ctx context.Context, keywords []string, location string, log *logrus.Entry, out chan<- []models.Job) (int, []error) {
+	// Build search query
+	query := api.SearchQuery{
+		Keywords:   keywords,
+		Location:   location,
+		RadiusKm:   radius(ctx),
+		DatePosted: datePosted(ctx),
+		Limit:      100, // Default limit per provider
+		Offset:     0,
+	}
+
+	found := 0
+	var failures []error
+	for result := range sc.apiManager.SearchAllPagedStream(ctx, query, sc.config.GlobalSettings.APIMaxPages) {
+		if result.Err != nil {
+			failures = append(failures, fmt.Errorf("provider %s: %w", result.Provider, result.Err))
+			continue
+		}
+		found += len(result.Result.Jobs)
+		log.WithField("provider", result.Provider).Infof("API provider %s returned %d jobs", result.Provider, len(result.Result.Jobs))
+		sendJobs(out, result.Result.Jobs)
+	}
+	return found, failures
+}
+
+// scrapeBoards scrapes the boards concurrently, sending each board's jobs to
+// out when it finishes, and returns how many jobs they found. Boards not yet
+// started, or waiting on a rate limiter, when ctx is done fail with ctx's
+// error.
+func (sc *ScraperCore) scrapeBoards(ctx context.Context, enabledBoards []JobBoard, keywords []string, location string, log *logrus.Entry, progress ProgressFunc, out chan<- []models.Job) (int, []error) {
 	resultChan := make(chan ScrapeResult, len(enabledBoards))
 	var wg sync.WaitGroup
 
+	if sc.proxyManager != nil {
+		if err := sc.proxyManager.StartRun(); err != nil {
+			log.Warnf("Failed to get new Tor circuits: %v", err)
+		}
+	}
+
 	// Launch goroutine for each enabled job board
 	for _, board := range enabledBoards {
 		wg.Add(1)
 		go func(board JobBoard) {
 			defer wg.Done()
 
-			// Rate limiting per board
-			if err := sc.rateLimiter.Wait(context.Background()); err != nil {
+			if err := ctx.Err(); err != nil {
 				resultChan <- ScrapeResult{Error: err, Source: board.Name}
 				return
 			}
 
-			jobs, err := sc.scrapeBoard(board, keywords, location)
+			progress(models.SourceProgress{Source: board.Name, Status: models.SourceRunning})
+			start := time.Now()
+			jobs, err := sc.scrapeBoard(ctx, board, keywords, location, log.WithField("board", board.Name))
 			resultChan <- ScrapeResult{
-				Jobs:   jobs,
-				Error:  err,
-				Source: board.Name,
+				Jobs:     jobs,
+				Error:    err,
+				Source:   board.Name,
+				Duration: time.Since(start),
 			}
 		}(board)
 	}
@@ -312,31 +768,40 @@ func (sc *ScraperCore) scrapeBoards(enabledBoards []JobBoard, keywords []string,
 		close(resultChan)
 	}()
 
-	// Collect results
-	var allJobs []models.Job
-	var errors []string
+	// Pass results on
+	found := 0
+	var failures []error
 
 	for result := range resultChan {
-		if result.Error != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", result.Source, result.Error))
-			sc.logger.Errorf("Failed to scrape %s: %v", result.Source, result.Error)
+		boardLog := log.WithField("board", result.Source)
+		if errors.Is(result.Error, errs.ErrBudgetSpent) {
+			// Not a failure: the board is scraped again once the budget
+			// has room
+			boardLog.Warnf("Deferring %s to a later run: %v", result.Source, result.Error)
+			progress(models.SourceProgress{Source: result.Source, Status: models.SourceDeferred, Error: result.Error.Error(), Duration: result.Duration})
+		} else if result.Error != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", result.Source, result.Error))
+			boardLog.Errorf("Failed to scrape %s: %v", result.Source, result.Error)
+			progress(models.SourceProgress{Source: result.Source, Status: models.SourceFailed, Error: result.Error.Error(), Duration: result.Duration})
 		} else {
-			allJobs = append(allJobs, result.Jobs...)
-			sc.logger.Infof("Successfully scraped %d jobs from %s", len(result.Jobs), result.Source)
+			found += len(result.Jobs)
+			boardLog.Infof("Successfully scraped %d jobs from %s", len(result.Jobs), result.Source)
+			progress(models.SourceProgress{Source: result.Source, Status: models.SourceCompleted, JobsFound: len(result.Jobs), Duration: result.Duration})
+			sendJobs(out, result.Jobs)
 		}
 	}
 
-	return allJobs, errors
+	return found, failures
 }
 
-func (sc *ScraperCore) scrapeBoard(board JobBoard, keywords []string, location string) ([]models.Job, error) {
+func (sc *ScraperCore) scrapeBoard(ctx context.Context, board JobBoard, keywords []string, location string, log *logrus.Entry) ([]models.Job, error) {
 	// Determine scraping method
 	method := board.ScrapingMethod
 	if method == "" {
 		method = "scraping" // default
 	}
 
-	sc.logger.Infof("Scraping %s using method: %s", board.Name, method)
+	log.Infof("Scraping %s using method: %s", board.Name, method)
 
 	switch method {
 	case "api":
@@ -345,60 +810,108 @@ func (sc *ScraperCore) scrapeBoard(board JobBoard, keywords []string, location s
 
 	case "rss":
 		if board.RSSConfig != nil {
-			return sc.rssClient.FetchJobs(*board.RSSConfig, keywords)
+			if err := sc.limiters.wait(ctx, board.RSSConfig.FeedURL, sc.requestInterval(board)); err != nil {
+				return nil, err
+			}
+			jobs, err := sc.rssClient.FetchJobs(*board.RSSConfig, keywords)
+			localize(board, jobs)
+			return jobs, err
 		}
 		return nil, fmt.Errorf("RSS config not provided for %s", board.Name)
 
 	default: // "scraping"
 		keywordStr := strings.Join(keywords, " ")
 		searchURL := sc.buildSearchURL(board, keywordStr, location)
-		sc.logger.Infof("Scraping %s: %s", board.Name, searchURL)
+		log.Infof("Scraping %s: %s", board.Name, searchURL)
 
 		// Choose between JavaScript and HTTP scraping
-		if sc.requiresJavaScript(board) {
-			return sc.scrapeWithChromedp(board, searchURL)
+		jobs, err := sc.retryThroughProxies(board, searchURL, log, func() ([]models.Job, error) {
+			// Offline, the page a browser rendered when recording is replayed
+			if sc.requiresJavaScript(board) && !transport.Offline() {
+				return sc.scrapeWithChromedp(ctx, board, searchURL)
+			}
+			return sc.scrapeWithColly(ctx, board, searchURL, log)
+		})
+		if err == nil && board.FetchDetails {
+			sc.fetchDetails(ctx, board, jobs, log)
 		}
-
-		return sc.scrapeWithColly(board, searchURL)
+		localize(board, jobs)
+		return jobs, err
 	}
 }
 
-func (sc *ScraperCore) scrapeWithColly(board JobBoard, url string) ([]models.Job, error) {
+func (sc *ScraperCore) scrapeWithColly(ctx context.Context, board JobBoard, url string, log *logrus.Entry) ([]models.Job, error) {
 	var jobs []models.Job
 	var mu sync.Mutex
 
 	c := colly.NewCollector(
-		colly.Debugger(&debug.LogDebugger{}),
+		colly.Debugger(&logrusDebugger{log: log}),
 	)
 
-	// Set user agent (potentially random if proxy manager available)
+	// Set user agent (random, or pinned to the board when sticky, if proxy
+	// manager available)
 	userAgent := sc.config.GlobalSettings.UserAgent
 	if sc.proxyManager != nil {
-		userAgent = sc.proxyManager.GetRandomUserAgent()
+		userAgent = sc.proxyManager.UserAgentFor(board.Name)
 	}
 	c.UserAgent = userAgent
 
-	// Use proxy if available
+	// Route each request through the proxy its board and domain call for;
+	// the URL carries any credentials. Requests run one at a time, so the
+	// last proxy chosen is the one a response or error came through.
+	var lastProxy string
+	proxyFunc := func(r *http.Request) (*neturl.URL, error) {
+		proxyURL := sc.proxyManager.ProxyFor(board.Name, r.URL.Hostname())
+		mu.Lock()
+		lastProxy = ""
+		if proxyURL != nil {
+			lastProxy = proxyURL.String()
+			log.Debugf("Using proxy %s for %s", proxyURL.Redacted(), r.URL.Host)
+		}
+		mu.Unlock()
+		return proxyURL, nil
+	}
 	if sc.proxyManager != nil {
-		proxyURL := sc.proxyManager.GetCurrentProxy()
-		if proxyURL != "direct" {
-			c.SetProxy(proxyURL)
-			sc.logger.Debugf("Using proxy: %s", proxyURL)
+		c.SetProxyFunc(proxyFunc)
+		c.OnResponse(func(r *colly.Response) {
+			mu.Lock()
+			defer mu.Unlock()
+			sc.proxyManager.ReportSuccess(lastProxy, int64(len(r.Body)))
+		})
+	}
+
+	// Recording saves each response; offline, responses come from the
+	// recordings instead of the network
+	if transport.Recording() || transport.Offline() {
+		base := transport.Shared().Clone()
+		if sc.proxyManager != nil {
+			base.Proxy = proxyFunc
 		}
+		c.WithTransport(transport.Wrap(base))
 	}
 
-	// Rate limiting
+	// Requests are spaced per domain by the shared limiters, which also
+	// hold every board to the global ceiling
 	c.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
 		Parallelism: 1, // Reduced for better stealth
-		Delay:       time.Duration(board.RateLimit) * time.Millisecond,
 	})
+	interval := sc.requestInterval(board)
+	var dropped error
 
 	// Add random delays and headers for better stealth
 	c.OnRequest(func(r *colly.Request) {
+		if err := sc.limiters.wait(ctx, r.URL.String(), interval); err != nil {
+			mu.Lock()
+			dropped = err
+			mu.Unlock()
+			r.Abort()
+			return
+		}
+
 		// Add common headers
 		r.Headers.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-		r.Headers.Set("Accept-Language", "en-US,en;q=0.5")
+		r.Headers.Set("Accept-Language", acceptLanguage(board.Locale))
 		r.Headers.Set("Accept-Encoding", "gzip, deflate")
 		r.Headers.Set("Upgrade-Insecure-Requests", "1")
 		r.Headers.Set("Sec-Fetch-Dest", "document")
@@ -406,16 +919,35 @@ func (sc *ScraperCore) scrapeWithColly(board JobBoard, url string) ([]models.Job
 		r.Headers.Set("Sec-Fetch-Site", "none")
 
 		// Random delay before request
-		if sc.config.GlobalSettings.Delay.Max > sc.config.GlobalSettings.Delay.Min {
+		if sc.config.GlobalSettings.Delay.Max > sc.config.GlobalSettings.Delay.Min && !transport.Offline() {
 			randomDelay := rand.Intn(sc.config.GlobalSettings.Delay.Max-sc.config.GlobalSettings.Delay.Min) + sc.config.GlobalSettings.Delay.Min
 			time.Sleep(time.Duration(randomDelay) * time.Millisecond)
 		}
 	})
 
+	// Decode pages colly left undecoded before the selectors read them
+	if board.Charset != "" {
+		c.OnRequest(func(r *colly.Request) {
+			r.ResponseCharacterEncoding = board.Charset
+		})
+	} else {
+		c.OnResponse(func(r *colly.Response) {
+			var name string
+			if r.Body, name = decodeBody(r.Body); name != "utf-8" {
+				log.Debugf("Decoded %s as %s", r.Request.URL, name)
+			}
+		})
+	}
+
 	c.OnHTML(board.Selectors.JobContainer, func(e *colly.HTMLElement) {
+		company := strings.TrimSpace(e.ChildText(board.Selectors.Company))
+		if company == "" {
+			company = board.CompanyName
+		}
+
 		job := models.NewJob(
 			strings.TrimSpace(e.ChildText(board.Selectors.Title)),
-			strings.TrimSpace(e.ChildText(board.Selectors.Company)),
+			company,
 			strings.TrimSpace(e.ChildText(board.Selectors.Location)),
 			strings.TrimSpace(e.ChildText(board.Selectors.Salary)),
 			strings.TrimSpace(e.ChildText(board.Selectors.Description)),
@@ -435,22 +967,83 @@ func (sc *ScraperCore) scrapeWithColly(board JobBoard, url string) ([]models.Job
 		}
 	})
 
+	// Blocks and connection failures may be the proxy's fault
+	var failure *proxyError
 	c.OnError(func(r *colly.Response, err error) {
-		sc.logger.Errorf("Colly error on %s: %v", r.Request.URL, err)
+		log.Errorf("Colly error on %s: %v", r.Request.URL, err)
+		mu.Lock()
+		defer mu.Unlock()
+		if errors.Is(err, transport.ErrNotRecorded) {
+			return
+		}
+		if r.StatusCode == 0 || isBlockStatus(r.StatusCode) {
+			failure = &proxyError{proxy: lastProxy, status: r.StatusCode, err: err}
+		} else if sc.proxyManager != nil {
+			// The proxy delivered the site's error page
+			sc.proxyManager.ReportSuccess(lastProxy, int64(len(r.Body)))
+		}
 	})
 
-	err := c.Visit(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to visit %s: %w", url, err)
+	// The next page link, when the board has one, is followed until
+	// MaxPages or the result limit is reached. An incremental scrape also
+	// stops at the first page holding no job it has not stored.
+	var next string
+	if board.Selectors.NextPage != "" {
+		c.OnHTML(board.Selectors.NextPage, func(e *colly.HTMLElement) {
+			mu.Lock()
+			defer mu.Unlock()
+			if href := e.Attr("href"); next == "" && href != "" {
+				next = e.Request.AbsoluteURL(href)
+			}
+		})
+	}
+	maxPages := 1
+	if board.Selectors.NextPage != "" {
+		maxPages = board.MaxPages
+		if maxPages <= 0 {
+			maxPages = defaultMaxPages
+		}
 	}
-
-	c.Wait()
-
 	// Limit results - use board-specific limit or global default
 	maxResults := board.MaxResults
 	if maxResults == 0 {
 		maxResults = sc.config.GlobalSettings.MaxResultsPerBoard
 	}
+	known := knownJobs(ctx)
+
+	for page := 1; ; page++ {
+		next = ""
+		before := len(jobs)
+		if err := c.Visit(url); err != nil {
+			if failure != nil {
+				err = failure
+			}
+			if page == 1 {
+				return nil, fmt.Errorf("failed to visit %s: %w", url, err)
+			}
+			log.Warnf("Stopped reading %s at page %d: %v", board.Name, page, err)
+			break
+		}
+		c.Wait()
+		// A request dropped while waiting to go out leaves the page unread
+		if dropped != nil {
+			if page == 1 {
+				return nil, dropped
+			}
+			log.Warnf("Stopped reading %s at page %d: %v", board.Name, page, dropped)
+			break
+		}
+
+		if page >= maxPages || next == "" || len(jobs) == before || len(jobs) >= maxResults {
+			break
+		}
+		if known != nil && allKnown(known, jobs[before:]) {
+			log.Infof("Stopped reading %s at page %d: every job on it was found before", board.Name, page)
+			break
+		}
+		url = next
+	}
+
 	if len(jobs) > maxResults {
 		jobs = jobs[:maxResults]
 	}
@@ -458,60 +1051,124 @@ func (sc *ScraperCore) scrapeWithColly(board JobBoard, url string) ([]models.Job
 	return jobs, nil
 }
 
-func (sc *ScraperCore) scrapeWithChromedp(board JobBoard, url string) ([]models.Job, error) {
-	ctx, cancel := chromedp.NewContext(context.Background())
-	defer cancel()
+func (sc *ScraperCore) scrapeWithChromedp(ctx context.Context, board JobBoard, url string) ([]models.Job, error) {
+	// The page's own resources load freely once the document is allowed
+	if err := sc.limiters.wait(ctx, url, sc.requestInterval(board)); err != nil {
+		return nil, err
+	}
 
-	ctx, cancel = context.WithTimeout(ctx, time.Duration(sc.config.GlobalSettings.Timeout)*time.Millisecond)
+	var proxyURL *neturl.URL
+	if sc.proxyManager != nil {
+		// One browser serves the whole scrape, so it is routed by the
+		// search page's domain
+		if target, err := neturl.Parse(url); err == nil {
+			proxyURL = sc.proxyManager.ProxyFor(board.Name, target.Hostname())
+		}
+	}
+
+	allocCtx := context.Background()
+	if sc.proxyManager != nil {
+		opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.UserAgent(sc.proxyManager.UserAgentFor(board.Name)))
+		if proxyURL != nil {
+			// Chrome takes the proxy without credentials; they are answered
+			// through the Fetch domain when the proxy challenges
+			if proxyURL.User != nil && proxyURL.Scheme == "socks5" {
+				return nil, fmt.Errorf("chrome cannot authenticate to SOCKS proxies; use an HTTP proxy for %s", board.Name)
+			}
+			server := neturl.URL{Scheme: proxyURL.Scheme, Host: proxyURL.Host}
+			opts = append(opts, chromedp.ProxyServer(server.String()))
+			sc.logger.WithField("board", board.Name).Debugf("Using proxy: %s", proxyURL.Redacted())
+		}
+		var cancelAlloc context.CancelFunc
+		allocCtx, cancelAlloc = chromedp.NewExecAllocator(allocCtx, opts...)
+		defer cancelAlloc()
+	}
+
+	ctx, cancel := chromedp.NewContext(allocCtx)
 	defer cancel()
 
-	type tempJob struct {
-		Title       string `json:"title"`
-		Company     string `json:"company"`
-		Location    string `json:"location"`
-		Salary      string `json:"salary"`
-		Description string `json:"description"`
-		Link        string `json:"link"`
+	// A blocked page never shows the job container, so remember its status
+	// to tell a block from a timeout. Bytes received count towards proxy usage.
+	var blockStatus, received atomic.Int64
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventResponseReceived:
+			if status := ev.Response.Status; ev.Type == network.ResourceTypeDocument && isBlockStatus(int(status)) {
+				blockStatus.Store(status)
+			}
+		case *network.EventLoadingFinished:
+			received.Add(int64(ev.EncodedDataLength))
+		}
+	})
+
+	var setup []chromedp.Action
+	if proxyURL != nil && proxyURL.User != nil {
+		handleProxyAuth(ctx, proxyURL.User)
+		setup = append(setup, fetch.Enable().WithHandleAuthRequests(true))
 	}
 
-	var tempJobs []tempJob
+	ctx, cancel = context.WithTimeout(ctx, time.Duration(sc.config.GlobalSettings.Timeout)*time.Millisecond)
+	defer cancel()
 
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(url),
-		chromedp.WaitVisible(board.Selectors.JobContainer, chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second), // Allow dynamic content to load
-		chromedp.Evaluate(`
-			(() => {
-				const jobs = [];
-				const containers = document.querySelectorAll('`+board.Selectors.JobContainer+`');
-				
-				containers.forEach(container => {
-					const job = {
-						title: container.querySelector('`+board.Selectors.Title+`')?.textContent?.trim() || '',
-						company: container.querySelector('`+board.Selectors.Company+`')?.textContent?.trim() || '',
-						location: container.querySelector('`+board.Selectors.Location+`')?.textContent?.trim() || '',
-						salary: container.querySelector('`+board.Selectors.Salary+`')?.textContent?.trim() || '',
-						description: container.querySelector('`+board.Selectors.Description+`')?.textContent?.trim() || '',
-						link: container.querySelector('`+board.Selectors.Link+`')?.href || ''
-					};
-					
-					if (job.title && job.company) {
-						jobs.push(job);
-					}
-				});
-				
-				return jobs;
-			})()
-		`, &tempJobs),
-	)
+	// The page loading and the job containers appearing are waited for
+	// apart, so a timeout on the second is known to be a selector miss
+	var containers int
+	err := chromedp.Run(ctx, append(setup, chromedp.Navigate(url))...)
+	if err == nil {
+		err = chromedp.Run(ctx,
+			chromedp.WaitVisible(board.Selectors.JobContainer, chromedp.ByQuery),
+			chromedp.Sleep(2*time.Second), // Allow dynamic content to load
+			chromedp.Evaluate(`document.querySelectorAll('`+board.Selectors.JobContainer+`').length`, &containers),
+		)
+		if errors.Is(err, context.DeadlineExceeded) && blockStatus.Load() == 0 {
+			err = fmt.Errorf("%w: no %s on the page: %v", errs.ErrSelectorMiss, board.Selectors.JobContainer, err)
+		}
+	}
 
+	var proxyUsed string
+	if proxyURL != nil {
+		proxyUsed = proxyURL.String()
+	}
 	if err != nil {
+		if status := blockStatus.Load(); status != 0 {
+			err = &proxyError{proxy: proxyUsed, status: int(status), err: err}
+		} else if isNavigationError(err) {
+			err = &proxyError{proxy: proxyUsed, err: err}
+		}
 		return nil, fmt.Errorf("chromedp error: %w", err)
 	}
 
+	// Limit results - use board-specific limit or global default
+	maxResults := board.MaxResults
+	if maxResults == 0 {
+		maxResults = sc.config.GlobalSettings.MaxResultsPerBoard
+	}
+	if board.Scroll != nil {
+		containers = sc.scrollForMore(ctx, board, containers, maxResults, sc.logger.WithField("board", board.Name))
+	}
+
+	// Recording keeps the rendered page, which offline runs read in its place
+	if transport.Recording() {
+		var html string
+		if err := chromedp.Run(ctx, chromedp.OuterHTML("html", &html, chromedp.ByQuery)); err != nil {
+			sc.logger.WithField("board", board.Name).Warnf("Failed to record the rendered page: %v", err)
+		} else if err := transport.Save(url, http.StatusOK, "text/html; charset=utf-8", []byte(html)); err != nil {
+			sc.logger.WithField("board", board.Name).Warnf("Failed to record the rendered page: %v", err)
+		}
+	}
+
+	tempJobs := sc.extractRendered(ctx, board, containers)
+	if sc.proxyManager != nil {
+		sc.proxyManager.ReportSuccess(proxyUsed, received.Load())
+	}
+
 	// Process results
 	processedJobs := make([]models.Job, 0, len(tempJobs))
 	for _, tempJob := range tempJobs {
+		if tempJob.Company == "" {
+			tempJob.Company = board.CompanyName
+		}
+
 		job := models.NewJob(
 			tempJob.Title,
 			tempJob.Company,
@@ -524,16 +1181,154 @@ func (sc *ScraperCore) scrapeWithChromedp(board JobBoard, url string) ([]models.
 		processedJobs = append(processedJobs, *job)
 	}
 
-	// Limit results - use board-specific limit or global default
+	if len(processedJobs) > maxResults {
+		processedJobs = processedJobs[:maxResults]
+	}
+
+	return processedJobs, nil
+}
+
+// renderedBatchSize is how many job containers are read from the page at a
+// time, so a huge page is never copied out whole
+const renderedBatchSize = 50
+
+// defaultMaxExtractBytes applies when a board sets no MaxExtractBytes
+const defaultMaxExtractBytes = 8 << 20
+
+type renderedJob struct {
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	Salary      string `json:"salary"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+func (j renderedJob) size() int {
+	return len(j.Title) + len(j.Company) + len(j.Location) + len(j.Salary) + len(j.Description) + len(j.Link)
+}
+
+// renderedBatch is what extractScript returns: the jobs of a batch of
+// containers, and whether the byte budget it was given ran out on them
+type renderedBatch struct {
+	Jobs  []renderedJob `json:"jobs"`
+	Spent bool          `json:"spent"`
+}
+
+// extractRendered reads the jobs from the page's job containers in batches
+// by index, stopping at the board's result limit or once MaxExtractBytes of
+// text is taken. The page truncates the text to the bytes left, so no more
+// than MaxExtractBytes is copied out of the browser. A batch that fails
+// ends extraction with the jobs so far.
+func (sc *ScraperCore) extractRendered(ctx context.Context, board JobBoard, containers int) []renderedJob {
+	log := sc.logger.WithField("board", board.Name)
 	maxResults := board.MaxResults
 	if maxResults == 0 {
 		maxResults = sc.config.GlobalSettings.MaxResultsPerBoard
 	}
-	if len(processedJobs) > maxResults {
-		processedJobs = processedJobs[:maxResults]
+	maxBytes := board.MaxExtractBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxExtractBytes
 	}
 
-	return processedJobs, nil
+	var jobs []renderedJob
+	extracted := 0
+	for start := 0; start < containers; start += renderedBatchSize {
+		var batch renderedBatch
+		script := extractScript(board, start, start+renderedBatchSize, maxBytes-extracted)
+		if err := chromedp.Run(ctx, chromedp.Evaluate(script, &batch)); err != nil {
+			log.Warnf("Stopped reading %s after %d of %d job containers: %v", board.Name, start, containers, err)
+			break
+		}
+		for _, job := range batch.Jobs {
+			jobs = append(jobs, job)
+			extracted += job.size()
+			if maxResults > 0 && len(jobs) >= maxResults {
+				return jobs
+			}
+		}
+		if batch.Spent || extracted >= maxBytes {
+			log.Warnf("Stopped reading %s at %d jobs: page text exceeds %d bytes", board.Name, len(jobs), maxBytes)
+			return jobs
+		}
+	}
+	return jobs
+}
+
+// extractScript returns the jobs in the job containers from index start up
+// to end, taking at most budget bytes of UTF-8 text: the field that runs
+// over is cut short and the containers after it are left. The containers
+// are found once, by the first batch, and kept on the page for the rest.
+func extractScript(board JobBoard, start, end, budget int) string {
+	return `
+			(() => {
+				if (` + strconv.Itoa(start) + ` === 0 || !window.__hireContainers) {
+					window.__hireContainers = document.querySelectorAll('` + board.Selectors.JobContainer + `');
+				}
+				const containers = window.__hireContainers;
+				const encoder = new TextEncoder();
+				const decoder = new TextDecoder();
+				let budget = ` + strconv.Itoa(budget) + `;
+				const take = text => {
+					if (budget <= 0) {
+						return '';
+					}
+					const bytes = encoder.encode(text);
+					if (bytes.length <= budget) {
+						budget -= bytes.length;
+						return text;
+					}
+					// Cut at the budget, dropping a character split by the cut
+					const cut = decoder.decode(bytes.subarray(0, budget)).replace(/\uFFFD$/, '');
+					budget = 0;
+					return cut;
+				};
+
+				const jobs = [];
+				for (let i = ` + strconv.Itoa(start) + `; i < Math.min(containers.length, ` + strconv.Itoa(end) + `) && budget > 0; i++) {
+					const container = containers[i];
+					const text = selector => container.querySelector(selector)?.textContent?.trim() || '';
+					const title = text('` + board.Selectors.Title + `');
+					const company = text('` + board.Selectors.Company + `');
+					if (!title || !(company || ` + strconv.FormatBool(board.CompanyName != "") + `)) {
+						continue;
+					}
+					jobs.push({
+						title: take(title),
+						company: take(company),
+						location: take(text('` + board.Selectors.Location + `')),
+						salary: take(text('` + board.Selectors.Salary + `')),
+						description: take(text('` + board.Selectors.Description + `')),
+						link: take(container.querySelector('` + board.Selectors.Link + `')?.href || '')
+					});
+				}
+
+				return {jobs: jobs, spent: budget <= 0};
+			})()
+		`
+}
+
+// handleProxyAuth answers the proxy's authentication challenges with user
+// and lets every other paused request continue. Fetch pauses all requests
+// once enabled, so each must be resumed.
+func handleProxyAuth(ctx context.Context, user *neturl.Userinfo) {
+	password, _ := user.Password()
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *fetch.EventRequestPaused:
+			go chromedp.Run(ctx, fetch.ContinueRequest(ev.RequestID))
+		case *fetch.EventAuthRequired:
+			response := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseCancelAuth}
+			if ev.AuthChallenge != nil && ev.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+				response = &fetch.AuthChallengeResponse{
+					Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+					Username: user.Username(),
+					Password: password,
+				}
+			}
+			go chromedp.Run(ctx, fetch.ContinueWithAuth(ev.RequestID, response))
+		}
+	})
 }
 
 func (sc *ScraperCore) buildSearchURL(board JobBoard, keywords, location string) string {
@@ -564,6 +1359,9 @@ func (sc *ScraperCore) buildSearchURL(board JobBoard, keywords, location string)
 }
 
 func (sc *ScraperCore) getEnabledBoards() []JobBoard {
+	sc.configMutex.RLock()
+	defer sc.configMutex.RUnlock()
+
 	var enabled []JobBoard
 	for _, board := range sc.config.JobBoards {
 		if board.Enabled {
@@ -574,6 +1372,11 @@ func (sc *ScraperCore) getEnabledBoards() []JobBoard {
 }
 
 func (sc *ScraperCore) requiresJavaScript(board JobBoard) bool {
+	// Only a browser can scroll for more results
+	if board.Scroll != nil {
+		return true
+	}
+
 	// Force JavaScript rendering for more reliable scraping
 	jsRequiredBoards := []string{
 		"linkedin", "glassdoor", "indeed", "naukri", "angel", "wellfound",
@@ -601,6 +1404,9 @@ func (sc *ScraperCore) requiresJavaScript(board JobBoard) bool {
 }
 
 func (sc *ScraperCore) randomDelay() {
+	if transport.Offline() {
+		return
+	}
 	min := sc.config.GlobalSettings.Delay.Min
 	max := sc.config.GlobalSettings.Delay.Max
 	delay := rand.Intn(max-min) + min
@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+// defaultDetailConcurrency applies when a board fetching details sets no
+// concurrency
+const defaultDetailConcurrency = 2
+
+// DetailConfig reads the pages of a board's jobs when FetchDetails is set.
+// Selectors left empty fall back to the schema.org JobPosting data most job
+// pages embed for search engines.
+type DetailConfig struct {
+	Description string `json:"description,omitempty"`
+	Salary      string `json:"salary,omitempty"`
+	// PostedDate selects the posting date, read from the element's datetime
+	// or content attribute when it has one and from its text otherwise
+	PostedDate string `json:"postedDate,omitempty"`
+	// Concurrency bounds how many job pages are fetched at once. Defaults
+	// to 2; requests to one domain are still spaced by its rate limit.
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// fetchDetails visits the links of a board's jobs and fills in the full
+// description, salary and posted date their pages give. A job whose page
+// fails keeps what the results page said. Once the request budget of a
+// domain is spent, or ctx is done, the rest are left as they are.
+func (sc *ScraperCore) fetchDetails(ctx context.Context, board JobBoard, jobs []models.Job, log *logrus.Entry) {
+	var details DetailConfig
+	if board.Details != nil {
+		details = *board.Details
+	}
+	concurrency := details.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDetailConcurrency
+	}
+	userAgent := sc.config.GlobalSettings.UserAgent
+	if sc.proxyManager != nil {
+		userAgent = sc.proxyManager.UserAgentFor(board.Name)
+	}
+	interval := sc.requestInterval(board)
+
+	var wg sync.WaitGroup
+	var fetched, failed atomic.Int32
+	var stopped atomic.Bool
+	slots := make(chan struct{}, concurrency)
+	for i := range jobs {
+		if jobs[i].Link == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(job *models.Job) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if stopped.Load() {
+				return
+			}
+
+			err := sc.limiters.wait(ctx, job.Link, interval)
+			if err == nil {
+				var body []byte
+				if body, _, err = fetchPage(ctx, sc.client, job.Link, userAgent); err == nil {
+					err = applyDetails(details, job, body)
+				}
+			}
+			switch {
+			case err == nil:
+				fetched.Add(1)
+			case errors.Is(err, errs.ErrBudgetSpent) || ctx.Err() != nil:
+				if !stopped.Swap(true) {
+					log.Warnf("Stopped fetching job pages from %s: %v", board.Name, err)
+				}
+			default:
+				failed.Add(1)
+				log.Debugf("Failed to fetch job page %s: %v", job.Link, err)
+			}
+		}(&jobs[i])
+	}
+	wg.Wait()
+
+	if failed.Load() > 0 {
+		log.Warnf("Failed to fetch %d job pages from %s; those jobs keep the details of the results page", failed.Load(), board.Name)
+	}
+	log.Infof("Fetched %d job pages from %s", fetched.Load(), board.Name)
+}
+
+// applyDetails fills in job from the HTML of its page, leaving the fields
+// the page does not give as they are
+func applyDetails(details DetailConfig, job *models.Job, body []byte) error {
+	body, _ = decodeBody(body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	posting := findJobPosting(doc)
+
+	description := selectedText(doc, details.Description)
+	if description == "" && posting != nil {
+		description = htmlToText(posting.Description)
+	}
+	if description != "" {
+		job.Description = description
+	}
+
+	salary := selectedText(doc, details.Salary)
+	if salary == "" && posting != nil {
+		salary = salaryOf(posting.BaseSalary)
+	}
+	if salary != "" {
+		job.Salary = salary
+		// The listing's parsed range is for the salary replaced
+		job.SalaryRange = nil
+	}
+
+	var posted string
+	if details.PostedDate != "" {
+		selection := doc.Find(details.PostedDate).First()
+		posted = firstNonEmpty(selection.AttrOr("datetime", ""), selection.AttrOr("content", ""), selection.Text())
+	}
+	if posted == "" && posting != nil {
+		posted = posting.DatePosted
+	}
+	if t, ok := models.ParseTimestamp(posted, time.UTC); ok {
+		job.SetPosted(t)
+	}
+	return nil
+}
+
+// selectedText returns the text of the first element selector matches, or
+// "" when selector is empty or matches nothing
+func selectedText(doc *goquery.Document, selector string) string {
+	if selector == "" {
+		return ""
+	}
+	return collapseSpace(doc.Find(selector).First().Text())
+}
@@ -0,0 +1,423 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"hire.ai/pkg/rss"
+)
+
+// BoardProposal is a suggested job board entry derived from a fetched page
+type BoardProposal struct {
+	Board    JobBoard           `json:"board"`
+	Detected string             `json:"detected"` // "feed", ATS name, or "heuristic"
+	Coverage map[string]float64 `json:"coverage,omitempty"`
+	Matches  int                `json:"matches"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// atsProfile describes the selectors used by a known applicant tracking system
+type atsProfile struct {
+	name      string
+	hostHints []string
+	selectors Selectors
+}
+
+var knownATS = []atsProfile{
+	{
+		name:      "greenhouse",
+		hostHints: []string{"greenhouse.io"},
+		selectors: Selectors{
+			JobContainer: "div.opening, tr.job-post",
+			Title:        "a, p.body--medium",
+			Location:     ".location, p.body--metadata",
+			Link:         "a",
+		},
+	},
+	{
+		name:      "lever",
+		hostHints: []string{"lever.co"},
+		selectors: Selectors{
+			JobContainer: "div.posting",
+			Title:        "h5[data-qa='posting-name'], .posting-title h5",
+			Location:     ".posting-categories .location, .sort-by-location",
+			Description:  ".posting-categories",
+			Link:         "a.posting-title",
+		},
+	},
+	{
+		name:      "workable",
+		hostHints: []string{"workable.com"},
+		selectors: Selectors{
+			JobContainer: "li[data-ui='job']",
+			Title:        "h3[data-ui='job-title']",
+			Location:     "[data-ui='job-location']",
+			Link:         "a",
+		},
+	},
+}
+
+// fieldHints maps selector fields to class-name fragments that usually mark them
+var fieldHints = map[string][]string{
+	"title":       {"title", "position", "role", "job-name", "jobname"},
+	"company":     {"company", "employer", "organization", "org-name"},
+	"location":    {"location", "city", "place", "region"},
+	"salary":      {"salary", "compensation", "pay", "wage"},
+	"description": {"description", "summary", "snippet", "excerpt", "teaser"},
+}
+
+// DiscoverBoard fetches pageURL and proposes a job board entry for it. Feeds
+// and known ATS pages are recognised directly; anything else gets heuristic
+// selectors based on the most frequently repeated link-bearing element.
+func DiscoverBoard(ctx context.Context, client *http.Client, pageURL, userAgent string) (*BoardProposal, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", pageURL)
+	}
+
+	body, contentType, err := fetchPage(ctx, client, pageURL, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	board := JobBoard{
+		Name:           boardNameFromHost(parsed.Host),
+		Enabled:        true,
+		BaseURL:        parsed.Scheme + "://" + parsed.Host,
+		SearchPath:     parsed.Path,
+		SearchParams:   make(map[string]string),
+		ScrapingMethod: "scraping",
+		RateLimit:      3000,
+		MaxResults:     50,
+	}
+	for key, values := range parsed.Query() {
+		if len(values) > 0 {
+			board.SearchParams[key] = values[0]
+		}
+	}
+
+	// The URL itself may already be a feed
+	if feedType := detectFeedType(contentType, body); feedType != "" {
+		return feedProposal(board, pageURL, feedType), nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	// Advertised feeds are more stable than CSS selectors, so prefer them
+	if feedURL, feedType := findFeedLink(doc, parsed); feedURL != "" {
+		return feedProposal(board, feedURL, feedType), nil
+	}
+
+	proposal := &BoardProposal{Board: board, Detected: "heuristic"}
+	if ats := detectATS(parsed.Host, body); ats != nil {
+		proposal.Detected = ats.name
+		proposal.Board.Selectors = ats.selectors
+		proposal.Board.CompanyName = companyFromTitle(doc)
+	} else {
+		selectors, ok := proposeSelectors(doc)
+		if !ok {
+			proposal.Warnings = append(proposal.Warnings, "no repeated job-like elements found; the page may be rendered with JavaScript")
+		}
+		proposal.Board.Selectors = selectors
+		if selectors.Company == "" {
+			proposal.Board.CompanyName = companyFromTitle(doc)
+		}
+	}
+
+	proposal.Matches, proposal.Coverage = measureCoverage(doc, proposal.Board.Selectors)
+	return proposal, nil
+}
+
+// fetchPage GETs pageURL the way a browser would and returns up to 10 MB of
+// the body along with its content type
+func fetchPage(ctx context.Context, client *http.Client, pageURL, userAgent string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s returned status: %d", pageURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+func feedProposal(board JobBoard, feedURL, feedType string) *BoardProposal {
+	board.ScrapingMethod = "rss"
+	board.BaseURL = ""
+	board.SearchPath = ""
+	board.SearchParams = nil
+	board.RSSConfig = &rss.RSSJobBoard{
+		Name:       board.Name,
+		FeedURL:    feedURL,
+		FeedType:   feedType,
+		MaxResults: board.MaxResults,
+	}
+	return &BoardProposal{Board: board, Detected: "feed"}
+}
+
+func detectFeedType(contentType string, body []byte) string {
+	head := strings.ToLower(string(bytes.TrimSpace(body[:min(len(body), 512)])))
+	switch {
+	case strings.Contains(head, "<feed"):
+		return "atom"
+	case strings.Contains(head, "<rss"):
+		return "rss"
+	case strings.Contains(contentType, "atom+xml"):
+		return "atom"
+	case strings.Contains(contentType, "rss+xml"):
+		return "rss"
+	}
+	return ""
+}
+
+func findFeedLink(doc *goquery.Document, base *url.URL) (string, string) {
+	var feedURL, feedType string
+	doc.Find("link[rel='alternate']").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		linkType, _ := s.Attr("type")
+		href, _ := s.Attr("href")
+		if href == "" {
+			return true
+		}
+		switch {
+		case strings.Contains(linkType, "rss"):
+			feedType = "rss"
+		case strings.Contains(linkType, "atom"):
+			feedType = "atom"
+		default:
+			return true
+		}
+		if ref, err := base.Parse(href); err == nil {
+			feedURL = ref.String()
+		}
+		return false
+	})
+	return feedURL, feedType
+}
+
+func detectATS(host string, body []byte) *atsProfile {
+	host = strings.ToLower(host)
+	for i := range knownATS {
+		for _, hint := range knownATS[i].hostHints {
+			if strings.Contains(host, hint) {
+				return &knownATS[i]
+			}
+		}
+	}
+
+	// Embedded career pages often load the ATS from the company domain
+	text := strings.ToLower(string(body))
+	for i := range knownATS {
+		for _, hint := range knownATS[i].hostHints {
+			if strings.Contains(text, "boards."+hint) || strings.Contains(text, "jobs."+hint) {
+				return &knownATS[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// proposeSelectors picks the most repeated element that wraps a link as the job
+// container and then looks for child classes that hint at each field
+func proposeSelectors(doc *goquery.Document) (Selectors, bool) {
+	type candidate struct {
+		selector string
+		count    int
+		linked   int
+	}
+
+	skipTags := map[string]bool{"a": true, "span": true, "i": true, "img": true, "svg": true, "button": true, "option": true, "path": true}
+	candidates := make(map[string]*candidate)
+
+	doc.Find("[class]").Each(func(_ int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		if skipTags[tag] {
+			return
+		}
+		class, _ := s.Attr("class")
+		fields := strings.Fields(class)
+		if len(fields) == 0 {
+			return
+		}
+
+		selector := tag + "." + fields[0]
+		c, ok := candidates[selector]
+		if !ok {
+			c = &candidate{selector: selector}
+			candidates[selector] = c
+		}
+		c.count++
+		if s.Find("a[href]").Length() > 0 {
+			c.linked++
+		}
+	})
+
+	var ranked []*candidate
+	for _, c := range candidates {
+		// Job lists repeat, and nearly every entry links somewhere
+		if c.count >= 3 && c.count <= 500 && float64(c.linked)/float64(c.count) >= 0.8 {
+			ranked = append(ranked, c)
+		}
+	}
+	if len(ranked) == 0 {
+		return Selectors{JobContainer: "", Link: "a"}, false
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].linked != ranked[j].linked {
+			return ranked[i].linked > ranked[j].linked
+		}
+		return ranked[i].selector < ranked[j].selector
+	})
+
+	selectors := Selectors{JobContainer: ranked[0].selector, Link: "a"}
+	containers := doc.Find(selectors.JobContainer)
+
+	fieldSelectors := make(map[string]string)
+	for field, hints := range fieldHints {
+		fieldSelectors[field] = findHintedClass(containers, hints)
+	}
+
+	selectors.Title = fieldSelectors["title"]
+	selectors.Company = fieldSelectors["company"]
+	selectors.Location = fieldSelectors["location"]
+	selectors.Salary = fieldSelectors["salary"]
+	selectors.Description = fieldSelectors["description"]
+
+	if selectors.Title == "" {
+		for _, heading := range []string{"h2", "h3", "h4", "a"} {
+			if containers.First().Find(heading).Length() > 0 {
+				selectors.Title = heading
+				break
+			}
+		}
+	}
+
+	// Prefer the link wrapping the title when there is one
+	if selectors.Title != "" && containers.Find("a"+selectors.Title).Length() > 0 {
+		selectors.Link = "a" + selectors.Title
+	}
+
+	return selectors, true
+}
+
+func findHintedClass(containers *goquery.Selection, hints []string) string {
+	counts := make(map[string]int)
+	containers.Slice(0, min(containers.Length(), 20)).Find("[class]").Each(func(_ int, s *goquery.Selection) {
+		class, _ := s.Attr("class")
+		for _, name := range strings.Fields(class) {
+			lower := strings.ToLower(name)
+			for _, hint := range hints {
+				if strings.Contains(lower, hint) && strings.TrimSpace(s.Text()) != "" {
+					counts[name]++
+				}
+			}
+		}
+	})
+
+	best, bestCount := "", 0
+	for name, count := range counts {
+		if count > bestCount || (count == bestCount && name < best) {
+			best, bestCount = name, count
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return "." + best
+}
+
+// measureCoverage reports how many containers matched and what fraction of
+// them yielded a value for each configured selector
+func measureCoverage(doc *goquery.Document, selectors Selectors) (int, map[string]float64) {
+	if selectors.JobContainer == "" {
+		return 0, nil
+	}
+
+	containers := doc.Find(selectors.JobContainer)
+	total := containers.Length()
+	if total == 0 {
+		return 0, nil
+	}
+
+	fields := map[string]string{
+		"title":       selectors.Title,
+		"company":     selectors.Company,
+		"location":    selectors.Location,
+		"salary":      selectors.Salary,
+		"description": selectors.Description,
+		"link":        selectors.Link,
+	}
+
+	coverage := make(map[string]float64)
+	for field, selector := range fields {
+		if selector == "" {
+			continue
+		}
+		hits := 0
+		containers.Each(func(_ int, s *goquery.Selection) {
+			target := s.Find(selector).First()
+			if field == "link" {
+				if href, ok := target.Attr("href"); ok && href != "" {
+					hits++
+				}
+				return
+			}
+			if strings.TrimSpace(target.Text()) != "" {
+				hits++
+			}
+		})
+		coverage[field] = float64(hits) / float64(total)
+	}
+
+	return total, coverage
+}
+
+func companyFromTitle(doc *goquery.Document) string {
+	if name, ok := doc.Find("meta[property='og:site_name']").Attr("content"); ok && strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name)
+	}
+
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	for _, sep := range []string{" | ", " - ", " – ", " at "} {
+		if idx := strings.LastIndex(title, sep); idx != -1 {
+			return strings.TrimSpace(title[idx+len(sep):])
+		}
+	}
+	return ""
+}
+
+func boardNameFromHost(host string) string {
+	host = strings.ToLower(strings.TrimPrefix(host, "www."))
+	if idx := strings.Index(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+	return strings.ReplaceAll(host, ".", "-")
+}
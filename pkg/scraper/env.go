@@ -0,0 +1,319 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/proxy"
+)
+
+// EnvPrefix starts the names of the environment variables that override
+// config keys. The rest of the name is the key's path in upper snake case:
+// HIREAI_GLOBAL_TIMEOUT sets globalSettings.timeout,
+// HIREAI_BOARD_<NAME>_ENABLED the enabled flag of the board named NAME and
+// HIREAI_PROVIDER_<NAME>_API_KEY an API provider's key. Lists take
+// comma-separated values; lists of objects and maps cannot be overridden.
+const EnvPrefix = "HIREAI_"
+
+// envReference matches ${VAR} and ${VAR:-default} in config strings; $${
+// stands for a literal ${
+var envReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// envReport lists what applyEnv did, for logging once a logger exists.
+// Values are left out as they are often secrets.
+type envReport struct {
+	overridden []string // HIREAI_ variables applied
+	unknown    []string // HIREAI_ variables that match no config key
+	unset      []string // variables referenced without a default but not set
+}
+
+// applyEnv expands ${VAR} references in the config's strings and then
+// applies the HIREAI_ overrides, so containers can configure everything
+// from the environment. proxyConfig expands its own references, escaping
+// them for URLs, so it is left to do so.
+func applyEnv(config *Config) (envReport, error) {
+	var report envReport
+	expandStrings(reflect.ValueOf(config).Elem(), &report)
+
+	if overrides := envOverrides(); len(overrides) > 0 {
+		w := &envWalker{overrides: overrides, report: &report}
+		if err := w.override(reflect.ValueOf(&config.GlobalSettings).Elem(), EnvPrefix+"GLOBAL"); err != nil {
+			return report, err
+		}
+		for i := range config.JobBoards {
+			if err := w.override(reflect.ValueOf(&config.JobBoards[i]).Elem(), EnvPrefix+"BOARD_"+envName(config.JobBoards[i].Name)); err != nil {
+				return report, err
+			}
+		}
+		for i := range config.APIProviders {
+			if err := w.override(reflect.ValueOf(&config.APIProviders[i]).Elem(), EnvPrefix+"PROVIDER_"+envName(config.APIProviders[i].Name)); err != nil {
+				return report, err
+			}
+		}
+		for name := range w.overrides {
+			report.unknown = append(report.unknown, name)
+		}
+	}
+
+	sort.Strings(report.overridden)
+	sort.Strings(report.unknown)
+	sort.Strings(report.unset)
+	return report, nil
+}
+
+// applyBoardEnv expands references in a board saved while running and
+// applies its HIREAI_BOARD_ overrides, as applyEnv does at startup. The
+// board is copied first, so the caller's config stays as written.
+func applyBoardEnv(board JobBoard) (JobBoard, error) {
+	var applied JobBoard
+	if err := cloneConfig(board, &applied); err != nil {
+		return JobBoard{}, err
+	}
+	return applied, applyEntryEnv(reflect.ValueOf(&applied).Elem(), EnvPrefix+"BOARD_"+envName(board.Name))
+}
+
+// applyProviderEnv is applyBoardEnv for an API provider and its
+// HIREAI_PROVIDER_ overrides
+func applyProviderEnv(provider api.APIConfig) (api.APIConfig, error) {
+	var applied api.APIConfig
+	if err := cloneConfig(provider, &applied); err != nil {
+		return api.APIConfig{}, err
+	}
+	return applied, applyEntryEnv(reflect.ValueOf(&applied).Elem(), EnvPrefix+"PROVIDER_"+envName(provider.Name))
+}
+
+func applyEntryEnv(v reflect.Value, name string) error {
+	var report envReport
+	expandStrings(v, &report)
+	w := &envWalker{overrides: envOverrides(), report: &report}
+	return w.override(v, name)
+}
+
+// cloneConfig deep-copies a config entry through JSON, so expanding the copy
+// leaves the pointers and maps of the original alone
+func cloneConfig(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// envOverrides returns the HIREAI_ variables by name
+func envOverrides() map[string]string {
+	overrides := make(map[string]string)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, EnvPrefix) {
+			overrides[name] = value
+		}
+	}
+	return overrides
+}
+
+// expandStrings replaces environment variable references in every string
+// reachable from v
+func expandStrings(v reflect.Value, report *envReport) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() && strings.Contains(v.String(), "${") {
+			v.SetString(expandReferences(v.String(), report))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() && v.Type() != reflect.TypeOf(&proxy.ProxyConfig{}) {
+			expandStrings(v.Elem(), report)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				expandStrings(v.Field(i), report)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			expandStrings(v.Index(i), report)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			if value := v.MapIndex(key).String(); strings.Contains(value, "${") {
+				v.SetMapIndex(key, reflect.ValueOf(expandReferences(value, report)).Convert(v.Type().Elem()))
+			}
+		}
+	}
+}
+
+func expandReferences(s string, report *envReport) string {
+	return envReference.ReplaceAllStringFunc(s, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference[1:]
+		}
+		match := envReference.FindStringSubmatch(reference)
+		value, ok := os.LookupEnv(match[1])
+		if match[2] != "" && value == "" {
+			return match[3]
+		}
+		if !ok {
+			report.unset = append(report.unset, match[1])
+		}
+		return value
+	})
+}
+
+// envWalker applies overrides to config values, removing each one used
+type envWalker struct {
+	overrides map[string]string
+	report    *envReport
+}
+
+// override sets v, or the fields under it, from the variables named after
+// their path from name
+func (w *envWalker) override(v reflect.Value, name string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := name
+			if !field.Anonymous {
+				key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if key == "-" {
+					continue
+				}
+				if key == "" {
+					key = field.Name
+				}
+				fieldName = name + "_" + envName(key)
+			}
+			if err := w.override(v.Field(i), fieldName); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Pointer:
+		// A section missing from the file is created when an override
+		// sets something in it
+		if !v.IsNil() {
+			return w.override(v.Elem(), name)
+		}
+		created := reflect.New(v.Type().Elem())
+		applied := len(w.report.overridden)
+		if err := w.override(created.Elem(), name); err != nil {
+			return err
+		}
+		if len(w.report.overridden) > applied {
+			v.Set(created)
+		}
+		return nil
+
+	case reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+	}
+
+	value, ok := w.overrides[name]
+	if !ok {
+		return nil
+	}
+	delete(w.overrides, name)
+	if err := setFromEnv(v, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	w.report.overridden = append(w.report.overridden, name)
+	return nil
+}
+
+// setFromEnv parses value into v according to its kind
+func setFromEnv(v reflect.Value, value string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(items)
+	default:
+		return fmt.Errorf("cannot set a %s from the environment", v.Type())
+	}
+	return nil
+}
+
+// envName converts a config key or board name to upper snake case:
+// maxResultsPerBoard becomes MAX_RESULTS_PER_BOARD and "Remote OK"
+// becomes REMOTE_OK
+func envName(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
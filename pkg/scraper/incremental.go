@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+
+	"hire.ai/pkg/models"
+)
+
+// defaultMaxPages applies when a board with a next page selector sets no
+// MaxPages
+const defaultMaxPages = 5
+
+// KnownJobs reports whether a scraped job was found by an earlier run
+type KnownJobs func(job *models.Job) bool
+
+type knownJobsKey struct{}
+
+// WithKnownJobs returns a copy of ctx making board scrapes incremental:
+// a board stops following its next page links after the first page whose
+// jobs known reports were all found before
+func WithKnownJobs(ctx context.Context, known KnownJobs) context.Context {
+	return context.WithValue(ctx, knownJobsKey{}, known)
+}
+
+// knownJobs returns the check carried by ctx, or nil when the scrape is
+// not incremental
+func knownJobs(ctx context.Context) KnownJobs {
+	known, _ := ctx.Value(knownJobsKey{}).(KnownJobs)
+	return known
+}
+
+// allKnown reports whether known reports every one of jobs
+func allKnown(known KnownJobs, jobs []models.Job) bool {
+	for i := range jobs {
+		if !known(&jobs[i]) {
+			return false
+		}
+	}
+	return true
+}
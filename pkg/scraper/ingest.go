@@ -0,0 +1,293 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"hire.ai/pkg/models"
+)
+
+// maxDescriptionLength caps descriptions taken from page text rather than
+// structured data, which would otherwise pull in navigation and footers
+const maxDescriptionLength = 5000
+
+// FetchPosting downloads a single job posting page and parses it with
+// ParsePosting
+func FetchPosting(ctx context.Context, client *http.Client, pageURL, userAgent string) (*models.Job, error) {
+	body, _, err := fetchPage(ctx, client, pageURL, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePosting(pageURL, body)
+}
+
+// ParsePosting extracts one job from the HTML of a posting page. schema.org
+// JobPosting data, which most ATS pages embed for search engines, is used
+// when present; otherwise the title, site name and description come from the
+// page's meta tags and headings. Fields it cannot find are left empty.
+func ParsePosting(pageURL string, body []byte) (*models.Job, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	job := &models.Job{Link: pageURL}
+	if posting := findJobPosting(doc); posting != nil {
+		posting.apply(job)
+	}
+
+	if job.Title == "" {
+		job.Title = firstNonEmpty(
+			metaContent(doc, "meta[property='og:title']"),
+			doc.Find("h1").First().Text(),
+			doc.Find("title").First().Text(),
+		)
+	}
+	if job.Company == "" {
+		job.Company = companyFromTitle(doc)
+	}
+	if job.Description == "" {
+		job.Description = firstNonEmpty(
+			collapseSpace(doc.Find("main, article, [role='main']").First().Text()),
+			metaContent(doc, "meta[property='og:description']"),
+			metaContent(doc, "meta[name='description']"),
+		)
+		job.Description = truncate(job.Description, maxDescriptionLength)
+	}
+
+	job.Title = collapseSpace(job.Title)
+	return job, nil
+}
+
+// PostingFromText builds a job from text the user selected on a posting
+// page; the first line is taken as the title
+func PostingFromText(pageURL, text string) *models.Job {
+	text = strings.TrimSpace(text)
+	title, _, _ := strings.Cut(text, "\n")
+
+	return &models.Job{
+		Title:       truncate(collapseSpace(title), 200),
+		Description: text,
+		Link:        pageURL,
+	}
+}
+
+// jobPosting holds the schema.org JobPosting fields ParsePosting uses. The
+// nested values vary between sites (objects, arrays or plain strings), so
+// they are decoded loosely.
+type jobPosting struct {
+	Title              string      `json:"title"`
+	Description        string      `json:"description"`
+	HiringOrganization interface{} `json:"hiringOrganization"`
+	JobLocation        interface{} `json:"jobLocation"`
+	JobLocationType    string      `json:"jobLocationType"`
+	BaseSalary         interface{} `json:"baseSalary"`
+	DatePosted         string      `json:"datePosted"`
+}
+
+func (p *jobPosting) apply(job *models.Job) {
+	job.Title = p.Title
+	job.Description = htmlToText(p.Description)
+	job.Company = nameOf(p.HiringOrganization)
+	job.Location = locationOf(p.JobLocation)
+	if strings.EqualFold(p.JobLocationType, "TELECOMMUTE") {
+		job.Location = strings.TrimPrefix(job.Location+", Remote", ", ")
+	}
+	job.Salary = salaryOf(p.BaseSalary)
+	if posted, ok := models.ParseTimestamp(p.DatePosted, time.UTC); ok {
+		job.SetPosted(posted)
+	}
+}
+
+// findJobPosting returns the first JobPosting in the page's JSON-LD blocks,
+// looking inside arrays and @graph containers
+func findJobPosting(doc *goquery.Document) *jobPosting {
+	var found *jobPosting
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(_ int, script *goquery.Selection) bool {
+		var data interface{}
+		if json.Unmarshal([]byte(script.Text()), &data) != nil {
+			return true
+		}
+		found = searchJobPosting(data)
+		return found == nil
+	})
+	return found
+}
+
+func searchJobPosting(data interface{}) *jobPosting {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if posting := searchJobPosting(item); posting != nil {
+				return posting
+			}
+		}
+	case map[string]interface{}:
+		if isType(value["@type"], "JobPosting") {
+			encoded, _ := json.Marshal(value)
+			var posting jobPosting
+			if json.Unmarshal(encoded, &posting) == nil {
+				return &posting
+			}
+		}
+		if graph, ok := value["@graph"]; ok {
+			return searchJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+// isType matches a JSON-LD @type, which may be a string or a list
+func isType(value interface{}, name string) bool {
+	switch typed := value.(type) {
+	case string:
+		return typed == name
+	case []interface{}:
+		for _, item := range typed {
+			if item == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nameOf(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case map[string]interface{}:
+		name, _ := typed["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// locationOf joins the localities of one or more schema.org Place values
+func locationOf(value interface{}) string {
+	var places []string
+	switch typed := value.(type) {
+	case []interface{}:
+		for _, item := range typed {
+			if place := locationOf(item); place != "" {
+				places = append(places, place)
+			}
+		}
+	case map[string]interface{}:
+		address, ok := typed["address"].(map[string]interface{})
+		if !ok {
+			return nameOf(typed)
+		}
+		var parts []string
+		for _, field := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+			switch part := address[field].(type) {
+			case string:
+				if part != "" {
+					parts = append(parts, part)
+				}
+			case map[string]interface{}:
+				if name := nameOf(part); name != "" {
+					parts = append(parts, name)
+				}
+			}
+		}
+		return strings.Join(parts, ", ")
+	case string:
+		return typed
+	}
+	return strings.Join(places, "; ")
+}
+
+// salaryOf formats a schema.org MonetaryAmount such as
+// {"currency": "USD", "value": {"minValue": 100000, "maxValue": 120000, "unitText": "YEAR"}}
+func salaryOf(value interface{}) string {
+	amount, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	currency, _ := amount["currency"].(string)
+
+	var figures, unit string
+	switch quantity := amount["value"].(type) {
+	case float64:
+		figures = fmt.Sprintf("%.0f", quantity)
+	case map[string]interface{}:
+		unit, _ = quantity["unitText"].(string)
+		min, hasMin := quantity["minValue"].(float64)
+		max, hasMax := quantity["maxValue"].(float64)
+		exact, hasExact := quantity["value"].(float64)
+		switch {
+		case hasMin && hasMax:
+			figures = fmt.Sprintf("%.0f-%.0f", min, max)
+		case hasExact:
+			figures = fmt.Sprintf("%.0f", exact)
+		case hasMin:
+			figures = fmt.Sprintf("%.0f+", min)
+		}
+	}
+	if figures == "" {
+		return ""
+	}
+
+	salary := strings.TrimSpace(currency + " " + figures)
+	if unit != "" {
+		salary += " per " + strings.ToLower(unit)
+	}
+	return salary
+}
+
+func htmlToText(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return collapseSpace(fragment)
+	}
+	return collapseSpace(doc.Text())
+}
+
+func metaContent(doc *goquery.Document, selector string) string {
+	content, _ := doc.Find(selector).First().Attr("content")
+	return content
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
+
+// CompanyFromURL names the employer after a posting's host when a page gives
+// no better clue, e.g. "jobs.example.com" becomes "example.com"
+func CompanyFromURL(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, prefix := range []string{"www.", "jobs.", "careers.", "boards."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
@@ -0,0 +1,293 @@
+package scraper
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/width"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/salary"
+)
+
+// LocaleRules describe how a board writes salaries and locations, so that
+// localized postings parse like English ones. A board's rules add to the
+// built-in rules of its locale.
+type LocaleRules struct {
+	// DecimalComma is set for boards writing 1.234,56
+	DecimalComma *bool `json:"decimalComma,omitempty"`
+	// Labels are removed from the start of salaries and locations when a
+	// colon follows, as in "Gehalt: 60.000 €"
+	Labels []string `json:"labels,omitempty"`
+	// SalaryWords translate words in salaries for the salary parser, such
+	// as "pro Jahr" to "per year" or "円" to "JPY"
+	SalaryWords map[string]string `json:"salaryWords,omitempty"`
+	// Multipliers scale the figure before them, such as 10000 for "万"
+	Multipliers map[string]float64 `json:"multipliers,omitempty"`
+	// RemoteWords mark remote work in locations and are replaced by
+	// "Remote"
+	RemoteWords []string `json:"remoteWords,omitempty"`
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// builtinLocales are the rules of the languages boards commonly use,
+// keyed by base language
+var builtinLocales = map[string]LocaleRules{
+	"de": {
+		DecimalComma: boolPtr(true),
+		Labels:       []string{"Gehalt", "Vergütung", "Standort", "Arbeitsort", "Ort"},
+		SalaryWords: map[string]string{
+			"pro Jahr": "per year", "jährlich": "per year", "Jahresgehalt": "per year",
+			"pro Monat": "per month", "monatlich": "per month", "Monatsgehalt": "per month",
+			"pro Stunde": "per hour", "Stundenlohn": "per hour", "bis": "-",
+		},
+		Multipliers: map[string]float64{"Tsd.": 1000, "Tsd": 1000},
+		RemoteWords: []string{"Homeoffice", "Home-Office", "Fernarbeit", "mobiles Arbeiten"},
+	},
+	"fr": {
+		DecimalComma: boolPtr(true),
+		Labels:       []string{"Salaire", "Rémunération", "Lieu", "Localisation"},
+		SalaryWords: map[string]string{
+			"par an": "per year", "annuel": "per year", "par mois": "per month", "mensuel": "per month",
+			"par heure": "per hour", "de l'heure": "per hour", "à": "-",
+		},
+		RemoteWords: []string{"télétravail", "à distance"},
+	},
+	"es": {
+		DecimalComma: boolPtr(true),
+		Labels:       []string{"Salario", "Sueldo", "Ubicación", "Ciudad"},
+		SalaryWords: map[string]string{
+			"al año": "per year", "por año": "per year", "anual": "per year",
+			"al mes": "per month", "por mes": "per month", "mensual": "per month", "por hora": "per hour",
+		},
+		Multipliers: map[string]float64{"mil": 1000, "millones": 1000000, "millón": 1000000},
+		RemoteWords: []string{"remoto", "teletrabajo", "a distancia"},
+	},
+	"pt": {
+		DecimalComma: boolPtr(true),
+		Labels:       []string{"Salário", "Remuneração", "Local", "Localização", "Cidade"},
+		SalaryWords: map[string]string{
+			"por ano": "per year", "anual": "per year", "por mês": "per month", "mensal": "per month",
+			"/mês": "/mo", "por hora": "per hour", "R$": "BRL ", "até": "-",
+		},
+		Multipliers: map[string]float64{"mil": 1000, "milhões": 1000000, "milhão": 1000000},
+		RemoteWords: []string{"remoto", "home office", "teletrabalho"},
+	},
+	"ja": {
+		Labels: []string{"給与", "給料", "勤務地", "所在地"},
+		SalaryWords: map[string]string{
+			"年収": "per year ", "年俸": "per year ", "月給": "per month ", "月収": "per month ",
+			"日給": "per day ", "時給": "per hour ", "円": " JPY", "~": "-", "〜": "-",
+		},
+		Multipliers: map[string]float64{"万": 10000, "億": 100000000},
+		RemoteWords: []string{"フルリモート", "リモート", "在宅勤務", "テレワーク"},
+	},
+}
+
+// localizer applies a board's locale rules to the jobs it scraped
+type localizer struct {
+	decimalComma bool
+	labels       *regexp.Regexp
+	salaryWords  *regexp.Regexp
+	translations map[string]string
+	multipliers  *regexp.Regexp
+	factors      map[string]float64
+	remoteWords  *regexp.Regexp
+}
+
+var (
+	// groupedFigure matches figures written with a decimal comma, such as
+	// 60.000 or 1.234,56, and spaceGroupedFigure ones grouped by spaces,
+	// such as 60 000
+	groupedFigure      = regexp.MustCompile(`\d{1,3}(?:\.\d{3})+(?:,\d+)?|\d+,\d+`)
+	spaceGroupedFigure = regexp.MustCompile(`\d{1,3}(?:[ \x{00a0}\x{202f}]\d{3})+\b`)
+)
+
+// newLocalizer merges the built-in rules of locale with the board's own;
+// it returns nil when there are none
+func newLocalizer(locale string, rules *LocaleRules) *localizer {
+	var merged LocaleRules
+	if locale != "" {
+		if tag, err := language.Parse(locale); err == nil {
+			base, _ := tag.Base()
+			merged = mergeLocaleRules(merged, builtinLocales[base.String()])
+		}
+	}
+	if rules != nil {
+		merged = mergeLocaleRules(merged, *rules)
+	}
+	if len(merged.Labels) == 0 && len(merged.SalaryWords) == 0 && len(merged.Multipliers) == 0 &&
+		len(merged.RemoteWords) == 0 && merged.DecimalComma == nil {
+		return nil
+	}
+
+	l := &localizer{
+		decimalComma: merged.DecimalComma != nil && *merged.DecimalComma,
+		translations: make(map[string]string, len(merged.SalaryWords)),
+		factors:      make(map[string]float64, len(merged.Multipliers)),
+	}
+	if len(merged.Labels) > 0 {
+		l.labels = regexp.MustCompile(`(?i)^\s*(?:` + alternation(merged.Labels) + `)\s*[:：]\s*`)
+	}
+	if len(merged.SalaryWords) > 0 {
+		words := make([]string, 0, len(merged.SalaryWords))
+		for word, translation := range merged.SalaryWords {
+			words = append(words, word)
+			l.translations[strings.ToLower(word)] = translation
+		}
+		l.salaryWords = regexp.MustCompile(`(?i)` + alternation(words))
+	}
+	if len(merged.Multipliers) > 0 {
+		words := make([]string, 0, len(merged.Multipliers))
+		for word, factor := range merged.Multipliers {
+			words = append(words, word)
+			l.factors[strings.ToLower(word)] = factor
+		}
+		// Words ending in a letter must end there, so "mil" leaves
+		// "5 milhões" alone
+		pattern := alternation(words)
+		pattern = regexp.MustCompile(`([A-Za-z])(\||$)`).ReplaceAllString(pattern, `$1\b$2`)
+		l.multipliers = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(` + pattern + `)`)
+	}
+	if len(merged.RemoteWords) > 0 {
+		l.remoteWords = regexp.MustCompile(`(?i)` + alternation(merged.RemoteWords))
+	}
+	return l
+}
+
+func mergeLocaleRules(base, extra LocaleRules) LocaleRules {
+	if extra.DecimalComma != nil {
+		base.DecimalComma = extra.DecimalComma
+	}
+	base.Labels = append(append([]string(nil), base.Labels...), extra.Labels...)
+	base.RemoteWords = append(append([]string(nil), base.RemoteWords...), extra.RemoteWords...)
+	words := make(map[string]string, len(base.SalaryWords)+len(extra.SalaryWords))
+	for word, translation := range base.SalaryWords {
+		words[word] = translation
+	}
+	for word, translation := range extra.SalaryWords {
+		words[word] = translation
+	}
+	base.SalaryWords = words
+	factors := make(map[string]float64, len(base.Multipliers)+len(extra.Multipliers))
+	for word, factor := range base.Multipliers {
+		factors[word] = factor
+	}
+	for word, factor := range extra.Multipliers {
+		factors[word] = factor
+	}
+	base.Multipliers = factors
+	return base
+}
+
+// alternation matches any of words, preferring the longest
+func alternation(words []string) string {
+	sorted := append([]string(nil), words...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, word := range sorted {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// localize applies the board's locale rules, if it has any, to jobs
+func localize(board JobBoard, jobs []models.Job) {
+	if len(jobs) == 0 {
+		return
+	}
+	if l := newLocalizer(board.Locale, board.LocaleRules); l != nil {
+		l.apply(jobs)
+	}
+}
+
+// acceptLanguage asks for a board's pages in its locale, falling back to
+// English
+func acceptLanguage(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "en-US,en;q=0.5"
+	}
+	base, _ := tag.Base()
+	switch {
+	case base.String() == "en":
+		return tag.String() + ",en;q=0.5"
+	case tag.String() == base.String():
+		return tag.String() + ",en;q=0.5"
+	}
+	return tag.String() + "," + base.String() + ";q=0.8,en;q=0.5"
+}
+
+// apply strips labels from the salary and location of jobs, marks remote
+// locations and parses salaries by the locale's rules. Salaries keep their
+// localized text; the parsed range is set so the English parser is not
+// run on them again.
+func (l *localizer) apply(jobs []models.Job) {
+	for i := range jobs {
+		job := &jobs[i]
+		job.Location = l.location(job.Location)
+		job.Salary = l.stripLabel(width.Fold.String(job.Salary))
+		if job.Salary != "" {
+			job.SalaryRange = salary.Parse(l.salaryText(job.Salary))
+		}
+	}
+}
+
+func (l *localizer) stripLabel(text string) string {
+	text = strings.TrimSpace(text)
+	if l.labels != nil {
+		text = l.labels.ReplaceAllString(text, "")
+	}
+	return text
+}
+
+func (l *localizer) location(text string) string {
+	text = l.stripLabel(width.Fold.String(text))
+	if l.remoteWords != nil {
+		text = l.remoteWords.ReplaceAllString(text, "Remote")
+	}
+	return text
+}
+
+// salaryText rewrites a localized salary in the form the salary parser
+// reads: figures with decimal points and no grouping, multipliers applied
+// and period and currency words in English
+func (l *localizer) salaryText(text string) string {
+	text = spaceGroupedFigure.ReplaceAllStringFunc(text, func(figure string) string {
+		return strings.Map(func(r rune) rune {
+			if r == ' ' || r == '\u00a0' || r == '\u202f' {
+				return -1
+			}
+			return r
+		}, figure)
+	})
+	if l.decimalComma {
+		text = groupedFigure.ReplaceAllStringFunc(text, func(figure string) string {
+			return strings.ReplaceAll(strings.ReplaceAll(figure, ".", ""), ",", ".")
+		})
+	} else {
+		text = strings.ReplaceAll(text, ",", "")
+	}
+	if l.multipliers != nil {
+		text = l.multipliers.ReplaceAllStringFunc(text, func(match string) string {
+			parts := l.multipliers.FindStringSubmatch(match)
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return match
+			}
+			return strconv.FormatFloat(value*l.factors[strings.ToLower(parts[2])], 'f', -1, 64)
+		})
+	}
+	if l.salaryWords != nil {
+		text = l.salaryWords.ReplaceAllStringFunc(text, func(word string) string {
+			return " " + l.translations[strings.ToLower(word)] + " "
+		})
+	}
+	return text
+}
@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gocolly/colly/v2/debug"
+	"github.com/sirupsen/logrus"
+)
+
+// logrusDebugger forwards colly collector events to logrus at debug level so
+// they share the run's format, destination and correlation fields
+type logrusDebugger struct {
+	log *logrus.Entry
+}
+
+func (d *logrusDebugger) Init() error {
+	return nil
+}
+
+func (d *logrusDebugger) Event(e *debug.Event) {
+	fields := logrus.Fields{
+		"collector_id": e.CollectorID,
+		"request_id":   e.RequestID,
+	}
+	for key, value := range e.Values {
+		fields[key] = value
+	}
+	d.log.WithFields(fields).Debugf("colly %s", e.Type)
+}
+
+type runIDKey struct{}
+
+// WithRunID returns a copy of ctx carrying the ID of the scrape run it
+// belongs to, so the run's logs and its recorded report share one ID
+func WithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, id)
+}
+
+// RunID returns the run ID carried by ctx, or a new one when it has none
+func RunID(ctx context.Context) string {
+	if id, ok := ctx.Value(runIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return NewRunID()
+}
+
+// NewRunID returns a sortable, reasonably unique identifier for a run
+func NewRunID() string {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405"), time.Now().UnixNano()%10000)
+	}
+	return fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405"), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,91 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"hire.ai/pkg/transport"
+)
+
+// defaultMaxRequestsPerSecond is the politeness ceiling on requests across
+// every board when GlobalSettings.MaxRequestsPerSecond is unset
+const defaultMaxRequestsPerSecond = 5
+
+// domainLimiters spaces the requests to each domain by the RateLimit of the
+// boards that scrape it, so a slow board only holds back its own domain,
+// while a global limiter caps the requests of all boards together and
+// budgets cap each domain's requests an hour
+type domainLimiters struct {
+	global  *rate.Limiter
+	budgets *requestBudgets
+	mutex   sync.Mutex
+	domains map[string]*rate.Limiter
+}
+
+func newDomainLimiters(maxRequestsPerSecond float64, budgets *requestBudgets) *domainLimiters {
+	if maxRequestsPerSecond <= 0 {
+		maxRequestsPerSecond = defaultMaxRequestsPerSecond
+	}
+	return &domainLimiters{
+		global:  rate.NewLimiter(rate.Limit(maxRequestsPerSecond), 1),
+		budgets: budgets,
+		domains: make(map[string]*rate.Limiter),
+	}
+}
+
+// wait blocks until a request to rawURL may go out, at most one per
+// interval to its domain and within the global ceiling, and counts it
+// against its domain's budget. Boards sharing a domain share its limiter,
+// which keeps the longest interval asked for. A spent budget returns a
+// *BudgetError at once.
+func (d *domainLimiters) wait(ctx context.Context, rawURL string, interval time.Duration) error {
+	// Replayed responses need no pacing and spend no budget
+	if transport.Offline() {
+		return ctx.Err()
+	}
+	if limiter := d.domain(rawURL, interval); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if err := d.global.Wait(ctx); err != nil {
+		return err
+	}
+	return d.budgets.spend(rawURL)
+}
+
+// domain returns the limiter for rawURL's domain, or nil when there is no
+// interval to keep to
+func (d *domainLimiters) domain(rawURL string, interval time.Duration) *rate.Limiter {
+	host := hostOf(rawURL)
+	if host == "" {
+		return nil
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	limiter, ok := d.domains[host]
+	if interval <= 0 {
+		return limiter
+	}
+	limit := rate.Every(interval)
+	if !ok {
+		limiter = rate.NewLimiter(limit, 1)
+		d.domains[host] = limiter
+	} else if limit < limiter.Limit() {
+		limiter.SetLimit(limit)
+	}
+	return limiter
+}
+
+// requestInterval is the spacing of a board's requests to its domain: its
+// RateLimit, or the minimum delay when it sets none
+func (sc *ScraperCore) requestInterval(board JobBoard) time.Duration {
+	if board.RateLimit > 0 {
+		return time.Duration(board.RateLimit) * time.Millisecond
+	}
+	return time.Duration(sc.config.GlobalSettings.Delay.Min) * time.Millisecond
+}
@@ -0,0 +1,53 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxRevisitBytes caps how much of a revisited page is read; the text that
+// says a posting is closed sits near the top
+const maxRevisitBytes = 2 << 20
+
+// Revisit requests a stored job's link again and returns the response
+// status with the page, which is left empty when head is set. Requests are
+// spaced by the minimum delay within each domain and count against its
+// request budget, so a spent budget returns a *BudgetError. Redirects are
+// followed.
+func (sc *ScraperCore) Revisit(ctx context.Context, link string, head bool) (int, []byte, error) {
+	interval := time.Duration(sc.config.GlobalSettings.Delay.Min) * time.Millisecond
+	if err := sc.limiters.wait(ctx, link, interval); err != nil {
+		return 0, nil, err
+	}
+
+	method := http.MethodGet
+	if head {
+		method = http.MethodHead
+	}
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if userAgent := sc.config.GlobalSettings.UserAgent; userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to fetch %s: %w", link, err)
+	}
+	defer resp.Body.Close()
+	if head {
+		return resp.StatusCode, nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRevisitBytes))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
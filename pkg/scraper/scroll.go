@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/sirupsen/logrus"
+)
+
+// Scrolling defaults, for boards that set a scroll section without them
+const (
+	defaultMaxScrolls  = 10
+	defaultScrollDelay = 1500 * time.Millisecond
+	defaultIdleScrolls = 2
+)
+
+// extractReserve is the part of the page timeout scrolling leaves for
+// reading the jobs off the page
+const extractReserve = 5 * time.Second
+
+// ScrollConfig loads more results on JavaScript boards that add them as the
+// page is scrolled, like LinkedIn, instead of linking to further pages
+type ScrollConfig struct {
+	// MaxScrolls bounds how many times the results are scrolled to the
+	// bottom. Defaults to 10.
+	MaxScrolls int `json:"maxScrolls,omitempty"`
+	// ScrollDelayMs is how long to wait after each scroll for results to
+	// load. Defaults to 1500.
+	ScrollDelayMs int `json:"scrollDelayMs,omitempty"`
+	// IdleScrolls is how many scrolls in a row may bring no new job
+	// containers before scrolling stops. Defaults to 2.
+	IdleScrolls int `json:"idleScrolls,omitempty"`
+	// Element selects the element the results scroll in, for boards that
+	// list them in a panel rather than scrolling the page
+	Element string `json:"element,omitempty"`
+	// LoadMore selects a "show more" button clicked after each scroll
+	LoadMore string `json:"loadMore,omitempty"`
+}
+
+// scrollForMore scrolls a board's results until MaxScrolls is reached, the
+// page holds maxResults job containers, or IdleScrolls scrolls in a row add
+// none. It returns how many containers the page then holds; a failed
+// scroll ends scrolling with the containers loaded so far.
+func (sc *ScraperCore) scrollForMore(ctx context.Context, board JobBoard, containers, maxResults int, log *logrus.Entry) int {
+	scroll := board.Scroll
+	maxScrolls := scroll.MaxScrolls
+	if maxScrolls <= 0 {
+		maxScrolls = defaultMaxScrolls
+	}
+	delay := time.Duration(scroll.ScrollDelayMs) * time.Millisecond
+	if delay <= 0 {
+		delay = defaultScrollDelay
+	}
+	idleScrolls := scroll.IdleScrolls
+	if idleScrolls <= 0 {
+		idleScrolls = defaultIdleScrolls
+	}
+
+	target := `document.scrollingElement`
+	if scroll.Element != "" {
+		target = `document.querySelector('` + scroll.Element + `')`
+	}
+	scrollScript := `(() => { const el = ` + target + `; if (el) el.scrollTop = el.scrollHeight; })()`
+	if scroll.LoadMore != "" {
+		scrollScript += `; document.querySelector('` + scroll.LoadMore + `')?.click()`
+	}
+	countScript := `document.querySelectorAll('` + board.Selectors.JobContainer + `').length`
+
+	scrolls, idle := 0, 0
+	for ; scrolls < maxScrolls && idle < idleScrolls && containers < maxResults; scrolls++ {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay+extractReserve {
+			log.Warnf("Stopped scrolling %s after %d scrolls: the page timeout is near", board.Name, scrolls)
+			break
+		}
+		var count int
+		err := chromedp.Run(ctx,
+			chromedp.Evaluate(scrollScript, nil),
+			chromedp.Sleep(delay),
+			chromedp.Evaluate(countScript, &count),
+		)
+		if err != nil {
+			log.Warnf("Stopped scrolling %s after %d scrolls: %v", board.Name, scrolls, err)
+			break
+		}
+		if count > containers {
+			containers, idle = count, 0
+		} else {
+			idle++
+		}
+	}
+	log.Debugf("Scrolled %s %d times to %d job containers", board.Name, scrolls, containers)
+	return containers
+}
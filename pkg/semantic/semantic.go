@@ -0,0 +1,198 @@
+// Package semantic searches the stored jobs by meaning. It embeds each
+// job's title and description as the job is stored and ranks jobs by the
+// cosine similarity of their embedding to a natural-language query's.
+package semantic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the semanticSearch section of GlobalSettings. Model names an
+// embedding model, e.g. text-embedding-3-small on OpenAI or
+// nomic-embed-text on a local Ollama, and defaults to the llm section's
+// embeddingModel. Without this section, semantic queries are matched by
+// their keywords.
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// MinSimilarity is the cosine similarity below which jobs are left out
+	// of results. Defaults to 0.3; models differ in how similar unrelated
+	// texts come out, so it may need raising for some.
+	MinSimilarity float64 `json:"minSimilarity,omitempty"`
+	// BatchSize is the number of texts per embeddings request. Defaults to 32.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// maxEmbedDescription keeps texts within embedding models' context
+const maxEmbedDescription = 4000
+
+// Index holds the embeddings of the stored jobs
+type Index struct {
+	config Config
+	client ai.LLMClient
+	path   string
+	logger *logrus.Logger
+
+	mutex sync.Mutex
+	// vectors are unit-length embeddings by job ID
+	vectors map[string][]float32
+}
+
+// indexFile is the saved index. It is tied to the model that made the
+// vectors and starts over when the model changes.
+type indexFile struct {
+	Model   string            `json:"model"`
+	Vectors map[string]string `json:"vectors"`
+}
+
+// New validates config, filling in what it leaves out from defaults, and
+// loads the embeddings saved at indexPath; a nil or disabled config returns
+// a nil Index
+func New(config *Config, defaults *ai.Defaults, indexPath string, logger *logrus.Logger) (*Index, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, true)
+	client, err := ai.NewClient(c.Config, "semantic")
+	if err != nil {
+		return nil, err
+	}
+	if c.MinSimilarity == 0 {
+		c.MinSimilarity = 0.3
+	}
+	if c.MinSimilarity < -1 || c.MinSimilarity > 1 {
+		return nil, fmt.Errorf("semantic search minSimilarity must be between -1 and 1")
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 32
+	}
+
+	index := &Index{config: c, client: client, path: indexPath, logger: logger}
+	if index.vectors, err = loadVectors(indexPath, client.Model()); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Add embeds the jobs not in the index yet and saves it. Jobs embedded
+// before an error are kept.
+func (x *Index) Add(ctx context.Context, jobs []models.Job) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	return x.add(ctx, jobs)
+}
+
+func (x *Index) add(ctx context.Context, jobs []models.Job) error {
+	var missing []*models.Job
+	for i := range jobs {
+		if x.vectors[jobs[i].ID] == nil {
+			missing = append(missing, &jobs[i])
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var err error
+	for start := 0; start < len(missing); start += x.config.BatchSize {
+		batch := missing[start:min(start+x.config.BatchSize, len(missing))]
+		texts := make([]string, len(batch))
+		for i, job := range batch {
+			texts[i] = job.Title + "\n" + job.Company + "\n" + job.Location + "\n\n" + ai.Truncate(job.Description, maxEmbedDescription)
+		}
+		var vectors [][]float64
+		if vectors, err = x.client.Embed(ctx, texts); err != nil {
+			err = fmt.Errorf("failed to embed jobs: %w", err)
+			break
+		}
+		for i, job := range batch {
+			x.vectors[job.ID] = ai.Normalize(vectors[i])
+		}
+	}
+
+	if saveErr := x.save(); saveErr != nil {
+		x.logger.Errorf("Failed to save semantic search index: %v", saveErr)
+	}
+	return err
+}
+
+// Similarities embeds query and returns the similarity to it of each job at
+// or above MinSimilarity, by job ID. Jobs stored before semantic search was
+// set up are embedded first. Failures are logged, as storage falls back to
+// keyword ranking on them.
+func (x *Index) Similarities(ctx context.Context, query string, jobs []models.Job) (map[string]float64, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	if err := x.add(ctx, jobs); err != nil {
+		x.logger.Warnf("Ranking the search by keywords: %v", err)
+		return nil, err
+	}
+	vectors, err := x.client.Embed(ctx, []string{query})
+	if err != nil {
+		x.logger.Warnf("Ranking the search by keywords: failed to embed the query: %v", err)
+		return nil, err
+	}
+	target := ai.Normalize(vectors[0])
+
+	similarities := make(map[string]float64)
+	for i := range jobs {
+		if similarity := ai.Dot(target, x.vectors[jobs[i].ID]); similarity >= x.config.MinSimilarity {
+			similarities[jobs[i].ID] = similarity
+		}
+	}
+	return similarities, nil
+}
+
+func loadVectors(path, model string) (map[string][]float32, error) {
+	vectors := make(map[string][]float32)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return vectors, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read semantic search index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse semantic search index: %w", err)
+	}
+	if file.Model != model {
+		return vectors, nil
+	}
+	for id, value := range file.Vectors {
+		if vector, err := ai.DecodeVector(value); err == nil {
+			vectors[id] = vector
+		}
+	}
+	return vectors, nil
+}
+
+func (x *Index) save() error {
+	file := indexFile{Model: x.client.Model(), Vectors: make(map[string]string, len(x.vectors))}
+	for id, vector := range x.vectors {
+		file.Vectors[id] = ai.EncodeVector(vector)
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(x.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(x.path+".tmp", x.path)
+}
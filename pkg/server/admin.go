@@ -0,0 +1,247 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/scraper"
+)
+
+// redactedSecret replaces API keys in provider responses. Sending it back
+// unchanged keeps the stored key.
+const redactedSecret = "********"
+
+// handleBoard serves GET, PUT and DELETE /boards/{name}. PUT creates or
+// replaces the board; changes are written to the config file and used by
+// the next scrape.
+func (s *Server) handleBoard(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/boards/"), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		board, err := s.scraper.Board(name)
+		if err != nil {
+			writeConfigError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, board)
+
+	case http.MethodPut:
+		if !s.requireScope(w, r, auth.ScopeAdmin) {
+			return
+		}
+		var board scraper.JobBoard
+		if !decodeConfig(w, r, &board) {
+			return
+		}
+		if board.Name == "" {
+			board.Name = name
+		}
+		if board.Name != name {
+			writeError(w, http.StatusBadRequest, "board name %q does not match the URL", board.Name)
+			return
+		}
+		s.saveBoard(w, board)
+
+	case http.MethodDelete:
+		if !s.requireScope(w, r, auth.ScopeAdmin) {
+			return
+		}
+		if err := s.scraper.DeleteBoard(name); err != nil {
+			writeConfigError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// createBoard serves POST /boards, which fails if the name is taken
+func (s *Server) createBoard(w http.ResponseWriter, r *http.Request) {
+	if !s.requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	var board scraper.JobBoard
+	if !decodeConfig(w, r, &board) {
+		return
+	}
+	if _, err := s.scraper.Board(board.Name); err == nil {
+		writeError(w, http.StatusConflict, "board %s already exists", board.Name)
+		return
+	}
+	s.saveBoard(w, board)
+}
+
+func (s *Server) saveBoard(w http.ResponseWriter, board scraper.JobBoard) {
+	// RSS entries carry their own name, which tags the jobs they produce
+	if board.RSSConfig != nil && board.RSSConfig.Name == "" {
+		board.RSSConfig.Name = board.Name
+	}
+
+	created, err := s.scraper.SaveBoard(board)
+	if err != nil {
+		writeConfigError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		w.Header().Set("Location", "/boards/"+board.Name)
+	}
+	writeJSON(w, status, board)
+}
+
+// handleProviderConfigs lists API provider configs on GET and adds one on
+// POST. Provider configs are admin-only since they may hold credentials,
+// which are redacted in responses.
+func (s *Server) handleProviderConfigs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if !s.requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		configs, err := s.scraper.Providers()
+		if err != nil {
+			writeConfigError(w, err)
+			return
+		}
+		providers := make([]api.APIConfig, 0, len(configs))
+		for _, provider := range configs {
+			providers = append(providers, redactProvider(provider))
+		}
+		writeJSON(w, http.StatusOK, providers)
+		return
+	}
+
+	var provider api.APIConfig
+	if !decodeConfig(w, r, &provider) {
+		return
+	}
+	if _, err := s.scraper.Provider(provider.Name); err == nil {
+		writeError(w, http.StatusConflict, "provider %s already exists", provider.Name)
+		return
+	}
+	s.saveProvider(w, provider)
+}
+
+// handleProviderConfig serves GET, PUT and DELETE /providers/{name}. PUT
+// creates or replaces the provider and re-registers providers at once.
+func (s *Server) handleProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	if !s.requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/providers/"), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		provider, err := s.scraper.Provider(name)
+		if err != nil {
+			writeConfigError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, redactProvider(provider))
+
+	case http.MethodPut:
+		var provider api.APIConfig
+		if !decodeConfig(w, r, &provider) {
+			return
+		}
+		if provider.Name == "" {
+			provider.Name = name
+		}
+		if provider.Name != name {
+			writeError(w, http.StatusBadRequest, "provider name %q does not match the URL", provider.Name)
+			return
+		}
+		s.saveProvider(w, provider)
+
+	case http.MethodDelete:
+		if err := s.scraper.DeleteProvider(name); err != nil {
+			writeConfigError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) saveProvider(w http.ResponseWriter, provider api.APIConfig) {
+	// "status" would be shadowed by GET /providers/status
+	if provider.Name == "status" {
+		writeError(w, http.StatusBadRequest, "provider name %q is reserved", provider.Name)
+		return
+	}
+	if provider.APIKey == redactedSecret {
+		provider.APIKey = ""
+	}
+	if provider.SecretKey == redactedSecret {
+		provider.SecretKey = ""
+	}
+
+	created, err := s.scraper.SaveProvider(provider)
+	if err != nil {
+		writeConfigError(w, err)
+		return
+	}
+
+	saved, err := s.scraper.Provider(provider.Name)
+	if err != nil {
+		writeConfigError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		w.Header().Set("Location", "/providers/"+provider.Name)
+	}
+	writeJSON(w, status, redactProvider(saved))
+}
+
+func redactProvider(provider api.APIConfig) api.APIConfig {
+	if provider.APIKey != "" {
+		provider.APIKey = redactedSecret
+	}
+	if provider.SecretKey != "" {
+		provider.SecretKey = redactedSecret
+	}
+	return provider
+}
+
+// decodeConfig decodes a board or provider body, rejecting unknown fields so
+// a misspelt key fails loudly instead of being dropped from the config
+func decodeConfig(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+func writeConfigError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, scraper.ErrInvalidConfig):
+		writeError(w, http.StatusBadRequest, "%v", err)
+	case errors.Is(err, scraper.ErrBoardNotFound), errors.Is(err, scraper.ErrProviderNotFound):
+		writeError(w, http.StatusNotFound, "%v", err)
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to update config: %v", err)
+	}
+}
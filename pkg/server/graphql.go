@@ -0,0 +1,823 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/graphql"
+	"hire.ai/pkg/models"
+)
+
+// graphQLSchemaSDL documents the schema served at /graphql. GET /graphql
+// without a query returns it.
+const graphQLSchemaSDL = `type Query {
+  jobs(q: String, semantic: String, keywords: [String!], keywordQuery: String, location: String, near: String, radius: String, sources: [String!], minSalary: Int, maxSalary: Int,
+       salaryCurrency: String, since: String, until: String, postedSince: String, active: Boolean, sort: String, order: String,
+       categories: [String!], seniorities: [String!], industries: [String!], employmentTypes: [String!], remoteTypes: [String!],
+       limit: Int = 50, offset: Int = 0, page: Int): JobConnection!
+  job(id: ID!): Job
+  companies(search: String, limit: Int = 50, offset: Int = 0): [Company!]!
+  company(name: String!): Company
+  stats: Stats!
+  savedSearches: [SavedSearch!]!
+  savedSearch(id: ID!): SavedSearch
+  me: User!
+  applications(status: String): [Application!]!
+}
+
+type Mutation {
+  createSavedSearch(name: String!, q: String, semantic: String, keywords: [String!], keywordQuery: String, location: String, near: String, radius: String, sources: [String!],
+                    minSalary: Int, maxSalary: Int, salaryCurrency: String, active: Boolean, sort: String, order: String,
+                    categories: [String!], seniorities: [String!], industries: [String!],
+                    employmentTypes: [String!], remoteTypes: [String!]): SavedSearch!
+  deleteSavedSearch(id: ID!): Boolean!
+  setApplicationStatus(jobId: ID!, status: String!, notes: String): Application!
+  deleteApplication(jobId: ID!): Boolean!
+}
+
+type JobConnection {
+  jobs: [Job!]!
+  total: Int!
+  page: Int!
+  perPage: Int!
+  totalPages: Int!
+}
+
+type Job {
+  id: ID!
+  title: String!
+  company: String!
+  location: String
+  salary: String
+  description: String
+  link: String!
+  source: String!
+  keywords: [String!]
+  scrapedAt: String!
+  updatedAt: String!
+  postedAt: String
+  postedZone: String
+  employmentType: String
+  remoteType: String
+  isActive: Boolean!
+  checkedAt: String
+  expiredAt: String
+  relevance: Float!
+  category: String
+  seniority: String
+  industry: String
+  application: Application
+}
+
+type Company {
+  name: String!
+  aliases: [String!]!
+  website: String
+  logo: String
+  jobCount: Int!
+  activeJobs: Int!
+  sources: [String!]!
+  locations: [String!]!
+  latestJobAt: String!
+  jobs(limit: Int = 10): [Job!]!
+}
+
+type Stats {
+  totalJobs: Int!
+  recentJobs: Int!
+  lastScraped: String!
+  jobsBySource: [Count!]!
+  jobsByLocation(limit: Int): [Count!]!
+  jobsByCompany(limit: Int): [Count!]!
+  keywords(limit: Int): [Count!]!
+  jobsByCategory: [Count!]!
+  jobsBySeniority: [Count!]!
+  jobsByIndustry: [Count!]!
+}
+
+type Count {
+  name: String!
+  count: Int!
+}
+
+type SavedSearch {
+  id: ID!
+  name: String!
+  query: String
+  semantic: String
+  keywords: [String!]
+  keywordQuery: String
+  location: String
+  near: String
+  radiusKm: Float
+  sources: [String!]
+  categories: [String!]
+  seniorities: [String!]
+  industries: [String!]
+  employmentTypes: [String!]
+  remoteTypes: [String!]
+  minSalary: Int
+  maxSalary: Int
+  salaryCurrency: String
+  active: Boolean
+  createdAt: String!
+  updatedAt: String!
+  jobs(limit: Int = 50, offset: Int = 0): JobConnection!
+}
+
+type User {
+  id: ID!
+  name: String!
+  email: String
+  notifications: NotificationSettings!
+  createdAt: String!
+  updatedAt: String!
+}
+
+type NotificationSettings {
+  enabled: Boolean!
+  email: String
+  minRelevance: Float
+  savedSearches: [String!]
+}
+
+type Application {
+  jobId: ID!
+  status: String!
+  notes: String
+  createdAt: String!
+  updatedAt: String!
+  job: Job
+}
+`
+
+var jobFilterArgs = []string{"q", "semantic", "keywords", "keywordQuery", "location", "near", "radius", "sources", "minSalary", "maxSalary",
+	"salaryCurrency", "since", "until", "postedSince", "active", "sort", "order", "categories", "seniorities", "industries",
+	"employmentTypes", "remoteTypes"}
+
+// countEntry is one row of a stats breakdown
+type countEntry struct {
+	Name  string
+	Count int
+}
+
+func (s *Server) buildGraphQLSchema() *graphql.Schema {
+	application := &graphql.Object{Name: "Application", Fields: map[string]*graphql.Field{
+		"jobId": {}, "status": {}, "notes": {}, "createdAt": {}, "updatedAt": {},
+	}}
+
+	job := &graphql.Object{Name: "Job", Fields: map[string]*graphql.Field{
+		"id": {}, "title": {}, "company": {}, "location": {}, "salary": {}, "description": {},
+		"link": {}, "source": {}, "keywords": {}, "scrapedAt": {}, "updatedAt": {},
+		"postedAt": {}, "postedZone": {}, "employmentType": {}, "remoteType": {},
+		"isActive": {}, "checkedAt": {}, "expiredAt": {}, "relevance": {},
+		"category":  classificationField(func(c *models.JobClassification) string { return c.Category }),
+		"seniority": classificationField(func(c *models.JobClassification) string { return c.Seniority }),
+		"industry":  classificationField(func(c *models.JobClassification) string { return c.Industry }),
+		"application": {Type: application, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if s.apps == nil {
+				return nil, nil
+			}
+			var id string
+			switch source := p.Source.(type) {
+			case *models.Job:
+				id = source.ID
+			case models.Job:
+				id = source.ID
+			}
+			if application, err := s.apps.Get(requestUser(p.Context), id); err == nil {
+				return application, nil
+			}
+			return nil, nil
+		}},
+	}}
+
+	application.Fields["job"] = &graphql.Field{Type: job, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		if job, err := s.storage.GetByID(p.Context, p.Source.(*models.Application).JobID); err == nil {
+			return job, nil
+		}
+		return nil, nil
+	}}
+
+	notificationSettings := &graphql.Object{Name: "NotificationSettings", Fields: map[string]*graphql.Field{
+		"enabled": {}, "email": {}, "minRelevance": {}, "savedSearches": {},
+	}}
+
+	user := &graphql.Object{Name: "User", Fields: map[string]*graphql.Field{
+		"id": {}, "name": {}, "email": {}, "createdAt": {}, "updatedAt": {},
+		"notifications": {Type: notificationSettings},
+	}}
+
+	jobConnection := &graphql.Object{Name: "JobConnection", Fields: map[string]*graphql.Field{
+		"jobs": {Type: job}, "total": {}, "page": {}, "perPage": {}, "totalPages": {},
+	}}
+
+	count := &graphql.Object{Name: "Count", Fields: map[string]*graphql.Field{
+		"name": {}, "count": {},
+	}}
+
+	company := &graphql.Object{Name: "Company", Fields: map[string]*graphql.Field{
+		"name": {}, "aliases": {}, "website": {}, "logo": {}, "jobCount": {}, "activeJobs": {},
+		"sources": {}, "locations": {}, "latestJobAt": {},
+		"jobs": {
+			Type: job,
+			Args: []string{"limit"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				limit, err := p.Int("limit", 10)
+				if err != nil {
+					return nil, err
+				}
+				jobs, err := s.companyJobs(p.Context, p.Source.(models.Company).Key)
+				if err != nil {
+					return nil, err
+				}
+				if limit >= 0 && limit < len(jobs) {
+					jobs = jobs[:limit]
+				}
+				return jobs, nil
+			},
+		},
+	}}
+
+	stats := &graphql.Object{Name: "Stats", Fields: map[string]*graphql.Field{
+		"totalJobs": {}, "recentJobs": {}, "lastScraped": {},
+		"jobsBySource": {Type: count, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return sortedCounts(p.Source.(*models.JobStats).JobsBySource, 0), nil
+		}},
+		"jobsByLocation": {Type: count, Args: []string{"limit"}, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			limit, err := p.Int("limit", 0)
+			if err != nil {
+				return nil, err
+			}
+			return sortedCounts(p.Source.(*models.JobStats).JobsByLocation, limit), nil
+		}},
+		"jobsByCompany": {Type: count, Args: []string{"limit"}, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			limit, err := p.Int("limit", 0)
+			if err != nil {
+				return nil, err
+			}
+			return sortedCounts(p.Source.(*models.JobStats).JobsByCompany, limit), nil
+		}},
+		"keywords": {Type: count, Args: []string{"limit"}, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			limit, err := p.Int("limit", 0)
+			if err != nil {
+				return nil, err
+			}
+			return sortedCounts(p.Source.(*models.JobStats).Keywords, limit), nil
+		}},
+		"jobsByCategory": {Type: count, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return sortedCounts(p.Source.(*models.JobStats).JobsByCategory, 0), nil
+		}},
+		"jobsBySeniority": {Type: count, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return sortedCounts(p.Source.(*models.JobStats).JobsBySeniority, 0), nil
+		}},
+		"jobsByIndustry": {Type: count, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return sortedCounts(p.Source.(*models.JobStats).JobsByIndustry, 0), nil
+		}},
+	}}
+
+	filterField := func(get func(models.JobFilter) interface{}) *graphql.Field {
+		return &graphql.Field{Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(models.SavedSearch).Filter), nil
+		}}
+	}
+	savedSearch := &graphql.Object{Name: "SavedSearch", Fields: map[string]*graphql.Field{
+		"id": {}, "name": {}, "createdAt": {}, "updatedAt": {},
+		"query":           filterField(func(f models.JobFilter) interface{} { return f.Query }),
+		"semantic":        filterField(func(f models.JobFilter) interface{} { return f.Semantic }),
+		"keywords":        filterField(func(f models.JobFilter) interface{} { return f.Keywords }),
+		"keywordQuery":    filterField(func(f models.JobFilter) interface{} { return f.KeywordQuery }),
+		"location":        filterField(func(f models.JobFilter) interface{} { return f.Location }),
+		"near":            filterField(func(f models.JobFilter) interface{} { return f.Near }),
+		"radiusKm":        filterField(func(f models.JobFilter) interface{} { return f.RadiusKm }),
+		"sources":         filterField(func(f models.JobFilter) interface{} { return f.Sources }),
+		"categories":      filterField(func(f models.JobFilter) interface{} { return f.Categories }),
+		"seniorities":     filterField(func(f models.JobFilter) interface{} { return f.Seniorities }),
+		"industries":      filterField(func(f models.JobFilter) interface{} { return f.Industries }),
+		"employmentTypes": filterField(func(f models.JobFilter) interface{} { return f.EmploymentTypes }),
+		"remoteTypes":     filterField(func(f models.JobFilter) interface{} { return f.RemoteTypes }),
+		"minSalary":       filterField(func(f models.JobFilter) interface{} { return f.MinSalary }),
+		"maxSalary":       filterField(func(f models.JobFilter) interface{} { return f.MaxSalary }),
+		"salaryCurrency": filterField(func(f models.JobFilter) interface{} {
+			if f.SalaryCurrency == "" {
+				return nil
+			}
+			return f.SalaryCurrency
+		}),
+		"active": filterField(func(f models.JobFilter) interface{} {
+			if f.IsActive == nil {
+				return nil
+			}
+			return *f.IsActive
+		}),
+		"jobs": {
+			Type: jobConnection,
+			Args: []string{"limit", "offset"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				filter := p.Source.(models.SavedSearch).Filter
+				if err := applyPaging(p, &filter); err != nil {
+					return nil, err
+				}
+				return s.storage.Search(p.Context, filter)
+			},
+		},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"jobs": {
+			Type: jobConnection,
+			Args: append([]string{"limit", "offset", "page"}, jobFilterArgs...),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				filter, err := filterFromArgs(p)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.locate(p.Context, &filter); err != nil {
+					return nil, err
+				}
+				if err := applyPaging(p, &filter); err != nil {
+					return nil, err
+				}
+				return s.storage.Search(p.Context, filter)
+			},
+		},
+		"job": {
+			Type: job,
+			Args: []string{"id"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := p.String("id")
+				if err != nil {
+					return nil, err
+				}
+				if job, err := s.storage.GetByID(p.Context, id); err == nil {
+					return job, nil
+				}
+				return nil, nil
+			},
+		},
+		"companies": {
+			Type: company,
+			Args: []string{"search", "limit", "offset"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				search, err := p.String("search")
+				if err != nil {
+					return nil, err
+				}
+				limit, err := p.Int("limit", defaultPageSize)
+				if err != nil {
+					return nil, err
+				}
+				offset, err := p.Int("offset", 0)
+				if err != nil {
+					return nil, err
+				}
+
+				companies, err := s.storage.GetCompanies(p.Context)
+				if err != nil {
+					return nil, err
+				}
+
+				var matched []models.Company
+				for _, c := range companies {
+					// Searching by an alias finds the company it names
+					if search == "" || strings.Contains(strings.ToLower(c.Name), strings.ToLower(search)) || models.CompanyKey(c.Name) == models.CompanyKey(search) {
+						matched = append(matched, c)
+					}
+				}
+				if offset >= len(matched) {
+					return []models.Company{}, nil
+				}
+				matched = matched[offset:]
+				if limit > 0 && limit < len(matched) {
+					matched = matched[:limit]
+				}
+				return matched, nil
+			},
+		},
+		"company": {
+			Type: company,
+			Args: []string{"name"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				name, err := p.String("name")
+				if err != nil {
+					return nil, err
+				}
+				companies, err := s.storage.GetCompanies(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				key := models.CompanyKey(name)
+				for _, c := range companies {
+					if c.Key == key {
+						return c, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		"stats": {
+			Type: stats,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return s.storage.GetStats(p.Context)
+			},
+		},
+		"savedSearches": {
+			Type: savedSearch,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if s.searches == nil {
+					return []models.SavedSearch{}, nil
+				}
+				return s.searches.List(requestUser(p.Context)), nil
+			},
+		},
+		"savedSearch": {
+			Type: savedSearch,
+			Args: []string{"id"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := p.String("id")
+				if err != nil || s.searches == nil {
+					return nil, err
+				}
+				search, err := s.searches.Get(requestUser(p.Context), id)
+				if err != nil {
+					return nil, nil
+				}
+				return *search, nil
+			},
+		},
+		"me": {
+			Type: user,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id := requestUser(p.Context)
+				if id == "" {
+					profile := defaultProfile
+					return &profile, nil
+				}
+				if s.users == nil {
+					return nil, fmt.Errorf("user %s not found", id)
+				}
+				return s.users.Get(id)
+			},
+		},
+		"applications": {
+			Type: application,
+			Args: []string{"status"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				status, err := p.String("status")
+				if err != nil {
+					return nil, err
+				}
+				if status != "" {
+					if err := models.ValidateApplicationStatus(status); err != nil {
+						return nil, err
+					}
+				}
+				applications := []*models.Application{}
+				if s.apps == nil {
+					return applications, nil
+				}
+				for _, a := range s.apps.List(requestUser(p.Context), status) {
+					a := a
+					applications = append(applications, &a)
+				}
+				return applications, nil
+			},
+		},
+	}}
+
+	mutation := &graphql.Object{Name: "Mutation", Fields: map[string]*graphql.Field{
+		"createSavedSearch": {
+			Type: savedSearch,
+			Args: append([]string{"name"}, jobFilterArgs...),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if s.searches == nil {
+					return nil, fmt.Errorf("saved searches are not available")
+				}
+				name, err := p.String("name")
+				if err != nil {
+					return nil, err
+				}
+				filter, err := filterFromArgs(p)
+				if err != nil {
+					return nil, err
+				}
+				// The place is located once, so matching the saved search
+				// needs no geocoding
+				if err := s.locate(p.Context, &filter); err != nil {
+					return nil, err
+				}
+
+				search := models.NewSavedSearch(name, filter)
+				search.UserID = requestUser(p.Context)
+				if err := s.searches.Create(search); err != nil {
+					return nil, err
+				}
+				return *search, nil
+			},
+		},
+		"deleteSavedSearch": {
+			Args: []string{"id"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if s.searches == nil {
+					return false, nil
+				}
+				id, err := p.String("id")
+				if err != nil {
+					return nil, err
+				}
+				return s.searches.Delete(requestUser(p.Context), id)
+			},
+		},
+		"setApplicationStatus": {
+			Type: application,
+			Args: []string{"jobId", "status", "notes"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if s.apps == nil {
+					return nil, fmt.Errorf("application tracking is not available")
+				}
+				jobID, err := p.String("jobId")
+				if err != nil {
+					return nil, err
+				}
+				status, err := p.String("status")
+				if err != nil {
+					return nil, err
+				}
+				notes, err := p.String("notes")
+				if err != nil {
+					return nil, err
+				}
+				if _, err := s.storage.GetByID(p.Context, jobID); err != nil {
+					return nil, fmt.Errorf("job %s not found", jobID)
+				}
+
+				application := &models.Application{
+					JobID:  jobID,
+					UserID: requestUser(p.Context),
+					Status: strings.ToLower(strings.TrimSpace(status)),
+					Notes:  strings.TrimSpace(notes),
+				}
+				if err := s.apps.Set(application); err != nil {
+					return nil, err
+				}
+				return application, nil
+			},
+		},
+		"deleteApplication": {
+			Args: []string{"jobId"},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if s.apps == nil {
+					return false, nil
+				}
+				jobID, err := p.String("jobId")
+				if err != nil {
+					return nil, err
+				}
+				return s.apps.Delete(requestUser(p.Context), jobID)
+			},
+		},
+	}}
+
+	return &graphql.Schema{Query: query, Mutation: mutation}
+}
+
+// classificationField resolves one label of a job's classification, or null
+// for an unclassified job
+func classificationField(get func(*models.JobClassification) string) *graphql.Field {
+	return &graphql.Field{Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		var classification *models.JobClassification
+		switch source := p.Source.(type) {
+		case *models.Job:
+			classification = source.Classification
+		case models.Job:
+			classification = source.Classification
+		}
+		if classification == nil {
+			return nil, nil
+		}
+		return get(classification), nil
+	}}
+}
+
+// filterFromArgs builds a job filter from the arguments shared by jobs and
+// createSavedSearch
+func filterFromArgs(p graphql.ResolveParams) (models.JobFilter, error) {
+	var filter models.JobFilter
+	var err error
+
+	if filter.Keywords, err = p.Strings("keywords"); err != nil {
+		return filter, err
+	}
+	if filter.KeywordQuery, err = p.String("keywordQuery"); err != nil {
+		return filter, err
+	}
+	if err = filter.Compile(); err != nil {
+		return filter, fmt.Errorf("invalid keywordQuery: %w", err)
+	}
+	if filter.Query, err = p.String("q"); err != nil {
+		return filter, err
+	}
+	if filter.Semantic, err = p.String("semantic"); err != nil {
+		return filter, err
+	}
+
+	if filter.Location, err = p.String("location"); err != nil {
+		return filter, err
+	}
+	if filter.Near, err = p.String("near"); err != nil {
+		return filter, err
+	}
+	radius, err := p.String("radius")
+	if err != nil {
+		return filter, err
+	}
+	if radius != "" {
+		if filter.RadiusKm, err = geo.ParseRadius(radius); err != nil {
+			return filter, err
+		}
+	}
+	if filter.Sources, err = p.Strings("sources"); err != nil {
+		return filter, err
+	}
+	if filter.Categories, err = p.Strings("categories"); err != nil {
+		return filter, err
+	}
+	if filter.Seniorities, err = p.Strings("seniorities"); err != nil {
+		return filter, err
+	}
+	if filter.Industries, err = p.Strings("industries"); err != nil {
+		return filter, err
+	}
+	if filter.EmploymentTypes, err = p.Strings("employmentTypes"); err != nil {
+		return filter, err
+	}
+	if filter.RemoteTypes, err = p.Strings("remoteTypes"); err != nil {
+		return filter, err
+	}
+	if filter.MinSalary, err = p.Int("minSalary", 0); err != nil {
+		return filter, err
+	}
+	if filter.MaxSalary, err = p.Int("maxSalary", 0); err != nil {
+		return filter, err
+	}
+	currency, err := p.String("salaryCurrency")
+	if err != nil {
+		return filter, err
+	}
+	if filter.SalaryCurrency, err = salaryCurrency(currency); err != nil {
+		return filter, err
+	}
+	if filter.SortBy, err = p.String("sort"); err != nil {
+		return filter, err
+	}
+	if filter.SortOrder, err = p.String("order"); err != nil {
+		return filter, err
+	}
+	if err := validateSort(filter); err != nil {
+		return filter, err
+	}
+
+	for name, target := range map[string]*time.Time{"since": &filter.DateFrom, "until": &filter.DateTo, "postedSince": &filter.PostedSince} {
+		value, err := p.String(name)
+		if err != nil {
+			return filter, err
+		}
+		if *target, err = parseTime(name, value); err != nil {
+			return filter, err
+		}
+	}
+
+	active, set, err := p.Bool("active")
+	if err != nil {
+		return filter, err
+	}
+	if set {
+		filter.IsActive = &active
+	}
+
+	return filter, nil
+}
+
+func applyPaging(p graphql.ResolveParams, filter *models.JobFilter) error {
+	var err error
+	if filter.Limit, err = p.Int("limit", defaultPageSize); err != nil {
+		return err
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	if filter.Offset, err = p.Int("offset", 0); err != nil {
+		return err
+	}
+	page, err := p.Int("page", 0)
+	if err != nil {
+		return err
+	}
+	if page > 0 {
+		filter.Offset = (page - 1) * filter.Limit
+	}
+	return nil
+}
+
+// companyJobs returns the stored jobs of the company with the given key,
+// newest first
+func (s *Server) companyJobs(ctx context.Context, key string) ([]models.Job, error) {
+	jobs, err := s.storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	var matched []models.Job
+	for _, job := range jobs {
+		if models.CompanyKey(job.Company) == key {
+			matched = append(matched, job)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].ScrapedAt.After(matched[j].ScrapedAt) })
+	return matched, nil
+}
+
+func sortedCounts(counts map[string]int, limit int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for name, n := range counts {
+		entries = append(entries, countEntry{Name: name, Count: n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// handleGraphQL executes GraphQL requests. POST accepts a JSON body or an
+// application/graphql query; GET accepts query, operationName and variables
+// parameters for read-only queries, or returns the schema when no query is
+// given.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	var req graphql.Request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if query.Get("query") == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			io.WriteString(w, graphQLSchemaSDL)
+			return
+		}
+
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid variables: %v", err)
+				return
+			}
+		}
+		if graphql.IsMutation(req) {
+			writeError(w, http.StatusMethodNotAllowed, "mutations must use POST")
+			return
+		}
+	case http.MethodPost:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read request body: %v", err)
+			return
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql") {
+			req.Query = string(body)
+		} else if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+			return
+		}
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	if graphql.IsMutation(req) && !s.requireProfileWrite(w, r) {
+		return
+	}
+
+	response := s.graphql.Execute(r.Context(), req)
+	status := http.StatusOK
+	if response.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, response)
+}
@@ -0,0 +1,466 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/scraper"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// handleJobs serves GET /jobs with filtering, sorting and pagination
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	filter, err := parseJobFilter(r.URL.Query())
+	if err == nil {
+		err = s.locate(r.Context(), &filter)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	result, err := s.storage.Search(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to search jobs: %v", err)
+		return
+	}
+	if result.Jobs == nil {
+		result.Jobs = []models.Job{}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleJob serves GET /jobs/{id}
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+	if id == "" {
+		writeError(w, http.StatusNotFound, "job id is required")
+		return
+	}
+
+	job, err := s.storage.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "job %s not found", id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	stats, err := s.storage.GetStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get stats: %v", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleScrape lists runs on GET and queues a new run on POST. Keywords and
+// location may come from a JSON body or query parameters.
+func (s *Server) handleScrape(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.queue.list())
+		return
+	}
+
+	if !s.requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	var req models.ScrapeRequest
+	if r.ContentLength != 0 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+			return
+		}
+	}
+	if len(req.Keywords) == 0 {
+		req.Keywords = splitList(r.URL.Query().Get("keywords"))
+	}
+	if req.Location == "" {
+		req.Location = r.URL.Query().Get("location")
+	}
+	if len(req.Keywords) == 0 {
+		writeError(w, http.StatusBadRequest, "keywords are required")
+		return
+	}
+	if req.Location == "" {
+		req.Location = "Remote"
+	}
+
+	run, err := s.queue.enqueue(req.Keywords, req.Location)
+	if err != nil {
+		w.Header().Set("Retry-After", "60")
+		writeError(w, http.StatusTooManyRequests, "%v; retry once queued runs finish", err)
+		return
+	}
+
+	w.Header().Set("Location", "/scrape/"+run.ID)
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (s *Server) executeRun(run models.ScrapeRun) {
+	log := s.logger.WithField("scrape_id", run.ID)
+	log.Infof("Starting API-triggered scrape for %v in %s (queued %v)", run.Keywords, run.Location,
+		run.StartedAt.Sub(run.QueuedAt).Round(time.Second))
+
+	start := time.Now()
+	progress := func(update models.SourceProgress) {
+		s.metrics.observeSource(update)
+		s.queue.progress(run.ID, update)
+	}
+	// The run keeps its queue ID in the logs and the run history
+	ctx := scraper.WithRunID(s.ctx, run.ID)
+	jobsFound, err := s.runScrape(ctx, run.Keywords, run.Location, progress)
+	status := models.RunStatusCompleted
+	if err != nil {
+		status = models.RunStatusFailed
+		log.Errorf("Scrape failed: %v", err)
+	} else {
+		log.Infof("Scrape completed with %d jobs", jobsFound)
+	}
+
+	s.metrics.scrapeDuration.Observe(time.Since(start).Seconds(), status)
+	s.metrics.scrapeRuns.Inc(status)
+	s.metrics.jobsScraped.Add(float64(jobsFound))
+
+	s.queue.finish(run.ID, jobsFound, err)
+}
+
+// runScrape calls the scrape function, converting a panic into an error so
+// one bad run cannot take the server down
+func (s *Server) runScrape(ctx context.Context, keywords []string, location string, progress func(models.SourceProgress)) (jobsFound int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scrape panicked: %v", r)
+		}
+	}()
+	return s.scrape(ctx, keywords, location, progress)
+}
+
+// handleScrapeRun serves GET /scrape/{id} and cancels a queued run on DELETE
+func (s *Server) handleScrapeRun(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodDelete) {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/scrape/"), "/")
+
+	if r.Method == http.MethodDelete {
+		if !s.requireScope(w, r, auth.ScopeAdmin) {
+			return
+		}
+		run, err := s.queue.cancel(id)
+		switch {
+		case errors.Is(err, errRunNotFound):
+			writeError(w, http.StatusNotFound, "scrape run %s not found", id)
+		case errors.Is(err, errRunStarted):
+			writeError(w, http.StatusConflict, "scrape run %s is %s and can no longer be cancelled", id, run.Status)
+		default:
+			writeJSON(w, http.StatusOK, run)
+		}
+		return
+	}
+
+	run, ok := s.queue.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "scrape run %s not found", id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// handleProviders reports configured API providers with their usage stats.
+// Passing validate=true also checks credentials, which calls each provider.
+func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	stats := s.scraper.GetAPIStats()
+
+	var validation map[string]api.ProviderValidation
+	if validate, _ := strconv.ParseBool(r.URL.Query().Get("validate")); validate {
+		if !s.requireScope(w, r, auth.ScopeAdmin) {
+			return
+		}
+		validation = make(map[string]api.ProviderValidation)
+		for _, result := range s.scraper.ValidateAPICredentials(r.Context()) {
+			validation[result.Provider] = result
+		}
+	}
+
+	config := s.scraper.GetConfig()
+	providers := make([]models.ProviderStatus, 0, len(config.APIProviders))
+	for _, provider := range config.APIProviders {
+		status := models.ProviderStatus{
+			Name:     provider.Name,
+			Provider: provider.Provider,
+			Enabled:  provider.Enabled,
+		}
+		if providerStats, ok := stats[provider.Provider]; ok {
+			status.Stats = &models.ProviderStats{
+				TotalRequests:   providerStats.TotalRequests,
+				SuccessRequests: providerStats.SuccessRequests,
+				FailedRequests:  providerStats.FailedRequests,
+				TotalJobs:       providerStats.TotalJobs,
+				AverageLatency:  providerStats.AverageLatency,
+				LastUsed:        providerStats.LastUsed,
+
+				CircuitState:        providerStats.CircuitState,
+				ConsecutiveFailures: providerStats.ConsecutiveFailures,
+				CircuitOpenUntil:    providerStats.CircuitOpenUntil,
+			}
+		}
+
+		if validation != nil {
+			if result, checked := validation[provider.Provider]; checked {
+				valid := result.Status == api.ValidationValid
+				status.Valid = &valid
+				status.ValidationStatus = string(result.Status)
+				if result.Err != nil {
+					status.Validation = result.Err.Error()
+				}
+			}
+		}
+
+		providers = append(providers, status)
+	}
+
+	writeJSON(w, http.StatusOK, providers)
+}
+
+// handleBoards summarises the configured boards on GET and adds a board on
+// POST
+func (s *Server) handleBoards(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodPost {
+		s.createBoard(w, r)
+		return
+	}
+
+	config := s.scraper.GetConfig()
+	boards := make([]models.BoardSummary, 0, len(config.JobBoards))
+	for _, board := range config.JobBoards {
+		method := board.ScrapingMethod
+		if method == "" {
+			method = "scraping"
+		}
+
+		boards = append(boards, models.BoardSummary{
+			Name:       board.Name,
+			Enabled:    board.Enabled,
+			Method:     method,
+			BaseURL:    board.BaseURL,
+			SearchPath: board.SearchPath,
+			RateLimit:  board.RateLimit,
+			MaxResults: board.MaxResults,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, boards)
+}
+
+// parseJobFilter builds a storage filter from /jobs query parameters:
+// q, semantic, keywords or keyword_query, location, near, radius, source, category, seniority,
+// industry, employment_type, remote_type, min_salary, max_salary, salary_currency, since, until, posted_since, active,
+// sort, order, limit, offset and page
+func parseJobFilter(query url.Values) (models.JobFilter, error) {
+	filter := models.JobFilter{
+		Keywords:        splitList(query.Get("keywords")),
+		KeywordQuery:    query.Get("keyword_query"),
+		Query:           query.Get("q"),
+		Semantic:        query.Get("semantic"),
+		Location:        query.Get("location"),
+		Near:            query.Get("near"),
+		Sources:         splitList(query.Get("source")),
+		Categories:      splitList(query.Get("category")),
+		Seniorities:     splitList(query.Get("seniority")),
+		Industries:      splitList(query.Get("industry")),
+		EmploymentTypes: splitList(query.Get("employment_type")),
+		RemoteTypes:     splitList(query.Get("remote_type")),
+		SortBy:          query.Get("sort"),
+		SortOrder:       query.Get("order"),
+		Limit:           defaultPageSize,
+	}
+
+	if err := validateSort(filter); err != nil {
+		return filter, err
+	}
+	if err := filter.Compile(); err != nil {
+		return filter, fmt.Errorf("invalid keyword_query: %w", err)
+	}
+
+	var err error
+	if radius := query.Get("radius"); radius != "" {
+		if filter.RadiusKm, err = geo.ParseRadius(radius); err != nil {
+			return filter, err
+		}
+	}
+	if filter.MinSalary, err = intParam(query, "min_salary", 0); err != nil {
+		return filter, err
+	}
+	if filter.MaxSalary, err = intParam(query, "max_salary", 0); err != nil {
+		return filter, err
+	}
+	if filter.SalaryCurrency, err = salaryCurrency(query.Get("salary_currency")); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = intParam(query, "limit", defaultPageSize); err != nil {
+		return filter, err
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		return filter, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	if filter.Offset, err = intParam(query, "offset", 0); err != nil {
+		return filter, err
+	}
+	page, err := intParam(query, "page", 0)
+	if err != nil {
+		return filter, err
+	}
+	if page > 0 {
+		filter.Offset = (page - 1) * filter.Limit
+	}
+
+	if filter.DateFrom, err = timeParam(query, "since"); err != nil {
+		return filter, err
+	}
+	if filter.DateTo, err = timeParam(query, "until"); err != nil {
+		return filter, err
+	}
+	if filter.PostedSince, err = timeParam(query, "posted_since"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid active %q", value)
+		}
+		filter.IsActive = &active
+	}
+
+	return filter, nil
+}
+
+// locate places a filter's near on the map
+func (s *Server) locate(ctx context.Context, filter *models.JobFilter) error {
+	if filter.Near == "" && filter.RadiusKm == 0 {
+		return nil
+	}
+	if s.geocoder == nil {
+		return fmt.Errorf("searching near a place is not available")
+	}
+	return s.geocoder.ResolveFilter(ctx, filter)
+}
+
+func validateSort(filter models.JobFilter) error {
+	switch strings.ToLower(filter.SortBy) {
+	case "", "relevance", "match", "date", "posted", "title", "company":
+	default:
+		return fmt.Errorf("invalid sort %q (use relevance, match, date, posted, title or company)", filter.SortBy)
+	}
+	switch strings.ToLower(filter.SortOrder) {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("invalid order %q (use asc or desc)", filter.SortOrder)
+	}
+	return nil
+}
+
+func intParam(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+// salaryCurrency upper-cases the currency salary bounds are given in,
+// rejecting ones with no exchange rate
+func salaryCurrency(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if currency != "" && !models.KnownCurrency(currency) {
+		return "", fmt.Errorf("unknown salary currency %q (known: %s)", value, strings.Join(models.Currencies(), ", "))
+	}
+	return currency, nil
+}
+
+// timeParam reads a time query parameter; see parseTime for accepted forms
+func timeParam(query url.Values, name string) (time.Time, error) {
+	return parseTime(name, query.Get(name))
+}
+
+// parseTime accepts RFC 3339 timestamps, plain dates, which start at
+// midnight in the display timezone, or an age such as 24h meaning that long
+// ago. An empty value yields the zero time.
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, models.DisplayLocation()); err == nil {
+		return t, nil
+	}
+	if age, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-age), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid %s %q (use RFC 3339, YYYY-MM-DD or a duration like 24h)", name, value)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReadinessCheck reports whether a dependency is ready to serve traffic
+type ReadinessCheck func(ctx context.Context) error
+
+// healthResponse is the body of /healthz and /readyz
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// pinger is implemented by storage backends with a cheap reachability check
+type pinger interface {
+	Ping() error
+}
+
+// readiness holds the named checks behind /readyz
+type readiness struct {
+	mutex    sync.RWMutex
+	checks   map[string]ReadinessCheck
+	draining bool
+}
+
+// AddReadinessCheck registers a check that must pass for /readyz to report
+// ready, e.g. a scheduler confirming it is running
+func (s *Server) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.readiness.mutex.Lock()
+	defer s.readiness.mutex.Unlock()
+	s.readiness.checks[name] = check
+}
+
+func (s *Server) registerDefaultChecks() {
+	s.AddReadinessCheck("storage", func(ctx context.Context) error {
+		if store, ok := s.storage.(pinger); ok {
+			return store.Ping()
+		}
+		_, err := s.storage.GetStats(ctx)
+		return err
+	})
+
+	s.AddReadinessCheck("config", func(ctx context.Context) error {
+		config := s.scraper.GetConfig()
+		for _, board := range config.JobBoards {
+			if board.Enabled {
+				return nil
+			}
+		}
+		for _, provider := range config.APIProviders {
+			if provider.Enabled {
+				return nil
+			}
+		}
+		return fmt.Errorf("no enabled job boards or API providers")
+	})
+}
+
+// handleHealthz is the liveness probe: the process is up and serving HTTP
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// handleReadyz runs every readiness check and reports 503 if any fails or
+// the server is shutting down
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.readiness.mutex.RLock()
+	checks := make(map[string]ReadinessCheck, len(s.readiness.checks))
+	for name, check := range s.readiness.checks {
+		checks[name] = check
+	}
+	draining := s.readiness.draining
+	s.readiness.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	response := healthResponse{Status: "ok", Checks: make(map[string]string, len(checks))}
+	status := http.StatusOK
+
+	if draining {
+		response.Checks["server"] = "shutting down"
+		status = http.StatusServiceUnavailable
+	}
+
+	for name, check := range checks {
+		if err := check(ctx); err != nil {
+			response.Checks[name] = err.Error()
+			status = http.StatusServiceUnavailable
+		} else {
+			response.Checks[name] = "ok"
+		}
+	}
+
+	if status != http.StatusOK {
+		response.Status = "unavailable"
+	}
+	writeJSON(w, status, response)
+}
+
+func (s *Server) setDraining() {
+	s.readiness.mutex.Lock()
+	s.readiness.draining = true
+	s.readiness.mutex.Unlock()
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/relevance"
+	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/transport"
+)
+
+const (
+	// ingestSource tags postings pushed through POST /ingest
+	ingestSource = "extension"
+	// maxIngestBody leaves room for the full HTML of a posting page
+	maxIngestBody = 10 << 20
+	// ingestFetchTimeout bounds fetching a posting when only its URL is sent
+	ingestFetchTimeout = 20 * time.Second
+)
+
+// handleIngest serves POST /ingest, which stores a single posting pushed by
+// the browser extension. Postings already in the store are returned as
+// duplicates with 200 instead of being stored again.
+func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireScope(w, r, auth.ScopeIngest) {
+		return
+	}
+
+	var req models.IngestRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	link, err := normalizeLink(req.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+
+	// A link already in the store needs no parsing or fetching
+	if existing := s.findDuplicate(r.Context(), &models.Job{Link: link}); existing != nil {
+		writeJSON(w, http.StatusOK, models.IngestResult{Job: *existing, Duplicate: true})
+		return
+	}
+
+	job, err := s.extractPosting(r.Context(), link, req)
+	var budget *scraper.BudgetError
+	if errors.As(err, &budget) {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(time.Until(budget.Resets).Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "%v; send the posting's html or title to store it without fetching", err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "failed to read posting: %v", err)
+		return
+	}
+	applyIngestOverrides(job, req)
+	job.Relevance = s.scorer.Score(job, relevance.Query{Keywords: req.Keywords})
+	if !job.IsValid() {
+		writeError(w, http.StatusUnprocessableEntity, "could not find the posting's title; send it in the title field")
+		return
+	}
+
+	s.ingestMutex.Lock()
+	defer s.ingestMutex.Unlock()
+
+	if existing := s.findDuplicate(r.Context(), job); existing != nil {
+		writeJSON(w, http.StatusOK, models.IngestResult{Job: *existing, Duplicate: true})
+		return
+	}
+
+	if err := s.storage.Store(r.Context(), []models.Job{*job}); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to store job: %v", err)
+		return
+	}
+	s.scorer.Index([]models.Job{*job})
+	s.logger.WithField("job_id", job.ID).Infof("Ingested %s at %s", job.Title, job.Company)
+	if s.dispatch != nil {
+		s.dispatch.Dispatch([]models.Job{*job})
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusCreated, models.IngestResult{Job: *job})
+}
+
+// extractPosting builds a job from the page HTML, the selected text, or by
+// fetching the page, in that order of preference. Selected text replaces the
+// parsed description since it is what the user cared about. A failed fetch
+// is only an error when the request carries no title of its own.
+func (s *Server) extractPosting(ctx context.Context, link string, req models.IngestRequest) (*models.Job, error) {
+	if req.HTML == "" && req.Text != "" {
+		return scraper.PostingFromText(link, req.Text), nil
+	}
+
+	var job *models.Job
+	var err error
+	if req.HTML != "" {
+		job, err = scraper.ParsePosting(link, []byte(req.HTML))
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, ingestFetchTimeout)
+		defer cancel()
+		// The fetch shares the domain's request budget with scrapes
+		if err = s.scraper.SpendRequest(link); err == nil {
+			client := transport.NewClient(ingestFetchTimeout)
+			job, err = scraper.FetchPosting(ctx, client, link, s.scraper.GetConfig().GlobalSettings.UserAgent)
+		}
+		// The fields sent with the request are enough to store the posting
+		if err != nil && strings.TrimSpace(req.Title) != "" {
+			s.logger.Warnf("Storing %s without its page: %v", link, err)
+			job, err = &models.Job{Link: link}, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if text := strings.TrimSpace(req.Text); text != "" {
+		job.Description = text
+	}
+	return job, nil
+}
+
+// applyIngestOverrides fills in fields sent explicitly and the ones scraped
+// jobs get from the scraper
+func applyIngestOverrides(job *models.Job, req models.IngestRequest) {
+	overrides := []struct {
+		field *string
+		value string
+	}{
+		{&job.Title, req.Title},
+		{&job.Company, req.Company},
+		{&job.Location, req.Location},
+		{&job.Salary, req.Salary},
+		{&job.Source, req.Source},
+	}
+	for _, override := range overrides {
+		if value := strings.TrimSpace(override.value); value != "" {
+			*override.field = value
+		}
+	}
+
+	if job.Company == "" {
+		job.Company = scraper.CompanyFromURL(job.Link)
+	}
+	job.Company = models.CompanyName(job.Company)
+	if job.Source == "" {
+		job.Source = ingestSource
+	}
+
+	now := time.Now().UTC()
+	job.ScrapedAt = now
+	job.UpdatedAt = now
+	job.IsActive = true
+	job.ExtractKeywords()
+	job.ID = job.GenerateID()
+}
+
+// findDuplicate returns the stored job with the same ID or link, or the same
+// title at the same company. Only the link is compared when job has no title.
+func (s *Server) findDuplicate(ctx context.Context, job *models.Job) *models.Job {
+	if job.ID != "" {
+		if existing, err := s.storage.GetByID(ctx, job.ID); err == nil {
+			return existing
+		}
+	}
+
+	jobs, err := s.storage.GetAll(ctx)
+	if err != nil {
+		return nil
+	}
+	for i := range jobs {
+		if jobs[i].Link == job.Link || (job.Title != "" && job.IsDuplicate(&jobs[i])) {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// normalizeLink drops the fragment and utm_* tracking parameters so the same
+// posting shared through different links is recognised as a duplicate
+func normalizeLink(raw string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid url %q", raw)
+	}
+
+	parsed.Fragment = ""
+	query := parsed.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
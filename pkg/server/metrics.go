@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/api"
+	"hire.ai/pkg/metrics"
+	"hire.ai/pkg/models"
+)
+
+// serverMetrics are the Prometheus metrics exposed at /metrics
+type serverMetrics struct {
+	registry       *metrics.Registry
+	scrapeDuration *metrics.Histogram
+	scrapeRuns     *metrics.Counter
+	jobsScraped    *metrics.Counter
+	sourceDuration *metrics.Histogram
+	sourceJobs     *metrics.Counter
+	httpRequests   *metrics.Counter
+	httpDuration   *metrics.Histogram
+}
+
+// sizer is implemented by storage backends that can report their size on disk
+type sizer interface {
+	Size() (int64, error)
+}
+
+func newServerMetrics(s *Server) *serverMetrics {
+	registry := metrics.NewRegistry()
+	m := &serverMetrics{
+		registry: registry,
+		scrapeDuration: registry.NewHistogram("hireai_scrape_duration_seconds",
+			"Duration of scrape runs started through the API.", nil, "status"),
+		scrapeRuns: registry.NewCounter("hireai_scrape_runs_total",
+			"Scrape runs started through the API by outcome.", "status"),
+		jobsScraped: registry.NewCounter("hireai_jobs_scraped_total",
+			"Jobs scraped and stored by API-triggered runs."),
+		sourceDuration: registry.NewHistogram("hireai_source_scrape_duration_seconds",
+			"Time each job board, or the API providers as a group, took within API-triggered runs.", nil, "source", "status"),
+		sourceJobs: registry.NewCounter("hireai_source_jobs_found_total",
+			"Jobs found per job board, or by the API providers as a group, in API-triggered runs.", "source"),
+		httpRequests: registry.NewCounter("hireai_http_requests_total",
+			"HTTP requests served by route, method and status code.", "route", "method", "code"),
+		httpDuration: registry.NewHistogram("hireai_http_request_duration_seconds",
+			"HTTP request latency by route.", nil, "route"),
+	}
+
+	providerDuration := registry.NewHistogram("hireai_provider_request_duration_seconds",
+		"API provider request latency, rate limit waits included, by outcome.", nil, "provider", "result")
+	s.scraper.ObserveAPIRequests(func(provider string, duration time.Duration, err error) {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		providerDuration.Observe(duration.Seconds(), provider, result)
+	})
+
+	registry.NewGaugeFunc("hireai_scrape_queue_depth", "Scrape runs queued or running.",
+		func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(s.queue.pending())}}
+		})
+
+	registry.NewGaugeFunc("hireai_jobs_stored", "Jobs currently in storage.",
+		func() []metrics.Sample {
+			stats, err := s.storage.GetStats(context.Background())
+			if err != nil {
+				return nil
+			}
+			return []metrics.Sample{{Value: float64(stats.TotalJobs)}}
+		})
+
+	registry.NewGaugeFunc("hireai_storage_size_bytes", "Size of the job store on disk.",
+		func() []metrics.Sample {
+			store, ok := s.storage.(sizer)
+			if !ok {
+				return nil
+			}
+			size, err := store.Size()
+			if err != nil {
+				return nil
+			}
+			return []metrics.Sample{{Value: float64(size)}}
+		})
+
+	registry.NewCounterFunc("hireai_provider_requests_total", "API provider requests by outcome.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for name, stats := range s.scraper.GetAPIStats() {
+				samples = append(samples,
+					metrics.Sample{LabelValues: []string{name, "success"}, Value: float64(stats.SuccessRequests)},
+					metrics.Sample{LabelValues: []string{name, "failure"}, Value: float64(stats.FailedRequests)})
+			}
+			return samples
+		}, "provider", "result")
+
+	registry.NewGaugeFunc("hireai_provider_error_ratio", "Share of API provider requests that failed.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for name, stats := range s.scraper.GetAPIStats() {
+				ratio := 0.0
+				if stats.TotalRequests > 0 {
+					ratio = float64(stats.FailedRequests) / float64(stats.TotalRequests)
+				}
+				samples = append(samples, metrics.Sample{LabelValues: []string{name}, Value: ratio})
+			}
+			return samples
+		}, "provider")
+
+	registry.NewGaugeFunc("hireai_provider_latency_seconds", "Average API provider request latency.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for name, stats := range s.scraper.GetAPIStats() {
+				samples = append(samples, metrics.Sample{LabelValues: []string{name}, Value: stats.AverageLatency.Seconds()})
+			}
+			return samples
+		}, "provider")
+
+	registry.NewGaugeFunc("hireai_provider_circuit_open", "Whether an API provider's circuit breaker is open (1) or half-open (0.5).",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for name, stats := range s.scraper.GetAPIStats() {
+				value := 0.0
+				switch stats.CircuitState {
+				case api.CircuitOpen:
+					value = 1
+				case api.CircuitHalfOpen:
+					value = 0.5
+				}
+				samples = append(samples, metrics.Sample{LabelValues: []string{name}, Value: value})
+			}
+			return samples
+		}, "provider")
+
+	registry.NewCounterFunc("hireai_proxy_requests_total", "Requests through each proxy by outcome, including earlier runs'.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for _, usage := range s.scraper.ProxyUsage() {
+				samples = append(samples,
+					metrics.Sample{LabelValues: []string{usage.Proxy, "success"}, Value: float64(usage.Successes)},
+					metrics.Sample{LabelValues: []string{usage.Proxy, "blocked"}, Value: float64(usage.Blocks)},
+					metrics.Sample{LabelValues: []string{usage.Proxy, "failure"}, Value: float64(usage.Failures)})
+			}
+			return samples
+		}, "proxy", "result")
+
+	registry.NewCounterFunc("hireai_ai_requests_total", "LLM requests by task and outcome.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for task, usage := range ai.UsageByTask() {
+				samples = append(samples,
+					metrics.Sample{LabelValues: []string{task, "success"}, Value: float64(usage.Requests - usage.Failures)},
+					metrics.Sample{LabelValues: []string{task, "failure"}, Value: float64(usage.Failures)},
+					metrics.Sample{LabelValues: []string{task, "cached"}, Value: float64(usage.CachedRequests)})
+			}
+			return samples
+		}, "task", "result")
+
+	registry.NewCounterFunc("hireai_ai_tokens_total", "LLM tokens used by task and kind.",
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for task, usage := range ai.UsageByTask() {
+				samples = append(samples,
+					metrics.Sample{LabelValues: []string{task, "prompt"}, Value: float64(usage.PromptTokens)},
+					metrics.Sample{LabelValues: []string{task, "completion"}, Value: float64(usage.CompletionTokens)})
+			}
+			return samples
+		}, "task", "kind")
+
+	return m
+}
+
+// observeSource records a source's time and jobs once it has settled
+func (m *serverMetrics) observeSource(update models.SourceProgress) {
+	switch update.Status {
+	case models.SourceCompleted, models.SourceFailed, models.SourceDeferred:
+		m.sourceDuration.Observe(update.Duration.Seconds(), update.Source, update.Status)
+		m.sourceJobs.Add(float64(update.JobsFound), update.Source)
+	}
+}
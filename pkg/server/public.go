@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"hire.ai/pkg/auth"
+)
+
+const (
+	defaultPublicRequestsPerMinute = 60
+	defaultPublicBurst             = 20
+	// clientIdleTimeout is how long an IP's limiter is kept after its last request
+	clientIdleTimeout = 10 * time.Minute
+)
+
+// PublicConfig configures public mode, where the server exposes only the
+// read-only routes in publicRoutes to anyone, without tokens, and limits how
+// often each client IP may call them
+type PublicConfig struct {
+	// RequestsPerMinute is the sustained rate allowed per client IP, with
+	// Burst requests allowed at once; defaults 60 and 20
+	RequestsPerMinute int
+	Burst             int
+	// TrustProxy takes the client IP from the last X-Forwarded-For entry;
+	// enable it only behind a reverse proxy that sets the header
+	TrustProxy bool
+}
+
+// publicRoutes are the mux patterns served in public mode. Everything that
+// changes state or reveals per-user data is left out.
+var publicRoutes = map[string]bool{
+	"/jobs":             true,
+	"/jobs/":            true,
+	"/stats":            true,
+	"/boards":           true,
+	"/providers/status": true,
+	"/openapi.json":     true,
+	"/healthz":          true,
+	"/readyz":           true,
+}
+
+// publicReader is the identity of every public-mode request. It has no user
+// and only the read scope, so handlers refuse anything beyond reads.
+var publicReader = &auth.Token{Name: "public", Scope: auth.ScopeRead}
+
+// publicOnly serves GET requests to publicRoutes and hides everything else
+func (s *Server) publicOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, route := s.mux.Handler(r); !publicRoutes[route] {
+			writeError(w, http.StatusNotFound, "%s is not available on the public API", r.URL.Path)
+			return
+		}
+		if !allowMethods(w, r, http.MethodGet) {
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenContextKey, publicReader)))
+	})
+}
+
+// rateLimit rejects clients that exceed their per-IP budget with 429
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	limiter := newIPLimiter(*s.public)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r, s.public.TrustProxy)
+		if !limiter.allow(ip) {
+			w.Header().Set("Retry-After", strconv.Itoa(limiter.retryAfter()))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded; retry later")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ipLimiter keeps a token bucket per client IP, forgetting idle clients
+type ipLimiter struct {
+	mutex     sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newIPLimiter(config PublicConfig) *ipLimiter {
+	perMinute := config.RequestsPerMinute
+	if perMinute <= 0 {
+		perMinute = defaultPublicRequestsPerMinute
+	}
+	burst := config.Burst
+	if burst <= 0 {
+		burst = defaultPublicBurst
+	}
+
+	return &ipLimiter{
+		limit:     rate.Limit(float64(perMinute) / 60),
+		burst:     burst,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *ipLimiter) allow(ip string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > clientIdleTimeout {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > clientIdleTimeout {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[ip] = client
+	}
+	client.lastSeen = now
+	return client.limiter.AllowN(now, 1)
+}
+
+// retryAfter is the whole seconds until a throttled client earns a request
+func (l *ipLimiter) retryAfter() int {
+	return int(math.Ceil(1 / float64(l.limit)))
+}
+
+// clientIP returns the IP a request came from
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// cors answers preflight requests and marks responses readable by browsers
+// on the allowed origins; "*" allows any origin
+func (s *Server) cors(next http.Handler) http.Handler {
+	if len(s.corsOrigins) == 0 {
+		return next
+	}
+
+	allowed := make(map[string]bool, len(s.corsOrigins))
+	for _, origin := range s.corsOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	methods := "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	if s.public != nil {
+		methods = "GET, OPTIONS"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !(allowed["*"] || allowed[origin]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if allowed["*"] {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "Location, Retry-After")
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
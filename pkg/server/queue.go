@@ -0,0 +1,317 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+const (
+	// maxTrackedRuns bounds the finished runs kept for GET /scrape
+	maxTrackedRuns = 100
+	// maxQueuedRuns bounds runs waiting for a worker
+	maxQueuedRuns = 50
+)
+
+var (
+	errQueueFull   = errors.New("scrape queue is full")
+	errRunNotFound = errors.New("scrape run not found")
+	errRunStarted  = errors.New("scrape run has already started")
+)
+
+// scrapeQueue holds scrape runs requested through the API. Runs wait in FIFO
+// order until a worker picks them up, so a burst of requests never starts
+// more scrapes than there are workers. The queue and recent history are
+// saved after every change when a store is configured.
+type scrapeQueue struct {
+	mutex   sync.RWMutex
+	runs    map[string]*models.ScrapeRun
+	order   []string
+	store   *storage.RunStore
+	logger  *logrus.Logger
+	wake    chan struct{}
+	counter int
+}
+
+func newScrapeQueue(store *storage.RunStore, logger *logrus.Logger) *scrapeQueue {
+	return &scrapeQueue{
+		runs:   make(map[string]*models.ScrapeRun),
+		store:  store,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// restore loads saved runs. Runs a restart interrupted mid-scrape go back to
+// the queue, keeping their place.
+func (q *scrapeQueue) restore() error {
+	if q.store == nil {
+		return nil
+	}
+
+	runs, err := q.store.Load()
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	requeued := 0
+	for i := range runs {
+		run := runs[i]
+		if run.Status == models.RunStatusRunning {
+			run.Status = models.RunStatusQueued
+			run.StartedAt = nil
+			run.Sources = nil
+			requeued++
+		}
+		q.runs[run.ID] = &run
+		q.order = append(q.order, run.ID)
+	}
+	q.counter = len(runs)
+
+	if requeued > 0 {
+		q.logger.Infof("Requeued %d scrape runs interrupted by the last shutdown", requeued)
+		q.save()
+	}
+	return nil
+}
+
+// enqueue adds a run to the back of the queue
+func (q *scrapeQueue) enqueue(keywords []string, location string) (models.ScrapeRun, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.countLocked(models.RunStatusQueued) >= maxQueuedRuns {
+		return models.ScrapeRun{}, errQueueFull
+	}
+
+	q.counter++
+	run := &models.ScrapeRun{
+		ID:       fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405"), q.counter),
+		Status:   models.RunStatusQueued,
+		Keywords: keywords,
+		Location: location,
+		QueuedAt: time.Now(),
+	}
+	q.runs[run.ID] = run
+	q.order = append(q.order, run.ID)
+	q.trim()
+	q.save()
+
+	q.signal()
+	return snapshot(run), nil
+}
+
+// next marks the oldest queued run as running and returns it
+func (q *scrapeQueue) next() (models.ScrapeRun, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, id := range q.order {
+		run := q.runs[id]
+		if run.Status != models.RunStatusQueued {
+			continue
+		}
+
+		startedAt := time.Now()
+		run.Status = models.RunStatusRunning
+		run.StartedAt = &startedAt
+		q.save()
+
+		// Let another idle worker look for more queued work
+		if q.countLocked(models.RunStatusQueued) > 0 {
+			q.signal()
+		}
+		return snapshot(run), true
+	}
+	return models.ScrapeRun{}, false
+}
+
+// progress records a source update for a running run
+func (q *scrapeQueue) progress(id string, update models.SourceProgress) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	run, ok := q.runs[id]
+	if !ok {
+		return
+	}
+
+	for i := range run.Sources {
+		if run.Sources[i].Source == update.Source {
+			run.Sources[i] = update
+			q.saveIfSettled(update)
+			return
+		}
+	}
+	run.Sources = append(run.Sources, update)
+	q.saveIfSettled(update)
+}
+
+func (q *scrapeQueue) finish(id string, jobsFound int, err error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	run, ok := q.runs[id]
+	if !ok {
+		return
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.JobsFound = jobsFound
+	if err != nil {
+		run.Status = models.RunStatusFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = models.RunStatusCompleted
+	}
+	q.trim()
+	q.save()
+}
+
+// cancel withdraws a run that has not started yet
+func (q *scrapeQueue) cancel(id string) (models.ScrapeRun, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	run, ok := q.runs[id]
+	if !ok {
+		return models.ScrapeRun{}, errRunNotFound
+	}
+	if run.Status != models.RunStatusQueued {
+		return snapshot(run), errRunStarted
+	}
+
+	finishedAt := time.Now()
+	run.Status = models.RunStatusCancelled
+	run.FinishedAt = &finishedAt
+	q.save()
+	return snapshot(run), nil
+}
+
+func (q *scrapeQueue) get(id string) (models.ScrapeRun, bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	run, ok := q.runs[id]
+	if !ok {
+		return models.ScrapeRun{}, false
+	}
+	return snapshot(run), true
+}
+
+// pending returns how many runs are queued or running
+func (q *scrapeQueue) pending() int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	return q.countLocked(models.RunStatusQueued) + q.countLocked(models.RunStatusRunning)
+}
+
+// list returns tracked runs, newest first
+func (q *scrapeQueue) list() []models.ScrapeRun {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	runs := make([]models.ScrapeRun, 0, len(q.order))
+	for i := len(q.order) - 1; i >= 0; i-- {
+		runs = append(runs, snapshot(q.runs[q.order[i]]))
+	}
+	return runs
+}
+
+// snapshot copies a run so callers can read it after the lock is released
+func snapshot(run *models.ScrapeRun) models.ScrapeRun {
+	copied := *run
+	copied.Sources = append([]models.SourceProgress(nil), run.Sources...)
+	return copied
+}
+
+func (q *scrapeQueue) countLocked(status string) int {
+	n := 0
+	for _, run := range q.runs {
+		if run.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// trim drops the oldest finished runs beyond maxTrackedRuns; queued and
+// running runs are always kept
+func (q *scrapeQueue) trim() {
+	excess := len(q.order) - maxTrackedRuns
+	if excess <= 0 {
+		return
+	}
+
+	kept := q.order[:0]
+	for _, id := range q.order {
+		if excess > 0 && q.runs[id].Done() {
+			delete(q.runs, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	q.order = kept
+}
+
+// saveIfSettled saves on updates worth surviving a restart, skipping the
+// pending and running transitions in between
+func (q *scrapeQueue) saveIfSettled(update models.SourceProgress) {
+	if update.Status == models.SourceCompleted || update.Status == models.SourceFailed || update.Status == models.SourceDeferred {
+		q.save()
+	}
+}
+
+// save writes every tracked run to the store; callers hold the lock
+func (q *scrapeQueue) save() {
+	if q.store == nil {
+		return
+	}
+
+	runs := make([]models.ScrapeRun, 0, len(q.order))
+	for _, id := range q.order {
+		runs = append(runs, *q.runs[id])
+	}
+	if err := q.store.Save(runs); err != nil {
+		q.logger.Errorf("Failed to save scrape queue: %v", err)
+	}
+}
+
+// signal wakes one idle worker without blocking
+func (q *scrapeQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runWorkers starts n workers that execute queued runs until ctx is cancelled
+func (s *Server) runWorkers(ctx context.Context, n int) {
+	for i := 0; i < n; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				run, ok := s.queue.next()
+				if !ok {
+					select {
+					case <-ctx.Done():
+					case <-s.queue.wake:
+					}
+					continue
+				}
+				s.executeRun(run)
+			}
+		}()
+	}
+}
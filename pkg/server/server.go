@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/geo"
+	"hire.ai/pkg/graphql"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/relevance"
+	"hire.ai/pkg/scraper"
+	"hire.ai/pkg/storage"
+	"hire.ai/pkg/webhook"
+)
+
+// openAPISpec documents the REST API; keep it in sync with the handlers
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// ScrapeFunc runs one scrape for the given keywords and location, stores the
+// results and returns how many jobs were scraped. progress receives
+// per-source updates and may be called concurrently.
+type ScrapeFunc func(ctx context.Context, keywords []string, location string, progress func(models.SourceProgress)) (int, error)
+
+// Server exposes stored jobs, stats, providers and boards over HTTP and lets
+// clients trigger scrape runs
+type Server struct {
+	storage  storage.Storage
+	searches *storage.SavedSearchStore
+	users    *storage.UserStore
+	apps     *storage.ApplicationStore
+	webhooks *storage.WebhookStore
+	dispatch *webhook.Dispatcher
+	scraper  *scraper.ScraperCore
+	scrape   ScrapeFunc
+	geocoder *geo.Geocoder
+	scorer   relevance.Scorer
+	tokens   *auth.TokenStore
+	insecure bool
+	logger   *logrus.Logger
+
+	public      *PublicConfig
+	corsOrigins []string
+	// ingestMutex serialises POST /ingest so concurrent pushes of the same
+	// posting cannot both pass the duplicate check
+	ingestMutex sync.Mutex
+
+	queue     *scrapeQueue
+	workers   int
+	readiness *readiness
+	metrics   *serverMetrics
+	graphql   *graphql.Schema
+	mux       *http.ServeMux
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// Config holds the dependencies of the API server
+type Config struct {
+	Storage storage.Storage
+	// SavedSearches may be nil, in which case saved searches are reported as empty
+	SavedSearches *storage.SavedSearchStore
+	// Users and Applications may be nil, in which case only the shared
+	// default profile exists and application statuses are unavailable
+	Users        *storage.UserStore
+	Applications *storage.ApplicationStore
+	// Webhooks may be nil to disable webhook subscriptions; Dispatcher is
+	// used to send test deliveries
+	Webhooks   *storage.WebhookStore
+	Dispatcher *webhook.Dispatcher
+	Scraper    *scraper.ScraperCore
+	Scrape     ScrapeFunc
+	// Geocoder places the near filters of searches; when nil, searching
+	// near a place is refused
+	Geocoder *geo.Geocoder
+	// Scorer scores ingested postings; when nil, they get the share of
+	// their keywords they mention
+	Scorer relevance.Scorer
+	// Runs persists the scrape queue; when nil, queued runs are lost on restart
+	Runs *storage.RunStore
+	// ScrapeWorkers is how many queued runs may execute at once; default 1,
+	// since runs share the scraper's rate limiters and proxies
+	ScrapeWorkers int
+	// Tokens holds the bearer tokens every non-public route requires. While
+	// it holds none, those routes are refused unless Insecure is set.
+	Tokens *auth.TokenStore
+	// Insecure serves every route as admin without a token while no tokens
+	// exist, for local use only
+	Insecure bool
+	// Public, when set, serves only the read-only public routes without
+	// tokens and rate limits them per client IP
+	Public *PublicConfig
+	// CORSOrigins lists browser origins allowed to call the API; "*" allows
+	// any origin and an empty list disables CORS
+	CORSOrigins []string
+	Logger      *logrus.Logger
+}
+
+// NewServer creates a new API server from the given configuration
+func NewServer(config Config) *Server {
+	logger := config.Logger
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	scorer := config.Scorer
+	if scorer == nil {
+		scorer = relevance.Keyword{}
+	}
+
+	workers := config.ScrapeWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		storage:     config.Storage,
+		searches:    config.SavedSearches,
+		users:       config.Users,
+		apps:        config.Applications,
+		webhooks:    config.Webhooks,
+		dispatch:    config.Dispatcher,
+		scraper:     config.Scraper,
+		geocoder:    config.Geocoder,
+		scorer:      scorer,
+		scrape:      config.Scrape,
+		tokens:      config.Tokens,
+		insecure:    config.Insecure,
+		logger:      logger,
+		public:      config.Public,
+		corsOrigins: config.CORSOrigins,
+		queue:       newScrapeQueue(config.Runs, logger),
+		workers:     workers,
+		readiness:   &readiness{checks: make(map[string]ReadinessCheck)},
+		mux:         http.NewServeMux(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	if err := s.queue.restore(); err != nil {
+		logger.Errorf("Failed to restore scrape queue: %v", err)
+	}
+
+	s.registerDefaultChecks()
+	s.metrics = newServerMetrics(s)
+	s.graphql = s.buildGraphQLSchema()
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("/jobs", s.handleJobs)
+	s.mux.HandleFunc("/jobs/", s.handleJob)
+	s.mux.HandleFunc("/stats", s.handleStats)
+	s.mux.HandleFunc("/scrape", s.handleScrape)
+	s.mux.HandleFunc("/scrape/", s.handleScrapeRun)
+	s.mux.HandleFunc("/providers/status", s.handleProviders)
+	s.mux.HandleFunc("/providers", s.handleProviderConfigs)
+	s.mux.HandleFunc("/providers/", s.handleProviderConfig)
+	s.mux.HandleFunc("/boards", s.handleBoards)
+	s.mux.HandleFunc("/boards/", s.handleBoard)
+	s.mux.HandleFunc("/me", s.handleMe)
+	s.mux.HandleFunc("/users", s.handleUsers)
+	s.mux.HandleFunc("/applications", s.handleApplications)
+	s.mux.HandleFunc("/applications/", s.handleApplication)
+	s.mux.HandleFunc("/webhooks", s.handleWebhooks)
+	s.mux.HandleFunc("/webhooks/", s.handleWebhook)
+	s.mux.HandleFunc("/ingest", s.handleIngest)
+	s.mux.HandleFunc("/graphql", s.handleGraphQL)
+	s.mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	s.mux.Handle("/metrics", s.metrics.registry.Handler())
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+}
+
+// Handler returns the HTTP handler with authentication, request logging and
+// metrics applied. In public mode, rate limiting and the public route filter
+// replace authentication.
+func (s *Server) Handler() http.Handler {
+	if s.public != nil {
+		return s.instrument(s.cors(s.rateLimit(s.publicOnly(s.mux))))
+	}
+	return s.instrument(s.cors(s.authenticate(s.mux)))
+}
+
+// ListenAndServe starts the scrape workers and serves on addr until ctx is
+// cancelled, then shuts down gracefully and cancels any scrape still in flight
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	s.runWorkers(s.ctx, s.workers)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Infof("API server listening on %s", addr)
+		if s.public != nil {
+			s.logger.Info("Public mode: serving read-only routes without authentication")
+		} else if s.tokens == nil || !s.tokens.Enabled() {
+			if s.insecure {
+				s.logger.Warn("API authentication is disabled: no tokens exist and -insecure is set, so anyone reaching this address has admin access")
+			} else {
+				s.logger.Warn("No API tokens exist: every route but health checks and the spec is refused until one is created with 'job-scraper tokens create'")
+			}
+		}
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		s.cancel()
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down API server...")
+	s.setDraining()
+	s.cancel()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// instrument logs each request and records it in the HTTP metrics, labelled
+// by the matched route so IDs in paths don't create new series
+func (s *Server) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		duration := time.Since(start)
+
+		_, route := s.mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		s.metrics.httpRequests.Inc(route, r.Method, strconv.Itoa(recorder.status))
+		s.metrics.httpDuration.Observe(duration.Seconds(), route)
+
+		s.logger.WithFields(logrus.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   recorder.status,
+			"duration": duration.String(),
+		}).Debug("HTTP request")
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// errorResponse is the body returned for every non-2xx response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// allowMethods rejects the request with 405 unless it uses one of methods
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	return false
+}
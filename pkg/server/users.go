@@ -0,0 +1,204 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hire.ai/pkg/auth"
+	"hire.ai/pkg/models"
+)
+
+// defaultProfile is reported by /me for tokens without a user and when auth
+// is disabled
+var defaultProfile = models.User{Name: "default"}
+
+// currentUser loads the account the request acts for, or the default profile
+func (s *Server) currentUser(r *http.Request) (*models.User, error) {
+	id := requestUser(r.Context())
+	if id == "" {
+		profile := defaultProfile
+		return &profile, nil
+	}
+	if s.users == nil {
+		return nil, fmt.Errorf("user %s not found", id)
+	}
+	return s.users.Get(id)
+}
+
+// handleMe returns the caller's profile on GET and updates their name, email
+// and notification settings on PATCH
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPatch) {
+		return
+	}
+
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, user)
+		return
+	}
+
+	if user.ID == "" {
+		writeError(w, http.StatusBadRequest, "the default profile cannot be edited; use a token created with -user")
+		return
+	}
+
+	var update models.ProfileUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if update.Name != nil {
+		if strings.TrimSpace(*update.Name) == "" {
+			writeError(w, http.StatusBadRequest, "name cannot be empty")
+			return
+		}
+		user.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Email != nil {
+		user.Email = strings.TrimSpace(*update.Email)
+	}
+	if update.Notifications != nil {
+		if update.Notifications.MinRelevance < 0 {
+			writeError(w, http.StatusBadRequest, "min_relevance cannot be negative")
+			return
+		}
+		for _, id := range update.Notifications.SavedSearches {
+			if s.searches == nil {
+				writeError(w, http.StatusBadRequest, "saved search %s not found", id)
+				return
+			}
+			if _, err := s.searches.Get(user.ID, id); err != nil {
+				writeError(w, http.StatusBadRequest, "%v", err)
+				return
+			}
+		}
+		user.Notifications = *update.Notifications
+	}
+
+	if err := s.users.Update(user); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update profile: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// handleUsers lists accounts for admin tokens. Users are created and removed
+// with the CLI.
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	if !s.requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	users := []models.User{}
+	if s.users != nil {
+		users = s.users.List()
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+// handleApplications serves GET /applications, optionally filtered by status
+func (s *Server) handleApplications(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" {
+		if err := models.ValidateApplicationStatus(status); err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+	}
+
+	applications := []models.Application{}
+	if s.apps != nil {
+		applications = s.apps.List(requestUser(r.Context()), status)
+	}
+	writeJSON(w, http.StatusOK, applications)
+}
+
+// handleApplication serves GET, PUT and DELETE /applications/{job_id} for the
+// caller's own application status on a job
+func (s *Server) handleApplication(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	if s.apps == nil {
+		writeError(w, http.StatusServiceUnavailable, "application tracking is not available")
+		return
+	}
+
+	jobID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/applications/"), "/")
+	if jobID == "" {
+		writeError(w, http.StatusNotFound, "job id is required")
+		return
+	}
+	userID := requestUser(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		application, err := s.apps.Get(userID, jobID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "%v", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, application)
+
+	case http.MethodPut:
+		if !s.requireProfileWrite(w, r) {
+			return
+		}
+		if _, err := s.storage.GetByID(r.Context(), jobID); err != nil {
+			writeError(w, http.StatusNotFound, "job %s not found", jobID)
+			return
+		}
+
+		var req models.ApplicationRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+			return
+		}
+
+		application := &models.Application{
+			JobID:  jobID,
+			UserID: userID,
+			Status: strings.ToLower(strings.TrimSpace(req.Status)),
+			Notes:  strings.TrimSpace(req.Notes),
+		}
+		if err := models.ValidateApplicationStatus(application.Status); err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+		if err := s.apps.Set(application); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to save application: %v", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, application)
+
+	case http.MethodDelete:
+		if !s.requireProfileWrite(w, r) {
+			return
+		}
+		deleted, err := s.apps.Delete(userID, jobID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to delete application: %v", err)
+			return
+		}
+		if !deleted {
+			writeError(w, http.StatusNotFound, "no application for job %s", jobID)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+// handleWebhooks lists the caller's webhooks on GET and registers a new one
+// on POST. The signing secret is only returned by POST.
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if s.webhooks == nil {
+		writeError(w, http.StatusServiceUnavailable, "webhooks are not available")
+		return
+	}
+	userID := requestUser(r.Context())
+
+	if r.Method == http.MethodGet {
+		webhooks := s.webhooks.List(userID)
+		for i := range webhooks {
+			webhooks[i].Secret = ""
+		}
+		writeJSON(w, http.StatusOK, webhooks)
+		return
+	}
+
+	if !s.requireProfileWrite(w, r) {
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	target, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		writeError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+	if req.MinRelevance < 0 {
+		writeError(w, http.StatusBadRequest, "min_relevance cannot be negative")
+		return
+	}
+
+	webhook := &models.Webhook{
+		UserID:  userID,
+		URL:     target.String(),
+		Enabled: true,
+		Filter: models.WebhookFilter{
+			Keywords:     req.Keywords,
+			KeywordQuery: req.KeywordQuery,
+			Sources:      req.Sources,
+			MinRelevance: req.MinRelevance,
+		},
+	}
+	if err := webhook.Filter.Compile(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid keyword_query: %v", err)
+		return
+	}
+	if err := s.webhooks.Create(webhook); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create webhook: %v", err)
+		return
+	}
+
+	w.Header().Set("Location", "/webhooks/"+webhook.ID)
+	writeJSON(w, http.StatusCreated, webhook)
+}
+
+// handleWebhook serves GET and DELETE /webhooks/{id} and POST
+// /webhooks/{id}/test, which sends a ping delivery and reports the outcome
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		writeError(w, http.StatusServiceUnavailable, "webhooks are not available")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhooks/"), "/")
+	id, action, _ := strings.Cut(path, "/")
+	if action != "" && action != "test" {
+		writeError(w, http.StatusNotFound, "unknown webhook action %s", action)
+		return
+	}
+
+	methods := []string{http.MethodGet, http.MethodDelete}
+	if action == "test" {
+		methods = []string{http.MethodPost}
+	}
+	if !allowMethods(w, r, methods...) {
+		return
+	}
+
+	userID := requestUser(r.Context())
+	webhook, err := s.webhooks.Get(userID, id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+
+	switch {
+	case action == "test":
+		if !s.requireProfileWrite(w, r) {
+			return
+		}
+		if s.dispatch == nil {
+			writeError(w, http.StatusServiceUnavailable, "webhook delivery is not available")
+			return
+		}
+		status, err := s.dispatch.Ping(r.Context(), *webhook)
+		result := models.WebhookTestResult{Delivered: err == nil, Status: status}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, result)
+
+	case r.Method == http.MethodGet:
+		webhook.Secret = ""
+		writeJSON(w, http.StatusOK, webhook)
+
+	case r.Method == http.MethodDelete:
+		if !s.requireProfileWrite(w, r) {
+			return
+		}
+		if _, err := s.webhooks.Delete(userID, id); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to delete webhook: %v", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"hire.ai/pkg/models"
+)
+
+const alertsFileName = "alerts.json"
+
+// AlertStore keeps the jobs saved searches matched as a JSON array in the
+// data directory
+type AlertStore struct {
+	filePath string
+	alerts   []models.Alert
+	mutex    sync.RWMutex
+}
+
+// NewAlertStore creates a new alert store in the specified data directory
+func NewAlertStore(dataDir string) (*AlertStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &AlertStore{filePath: filepath.Join(dataDir, alertsFileName)}
+
+	data, err := os.ReadFile(store.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", store.filePath, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.alerts); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", store.filePath, err)
+		}
+	}
+
+	return store, nil
+}
+
+// Add records alerts, skipping jobs a search already alerted about, and
+// returns the alerts that were new
+func (s *AlertStore) Add(alerts []models.Alert) ([]models.Alert, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	known := make(map[string]bool, len(s.alerts))
+	for _, alert := range s.alerts {
+		known[alertKey(alert)] = true
+	}
+
+	var added []models.Alert
+	for _, alert := range alerts {
+		key := alertKey(alert)
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		added = append(added, alert)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	s.alerts = append(s.alerts, added...)
+	return added, s.save()
+}
+
+// List returns the user's alerts, newest first, limited to one saved search
+// when searchID is set and to unread alerts when unreadOnly is set; an empty
+// userID selects the shared default profile
+func (s *AlertStore) List(userID, searchID string, unreadOnly bool) []models.Alert {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	alerts := make([]models.Alert, 0)
+	for _, alert := range s.alerts {
+		if alert.UserID != userID || (searchID != "" && alert.SearchID != searchID) || (unreadOnly && alert.Read) {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].MatchedAt.After(alerts[j].MatchedAt) })
+	return alerts
+}
+
+// Unread counts the user's unread alerts by saved search ID
+func (s *AlertStore) Unread(userID string) map[string]int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	counts := make(map[string]int)
+	for _, alert := range s.alerts {
+		if alert.UserID == userID && !alert.Read {
+			counts[alert.SearchID]++
+		}
+	}
+	return counts
+}
+
+// MarkRead marks the user's unread alerts read, only those of one saved
+// search when searchID is set, and returns how many it marked
+func (s *AlertStore) MarkRead(userID, searchID string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	marked := 0
+	for i := range s.alerts {
+		alert := &s.alerts[i]
+		if alert.UserID == userID && (searchID == "" || alert.SearchID == searchID) && !alert.Read {
+			alert.Read = true
+			marked++
+		}
+	}
+	if marked == 0 {
+		return 0, nil
+	}
+	return marked, s.save()
+}
+
+// DeleteSearch removes the alerts of the user's saved search and returns
+// how many were removed
+func (s *AlertStore) DeleteSearch(userID, searchID string) (int, error) {
+	return s.delete(func(alert models.Alert) bool {
+		return alert.UserID == userID && alert.SearchID == searchID
+	})
+}
+
+// DeleteUser removes every alert of the user's saved searches and returns
+// how many were removed
+func (s *AlertStore) DeleteUser(userID string) (int, error) {
+	return s.delete(func(alert models.Alert) bool { return alert.UserID == userID })
+}
+
+func (s *AlertStore) delete(match func(models.Alert) bool) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.alerts[:0]
+	for _, alert := range s.alerts {
+		if !match(alert) {
+			kept = append(kept, alert)
+		}
+	}
+	removed := len(s.alerts) - len(kept)
+	s.alerts = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+// alertKey identifies the job a user's saved search alerted about
+func alertKey(alert models.Alert) string {
+	return alert.UserID + "\x00" + alert.SearchID + "\x00" + alert.JobID
+}
+
+func (s *AlertStore) save() error {
+	data, err := json.MarshalIndent(s.alerts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alerts: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write alerts: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
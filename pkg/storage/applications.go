@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+const applicationsFileName = "applications.json"
+
+// ApplicationStore keeps per-user application statuses as a JSON array in the
+// data directory. Each user has at most one entry per job.
+type ApplicationStore struct {
+	filePath     string
+	applications []models.Application
+	mutex        sync.RWMutex
+}
+
+// NewApplicationStore creates a new application store in the specified data directory
+func NewApplicationStore(dataDir string) (*ApplicationStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &ApplicationStore{filePath: filepath.Join(dataDir, applicationsFileName)}
+
+	data, err := os.ReadFile(store.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", store.filePath, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.applications); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", store.filePath, err)
+		}
+	}
+
+	return store, nil
+}
+
+// List returns the user's applications, most recently updated first. A
+// non-empty status keeps only applications in that state.
+func (s *ApplicationStore) List(userID, status string) []models.Application {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	applications := make([]models.Application, 0)
+	for _, application := range s.applications {
+		if application.UserID == userID && (status == "" || application.Status == status) {
+			applications = append(applications, application)
+		}
+	}
+	sort.Slice(applications, func(i, j int) bool { return applications[i].UpdatedAt.After(applications[j].UpdatedAt) })
+	return applications
+}
+
+// Get returns the user's application for a job
+func (s *ApplicationStore) Get(userID, jobID string) (*models.Application, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.applications {
+		if s.applications[i].UserID == userID && s.applications[i].JobID == jobID {
+			application := s.applications[i]
+			return &application, nil
+		}
+	}
+	return nil, fmt.Errorf("no application for job %s", jobID)
+}
+
+// Set creates or replaces the user's application for a job
+func (s *ApplicationStore) Set(application *models.Application) error {
+	if err := models.ValidateApplicationStatus(application.Status); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	application.UpdatedAt = now
+	for i := range s.applications {
+		if s.applications[i].UserID == application.UserID && s.applications[i].JobID == application.JobID {
+			application.CreatedAt = s.applications[i].CreatedAt
+			s.applications[i] = *application
+			return s.save()
+		}
+	}
+
+	application.CreatedAt = now
+	s.applications = append(s.applications, *application)
+	return s.save()
+}
+
+// Delete removes the user's application for a job and reports whether it existed
+func (s *ApplicationStore) Delete(userID, jobID string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.applications {
+		if s.applications[i].UserID == userID && s.applications[i].JobID == jobID {
+			s.applications = append(s.applications[:i], s.applications[i+1:]...)
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+// DeleteUser removes every application owned by the user and returns how
+// many were removed
+func (s *ApplicationStore) DeleteUser(userID string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.applications[:0]
+	for _, application := range s.applications {
+		if application.UserID != userID {
+			kept = append(kept, application)
+		}
+	}
+	removed := len(s.applications) - len(kept)
+	s.applications = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+func (s *ApplicationStore) save() error {
+	data, err := json.MarshalIndent(s.applications, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode applications: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write applications: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
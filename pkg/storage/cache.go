@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// DefaultCacheSize is how many search results a CachedStorage keeps
+const DefaultCacheSize = 128
+
+// CachedStorage keeps the results of recent searches of another Storage,
+// so the same filters repeated by the server skip rescanning the jobs.
+// Every write through it drops what it has cached; writes made to the
+// wrapped storage directly are not seen.
+type CachedStorage struct {
+	Storage
+
+	size  int
+	mutex sync.Mutex
+	// generation counts writes, so results computed across a write are not
+	// cached
+	generation uint64
+	searches   map[string]*list.Element
+	order      *list.List // of *cachedSearch, most recently used first
+}
+
+type cachedSearch struct {
+	key    string
+	result *models.JobSearchResult
+}
+
+// NewCachedStorage wraps storage with a cache of up to size search results,
+// or DefaultCacheSize when size is not positive
+func NewCachedStorage(storage Storage, size int) *CachedStorage {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &CachedStorage{
+		Storage:  storage,
+		size:     size,
+		searches: make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Search returns the cached result for filter, searching the wrapped
+// storage on a miss
+func (cs *CachedStorage) Search(ctx context.Context, filter models.JobFilter) (*models.JobSearchResult, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return cs.Storage.Search(ctx, filter)
+	}
+	key := string(data)
+
+	cs.mutex.Lock()
+	if element, ok := cs.searches[key]; ok {
+		cs.order.MoveToFront(element)
+		result := copyResult(element.Value.(*cachedSearch).result)
+		cs.mutex.Unlock()
+		return result, nil
+	}
+	generation := cs.generation
+	cs.mutex.Unlock()
+
+	result, err := cs.Storage.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.generation == generation {
+		if _, ok := cs.searches[key]; !ok {
+			cs.searches[key] = cs.order.PushFront(&cachedSearch{key: key, result: copyResult(result)})
+			if cs.order.Len() > cs.size {
+				oldest := cs.order.Back()
+				cs.order.Remove(oldest)
+				delete(cs.searches, oldest.Value.(*cachedSearch).key)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (cs *CachedStorage) Store(ctx context.Context, jobs []models.Job) error {
+	defer cs.invalidate()
+	return cs.Storage.Store(ctx, jobs)
+}
+
+func (cs *CachedStorage) Upsert(ctx context.Context, jobs []models.Job) ([]models.Job, error) {
+	defer cs.invalidate()
+	return cs.Storage.Upsert(ctx, jobs)
+}
+
+func (cs *CachedStorage) MarkNotified(ctx context.Context, ids []string, channel string, at time.Time) error {
+	defer cs.invalidate()
+	return cs.Storage.MarkNotified(ctx, ids, channel, at)
+}
+
+func (cs *CachedStorage) MarkChecked(ctx context.Context, ids, expired []string, at time.Time) error {
+	defer cs.invalidate()
+	return cs.Storage.MarkChecked(ctx, ids, expired, at)
+}
+
+func (cs *CachedStorage) UpdateStatus(ctx context.Context, id, status string) (*models.Job, error) {
+	defer cs.invalidate()
+	return cs.Storage.UpdateStatus(ctx, id, status)
+}
+
+func (cs *CachedStorage) Delete(ctx context.Context, ids []string) (int, error) {
+	defer cs.invalidate()
+	return cs.Storage.Delete(ctx, ids)
+}
+
+// invalidate drops every cached result after a write
+func (cs *CachedStorage) invalidate() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	cs.generation++
+	cs.searches = make(map[string]*list.Element)
+	cs.order.Init()
+}
+
+// copyResult copies a result's job list, so callers cannot change the
+// cached jobs by editing theirs
+func copyResult(result *models.JobSearchResult) *models.JobSearchResult {
+	copied := *result
+	copied.Jobs = append([]models.Job(nil), result.Jobs...)
+	return &copied
+}
@@ -0,0 +1,708 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+const jobsFileName = "jobs.json"
+
+// searchCheckInterval is how many jobs Search scans between checks of its
+// context
+const searchCheckInterval = 1024
+
+// FileStorage stores jobs as a JSON array in the data directory
+type FileStorage struct {
+	dataDir  string
+	filePath string
+	jobs     []models.Job
+	counters *jobCounters
+	mutex    sync.RWMutex
+
+	// text indexes jobs for full-text queries. It is built by the first
+	// query after a write, under textMutex as searches share mutex.
+	text      *textIndex
+	textMutex sync.Mutex
+
+	// ranker, when set, ranks semantic searches by meaning
+	ranker SemanticRanker
+	// seen, when set, lets Upsert store certainly new jobs without
+	// indexing the whole store to look for their stored copies
+	seen SeenIndex
+}
+
+// SemanticRanker rates jobs by how close their meaning is to a
+// natural-language query
+type SemanticRanker interface {
+	// Similarities returns the similarity to query of each job close
+	// enough to it, by job ID
+	Similarities(ctx context.Context, query string, jobs []models.Job) (map[string]float64, error)
+}
+
+// SetSemanticRanker has semantic searches ranked by ranker instead of by
+// their keywords
+func (fs *FileStorage) SetSemanticRanker(ranker SemanticRanker) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	fs.ranker = ranker
+}
+
+// SeenIndex tells jobs that are certainly not stored from ones that may be,
+// as dedup.SeenIndex does
+type SeenIndex interface {
+	// Seen reports whether job may be stored under its ID, its title and
+	// company or its link; a false answer is certain
+	Seen(job *models.Job) bool
+	// Add records jobs as stored
+	Add(jobs []models.Job)
+	// Save persists the index
+	Save() error
+}
+
+// SetSeenIndex has Upsert consult seen before looking for stored copies,
+// and record and save the jobs it stores in it, so the index keeps up with
+// the store. seen must hold every stored job.
+func (fs *FileStorage) SetSeenIndex(seen SeenIndex) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	fs.seen = seen
+}
+
+// NewFileStorage creates a new file storage rooted at the specified data directory
+func NewFileStorage(dataDir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	fs := &FileStorage{
+		dataDir:  dataDir,
+		filePath: filepath.Join(dataDir, jobsFileName),
+	}
+
+	if err := fs.load(); err != nil {
+		return nil, err
+	}
+	fs.counters = newJobCounters(fs.jobs)
+
+	return fs, nil
+}
+
+// ModTime returns when the jobs were last saved, or the zero time before
+// they ever were
+func (fs *FileStorage) ModTime() time.Time {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	info, err := os.Stat(fs.filePath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (fs *FileStorage) load() error {
+	data, err := os.ReadFile(fs.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fs.filePath, err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &fs.jobs); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", fs.filePath, err)
+	}
+	// Jobs stored before timestamps were normalized may carry local
+	// offsets, and those stored before work types were fields may carry
+	// them in the description
+	for i := range fs.jobs {
+		fs.jobs[i].NormalizeTimes()
+		fs.jobs[i].DetectWorkTypes()
+	}
+
+	return nil
+}
+
+// save writes the jobs to a temporary file and renames it into place so a
+// crash mid-write never leaves a truncated jobs.json behind
+func (fs *FileStorage) save() error {
+	fs.text = nil
+	data, err := json.MarshalIndent(fs.jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode jobs: %w", err)
+	}
+
+	tmpPath := fs.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+
+	return os.Rename(tmpPath, fs.filePath)
+}
+
+// Store merges jobs into storage as Upsert does, for callers that do not
+// need to know which were new
+func (fs *FileStorage) Store(ctx context.Context, jobs []models.Job) error {
+	_, err := fs.Upsert(ctx, jobs)
+	return err
+}
+
+// Upsert merges jobs into storage. A job matching a stored one keeps the
+// stored ID, link, first-seen time and notification history and takes the
+// rest of its fields from the new scrape; duplicates within jobs are
+// merged the same way.
+func (fs *FileStorage) Upsert(ctx context.Context, jobs []models.Job) ([]models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	// Certainly new jobs are only matched against the batch before them;
+	// the store is indexed at the first job that may be stored
+	matcher := newJobMatcher(nil)
+	indexed := false
+	var inserted []models.Job
+	for _, job := range jobs {
+		job.NormalizeTimes()
+		if !indexed && (fs.seen == nil || fs.seen.Seen(&job)) {
+			matcher, indexed = newJobMatcher(fs.jobs), true
+		}
+		if i, ok := matcher.find(fs.jobs, &job); ok {
+			fs.counters.remove(&fs.jobs[i])
+			fs.jobs[i] = mergeJob(fs.jobs[i], job)
+			fs.counters.add(&fs.jobs[i])
+			continue
+		}
+
+		fs.jobs = append(fs.jobs, job)
+		fs.counters.add(&job)
+		matcher.add(fs.jobs, len(fs.jobs)-1)
+		inserted = append(inserted, job)
+	}
+
+	// The jobs are in memory, and so in the index, even if saving fails
+	if fs.seen != nil {
+		fs.seen.Add(jobs)
+	}
+	if err := fs.save(); err != nil {
+		return inserted, err
+	}
+	if fs.seen != nil {
+		if err := fs.seen.Save(); err != nil {
+			return inserted, fmt.Errorf("failed to save seen job index: %w", err)
+		}
+	}
+	return inserted, nil
+}
+
+// mergeJob refreshes stored with a newer scrape of the same posting
+func mergeJob(stored, scraped models.Job) models.Job {
+	merged := scraped
+	merged.ID = stored.ID
+	merged.Link = stored.Link
+	merged.Source = stored.Source
+	merged.ScrapedAt = stored.ScrapedAt
+	merged.NotifiedAt = stored.NotifiedAt
+	merged.CheckedAt = stored.CheckedAt
+	merged.Status, merged.StatusChangedAt = stored.Status, stored.StatusChangedAt
+	if merged.PostedAt == nil {
+		merged.PostedAt, merged.PostedZone = stored.PostedAt, stored.PostedZone
+	}
+	if merged.Coordinates == nil {
+		merged.Coordinates = stored.Coordinates
+	}
+	if merged.CompanyWebsite == "" {
+		merged.CompanyWebsite = stored.CompanyWebsite
+	}
+	if merged.CompanyLogo == "" {
+		merged.CompanyLogo = stored.CompanyLogo
+	}
+	if merged.EmploymentType == "" {
+		merged.EmploymentType = stored.EmploymentType
+	}
+	if merged.RemoteType == "" {
+		merged.RemoteType = stored.RemoteType
+	}
+	if merged.Summary == nil {
+		merged.Summary = stored.Summary
+	}
+	if merged.Enrichment == nil {
+		merged.Enrichment = stored.Enrichment
+	}
+	if merged.Match == nil {
+		merged.Match = stored.Match
+	}
+	if merged.SalaryRange == nil {
+		merged.SalaryRange = stored.SalaryRange
+	}
+	// A rule-based rescrape does not replace the LLM's labels
+	if merged.Classification == nil || (stored.Classification != nil &&
+		stored.Classification.Source == models.ClassificationSourceLLM &&
+		merged.Classification.Source != models.ClassificationSourceLLM) {
+		merged.Classification = stored.Classification
+	}
+	if merged.Description == "" {
+		merged.Description = stored.Description
+	}
+	if merged.Salary == "" {
+		merged.Salary = stored.Salary
+	}
+	if merged.Location == "" {
+		merged.Location = stored.Location
+	}
+	if merged.UpdatedAt.IsZero() {
+		merged.UpdatedAt = time.Now().UTC()
+	}
+	return merged
+}
+
+// MarkNotified stamps each job's NotifiedAt for channel. The map is
+// replaced rather than modified since copies returned earlier share it.
+func (fs *FileStorage) MarkNotified(ctx context.Context, ids []string, channel string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	mark := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		mark[id] = true
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	for i := range fs.jobs {
+		if !mark[fs.jobs[i].ID] {
+			continue
+		}
+		notified := make(map[string]time.Time, len(fs.jobs[i].NotifiedAt)+1)
+		for name, sent := range fs.jobs[i].NotifiedAt {
+			notified[name] = sent
+		}
+		notified[channel] = at.UTC()
+		fs.jobs[i].NotifiedAt = notified
+	}
+	return fs.save()
+}
+
+// MarkChecked stamps each job's CheckedAt and, for those in expired, its
+// ExpiredAt, marking them inactive
+func (fs *FileStorage) MarkChecked(ctx context.Context, ids, expired []string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	checked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		checked[id] = true
+	}
+	gone := make(map[string]bool, len(expired))
+	for _, id := range expired {
+		gone[id] = true
+	}
+
+	at = at.UTC()
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	for i := range fs.jobs {
+		job := &fs.jobs[i]
+		if !checked[job.ID] {
+			continue
+		}
+		checkedAt := at
+		job.CheckedAt = &checkedAt
+		if gone[job.ID] && job.ExpiredAt == nil {
+			expiredAt := at
+			job.ExpiredAt = &expiredAt
+			job.IsActive = false
+		}
+	}
+	return fs.save()
+}
+
+// UpdateStatus sets a job's lifecycle state, noting when it changed
+func (fs *FileStorage) UpdateStatus(ctx context.Context, id, status string) (*models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := models.ValidateJobStatus(status); err != nil {
+		return nil, err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	for i := range fs.jobs {
+		job := &fs.jobs[i]
+		if job.ID != id {
+			continue
+		}
+		if job.CurrentStatus() != status {
+			now := time.Now().UTC()
+			job.Status = status
+			job.StatusChangedAt = &now
+		}
+		updated := *job
+		return &updated, fs.save()
+	}
+	return nil, fmt.Errorf("job %s %w", id, errs.ErrNotFound)
+}
+
+func (fs *FileStorage) Search(ctx context.Context, filter models.JobFilter) (*models.JobSearchResult, error) {
+	// A full-text query picks the candidates from the index and scores
+	// them; the other criteria are then checked job by job
+	query := models.ParseQuery(filter.Query)
+	filter.Query = ""
+	// A semantic query ranks the jobs matching the other criteria by
+	// meaning, or without a ranker by its keywords, which are looked up
+	// either way in case the ranker fails
+	semantic := filter.Semantic
+	filter.Semantic = ""
+	// A boolean keyword query is parsed once rather than for every job
+	if err := filter.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid keyword query: %w", err)
+	}
+
+	fs.mutex.RLock()
+	// scores are by position and ranks by ID, as sorting moves the jobs
+	var scores map[int]float64
+	var ranks map[string]float64
+	if len(query) > 0 {
+		scores = fs.textIndex().search(query)
+		ranks = make(map[string]float64, len(scores))
+	}
+	var keywordScores map[int]float64
+	var keywordRanks map[string]float64
+	if semantic != "" {
+		keywordScores = fs.textIndex().searchAny(models.KeywordTerms(semantic))
+		keywordRanks = make(map[string]float64, len(keywordScores))
+	}
+	ranker := fs.ranker
+	var matched []models.Job
+	for i, job := range fs.jobs {
+		// Large stores are scanned in steps, giving up once ctx is done
+		if i%searchCheckInterval == 0 && ctx.Err() != nil {
+			fs.mutex.RUnlock()
+			return nil, ctx.Err()
+		}
+		score, ok := scores[i]
+		if scores != nil && !ok {
+			continue
+		}
+		if filter.Matches(&job) {
+			matched = append(matched, job)
+			if scores != nil {
+				ranks[job.ID] = score
+			}
+			if score, ok := keywordScores[i]; ok {
+				keywordRanks[job.ID] = score
+			}
+		}
+	}
+	fs.mutex.RUnlock()
+
+	if semantic != "" {
+		textRanks := ranks
+		ranks = nil
+		if ranker != nil {
+			similarities, err := ranker.Similarities(ctx, semantic, matched)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err == nil {
+				ranks = similarities
+			}
+		}
+		if ranks == nil {
+			ranks = keywordRanks
+		}
+		matched = ranked(matched, ranks)
+		// A full-text query given as well ranks the jobs too
+		if textRanks != nil {
+			ranks = fuseRanks(ranks, textRanks)
+		}
+	}
+
+	switch strings.ToLower(filter.SortBy) {
+	case "", "relevance":
+		if ranks != nil {
+			sortByScore(matched, ranks, filter.SortOrder)
+			break
+		}
+		fallthrough
+	default:
+		sortJobs(matched, filter.SortBy, filter.SortOrder)
+	}
+
+	result := paginate(matched, filter.Limit, filter.Offset)
+	result.Facets = facets(matched)
+	return result, nil
+}
+
+// ranked keeps the jobs that have a rank
+func ranked(jobs []models.Job, ranks map[string]float64) []models.Job {
+	kept := jobs[:0]
+	for i := range jobs {
+		if _, ok := ranks[jobs[i].ID]; ok {
+			kept = append(kept, jobs[i])
+		}
+	}
+	return kept
+}
+
+// textIndex returns the full-text index of the stored jobs, building it if
+// a write dropped it. The caller holds mutex for reading.
+func (fs *FileStorage) textIndex() *textIndex {
+	fs.textMutex.Lock()
+	defer fs.textMutex.Unlock()
+	if fs.text == nil {
+		fs.text = newTextIndex(fs.jobs)
+	}
+	return fs.text
+}
+
+// facets counts jobs by classification
+func facets(jobs []models.Job) map[string]map[string]int {
+	counts := map[string]map[string]int{
+		"category":  {},
+		"seniority": {},
+		"industry":  {},
+	}
+	for _, job := range jobs {
+		if c := job.Classification; c != nil {
+			counts["category"][c.Category]++
+			counts["seniority"][c.Seniority]++
+			counts["industry"][c.Industry]++
+		}
+	}
+	return counts
+}
+
+func (fs *FileStorage) GetByID(ctx context.Context, id string) (*models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	for i := range fs.jobs {
+		if fs.jobs[i].ID == id {
+			job := fs.jobs[i]
+			return &job, nil
+		}
+	}
+
+	return nil, fmt.Errorf("job %s %w", id, errs.ErrNotFound)
+}
+
+func (fs *FileStorage) GetAll(ctx context.Context) ([]models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	jobs := make([]models.Job, len(fs.jobs))
+	copy(jobs, fs.jobs)
+	return jobs, nil
+}
+
+func (fs *FileStorage) Delete(ctx context.Context, ids []string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	kept := fs.jobs[:0]
+	for _, job := range fs.jobs {
+		if remove[job.ID] {
+			fs.counters.remove(&job)
+			continue
+		}
+		kept = append(kept, job)
+	}
+
+	removed := len(fs.jobs) - len(kept)
+	fs.jobs = kept
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, fs.save()
+}
+
+// GetCompanies groups the stored jobs by company
+func (fs *FileStorage) GetCompanies(ctx context.Context) ([]models.Company, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	return models.GroupCompanies(fs.jobs), nil
+}
+
+// GetStats reports the counters kept up to date by every write rather than
+// scanning the jobs
+func (fs *FileStorage) GetStats(ctx context.Context) (*models.JobStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Reading the counters drops expired recent jobs, so it takes the
+	// write lock
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	return fs.counters.stats(fs.jobs), nil
+}
+
+// Ping checks that the data directory is still present and accessible
+func (fs *FileStorage) Ping() error {
+	info, err := os.Stat(fs.dataDir)
+	if err != nil {
+		return fmt.Errorf("data directory unavailable: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("data directory %s is not a directory", fs.dataDir)
+	}
+	return nil
+}
+
+// Size returns the size of jobs.json in bytes
+func (fs *FileStorage) Size() (int64, error) {
+	info, err := os.Stat(fs.filePath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (fs *FileStorage) Close() error {
+	return nil
+}
+
+// sortJobs orders jobs by the requested field. Relevance, date and posted
+// sort descending by default, title and company ascending.
+func sortJobs(jobs []models.Job, sortBy, order string) {
+	var less func(a, b *models.Job) bool
+	descending := true
+
+	switch strings.ToLower(sortBy) {
+	case "date":
+		less = func(a, b *models.Job) bool { return a.ScrapedAt.Before(b.ScrapedAt) }
+	case "posted":
+		less = func(a, b *models.Job) bool { return a.PostedTime().Before(b.PostedTime()) }
+	case "match":
+		less = func(a, b *models.Job) bool {
+			if matchScore(a) != matchScore(b) {
+				return matchScore(a) < matchScore(b)
+			}
+			return a.Relevance < b.Relevance
+		}
+	case "title":
+		less = func(a, b *models.Job) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+		descending = false
+	case "company":
+		less = func(a, b *models.Job) bool { return strings.ToLower(a.Company) < strings.ToLower(b.Company) }
+		descending = false
+	default:
+		less = func(a, b *models.Job) bool {
+			if a.Relevance != b.Relevance {
+				return a.Relevance < b.Relevance
+			}
+			return a.ScrapedAt.Before(b.ScrapedAt)
+		}
+	}
+
+	switch strings.ToLower(order) {
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if descending {
+			return less(&jobs[j], &jobs[i])
+		}
+		return less(&jobs[i], &jobs[j])
+	})
+}
+
+// matchScore is the job's resume match score, 0 when it was never matched
+func matchScore(job *models.Job) float64 {
+	if job.Match == nil {
+		return 0
+	}
+	return job.Match.Score
+}
+
+func paginate(jobs []models.Job, limit, offset int) *models.JobSearchResult {
+	total := len(jobs)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	result := &models.JobSearchResult{
+		Jobs:    jobs[offset:end],
+		Total:   total,
+		Page:    1,
+		PerPage: limit,
+	}
+
+	if limit > 0 {
+		result.Page = offset/limit + 1
+		result.TotalPages = (total + limit - 1) / limit
+	} else if total > 0 {
+		result.PerPage = total
+		result.TotalPages = 1
+	}
+
+	return result
+}
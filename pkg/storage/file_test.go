@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"hire.ai/pkg/models"
+)
+
+// storedJobs makes n distinct postings numbered from start, with the fields
+// matching and searching read
+func storedJobs(start, n int) []models.Job {
+	titles := []string{"Senior Go Developer", "Backend Engineer", "DevOps Engineer", "Frontend Developer", "Data Engineer"}
+	skills := []string{"golang", "kubernetes", "terraform", "react", "python", "postgres", "aws"}
+	jobs := make([]models.Job, n)
+	for i := range jobs {
+		k := start + i
+		jobs[i] = models.Job{
+			ID:          fmt.Sprintf("job-%d", k),
+			Title:       fmt.Sprintf("%s %d", titles[k%len(titles)], k),
+			Company:     fmt.Sprintf("Company %d", k%500),
+			Location:    "Berlin, Germany",
+			Link:        fmt.Sprintf("https://jobs.example.com/postings/%d", k),
+			Description: fmt.Sprintf("We build services in %s and run them on %s.", skills[k%len(skills)], skills[(k+1)%len(skills)]),
+		}
+	}
+	return jobs
+}
+
+// BenchmarkJobMatch times finding and merging the stored copies of jobs
+// found again on another board, under IDs of their own, in a store of
+// 20,000 jobs
+func BenchmarkJobMatch(b *testing.B) {
+	stored := storedJobs(0, 20000)
+	matcher := newJobMatcher(stored)
+	again := storedJobs(10000, 500)
+	for i := range again {
+		again[i].ID = fmt.Sprintf("other-%d", i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		job := &again[i%len(again)]
+		k, ok := matcher.find(stored, job)
+		if !ok {
+			b.Fatal("stored copy not found")
+		}
+		stored[k] = mergeJob(stored[k], *job)
+	}
+}
+
+// BenchmarkJobMatcherIndex times indexing a store of 20,000 jobs, which
+// Upsert does once a batch that may hold stored jobs
+func BenchmarkJobMatcherIndex(b *testing.B) {
+	stored := storedJobs(0, 20000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newJobMatcher(stored)
+	}
+}
+
+// BenchmarkSave times rewriting jobs.json for a store of 20,000 jobs, which
+// every Upsert does after matching
+func BenchmarkSave(b *testing.B) {
+	fs := benchStorage(b, 20000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := fs.save(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchStorage opens a storage holding n jobs
+func benchStorage(b *testing.B, n int) *FileStorage {
+	fs, err := NewFileStorage(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := fs.Upsert(context.Background(), storedJobs(0, n)); err != nil {
+		b.Fatal(err)
+	}
+	return fs
+}
+
+func BenchmarkSearch(b *testing.B) {
+	fs := benchStorage(b, 20000)
+	filter := models.JobFilter{Keywords: []string{"golang"}, Location: "berlin", Limit: 50}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fs.Search(context.Background(), filter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchQuery(b *testing.B) {
+	fs := benchStorage(b, 20000)
+	filter := models.JobFilter{Query: `"backend engineer" kubernetes`, Limit: 50}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fs.Search(context.Background(), filter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
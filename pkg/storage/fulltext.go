@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"hire.ai/pkg/models"
+)
+
+// BM25 parameters: k1 limits how much repeating a word raises a job's
+// score, b how much long fields are discounted
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fieldWeights rank a match in the title above one in the description
+var fieldWeights = [models.TextFieldCount]float64{
+	models.FieldTitle:       3,
+	models.FieldCompany:     2,
+	models.FieldDescription: 1,
+	models.FieldKeywords:    2,
+}
+
+// fieldKey is one text field of one job, by the job's position
+type fieldKey struct {
+	doc   int
+	field int
+}
+
+// textIndex is an inverted index of the stored jobs' text: for each word,
+// the positions it appears at in each field of each job
+type textIndex struct {
+	postings map[string]map[fieldKey][]int
+	// terms are the indexed words in order, for prefix lookups
+	terms   []string
+	lengths [][models.TextFieldCount]int
+	average [models.TextFieldCount]float64
+}
+
+// newTextIndex indexes jobs
+func newTextIndex(jobs []models.Job) *textIndex {
+	index := &textIndex{
+		postings: make(map[string]map[fieldKey][]int),
+		lengths:  make([][models.TextFieldCount]int, len(jobs)),
+	}
+	for doc := range jobs {
+		for field, text := range models.TextFields(&jobs[doc]) {
+			words := models.Tokenize(text)
+			index.lengths[doc][field] = len(words)
+			index.average[field] += float64(len(words))
+			for position, word := range words {
+				postings, ok := index.postings[word]
+				if !ok {
+					postings = make(map[fieldKey][]int)
+					index.postings[word] = postings
+					index.terms = append(index.terms, word)
+				}
+				key := fieldKey{doc, field}
+				postings[key] = append(postings[key], position)
+			}
+		}
+	}
+	sort.Strings(index.terms)
+	if len(jobs) > 0 {
+		for field := range index.average {
+			index.average[field] /= float64(len(jobs))
+		}
+	}
+	return index
+}
+
+// search returns the positions of the jobs matching query with their
+// BM25F scores. A query of only excluded terms matches every other job
+// with a score of 0.
+func (index *textIndex) search(query models.TextQuery) map[int]float64 {
+	var scores map[int]float64
+	excluded := make(map[int]bool)
+	for _, term := range query {
+		frequencies := index.frequencies(term)
+		if term.Exclude {
+			for doc := range frequencies {
+				excluded[doc] = true
+			}
+			continue
+		}
+
+		idf := index.idf(len(frequencies))
+		if scores == nil {
+			scores = make(map[int]float64, len(frequencies))
+			for doc, counts := range frequencies {
+				scores[doc] = idf * index.saturate(doc, counts)
+			}
+			continue
+		}
+		// Every term must match, so jobs missing this one drop out
+		for doc, score := range scores {
+			counts, ok := frequencies[doc]
+			if !ok {
+				delete(scores, doc)
+				continue
+			}
+			scores[doc] = score + idf*index.saturate(doc, counts)
+		}
+	}
+
+	if scores == nil {
+		scores = make(map[int]float64, len(index.lengths))
+		for doc := range index.lengths {
+			scores[doc] = 0
+		}
+	}
+	for doc := range excluded {
+		delete(scores, doc)
+	}
+	return scores
+}
+
+// searchAny returns the positions of the jobs matching any of the query's
+// terms with the sum of their BM25F scores. Excluded terms are ignored.
+func (index *textIndex) searchAny(query models.TextQuery) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, term := range query {
+		if term.Exclude {
+			continue
+		}
+		frequencies := index.frequencies(term)
+		idf := index.idf(len(frequencies))
+		for doc, counts := range frequencies {
+			scores[doc] += idf * index.saturate(doc, counts)
+		}
+	}
+	return scores
+}
+
+// frequencies counts how often term occurs in each field of the jobs it
+// occurs in
+func (index *textIndex) frequencies(term models.QueryTerm) map[int][models.TextFieldCount]int {
+	// Each word's positions by field; the last word of a prefix term
+	// gathers those of every word it begins
+	occurrences := make([]map[fieldKey][]int, len(term.Words))
+	for i, word := range term.Words {
+		if term.Prefix && i == len(term.Words)-1 {
+			occurrences[i] = index.withPrefix(word)
+		} else {
+			occurrences[i] = index.postings[word]
+		}
+		if len(occurrences[i]) == 0 {
+			return nil
+		}
+	}
+
+	// The other words of a phrase must follow the first in the same field
+	rest := make([]map[fieldKey]map[int]bool, len(term.Words))
+	for i := 1; i < len(term.Words); i++ {
+		rest[i] = make(map[fieldKey]map[int]bool, len(occurrences[i]))
+		for key, positions := range occurrences[i] {
+			set := make(map[int]bool, len(positions))
+			for _, position := range positions {
+				set[position] = true
+			}
+			rest[i][key] = set
+		}
+	}
+
+	frequencies := make(map[int][models.TextFieldCount]int)
+	for key, positions := range occurrences[0] {
+		count := 0
+		for _, position := range positions {
+			found := true
+			for i := 1; i < len(term.Words); i++ {
+				if !rest[i][key][position+i] {
+					found = false
+					break
+				}
+			}
+			if found {
+				count++
+			}
+		}
+		if count > 0 {
+			counts := frequencies[key.doc]
+			counts[key.field] += count
+			frequencies[key.doc] = counts
+		}
+	}
+	return frequencies
+}
+
+// withPrefix merges the postings of every indexed word starting with
+// prefix
+func (index *textIndex) withPrefix(prefix string) map[fieldKey][]int {
+	merged := make(map[fieldKey][]int)
+	for i := sort.SearchStrings(index.terms, prefix); i < len(index.terms) && strings.HasPrefix(index.terms[i], prefix); i++ {
+		for key, positions := range index.postings[index.terms[i]] {
+			merged[key] = append(merged[key], positions...)
+		}
+	}
+	return merged
+}
+
+// idf weighs a term by how few of the jobs it matches
+func (index *textIndex) idf(matching int) float64 {
+	total := float64(len(index.lengths))
+	n := float64(matching)
+	return math.Log(1 + (total-n+0.5)/(n+0.5))
+}
+
+// saturate combines a term's counts in a job's fields, weighted by field
+// and discounted for long fields, into its BM25 term frequency
+func (index *textIndex) saturate(doc int, counts [models.TextFieldCount]int) float64 {
+	weighted := 0.0
+	for field, count := range counts {
+		if count == 0 {
+			continue
+		}
+		norm := 1.0
+		if index.average[field] > 0 {
+			norm = 1 - bm25B + bm25B*float64(index.lengths[doc][field])/index.average[field]
+		}
+		weighted += fieldWeights[field] * float64(count) / norm
+	}
+	return weighted * (bm25K1 + 1) / (weighted + bm25K1)
+}
+
+// sortByScore orders jobs by their text scores, highest first unless order
+// is asc, and jobs scoring the same by relevance and then recency
+func sortByScore(jobs []models.Job, scores map[string]float64, order string) {
+	descending := !strings.EqualFold(order, "asc")
+	less := func(a, b *models.Job) bool {
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] < scores[b.ID]
+		}
+		if a.Relevance != b.Relevance {
+			return a.Relevance < b.Relevance
+		}
+		return a.ScrapedAt.Before(b.ScrapedAt)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if descending {
+			return less(&jobs[j], &jobs[i])
+		}
+		return less(&jobs[i], &jobs[j])
+	})
+}
+
+// fuseRanks merges rankings by reciprocal rank, which needs no common scale
+// between full-text scores and similarities
+func fuseRanks(rankings ...map[string]float64) map[string]float64 {
+	const k = 60
+	fused := make(map[string]float64)
+	for _, ranking := range rankings {
+		ids := make([]string, 0, len(ranking))
+		for id := range ranking {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if ranking[ids[i]] != ranking[ids[j]] {
+				return ranking[ids[i]] > ranking[ids[j]]
+			}
+			return ids[i] < ids[j]
+		})
+		for rank, id := range ids {
+			fused[id] += 1 / float64(k+rank+1)
+		}
+	}
+	return fused
+}
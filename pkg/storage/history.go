@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+const runHistoryFileName = "run_history.json"
+
+// maxRunHistory caps the reports kept; the oldest are dropped first
+const maxRunHistory = 1000
+
+// RunHistory keeps the reports of finished scrape runs, oldest first, as a
+// JSON array in the data directory
+type RunHistory struct {
+	filePath string
+	runs     []models.RunReport
+	mutex    sync.RWMutex
+}
+
+// NewRunHistory creates a new run history in the specified data directory
+func NewRunHistory(dataDir string) (*RunHistory, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	history := &RunHistory{filePath: filepath.Join(dataDir, runHistoryFileName)}
+
+	data, err := os.ReadFile(history.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", history.filePath, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &history.runs); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", history.filePath, err)
+		}
+	}
+
+	return history, nil
+}
+
+// Add records a finished run
+func (h *RunHistory) Add(report models.RunReport) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.runs = append(h.runs, report)
+	if len(h.runs) > maxRunHistory {
+		h.runs = append([]models.RunReport(nil), h.runs[len(h.runs)-maxRunHistory:]...)
+	}
+	return h.save()
+}
+
+// List returns the latest runs, newest first; a limit of zero or less
+// returns them all
+func (h *RunHistory) List(limit int) []models.RunReport {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if limit <= 0 || limit > len(h.runs) {
+		limit = len(h.runs)
+	}
+	runs := make([]models.RunReport, 0, limit)
+	for i := len(h.runs) - 1; i >= len(h.runs)-limit; i-- {
+		runs = append(runs, h.runs[i])
+	}
+	return runs
+}
+
+// Get returns the run with the given ID
+func (h *RunHistory) Get(id string) (*models.RunReport, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for i := range h.runs {
+		if h.runs[i].ID == id {
+			run := h.runs[i]
+			return &run, nil
+		}
+	}
+	return nil, fmt.Errorf("run %s %w", id, errs.ErrNotFound)
+}
+
+func (h *RunHistory) save() error {
+	data, err := json.MarshalIndent(h.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run history: %w", err)
+	}
+
+	tmpPath := h.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run history: %w", err)
+	}
+	return os.Rename(tmpPath, h.filePath)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import "hire.ai/pkg/models"
+
+// jobMatcher finds the stored copy of a scraped job, as Job.IsDuplicate
+// would but without comparing every pair: by ID, by title and company in a
+// matching location, or by normalized link with a similar title
+type jobMatcher struct {
+	byID      map[string]int
+	byPosting map[string][]int
+	byLink    map[string][]int
+}
+
+// newJobMatcher indexes jobs by their positions
+func newJobMatcher(jobs []models.Job) *jobMatcher {
+	m := &jobMatcher{
+		byID:      make(map[string]int, len(jobs)),
+		byPosting: make(map[string][]int, len(jobs)),
+		byLink:    make(map[string][]int, len(jobs)),
+	}
+	for i := range jobs {
+		m.add(jobs, i)
+	}
+	return m
+}
+
+// add indexes jobs[i]
+func (m *jobMatcher) add(jobs []models.Job, i int) {
+	job := &jobs[i]
+	m.byID[job.ID] = i
+	posting := models.PostingKey(job)
+	m.byPosting[posting] = append(m.byPosting[posting], i)
+	if link := models.LinkKey(job.Link); link != "" {
+		m.byLink[link] = append(m.byLink[link], i)
+	}
+}
+
+// find returns the position in jobs of job's stored copy
+func (m *jobMatcher) find(jobs []models.Job, job *models.Job) (int, bool) {
+	if i, ok := m.byID[job.ID]; ok {
+		return i, true
+	}
+	for _, i := range m.byPosting[models.PostingKey(job)] {
+		if models.LocationsMatch(jobs[i].Location, job.Location) {
+			return i, true
+		}
+	}
+	if link := models.LinkKey(job.Link); link != "" {
+		for _, i := range m.byLink[link] {
+			if models.TitlesSimilar(jobs[i].Title, job.Title) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hire.ai/pkg/models"
+)
+
+const scrapeRunsFileName = "scrape_runs.json"
+
+// RunStore persists the API server's scrape queue and run history so queued
+// work survives a restart. Callers own the runs and save them as a whole.
+type RunStore struct {
+	filePath string
+}
+
+// NewRunStore creates a new run store in the specified data directory
+func NewRunStore(dataDir string) (*RunStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return &RunStore{filePath: filepath.Join(dataDir, scrapeRunsFileName)}, nil
+}
+
+// Load returns the saved runs, oldest first
+func (s *RunStore) Load() ([]models.ScrapeRun, error) {
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.filePath, err)
+	}
+
+	var runs []models.ScrapeRun
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &runs); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", s.filePath, err)
+		}
+	}
+	return runs, nil
+}
+
+// Save replaces the saved runs
+func (s *RunStore) Save(runs []models.ScrapeRun) error {
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scrape runs: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write scrape runs: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+const savedSearchesFileName = "saved_searches.json"
+
+// SavedSearchStore keeps named searches as a JSON array in the data directory
+type SavedSearchStore struct {
+	filePath string
+	searches []models.SavedSearch
+	mutex    sync.RWMutex
+}
+
+// NewSavedSearchStore creates a new saved search store in the specified data directory
+func NewSavedSearchStore(dataDir string) (*SavedSearchStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &SavedSearchStore{filePath: filepath.Join(dataDir, savedSearchesFileName)}
+
+	data, err := os.ReadFile(store.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", store.filePath, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.searches); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", store.filePath, err)
+		}
+	}
+
+	return store, nil
+}
+
+// List returns the user's saved searches sorted by name; an empty userID
+// selects the shared default profile
+func (s *SavedSearchStore) List(userID string) []models.SavedSearch {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	searches := make([]models.SavedSearch, 0)
+	for _, search := range s.searches {
+		if search.UserID == userID {
+			searches = append(searches, search)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	return searches
+}
+
+// All returns every user's saved searches, sorted by user and name
+func (s *SavedSearchStore) All() []models.SavedSearch {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	searches := append([]models.SavedSearch(nil), s.searches...)
+	sort.Slice(searches, func(i, j int) bool {
+		if searches[i].UserID != searches[j].UserID {
+			return searches[i].UserID < searches[j].UserID
+		}
+		return searches[i].Name < searches[j].Name
+	})
+	return searches
+}
+
+// Get returns the user's saved search with the given ID
+func (s *SavedSearchStore) Get(userID, id string) (*models.SavedSearch, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.searches {
+		if s.searches[i].UserID == userID && s.searches[i].ID == id {
+			search := s.searches[i]
+			return &search, nil
+		}
+	}
+	return nil, fmt.Errorf("saved search %s %w", id, errs.ErrNotFound)
+}
+
+// Create adds a new saved search, rejecting names the same user already took
+func (s *SavedSearchStore) Create(search *models.SavedSearch) error {
+	if search.ID == "" {
+		return fmt.Errorf("saved search name must contain letters or digits")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, existing := range s.searches {
+		if existing.UserID == search.UserID && existing.ID == search.ID {
+			return fmt.Errorf("saved search %s already exists", search.ID)
+		}
+	}
+
+	s.searches = append(s.searches, *search)
+	return s.save()
+}
+
+// Update replaces an existing saved search
+func (s *SavedSearchStore) Update(search *models.SavedSearch) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.searches {
+		if s.searches[i].UserID == search.UserID && s.searches[i].ID == search.ID {
+			search.UpdatedAt = time.Now()
+			s.searches[i] = *search
+			return s.save()
+		}
+	}
+	return fmt.Errorf("saved search %s %w", search.ID, errs.ErrNotFound)
+}
+
+// Delete removes the user's saved search and reports whether it existed
+func (s *SavedSearchStore) Delete(userID, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.searches {
+		if s.searches[i].UserID == userID && s.searches[i].ID == id {
+			s.searches = append(s.searches[:i], s.searches[i+1:]...)
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+// DeleteUser removes every saved search owned by the user and returns how
+// many were removed
+func (s *SavedSearchStore) DeleteUser(userID string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.searches[:0]
+	for _, search := range s.searches {
+		if search.UserID != userID {
+			kept = append(kept, search)
+		}
+	}
+	removed := len(s.searches) - len(kept)
+	s.searches = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+func (s *SavedSearchStore) save() error {
+	data, err := json.MarshalIndent(s.searches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved searches: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
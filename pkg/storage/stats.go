@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// recentWindow is how far back JobStats.RecentJobs counts
+const recentWindow = 24 * time.Hour
+
+// dayFormat keys JobStats.JobsByDay by the date jobs were first scraped in
+// the display timezone
+const dayFormat = "2006-01-02"
+
+// jobCounters keeps the figures of GetStats up to date as jobs are stored,
+// merged and deleted, so reading stats never scans the jobs
+type jobCounters struct {
+	total       int
+	bySource    map[string]int
+	byLocation  map[string]int
+	byCompany   map[string]int
+	byDay       map[string]int
+	keywords    map[string]int
+	byCategory  map[string]int
+	bySeniority map[string]int
+	byIndustry  map[string]int
+	// recent holds, in order, the scrape times of jobs that were inside the
+	// recent window when last looked at
+	recent []time.Time
+	// lastScraped is exact unless lastStale, set when the job it came from
+	// was removed
+	lastScraped time.Time
+	lastStale   bool
+}
+
+func newJobCounters(jobs []models.Job) *jobCounters {
+	c := &jobCounters{
+		bySource:    make(map[string]int),
+		byLocation:  make(map[string]int),
+		byCompany:   make(map[string]int),
+		byDay:       make(map[string]int),
+		keywords:    make(map[string]int),
+		byCategory:  make(map[string]int),
+		bySeniority: make(map[string]int),
+		byIndustry:  make(map[string]int),
+	}
+	for i := range jobs {
+		c.add(&jobs[i])
+	}
+	return c
+}
+
+// add counts a job going into storage
+func (c *jobCounters) add(job *models.Job) {
+	c.count(job, 1)
+	if job.ScrapedAt.After(time.Now().Add(-recentWindow)) {
+		i := sort.Search(len(c.recent), func(i int) bool { return !c.recent[i].Before(job.ScrapedAt) })
+		c.recent = append(c.recent, time.Time{})
+		copy(c.recent[i+1:], c.recent[i:])
+		c.recent[i] = job.ScrapedAt
+	}
+	if job.ScrapedAt.After(c.lastScraped) {
+		c.lastScraped = job.ScrapedAt
+	}
+}
+
+// remove uncounts a job leaving storage, or the stored copy of a job about
+// to be replaced by a merged one
+func (c *jobCounters) remove(job *models.Job) {
+	c.count(job, -1)
+	i := sort.Search(len(c.recent), func(i int) bool { return !c.recent[i].Before(job.ScrapedAt) })
+	if i < len(c.recent) && c.recent[i].Equal(job.ScrapedAt) {
+		c.recent = append(c.recent[:i], c.recent[i+1:]...)
+	}
+	if job.ScrapedAt.Equal(c.lastScraped) {
+		c.lastStale = true
+	}
+}
+
+func (c *jobCounters) count(job *models.Job, delta int) {
+	c.total += delta
+	adjust(c.bySource, job.Source, delta)
+	if job.Location != "" {
+		adjust(c.byLocation, job.Location, delta)
+	}
+	if company := models.CompanyName(job.Company); company != "" {
+		adjust(c.byCompany, company, delta)
+	}
+	adjust(c.byDay, models.InDisplayZone(job.ScrapedAt).Format(dayFormat), delta)
+	for _, keyword := range job.Keywords {
+		adjust(c.keywords, strings.ToLower(keyword), delta)
+	}
+	if cl := job.Classification; cl != nil {
+		adjust(c.byCategory, cl.Category, delta)
+		adjust(c.bySeniority, cl.Seniority, delta)
+		adjust(c.byIndustry, cl.Industry, delta)
+	}
+}
+
+// adjust changes a count, dropping it at zero so removed sources and
+// locations stop being reported
+func adjust(counts map[string]int, key string, delta int) {
+	if counts[key]+delta == 0 {
+		delete(counts, key)
+		return
+	}
+	counts[key] += delta
+}
+
+// stats reports the counters. jobs is only scanned when the latest scrape
+// time has to be found again after its job was removed.
+func (c *jobCounters) stats(jobs []models.Job) *models.JobStats {
+	cutoff := time.Now().Add(-recentWindow)
+	expired := sort.Search(len(c.recent), func(i int) bool { return c.recent[i].After(cutoff) })
+	c.recent = c.recent[expired:]
+
+	if c.lastStale {
+		c.lastScraped = time.Time{}
+		for i := range jobs {
+			if jobs[i].ScrapedAt.After(c.lastScraped) {
+				c.lastScraped = jobs[i].ScrapedAt
+			}
+		}
+		c.lastStale = false
+	}
+
+	return &models.JobStats{
+		TotalJobs:       c.total,
+		JobsBySource:    copyCounts(c.bySource),
+		JobsByLocation:  copyCounts(c.byLocation),
+		JobsByCompany:   copyCounts(c.byCompany),
+		JobsByDay:       copyCounts(c.byDay),
+		RecentJobs:      len(c.recent),
+		LastScraped:     c.lastScraped,
+		Keywords:        copyCounts(c.keywords),
+		JobsByCategory:  copyCounts(c.byCategory),
+		JobsBySeniority: copyCounts(c.bySeniority),
+		JobsByIndustry:  copyCounts(c.byIndustry),
+	}
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// RebuildStats recounts the stats from every stored job, replacing the
+// maintained counters, and reports whether they had drifted from the
+// recount
+func (fs *FileStorage) RebuildStats() (drifted bool) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	rebuilt := newJobCounters(fs.jobs)
+	drifted = !reflect.DeepEqual(fs.counters.stats(fs.jobs), rebuilt.stats(fs.jobs))
+	fs.counters = rebuilt
+	return drifted
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"hire.ai/pkg/models"
+)
+
+// Storage defines the persistence operations used by the scraper and CLI.
+// Operations give up with ctx's error once it is done; a write already
+// under way completes so storage is never left half-written.
+type Storage interface {
+	// Store persists a batch of scraped jobs, merging those already stored
+	// into their stored copies as Upsert does
+	Store(ctx context.Context, jobs []models.Job) error
+
+	// Upsert stores jobs not seen before and refreshes the stored copy of
+	// the rest, matching as Job.IsDuplicate does: by ID, by title and
+	// company in a matching location, or by normalized link with a similar
+	// title, so a posting found again, or on another source, is not stored
+	// twice. It returns the jobs that were new.
+	Upsert(ctx context.Context, jobs []models.Job) ([]models.Job, error)
+
+	// MarkNotified records that the jobs with the given IDs were sent to a
+	// notification channel
+	MarkNotified(ctx context.Context, ids []string, channel string, at time.Time) error
+
+	// MarkChecked records that the links of the jobs with the given IDs
+	// were revisited at at, and expires those in expired
+	MarkChecked(ctx context.Context, ids, expired []string, at time.Time) error
+
+	// UpdateStatus sets the lifecycle state of the job with the given ID
+	// and returns the updated job
+	UpdateStatus(ctx context.Context, id, status string) (*models.Job, error)
+
+	// Search returns jobs matching the filter, sorted by relevance
+	Search(ctx context.Context, filter models.JobFilter) (*models.JobSearchResult, error)
+
+	// GetByID returns a single job by its ID
+	GetByID(ctx context.Context, id string) (*models.Job, error)
+
+	// GetAll returns every stored job
+	GetAll(ctx context.Context) ([]models.Job, error)
+
+	// Delete removes the jobs with the given IDs and returns how many were removed
+	Delete(ctx context.Context, ids []string) (int, error)
+
+	// GetCompanies returns the companies of the stored jobs, a company's
+	// aliases counted together under the name it is shown by, largest
+	// employers first
+	GetCompanies(ctx context.Context) ([]models.Company, error)
+
+	// GetStats returns aggregate statistics over the stored jobs
+	GetStats(ctx context.Context) (*models.JobStats, error)
+
+	// Close releases any resources held by the storage
+	Close() error
+}
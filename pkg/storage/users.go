@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+const usersFileName = "users.json"
+
+// UserStore keeps user accounts as a JSON array in the data directory. Like
+// the token store it rereads the file when it changes, so users added with
+// the CLI can sign in to a running server.
+type UserStore struct {
+	filePath string
+	users    []models.User
+	modTime  time.Time
+	mutex    sync.RWMutex
+}
+
+// NewUserStore creates a new user store in the specified data directory
+func NewUserStore(dataDir string) (*UserStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &UserStore{filePath: filepath.Join(dataDir, usersFileName)}
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// reload rereads users.json if it changed since the last read
+func (s *UserStore) reload() error {
+	info, err := os.Stat(s.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", s.filePath, err)
+	}
+
+	s.mutex.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mutex.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", s.filePath, err)
+	}
+
+	var users []models.User
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &users); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", s.filePath, err)
+		}
+	}
+
+	s.mutex.Lock()
+	s.users, s.modTime = users, info.ModTime()
+	s.mutex.Unlock()
+	return nil
+}
+
+// List returns all users sorted by name
+func (s *UserStore) List() []models.User {
+	if err := s.reload(); err != nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	users := make([]models.User, len(s.users))
+	copy(users, s.users)
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	return users
+}
+
+// Get returns the user with the given ID
+func (s *UserStore) Get(id string) (*models.User, error) {
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.users {
+		if s.users[i].ID == id {
+			user := s.users[i]
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("user %s %w", id, errs.ErrNotFound)
+}
+
+// Create adds a new user, rejecting names that are already taken
+func (s *UserStore) Create(user *models.User) error {
+	if user.ID == "" {
+		return fmt.Errorf("user name must contain letters or digits")
+	}
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, existing := range s.users {
+		if existing.ID == user.ID {
+			return fmt.Errorf("user %s already exists", user.ID)
+		}
+	}
+
+	s.users = append(s.users, *user)
+	return s.save()
+}
+
+// Update replaces an existing user
+func (s *UserStore) Update(user *models.User) error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.users {
+		if s.users[i].ID == user.ID {
+			user.UpdatedAt = time.Now()
+			s.users[i] = *user
+			return s.save()
+		}
+	}
+	return fmt.Errorf("user %s %w", user.ID, errs.ErrNotFound)
+}
+
+// Delete removes a user and reports whether they existed. Their saved
+// searches, applications and tokens are left to the caller.
+func (s *UserStore) Delete(id string) (bool, error) {
+	if err := s.reload(); err != nil {
+		return false, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.users {
+		if s.users[i].ID == id {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+func (s *UserStore) save() error {
+	data, err := json.MarshalIndent(s.users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode users: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write users: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		return fmt.Errorf("failed to write users: %w", err)
+	}
+
+	if info, err := os.Stat(s.filePath); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+)
+
+const webhooksFileName = "webhooks.json"
+
+// WebhookStore keeps webhook subscriptions as a JSON array in the data
+// directory. The file holds signing secrets, so it is written owner-only.
+type WebhookStore struct {
+	filePath string
+	webhooks []models.Webhook
+	mutex    sync.RWMutex
+}
+
+// NewWebhookStore creates a new webhook store in the specified data directory
+func NewWebhookStore(dataDir string) (*WebhookStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	store := &WebhookStore{filePath: filepath.Join(dataDir, webhooksFileName)}
+
+	data, err := os.ReadFile(store.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", store.filePath, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.webhooks); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", store.filePath, err)
+		}
+	}
+	// An invalid keyword query is left uncompiled; the dispatcher skips
+	// the webhook and says why
+	for i := range store.webhooks {
+		store.webhooks[i].Filter.Compile()
+	}
+
+	return store, nil
+}
+
+// List returns the user's webhooks, oldest first
+func (s *WebhookStore) List(userID string) []models.Webhook {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	webhooks := make([]models.Webhook, 0)
+	for _, webhook := range s.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks
+}
+
+// Enabled returns every enabled webhook across all users
+func (s *WebhookStore) Enabled() []models.Webhook {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var webhooks []models.Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.Enabled {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
+
+// Get returns the user's webhook with the given ID
+func (s *WebhookStore) Get(userID, id string) (*models.Webhook, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.webhooks {
+		if s.webhooks[i].UserID == userID && s.webhooks[i].ID == id {
+			webhook := s.webhooks[i]
+			return &webhook, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook %s %w", id, errs.ErrNotFound)
+}
+
+// Create assigns the webhook an ID and signing secret and stores it. Its
+// filter must compile.
+func (s *WebhookStore) Create(webhook *models.Webhook) error {
+	if err := webhook.Filter.Compile(); err != nil {
+		return fmt.Errorf("invalid keyword query: %w", err)
+	}
+	id, err := randomHex(4)
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook id: %w", err)
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	webhook.ID = id
+	webhook.Secret = "whsec_" + secret
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.webhooks = append(s.webhooks, *webhook)
+	return s.save()
+}
+
+// Delete removes the user's webhook and reports whether it existed
+func (s *WebhookStore) Delete(userID, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.webhooks {
+		if s.webhooks[i].UserID == userID && s.webhooks[i].ID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+// DeleteUser removes every webhook owned by the user and returns how many
+// were removed
+func (s *WebhookStore) DeleteUser(userID string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.webhooks[:0]
+	for _, webhook := range s.webhooks {
+		if webhook.UserID != userID {
+			kept = append(kept, webhook)
+		}
+	}
+	removed := len(s.webhooks) - len(kept)
+	s.webhooks = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+// RecordDelivery stores the outcome of the latest delivery to a webhook.
+// status is the HTTP status received, or 0 if the request never completed.
+func (s *WebhookStore) RecordDelivery(id string, status int, deliveryErr error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.webhooks {
+		if s.webhooks[i].ID == id {
+			now := time.Now()
+			s.webhooks[i].LastDeliveryAt = &now
+			s.webhooks[i].LastStatus = status
+			s.webhooks[i].LastError = ""
+			if deliveryErr != nil {
+				s.webhooks[i].LastError = deliveryErr.Error()
+			}
+			return s.save()
+		}
+	}
+	return nil
+}
+
+func (s *WebhookStore) save() error {
+	data, err := json.MarshalIndent(s.webhooks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode webhooks: %w", err)
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write webhooks: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
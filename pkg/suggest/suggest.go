@@ -0,0 +1,259 @@
+// Package suggest learns from the jobs a user keeps and the ones they
+// dismiss, and proposes search keywords, exclusions and companies.
+package suggest
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/resume"
+)
+
+// Suggestions are what the user's application statuses say about their
+// search
+type Suggestions struct {
+	// Liked counts jobs saved, applied to or further along
+	Liked int `json:"liked"`
+	// Archived counts jobs archived or withdrawn from
+	Archived int `json:"archived"`
+	// Keywords are terms far more common in liked jobs than in archived
+	// ones (or than in the other stored jobs, before any are archived)
+	Keywords []Term `json:"keywords"`
+	// Exclusions are terms far more common in archived jobs than in liked
+	// ones
+	Exclusions []Term `json:"exclusions"`
+	// Companies posted liked jobs, most liked first
+	Companies []Company `json:"companies"`
+	// AvoidCompanies posted only archived jobs
+	AvoidCompanies []Company `json:"avoid_companies"`
+}
+
+// Term is a skill or title word and how often each group of jobs mentions it
+type Term struct {
+	Term     string `json:"term"`
+	Liked    int    `json:"liked"`
+	Archived int    `json:"archived"`
+	// Others counts the stored jobs the user has not marked
+	Others int `json:"others"`
+	// Lift is the term's share of liked (or archived) jobs minus its share
+	// of the jobs compared against
+	Lift float64 `json:"lift"`
+}
+
+// Company is an employer and the user's statuses for its jobs
+type Company struct {
+	Name     string `json:"name"`
+	Liked    int    `json:"liked"`
+	Archived int    `json:"archived"`
+	// Unreviewed counts its stored jobs the user has not marked yet
+	Unreviewed int `json:"unreviewed"`
+}
+
+// liked are the statuses of jobs the user wanted; a rejection still says
+// the job was of interest
+var liked = map[string]bool{
+	models.ApplicationSaved:        true,
+	models.ApplicationApplied:      true,
+	models.ApplicationInterviewing: true,
+	models.ApplicationOffer:        true,
+	models.ApplicationRejected:     true,
+}
+
+// disliked are the statuses of jobs the user turned down
+var disliked = map[string]bool{
+	models.ApplicationArchived:  true,
+	models.ApplicationWithdrawn: true,
+}
+
+// titleStopWords carry no preference on their own
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "for": true, "in": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true, "m": true, "f": true,
+	"d": true, "w": true, "x": true, "all": true, "genders": true,
+}
+
+// Suggest compares the jobs the user's applications mark as liked with the
+// archived ones. A job without an application counts by its own status, as
+// set by the status command or the Telegram bot, whose applied, rejected
+// and archived states mean the same as the application ones. Terms need to
+// appear in at least minJobs jobs of a group and terms already in current
+// are left out; each list is cut to limit.
+func Suggest(jobs []models.Job, applications []models.Application, current []string, minJobs, limit int) *Suggestions {
+	status := make(map[string]string, len(jobs))
+	for i := range jobs {
+		switch s := jobs[i].CurrentStatus(); s {
+		case models.JobApplied, models.JobRejected, models.JobArchived:
+			status[jobs[i].ID] = s
+		}
+	}
+	for _, application := range applications {
+		status[application.JobID] = application.Status
+	}
+
+	known := make(map[string]bool, len(current))
+	for _, keyword := range current {
+		known[strings.ToLower(strings.TrimSpace(keyword))] = true
+	}
+	// "golang" and "go" describe the same skill, as in resume matching
+	if known["go"] || known["golang"] {
+		known["go"], known["golang"] = true, true
+	}
+
+	result := &Suggestions{}
+	terms := make(map[string]*Term)
+	companies := make(map[string]*Company)
+	others := 0
+	for i := range jobs {
+		job := &jobs[i]
+		name := strings.TrimSpace(job.Company)
+		company, ok := companies[strings.ToLower(name)]
+		if !ok && name != "" {
+			company = &Company{Name: name}
+			companies[strings.ToLower(name)] = company
+		}
+
+		var count func(*Term)
+		switch s := status[job.ID]; {
+		case liked[s]:
+			result.Liked++
+			count = func(t *Term) { t.Liked++ }
+			if company != nil {
+				company.Liked++
+			}
+		case disliked[s]:
+			result.Archived++
+			count = func(t *Term) { t.Archived++ }
+			if company != nil {
+				company.Archived++
+			}
+		default:
+			others++
+			count = func(t *Term) { t.Others++ }
+			if company != nil {
+				company.Unreviewed++
+			}
+		}
+
+		for _, word := range jobTerms(job) {
+			if known[word] {
+				continue
+			}
+			term, ok := terms[word]
+			if !ok {
+				term = &Term{Term: word}
+				terms[word] = term
+			}
+			count(term)
+		}
+	}
+	if result.Liked == 0 {
+		return result
+	}
+
+	for _, term := range terms {
+		likedShare := share(term.Liked, result.Liked)
+		if term.Liked >= minJobs {
+			// Before anything is archived, the unmarked jobs stand in for
+			// what the user passes over
+			baseline := share(term.Others, others)
+			if result.Archived > 0 {
+				baseline = share(term.Archived, result.Archived)
+			}
+			if lift := likedShare - baseline; lift > 0 {
+				result.Keywords = append(result.Keywords, Term{Term: term.Term, Liked: term.Liked, Archived: term.Archived, Others: term.Others, Lift: lift})
+			}
+		}
+		if term.Archived >= minJobs {
+			if lift := share(term.Archived, result.Archived) - likedShare; lift > 0 {
+				result.Exclusions = append(result.Exclusions, Term{Term: term.Term, Liked: term.Liked, Archived: term.Archived, Others: term.Others, Lift: lift})
+			}
+		}
+	}
+	result.Keywords = topTerms(result.Keywords, limit)
+	result.Exclusions = topTerms(result.Exclusions, limit)
+
+	for _, company := range companies {
+		switch {
+		case company.Liked > 0:
+			result.Companies = append(result.Companies, *company)
+		case company.Archived >= minJobs:
+			result.AvoidCompanies = append(result.AvoidCompanies, *company)
+		}
+	}
+	sort.Slice(result.Companies, func(i, j int) bool {
+		a, b := result.Companies[i], result.Companies[j]
+		if a.Liked != b.Liked {
+			return a.Liked > b.Liked
+		}
+		if a.Unreviewed != b.Unreviewed {
+			return a.Unreviewed > b.Unreviewed
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(result.AvoidCompanies, func(i, j int) bool {
+		a, b := result.AvoidCompanies[i], result.AvoidCompanies[j]
+		if a.Archived != b.Archived {
+			return a.Archived > b.Archived
+		}
+		return a.Name < b.Name
+	})
+	if len(result.Companies) > limit {
+		result.Companies = result.Companies[:limit]
+	}
+	if len(result.AvoidCompanies) > limit {
+		result.AvoidCompanies = result.AvoidCompanies[:limit]
+	}
+	return result
+}
+
+// jobTerms are the skills a job mentions and the words of its title
+func jobTerms(job *models.Job) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	for _, skill := range resume.JobSkills(strings.ToLower(job.Title + " " + job.Description)) {
+		add(skill)
+	}
+	words := strings.FieldsFunc(strings.ToLower(job.Title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.'
+	})
+	for _, word := range words {
+		word = strings.Trim(word, ".")
+		if word != "" && !titleStopWords[word] && strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			add(word)
+		}
+	}
+	return terms
+}
+
+func share(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// topTerms orders terms by lift, then by how many jobs back them
+func topTerms(terms []Term, limit int) []Term {
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Lift != terms[j].Lift {
+			return terms[i].Lift > terms[j].Lift
+		}
+		if support := terms[i].Liked + terms[i].Archived - terms[j].Liked - terms[j].Archived; support != 0 {
+			return support > 0
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
@@ -0,0 +1,157 @@
+// Package summarize condenses job descriptions into three short bullets
+// (responsibilities, must-have skills, red flags) using an LLM.
+package summarize
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/ai"
+	"hire.ai/pkg/models"
+)
+
+// Config is the summarization section of GlobalSettings
+type Config struct {
+	Enabled bool `json:"enabled"`
+	ai.Config
+	// MaxJobsPerRun bounds the LLM calls after one scrape. Defaults to 50.
+	MaxJobsPerRun int `json:"maxJobsPerRun,omitempty"`
+	// Concurrency defaults to 4 requests at a time
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// maxPromptDescription keeps prompts within small local models' context
+const maxPromptDescription = 12000
+
+const systemPrompt = `You summarise job postings for a job seeker. Reply with a single JSON object and nothing else:
+{"responsibilities": "...", "must_have_skills": "...", "red_flags": "..."}
+Each value is one plain sentence of at most 25 words. Red flags are things like unpaid work, unrealistic requirements, vague pay or excessive hours; use "None noted" when there are none.`
+
+// Summarizer calls the configured LLM
+type Summarizer struct {
+	config Config
+	client ai.LLMClient
+	logger *logrus.Logger
+}
+
+// New validates config, filling in what it leaves out from defaults; a nil
+// or disabled config returns a nil Summarizer
+func New(config *Config, defaults *ai.Defaults, logger *logrus.Logger) (*Summarizer, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	c := *config
+	defaults.Apply(&c.Config, false)
+	client, err := ai.NewClient(c.Config, "summarization")
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxJobsPerRun <= 0 {
+		c.MaxJobsPerRun = 50
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+
+	return &Summarizer{config: c, client: client, logger: logger}, nil
+}
+
+// SummarizeAll fills in Summary for jobs that have a description but no
+// summary yet, up to limit (MaxJobsPerRun when limit is 0), and returns how
+// many it summarised. Failures are logged and leave the job unsummarised.
+func (s *Summarizer) SummarizeAll(ctx context.Context, jobs []models.Job, limit int) int {
+	if limit <= 0 {
+		limit = s.config.MaxJobsPerRun
+	}
+
+	var pending []int
+	for i := range jobs {
+		if jobs[i].Summary == nil && strings.TrimSpace(jobs[i].Description) != "" {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) > limit {
+		s.logger.Warnf("Summarizing %d of %d jobs; the rest stay unsummarized", limit, len(pending))
+		pending = pending[:limit]
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		succeeded int
+	)
+	slots := make(chan struct{}, s.config.Concurrency)
+	for _, i := range pending {
+		wg.Add(1)
+		go func(job *models.Job) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			summary, err := s.Summarize(ctx, job)
+			if err != nil {
+				s.logger.WithField("job_id", job.ID).Warnf("Failed to summarize %s: %v", job.Title, err)
+				return
+			}
+			job.Summary = summary
+			mutex.Lock()
+			succeeded++
+			mutex.Unlock()
+		}(&jobs[i])
+	}
+	wg.Wait()
+	return succeeded
+}
+
+// Summarize asks the LLM for one job's summary
+func (s *Summarizer) Summarize(ctx context.Context, job *models.Job) (*models.JobSummary, error) {
+	reply, err := s.client.Complete(ctx, systemPrompt, prompt(job))
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := parseSummary(reply)
+	if err != nil {
+		return nil, err
+	}
+	summary.Model = s.client.Model()
+	summary.CreatedAt = time.Now()
+	return summary, nil
+}
+
+func prompt(job *models.Job) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Title: %s\nCompany: %s\n", job.Title, job.Company)
+	if job.Location != "" {
+		fmt.Fprintf(&text, "Location: %s\n", job.Location)
+	}
+	if job.Salary != "" {
+		fmt.Fprintf(&text, "Salary: %s\n", job.Salary)
+	}
+	fmt.Fprintf(&text, "\nDescription:\n%s", ai.Truncate(job.Description, maxPromptDescription))
+	return text.String()
+}
+
+// parseSummary reads the summary JSON in a reply
+func parseSummary(reply string) (*models.JobSummary, error) {
+	var summary models.JobSummary
+	if err := ai.DecodeJSON(reply, &summary); err != nil {
+		return nil, err
+	}
+	summary.Responsibilities = strings.TrimSpace(summary.Responsibilities)
+	summary.MustHaveSkills = strings.TrimSpace(summary.MustHaveSkills)
+	summary.RedFlags = strings.TrimSpace(summary.RedFlags)
+	if summary.Responsibilities == "" && summary.MustHaveSkills == "" {
+		return nil, fmt.Errorf("summary is empty")
+	}
+	if summary.RedFlags == "" {
+		summary.RedFlags = "None noted"
+	}
+	return &summary, nil
+}
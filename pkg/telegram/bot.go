@@ -0,0 +1,229 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/errs"
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+)
+
+const (
+	// botResults is how many jobs /search and /latest answer with
+	botResults = 5
+	// maxLatest bounds the count /latest accepts
+	maxLatest = 20
+	// retryDelay is how long the bot waits after a failed poll
+	retryDelay = 5 * time.Second
+)
+
+const botHelp = `<b>hire.ai bot</b>
+/search golang berlin – best stored jobs matching every word, in the title, company, description or location
+/latest [n] – the most recently found jobs
+/applied &lt;id&gt; – mark a job applied, by the ID shown under it (a prefix will do)`
+
+// Bot answers commands about the stored jobs from the chats it allows
+type Bot struct {
+	client *Client
+	store  storage.Storage
+	chats  map[string]bool
+	logger *logrus.Logger
+}
+
+// NewBot creates a bot answering the chats with the given IDs
+func NewBot(client *Client, store storage.Storage, chatIDs []string, logger *logrus.Logger) *Bot {
+	chats := make(map[string]bool, len(chatIDs))
+	for _, id := range chatIDs {
+		chats[strings.TrimSpace(id)] = true
+	}
+	return &Bot{client: client, store: store, chats: chats, logger: logger}
+}
+
+// Run polls for messages and answers them until ctx is cancelled. Failed
+// polls are logged and retried.
+func (b *Bot) Run(ctx context.Context) error {
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			b.logger.Warnf("Telegram poll failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || strings.TrimSpace(update.Message.Text) == "" {
+				continue
+			}
+			b.handle(ctx, update.Message)
+		}
+	}
+}
+
+// handle answers one message, replying with the error when a command fails
+func (b *Bot) handle(ctx context.Context, message *Message) {
+	chatID := strconv.FormatInt(message.Chat.ID, 10)
+	if !b.chats[chatID] {
+		b.logger.Warnf("Ignoring Telegram message from chat %s, which is not in chatIds", chatID)
+		b.reply(ctx, chatID, []string{fmt.Sprintf("This chat (%s) may not use the bot; add its ID to chatIds in the telegram notification settings.", chatID)})
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	// Commands in groups may name the bot, as in /latest@hireai_bot
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+	b.logger.Debugf("Telegram command %s from chat %s", command, chatID)
+
+	var replies []string
+	var err error
+	switch command {
+	case "/search":
+		replies, err = b.search(ctx, args)
+	case "/latest":
+		replies, err = b.latest(ctx, args)
+	case "/applied":
+		replies, err = b.applied(ctx, args)
+	default:
+		replies = []string{botHelp}
+	}
+	if err != nil {
+		replies = []string{html.EscapeString(err.Error())}
+	}
+	b.reply(ctx, chatID, replies)
+}
+
+func (b *Bot) reply(ctx context.Context, chatID string, messages []string) {
+	for _, message := range messages {
+		if err := b.client.SendMessage(ctx, chatID, message); err != nil {
+			b.logger.Errorf("Failed to answer Telegram chat %s: %v", chatID, err)
+			return
+		}
+	}
+}
+
+// search answers with the best jobs matching every word, each found in the
+// job's text as a full-text query would find it or in its location
+func (b *Bot) search(ctx context.Context, words []string) ([]string, error) {
+	if len(words) == 0 {
+		return nil, errors.New("usage: /search <words>, e.g. /search golang berlin")
+	}
+	jobs, err := b.store.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	terms := make([]models.TextQuery, len(words))
+	for i, word := range words {
+		terms[i] = models.ParseQuery(word)
+	}
+	var matched []models.Job
+	for i := range jobs {
+		if jobs[i].CurrentStatus() == models.JobArchived || !matchesAll(&jobs[i], words, terms) {
+			continue
+		}
+		matched = append(matched, jobs[i])
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Relevance > matched[j].Relevance })
+
+	query := html.EscapeString(strings.Join(words, " "))
+	if len(matched) == 0 {
+		return []string{fmt.Sprintf("No stored jobs match <b>%s</b>.", query)}, nil
+	}
+	heading := fmt.Sprintf("<b>%d jobs match %s</b>", len(matched), query)
+	if len(matched) == 1 {
+		heading = fmt.Sprintf("<b>1 job matches %s</b>", query)
+	}
+	return Messages(heading, matched[:min(len(matched), botResults)]), nil
+}
+
+// matchesAll reports whether every word is in the job's text or location
+func matchesAll(job *models.Job, words []string, terms []models.TextQuery) bool {
+	location := strings.ToLower(job.Location)
+	for i, word := range words {
+		if !terms[i].Match(job) && !strings.Contains(location, strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+// latest answers with the most recently found jobs
+func (b *Bot) latest(ctx context.Context, args []string) ([]string, error) {
+	count := botResults
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return nil, errors.New("usage: /latest [n]")
+		}
+		count = min(n, maxLatest)
+	}
+
+	result, err := b.store.Search(ctx, models.JobFilter{SortBy: "date", Limit: count})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jobs: %w", err)
+	}
+	if len(result.Jobs) == 0 {
+		return []string{"No jobs stored yet."}, nil
+	}
+	return Messages(fmt.Sprintf("<b>Latest %d of %d jobs</b>", len(result.Jobs), result.Total), result.Jobs), nil
+}
+
+// applied marks the job whose ID starts with the argument applied
+func (b *Bot) applied(ctx context.Context, args []string) ([]string, error) {
+	if len(args) != 1 {
+		return nil, errors.New("usage: /applied <id>")
+	}
+	id, err := b.resolveID(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	job, err := b.store.UpdateStatus(ctx, id, models.JobApplied)
+	if err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("Marked applied: <b>%s</b> at %s", html.EscapeString(job.Title), html.EscapeString(job.Company))}, nil
+}
+
+// resolveID finds the one stored job whose ID starts with prefix
+func (b *Bot) resolveID(ctx context.Context, prefix string) (string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if job, err := b.store.GetByID(ctx, prefix); err == nil {
+		return job.ID, nil
+	}
+
+	jobs, err := b.store.GetAll(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load jobs: %w", err)
+	}
+	var found []string
+	for _, job := range jobs {
+		if strings.HasPrefix(job.ID, prefix) {
+			found = append(found, job.ID)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("job %s %w", prefix, errs.ErrNotFound)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("%d jobs have IDs starting %s; give more of the ID", len(found), prefix)
+}
@@ -0,0 +1,200 @@
+// Package telegram talks to the Telegram Bot API: it sends job alerts to
+// chats and answers the commands people send the bot.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/transport"
+)
+
+const (
+	apiURL = "https://api.telegram.org/bot"
+	// MaxMessageLength is the most characters Telegram accepts in a message
+	MaxMessageLength = 4096
+	// ShortIDLength is how much of a job ID messages show; commands accept
+	// any unique prefix
+	ShortIDLength = 8
+	// pollTimeout is how long getUpdates waits for new messages
+	pollTimeout = 50 * time.Second
+)
+
+// Client calls the Bot API with one bot's token
+type Client struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client for the bot with the given token
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: apiURL,
+		// Long polls hold the request open for pollTimeout
+		client: transport.NewClient(pollTimeout + 20*time.Second),
+	}
+}
+
+// Update is one incoming event; only messages are asked for
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a message sent to the bot
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+// Chat is the private chat or group a message came from
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// apiResponse is the envelope of every Bot API response
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// SendMessage sends HTML-formatted text to a chat, without link previews
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	params := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+// GetUpdates long-polls for the messages sent after offset
+func (c *Client) GetUpdates(ctx context.Context, offset int64) ([]Update, error) {
+	params := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The URL holds the token, so it is kept out of the error
+		return fmt.Errorf("telegram %s failed: %w", method, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	var envelope apiResponse
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("telegram %s returned %s", method, resp.Status)
+	}
+	if !envelope.OK {
+		if envelope.Parameters.RetryAfter > 0 {
+			return fmt.Errorf("telegram %s: %s (retry after %ds)", method, envelope.Description, envelope.Parameters.RetryAfter)
+		}
+		return fmt.Errorf("telegram %s: %s", method, envelope.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(envelope.Result, result)
+	}
+	return nil
+}
+
+// unwrapURLError drops the request URL a *url.Error wraps its cause with
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// JobText formats a job as an HTML message card: the title linking to the
+// posting, company and location, salary, posted date, relevance and short
+// ID
+func JobText(job models.Job) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b><a href=\"%s\">%s</a></b>\n%s", html.EscapeString(job.Link), html.EscapeString(job.Title), html.EscapeString(job.Company))
+	if job.Location != "" {
+		fmt.Fprintf(&text, " · %s", html.EscapeString(job.Location))
+	}
+	if job.Salary != "" {
+		fmt.Fprintf(&text, "\n💰 %s", html.EscapeString(job.Salary))
+	}
+	source := html.EscapeString(job.Source)
+	if posted := job.PostedDate(); posted != "" {
+		source += " · posted " + posted
+	}
+	fmt.Fprintf(&text, "\n<i>%s · relevance %s</i> · <code>%s</code>",
+		source, strconv.FormatFloat(job.Relevance, 'f', 2, 64), ShortID(job.ID))
+	return text.String()
+}
+
+// ShortID returns the start of a job ID that messages show
+func ShortID(id string) string {
+	if len(id) > ShortIDLength {
+		return id[:ShortIDLength]
+	}
+	return id
+}
+
+// Messages joins a heading and job cards into as few messages as fit
+// Telegram's length limit
+func Messages(heading string, jobs []models.Job) []string {
+	var messages []string
+	current := heading
+	for _, job := range jobs {
+		card := JobText(job)
+		if len(current)+len(card)+2 > MaxMessageLength {
+			messages = append(messages, current)
+			current = ""
+		}
+		if current != "" {
+			current += "\n\n"
+		}
+		current += card
+	}
+	if current != "" {
+		messages = append(messages, current)
+	}
+	return messages
+}
@@ -0,0 +1,326 @@
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Recording modes for SetRecording
+const (
+	// ModeLive sends requests to the network as usual
+	ModeLive = ""
+	// ModeRecord sends requests to the network and saves every response
+	ModeRecord = "record"
+	// ModeOffline answers requests from saved responses and never touches
+	// the network
+	ModeOffline = "offline"
+)
+
+// ErrNotRecorded is returned offline for a request with no saved response
+var ErrNotRecorded = errors.New("no recording")
+
+var (
+	recordMode string
+	recordDir  string
+	// secrets are credentials sent in URL paths, such as Jooble's API key
+	secrets = map[string]bool{}
+)
+
+// redacted stands in for credentials in recorded URLs
+const redacted = "REDACTED"
+
+// credentialParams are query parameters holding credentials, compared
+// lowercased
+var credentialParams = map[string]bool{
+	"api_key": true, "apikey": true, "app_key": true, "app_id": true, "key": true,
+	"token": true, "access_token": true, "secret": true, "client_secret": true, "password": true,
+}
+
+// AddSecret names a credential that clients send as part of a URL path, so
+// recordings store and match it as REDACTED. Recordings can then be shared,
+// and replayed with any key.
+func AddSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	secrets[secret] = true
+}
+
+// SetRecording switches every client made afterwards to recording
+// responses into dir, or to replaying them from it. Call it before any
+// client is made.
+func SetRecording(mode, dir string) error {
+	switch mode {
+	case ModeLive, ModeRecord, ModeOffline:
+	default:
+		return fmt.Errorf("unknown recording mode %q", mode)
+	}
+	if mode == ModeOffline {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("no recordings in %s; make some with -record first", dir)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	recordMode = mode
+	recordDir = dir
+	return nil
+}
+
+// Offline reports whether requests are answered from recordings
+func Offline() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return recordMode == ModeOffline
+}
+
+// Recording reports whether responses are being saved
+func Recording() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return recordMode == ModeRecord
+}
+
+// Wrap returns rt recording its responses, or a replayer standing in for
+// it offline. Live, rt is returned as is.
+func Wrap(rt http.RoundTripper) http.RoundTripper {
+	mutex.Lock()
+	defer mutex.Unlock()
+	switch recordMode {
+	case ModeRecord:
+		return &recorder{next: rt, dir: recordDir}
+	case ModeOffline:
+		return &replayer{dir: recordDir}
+	}
+	return rt
+}
+
+// Save records body as the response to a GET of rawURL, for pages fetched
+// outside an HTTP client such as those a browser rendered. It does nothing
+// unless recording.
+func Save(rawURL string, status int, contentType string, body []byte) error {
+	mutex.Lock()
+	mode, dir := recordMode, recordDir
+	mutex.Unlock()
+	if mode != ModeRecord {
+		return nil
+	}
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return writeRecording(dir, http.MethodGet, rawURL, nil, status, header, body)
+}
+
+// recording is the saved metadata of one response; its body sits beside it
+// in a .body file so recorded pages can be read and edited
+type recording struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+type recorder struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// Gzipped pages are kept plain so they can be read, and replayed
+	// without an encoding
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if plain, err := gunzip(body); err == nil {
+			body = plain
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = int64(len(body))
+			resp.Uncompressed = true
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := writeRecording(r.dir, req.Method, req.URL.String(), reqBody, resp.StatusCode, resp.Header, body); err != nil {
+		return nil, fmt.Errorf("failed to record %s: %w", req.URL.Redacted(), err)
+	}
+	return resp, nil
+}
+
+type replayer struct {
+	dir string
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	base := recordingPath(r.dir, req.Method, req.URL.String(), reqBody)
+	data, err := os.ReadFile(base + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("offline: %w of %s %s", ErrNotRecorded, req.Method, req.URL.Redacted())
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("offline: invalid recording %s.json: %w", base, err)
+	}
+	body, err := os.ReadFile(base + ".body")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	header := rec.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// requestBody reads a request's body, leaving it in place for the request
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func writeRecording(dir, method, rawURL string, reqBody []byte, status int, header http.Header, body []byte) error {
+	base := recordingPath(dir, method, rawURL, reqBody)
+	if err := os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return err
+	}
+	header = header.Clone()
+	header.Del("Set-Cookie")
+	data, err := json.MarshalIndent(recording{
+		Method: method, URL: redactURL(rawURL), Status: status, Header: header, RecordedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".body", body, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(base+".json", data, 0644)
+}
+
+// recordingPath is where the response to a request is kept, without its
+// extension: a directory per host and a file named by a hash of the method,
+// the redacted URL with its query sorted and the request body
+func recordingPath(dir, method, rawURL string, reqBody []byte) string {
+	host := "other"
+	key := redactURL(rawURL)
+	if parsed, err := url.Parse(key); err == nil {
+		if parsed.Host != "" {
+			host = strings.ToLower(parsed.Host)
+		}
+		parsed.RawQuery = parsed.Query().Encode()
+		parsed.Fragment = ""
+		key = parsed.String()
+	}
+	hash := sha256.New()
+	io.WriteString(hash, strings.ToUpper(method)+" "+key+"\n")
+	hash.Write(reqBody)
+	name := hex.EncodeToString(hash.Sum(nil))[:24]
+	return filepath.Join(dir, strings.NewReplacer(":", "_", "/", "_").Replace(host), name)
+}
+
+// redactURL replaces the credentials in rawURL with REDACTED: path
+// segments that are a secret added with AddSecret, and the values of
+// credential query parameters or of any parameter holding such a secret
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	segments := strings.Split(parsed.Path, "/")
+	changed := false
+	for i, segment := range segments {
+		if secrets[segment] {
+			segments[i] = redacted
+			changed = true
+		}
+	}
+	if changed {
+		parsed.Path, parsed.RawPath = strings.Join(segments, "/"), ""
+	}
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for name, values := range query {
+			for i, value := range values {
+				if credentialParams[strings.ToLower(name)] || secrets[value] {
+					values[i] = redacted
+					changed = true
+				}
+			}
+		}
+		if changed {
+			parsed.RawQuery = query.Encode()
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	return parsed.String()
+}
+
+func gunzip(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
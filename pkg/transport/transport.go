@@ -0,0 +1,119 @@
+// Package transport provides the HTTP transport shared by the API providers,
+// the RSS client, notifiers and webhooks, so they pool connections to the
+// same hosts and agree on timeouts and proxying. Its clients can also record
+// every response to disk and replay them offline.
+package transport
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Config is the http section of GlobalSettings. Durations are in seconds.
+type Config struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept for reuse
+	// per host. Defaults to 16.
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// DialTimeout defaults to 10
+	DialTimeout int `json:"dialTimeout,omitempty"`
+	// TLSHandshakeTimeout defaults to 10
+	TLSHandshakeTimeout int `json:"tlsHandshakeTimeout,omitempty"`
+	// IdleConnTimeout is how long an idle connection is kept. Defaults to 90.
+	IdleConnTimeout int `json:"idleConnTimeout,omitempty"`
+	// Proxy sends every request through this proxy URL. Without it the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables apply.
+	Proxy string `json:"proxy,omitempty"`
+}
+
+var (
+	mutex  sync.Mutex
+	shared *http.Transport
+)
+
+// New builds a transport from config, filling in defaults for what it
+// leaves out. A nil config gives the defaults.
+func New(config *Config) (*http.Transport, error) {
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.MaxIdleConnsPerHost <= 0 {
+		c.MaxIdleConnsPerHost = 16
+	}
+	if c.DialTimeout <= 0 {
+		c.DialTimeout = 10
+	}
+	if c.TLSHandshakeTimeout <= 0 {
+		c.TLSHandshakeTimeout = 10
+	}
+	if c.IdleConnTimeout <= 0 {
+		c.IdleConnTimeout = 90
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.Proxy != "" {
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid http proxy URL")
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported http proxy scheme %q", proxyURL.Scheme)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   time.Duration(c.DialTimeout) * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   c.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(c.IdleConnTimeout) * time.Second,
+		TLSHandshakeTimeout:   time.Duration(c.TLSHandshakeTimeout) * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+// Configure replaces the shared transport with one built from config.
+// Clients made before keep the transport they were given.
+func Configure(config *Config) error {
+	transport, err := New(config)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if shared != nil {
+		shared.CloseIdleConnections()
+	}
+	shared = transport
+	return nil
+}
+
+// Shared returns the shared transport, built with the defaults unless
+// Configure was called first
+func Shared() *http.Transport {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if shared == nil {
+		shared, _ = New(nil)
+	}
+	return shared
+}
+
+// NewClient returns a client on the shared transport with the given
+// overall request timeout, recording or replaying as SetRecording says
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Wrap(Shared()), Timeout: timeout}
+}
@@ -0,0 +1,253 @@
+// Package webhook delivers newly stored jobs to user-registered URLs. Each
+// delivery is a signed JSON POST retried with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hire.ai/pkg/models"
+	"hire.ai/pkg/storage"
+	"hire.ai/pkg/transport"
+)
+
+// Headers sent with every delivery. Receivers verify SignatureHeader by
+// computing "sha256=" + hex(HMAC-SHA256(secret, body)). DeliveryHeader
+// repeats the payload's delivery_id, which retries keep.
+const (
+	SignatureHeader = "X-Hire-Signature"
+	EventHeader     = "X-Hire-Event"
+	DeliveryHeader  = "X-Hire-Delivery"
+)
+
+const (
+	defaultWorkers     = 2
+	defaultQueueSize   = 100
+	defaultMaxAttempts = 5
+	defaultBackoff     = 2 * time.Second
+	maxJobsPerDelivery = 100
+)
+
+// Dispatcher matches stored jobs against webhooks and delivers them in the
+// background
+type Dispatcher struct {
+	store       *storage.WebhookStore
+	client      *http.Client
+	logger      *logrus.Logger
+	queue       chan delivery
+	maxAttempts int
+	backoff     time.Duration
+	wg          sync.WaitGroup
+}
+
+type delivery struct {
+	webhook models.Webhook
+	payload models.WebhookPayload
+}
+
+// NewDispatcher creates a dispatcher for the webhooks in store
+func NewDispatcher(store *storage.WebhookStore, logger *logrus.Logger) *Dispatcher {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Dispatcher{
+		store:       store,
+		client:      transport.NewClient(15 * time.Second),
+		logger:      logger,
+		queue:       make(chan delivery, defaultQueueSize),
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+}
+
+// Start runs the delivery workers until ctx is cancelled. Wait blocks until
+// they have exited.
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < defaultWorkers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-d.queue:
+					d.deliver(ctx, item)
+				}
+			}
+		}()
+	}
+}
+
+// Wait blocks until the workers started by Start have exited
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Dispatch queues one delivery per enabled webhook with the jobs that match
+// its filter. It never blocks; deliveries are dropped when the queue is full.
+func (d *Dispatcher) Dispatch(jobs []models.Job) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	for _, webhook := range d.store.Enabled() {
+		// The store compiled the filters as it loaded them
+		if err := webhook.Filter.CompileError(); err != nil {
+			d.logger.WithField("webhook_id", webhook.ID).Warnf("Skipping webhook with an invalid keyword query: %v", err)
+			continue
+		}
+		var matched []models.Job
+		for i := range jobs {
+			if webhook.Filter.Matches(&jobs[i]) {
+				matched = append(matched, jobs[i])
+			}
+		}
+
+		for len(matched) > 0 {
+			batch := matched
+			if len(batch) > maxJobsPerDelivery {
+				batch = batch[:maxJobsPerDelivery]
+			}
+			matched = matched[len(batch):]
+
+			item := delivery{webhook: webhook, payload: models.WebhookPayload{
+				Event:      models.WebhookEventJobsMatched,
+				WebhookID:  webhook.ID,
+				DeliveryID: newDeliveryID(),
+				Jobs:       batch,
+			}}
+			select {
+			case d.queue <- item:
+			default:
+				d.logger.WithField("webhook_id", webhook.ID).Warnf("Webhook queue full, dropping delivery of %d jobs", len(batch))
+			}
+		}
+	}
+}
+
+// Ping sends a single signed ping event to webhook without retrying and
+// returns the HTTP status received
+func (d *Dispatcher) Ping(ctx context.Context, webhook models.Webhook) (int, error) {
+	payload := models.WebhookPayload{Event: models.WebhookEventPing, WebhookID: webhook.ID, DeliveryID: newDeliveryID(), Jobs: []models.Job{}}
+	status, err := d.send(ctx, webhook, payload)
+	if recordErr := d.store.RecordDelivery(webhook.ID, status, err); recordErr != nil {
+		d.logger.Warnf("Failed to record webhook delivery: %v", recordErr)
+	}
+	return status, err
+}
+
+// deliver sends item, retrying network errors, 429s and 5xx responses with
+// exponential backoff. Every attempt carries the same delivery ID.
+func (d *Dispatcher) deliver(ctx context.Context, item delivery) {
+	log := d.logger.WithFields(logrus.Fields{"webhook_id": item.webhook.ID, "jobs": len(item.payload.Jobs)})
+
+	var status int
+	var err error
+	backoff := d.backoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		status, err = d.send(ctx, item.webhook, item.payload)
+		if err == nil || !retryable(status, err) {
+			break
+		}
+
+		if attempt < d.maxAttempts {
+			log.Debugf("Webhook delivery attempt %d failed, retrying in %v: %v", attempt, backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+
+	if err != nil {
+		log.Warnf("Webhook delivery failed: %v", err)
+	} else {
+		log.Debug("Webhook delivered")
+	}
+	if recordErr := d.store.RecordDelivery(item.webhook.ID, status, err); recordErr != nil {
+		log.Warnf("Failed to record webhook delivery: %v", recordErr)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook models.Webhook, payload models.WebhookPayload) (int, error) {
+	payload.SentAt = time.Now().UTC()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, notSentError{fmt.Errorf("failed to encode payload: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, notSentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hire.ai-webhooks/1.0")
+	req.Header.Set(EventHeader, payload.Event)
+	req.Header.Set(DeliveryHeader, payload.DeliveryID)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newDeliveryID returns a random identifier for a delivery and its retries
+func newDeliveryID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+// notSentError is an error that stopped a delivery before its request was
+// made, such as a payload that cannot be encoded or an invalid URL, which a
+// retry would meet again
+type notSentError struct {
+	err error
+}
+
+func (e notSentError) Error() string { return e.err.Error() }
+func (e notSentError) Unwrap() error { return e.err }
+
+// retryable reports whether a delivery that got status and err should be
+// retried; status 0 means the request never completed, which is retried
+// unless the request was never made
+func retryable(status int, err error) bool {
+	var notSent notSentError
+	if errors.As(err, &notSent) {
+		return false
+	}
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}